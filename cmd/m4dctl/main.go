@@ -0,0 +1,162 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	appv1 "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/backup"
+	"github.com/mesh-for-data/mesh-for-data/pkg/visualize"
+	corev1 "k8s.io/api/core/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const usage = `m4dctl manages the control plane resources of a cluster.
+
+Usage:
+  m4dctl export [-o file]                         export the control plane resources, with the referenced secrets redacted
+  m4dctl import -f file [-namespace old=new,...]  import exported resources into the current cluster
+  m4dctl plotter graph [-format dot|mermaid] [-namespace ns] name
+                                                  render the data flow of a plotter, e.g. for debugging multicluster flows
+`
+
+func newClient() (client.Client, error) {
+	scheme := kruntime.NewScheme()
+	_ = appv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
+
+func exportCommand(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("o", "", "The file to which the resources are written. The standard output is used if not set.")
+	systemNamespace := flags.String("system-namespace", "m4d-system", "The namespace of the control plane, holding the secrets of the promoted copies.")
+	_ = flags.Parse(args)
+
+	cl, err := newClient()
+	if err != nil {
+		return err
+	}
+	bundle, err := backup.Export(context.Background(), cl, *systemNamespace)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(*output, data, 0600)
+}
+
+func importCommand(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	input := flags.String("f", "", "The file containing the exported resources.")
+	namespaces := flags.String("namespace", "", "Comma separated namespace mappings old=new, e.g. when the control plane is installed in a different namespace.")
+	skipPlotters := flags.Bool("skip-plotters", false, "Do not import plotters, they are generated again by the manager from the applications.")
+	_ = flags.Parse(args)
+	if *input == "" {
+		return errors.New("the file to import must be specified")
+	}
+
+	options := backup.ImportOptions{Namespaces: map[string]string{}, SkipPlotters: *skipPlotters}
+	if *namespaces != "" {
+		for _, mapping := range strings.Split(*namespaces, ",") {
+			names := strings.Split(mapping, "=")
+			if len(names) != 2 || names[0] == "" || names[1] == "" {
+				return errors.New("invalid namespace mapping " + mapping)
+			}
+			options.Namespaces[names[0]] = names[1]
+		}
+	}
+	data, err := ioutil.ReadFile(*input)
+	if err != nil {
+		return err
+	}
+	bundle := &backup.Bundle{}
+	if err := yaml.Unmarshal(data, bundle); err != nil {
+		return err
+	}
+	cl, err := newClient()
+	if err != nil {
+		return err
+	}
+	missing, err := backup.Import(context.Background(), cl, bundle, options)
+	if err != nil {
+		return err
+	}
+	for _, ref := range missing {
+		fmt.Printf("secret %s is referenced by the imported resources and has to be created\n", ref.String())
+	}
+	return nil
+}
+
+func plotterCommand(args []string) error {
+	if len(args) < 1 || args[0] != "graph" {
+		return errors.New("unknown plotter command, expected graph")
+	}
+	flags := flag.NewFlagSet("plotter graph", flag.ExitOnError)
+	format := flags.String("format", string(visualize.DOT), "The format of the graph: dot or mermaid.")
+	namespace := flags.String("namespace", "m4d-system", "The namespace of the plotter.")
+	_ = flags.Parse(args[1:])
+	if flags.NArg() != 1 {
+		return errors.New("the name of the plotter must be specified")
+	}
+
+	cl, err := newClient()
+	if err != nil {
+		return err
+	}
+	plotter := &appv1.Plotter{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: *namespace, Name: flags.Arg(0)}, plotter); err != nil {
+		return err
+	}
+	graph, err := visualize.PlotterGraph(plotter, visualize.Format(*format))
+	if err != nil {
+		return err
+	}
+	fmt.Print(graph)
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(1)
+	}
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = exportCommand(os.Args[2:])
+	case "import":
+		err = importCommand(os.Args[2:])
+	case "plotter":
+		err = plotterCommand(os.Args[2:])
+	default:
+		fmt.Print(usage)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
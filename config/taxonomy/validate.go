@@ -6,20 +6,46 @@ package taxonomy
 import (
 	"fmt"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/xeipuuv/gojsonschema"
 )
 
+var (
+	schemasMutex sync.Mutex
+	schemas      = map[string]*gojsonschema.Schema{}
+)
+
+// compiledTaxonomy returns the json schema taxonomy of the indicated file, which is compiled once
+func compiledTaxonomy(taxonomyFile string) (*gojsonschema.Schema, error) {
+	path, err := filepath.Abs(taxonomyFile)
+	if err != nil {
+		return nil, err
+	}
+	schemasMutex.Lock()
+	defer schemasMutex.Unlock()
+	if schema, found := schemas[path]; found {
+		return schema, nil
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewReferenceLoader("file://" + path))
+	if err != nil {
+		return nil, err
+	}
+	schemas[path] = schema
+	return schema, nil
+}
+
 // ValidateTaxonomy loads a json schema taxonomy from the indicated file, and validates the jsonData against the taxonomy.
 func ValidateTaxonomy(t *testing.T, taxonomyFile string, jsonData string, testName string, expectedValid bool) {
-	path, err := filepath.Abs(taxonomyFile)
-	assert.Nil(t, err)
+	schema, err := compiledTaxonomy(taxonomyFile)
+	if !assert.Nil(t, err) {
+		return
+	}
 
-	taxonomyLoader := gojsonschema.NewReferenceLoader("file://" + path)
 	documentLoader := gojsonschema.NewStringLoader(jsonData)
-	result, err := gojsonschema.Validate(taxonomyLoader, documentLoader)
+	result, err := schema.Validate(documentLoader)
 	assert.Nil(t, err)
 
 	if expectedValid {
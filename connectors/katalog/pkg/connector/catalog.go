@@ -131,6 +131,22 @@ func buildDataStore(asset *Asset) (*connectors.DataStore, error) {
 				Ssl:      emptyIfNil(connection.Db2.Ssl),
 			},
 		}, nil
+	case "jdbc":
+		if connection.Jdbc == nil {
+			return nil, errors.New("the jdbc connection details are missing")
+		}
+		return &connectors.DataStore{
+			Type: connectors.DataStore_JDBC,
+			Name: asset.Name,
+			Jdbc: &connectors.JdbcDataStore{
+				Url:      emptyIfNil(connection.Jdbc.Url),
+				Database: emptyIfNil(connection.Jdbc.Database),
+				Table:    emptyIfNil(connection.Jdbc.Table),
+				Port:     emptyIfNil(connection.Jdbc.Port),
+				Ssl:      emptyIfNil(connection.Jdbc.Ssl),
+				Vendor:   emptyIfNil(connection.Jdbc.Vendor),
+			},
+		}, nil
 	default:
 		return nil, errors.New("unknown datastore type")
 	}
@@ -0,0 +1,233 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/mesh-for-data/mesh-for-data/connectors/katalog/pkg/api"
+	"github.com/mesh-for-data/mesh-for-data/connectors/katalog/pkg/taxonomy"
+	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// invalidNameCharacters matches the characters that are not allowed in the names of Kubernetes resources
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// RegisterDatasetInfo registers a copy of a dataset as a new Asset in the namespace given as the destination catalog.
+// The credentials of the copy are stored in a Secret referenced by the asset, and the metadata of the source asset,
+// sent with the details of the copy, is propagated to the new asset.
+// Registering the same copy again returns the identifier of the existing asset, while an existing asset of the same
+// name describing another data store is reported as a conflict.
+func (s *DataCatalogService) RegisterDatasetInfo(ctx context.Context, req *connectors.RegisterAssetRequest) (*connectors.RegisterAssetResponse, error) {
+	namespace := req.GetDestinationCatalogId()
+	if namespace == "" {
+		return nil, status.Error(codes.InvalidArgument, "the destination catalog is missing")
+	}
+	details := req.GetDatasetDetails()
+	name := assetName(details)
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "the name of the asset is missing")
+	}
+	connection, err := buildConnection(details.GetDataStore())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	log.Printf("In RegisterDatasetInfo: asset namespace is " + namespace + " asset name is " + name)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-creds", Namespace: namespace},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: buildCredentials(req.GetCreds()),
+	}
+	if err := s.client.Create(ctx, secret); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return nil, errors.Wrap(err, "failed to create the credentials of the asset")
+	}
+
+	asset := &Asset{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Asset: api.Asset{
+			Spec: api.AssetSpec{
+				SecretRef: api.SecretRef{Name: secret.Name},
+				AssetDetails: api.AssetDetails{
+					Connection: *connection,
+					DataFormat: nilIfEmpty(details.GetDataFormat()),
+				},
+				AssetMetadata: buildAssetMetadata(details),
+			},
+		},
+	}
+	if err := createAsset(ctx, s.client, asset); err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			return nil, errors.Wrap(err, "failed to create the asset")
+		}
+		if err := checkExistingAsset(ctx, s.client, asset); err != nil {
+			return nil, err
+		}
+	}
+	return &connectors.RegisterAssetResponse{AssetId: namespace + "/" + name}, nil
+}
+
+// assetName returns a valid resource name for the asset, derived from the name of the data store of the copy
+// or, if not set, from the name of the dataset
+func assetName(details *connectors.DatasetDetails) string {
+	name := details.GetDataStore().GetName()
+	if name == "" {
+		name = details.GetName()
+	}
+	name = invalidNameCharacters.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > 240 {
+		name = name[:240]
+	}
+	return strings.Trim(name, ".-")
+}
+
+func buildConnection(datastore *connectors.DataStore) (*taxonomy.Connection, error) {
+	switch datastore.GetType() {
+	case connectors.DataStore_S3:
+		s3 := datastore.GetS3()
+		return &taxonomy.Connection{
+			Type: "s3",
+			S3: &taxonomy.S3{
+				Endpoint:  s3.GetEndpoint(),
+				Bucket:    s3.GetBucket(),
+				ObjectKey: s3.GetObjectKey(),
+				Region:    nilIfEmpty(s3.GetRegion()),
+			},
+		}, nil
+	case connectors.DataStore_KAFKA:
+		kafka := datastore.GetKafka()
+		return &taxonomy.Connection{
+			Type: "kafka",
+			Kafka: &taxonomy.Kafka{
+				TopicName:             nilIfEmpty(kafka.GetTopicName()),
+				BootstrapServers:      nilIfEmpty(kafka.GetBootstrapServers()),
+				SchemaRegistry:        nilIfEmpty(kafka.GetSchemaRegistry()),
+				KeyDeserializer:       nilIfEmpty(kafka.GetKeyDeserializer()),
+				ValueDeserializer:     nilIfEmpty(kafka.GetValueDeserializer()),
+				SecurityProtocol:      nilIfEmpty(kafka.GetSecurityProtocol()),
+				SaslMechanism:         nilIfEmpty(kafka.GetSaslMechanism()),
+				SslTruststore:         nilIfEmpty(kafka.GetSslTruststore()),
+				SslTruststorePassword: nilIfEmpty(kafka.GetSslTruststorePassword()),
+			},
+		}, nil
+	case connectors.DataStore_DB2:
+		db2 := datastore.GetDb2()
+		return &taxonomy.Connection{
+			Type: "db2",
+			Db2: &taxonomy.DB2{
+				Url:      nilIfEmpty(db2.GetUrl()),
+				Database: nilIfEmpty(db2.GetDatabase()),
+				Table:    nilIfEmpty(db2.GetTable()),
+				Port:     nilIfEmpty(db2.GetPort()),
+				Ssl:      nilIfEmpty(db2.GetSsl()),
+			},
+		}, nil
+	case connectors.DataStore_JDBC:
+		jdbc := datastore.GetJdbc()
+		return &taxonomy.Connection{
+			Type: "jdbc",
+			Jdbc: &taxonomy.JDBC{
+				Url:      nilIfEmpty(jdbc.GetUrl()),
+				Database: nilIfEmpty(jdbc.GetDatabase()),
+				Table:    nilIfEmpty(jdbc.GetTable()),
+				Port:     nilIfEmpty(jdbc.GetPort()),
+				Ssl:      nilIfEmpty(jdbc.GetSsl()),
+				Vendor:   nilIfEmpty(jdbc.GetVendor()),
+			},
+		}, nil
+	default:
+		return nil, errors.New("unknown datastore type")
+	}
+}
+
+func buildAssetMetadata(details *connectors.DatasetDetails) api.AssetMetadata {
+	metadata := details.GetMetadata()
+	componentsMetadata := map[string]api.ComponentMetadata{}
+	for componentName, componentValue := range metadata.GetComponentsMetadata() {
+		component := api.ComponentMetadata{
+			ComponentType: nilIfEmpty(componentValue.GetComponentType()),
+			Tags:          nilIfEmptyArray(componentValue.GetTags()),
+		}
+		if len(componentValue.GetNamedMetadata()) > 0 {
+			component.NamedMetadata = &api.ComponentMetadata_NamedMetadata{AdditionalProperties: componentValue.GetNamedMetadata()}
+		}
+		componentsMetadata[componentName] = component
+	}
+
+	assetMetadata := api.AssetMetadata{
+		Owner:              nilIfEmpty(details.GetDataOwner()),
+		Geography:          nilIfEmpty(details.GetGeo()),
+		Tags:               nilIfEmptyArray(metadata.GetDatasetTags()),
+		ComponentsMetadata: &api.AssetMetadata_ComponentsMetadata{AdditionalProperties: componentsMetadata},
+	}
+	if len(metadata.GetDatasetNamedMetadata()) > 0 {
+		assetMetadata.NamedMetadata = &api.AssetMetadata_NamedMetadata{AdditionalProperties: metadata.GetDatasetNamedMetadata()}
+	}
+	return assetMetadata
+}
+
+// buildCredentials returns the data of the Secret holding the credentials, with the keys read by the modules
+func buildCredentials(creds *connectors.Credentials) map[string]string {
+	data := map[string]string{}
+	for key, value := range map[string]string{
+		"access_key":           creds.GetAccessKey(),
+		"secret_key":           creds.GetSecretKey(),
+		"username":             creds.GetUsername(),
+		"password":             creds.GetPassword(),
+		"api_key":              creds.GetApiKey(),
+		"resource_instance_id": creds.GetResourceInstanceId(),
+	} {
+		if value != "" {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+// checkExistingAsset returns an error unless the existing asset of the same name is the registered copy,
+// i.e. it refers to the same data store with the same credentials
+func checkExistingAsset(ctx context.Context, client kclient.Client, asset *Asset) error {
+	existing, err := getAsset(ctx, client, asset.Namespace, asset.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the existing asset")
+	}
+	registered, err := json.Marshal(asset.Spec.AssetDetails.Connection)
+	if err != nil {
+		return err
+	}
+	found, err := json.Marshal(existing.Spec.AssetDetails.Connection)
+	if err != nil {
+		return err
+	}
+	if string(registered) != string(found) || existing.Spec.SecretRef.Name != asset.Spec.SecretRef.Name {
+		return status.Errorf(codes.AlreadyExists, "the asset %s/%s already exists for another data store", asset.Namespace, asset.Name)
+	}
+	return nil
+}
+
+func createAsset(ctx context.Context, client kclient.Client, asset *Asset) error {
+	// Encode the asset as unstructured, as read by getAsset
+	bytes, err := json.Marshal(asset)
+	if err != nil {
+		return err
+	}
+	object := &unstructured.Unstructured{}
+	if err := json.Unmarshal(bytes, &object.Object); err != nil {
+		return err
+	}
+	object.SetAPIVersion(GroupVersion.String())
+	object.SetKind("Asset")
+	return client.Create(ctx, object)
+}
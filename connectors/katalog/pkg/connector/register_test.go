@@ -0,0 +1,139 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+package connector
+
+import (
+	"context"
+	"testing"
+
+	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	vault "github.com/mesh-for-data/mesh-for-data/pkg/vault"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newRegisterRequest() *connectors.RegisterAssetRequest {
+	return &connectors.RegisterAssetRequest{
+		DestinationCatalogId: "m4d-notebook-sample",
+		Creds:                &connectors.Credentials{AccessKey: "ak", SecretKey: "sk"},
+		DatasetDetails: &connectors.DatasetDetails{
+			Name:       "data-csv",
+			DataOwner:  "finance",
+			DataFormat: "parquet",
+			Geo:        "theshire",
+			DataStore: &connectors.DataStore{
+				Type: connectors.DataStore_S3,
+				Name: "data-csv-copy_3f2a",
+				S3: &connectors.S3DataStore{
+					Endpoint:  "http://minio:9000",
+					Bucket:    "m4d-copy",
+					ObjectKey: "data.parquet",
+				},
+			},
+			Metadata: &connectors.DatasetMetadata{
+				DatasetTags:          []string{"finance"},
+				DatasetNamedMetadata: map[string]string{"retention": "30d"},
+				ComponentsMetadata: map[string]*connectors.DataComponentMetadata{
+					"nameOrig": {ComponentType: "column", Tags: []string{"PII"}},
+				},
+			},
+		},
+	}
+}
+
+func newTestService(t *testing.T) *DataCatalogService {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, corev1.AddToScheme(scheme))
+	assert.Nil(t, AddToScheme(scheme))
+	return &DataCatalogService{client: fake.NewFakeClientWithScheme(scheme)}
+}
+
+func TestRegisterDatasetInfo(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	response, err := service.RegisterDatasetInfo(ctx, newRegisterRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, "m4d-notebook-sample/data-csv-copy-3f2a", response.GetAssetId())
+
+	// the credentials of the copy are stored in a secret referenced by the asset
+	secret := &corev1.Secret{}
+	assert.Nil(t, service.client.Get(ctx, types.NamespacedName{Name: "data-csv-copy-3f2a-creds", Namespace: "m4d-notebook-sample"}, secret))
+	assert.Equal(t, map[string]string{"access_key": "ak", "secret_key": "sk"}, secret.StringData)
+
+	// the registered asset is found with the metadata of the source asset
+	info, err := service.GetDatasetInfo(ctx, &connectors.CatalogDatasetRequest{DatasetId: response.GetAssetId()})
+	assert.Nil(t, err)
+	details := info.GetDetails()
+	assert.Equal(t, "finance", details.GetDataOwner())
+	assert.Equal(t, "parquet", details.GetDataFormat())
+	assert.Equal(t, "theshire", details.GetGeo())
+	assert.Equal(t, "m4d-copy", details.GetDataStore().GetS3().GetBucket())
+	assert.Equal(t, "data.parquet", details.GetDataStore().GetS3().GetObjectKey())
+	assert.Equal(t, vault.PathForReadingKubeSecret("m4d-notebook-sample", "data-csv-copy-3f2a-creds"), details.GetCredentialsInfo().GetVaultSecretPath())
+	assert.Equal(t, []string{"finance"}, details.GetMetadata().GetDatasetTags())
+	assert.Equal(t, map[string]string{"retention": "30d"}, details.GetMetadata().GetDatasetNamedMetadata())
+	assert.Equal(t, []string{"PII"}, details.GetMetadata().GetComponentsMetadata()["nameOrig"].GetTags())
+
+	// registering the same copy again returns the existing asset
+	response, err = service.RegisterDatasetInfo(ctx, newRegisterRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, "m4d-notebook-sample/data-csv-copy-3f2a", response.GetAssetId())
+
+	// an existing asset of the same name describing another data store is not taken over
+	request := newRegisterRequest()
+	request.DatasetDetails.DataStore.S3.Bucket = "other-bucket"
+	_, err = service.RegisterDatasetInfo(ctx, request)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestRegisterDatasetInfoInvalidRequest(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	request := newRegisterRequest()
+	request.DestinationCatalogId = ""
+	_, err := service.RegisterDatasetInfo(ctx, request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	request = newRegisterRequest()
+	request.DatasetDetails.DataStore = nil
+	_, err = service.RegisterDatasetInfo(ctx, request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRegisterDatasetInfoJdbc(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	request := newRegisterRequest()
+	request.DatasetDetails.DataFormat = "table"
+	request.DatasetDetails.DataStore = &connectors.DataStore{
+		Type: connectors.DataStore_JDBC,
+		Name: "sales-copy_3f2a",
+		Jdbc: &connectors.JdbcDataStore{
+			Url:      "jdbc:postgresql://postgres.example.com:5432/sales",
+			Database: "sales",
+			Table:    "PUBLIC.ORDERS",
+			Port:     "5432",
+			Vendor:   "postgresql",
+		},
+	}
+	response, err := service.RegisterDatasetInfo(ctx, request)
+	assert.Nil(t, err)
+
+	// the connection of the registered asset is read back as a JDBC data store
+	info, err := service.GetDatasetInfo(ctx, &connectors.CatalogDatasetRequest{DatasetId: response.GetAssetId()})
+	assert.Nil(t, err)
+	datastore := info.GetDetails().GetDataStore()
+	assert.Equal(t, connectors.DataStore_JDBC, datastore.GetType())
+	assert.Equal(t, "jdbc:postgresql://postgres.example.com:5432/sales", datastore.GetJdbc().GetUrl())
+	assert.Equal(t, "PUBLIC.ORDERS", datastore.GetJdbc().GetTable())
+	assert.Equal(t, "postgresql", datastore.GetJdbc().GetVendor())
+	assert.Empty(t, datastore.GetJdbc().GetSsl())
+}
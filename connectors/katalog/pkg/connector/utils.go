@@ -21,6 +21,20 @@ func emptyArrayIfNil(val *[]string) []string {
 	return *val
 }
 
+func nilIfEmpty(val string) *string {
+	if val == "" {
+		return nil
+	}
+	return &val
+}
+
+func nilIfEmptyArray(val []string) *[]string {
+	if len(val) == 0 {
+		return nil
+	}
+	return &val
+}
+
 func splitNamespacedName(value string) (namespace string, name string, err error) {
 	identifier := strings.SplitN(value, "/", 2)
 	if len(identifier) != 2 {
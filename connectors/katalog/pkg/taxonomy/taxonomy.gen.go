@@ -25,6 +25,7 @@ type Authentication struct {
 // Connection defines model for Connection.
 type Connection struct {
 	Db2   *DB2   `json:"db2,omitempty"`
+	Jdbc  *JDBC  `json:"jdbc,omitempty"`
 	Kafka *Kafka `json:"kafka,omitempty"`
 
 	// Connection information for S3 compatible object store
@@ -41,6 +42,16 @@ type DB2 struct {
 	Url      *string `json:"url,omitempty"`
 }
 
+// JDBC defines model for JDBC.
+type JDBC struct {
+	Database *string `json:"database,omitempty"`
+	Port     *string `json:"port,omitempty"`
+	Ssl      *string `json:"ssl,omitempty"`
+	Table    *string `json:"table,omitempty"`
+	Url      *string `json:"url,omitempty"`
+	Vendor   *string `json:"vendor,omitempty"`
+}
+
 // Kafka defines model for Kafka.
 type Kafka struct {
 	BootstrapServers      *string `json:"bootstrap_servers,omitempty"`
@@ -10,6 +10,11 @@ $(TOOLBIN)/controller-gen:
 	GOBIN=$(ABSTOOLBIN) GO111MODULE=on go get sigs.k8s.io/controller-tools/cmd/controller-gen@v0.5.0
 	$(call post-install-check)
 
+INSTALL_TOOLS += $(TOOLBIN)/client-gen $(TOOLBIN)/lister-gen $(TOOLBIN)/informer-gen
+$(TOOLBIN)/client-gen $(TOOLBIN)/lister-gen $(TOOLBIN)/informer-gen:
+	GOBIN=$(ABSTOOLBIN) GO111MODULE=on go get k8s.io/code-generator/cmd/client-gen@v0.20.2 k8s.io/code-generator/cmd/lister-gen@v0.20.2 k8s.io/code-generator/cmd/informer-gen@v0.20.2
+	$(call post-install-check)
+
 INSTALL_TOOLS += $(TOOLBIN)/crd-ref-docs
 $(TOOLBIN)/crd-ref-docs:
 	GOBIN=$(ABSTOOLBIN) GO111MODULE=on go get github.com/elastic/crd-ref-docs@v0.0.5
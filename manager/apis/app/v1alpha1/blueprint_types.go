@@ -5,6 +5,7 @@ package v1alpha1
 
 import (
 	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -47,6 +48,11 @@ type WriteModuleArgs struct {
 	// +required
 	Destination DataStore `json:"destination"`
 
+	// AssetID identifies the asset to be used for writing the data when it is ready
+	// It is copied from the M4DApplication resource
+	// +required
+	AssetID string `json:"assetID"`
+
 	// Transformations are different types of processing that may be done to the data as it is written.
 	// +optional
 	Transformations []serde.Arbitrary `json:"transformations,omitempty"`
@@ -67,6 +73,28 @@ type ModuleArguments struct {
 	// WriteArgs are parameters that are specific to modules that enable an application to write data
 	// +optional
 	Write []WriteModuleArgs `json:"write,omitempty"`
+
+	// Labels are propagated to the resources of the module, e.g. to attribute their cost to the owner of the application.
+	// The labels identifying the application are added by the manager.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are propagated to the resources of the module, e.g. the identifiers of the datasets that the module instance serves
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Scheduling of the pods of the module, according to its capability in the step
+	// +optional
+	Scheduling *SchedulingPolicy `json:"scheduling,omitempty"`
+
+	// Resources are the compute resources requested by the pods of the module, i.e. the defaults of the module
+	// overridden by the requirements of the datasets that the module instance serves
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Sidecars are the governance agents required by the module, to be injected as sidecars of its pods
+	// +optional
+	Sidecars []Sidecar `json:"sidecars,omitempty"`
 }
 
 // FlowStep is one step indicates an instance of a module in the blueprint,
@@ -154,8 +182,72 @@ type BlueprintStatus struct {
 	// At the end of reconcile, each release should be mapped to the latest blueprint version or be uninstalled.
 	// +optional
 	Releases map[string]int64 `json:"releases,omitempty"`
+
+	// Steps maps the name of each step to the Kubernetes resources deployed for it, to help debugging the data plane
+	// +optional
+	Steps map[string]StepResources `json:"steps,omitempty"`
 }
 
+// StepResources identifies the Kubernetes resources deployed for a step of the blueprint
+type StepResources struct {
+	// Release is the name of the Helm release deploying the step
+	Release string `json:"release"`
+
+	// Namespace in which the release is installed
+	Namespace string `json:"namespace"`
+
+	// Workloads lists the main resources of the release (e.g. deployments, jobs and data transfers) as <kind>/<name>
+	// +optional
+	Workloads []string `json:"workloads,omitempty"`
+
+	// Readiness is the condition under which the step is ready, according to the capability of its module
+	// +optional
+	Readiness StepReadiness `json:"readiness,omitempty"`
+
+	// State of the step according to its readiness semantics
+	// +optional
+	State StepState `json:"state,omitempty"`
+
+	// Reruns is the number of times the failed jobs of the step have been run again since the blueprint spec has changed
+	// +optional
+	Reruns int32 `json:"reruns,omitempty"`
+
+	// Health aggregates the readiness and liveness of the pods of the workloads of the step
+	// +optional
+	Health *ModuleHealth `json:"health,omitempty"`
+}
+
+// StepReadiness defines when a step is ready
+// +kubebuilder:validation:Enum=Completion;Availability
+type StepReadiness string
+
+const (
+	// CompletionReadiness applies to copy steps, run to completion by jobs:
+	// the step is ready once all its jobs have succeeded, and fails if one of them fails.
+	// Failed jobs are run again by installing the release of the step again, up to MaxStepReruns times.
+	CompletionReadiness StepReadiness = "Completion"
+	// AvailabilityReadiness applies to read and write steps, served by deployments:
+	// the step is ready once all its deployments are available. Its failures are not rerun.
+	AvailabilityReadiness StepReadiness = "Availability"
+)
+
+// MaxStepReruns is the number of times the failed jobs of a step are run again before the step remains failed
+const MaxStepReruns int32 = 3
+
+// StepState is the state of a step of the blueprint
+// +kubebuilder:validation:Enum=Pending;Ready;Failed
+type StepState string
+
+const (
+	// StepPending indicates that the resources of the step are being deployed, or its jobs are running or rerun
+	StepPending StepState = "Pending"
+	// StepReady indicates that the step is ready according to its readiness semantics
+	StepReady StepState = "Ready"
+	// StepFailed indicates that a resource of the step has failed
+	StepFailed StepState = "Failed"
+)
+
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced
@@ -179,6 +271,10 @@ type MetaBlueprint struct {
 	// +required
 	Namespace string `json:"namespace"`
 
+	// ReleaseNames maps the steps of the blueprint to the names of the Helm releases deploying them
+	// +optional
+	ReleaseNames map[string]string `json:"releaseNames,omitempty"`
+
 	// +required
 	Status BlueprintStatus `json:"status"`
 }
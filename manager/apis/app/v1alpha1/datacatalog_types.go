@@ -6,10 +6,17 @@ package v1alpha1
 import "github.com/mesh-for-data/mesh-for-data/pkg/serde"
 
 // DataStore contains the details for accesing the data that are sent by catalog connectors
-// Credentials for accesing the data are stored in Vault, in the location represented by Vault property.
+// Credentials for accesing the data are stored in Vault, in the location represented by Vault property,
+// or in the Kubernetes secret represented by the KubernetesSecret property when Vault is not deployed.
 type DataStore struct {
 	// Holds details for retrieving credentials by the modules from Vault store.
 	Vault Vault `json:"vault"`
+	// CredentialsType is the backend from which the modules retrieve the credentials, vault if not specified
+	// +optional
+	CredentialsType CredentialsType `json:"credentialsType,omitempty"`
+	// KubernetesSecret references the secret holding the credentials when CredentialsType is kubernetes
+	// +optional
+	KubernetesSecret *KubernetesSecretRef `json:"kubernetesSecret,omitempty"`
 	// Connection has the relevant details for accesing the data (url, table, ssl, etc.)
 	// +required
 	Connection serde.Arbitrary `json:"connection"`
@@ -0,0 +1,108 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// AllCatalogs stands for all the catalogs in DatasetIDNormalizer.CaseInsensitiveCatalogs
+const AllCatalogs = "*"
+
+// DatasetIDNormalizer normalizes the dataset IDs of the applications, such that the variants of an ID given by the users
+// refer to the same dataset in the status of the application. The catalog and the asset of an ID are trimmed, the JSON
+// representation of an ID is compacted with sorted keys, and the asset is case folded for the case-insensitive catalogs.
+type DatasetIDNormalizer struct {
+	// CaseInsensitiveCatalogs lists the catalogs whose asset IDs are case-insensitive, or AllCatalogs
+	CaseInsensitiveCatalogs []string
+}
+
+// datasetIDNormalizer normalizes the dataset IDs of the applications upon admission, if set
+var datasetIDNormalizer *DatasetIDNormalizer
+
+// EnableDatasetIDNormalization enables the normalization of the dataset IDs of the applications upon admission
+func EnableDatasetIDNormalization(normalizer *DatasetIDNormalizer) {
+	datasetIDNormalizer = normalizer
+}
+
+// caseInsensitive returns whether the asset IDs of a catalog are case-insensitive
+func (n *DatasetIDNormalizer) caseInsensitive(catalog string) bool {
+	for _, name := range n.CaseInsensitiveCatalogs {
+		if name == AllCatalogs || strings.EqualFold(name, catalog) {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize returns the normalized form of a dataset ID, given either as <catalog>/<asset>
+// or as a JSON object with catalog_id and asset_id keys
+func (n *DatasetIDNormalizer) Normalize(id string) string {
+	id = strings.TrimSpace(id)
+	fields := map[string]string{}
+	if err := json.Unmarshal([]byte(id), &fields); err == nil {
+		for key, value := range fields {
+			fields[key] = strings.TrimSpace(value)
+		}
+		if asset, found := fields["asset_id"]; found && n.caseInsensitive(fields["catalog_id"]) {
+			fields["asset_id"] = strings.ToLower(asset)
+		}
+		// the keys of a map are marshaled in sorted order
+		normalized, err := json.Marshal(fields)
+		if err != nil {
+			return id
+		}
+		return string(normalized)
+	}
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 {
+		return id
+	}
+	catalog, asset := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if n.caseInsensitive(catalog) {
+		asset = strings.ToLower(asset)
+	}
+	return catalog + "/" + asset
+}
+
+// NormalizeSpec normalizes the dataset IDs of the spec of an application.
+// It is the only normalization applied upon admission, since the changes of the status are ignored there.
+func (n *DatasetIDNormalizer) NormalizeSpec(application *M4DApplication) {
+	for i := range application.Spec.Data {
+		application.Spec.Data[i].DataSetID = n.Normalize(application.Spec.Data[i].DataSetID)
+	}
+	for i := range application.Spec.PinnedModules {
+		application.Spec.PinnedModules[i].DataSetID = n.Normalize(application.Spec.PinnedModules[i].DataSetID)
+	}
+}
+
+// NormalizeApplication normalizes the dataset IDs of the spec of an application, and the dataset IDs keying the
+// entries of its status that are kept across reconciles, e.g. the storage allocated for the copies.
+// Status entries of IDs whose normalized form is already present are left as is, to be cleaned up by the controller.
+func (n *DatasetIDNormalizer) NormalizeApplication(application *M4DApplication) {
+	n.NormalizeSpec(application)
+	status := &application.Status
+	for _, entries := range []interface{}{status.ProvisionedStorage, status.CatalogedAssets, status.GrantedCopies,
+		status.RetainedCopies, status.PromotedCopies, status.ObservedData} {
+		n.normalizeKeys(entries)
+	}
+}
+
+// normalizeKeys normalizes the dataset IDs keying a map
+func (n *DatasetIDNormalizer) normalizeKeys(entries interface{}) {
+	value := reflect.ValueOf(entries)
+	if value.IsNil() {
+		return
+	}
+	for _, key := range value.MapKeys() {
+		normalized := reflect.ValueOf(n.Normalize(key.String()))
+		if normalized.String() == key.String() || value.MapIndex(normalized).IsValid() {
+			continue
+		}
+		value.SetMapIndex(normalized, value.MapIndex(key))
+		value.SetMapIndex(key, reflect.Value{})
+	}
+}
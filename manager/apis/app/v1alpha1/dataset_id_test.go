@@ -0,0 +1,64 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestNormalizeDatasetID(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	normalizer := &DatasetIDNormalizer{CaseInsensitiveCatalogs: []string{"s3"}}
+
+	g.Expect(normalizer.Normalize(" s3 / Allow-Dataset ")).To(gomega.Equal("s3/allow-dataset"))
+	// the assets of other catalogs are case-sensitive
+	g.Expect(normalizer.Normalize("db2/Allow-Dataset ")).To(gomega.Equal("db2/Allow-Dataset"))
+	g.Expect(normalizer.Normalize(`{"asset_id": " ABC ", "catalog_id": "s3"}`)).To(gomega.Equal(`{"asset_id":"abc","catalog_id":"s3"}`))
+	g.Expect(normalizer.Normalize(`{"catalog_id":"db2","asset_id":"ABC"}`)).To(gomega.Equal(`{"asset_id":"ABC","catalog_id":"db2"}`))
+	g.Expect(normalizer.Normalize(" no-catalog ")).To(gomega.Equal("no-catalog"))
+
+	all := &DatasetIDNormalizer{CaseInsensitiveCatalogs: []string{AllCatalogs}}
+	g.Expect(all.Normalize("DB2/Allow-Dataset")).To(gomega.Equal("DB2/allow-dataset"))
+}
+
+func TestNormalizeApplication(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	normalizer := &DatasetIDNormalizer{CaseInsensitiveCatalogs: []string{"s3"}}
+	application := &M4DApplication{
+		Spec: M4DApplicationSpec{
+			Data:          []DataContext{{DataSetID: "s3/Allow-Dataset"}},
+			PinnedModules: []PinnedModule{{DataSetID: "s3/ALLOW-dataset", Flow: Read, Module: "arrow-flight-module"}},
+		},
+		Status: M4DApplicationStatus{
+			ProvisionedStorage: map[string]DatasetDetails{
+				"s3/Allow-Dataset": {DatasetRef: "bucket-1"},
+			},
+			CatalogedAssets: map[string]string{
+				"s3/Redact-Dataset": "asset-1",
+				"s3/redact-dataset": "asset-2",
+			},
+		},
+	}
+	// upon admission, only the spec is normalized
+	admitted := application.DeepCopy()
+	normalizer.NormalizeSpec(admitted)
+	g.Expect(admitted.Spec.Data[0].DataSetID).To(gomega.Equal("s3/allow-dataset"))
+	g.Expect(admitted.Status).To(gomega.Equal(application.Status))
+
+	normalizer.NormalizeApplication(application)
+
+	g.Expect(application.Spec.Data[0].DataSetID).To(gomega.Equal("s3/allow-dataset"))
+	g.Expect(application.Spec.PinnedModules[0].DataSetID).To(gomega.Equal("s3/allow-dataset"))
+	// the storage allocated for the dataset is kept
+	g.Expect(application.Status.ProvisionedStorage).To(gomega.Equal(map[string]DatasetDetails{
+		"s3/allow-dataset": {DatasetRef: "bucket-1"},
+	}))
+	// entries whose normalized ID is already present are left to the controller
+	g.Expect(application.Status.CatalogedAssets).To(gomega.Equal(map[string]string{
+		"s3/Redact-Dataset": "asset-1",
+		"s3/redact-dataset": "asset-2",
+	}))
+}
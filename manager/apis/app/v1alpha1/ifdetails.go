@@ -5,13 +5,14 @@ package v1alpha1
 
 // Values used in tests and for grpc connection with connectors.
 const (
-	S3          string = "s3"
-	Kafka       string = "kafka"
-	JdbcDb2     string = "jdbc-db2"
-	ArrowFlight string = "m4d-arrow-flight"
-	Arrow       string = "arrow"
-	Parquet     string = "parquet"
-	Table       string = "table"
+	S3           string = "s3"
+	Kafka        string = "kafka"
+	JdbcDb2      string = "jdbc-db2"
+	JdbcPostgres string = "jdbc-postgresql"
+	ArrowFlight  string = "m4d-arrow-flight"
+	Arrow        string = "arrow"
+	Parquet      string = "parquet"
+	Table        string = "table"
 )
 
 // InterfaceDetails indicate how the application or module receive or write the data
@@ -0,0 +1,80 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	log "log"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// PinnedModulesWebhookPath is the path of the webhook admitting changes of the pinned modules of applications
+const PinnedModulesWebhookPath = "/validate-app-m4d-ibm-com-v1alpha1-m4dapplication-pinnedmodules"
+
+// DefaultPinningAdminGroups are the groups whose members may pin modules if no group is configured
+var DefaultPinningAdminGroups = []string{"system:masters"}
+
+// +kubebuilder:webhook:verbs=create;update,admissionReviewVersions=v1;v1beta1,sideEffects=None,path=/validate-app-m4d-ibm-com-v1alpha1-m4dapplication-pinnedmodules,mutating=false,failurePolicy=fail,groups=app.m4d.ibm.com,resources=m4dapplications,versions=v1alpha1,name=vm4dapplicationpinnedmodules.kb.io
+
+// PinnedModulesValidator admits changes of spec.pinnedModules of a M4DApplication only from administrators,
+// since pinned modules bypass the automatic selection of modules.
+// Applications whose pinned modules do not change are always admitted.
+type PinnedModulesValidator struct {
+	// AdminGroups are the groups whose members may pin modules
+	AdminGroups []string
+	decoder     *admission.Decoder
+}
+
+var _ admission.Handler = &PinnedModulesValidator{}
+var _ admission.DecoderInjector = &PinnedModulesValidator{}
+
+// Handle denies the requests of users that are not administrators if they change the pinned modules
+func (v *PinnedModulesValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	application := &M4DApplication{}
+	if err := v.decoder.Decode(req, application); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	var previous []PinnedModule
+	if req.Operation == admissionv1.Update {
+		old := &M4DApplication{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		previous = old.Spec.PinnedModules
+	}
+	if len(application.Spec.PinnedModules) == 0 && len(previous) == 0 ||
+		equality.Semantic.DeepEqual(application.Spec.PinnedModules, previous) {
+		return admission.Allowed("")
+	}
+	if !v.IsAdmin(req.UserInfo) {
+		log.Printf("Denying the change of the pinned modules of m4dapplication %s by %s", application.Name, req.UserInfo.Username)
+		return admission.Denied(fmt.Sprintf("only members of the groups %v may change spec.pinnedModules", v.AdminGroups))
+	}
+	log.Printf("Pinned modules of m4dapplication %s changed by %s", application.Name, req.UserInfo.Username)
+	return admission.Allowed("")
+}
+
+// IsAdmin returns true if the user belongs to one of the administrator groups
+func (v *PinnedModulesValidator) IsAdmin(user authenticationv1.UserInfo) bool {
+	for _, group := range user.Groups {
+		for _, admin := range v.AdminGroups {
+			if group == admin {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *PinnedModulesValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
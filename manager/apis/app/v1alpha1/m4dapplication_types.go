@@ -29,17 +29,81 @@ type CopyRequirements struct {
 	// Catalog indicates that the data asset must be cataloged.
 	// +optional
 	Catalog CatalogRequirements `json:"catalog,omitempty"`
+
+	// MaxStaleness is the maximal duration for which a copy may remain out of date after the source asset is modified,
+	// according to the modification time reported by the data catalog. Stale copies are reported in the status.
+	// If not specified, the freshness of the copy is not checked.
+	// +optional
+	MaxStaleness *metav1.Duration `json:"maxStaleness,omitempty"`
+
+	// TTL is the duration for which an implicit copy is kept after it has been made. Once it has expired, the copy
+	// and its provisioned storage are deleted, and the data is no longer copied for the application.
+	// If not specified, the copy is kept as long as the application exists.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
 }
 
 // DataRequirements structure contains a list of requirements (interface, need to catalog the dataset, etc.)
 type DataRequirements struct {
-	// Interface indicates the protocol and format expected by the data user
-	// +required
-	Interface InterfaceDetails `json:"interface"`
+	// Interface indicates the protocol and format expected by the data user.
+	// If not specified, the interface is negotiated based on the installed modules and the governance policies.
+	// +optional
+	Interface InterfaceDetails `json:"interface,omitempty"`
+
+	// FallbackInterfaces are additional interfaces accepted by the data user, in order of preference.
+	// They are tried in order if no modules can serve the data in Interface, and the interface
+	// that has been satisfied is reported in the NegotiatedInterfaces field of the status.
+	// +optional
+	FallbackInterfaces []InterfaceDetails `json:"fallbackInterfaces,omitempty"`
+
+	// WriteInterface indicates the protocol and format in which the data user writes the dataset, if it differs from
+	// the interface in which the dataset is read, e.g. a dataset read through Arrow Flight and written through S3.
+	// It applies to the write flow only, in which the fallback interfaces are not tried.
+	// +optional
+	WriteInterface *InterfaceDetails `json:"writeInterface,omitempty"`
 
 	// CopyRequrements include the requirements for copying the data
 	// +optional
 	Copy CopyRequirements `json:"copy,omitempty"`
+
+	// InPlace indicates that the data is accessed directly at its source, without deploying any module.
+	// It is allowed only for trusted workloads, i.e. in namespaces labeled app.m4d.ibm.com/trustedWorkloads=true by the
+	// administrators, running in the cluster of the manager, and only if the governance policies permit accessing the data
+	// without transformations. The source connection details are then reported in the status. The access is not enforced
+	// by any module but at the network level: the manager creates a network policy restricting the egress of the workload
+	// outside of the cluster to the sources of the datasets accessed in place.
+	// +optional
+	InPlace bool `json:"inPlace,omitempty"`
+
+	// PerformanceClass is the latency and throughput class preferred for reading the data.
+	// A read module of this class is selected if one is available, otherwise the mismatch is reported in the status.
+	// +optional
+	PerformanceClass PerformanceClass `json:"performanceClass,omitempty"`
+
+	// PrivacyLevel is the target privacy level of the data read by the application, as defined by the privacy levels taxonomy
+	// (e.g. anonymized). It is translated into the actions achieving it, which are applied on top of the actions required
+	// by the governance policies.
+	// +optional
+	PrivacyLevel string `json:"privacyLevel,omitempty"`
+
+	// Resources override the compute resources (CPU, memory) requested by default by the modules serving the dataset
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Geography is the region in which the dataset has to be processed, e.g. to comply with data residency regulations.
+	// It overrides the geography of the workload for this dataset: the modules serving the dataset run in clusters of
+	// this region, provided that the governance policies allow processing the dataset there.
+	// +optional
+	Geography string `json:"geography,omitempty"`
+}
+
+// GetInterface returns the interface in which the data user accesses the dataset in the given flow:
+// the write interface when writing, if specified, and the interface otherwise
+func (r *DataRequirements) GetInterface(flow ModuleFlow) InterfaceDetails {
+	if flow == Write && r.WriteInterface != nil {
+		return *r.WriteInterface
+	}
+	return r.Interface
 }
 
 // DataContext indicates data set chosen by the Data Scientist to be used by his application,
@@ -58,6 +122,40 @@ type DataContext struct {
 	// Requirements from the system
 	// +required
 	Requirements DataRequirements `json:"requirements"`
+
+	// Flow indicates whether the application reads the dataset (default) or writes it.
+	// A dataset is written through a write module exposing the requested interface, which writes the data
+	// to the location of the dataset in the data catalog.
+	// +optional
+	Flow ModuleFlow `json:"flow,omitempty"`
+
+	// Flows lists the flows of a dataset that the application both reads and writes, e.g. [read, write] for a job
+	// reading raw data and writing features to the same dataset. Each flow is evaluated by the policy manager and
+	// served by its own modules, and the endpoints are reported in readEndpointsMap and writeEndpointsMap.
+	// Flows takes precedence over Flow.
+	// +optional
+	Flows []ModuleFlow `json:"flows,omitempty"`
+}
+
+// GetFlows returns the flows of the dataset, read if none is specified
+func (d *DataContext) GetFlows() []ModuleFlow {
+	if len(d.Flows) > 0 {
+		return d.Flows
+	}
+	if d.Flow == "" {
+		return []ModuleFlow{Read}
+	}
+	return []ModuleFlow{d.Flow}
+}
+
+// HasFlow returns true if the dataset is used in the given flow
+func (d *DataContext) HasFlow(flow ModuleFlow) bool {
+	for _, f := range d.GetFlows() {
+		if f == flow {
+			return true
+		}
+	}
+	return false
 }
 
 // ApplicationDetails provides information about the Data Scientist's application, which is deployed separately.
@@ -84,12 +182,101 @@ type M4DApplicationSpec struct {
 	// +required
 	AppInfo ApplicationDetails `json:"appInfo"`
 
+	// Profile is the name of a M4DApplicationProfile in the namespace of the application.
+	// Data requirements that are not specified by the application are taken from the profile.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
 	// Data contains the identifiers of the data to be used by the Data Scientist's application,
 	// and the protocol used to access it and the format expected.
 	// +required
 	Data []DataContext `json:"data"`
+
+	// CopyCleanupPolicy defines what happens to the copies of the datasets when the application is deleted.
+	// Delete removes all the copies, Retain keeps all of them, and RetainIfRegistered (the default) keeps
+	// only the copies that have been registered in a data catalog.
+	// +optional
+	CopyCleanupPolicy CopyCleanupPolicy `json:"copyCleanupPolicy,omitempty"`
+
+	// RetryPolicy limits the attempts to orchestrate the modules of the application when a blueprint fails.
+	// If not set, the orchestration is retried until it succeeds.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// PinnedModules bypass the automatic selection of modules for some of the datasets, e.g. to debug or to roll out a new module.
+	// A pinned module must still support the interfaces and the actions required for the dataset.
+	// Only administrators may pin modules, as enforced by the admission webhook.
+	// +optional
+	PinnedModules []PinnedModule `json:"pinnedModules,omitempty"`
+
+	// ModuleVersions constrains the versions of the modules that may be selected, e.g. to opt in to a new version of a
+	// module before it is rolled out to all the applications. It maps the repositories of the charts of the modules,
+	// without their tags, to semantic version ranges such as ">=0.2.0 <0.3.0", matched against the tags of the charts.
+	// The latest version in the range is selected. The ranges take precedence over the ones set by the administrators.
+	// +optional
+	ModuleVersions map[string]string `json:"moduleVersions,omitempty"`
+
+	// Priority of the application when the manager has many applications to reconcile, from 0 (the default, e.g. for
+	// batch or experimental applications) to 100 (e.g. for production pipelines). Pending applications are reconciled
+	// in proportion to their priority plus one, such that the applications of low priority are delayed but not starved.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// PinnedModule selects the module performing a flow of a dataset
+type PinnedModule struct {
+	// DataSetID is the identifier of the dataset, as listed in spec.data
+	// +required
+	DataSetID string `json:"dataSetID"`
+
+	// Flow is the data flow for which the module is pinned
+	// +required
+	Flow ModuleFlow `json:"flow"`
+
+	// Module is the name of the M4DModule performing the flow
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Module string `json:"module"`
+}
+
+// GetPinnedModule returns the name of the module pinned for the flow of a dataset, or an empty string if the module is
+// selected automatically
+func (r *M4DApplication) GetPinnedModule(datasetID string, flow ModuleFlow) string {
+	for _, pinned := range r.Spec.PinnedModules {
+		if pinned.DataSetID == datasetID && pinned.Flow == flow {
+			return pinned.Module
+		}
+	}
+	return ""
+}
+
+// RetryPolicy defines how often and how fast a failed orchestration is retried
+type RetryPolicy struct {
+	// MaxRetries is the number of consecutive failures of a blueprint after which the orchestration is given up,
+	// and the application enters a terminal failure
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int32 `json:"maxRetries"`
+
+	// Backoff is the delay before the first retry, doubled after each consecutive failure. Defaults to 5s.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
 }
 
+// CopyCleanupPolicy defines what happens to the copies of the datasets when the application is deleted
+// +kubebuilder:validation:Enum=Delete;Retain;RetainIfRegistered
+type CopyCleanupPolicy string
+
+const (
+	// DeleteCopies removes all the copies. Catalog entries of registered copies are not removed.
+	DeleteCopies CopyCleanupPolicy = "Delete"
+	// RetainCopies keeps all the copies
+	RetainCopies CopyCleanupPolicy = "Retain"
+	// RetainRegisteredCopies keeps the copies that have been registered in a data catalog and removes the others
+	RetainRegisteredCopies CopyCleanupPolicy = "RetainIfRegistered"
+)
+
 // ErrorMessages that are reported to the user
 const (
 	ReadAccessDenied            string = "Governance policies forbid access to the data."
@@ -97,32 +284,112 @@ const (
 	WriteNotAllowed             string = "Governance policies forbid writing of the data."
 	ModuleNotFound              string = "No module has been registered"
 	InsufficientStorage         string = "No bucket was provisioned for implicit copy"
+	InPlaceNotAllowed           string = "Governance policies require transformations of the data, which can not be applied when accessing it in place."
+	InPlaceNotTrusted           string = "Data can be accessed in place only by trusted workloads, running in the cluster of the manager in a namespace labeled " + TrustedWorkloadsLabel + "=true."
 	InvalidClusterConfiguration string = "Cluster configuration does not support the requirements."
+	AccessRevoked               string = "Access to the data has been revoked by an administrator."
+	ReadOnlyMode                string = "The manager is in read-only mode. Changes will be applied once the maintenance is over."
+	ConflictingRequirements     string = "The dataset is listed several times with different requirements."
+	RetriesExhausted            string = "The orchestration of the modules has failed too many times and will not be retried."
+	CopyExpired                 string = "The copy of the data has expired and can not be made again."
+	PolicyManagerUnavailable    string = "The governance policies can not be evaluated since the policy manager is unavailable."
+	GeographyNotAllowed         string = "Governance policies forbid processing the data in the required geography."
 )
 
-// Condition indices are static. Conditions always present in the status.
+// ConditionType represents a condition type
+type ConditionType string
+
+// Types of the conditions reported in the status of a M4DApplication.
+// The conditions follow the Kubernetes conventions, e.g. `kubectl wait --for=condition=Ready m4dapplication/<name>`
+// waits for the orchestration of the application.
 const (
-	FailureConditionIndex int64 = 0
-	ErrorConditionIndex   int64 = 1
+	// ReadyCondition means that a blueprint has been successfully orchestrated, mirroring the ready field of the status
+	ReadyCondition string = "Ready"
+
+	// DeniedCondition means that a blueprint could not be constructed, e.g. since the governance policies forbid
+	// the access to the data. The application is not reconciled again until it is changed.
+	DeniedCondition string = "Denied"
+
+	// ErrorCondition means that an error was encountered during blueprint construction, and the construction is retried
+	ErrorCondition string = "Error"
+
+	// ProvisionedCondition means that the storage allocated for copies of the datasets is ready.
+	// While it is not, the reason is either ProvisioningInProgress or ProvisioningFailed.
+	ProvisionedCondition string = "Provisioned"
+
+	// RevokedCondition means that the access to some of the datasets has been revoked by an administrator.
+	// The blueprint is constructed without the revoked datasets.
+	RevokedCondition string = "Revoked"
+
+	// PolicyManagerUnavailableCondition means that the governance policies could not be evaluated since the policy manager
+	// is unavailable. The reason is the mode configured by the administrators, either FailClosed or FailOpen.
+	PolicyManagerUnavailableCondition string = "PolicyManagerUnavailable"
+
+	// ReadOnlyModeCondition means that changes of the application are pending since the manager is in read-only mode.
+	// The other conditions report the state of the application before the changes, and are kept until they are applied.
+	ReadOnlyModeCondition string = "ReadOnlyMode"
 )
 
-// ConditionType represents a condition type
-type ConditionType string
+// Reasons of the ready condition
+const (
+	// OrchestratedReason means that the blueprint of the application is ready
+	OrchestratedReason string = "Orchestrated"
+	// PendingReason means that the blueprint of the application is being orchestrated
+	PendingReason string = "Pending"
+	// FailedReason means that the application reports an error or has been denied
+	FailedReason string = "Failed"
+)
+
+// NominalReason is the reason of the conditions that report no issue
+const NominalReason string = "Nominal"
 
+// Reasons of the denied and error conditions
 const (
-	// ErrorCondition means that an error was encountered during blueprint construction
-	ErrorCondition ConditionType = "Error"
+	// PolicyDeniedReason means that the governance policies forbid the requested access to the data,
+	// or its processing in the required geography
+	PolicyDeniedReason string = "PolicyDenied"
+	// InvalidRequestReason means that the application can not be orchestrated as requested
+	InvalidRequestReason string = "InvalidRequest"
+	// ReconcileErrorReason means that an error, possibly transient, was encountered while reconciling the application
+	ReconcileErrorReason string = "ReconcileError"
+)
+
+// AccessRevokedReason is the reason of the revoked condition while the access to some of the datasets is revoked
+const AccessRevokedReason string = "AccessRevoked"
+
+// Reasons of the policy manager unavailable condition
+const (
+	// FailClosedReason means that the application is not granted access until the policies can be evaluated
+	FailClosedReason string = "FailClosed"
+	// FailOpenReason means that the previously granted generation of the application remains deployed
+	FailOpenReason string = "FailOpen"
+)
 
-	// FailureCondition means that a blueprint could not be constructed
-	FailureCondition ConditionType = "Failure"
+// Reasons of the provisioned condition while the storage is not ready
+const (
+	// ProvisioningInProgress means that the storage has been requested and is being provisioned
+	ProvisioningInProgress string = "ProvisioningInProgress"
+	// ProvisioningFailed means that the provisioner has reported an error for some of the storage
+	ProvisioningFailed string = "ProvisioningFailed"
 )
 
-// Condition describes the state of a M4DApplication at a certain point.
+// ChangesPendingReason is the reason of the read-only mode condition
+const ChangesPendingReason string = "ChangesPending"
+
+// RetriesExhaustedReason is the reason of the denied condition once the orchestration of the modules
+// has been given up according to the retry policy of the application
+const RetriesExhaustedReason string = "RetriesExhausted"
+
+// Condition describes the state of a M4DStorageAccount at a certain point.
+// The conditions of a M4DApplication are metav1.Condition instead.
 type Condition struct {
 	// Type of the condition
 	Type ConditionType `json:"type"`
 	// Status of the condition: true or false
 	Status corev1.ConditionStatus `json:"status"`
+	// Reason is a machine readable cause of the condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
 	// Message contains the details of the current condition
 	// +optional
 	Message string `json:"message,omitempty"`
@@ -138,16 +405,97 @@ type ResourceReference struct {
 	Kind string `json:"kind"`
 	// Version of M4DApplication that has generated this resource
 	AppVersion int64 `json:"appVersion"`
+	// SpecDigest is a digest of the spec of the resource as last written by the manager.
+	// A different digest of the spec indicates that the resource has been modified by others.
+	// +optional
+	SpecDigest string `json:"specDigest,omitempty"`
+}
+
+// OwnedResource references a resource created by the manager for an application
+type OwnedResource struct {
+	// Kind of the resource, e.g. Plotter or Dataset
+	Kind string `json:"kind"`
+	// Name of the resource
+	Name string `json:"name"`
+	// Namespace of the resource
+	Namespace string `json:"namespace"`
+	// StorageType is the type of the storage account provisioned by a Dataset resource, s3 if empty
+	// +optional
+	StorageType string `json:"storageType,omitempty"`
 }
 
 // DatasetDetails contain dataset connection and metadata required to register this dataset in the enterprise catalog
 type DatasetDetails struct {
 	// Reference to a Dataset resource containing the request to provision storage
 	DatasetRef string `json:"datasetRef,omitempty"`
+	// StorageType is the type of the storage account in which the storage has been provisioned, s3 if empty
+	// +optional
+	StorageType string `json:"storageType,omitempty"`
 	// Reference to a secret where the credentials are stored
 	SecretRef string `json:"secretRef,omitempty"`
 	// Dataset information
 	Details serde.Arbitrary `json:"details,omitempty"`
+	// CopiedAt is the time at which the application has become ready with the copy, approximating the time of the copy
+	// +optional
+	CopiedAt *metav1.Time `json:"copiedAt,omitempty"`
+}
+
+// GrantedCopy references the copy of a dataset made by another application
+type GrantedCopy struct {
+	// Application is the namespace/name of the application that made the copy
+	Application string `json:"application"`
+	// DatasetRef is the reference to the storage of the copy, as in the status of the application that made it
+	DatasetRef string `json:"datasetRef"`
+}
+
+// AppliedAction is a governance action applied to an asset, or an operation on the asset denied by the governance policies
+type AppliedAction struct {
+	// Name of the action, e.g. redact, or Deny for a denied operation
+	Name string `json:"name"`
+	// Level at which the action is applied, e.g. COLUMN
+	// +optional
+	Level string `json:"level,omitempty"`
+	// Args of the action, e.g. the redacted columns
+	// +optional
+	Args map[string]string `json:"args,omitempty"`
+	// Flow in which the action is applied or the operation is denied
+	Flow ModuleFlow `json:"flow"`
+	// Module applying the action, empty for denials
+	// +optional
+	Module string `json:"module,omitempty"`
+	// Destination is the geography for which the operation is denied
+	// +optional
+	Destination string `json:"destination,omitempty"`
+}
+
+// AssetState describes how the governance policies affect the data of an asset served to the application
+type AssetState struct {
+	// Actions lists the governance actions applied to the data of the asset, and the denied operations
+	// +optional
+	Actions []AppliedAction `json:"actions,omitempty"`
+	// Health aggregates the readiness and liveness of the pods of the modules serving the asset, once they are deployed
+	// +optional
+	Health *ModuleHealth `json:"health,omitempty"`
+}
+
+// DirectAccessDetails contain the details for accessing a dataset in place, as received from the data catalog
+type DirectAccessDetails struct {
+	// Interface is the protocol and format of the dataset source
+	Interface InterfaceDetails `json:"interface"`
+	// Connection contains the connection details of the dataset source
+	// +optional
+	Connection serde.Arbitrary `json:"connection,omitempty"`
+	// CredentialsPath is the path in Vault from which the credentials of the dataset are read
+	// +optional
+	CredentialsPath string `json:"credentialsPath,omitempty"`
+}
+
+// OutdatedModule identifies the installed version of a module whose previous version is kept for an application
+type OutdatedModule struct {
+	// Chart is the chart of the installed version
+	Chart string `json:"chart"`
+	// Generation is the generation of the installed M4DModule, which changes with each upgrade even if the chart is unchanged
+	Generation int64 `json:"generation"`
 }
 
 // M4DApplicationStatus defines the observed state of M4DApplication.
@@ -156,9 +504,13 @@ type M4DApplicationStatus struct {
 	// Ready is true if a blueprint has been successfully orchestrated
 	Ready bool `json:"ready,omitempty"`
 
-	// Conditions represent the possible error and failure conditions
+	// Conditions represent the state of the application: whether it is ready, denied, failing with an error,
+	// waiting for the provisioning of storage, whether access to some of its datasets has been revoked,
+	// and whether its changes are pending while the manager is in read-only mode
 	// +optional
-	Conditions []Condition `json:"conditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// DataAccessInstructions indicate how the data user or his application may access the data.
 	// Instructions are available upon successful orchestration.
@@ -179,6 +531,13 @@ type M4DApplicationStatus struct {
 	// +optional
 	Generated *ResourceReference `json:"generated,omitempty"`
 
+	// OwnedResources lists the resources created by the manager for the application, i.e. the generated resource and
+	// the Dataset resources provisioning the storage of the copies. A resource is recorded before it is created,
+	// such that it is deleted with the application even if the manager restarts before the status is updated, and the
+	// owned resources that the application no longer references are deleted.
+	// +optional
+	OwnedResources []OwnedResource `json:"ownedResources,omitempty"`
+
 	// ProvisionedStorage maps a dataset (identified by AssetID) to the new provisioned bucket.
 	// It allows M4DApplication controller to manage buckets in case the spec has been modified, an error has occurred, or a delete event has been received.
 	// ProvisionedStorage has the information required to register the dataset once the owned plotter resource is ready
@@ -187,6 +546,90 @@ type M4DApplicationStatus struct {
 
 	// ReadEndpointsMap maps an datasetID (after parsing from json to a string with dashes) to the endpoint spec from which the asset will be served to the application
 	ReadEndpointsMap map[string]EndpointSpec `json:"readEndpointsMap,omitempty"`
+
+	// WriteEndpointsMap maps an datasetID (after parsing from json to a string with dashes) to the endpoint spec through which the application writes the asset
+	// +optional
+	WriteEndpointsMap map[string]EndpointSpec `json:"writeEndpointsMap,omitempty"`
+
+	// ObservedData maps a dataset to its requirements as specified in the last reconciled generation.
+	// It is used to compute the changes when the spec is modified.
+	// +optional
+	ObservedData map[string]DataRequirements `json:"observedData,omitempty"`
+
+	// SpecChanges summarizes the differences between the data requirements of the current generation and the previously reconciled one,
+	// e.g. datasets that have been added or removed and interfaces that have been changed.
+	// +optional
+	SpecChanges []string `json:"specChanges,omitempty"`
+
+	// RevokedDatasets maps the datasets whose access has been revoked by a M4DDatasetRevocation to the revocation reason
+	// +optional
+	RevokedDatasets map[string]string `json:"revokedDatasets,omitempty"`
+
+	// NegotiatedInterfaces maps the datasets whose requirements do not specify an interface to the negotiated interface,
+	// and the datasets served through one of their fallback interfaces to the satisfied fallback interface.
+	// The write flow of a dataset is keyed by the dataset identifier followed by "#write".
+	// +optional
+	NegotiatedInterfaces map[string]InterfaceDetails `json:"negotiatedInterfaces,omitempty"`
+
+	// StaleDatasets lists the datasets whose copies have been out of date for longer than the allowed staleness
+	// +optional
+	StaleDatasets []string `json:"staleDatasets,omitempty"`
+
+	// ExpiredCopies lists the datasets whose copies have been deleted once their TTL has expired
+	// +optional
+	ExpiredCopies []string `json:"expiredCopies,omitempty"`
+
+	// CatalogHashes maps a dataset to a hash of its metadata in the data catalog, as used by the last evaluation.
+	// A change of the metadata (e.g. tags, geography, format) triggers a new evaluation.
+	// +optional
+	CatalogHashes map[string]string `json:"catalogHashes,omitempty"`
+
+	// DirectAccess maps the datasets accessed in place to the details of their source.
+	// The egress of the workload outside of the cluster is restricted to these sources by the network policy
+	// <application name>-in-place, which is created in the namespace of the application.
+	// The write flow of a dataset is keyed by the dataset identifier followed by "#write".
+	// +optional
+	DirectAccess map[string]DirectAccessDetails `json:"directAccess,omitempty"`
+
+	// MismatchedPerformanceClasses maps the datasets for which no read module of the requested performance class is available
+	// to a description of the selected module class
+	// +optional
+	MismatchedPerformanceClasses map[string]string `json:"mismatchedPerformanceClasses,omitempty"`
+
+	// RetainedCopies maps the datasets whose copies will be kept when the application is deleted, according to
+	// spec.copyCleanupPolicy, to the provisioned buckets
+	// +optional
+	RetainedCopies map[string]string `json:"retainedCopies,omitempty"`
+
+	// PromotedCopies maps the datasets whose copies have been detached from the application by a M4DCopyPromotion
+	// to the provisioned buckets, which are no longer managed by the application
+	// +optional
+	PromotedCopies map[string]string `json:"promotedCopies,omitempty"`
+
+	// GrantedCopies maps the datasets read from the copies of other applications, shared by a M4DGrant, to these copies
+	// +optional
+	GrantedCopies map[string]GrantedCopy `json:"grantedCopies,omitempty"`
+
+	// DebugInfo maps the datasets for which a module has not been found to the reasons for which
+	// the installed modules have been rejected, e.g. "read: arrow-flight-module: exposes no API matching s3/csv"
+	// +optional
+	DebugInfo map[string][]string `json:"debugInfo,omitempty"`
+
+	// DeployedModules maps the modules deployed for the application to their specs, as orchestrated by the last evaluation.
+	// The previous version of a module upgraded with the Manual policy is deployed from its spec recorded here.
+	// +optional
+	DeployedModules map[string]M4DModuleSpec `json:"deployedModules,omitempty"`
+
+	// OutdatedModules maps the modules whose previous version is kept for the application, until their upgrade is approved,
+	// to their installed version
+	// +optional
+	OutdatedModules map[string]OutdatedModule `json:"outdatedModules,omitempty"`
+
+	// AssetStates maps the assets to the governance actions applied to their data, so that users know
+	// whether the data they receive is transformed, and to the health of the modules serving them.
+	// Assets served as is without modules are not listed.
+	// +optional
+	AssetStates map[string]AssetState `json:"assetStates,omitempty"`
 }
 
 // M4DApplication provides information about the application being used by a Data Scientist,
@@ -197,6 +640,7 @@ type M4DApplicationStatus struct {
 // in it.  This runtime environment provides the Data Scientist's application with access to the data requested
 // in a secure manner and without having to provide any credentials for the data sets.  The credentials are obtained automatically
 // by the manager from an external credential management system, which may or may not be part of a data catalog.
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 type M4DApplication struct {
@@ -224,4 +668,21 @@ const (
 	ApplicationClusterLabel   = "app.m4d.ibm.com/appCluster"
 	ApplicationNamespaceLabel = "app.m4d.ibm.com/appNamespace"
 	ApplicationNameLabel      = "app.m4d.ibm.com/appName"
+	DatasetIDsAnnotation      = "app.m4d.ibm.com/datasetIDs"
+	// TrustedWorkloadsLabel is set to "true" by the administrators on the namespaces whose workloads may access data in place
+	TrustedWorkloadsLabel = "app.m4d.ibm.com/trustedWorkloads"
+	// ChainedToAnnotation names the module reading the output of a module of a transformation chain
+	ChainedToAnnotation = "app.m4d.ibm.com/chainedTo"
+	// ApplicationVersionLabel records the generation of the application on the resources generated for it.
+	// The status of a resource generated for an older generation is not propagated to the application.
+	ApplicationVersionLabel = "app.m4d.ibm.com/appVersion"
+	// ChangedBlueprintsAnnotation lists the clusters whose blueprints have been changed by the updates of a plotter spec
+	// since the last generation observed by the plotter controller
+	ChangedBlueprintsAnnotation = "app.m4d.ibm.com/changedBlueprints"
+	// AppliedBlueprintsAnnotation lists the clusters whose blueprints have been last written to a plotter spec by the
+	// M4DApplication controller. The blueprints of the other clusters have been added by others and are left untouched.
+	AppliedBlueprintsAnnotation = "app.m4d.ibm.com/appliedBlueprints"
+	// UpgradeModulesAnnotation lists the modules with the Manual upgrade policy that are migrated to their installed version
+	// for the application, separated by commas. The upgrades of the listed modules are approved until the annotation is removed.
+	UpgradeModulesAnnotation = "app.m4d.ibm.com/upgradeModules"
 )
@@ -4,23 +4,143 @@
 package v1alpha1
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	log "log"
+	"strings"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/mesh-for-data/mesh-for-data/pkg/capabilities/match"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+// moduleReader is used to list the installed modules when the validation of requested interfaces is enabled
+var moduleReader client.Reader
+
+// modulesNamespace is the namespace in which the modules are installed
+var modulesNamespace string
+
+// EnableInterfaceValidation enables the admission check of the requested interfaces against capabilities of the installed modules.
+// Applications requesting an interface that no installed module supports are rejected.
+func EnableInterfaceValidation(reader client.Reader, namespace string) {
+	moduleReader = reader
+	modulesNamespace = namespace
+}
+
+// appInfoValidator validates the application details against the application taxonomy, if set
+var appInfoValidator *taxonomy.Validator
+
+// EnableAppInfoValidation enables the admission check of the application details against the app_info definition
+// of the application taxonomy. The validator is compiled once and shared by all the admission requests.
+func EnableAppInfoValidation(validator *taxonomy.Validator) {
+	appInfoValidator = validator
+}
+
+// profileReader is used to fetch the M4DApplicationProfile referenced by an application
+var profileReader client.Reader
+
+// EnableProfiles enables the expansion of the M4DApplicationProfile referenced by an application upon admission.
+func EnableProfiles(reader client.Reader) {
+	profileReader = reader
+}
+
 func (r *M4DApplication) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
 }
 
+// +kubebuilder:webhook:verbs=create;update,admissionReviewVersions=v1;v1beta1,sideEffects=None,path=/mutate-app-m4d-ibm-com-v1alpha1-m4dapplication,mutating=true,failurePolicy=fail,groups=app.m4d.ibm.com,resources=m4dapplications,versions=v1alpha1,name=mm4dapplication.kb.io
+
+var _ webhook.Defaulter = &M4DApplication{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// The dataset IDs of the spec are normalized if enabled, those keying the status are normalized by the controller
+// since the changes of the status are ignored upon admission. The data requirements that are not specified are
+// filled in from the referenced profile. A missing profile is reported by the validation.
+func (r *M4DApplication) Default() {
+	if datasetIDNormalizer != nil {
+		datasetIDNormalizer.NormalizeSpec(r)
+	}
+	if r.Spec.Profile == "" || profileReader == nil {
+		return
+	}
+	profile := &M4DApplicationProfile{}
+	key := client.ObjectKey{Namespace: r.Namespace, Name: r.Spec.Profile}
+	if err := profileReader.Get(context.Background(), key, profile); err != nil {
+		log.Printf("Could not get profile %s of m4dapplication %s: %v", r.Spec.Profile, r.Name, err)
+		return
+	}
+	log.Printf("Applying profile %s to m4dapplication %s", r.Spec.Profile, r.Name)
+	r.ApplyProfile(profile)
+}
+
+// ApplyProfile fills in the data requirements that are not specified with the defaults of the given profile.
+// The interface is taken from the profile only if neither the protocol nor the format is specified.
+// Copy is required if either the application or the profile requires it.
+// The cluster of the workload is taken from the placement of the profile if the application does not specify one.
+func (r *M4DApplication) ApplyProfile(profile *M4DApplicationProfile) {
+	if r.Spec.Selector.ClusterName == "" {
+		r.Spec.Selector.ClusterName = profile.Spec.Placement.ClusterName
+	}
+	defaults := &profile.Spec.Requirements
+	for i := range r.Spec.Data {
+		requirements := &r.Spec.Data[i].Requirements
+		if requirements.Interface.Protocol == "" && requirements.Interface.DataFormat == "" {
+			requirements.Interface = defaults.Interface
+		}
+		if defaults.Copy.Required {
+			requirements.Copy.Required = true
+		}
+		if requirements.PerformanceClass == "" {
+			requirements.PerformanceClass = defaults.PerformanceClass
+		}
+		if requirements.PrivacyLevel == "" {
+			requirements.PrivacyLevel = defaults.PrivacyLevel
+		}
+		if requirements.Geography == "" {
+			requirements.Geography = defaults.Geography
+		}
+		if requirements.Copy.Catalog.CatalogService == "" {
+			requirements.Copy.Catalog.CatalogService = defaults.Copy.Catalog.CatalogService
+		}
+		if requirements.Copy.Catalog.CatalogID == "" {
+			requirements.Copy.Catalog.CatalogID = defaults.Copy.Catalog.CatalogID
+		}
+		if requirements.Copy.MaxStaleness == nil && defaults.Copy.MaxStaleness != nil {
+			maxStaleness := *defaults.Copy.MaxStaleness
+			requirements.Copy.MaxStaleness = &maxStaleness
+		}
+	}
+}
+
+// FirstOccurrence returns the index of the first data context listing the same dataset as the data context at index i.
+// A dataset listed several times with identical requirements is processed once, as if it was listed once.
+// A dataset listed several times with different requirements is rejected, see HasSameRequirements.
+func (r *M4DApplication) FirstOccurrence(i int) int {
+	for j := 0; j < i; j++ {
+		if r.Spec.Data[j].DataSetID == r.Spec.Data[i].DataSetID {
+			return j
+		}
+	}
+	return i
+}
+
+// HasSameRequirements returns true if the data contexts at the given indices have the same flows and requirements
+func (r *M4DApplication) HasSameRequirements(i int, j int) bool {
+	return equality.Semantic.DeepEqual(r.Spec.Data[i].GetFlows(), r.Spec.Data[j].GetFlows()) &&
+		equality.Semantic.DeepEqual(r.Spec.Data[i].Requirements, r.Spec.Data[j].Requirements)
+}
+
 // +kubebuilder:webhook:verbs=create;update,admissionReviewVersions=v1;v1beta1,sideEffects=None,path=/validate-app-m4d-ibm-com-v1alpha1-m4dapplication,mutating=false,failurePolicy=fail,groups=app.m4d.ibm.com,resources=m4dapplications,versions=v1alpha1,name=vm4dapplication.kb.io
 
 var _ webhook.Validator = &M4DApplication{}
@@ -71,18 +191,253 @@ func (r *M4DApplication) validateM4DApplicationSpec() []*field.Error {
 		if err := r.validateDataContext(specField.Index(i), &dataSet); err != nil {
 			allErrs = append(allErrs, err...)
 		}
+		if first := r.FirstOccurrence(i); first != i && !r.HasSameRequirements(i, first) {
+			allErrs = append(allErrs, field.Invalid(specField.Index(i).Child("DataSetID"), dataSet.DataSetID,
+				fmt.Sprintf("the dataset is already listed in %s with different requirements", specField.Index(first).String())))
+		}
+	}
+	allErrs = append(allErrs, r.validatePinnedModules(field.NewPath("spec").Child("pinnedModules"))...)
+	allErrs = append(allErrs, r.validateModuleVersions(field.NewPath("spec").Child("moduleVersions"))...)
+	if appInfoValidator != nil {
+		if err := appInfoValidator.Validate(r.Spec.AppInfo); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("appInfo"), r.Spec.AppInfo, err.Error()))
+		}
+	}
+	if r.Spec.Profile != "" && profileReader != nil {
+		if err := r.validateProfile(field.NewPath("spec").Child("profile")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if len(allErrs) == 0 && moduleReader != nil {
+		allErrs = append(allErrs, r.validateSupportedInterfaces(specField)...)
+	}
+	return allErrs
+}
+
+// validateSupportedInterfaces checks that each requested interface, or one of its fallback interfaces, is supported
+// by at least one installed module: by the API of a read or write module if the application has a workload,
+// or by the sink of a copy module otherwise.
+func (r *M4DApplication) validateSupportedInterfaces(path *field.Path) []*field.Error {
+	var allErrs []*field.Error
+	var moduleList M4DModuleList
+	if err := moduleReader.List(context.Background(), &moduleList, client.InNamespace(modulesNamespace)); err != nil {
+		return append(allErrs, field.InternalError(path, err))
+	}
+	hasWorkload := r.Spec.Selector.WorkloadSelector.Size() != 0
+	for i, dataSet := range r.Spec.Data {
+		// no module is required for datasets accessed in place
+		if dataSet.Requirements.InPlace {
+			continue
+		}
+		reported := map[string]bool{}
+		// each flow of the dataset is served by its own module, in the interface of the flow
+		for _, flow := range dataSet.GetFlows() {
+			requested := dataSet.Requirements.GetInterface(flow)
+			if requested == (InterfaceDetails{}) {
+				continue
+			}
+			interfacePath := path.Index(i).Child("Requirements", "Interface")
+			candidates := append([]InterfaceDetails{requested}, dataSet.Requirements.FallbackInterfaces...)
+			if flow == Write && dataSet.Requirements.WriteInterface != nil {
+				interfacePath = path.Index(i).Child("Requirements", "WriteInterface")
+				candidates = []InterfaceDetails{requested}
+			}
+			supported := false
+			for j := 0; j < len(moduleList.Items) && !supported; j++ {
+				for k := range candidates {
+					if supportsRequestedInterface(&moduleList.Items[j], &candidates[k], hasWorkload, flow) {
+						supported = true
+						break
+					}
+				}
+			}
+			// an interface shared by the flows is reported once
+			if !supported && !reported[interfacePath.String()] {
+				allErrs = append(allErrs, field.Invalid(interfacePath, &requested,
+					fmt.Sprintf("%s: no installed module supports protocol %s and format %s", ModuleNotFound, requested.Protocol, requested.DataFormat)))
+				reported[interfacePath.String()] = true
+			}
+		}
 	}
 	return allErrs
 }
 
+// supportsRequestedInterface returns true if the module supports the requested interface, in the API of the module
+// if the application has a workload, or in the sink of a copy otherwise
+func supportsRequestedInterface(module *M4DModule, requested *InterfaceDetails, hasWorkload bool, flow ModuleFlow) bool {
+	supports := func(declared *InterfaceDetails) bool {
+		return declared != nil && match.Interface(declared.Protocol, declared.DataFormat, requested.Protocol, requested.DataFormat)
+	}
+	if hasWorkload {
+		if flow == Write && !supportsFlow(module, Write) {
+			return false
+		}
+		if api := module.Spec.Capabilities.API; api != nil && supports(&api.InterfaceDetails) {
+			return true
+		}
+		for i := range module.Spec.Capabilities.APIs {
+			if supports(&module.Spec.Capabilities.APIs[i].InterfaceDetails) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, inter := range module.Spec.Capabilities.SupportedInterfaces {
+		if inter.Flow == Copy && supports(inter.Sink) {
+			return true
+		}
+	}
+	return false
+}
+
+func supportsFlow(module *M4DModule, flow ModuleFlow) bool {
+	for _, supported := range module.Spec.Flows {
+		if supported == flow {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *M4DApplication) validateDataContext(path *field.Path, dataSet *DataContext) []*field.Error {
 	var allErrs []*field.Error
-	interfacePath := path.Child("Requirements", "Interface")
-	if err := validateProtocol(dataSet.Requirements.Interface.Protocol); err != nil {
-		allErrs = append(allErrs, field.Invalid(interfacePath.Child("Protocol"), &dataSet.Requirements.Interface.Protocol, err.Error()))
+	flowPath := path.Child("Flow")
+	switch dataSet.Flow {
+	case "", Read, Write:
+	default:
+		allErrs = append(allErrs, field.NotSupported(flowPath, dataSet.Flow, []string{string(Read), string(Write)}))
+	}
+	// a dataset that is both read and written lists its flows, each of them once
+	if len(dataSet.Flows) > 0 {
+		flowsPath := path.Child("Flows")
+		listed := map[ModuleFlow]bool{}
+		for j, flow := range dataSet.Flows {
+			switch {
+			case flow != Read && flow != Write:
+				allErrs = append(allErrs, field.NotSupported(flowsPath.Index(j), flow, []string{string(Read), string(Write)}))
+			case listed[flow]:
+				allErrs = append(allErrs, field.Duplicate(flowsPath.Index(j), flow))
+			}
+			listed[flow] = true
+		}
+		if dataSet.Flow != "" && !listed[dataSet.Flow] {
+			allErrs = append(allErrs, field.Invalid(flowPath, dataSet.Flow, "the flow is not listed in the flows of the dataset"))
+		}
+		flowPath = flowsPath
+	}
+	if dataSet.HasFlow(Write) {
+		if r.Spec.Selector.WorkloadSelector.Size() == 0 {
+			allErrs = append(allErrs, field.Invalid(flowPath, Write, "data can be written only by a workload"))
+		}
+		if dataSet.Requirements.Copy.Required || dataSet.Requirements.InPlace {
+			allErrs = append(allErrs, field.Invalid(flowPath, Write, "written data can not be copied or accessed in place"))
+		}
+		if dataSet.Requirements.GetInterface(Write) == (InterfaceDetails{}) {
+			allErrs = append(allErrs, field.Required(path.Child("Requirements", "Interface"), "the interface is required for writing data"))
+		}
+	}
+	if writeInterface := dataSet.Requirements.WriteInterface; writeInterface != nil {
+		writeInterfacePath := path.Child("Requirements", "WriteInterface")
+		if !dataSet.HasFlow(Write) {
+			allErrs = append(allErrs, field.Invalid(writeInterfacePath, writeInterface, "a write interface applies only to data that is written"))
+		}
+		allErrs = append(allErrs, validateInterface(writeInterfacePath, writeInterface)...)
+	}
+	if dataSet.Requirements.InPlace {
+		inPlacePath := path.Child("Requirements", "InPlace")
+		if dataSet.Requirements.Copy.Required {
+			allErrs = append(allErrs, field.Invalid(inPlacePath, dataSet.Requirements.InPlace, "data accessed in place can not be copied"))
+		}
+		if r.Spec.Selector.WorkloadSelector.Size() == 0 {
+			allErrs = append(allErrs, field.Invalid(inPlacePath, dataSet.Requirements.InPlace, "data can be accessed in place only by a workload"))
+		}
+	}
+	if privacyLevel := dataSet.Requirements.PrivacyLevel; privacyLevel != "" {
+		privacyPath := path.Child("Requirements", "PrivacyLevel")
+		if !dataSet.HasFlow(Read) {
+			allErrs = append(allErrs, field.Invalid(privacyPath, privacyLevel, "a privacy level applies only to data that is read"))
+		}
+		if dataSet.Requirements.InPlace {
+			allErrs = append(allErrs, field.Invalid(privacyPath, privacyLevel, "data accessed in place can not be transformed to a privacy level"))
+		}
+	}
+	if geography := dataSet.Requirements.Geography; geography != "" {
+		geographyPath := path.Child("Requirements", "Geography")
+		switch {
+		case strings.TrimSpace(geography) != geography:
+			allErrs = append(allErrs, field.Invalid(geographyPath, geography, "geography must not contain leading or trailing whitespace"))
+		case len(knownGeographies) > 0 && !containsGeography(knownGeographies, geography):
+			allErrs = append(allErrs, field.NotSupported(geographyPath, geography, knownGeographies))
+		}
+		if dataSet.Requirements.InPlace {
+			allErrs = append(allErrs, field.Invalid(geographyPath, geography, "data accessed in place is processed by the workload"))
+		}
+	}
+	// the interface is negotiated by the manager if it is not specified
+	if dataSet.Requirements.Interface == (InterfaceDetails{}) {
+		if len(dataSet.Requirements.FallbackInterfaces) > 0 {
+			allErrs = append(allErrs, field.Required(path.Child("Requirements", "Interface"), "the interface is required if fallback interfaces are specified"))
+		}
+		return allErrs
+	}
+	allErrs = append(allErrs, validateInterface(path.Child("Requirements", "Interface"), &dataSet.Requirements.Interface)...)
+	for i := range dataSet.Requirements.FallbackInterfaces {
+		allErrs = append(allErrs, validateInterface(path.Child("Requirements", "FallbackInterfaces").Index(i), &dataSet.Requirements.FallbackInterfaces[i])...)
+	}
+	return allErrs
+}
+
+// validatePinnedModules checks that the modules are pinned for the datasets of the application, once per flow
+func (r *M4DApplication) validatePinnedModules(path *field.Path) []*field.Error {
+	var allErrs []*field.Error
+	pinned := map[string]bool{}
+	for i, pin := range r.Spec.PinnedModules {
+		pinPath := path.Index(i)
+		if !r.hasDataset(pin.DataSetID) {
+			allErrs = append(allErrs, field.Invalid(pinPath.Child("dataSetID"), pin.DataSetID, "the dataset is not listed in spec.data"))
+		}
+		switch pin.Flow {
+		case Read, Copy, Write:
+		default:
+			allErrs = append(allErrs, field.NotSupported(pinPath.Child("flow"), pin.Flow, []string{string(Read), string(Copy), string(Write)}))
+		}
+		key := pin.DataSetID + "/" + string(pin.Flow)
+		if pinned[key] {
+			allErrs = append(allErrs, field.Duplicate(pinPath, key))
+		}
+		pinned[key] = true
+	}
+	return allErrs
+}
+
+// validateModuleVersions checks that the version ranges of the modules are semantic version ranges
+func (r *M4DApplication) validateModuleVersions(path *field.Path) []*field.Error {
+	var allErrs []*field.Error
+	for chart, versions := range r.Spec.ModuleVersions {
+		if _, err := semver.NewConstraint(versions); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Key(chart), versions, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// hasDataset returns true if the dataset is listed in the data of the application
+func (r *M4DApplication) hasDataset(datasetID string) bool {
+	for _, dataCtx := range r.Spec.Data {
+		if dataCtx.DataSetID == datasetID {
+			return true
+		}
+	}
+	return false
+}
+
+func validateInterface(interfacePath *field.Path, inter *InterfaceDetails) []*field.Error {
+	var allErrs []*field.Error
+	if err := validateProtocol(inter.Protocol); err != nil {
+		allErrs = append(allErrs, field.Invalid(interfacePath.Child("Protocol"), &inter.Protocol, err.Error()))
 	}
-	if err := validateDataFormat(dataSet.Requirements.Interface.DataFormat); err != nil {
-		allErrs = append(allErrs, field.Invalid(interfacePath.Child("DataFormat"), &dataSet.Requirements.Interface.DataFormat, err.Error()))
+	if err := validateDataFormat(inter.DataFormat); err != nil {
+		allErrs = append(allErrs, field.Invalid(interfacePath.Child("DataFormat"), &inter.DataFormat, err.Error()))
 	}
 	return allErrs
 }
@@ -104,3 +459,16 @@ func validateDataFormat(format string) error {
 		return errors.New("Value should be one of these: parquet, table, csv, json, avro, orc, binary, arrow")
 	}
 }
+
+// validateProfile checks that the referenced profile exists in the namespace of the application
+func (r *M4DApplication) validateProfile(path *field.Path) *field.Error {
+	profile := &M4DApplicationProfile{}
+	key := client.ObjectKey{Namespace: r.Namespace, Name: r.Spec.Profile}
+	if err := profileReader.Get(context.Background(), key, profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.NotFound(path, r.Spec.Profile)
+		}
+		return field.InternalError(path, err)
+	}
+	return nil
+}
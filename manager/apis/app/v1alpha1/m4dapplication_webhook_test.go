@@ -0,0 +1,339 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	"github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestApplyProfile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	profile := &M4DApplicationProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "analytics", Namespace: "default"},
+		Spec: M4DApplicationProfileSpec{
+			Requirements: DataRequirements{
+				Interface: InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"},
+				Copy:      CopyRequirements{Catalog: CatalogRequirements{CatalogID: "enterprise"}},
+				Geography: "theshire",
+			},
+			Placement: ProfilePlacement{ClusterName: "analytics-cluster"},
+		},
+	}
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Profile: "analytics",
+			Data: []DataContext{
+				{DataSetID: "s3/allow-dataset"},
+				{
+					DataSetID: "db2/redact-dataset",
+					Requirements: DataRequirements{
+						Interface: InterfaceDetails{Protocol: "s3", DataFormat: "parquet"},
+						Copy:      CopyRequirements{Required: true},
+						Geography: "neverland",
+					},
+				},
+			},
+		},
+	}
+	application.ApplyProfile(profile)
+
+	// unspecified requirements are taken from the profile
+	g.Expect(application.Spec.Data[0].Requirements.Interface).To(gomega.Equal(profile.Spec.Requirements.Interface))
+	g.Expect(application.Spec.Data[0].Requirements.Copy.Required).To(gomega.BeFalse())
+	g.Expect(application.Spec.Data[0].Requirements.Copy.Catalog.CatalogID).To(gomega.Equal("enterprise"))
+	g.Expect(application.Spec.Data[0].Requirements.Geography).To(gomega.Equal("theshire"))
+	g.Expect(application.Spec.Selector.ClusterName).To(gomega.Equal("analytics-cluster"))
+	// specified requirements are kept
+	g.Expect(application.Spec.Data[1].Requirements.Interface.Protocol).To(gomega.Equal("s3"))
+	g.Expect(application.Spec.Data[1].Requirements.Interface.DataFormat).To(gomega.Equal("parquet"))
+	g.Expect(application.Spec.Data[1].Requirements.Copy.Required).To(gomega.BeTrue())
+	g.Expect(application.Spec.Data[1].Requirements.Geography).To(gomega.Equal("neverland"))
+
+	// the cluster specified by the application is kept
+	application.Spec.Selector.ClusterName = "edge-cluster"
+	application.ApplyProfile(profile)
+	g.Expect(application.Spec.Selector.ClusterName).To(gomega.Equal("edge-cluster"))
+}
+
+func TestValidateDuplicateDatasets(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	requirements := DataRequirements{Interface: InterfaceDetails{Protocol: "s3", DataFormat: "parquet"}}
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Data: []DataContext{
+				{DataSetID: "s3/allow-dataset", Requirements: requirements},
+				{DataSetID: "db2/redact-dataset", Requirements: requirements},
+				{DataSetID: "s3/allow-dataset", Requirements: requirements},
+			},
+		},
+	}
+	// a dataset listed twice with the same requirements is accepted
+	g.Expect(application.FirstOccurrence(2)).To(gomega.Equal(0))
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+
+	application.Spec.Data[2].Requirements.Interface.DataFormat = "csv"
+	err := application.validateM4DApplication()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("already listed in spec.data[0]"))
+}
+
+func TestValidateAppInfo(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	validator, err := taxonomy.CachedValidator("../../../../charts/m4d/files/taxonomy/application.values.schema.json", "app_info")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	EnableAppInfoValidation(validator)
+	defer EnableAppInfoValidation(nil)
+
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			AppInfo: ApplicationDetails{"intent": "Fraud Detection", "role": "Data Scientist"},
+			Data:    []DataContext{{DataSetID: "s3/allow-dataset"}},
+		},
+	}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+
+	application.Spec.AppInfo["intent"] = "Curiosity"
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+}
+
+func TestValidateWriteFlow(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Data: []DataContext{
+				{
+					DataSetID:    "s3/allow-dataset",
+					Flow:         Write,
+					Requirements: DataRequirements{Interface: InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"}},
+				},
+			},
+		},
+	}
+	// data can be written only by a workload
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+
+	application.Spec.Selector.WorkloadSelector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "notebook"}}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+
+	// the interface is not negotiated for written data
+	application.Spec.Data[0].Requirements.Interface = InterfaceDetails{}
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+
+	application.Spec.Data[0].Flow = Copy
+	application.Spec.Data[0].Requirements.Interface = InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"}
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+}
+
+func TestValidateReadWriteFlows(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "training", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Selector: Selector{WorkloadSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "training"}}},
+			Data: []DataContext{
+				{
+					DataSetID:    "s3/allow-dataset",
+					Flows:        []ModuleFlow{Read, Write},
+					Requirements: DataRequirements{Interface: InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"}},
+				},
+			},
+		},
+	}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+	g.Expect(application.Spec.Data[0].HasFlow(Write)).To(gomega.BeTrue())
+
+	// the written data can not be copied
+	application.Spec.Data[0].Requirements.Copy.Required = true
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+	application.Spec.Data[0].Requirements.Copy.Required = false
+
+	// each flow is listed once, and the flow must be one of them
+	application.Spec.Data[0].Flows = []ModuleFlow{Read, Read}
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+	application.Spec.Data[0].Flows = []ModuleFlow{Read, Copy}
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+	application.Spec.Data[0].Flows = []ModuleFlow{Read}
+	application.Spec.Data[0].Flow = Write
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+}
+
+func TestValidateWriteInterface(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "training", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Selector: Selector{WorkloadSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "training"}}},
+			Data: []DataContext{
+				{
+					DataSetID: "s3/allow-dataset",
+					Flows:     []ModuleFlow{Read, Write},
+					Requirements: DataRequirements{
+						Interface:      InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"},
+						WriteInterface: &InterfaceDetails{Protocol: "s3", DataFormat: "parquet"},
+					},
+				},
+			},
+		},
+	}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+	g.Expect(application.Spec.Data[0].Requirements.GetInterface(Read).Protocol).To(gomega.Equal("m4d-arrow-flight"))
+	g.Expect(application.Spec.Data[0].Requirements.GetInterface(Write).Protocol).To(gomega.Equal("s3"))
+
+	// the write interface is validated as the interface is
+	application.Spec.Data[0].Requirements.WriteInterface = &InterfaceDetails{Protocol: "ftp", DataFormat: "parquet"}
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+
+	// the interface of data that is only written may be specified by the write interface alone
+	application.Spec.Data[0].Flows = nil
+	application.Spec.Data[0].Flow = Write
+	application.Spec.Data[0].Requirements.Interface = InterfaceDetails{}
+	application.Spec.Data[0].Requirements.WriteInterface = &InterfaceDetails{Protocol: "s3", DataFormat: "parquet"}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+
+	// a write interface applies only to data that is written
+	application.Spec.Data[0].Flow = Read
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+}
+
+func TestValidatePrivacyLevel(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "training", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Selector: Selector{WorkloadSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "training"}}},
+			Data: []DataContext{
+				{
+					DataSetID: "s3/allow-dataset",
+					Requirements: DataRequirements{Interface: InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"},
+						PrivacyLevel: "anonymized"},
+				},
+			},
+		},
+	}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+
+	// the data accessed in place is not transformed
+	application.Spec.Data[0].Requirements.InPlace = true
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+	application.Spec.Data[0].Requirements.InPlace = false
+
+	// the written data has no privacy level
+	application.Spec.Data[0].Flow = Write
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+}
+
+func TestValidatePinnedModules(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Data: []DataContext{
+				{DataSetID: "s3/allow-dataset", Requirements: DataRequirements{Interface: InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"}}},
+			},
+			PinnedModules: []PinnedModule{{DataSetID: "s3/allow-dataset", Flow: Read, Module: "arrow-flight-module"}},
+		},
+	}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+
+	// modules are pinned once per flow of the datasets of the application
+	application.Spec.PinnedModules = append(application.Spec.PinnedModules, PinnedModule{DataSetID: "s3/allow-dataset", Flow: Read, Module: "other"})
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+	application.Spec.PinnedModules[1] = PinnedModule{DataSetID: "s3/deny-dataset", Flow: Copy, Module: "implicit-copy"}
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+}
+
+func TestValidateModuleVersions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Data: []DataContext{
+				{DataSetID: "s3/allow-dataset", Requirements: DataRequirements{Interface: InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"}}},
+			},
+			ModuleVersions: map[string]string{"ghcr.io/mesh-for-data/arrow-flight-module": ">=0.1.0, <0.3.0"},
+		},
+	}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+
+	application.Spec.ModuleVersions["ghcr.io/mesh-for-data/implicit-copy"] = "not-a-range"
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+}
+
+func TestPinnedModulesValidator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(gomega.Succeed())
+	decoder, err := admission.NewDecoder(scheme)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	validator := &PinnedModulesValidator{AdminGroups: DefaultPinningAdminGroups}
+	g.Expect(validator.InjectDecoder(decoder)).To(gomega.Succeed())
+
+	application := &M4DApplication{
+		TypeMeta:   metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: "M4DApplication"},
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec:       M4DApplicationSpec{Data: []DataContext{{DataSetID: "s3/allow-dataset"}}},
+	}
+	pinned := application.DeepCopy()
+	pinned.Spec.PinnedModules = []PinnedModule{{DataSetID: "s3/allow-dataset", Flow: Read, Module: "arrow-flight-module"}}
+	request := func(operation admissionv1.Operation, obj *M4DApplication, old *M4DApplication, groups ...string) admission.Request {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			UserInfo:  authenticationv1.UserInfo{Username: "user", Groups: groups},
+		}}
+		req.Object.Raw, err = json.Marshal(obj)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		if old != nil {
+			req.OldObject.Raw, err = json.Marshal(old)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		}
+		return req
+	}
+
+	// applications without pinned modules are admitted
+	g.Expect(validator.Handle(context.Background(), request(admissionv1.Create, application, nil, "system:authenticated")).Allowed).To(gomega.BeTrue())
+	// only administrators may pin modules
+	g.Expect(validator.Handle(context.Background(), request(admissionv1.Create, pinned, nil, "system:authenticated")).Allowed).To(gomega.BeFalse())
+	g.Expect(validator.Handle(context.Background(), request(admissionv1.Update, pinned, application, "system:authenticated")).Allowed).To(gomega.BeFalse())
+	g.Expect(validator.Handle(context.Background(), request(admissionv1.Update, pinned, application, "system:masters")).Allowed).To(gomega.BeTrue())
+	// other changes of an application with pinned modules are admitted
+	updated := pinned.DeepCopy()
+	updated.Spec.Data = append(updated.Spec.Data, DataContext{DataSetID: "s3/redact-dataset"})
+	g.Expect(validator.Handle(context.Background(), request(admissionv1.Update, updated, pinned, "system:authenticated")).Allowed).To(gomega.BeTrue())
+	g.Expect(validator.Handle(context.Background(), request(admissionv1.Update, application, pinned, "system:authenticated")).Allowed).To(gomega.BeFalse())
+}
+
+func TestValidateGeography(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	SetGeographies([]string{"Netherlands", "Turkey"})
+	defer SetGeographies(nil)
+	application := &M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: M4DApplicationSpec{
+			Data: []DataContext{{DataSetID: "s3/allow-dataset", Requirements: DataRequirements{
+				Interface: InterfaceDetails{Protocol: "m4d-arrow-flight", DataFormat: "arrow"},
+				Geography: "Netherlands",
+			}}},
+		},
+	}
+	g.Expect(application.validateM4DApplication()).To(gomega.Succeed())
+
+	application.Spec.Data[0].Requirements.Geography = "Netherlands "
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+	application.Spec.Data[0].Requirements.Geography = "Atlantis"
+	g.Expect(application.validateM4DApplication()).NotTo(gomega.Succeed())
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// M4DApplicationProfileSpec contains the default requirements of the applications referencing the profile
+type M4DApplicationProfileSpec struct {
+	// Requirements are applied to every dataset of a referencing application.
+	// Values that are specified explicitly by the application are kept.
+	// +required
+	Requirements DataRequirements `json:"requirements"`
+
+	// Placement contains the defaults of the placement of the workloads of the referencing applications
+	// +optional
+	Placement ProfilePlacement `json:"placement,omitempty"`
+}
+
+// ProfilePlacement contains the placement defaults of a profile.
+// The region in which the datasets are processed is set by the geography of the requirements.
+type ProfilePlacement struct {
+	// ClusterName is the cluster in which the workload runs, if the application does not specify one
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// M4DApplicationProfile contains reusable requirement defaults for M4DApplication resources.
+// An application references a profile by name in its namespace, and the requirements that the
+// application does not specify are filled in from the profile when the application is admitted.
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Protocol",type=string,JSONPath=`.spec.requirements.interface.protocol`
+// +kubebuilder:printcolumn:name="DataFormat",type=string,JSONPath=`.spec.requirements.interface.dataformat`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type M4DApplicationProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec M4DApplicationProfileSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// M4DApplicationProfileList contains a list of M4DApplicationProfile
+type M4DApplicationProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []M4DApplicationProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&M4DApplicationProfile{}, &M4DApplicationProfileList{})
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// M4DCopyPromotionSpec defines the copy that is detached from its application
+type M4DCopyPromotionSpec struct {
+	// Application is the name of the M4DApplication, in the namespace of the promotion, that made the copy
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Application string `json:"application"`
+
+	// DataSetID is the identifier of the copied dataset, as specified in the M4DApplication
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	DataSetID string `json:"dataSetID"`
+}
+
+// M4DCopyPromotionStatus reports the standalone dataset made of the copy
+type M4DCopyPromotionStatus struct {
+	// Promoted is set once the copy has been detached from the application
+	// +optional
+	Promoted bool `json:"promoted,omitempty"`
+
+	// AssetID is the identifier of the copy in the data catalog
+	// +optional
+	AssetID string `json:"assetID,omitempty"`
+
+	// DatasetRef is the name of the Dataset resource, in the control plane namespace, provisioning the storage of the copy
+	// +optional
+	DatasetRef string `json:"datasetRef,omitempty"`
+
+	// StorageType is the type of the storage account in which the storage has been provisioned, s3 if empty
+	// +optional
+	StorageType string `json:"storageType,omitempty"`
+
+	// SecretRef is the name of the secret, in the control plane namespace, holding the credentials of the storage of the copy.
+	// The secret is a copy of the secret of the storage account, so that the dataset remains accessible if the account changes.
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// Message explains why the copy has not been promoted yet
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// M4DCopyPromotion detaches the copy of a dataset made by an application, typically an ingest, from the application,
+// turning it into a persistent dataset managed independently of the application. The copy is kept when the application
+// is deleted or copies the dataset again, whatever its copy cleanup policy.
+// Only copies registered in the data catalog can be promoted. Promotions are created by the owner of the application,
+// in the namespace of the application. Deleting the promotion does not delete the dataset.
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Application",type=string,JSONPath=`.spec.application`
+// +kubebuilder:printcolumn:name="Dataset",type=string,JSONPath=`.spec.dataSetID`
+// +kubebuilder:printcolumn:name="Promoted",type=boolean,JSONPath=`.status.promoted`
+// +kubebuilder:printcolumn:name="Asset",type=string,JSONPath=`.status.assetID`
+type M4DCopyPromotion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   M4DCopyPromotionSpec   `json:"spec,omitempty"`
+	Status M4DCopyPromotionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// M4DCopyPromotionList contains a list of M4DCopyPromotion
+type M4DCopyPromotionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []M4DCopyPromotion `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&M4DCopyPromotion{}, &M4DCopyPromotionList{})
+}
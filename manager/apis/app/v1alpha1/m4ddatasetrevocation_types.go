@@ -0,0 +1,49 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// M4DDatasetRevocationSpec defines the dataset whose access is revoked
+type M4DDatasetRevocationSpec struct {
+	// DataSetID is the identifier of the revoked dataset, as specified in M4DApplication resources
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	DataSetID string `json:"dataSetID"`
+
+	// Reason is reported to the owners of the applications referencing the dataset
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// M4DDatasetRevocation immediately revokes the access to a dataset across all applications.
+// The data plane of every application referencing the dataset is rebuilt without it,
+// without waiting for the governance policies to be re-evaluated.
+// Revocations are created by an administrator in the control plane namespace.
+// Deleting the revocation restores the access, subject to the governance policies.
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Dataset",type=string,JSONPath=`.spec.dataSetID`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type M4DDatasetRevocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec M4DDatasetRevocationSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// M4DDatasetRevocationList contains a list of M4DDatasetRevocation
+type M4DDatasetRevocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []M4DDatasetRevocation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&M4DDatasetRevocation{}, &M4DDatasetRevocationList{})
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// M4DGrantSpec defines the copy that is shared and the namespace it is shared with
+type M4DGrantSpec struct {
+	// Application is the name of the M4DApplication, in the namespace of the grant, that made the copy
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Application string `json:"application"`
+
+	// DataSetID is the identifier of the copied dataset, as specified in the M4DApplication
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	DataSetID string `json:"dataSetID"`
+
+	// Grantee is the namespace whose applications may read the copy
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Grantee string `json:"grantee"`
+}
+
+// M4DGrant grants the applications of another namespace read access to the copy of a dataset made by an application.
+// The applications of the grantee namespace that read the dataset are wired to the existing copy instead of making their own,
+// as long as their governance policies do not require transformations of the copy.
+// Grants are created by the owner of the application that made the copy, in the namespace of the application.
+// Deleting the grant makes the applications of the grantee namespace copy the dataset again.
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Application",type=string,JSONPath=`.spec.application`
+// +kubebuilder:printcolumn:name="Dataset",type=string,JSONPath=`.spec.dataSetID`
+// +kubebuilder:printcolumn:name="Grantee",type=string,JSONPath=`.spec.grantee`
+type M4DGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec M4DGrantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// M4DGrantList contains a list of M4DGrant
+type M4DGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []M4DGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&M4DGrant{}, &M4DGrantList{})
+}
@@ -5,6 +5,8 @@ package v1alpha1
 
 import (
 	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -52,6 +54,11 @@ type ModuleInOut struct {
 	// Sink specifies the output data protocol and format
 	// +optional
 	Sink *InterfaceDetails `json:"sink,omitempty"`
+
+	// Streaming indicates that the module reads the source as a stream, e.g. consumes a Kafka topic,
+	// and serves it to the workload without the data being copied first. Applies to the read flow only.
+	// +optional
+	Streaming bool `json:"streaming,omitempty"`
 }
 
 // Dependency details another component on which this module relies - i.e. a pre-requisit
@@ -72,6 +79,11 @@ type SupportedAction struct {
 	ID string `json:"id,omitempty"`
 	// +optional
 	Level connectors.EnforcementAction_EnforcementActionLevel `json:"level,omitempty"`
+	// ArgsSchema is a JSON schema of the action arguments that the module supports, e.g. the allowed hash algorithms.
+	// The module is selected for the action only if the arguments returned by the policy manager are valid against the schema.
+	// The values of the arguments are strings.
+	// +optional
+	ArgsSchema *serde.Arbitrary `json:"argsSchema,omitempty"`
 }
 
 // EndpointSpec is used both by the module creator and by the status of the m4dapplication
@@ -79,6 +91,10 @@ type EndpointSpec struct {
 	// Always equals the release name. Can be omitted.
 	// +optional
 	Hostname string `json:"hostname,omitempty"`
+	// Name distinguishes the endpoints of a module exposing several APIs.
+	// In the status of the m4dapplication it identifies the endpoint serving the asset.
+	// +optional
+	Name string `json:"name,omitempty"`
 	// +required
 	Port int32 `json:"port"`
 
@@ -108,11 +124,39 @@ type Capability struct {
 	// +optional
 	API *ModuleAPI `json:"api,omitempty"`
 
+	// APIs are additional APIs of the module, each exposed on a distinct named endpoint.
+	// The endpoint of the API matching the interface requested for a dataset serves the dataset, and API is used otherwise.
+	// +optional
+	APIs []ModuleAPI `json:"apis,omitempty"`
+
 	// Actions are the data transformations that the module supports
 	// +optional
 	Actions []SupportedAction `json:"actions,omitempty"`
+
+	// PerformanceClass is the latency and throughput class of the module, which is preferred
+	// when selecting a read module for data whose requirements specify the same class
+	// +optional
+	PerformanceClass PerformanceClass `json:"performanceClass,omitempty"`
+
+	// Sidecars are the names of the governance agents required by the module, e.g. an audit logger or a policy
+	// enforcement proxy. The agents are defined by the administrators, and injected as sidecars in the pods rendered
+	// by the chart of the module.
+	// +optional
+	Sidecars []string `json:"sidecars,omitempty"`
 }
 
+// PerformanceClass is a latency and throughput class of data access
+// +kubebuilder:validation:Enum=interactive;batch
+type PerformanceClass string
+
+// Performance classes of modules and data requirements
+const (
+	// InteractivePerformance favors low latency, e.g. for notebooks and dashboards
+	InteractivePerformance PerformanceClass = "interactive"
+	// BatchPerformance favors throughput, e.g. for training and ETL jobs
+	BatchPerformance PerformanceClass = "batch"
+)
+
 // ResourceStatusIndicator is used to determine the status of an orchestrated resource
 type ResourceStatusIndicator struct {
 	// Kind provides information about the resource kind
@@ -157,6 +201,74 @@ type M4DModuleSpec struct {
 	// StatusIndicators allow to check status of a non-standard resource that can not be computed by helm/kstatus
 	// +optional
 	StatusIndicators []ResourceStatusIndicator `json:"statusIndicators,omitempty"`
+
+	// Scheduling maps the capabilities of the module (copy, read, write) to the scheduling of the pods deployed for them,
+	// e.g. a low priority for copy jobs so that they do not evict read modules serving interactive workloads.
+	// The scheduling set by the administrators takes precedence.
+	// +optional
+	Scheduling map[ModuleFlow]SchedulingPolicy `json:"scheduling,omitempty"`
+
+	// Resources are the default compute resources (CPU, memory) requested by the pods of the module.
+	// They can be overridden per dataset in the requirements of the application.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// UpgradePolicy defines how the applications running a previous version of the module are migrated when its chart changes.
+	// Immediate re-orchestrates them at once, NextReconcile (the default) migrates each of them the next time it is reconciled,
+	// and Manual keeps the previous chart of an application until the upgrade is approved by its upgradeModules annotation.
+	// +optional
+	UpgradePolicy UpgradePolicy `json:"upgradePolicy,omitempty"`
+}
+
+// UpgradePolicy defines how the applications running a previous version of a module are migrated
+// +kubebuilder:validation:Enum=Immediate;NextReconcile;Manual
+type UpgradePolicy string
+
+const (
+	// ImmediateUpgrade re-orchestrates the applications running a previous version as soon as the module changes
+	ImmediateUpgrade UpgradePolicy = "Immediate"
+	// NextReconcileUpgrade migrates an application the next time it is reconciled, e.g. when its spec changes
+	NextReconcileUpgrade UpgradePolicy = "NextReconcile"
+	// ManualUpgrade keeps the previous version of the module for an application until its upgrade is approved
+	ManualUpgrade UpgradePolicy = "Manual"
+)
+
+// SchedulingPolicy defines how the pods of a module are scheduled
+type SchedulingPolicy struct {
+	// PriorityClassName is the priority class of the pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// SchedulerName is the scheduler dispatching the pods, the default scheduler if not specified
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
+	// TopologySpreadConstraints describe how the pods are spread across the topology domains of the cluster
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// Sidecar is a governance agent injected as a sidecar container in the pods of the modules requiring it
+type Sidecar struct {
+	// Name of the sidecar container
+	// +required
+	Name string `json:"name"`
+
+	// Image of the sidecar container
+	// +required
+	Image string `json:"image"`
+
+	// Args are the arguments of the entrypoint of the image
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env maps the environment variables of the sidecar container to their values
+	// +optional
+	Env map[string]string `json:"env,omitempty"`
+
+	// Resources are the compute resources requested by the sidecar container
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // ChartSpec specifies chart name and values
@@ -168,8 +280,44 @@ type ChartSpec struct {
 	// Values to pass to helm chart installation
 	// +optional
 	Values map[string]string `json:"values,omitempty"`
+
+	// Overrides are structured values merged with the arguments passed to the chart, e.g. to tune the image pull policy,
+	// node selectors or tolerations of the pods of the module without forking its chart.
+	// The arguments set by the manager take precedence over the overrides.
+	// +optional
+	Overrides *serde.Arbitrary `json:"overrides,omitempty"`
+
+	// ClusterOverrides are overlaid on the overrides for the instances of the module deployed in a cluster,
+	// mapped by the names of the clusters
+	// +optional
+	ClusterOverrides map[string]serde.Arbitrary `json:"clusterOverrides,omitempty"`
+
+	// ValuesContract is the version of the contract of the values passed by the manager to the chart, e.g. v2.
+	// The manager refuses to deploy charts declaring a contract it does not support, and omits the values
+	// that are not part of the declared contract. Charts that do not declare a contract are assumed to support v1.
+	// +optional
+	ValuesContract string `json:"valuesContract,omitempty"`
 }
 
+// Versions of the contract of the values passed by the manager to module charts
+const (
+	// ValuesContractV1 includes the copy, read and write arguments and the labels identifying the application
+	ValuesContractV1 string = "v1"
+	// ValuesContractV2 adds the labels propagated from the application, the annotations of the module resources
+	// and the hostname under which read modules are registered in an external DNS
+	ValuesContractV2 string = "v2"
+	// ValuesContractV3 adds the scheduling of the pods of the module
+	ValuesContractV3 string = "v3"
+	// ValuesContractV4 adds the compute resources requested by the pods of the module
+	ValuesContractV4 string = "v4"
+	// ValuesContractV5 does not add any value to v4. The governance sidecars required by the module are injected
+	// in the rendered manifests of the charts following any version of the contract.
+	ValuesContractV5 string = "v5"
+	// ValuesContractV6 adds the reference to the ConfigMap holding the governance decisions of the blueprint
+	ValuesContractV6 string = "v6"
+)
+
+// +genclient
 // +kubebuilder:object:root=true
 
 // M4DModule is a description of an injectable component.
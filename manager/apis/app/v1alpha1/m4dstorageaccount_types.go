@@ -4,6 +4,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -20,17 +21,52 @@ type M4DStorageAccountSpec struct {
 	// +kubebuilder:validation:MinItems=1
 	// Regions
 	Regions []string `json:"regions"`
+	// +optional
+	// Type of the storage, which selects the provisioner of the storage, e.g. s3 (default), azure-blob or gcs.
+	// Provisioners of types other than s3 have to be registered in the manager.
+	Type string `json:"type,omitempty"`
+	// +optional
+	// Details are provider-specific settings passed to the provisioner, e.g. a container or a project
+	Details map[string]string `json:"details,omitempty"`
+	// +optional
+	// Tags describe the storage account, e.g. its cost tier or compliance certifications, and are validated against the storage taxonomy.
+	// Governance policies may require copies to be stored in accounts with specific tags.
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // M4DStorageAccountStatus defines the observed state of M4DStorageAccount
 type M4DStorageAccountStatus struct {
+	// Conditions report whether the secret of the storage account contains the expected credentials,
+	// and whether its regions are valid geography names. Storage is not allocated in accounts whose credentials are invalid.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// CredentialsValidCondition indicates whether the secret referenced by a storage account exists and contains the expected keys
+const CredentialsValidCondition ConditionType = "CredentialsValid"
+
+// RegionsValidCondition indicates whether the regions of a storage account are valid geography names.
+// Storage accounts created before the regions were validated upon admission may have invalid regions.
+const RegionsValidCondition ConditionType = "RegionsValid"
+
+// InvalidCredentials returns the reason for which the credentials of the storage account have been found invalid,
+// or an empty string if they are valid or have not been checked yet
+func (r *M4DStorageAccount) InvalidCredentials() string {
+	for _, condition := range r.Status.Conditions {
+		if condition.Type == CredentialsValidCondition && condition.Status == corev1.ConditionFalse {
+			return condition.Message
+		}
+	}
+	return ""
 }
 
 // M4DStorageAccount defines a storage account used for copying data.
-// Only S3 based storage is supported.
+// S3 based storage is supported by default, other types of storage are supported by registered provisioners.
 // It contains endpoint, region and a reference to the credentials a
 // Owner of the asset is responsible to store the credentials
+// +genclient
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 type M4DStorageAccount struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
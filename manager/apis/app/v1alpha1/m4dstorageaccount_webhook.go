@@ -0,0 +1,110 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	log "log"
+	"strings"
+
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// knownGeographies lists the geography names defined by the governance taxonomy.
+// Regions are only checked for well-formedness if the list is empty.
+var knownGeographies []string
+
+// SetGeographies sets the geography names that storage account regions, and the geographies required for the datasets
+// of applications, are validated against
+func SetGeographies(geographies []string) {
+	knownGeographies = geographies
+}
+
+// tagsValidator validates the tags of storage accounts against the storage taxonomy, if set
+var tagsValidator *taxonomy.Validator
+
+// EnableTagsValidation enables the admission check of the tags of storage accounts against the storage_tags definition
+// of the storage taxonomy. Passing nil disables the check.
+func EnableTagsValidation(validator *taxonomy.Validator) {
+	tagsValidator = validator
+}
+
+func (r *M4DStorageAccount) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,admissionReviewVersions=v1;v1beta1,sideEffects=None,path=/validate-app-m4d-ibm-com-v1alpha1-m4dstorageaccount,mutating=false,failurePolicy=fail,groups=app.m4d.ibm.com,resources=m4dstorageaccounts,versions=v1alpha1,name=vm4dstorageaccount.kb.io
+
+var _ webhook.Validator = &M4DStorageAccount{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *M4DStorageAccount) ValidateCreate() error {
+	log.Printf("Validating m4dstorageaccount %s for creation", r.Name)
+	return r.validateM4DStorageAccount()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *M4DStorageAccount) ValidateUpdate(old runtime.Object) error {
+	log.Printf("Validating m4dstorageaccount %s for update", r.Name)
+	return r.validateM4DStorageAccount()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *M4DStorageAccount) ValidateDelete() error {
+	return nil
+}
+
+func (r *M4DStorageAccount) validateM4DStorageAccount() error {
+	if err := r.ValidateRegions(); err != nil {
+		return err
+	}
+	if tagsValidator == nil || len(r.Spec.Tags) == 0 {
+		return nil
+	}
+	if err := tagsValidator.Validate(r.Spec.Tags); err != nil {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: "app.m4d.ibm.com", Kind: "M4DStorageAccount"},
+			r.Name, field.ErrorList{field.Invalid(field.NewPath("spec").Child("tags"), r.Spec.Tags, err.Error())})
+	}
+	return nil
+}
+
+// ValidateRegions checks that the regions of the storage account are valid geography names.
+// A region with surrounding whitespace would never match a geography and silently disable the account.
+func (r *M4DStorageAccount) ValidateRegions() error {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec").Child("regions")
+	for i, region := range r.Spec.Regions {
+		switch {
+		case region == "":
+			allErrs = append(allErrs, field.Required(path.Index(i), "region must not be empty"))
+		case strings.TrimSpace(region) != region:
+			allErrs = append(allErrs, field.Invalid(path.Index(i), region, "region must not contain leading or trailing whitespace"))
+		case len(knownGeographies) > 0 && !containsGeography(knownGeographies, region):
+			allErrs = append(allErrs, field.NotSupported(path.Index(i), region, knownGeographies))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "app.m4d.ibm.com", Kind: "M4DStorageAccount"},
+		r.Name, allErrs)
+}
+
+func containsGeography(geographies []string, region string) bool {
+	for _, geo := range geographies {
+		if geo == region {
+			return true
+		}
+	}
+	return false
+}
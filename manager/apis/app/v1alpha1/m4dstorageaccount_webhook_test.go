@@ -0,0 +1,61 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateRegions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	account := &M4DStorageAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "account", Namespace: "m4d-system"},
+		Spec: M4DStorageAccountSpec{
+			SecretRef: "credentials",
+			Endpoint:  "http://s3.eu.cloud-object-storage.appdomain.cloud",
+			Regions:   []string{"theshire"},
+		},
+	}
+	g.Expect(account.ValidateRegions()).To(gomega.Succeed())
+
+	account.Spec.Regions = []string{"theshire "}
+	g.Expect(account.ValidateRegions()).NotTo(gomega.Succeed())
+
+	account.Spec.Regions = []string{""}
+	g.Expect(account.ValidateRegions()).NotTo(gomega.Succeed())
+
+	SetGeographies([]string{"Netherlands", "Turkey"})
+	defer SetGeographies(nil)
+	account.Spec.Regions = []string{"Netherlands"}
+	g.Expect(account.ValidateRegions()).To(gomega.Succeed())
+	account.Spec.Regions = []string{"theshire"}
+	g.Expect(account.ValidateRegions()).NotTo(gomega.Succeed())
+}
+
+func TestValidateTags(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	account := &M4DStorageAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "account", Namespace: "m4d-system"},
+		Spec: M4DStorageAccountSpec{
+			SecretRef: "credentials",
+			Endpoint:  "http://s3.eu.cloud-object-storage.appdomain.cloud",
+			Regions:   []string{"theshire"},
+			Tags:      map[string]string{"cost_tier": "premium"},
+		},
+	}
+	// tags are not validated without a taxonomy
+	g.Expect(account.ValidateCreate()).To(gomega.Succeed())
+
+	validator, err := taxonomy.CachedValidator("../../../../charts/m4d/files/taxonomy/storage.values.schema.json", "storage_tags")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	EnableTagsValidation(validator)
+	defer EnableTagsValidation(nil)
+	g.Expect(account.ValidateCreate()).NotTo(gomega.Succeed())
+	account.Spec.Tags = map[string]string{"cost_tier": "archive", "compliance_certification": "iso-27001", "team": "analytics"}
+	g.Expect(account.ValidateCreate()).To(gomega.Succeed())
+}
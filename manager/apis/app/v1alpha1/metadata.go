@@ -9,7 +9,37 @@ type ObservedState struct {
 	Ready bool `json:"ready,omitempty"`
 	// Error indicates that there has been an error to orchestrate the modules and provides the error message
 	Error string `json:"error,omitempty"`
+	// Failed indicates that the error is terminal: the retries allowed by the retry policy of the application
+	// have been exhausted and the modules are not orchestrated again until the spec changes
+	Failed bool `json:"failed,omitempty"`
 	// DataAccessInstructions indicate how the data user or his application may access the data.
 	// Instructions are available upon successful orchestration.
 	DataAccessInstructions string `json:"dataAccessInstructions,omitempty"`
+	// Health maps the assets to the health of the modules serving them, aggregated from the pods of the modules
+	Health map[string]ModuleHealth `json:"health,omitempty"`
+	// ExternalHostnames maps the releases of the modules to the hostnames under which their services are registered
+	// in the external DNS. Modules whose charts do not create a service named after the release are not registered.
+	ExternalHostnames map[string]string `json:"externalHostnames,omitempty"`
+}
+
+// ModuleHealth aggregates the readiness and liveness of the pods running modules, as reported by their probes
+type ModuleHealth struct {
+	// Pods is the number of pods running the modules
+	Pods int32 `json:"pods"`
+	// ReadyPods is the number of pods whose containers pass their readiness probes
+	ReadyPods int32 `json:"readyPods"`
+	// Restarts is the number of restarts of the containers of the pods, e.g. after failing their liveness probes
+	Restarts int32 `json:"restarts,omitempty"`
+	// Message describes why a pod is not ready, e.g. a container waiting in CrashLoopBackOff
+	Message string `json:"message,omitempty"`
+}
+
+// Add aggregates the health of other pods
+func (h *ModuleHealth) Add(other *ModuleHealth) {
+	h.Pods += other.Pods
+	h.ReadyPods += other.ReadyPods
+	h.Restarts += other.Restarts
+	if h.Message == "" {
+		h.Message = other.Message
+	}
 }
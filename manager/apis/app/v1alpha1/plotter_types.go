@@ -19,6 +19,10 @@ type PlotterSpec struct {
 	// +required
 	// Blueprints structure represents remote blueprints mapped by the identifier of a cluster in which they will be running
 	Blueprints map[string]BlueprintSpec `json:"blueprints"`
+
+	// RetryPolicy is taken from the owner M4DApplication. It limits the attempts to orchestrate failing blueprints.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
 }
 
 // PlotterStatus defines the observed state of Plotter
@@ -38,10 +42,26 @@ type PlotterStatus struct {
 	// + optional
 	Blueprints map[string]MetaBlueprint `json:"blueprints,omitempty"`
 
+	// BlueprintErrors maps a cluster name to the error received while creating, updating or fetching its blueprint.
+	// Clusters are handled independently, so a failure in one cluster does not block the others.
+	// +optional
+	BlueprintErrors map[string]string `json:"blueprintErrors,omitempty"`
+
+	// BlueprintFailures maps a cluster name to the number of consecutive reconciles in which its blueprint has failed.
+	// The counters are reset when the blueprint recovers or the spec of the plotter changes.
+	// +optional
+	BlueprintFailures map[string]int32 `json:"blueprintFailures,omitempty"`
+
+	// ChangedBlueprints lists the clusters whose blueprints have been created, updated or removed in the last observed
+	// generation of the spec. The blueprints of the other clusters have been left untouched.
+	// +optional
+	ChangedBlueprints []string `json:"changedBlueprints,omitempty"`
+
 	// + optional
 	ReadyTimestamp *metav1.Time `json:"readyTimestamp,omitempty"`
 }
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced
@@ -2,6 +2,27 @@
 
 package v1alpha1
 
+// CredentialsType is the backend from which the modules retrieve the credentials of a data store
+// +kubebuilder:validation:Enum=vault;kubernetes
+type CredentialsType string
+
+const (
+	// VaultCredentials are retrieved from Vault, as described by the Vault property of the data store
+	VaultCredentials CredentialsType = "vault"
+	// KubernetesCredentials are read by the modules from the Kubernetes secret referenced by the data store
+	KubernetesCredentials CredentialsType = "kubernetes"
+)
+
+// KubernetesSecretRef references a Kubernetes secret holding credentials
+type KubernetesSecretRef struct {
+	// Name of the secret
+	// +required
+	Name string `json:"name"`
+	// Namespace of the secret
+	// +required
+	Namespace string `json:"namespace"`
+}
+
 // Holds details for retrieving credentials from Vault store.
 type Vault struct {
 	// Role is the Vault role used for retrieving the credentials
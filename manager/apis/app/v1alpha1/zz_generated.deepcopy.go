@@ -9,6 +9,8 @@ package v1alpha1
 
 import (
 	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -33,6 +35,55 @@ func (in ApplicationDetails) DeepCopy() ApplicationDetails {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedAction) DeepCopyInto(out *AppliedAction) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedAction.
+func (in *AppliedAction) DeepCopy() *AppliedAction {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssetState) DeepCopyInto(out *AssetState) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]AppliedAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(ModuleHealth)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssetState.
+func (in *AssetState) DeepCopy() *AssetState {
+	if in == nil {
+		return nil
+	}
+	out := new(AssetState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Blueprint) DeepCopyInto(out *Blueprint) {
 	*out = *in
@@ -118,7 +169,7 @@ func (in *BlueprintSpec) DeepCopy() *BlueprintSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlueprintStatus) DeepCopyInto(out *BlueprintStatus) {
 	*out = *in
-	out.ObservedState = in.ObservedState
+	in.ObservedState.DeepCopyInto(&out.ObservedState)
 	if in.Releases != nil {
 		in, out := &in.Releases, &out.Releases
 		*out = make(map[string]int64, len(*in))
@@ -126,6 +177,13 @@ func (in *BlueprintStatus) DeepCopyInto(out *BlueprintStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make(map[string]StepResources, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintStatus.
@@ -153,9 +211,21 @@ func (in *Capability) DeepCopyInto(out *Capability) {
 		*out = new(ModuleAPI)
 		**out = **in
 	}
+	if in.APIs != nil {
+		in, out := &in.APIs, &out.APIs
+		*out = make([]ModuleAPI, len(*in))
+		copy(*out, *in)
+	}
 	if in.Actions != nil {
 		in, out := &in.Actions, &out.Actions
 		*out = make([]SupportedAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
@@ -195,6 +265,20 @@ func (in *ChartSpec) DeepCopyInto(out *ChartSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(serde.Arbitrary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterOverrides != nil {
+		in, out := &in.ClusterOverrides, &out.ClusterOverrides
+		*out = make(map[string]serde.Arbitrary, len(*in))
+		for key, val := range *in {
+			var outVal serde.Arbitrary
+			val.DeepCopyInto(&outVal)
+			(*out)[key] = outVal
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartSpec.
@@ -266,6 +350,16 @@ func (in *CopyModuleArgs) DeepCopy() *CopyModuleArgs {
 func (in *CopyRequirements) DeepCopyInto(out *CopyRequirements) {
 	*out = *in
 	out.Catalog = in.Catalog
+	if in.MaxStaleness != nil {
+		in, out := &in.MaxStaleness, &out.MaxStaleness
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyRequirements.
@@ -281,7 +375,12 @@ func (in *CopyRequirements) DeepCopy() *CopyRequirements {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataContext) DeepCopyInto(out *DataContext) {
 	*out = *in
-	out.Requirements = in.Requirements
+	in.Requirements.DeepCopyInto(&out.Requirements)
+	if in.Flows != nil {
+		in, out := &in.Flows, &out.Flows
+		*out = make([]ModuleFlow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataContext.
@@ -320,7 +419,22 @@ func (in *DataFlow) DeepCopy() *DataFlow {
 func (in *DataRequirements) DeepCopyInto(out *DataRequirements) {
 	*out = *in
 	out.Interface = in.Interface
-	out.Copy = in.Copy
+	if in.FallbackInterfaces != nil {
+		in, out := &in.FallbackInterfaces, &out.FallbackInterfaces
+		*out = make([]InterfaceDetails, len(*in))
+		copy(*out, *in)
+	}
+	if in.WriteInterface != nil {
+		in, out := &in.WriteInterface, &out.WriteInterface
+		*out = new(InterfaceDetails)
+		**out = **in
+	}
+	in.Copy.DeepCopyInto(&out.Copy)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataRequirements.
@@ -337,6 +451,11 @@ func (in *DataRequirements) DeepCopy() *DataRequirements {
 func (in *DataStore) DeepCopyInto(out *DataStore) {
 	*out = *in
 	out.Vault = in.Vault
+	if in.KubernetesSecret != nil {
+		in, out := &in.KubernetesSecret, &out.KubernetesSecret
+		*out = new(KubernetesSecretRef)
+		**out = **in
+	}
 	in.Connection.DeepCopyInto(&out.Connection)
 }
 
@@ -354,6 +473,10 @@ func (in *DataStore) DeepCopy() *DataStore {
 func (in *DatasetDetails) DeepCopyInto(out *DatasetDetails) {
 	*out = *in
 	in.Details.DeepCopyInto(&out.Details)
+	if in.CopiedAt != nil {
+		in, out := &in.CopiedAt, &out.CopiedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasetDetails.
@@ -381,6 +504,23 @@ func (in *Dependency) DeepCopy() *Dependency {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DirectAccessDetails) DeepCopyInto(out *DirectAccessDetails) {
+	*out = *in
+	out.Interface = in.Interface
+	in.Connection.DeepCopyInto(&out.Connection)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectAccessDetails.
+func (in *DirectAccessDetails) DeepCopy() *DirectAccessDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(DirectAccessDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EndpointSpec) DeepCopyInto(out *EndpointSpec) {
 	*out = *in
@@ -412,6 +552,21 @@ func (in *FlowStep) DeepCopy() *FlowStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrantedCopy) DeepCopyInto(out *GrantedCopy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrantedCopy.
+func (in *GrantedCopy) DeepCopy() *GrantedCopy {
+	if in == nil {
+		return nil
+	}
+	out := new(GrantedCopy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InterfaceDetails) DeepCopyInto(out *InterfaceDetails) {
 	*out = *in
@@ -427,6 +582,21 @@ func (in *InterfaceDetails) DeepCopy() *InterfaceDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesSecretRef) DeepCopyInto(out *KubernetesSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesSecretRef.
+func (in *KubernetesSecretRef) DeepCopy() *KubernetesSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *M4DApplication) DeepCopyInto(out *M4DApplication) {
 	*out = *in
@@ -486,6 +656,81 @@ func (in *M4DApplicationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DApplicationProfile) DeepCopyInto(out *M4DApplicationProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DApplicationProfile.
+func (in *M4DApplicationProfile) DeepCopy() *M4DApplicationProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DApplicationProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *M4DApplicationProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DApplicationProfileList) DeepCopyInto(out *M4DApplicationProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]M4DApplicationProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DApplicationProfileList.
+func (in *M4DApplicationProfileList) DeepCopy() *M4DApplicationProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DApplicationProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *M4DApplicationProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DApplicationProfileSpec) DeepCopyInto(out *M4DApplicationProfileSpec) {
+	*out = *in
+	in.Requirements.DeepCopyInto(&out.Requirements)
+	out.Placement = in.Placement
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DApplicationProfileSpec.
+func (in *M4DApplicationProfileSpec) DeepCopy() *M4DApplicationProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DApplicationProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *M4DApplicationSpec) DeepCopyInto(out *M4DApplicationSpec) {
 	*out = *in
@@ -500,8 +745,27 @@ func (in *M4DApplicationSpec) DeepCopyInto(out *M4DApplicationSpec) {
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
 		*out = make([]DataContext, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PinnedModules != nil {
+		in, out := &in.PinnedModules, &out.PinnedModules
+		*out = make([]PinnedModule, len(*in))
 		copy(*out, *in)
 	}
+	if in.ModuleVersions != nil {
+		in, out := &in.ModuleVersions, &out.ModuleVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DApplicationSpec.
@@ -519,8 +783,10 @@ func (in *M4DApplicationStatus) DeepCopyInto(out *M4DApplicationStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
-		copy(*out, *in)
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.CatalogedAssets != nil {
 		in, out := &in.CatalogedAssets, &out.CatalogedAssets
@@ -534,6 +800,11 @@ func (in *M4DApplicationStatus) DeepCopyInto(out *M4DApplicationStatus) {
 		*out = new(ResourceReference)
 		**out = **in
 	}
+	if in.OwnedResources != nil {
+		in, out := &in.OwnedResources, &out.OwnedResources
+		*out = make([]OwnedResource, len(*in))
+		copy(*out, *in)
+	}
 	if in.ProvisionedStorage != nil {
 		in, out := &in.ProvisionedStorage, &out.ProvisionedStorage
 		*out = make(map[string]DatasetDetails, len(*in))
@@ -548,6 +819,127 @@ func (in *M4DApplicationStatus) DeepCopyInto(out *M4DApplicationStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.WriteEndpointsMap != nil {
+		in, out := &in.WriteEndpointsMap, &out.WriteEndpointsMap
+		*out = make(map[string]EndpointSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ObservedData != nil {
+		in, out := &in.ObservedData, &out.ObservedData
+		*out = make(map[string]DataRequirements, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.SpecChanges != nil {
+		in, out := &in.SpecChanges, &out.SpecChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevokedDatasets != nil {
+		in, out := &in.RevokedDatasets, &out.RevokedDatasets
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NegotiatedInterfaces != nil {
+		in, out := &in.NegotiatedInterfaces, &out.NegotiatedInterfaces
+		*out = make(map[string]InterfaceDetails, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StaleDatasets != nil {
+		in, out := &in.StaleDatasets, &out.StaleDatasets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiredCopies != nil {
+		in, out := &in.ExpiredCopies, &out.ExpiredCopies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CatalogHashes != nil {
+		in, out := &in.CatalogHashes, &out.CatalogHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DirectAccess != nil {
+		in, out := &in.DirectAccess, &out.DirectAccess
+		*out = make(map[string]DirectAccessDetails, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.MismatchedPerformanceClasses != nil {
+		in, out := &in.MismatchedPerformanceClasses, &out.MismatchedPerformanceClasses
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RetainedCopies != nil {
+		in, out := &in.RetainedCopies, &out.RetainedCopies
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PromotedCopies != nil {
+		in, out := &in.PromotedCopies, &out.PromotedCopies
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GrantedCopies != nil {
+		in, out := &in.GrantedCopies, &out.GrantedCopies
+		*out = make(map[string]GrantedCopy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DebugInfo != nil {
+		in, out := &in.DebugInfo, &out.DebugInfo
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.DeployedModules != nil {
+		in, out := &in.DeployedModules, &out.DeployedModules
+		*out = make(map[string]M4DModuleSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.OutdatedModules != nil {
+		in, out := &in.OutdatedModules, &out.OutdatedModules
+		*out = make(map[string]OutdatedModule, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AssetStates != nil {
+		in, out := &in.AssetStates, &out.AssetStates
+		*out = make(map[string]AssetState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DApplicationStatus.
@@ -561,11 +953,246 @@ func (in *M4DApplicationStatus) DeepCopy() *M4DApplicationStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *M4DModule) DeepCopyInto(out *M4DModule) {
+func (in *M4DCopyPromotion) DeepCopyInto(out *M4DCopyPromotion) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DCopyPromotion.
+func (in *M4DCopyPromotion) DeepCopy() *M4DCopyPromotion {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DCopyPromotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *M4DCopyPromotion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DCopyPromotionList) DeepCopyInto(out *M4DCopyPromotionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]M4DCopyPromotion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DCopyPromotionList.
+func (in *M4DCopyPromotionList) DeepCopy() *M4DCopyPromotionList {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DCopyPromotionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *M4DCopyPromotionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DCopyPromotionSpec) DeepCopyInto(out *M4DCopyPromotionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DCopyPromotionSpec.
+func (in *M4DCopyPromotionSpec) DeepCopy() *M4DCopyPromotionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DCopyPromotionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DCopyPromotionStatus) DeepCopyInto(out *M4DCopyPromotionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DCopyPromotionStatus.
+func (in *M4DCopyPromotionStatus) DeepCopy() *M4DCopyPromotionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DCopyPromotionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DDatasetRevocation) DeepCopyInto(out *M4DDatasetRevocation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DDatasetRevocation.
+func (in *M4DDatasetRevocation) DeepCopy() *M4DDatasetRevocation {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DDatasetRevocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *M4DDatasetRevocation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DDatasetRevocationList) DeepCopyInto(out *M4DDatasetRevocationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]M4DDatasetRevocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DDatasetRevocationList.
+func (in *M4DDatasetRevocationList) DeepCopy() *M4DDatasetRevocationList {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DDatasetRevocationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *M4DDatasetRevocationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DDatasetRevocationSpec) DeepCopyInto(out *M4DDatasetRevocationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DDatasetRevocationSpec.
+func (in *M4DDatasetRevocationSpec) DeepCopy() *M4DDatasetRevocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DDatasetRevocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DGrant) DeepCopyInto(out *M4DGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DGrant.
+func (in *M4DGrant) DeepCopy() *M4DGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *M4DGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DGrantList) DeepCopyInto(out *M4DGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]M4DGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DGrantList.
+func (in *M4DGrantList) DeepCopy() *M4DGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *M4DGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DGrantSpec) DeepCopyInto(out *M4DGrantSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DGrantSpec.
+func (in *M4DGrantSpec) DeepCopy() *M4DGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(M4DGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *M4DModule) DeepCopyInto(out *M4DModule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DModule.
@@ -638,6 +1265,18 @@ func (in *M4DModuleSpec) DeepCopyInto(out *M4DModuleSpec) {
 		*out = make([]ResourceStatusIndicator, len(*in))
 		copy(*out, *in)
 	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = make(map[ModuleFlow]SchedulingPolicy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DModuleSpec.
@@ -656,7 +1295,7 @@ func (in *M4DStorageAccount) DeepCopyInto(out *M4DStorageAccount) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DStorageAccount.
@@ -717,6 +1356,20 @@ func (in *M4DStorageAccountSpec) DeepCopyInto(out *M4DStorageAccountSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DStorageAccountSpec.
@@ -732,6 +1385,11 @@ func (in *M4DStorageAccountSpec) DeepCopy() *M4DStorageAccountSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *M4DStorageAccountStatus) DeepCopyInto(out *M4DStorageAccountStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new M4DStorageAccountStatus.
@@ -747,6 +1405,13 @@ func (in *M4DStorageAccountStatus) DeepCopy() *M4DStorageAccountStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetaBlueprint) DeepCopyInto(out *MetaBlueprint) {
 	*out = *in
+	if in.ReleaseNames != nil {
+		in, out := &in.ReleaseNames, &out.ReleaseNames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -799,6 +1464,37 @@ func (in *ModuleArguments) DeepCopyInto(out *ModuleArguments) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(SchedulingPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]Sidecar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleArguments.
@@ -811,6 +1507,21 @@ func (in *ModuleArguments) DeepCopy() *ModuleArguments {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleHealth) DeepCopyInto(out *ModuleHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleHealth.
+func (in *ModuleHealth) DeepCopy() *ModuleHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleInOut) DeepCopyInto(out *ModuleInOut) {
 	*out = *in
@@ -839,6 +1550,20 @@ func (in *ModuleInOut) DeepCopy() *ModuleInOut {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObservedState) DeepCopyInto(out *ObservedState) {
 	*out = *in
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = make(map[string]ModuleHealth, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExternalHostnames != nil {
+		in, out := &in.ExternalHostnames, &out.ExternalHostnames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedState.
@@ -851,6 +1576,51 @@ func (in *ObservedState) DeepCopy() *ObservedState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutdatedModule) DeepCopyInto(out *OutdatedModule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutdatedModule.
+func (in *OutdatedModule) DeepCopy() *OutdatedModule {
+	if in == nil {
+		return nil
+	}
+	out := new(OutdatedModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OwnedResource) DeepCopyInto(out *OwnedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OwnedResource.
+func (in *OwnedResource) DeepCopy() *OwnedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OwnedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PinnedModule) DeepCopyInto(out *PinnedModule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PinnedModule.
+func (in *PinnedModule) DeepCopy() *PinnedModule {
+	if in == nil {
+		return nil
+	}
+	out := new(PinnedModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Plotter) DeepCopyInto(out *Plotter) {
 	*out = *in
@@ -921,6 +1691,11 @@ func (in *PlotterSpec) DeepCopyInto(out *PlotterSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlotterSpec.
@@ -936,7 +1711,7 @@ func (in *PlotterSpec) DeepCopy() *PlotterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlotterStatus) DeepCopyInto(out *PlotterStatus) {
 	*out = *in
-	out.ObservedState = in.ObservedState
+	in.ObservedState.DeepCopyInto(&out.ObservedState)
 	if in.Blueprints != nil {
 		in, out := &in.Blueprints, &out.Blueprints
 		*out = make(map[string]MetaBlueprint, len(*in))
@@ -944,6 +1719,25 @@ func (in *PlotterStatus) DeepCopyInto(out *PlotterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.BlueprintErrors != nil {
+		in, out := &in.BlueprintErrors, &out.BlueprintErrors
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BlueprintFailures != nil {
+		in, out := &in.BlueprintFailures, &out.BlueprintFailures
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ChangedBlueprints != nil {
+		in, out := &in.ChangedBlueprints, &out.ChangedBlueprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ReadyTimestamp != nil {
 		in, out := &in.ReadyTimestamp, &out.ReadyTimestamp
 		*out = (*in).DeepCopy()
@@ -960,6 +1754,21 @@ func (in *PlotterStatus) DeepCopy() *PlotterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfilePlacement) DeepCopyInto(out *ProfilePlacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfilePlacement.
+func (in *ProfilePlacement) DeepCopy() *ProfilePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfilePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadModuleArgs) DeepCopyInto(out *ReadModuleArgs) {
 	*out = *in
@@ -1013,6 +1822,48 @@ func (in *ResourceStatusIndicator) DeepCopy() *ResourceStatusIndicator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingPolicy) DeepCopyInto(out *SchedulingPolicy) {
+	*out = *in
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingPolicy.
+func (in *SchedulingPolicy) DeepCopy() *SchedulingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Selector) DeepCopyInto(out *Selector) {
 	*out = *in
@@ -1029,9 +1880,71 @@ func (in *Selector) DeepCopy() *Selector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sidecar) DeepCopyInto(out *Sidecar) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sidecar.
+func (in *Sidecar) DeepCopy() *Sidecar {
+	if in == nil {
+		return nil
+	}
+	out := new(Sidecar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepResources) DeepCopyInto(out *StepResources) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(ModuleHealth)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepResources.
+func (in *StepResources) DeepCopy() *StepResources {
+	if in == nil {
+		return nil
+	}
+	out := new(StepResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SupportedAction) DeepCopyInto(out *SupportedAction) {
 	*out = *in
+	if in.ArgsSchema != nil {
+		in, out := &in.ArgsSchema, &out.ArgsSchema
+		*out = new(serde.Arbitrary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportedAction.
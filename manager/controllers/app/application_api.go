@@ -0,0 +1,435 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ApplicationAPIPrefix is the prefix of the paths of the application API:
+// POST /v1/namespaces/<namespace>/applications applies an application,
+// GET /v1/namespaces/<namespace>/applications/<name> returns its status, and streams its changes with ?watch=true.
+const ApplicationAPIPrefix = "/v1/namespaces/"
+
+// maxApplicationBytes limits the size of the applications applied through the application API,
+// as the request bodies of the Kubernetes API server are limited
+const maxApplicationBytes = 3 * 1024 * 1024
+
+// applyVerbs are the verbs that the callers applying an application must be allowed, whether the application exists or not,
+// such that the response does not disclose the existence of applications that the callers may not get
+var applyVerbs = []string{"create", "update"}
+
+// AppliedApplication identifies an application applied through the application API
+type AppliedApplication struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	UID        string `json:"uid"`
+	Generation int64  `json:"generation"`
+	// Created is set if the application did not exist, otherwise its spec has been updated
+	Created bool `json:"created"`
+}
+
+// ApplicationStatusEvent is an observed status of an application, as returned and streamed by the application API
+type ApplicationStatusEvent struct {
+	Generation int64                    `json:"generation"`
+	Status     app.M4DApplicationStatus `json:"status"`
+	// Deleted is set in the last event of a stream if the application has been deleted
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// AccessReviewer authenticates the callers of the application API and authorizes their requests
+type AccessReviewer interface {
+	// Authenticate returns the user presenting the bearer token, or nil if the token is not valid
+	Authenticate(ctx context.Context, token string) (*authenticationv1.UserInfo, error)
+	// Authorize returns true if the user may perform the verb on the applications of the namespace
+	Authorize(ctx context.Context, user *authenticationv1.UserInfo, namespace string, verb string) (bool, error)
+}
+
+// KubeAccessReviewer reviews the access to the application API with TokenReview and SubjectAccessReview resources,
+// such that callers are authenticated by Kubernetes tokens, e.g. of service accounts, and authorized by the RBAC rules
+// of the M4DApplication resources.
+type KubeAccessReviewer struct {
+	Client client.Client
+}
+
+// Authenticate reviews the token with a TokenReview
+func (r *KubeAccessReviewer) Authenticate(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := r.Client.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	if !review.Status.Authenticated {
+		return nil, nil
+	}
+	return &review.Status.User, nil
+}
+
+// Authorize reviews the access of the user to the applications with a SubjectAccessReview
+func (r *KubeAccessReviewer) Authorize(ctx context.Context, user *authenticationv1.UserInfo, namespace string, verb string) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		Groups: user.Groups,
+		UID:    user.UID,
+		Extra:  extra,
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      verb,
+			Group:     app.GroupVersion.Group,
+			Resource:  "m4dapplications",
+		},
+	}}
+	if err := r.Client.Create(ctx, review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// Impersonator returns the clients through which the applications are written on behalf of the callers of the API,
+// such that the admission webhooks and the audit log of the API server see the caller instead of the manager
+type Impersonator interface {
+	ClientFor(user *authenticationv1.UserInfo) (client.Client, error)
+}
+
+// KubeImpersonator creates clients impersonating the callers, which requires the manager to be allowed
+// to impersonate users, groups and service accounts
+type KubeImpersonator struct {
+	Config *rest.Config
+	Scheme *runtime.Scheme
+	Mapper meta.RESTMapper
+}
+
+// ClientFor returns a client impersonating the user
+func (i *KubeImpersonator) ClientFor(user *authenticationv1.UserInfo) (client.Client, error) {
+	config := rest.CopyConfig(i.Config)
+	config.Impersonate = rest.ImpersonationConfig{UserName: user.Username, Groups: user.Groups}
+	if len(user.Extra) > 0 {
+		config.Impersonate.Extra = make(map[string][]string, len(user.Extra))
+		for key, value := range user.Extra {
+			config.Impersonate.Extra[key] = value
+		}
+	}
+	return client.New(config, client.Options{Scheme: i.Scheme, Mapper: i.Mapper})
+}
+
+// ApplicationAPI lets SDKs apply applications and follow their status without access to the Kubernetes API.
+// The applications are defaulted and validated as by the admission webhooks before they are created or updated,
+// and are written on behalf of the callers.
+type ApplicationAPI struct {
+	Client       client.Client
+	Reviewer     AccessReviewer
+	Impersonator Impersonator
+	// AdminGroups are the groups whose members may pin modules
+	AdminGroups []string
+	// PollInterval is the interval at which the status of a watched application is checked for changes
+	PollInterval time.Duration
+	Log          logr.Logger
+}
+
+// NewApplicationAPI creates a new handler of the application API
+func NewApplicationAPI(c client.Client, reviewer AccessReviewer, impersonator Impersonator, adminGroups []string, log logr.Logger) *ApplicationAPI {
+	return &ApplicationAPI{Client: c, Reviewer: reviewer, Impersonator: impersonator, AdminGroups: adminGroups, PollInterval: time.Second, Log: log}
+}
+
+func (a *ApplicationAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the path is <prefix><namespace>/applications[/<name>]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, ApplicationAPIPrefix), "/")
+	if !strings.HasPrefix(r.URL.Path, ApplicationAPIPrefix) || len(parts) < 2 || len(parts) > 3 ||
+		parts[0] == "" || parts[1] != "applications" || (len(parts) == 3 && parts[2] == "") {
+		http.NotFound(w, r)
+		return
+	}
+	namespace := parts[0]
+	var verb string
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		// the verb is reviewed once it is known whether the application exists
+		verb = "apply"
+	case len(parts) == 3 && r.Method == http.MethodGet:
+		verb = "get"
+		if r.URL.Query().Get("watch") == "true" {
+			verb = "watch"
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := a.authenticate(w, r)
+	if !ok {
+		return
+	}
+	if verb == "apply" {
+		a.apply(w, r, namespace, user)
+		return
+	}
+	if !a.authorize(w, r, user, namespace, verb) {
+		return
+	}
+	switch verb {
+	case "get":
+		a.getStatus(w, r, types.NamespacedName{Namespace: namespace, Name: parts[2]})
+	default:
+		a.watchStatus(w, r, types.NamespacedName{Namespace: namespace, Name: parts[2]})
+	}
+}
+
+// authenticate authenticates the caller by its bearer token
+func (a *ApplicationAPI) authenticate(w http.ResponseWriter, r *http.Request) (*authenticationv1.UserInfo, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+		return nil, false
+	}
+	user, err := a.Reviewer.Authenticate(r.Context(), token)
+	if err != nil {
+		a.Log.Error(err, "unable to review the token of an application API request")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	if user == nil {
+		http.Error(w, "the bearer token is not valid", http.StatusUnauthorized)
+		return nil, false
+	}
+	return user, true
+}
+
+// authorize checks that the caller may perform the verb on the applications of the namespace
+func (a *ApplicationAPI) authorize(w http.ResponseWriter, r *http.Request, user *authenticationv1.UserInfo, namespace string, verb string) bool {
+	return a.authorizeAll(w, r, user, namespace, verb, []string{verb})
+}
+
+// authorizeAll checks that the caller may perform all the verbs on the applications of the namespace.
+// A denial is reported as a denial of the action, whichever verb is denied.
+func (a *ApplicationAPI) authorizeAll(w http.ResponseWriter, r *http.Request, user *authenticationv1.UserInfo, namespace string,
+	action string, verbs []string) bool {
+	for _, verb := range verbs {
+		allowed, err := a.Reviewer.Authorize(r.Context(), user, namespace, verb)
+		if err != nil {
+			a.Log.Error(err, "unable to review the access of an application API request", "user", user.Username)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return false
+		}
+		if !allowed {
+			http.Error(w, user.Username+" may not "+action+" m4dapplications in the namespace "+namespace, http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
+// apply creates the application, or updates the spec, labels and annotations of an existing one
+func (a *ApplicationAPI) apply(w http.ResponseWriter, r *http.Request, namespace string, user *authenticationv1.UserInfo) {
+	// the caller is authorized before the application is looked up, such that the existence of the application is not disclosed
+	if !a.authorizeAll(w, r, user, namespace, "apply", applyVerbs) {
+		return
+	}
+	application := &app.M4DApplication{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxApplicationBytes)).Decode(application); err != nil {
+		http.Error(w, "invalid application: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if application.Namespace != "" && application.Namespace != namespace {
+		http.Error(w, "the namespace of the application does not match the namespace of the request", http.StatusBadRequest)
+		return
+	}
+	if application.Name == "" {
+		http.Error(w, "the name of the application is required", http.StatusBadRequest)
+		return
+	}
+	application.Namespace = namespace
+	existing := &app.M4DApplication{}
+	err := a.Client.Get(r.Context(), client.ObjectKeyFromObject(application), existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	created := apierrors.IsNotFound(err)
+	// the requests are admitted as by the webhooks before they are written, such that invalid applications
+	// are rejected with a status of the API. The webhooks admit the writes again on behalf of the caller.
+	application.Default()
+	var previous []app.PinnedModule
+	if !created {
+		previous = existing.Spec.PinnedModules
+	}
+	if !equality.Semantic.DeepEqual(application.Spec.PinnedModules, previous) &&
+		(len(application.Spec.PinnedModules) > 0 || len(previous) > 0) &&
+		!(&app.PinnedModulesValidator{AdminGroups: a.AdminGroups}).IsAdmin(*user) {
+		http.Error(w, "only administrators may change spec.pinnedModules", http.StatusForbidden)
+		return
+	}
+	if created {
+		err = application.ValidateCreate()
+	} else {
+		err = application.ValidateUpdate(existing)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	writer, err := a.Impersonator.ClientFor(user)
+	if err != nil {
+		a.Log.Error(err, "unable to impersonate the caller of the application API", "user", user.Username)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if created {
+		application.ResourceVersion = ""
+		application.Status = app.M4DApplicationStatus{}
+		err = writer.Create(r.Context(), application)
+	} else {
+		existing.Spec = application.Spec
+		existing.Labels = application.Labels
+		existing.Annotations = application.Annotations
+		application = existing
+		err = writer.Update(r.Context(), application)
+	}
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	a.Log.Info("application applied", "application", client.ObjectKeyFromObject(application), "user", user.Username, "created", created)
+	writeJSON(w, http.StatusOK, &AppliedApplication{
+		Name:       application.Name,
+		Namespace:  application.Namespace,
+		UID:        string(application.UID),
+		Generation: application.Generation,
+		Created:    created,
+	})
+}
+
+// getStatus returns the current status of an application
+func (a *ApplicationAPI) getStatus(w http.ResponseWriter, r *http.Request, key types.NamespacedName) {
+	application := &app.M4DApplication{}
+	if err := a.Client.Get(r.Context(), key, application); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &ApplicationStatusEvent{Generation: application.Generation, Status: application.Status})
+}
+
+// watchStatus streams the status of an application as newline delimited JSON, each time it changes,
+// until the application is deleted or the caller disconnects
+func (a *ApplicationAPI) watchStatus(w http.ResponseWriter, r *http.Request, key types.NamespacedName) {
+	application := &app.M4DApplication{}
+	if err := a.Client.Get(r.Context(), key, application); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	ticker := time.NewTicker(a.PollInterval)
+	defer ticker.Stop()
+	// the current status is sent first, whatever its resource version
+	observed, sent := "", false
+	for {
+		event := &ApplicationStatusEvent{Generation: application.Generation, Status: application.Status}
+		if !sent || application.ResourceVersion != observed {
+			observed, sent = application.ResourceVersion, true
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+		if err := a.Client.Get(r.Context(), key, application); err != nil {
+			if apierrors.IsNotFound(err) {
+				_ = encoder.Encode(&ApplicationStatusEvent{Generation: application.Generation, Deleted: true})
+			}
+			return
+		}
+	}
+}
+
+// writeAPIError writes a Kubernetes API error with its status code
+func writeAPIError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Code != 0 {
+		code = int(status.Status().Code)
+	}
+	http.Error(w, err.Error(), code)
+}
+
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// ApplicationAPIServer serves the application API on its own address, over TLS only as the requests carry bearer tokens.
+// It implements manager.Runnable so that it is started and stopped together with the manager.
+type ApplicationAPIServer struct {
+	Address string
+	// CertDir holds the tls.crt and tls.key files of the serving certificate
+	CertDir string
+	Handler http.Handler
+	Log     logr.Logger
+}
+
+// Start serves the application API until the context is done
+func (s *ApplicationAPIServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(ApplicationAPIPrefix, s.Handler)
+	// the watches end when the manager stops
+	server := &http.Server{Addr: s.Address, Handler: mux, BaseContext: func(net.Listener) context.Context { return ctx }}
+
+	if s.CertDir == "" {
+		return errors.New("the application API is only served over TLS, a certificate directory is required")
+	}
+	certFile, keyFile := filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key")
+
+	errChan := make(chan error, 1)
+	go func() {
+		s.Log.Info("starting application API server", "address", s.Address)
+		if err := server.ListenAndServeTLS(certFile, keyFile); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		return err
+	}
+}
+
+// NeedLeaderElection returns false as every replica of the manager serves the API
+func (s *ApplicationAPIServer) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,177 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeAccessReviewer authenticates the tokens it knows, and authorizes the users to access the namespace "default",
+// except for the denied verbs of each user
+type fakeAccessReviewer struct {
+	users  map[string]*authenticationv1.UserInfo
+	denied map[string]string
+}
+
+func (r *fakeAccessReviewer) Authenticate(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
+	return r.users[token], nil
+}
+
+func (r *fakeAccessReviewer) Authorize(ctx context.Context, user *authenticationv1.UserInfo, namespace string, verb string) (bool, error) {
+	return namespace == "default" && r.denied[user.Username] != verb, nil
+}
+
+// fakeImpersonator records the users on behalf of which the applications are written
+type fakeImpersonator struct {
+	client.Client
+	users []string
+}
+
+func (i *fakeImpersonator) ClientFor(user *authenticationv1.UserInfo) (client.Client, error) {
+	i.users = append(i.users, user.Username)
+	return i.Client, nil
+}
+
+func applyRequest(api *ApplicationAPI, token string, namespace string, application *app.M4DApplication) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(application)
+	req := httptest.NewRequest(http.MethodPost, ApplicationAPIPrefix+namespace+"/applications", bytes.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	api.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// TestApplicationAPI checks that applications are applied and their status returned on behalf of the authorized callers
+func TestApplicationAPI(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/m4dcopyapp-csv.yaml", application)).NotTo(gomega.HaveOccurred())
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g))
+	reviewer := &fakeAccessReviewer{users: map[string]*authenticationv1.UserInfo{
+		"user-token":    {Username: "alice", Groups: []string{"system:authenticated"}},
+		"admin-token":   {Username: "bob", Groups: []string{"admins"}},
+		"creator-token": {Username: "carol"},
+	}, denied: map[string]string{"carol": "update"}}
+	impersonator := &fakeImpersonator{Client: cl}
+	api := NewApplicationAPI(cl, reviewer, impersonator, []string{"admins"}, ctrl.Log.WithName("application-api"))
+
+	// the callers are authenticated and authorized
+	g.Expect(applyRequest(api, "", "default", application).Code).To(gomega.Equal(http.StatusUnauthorized))
+	g.Expect(applyRequest(api, "unknown", "default", application).Code).To(gomega.Equal(http.StatusUnauthorized))
+	g.Expect(applyRequest(api, "user-token", "other", application).Code).To(gomega.Equal(http.StatusForbidden))
+
+	// the application is created, and updated when applied again
+	recorder := applyRequest(api, "user-token", "default", application)
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	applied := &AppliedApplication{}
+	g.Expect(json.Unmarshal(recorder.Body.Bytes(), applied)).To(gomega.Succeed())
+	g.Expect(applied.Name).To(gomega.Equal(application.Name))
+	g.Expect(applied.Created).To(gomega.BeTrue())
+	application.Spec.AppInfo["intent"] = "marketing"
+	recorder = applyRequest(api, "user-token", "default", application)
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(json.Unmarshal(recorder.Body.Bytes(), applied)).To(gomega.Succeed())
+	g.Expect(applied.Created).To(gomega.BeFalse())
+	stored := &app.M4DApplication{}
+	g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(application), stored)).To(gomega.Succeed())
+	g.Expect(stored.Spec.AppInfo["intent"]).To(gomega.Equal("marketing"))
+	// the applications are written on behalf of the caller
+	g.Expect(impersonator.users).To(gomega.Equal([]string{"alice", "alice"}))
+
+	// applying an application requires both the create and update verbs, whether the application exists or not
+	recorder = applyRequest(api, "creator-token", "default", application)
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusForbidden))
+	g.Expect(recorder.Body.String()).To(gomega.ContainSubstring("carol may not apply m4dapplications"))
+	unknown := application.DeepCopy()
+	unknown.Name = "unknown"
+	g.Expect(applyRequest(api, "creator-token", "default", unknown).Body.String()).To(gomega.Equal(recorder.Body.String()))
+
+	// the size of the applications is limited
+	req := httptest.NewRequest(http.MethodPost, ApplicationAPIPrefix+"default/applications",
+		io.MultiReader(strings.NewReader(`{"metadata":{"name":"`), strings.NewReader(strings.Repeat("a", maxApplicationBytes))))
+	req.Header.Set("Authorization", "Bearer user-token")
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, req)
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusBadRequest))
+
+	// the application is validated as by the webhooks
+	invalid := application.DeepCopy()
+	invalid.Spec.Data = append(invalid.Spec.Data, invalid.Spec.Data[0])
+	invalid.Spec.Data[1].Requirements.Copy.Required = false
+	g.Expect(applyRequest(api, "user-token", "default", invalid).Code).To(gomega.Equal(http.StatusUnprocessableEntity))
+
+	// only administrators may pin modules
+	pinned := application.DeepCopy()
+	pinned.Spec.PinnedModules = []app.PinnedModule{{DataSetID: "s3-csv/redact-dataset", Flow: app.Copy, Module: "implicit-copy-batch"}}
+	g.Expect(applyRequest(api, "user-token", "default", pinned).Code).To(gomega.Equal(http.StatusForbidden))
+	g.Expect(applyRequest(api, "admin-token", "default", pinned).Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(impersonator.users[len(impersonator.users)-1]).To(gomega.Equal("bob"))
+
+	// the status is returned
+	g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(application), stored)).To(gomega.Succeed())
+	stored.Status.Ready = true
+	g.Expect(cl.Status().Update(context.Background(), stored)).To(gomega.Succeed())
+	req = httptest.NewRequest(http.MethodGet, ApplicationAPIPrefix+"default/applications/"+application.Name, nil)
+	req.Header.Set("Authorization", "Bearer user-token")
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, req)
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	event := &ApplicationStatusEvent{}
+	g.Expect(json.Unmarshal(recorder.Body.Bytes(), event)).To(gomega.Succeed())
+	g.Expect(event.Status.Ready).To(gomega.BeTrue())
+}
+
+// TestApplicationAPIWatch checks that the status is streamed until the application is deleted
+func TestApplicationAPIWatch(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/m4dcopyapp-csv.yaml", application)).NotTo(gomega.HaveOccurred())
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), application)
+	reviewer := &fakeAccessReviewer{users: map[string]*authenticationv1.UserInfo{"user-token": {Username: "alice"}}}
+	api := NewApplicationAPI(cl, reviewer, &fakeImpersonator{Client: cl}, nil, ctrl.Log.WithName("application-api"))
+	api.PollInterval = 10 * time.Millisecond
+
+	req := httptest.NewRequest(http.MethodGet, ApplicationAPIPrefix+"default/applications/"+application.Name+"?watch=true", nil)
+	req.Header.Set("Authorization", "Bearer user-token")
+	recorder := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		api.ServeHTTP(recorder, req)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	g.Expect(cl.Delete(context.Background(), application.DeepCopy())).To(gomega.Succeed())
+	g.Eventually(done).Should(gomega.BeClosed())
+
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	g.Expect(lines).To(gomega.HaveLen(2))
+	event := &ApplicationStatusEvent{}
+	g.Expect(json.Unmarshal([]byte(lines[0]), event)).To(gomega.Succeed())
+	g.Expect(event.Deleted).To(gomega.BeFalse())
+	g.Expect(json.Unmarshal([]byte(lines[1]), event)).To(gomega.Succeed())
+	g.Expect(event.Deleted).To(gomega.BeTrue())
+}
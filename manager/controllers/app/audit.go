@@ -0,0 +1,99 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sort"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/audit"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// auditRecord describes the policy decisions on the datasets of the application and the modules constructing its data
+// plane, as evaluated by the reconcile that has produced the status of the application
+func auditRecord(application *app.M4DApplication, blueprints map[string]app.BlueprintSpec) audit.Record {
+	record := audit.Record{
+		Application: audit.Application{
+			Name:       application.Name,
+			Namespace:  application.Namespace,
+			UID:        string(application.UID),
+			Generation: application.Generation,
+		},
+		Requester: audit.Requester{
+			Cluster:          application.Spec.Selector.ClusterName,
+			WorkloadSelector: application.Spec.Selector.WorkloadSelector.MatchLabels,
+			AppInfo:          application.Spec.AppInfo,
+		},
+		Decisions: []audit.Decision{},
+		Modules:   []audit.Module{},
+		Outcome:   audit.Granted,
+	}
+	datasetIDs := make([]string, 0, len(application.Status.AssetStates))
+	for datasetID := range application.Status.AssetStates {
+		datasetIDs = append(datasetIDs, datasetID)
+	}
+	sort.Strings(datasetIDs)
+	for _, datasetID := range datasetIDs {
+		for _, action := range application.Status.AssetStates[datasetID].Actions {
+			record.Decisions = append(record.Decisions, audit.Decision{
+				DatasetID:   datasetID,
+				Flow:        string(action.Flow),
+				Action:      action.Name,
+				Level:       action.Level,
+				Args:        action.Args,
+				Destination: action.Destination,
+				Denied:      action.Name == evaluate.DenyAction,
+				Module:      action.Module,
+			})
+		}
+	}
+	clusters := make([]string, 0, len(blueprints))
+	for cluster := range blueprints {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	for _, cluster := range clusters {
+		for i := range blueprints[cluster].Flow.Steps {
+			step := &blueprints[cluster].Flow.Steps[i]
+			record.Modules = append(record.Modules, audit.Module{
+				Name:      step.Template,
+				Cluster:   cluster,
+				Flow:      string(evaluate.ArgumentsFlow(&step.Arguments)),
+				Step:      step.Name,
+				DatasetID: step.Arguments.Annotations[app.DatasetIDsAnnotation],
+			})
+		}
+	}
+	if generated := application.Status.Generated; generated != nil {
+		record.Generated = generated.Kind + " " + generated.Namespace + "/" + generated.Name
+	}
+	switch {
+	case meta.IsStatusConditionTrue(application.Status.Conditions, app.DeniedCondition):
+		record.Outcome = audit.Denied
+	case meta.IsStatusConditionTrue(application.Status.Conditions, app.ErrorCondition):
+		record.Outcome = audit.Failed
+	}
+	record.Message = evaluate.GetErrorMessages(application)
+	return record
+}
+
+// auditReconcile records the outcome of a reconcile evaluating the policies of the application, if an audit log is configured.
+// The evaluation is nil if it has failed, in which case the reconcile is recorded as failed without modules.
+func (r *M4DApplicationReconciler) auditReconcile(application *app.M4DApplication, evaluation *evaluate.Evaluation) {
+	if r.Audit == nil {
+		return
+	}
+	if evaluation == nil {
+		record := auditRecord(application, nil)
+		record.Outcome = audit.Failed
+		if record.Message == "" {
+			record.Message = "the governance policies could not be evaluated"
+		}
+		r.Audit.Log(record)
+		return
+	}
+	r.Audit.Log(auditRecord(application, evaluation.Blueprints))
+}
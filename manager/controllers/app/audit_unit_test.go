@@ -0,0 +1,82 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/audit"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordingAuditLogger keeps the audit records in memory
+type recordingAuditLogger struct {
+	records []audit.Record
+}
+
+func (l *recordingAuditLogger) Log(record audit.Record) {
+	l.records = append(l.records, record)
+}
+
+func TestAuditRecord(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default", UID: "uid", Generation: 2},
+		Spec: app.M4DApplicationSpec{
+			Selector: app.Selector{ClusterName: "thegreendragon",
+				WorkloadSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "notebook"}}},
+			AppInfo: app.ApplicationDetails{"intent": "fraud-detection"},
+		},
+		Status: app.M4DApplicationStatus{
+			AssetStates: map[string]app.AssetState{
+				"s3/redact-dataset": {Actions: []app.AppliedAction{
+					{Name: "redact", Level: "COLUMN", Flow: app.Read, Module: "arrow-flight-module"}}},
+				"s3/deny-dataset": {Actions: []app.AppliedAction{{Name: evaluate.DenyAction, Flow: app.Write, Destination: "Turkey"}}},
+			},
+			Generated: &app.ResourceReference{Kind: "Plotter", Namespace: "m4d-system", Name: "notebook-default"},
+		},
+	}
+	blueprints := map[string]app.BlueprintSpec{
+		"thegreendragon": {Flow: app.DataFlow{Steps: []app.FlowStep{{Name: "read-step", Template: "arrow-flight-module",
+			Arguments: app.ModuleArguments{Read: []app.ReadModuleArgs{{AssetID: "s3/redact-dataset"}},
+				Annotations: map[string]string{app.DatasetIDsAnnotation: "s3/redact-dataset"}}}}}},
+		"neverland-cluster": {Flow: app.DataFlow{Steps: []app.FlowStep{{Name: "copy-step", Template: "implicit-copy-batch",
+			Arguments: app.ModuleArguments{Copy: &app.CopyModuleArgs{}}}}}},
+	}
+	record := auditRecord(application, blueprints)
+	g.Expect(record.Application).To(gomega.Equal(audit.Application{Name: "notebook", Namespace: "default", UID: "uid", Generation: 2}))
+	g.Expect(record.Requester.WorkloadSelector).To(gomega.HaveKeyWithValue("app", "notebook"))
+	g.Expect(record.Requester.AppInfo).To(gomega.HaveKeyWithValue("intent", "fraud-detection"))
+	// the decisions and the modules are sorted by dataset and by cluster
+	g.Expect(record.Decisions).To(gomega.Equal([]audit.Decision{
+		{DatasetID: "s3/deny-dataset", Flow: "write", Action: evaluate.DenyAction, Destination: "Turkey", Denied: true},
+		{DatasetID: "s3/redact-dataset", Flow: "read", Action: "redact", Level: "COLUMN", Module: "arrow-flight-module"},
+	}))
+	g.Expect(record.Modules).To(gomega.Equal([]audit.Module{
+		{Name: "implicit-copy-batch", Cluster: "neverland-cluster", Flow: "copy", Step: "copy-step"},
+		{Name: "arrow-flight-module", Cluster: "thegreendragon", Flow: "read", Step: "read-step", DatasetID: "s3/redact-dataset"},
+	}))
+	g.Expect(record.Generated).To(gomega.Equal("Plotter m4d-system/notebook-default"))
+	g.Expect(record.Outcome).To(gomega.Equal(audit.Granted))
+
+	evaluate.SetCondition(application, "s3/deny-dataset", app.WriteNotAllowed, true)
+	g.Expect(auditRecord(application, nil).Outcome).To(gomega.Equal(audit.Denied))
+
+	// the reconcile is audited if an audit log is configured
+	logger := &recordingAuditLogger{}
+	r := &M4DApplicationReconciler{Audit: logger}
+	r.auditReconcile(application, &evaluate.Evaluation{Blueprints: blueprints})
+	g.Expect(logger.records).To(gomega.HaveLen(1))
+	// a reconcile in which the evaluation fails is audited as failed
+	r.auditReconcile(application, nil)
+	g.Expect(logger.records).To(gomega.HaveLen(2))
+	g.Expect(logger.records[1].Outcome).To(gomega.Equal(audit.Failed))
+	g.Expect(logger.records[1].Modules).To(gomega.BeEmpty())
+	(&M4DApplicationReconciler{}).auditReconcile(application, &evaluate.Evaluation{})
+}
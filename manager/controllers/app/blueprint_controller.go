@@ -19,18 +19,25 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
 	"github.com/mesh-for-data/mesh-for-data/pkg/helm"
 	corev1 "k8s.io/api/core/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	kstatus "sigs.k8s.io/cli-utils/pkg/kstatus/status"
 )
 
+// ExternalDNSHostnameAnnotation is the annotation used by external-dns to register the hostname of a service
+const ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
 // BlueprintReconciler reconciles a Blueprint object
 type BlueprintReconciler struct {
 	client.Client
@@ -38,6 +45,11 @@ type BlueprintReconciler struct {
 	Log    logr.Logger
 	Scheme *runtime.Scheme
 	Helmer helm.Interface
+	// Options tunes the concurrency and the retries of the controller
+	Options ControllerOptions
+	// Pods reads the pods of the modules from a cache restricted to the blueprint namespace.
+	// The pods are read by the client if it is not set.
+	Pods client.Reader
 }
 
 // Reconcile receives a Blueprint CRD
@@ -90,6 +102,9 @@ func (r *BlueprintReconciler) reconcileFinalizers(blueprint *app.Blueprint) (ctr
 	if !blueprint.DeletionTimestamp.IsZero() {
 		// The object is being deleted
 		if hasFinalizer { // Finalizer was created when the object was created
+			if utils.IsReadOnlyMode() {
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.NewPlain("the manager is in read-only mode, helm releases can not be uninstalled")
+			}
 			// the finalizer is present - delete the allocated resources
 			if err := r.deleteExternalResources(blueprint); err != nil {
 				r.Log.V(0).Info("Error while deleting owned resources: " + err.Error())
@@ -144,11 +159,18 @@ func (r *BlueprintReconciler) hasExternalResources(blueprint *app.Blueprint) boo
 	return false
 }
 
-func (r *BlueprintReconciler) applyChartResource(log logr.Logger, chartSpec app.ChartSpec, args map[string]interface{}, blueprint *app.Blueprint, releaseName string) (ctrl.Result, error) {
+// applyChartResource installs or upgrades the release of a module, injecting the given governance sidecars in its pods
+func (r *BlueprintReconciler) applyChartResource(log logr.Logger, chartSpec app.ChartSpec, args map[string]interface{}, sidecars []app.Sidecar,
+	blueprint *app.Blueprint, releaseName string) (ctrl.Result, error) {
 	log.Info(fmt.Sprintf("--- Chart Ref ---\n\n%v\n\n", chartSpec.Name))
 	kubeNamespace := blueprint.Namespace
 
-	args = CopyMap(args)
+	if !evaluate.IsValuesContractSupported(&chartSpec) {
+		return ctrl.Result{}, errors.Errorf("%s: values contract %s is not supported", chartSpec.Name, chartSpec.ValuesContract)
+	}
+	args = utils.CopyMap(args)
+	evaluate.AdaptValues(args, &chartSpec)
+	args = evaluate.ApplyOverrides(args, &chartSpec)
 	for k, v := range chartSpec.Values {
 		SetMapField(args, k, v)
 	}
@@ -158,6 +180,16 @@ func (r *BlueprintReconciler) applyChartResource(log logr.Logger, chartSpec app.
 		app.BlueprintNamespaceLabel:   kubeNamespace,
 		app.BlueprintNameLabel:        blueprint.Name,
 	}
+	// labels propagated from the application (e.g. for cost attribution) are kept unless they conflict with the labels above
+	if propagated, ok := args["labels"].(map[string]interface{}); ok {
+		for k, v := range propagated {
+			if _, found := labels[k]; !found {
+				if value, isString := v.(string); isString {
+					labels[k] = value
+				}
+			}
+		}
+	}
 	SetMapField(args, "labels", labels)
 	nbytes, _ := yaml.Marshal(args)
 	log.Info(fmt.Sprintf("--- Values.yaml ---\n\n%s\n\n", nbytes))
@@ -173,12 +205,16 @@ func (r *BlueprintReconciler) applyChartResource(log logr.Logger, chartSpec app.
 
 	rel, err := r.Helmer.Status(kubeNamespace, releaseName)
 	if err == nil && rel != nil {
-		rel, err = r.Helmer.Upgrade(chart, kubeNamespace, releaseName, args)
+		if appName, appNamespace, owned := releaseOwner(rel); owned &&
+			(appName != labels[app.ApplicationNameLabel] || appNamespace != labels[app.ApplicationNamespaceLabel]) {
+			return ctrl.Result{}, errors.Errorf("release name %s is already used by application %s/%s", releaseName, appNamespace, appName)
+		}
+		rel, err = r.Helmer.Upgrade(chart, kubeNamespace, releaseName, args, newSidecarInjector(sidecars))
 		if err != nil {
 			return ctrl.Result{}, errors.WithMessage(err, chartSpec.Name+": failed upgrade")
 		}
 	} else {
-		rel, err = r.Helmer.Install(chart, kubeNamespace, releaseName, args)
+		rel, err = r.Helmer.Install(chart, kubeNamespace, releaseName, args, newSidecarInjector(sidecars))
 		if err != nil {
 			return ctrl.Result{}, errors.WithMessage(err, chartSpec.Name+": failed install")
 		}
@@ -187,19 +223,19 @@ func (r *BlueprintReconciler) applyChartResource(log logr.Logger, chartSpec app.
 	return ctrl.Result{}, nil
 }
 
-// CopyMap copies a map
-func CopyMap(m map[string]interface{}) map[string]interface{} {
-	cp := make(map[string]interface{})
-	for k, v := range m {
-		vm, ok := v.(map[string]interface{})
-		if ok {
-			cp[k] = CopyMap(vm)
-		} else {
-			cp[k] = v
-		}
+// releaseOwner returns the name and namespace of the application for which the release has been installed,
+// as recorded in the labels passed to the chart
+func releaseOwner(rel *release.Release) (string, string, bool) {
+	if rel.Config == nil {
+		return "", "", false
 	}
-
-	return cp
+	labels, ok := rel.Config["labels"].(map[string]interface{})
+	if !ok {
+		return "", "", false
+	}
+	appName, nameFound := labels[app.ApplicationNameLabel].(string)
+	appNamespace, namespaceFound := labels[app.ApplicationNamespaceLabel].(string)
+	return appName, appNamespace, nameFound && namespaceFound
 }
 
 // SetMapField updates a map
@@ -226,14 +262,43 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, log logr.Logger, bl
 	// Gather all templates and process them into a list of resources to apply
 	// force-update if the blueprint spec is different
 	updateRequired := blueprint.Status.ObservedGeneration != blueprint.GetGeneration()
-	blueprint.Status.ObservedGeneration = blueprint.GetGeneration()
+	// in read-only mode no chart is installed, upgraded or uninstalled, only the status of the releases is checked
+	readOnly := utils.IsReadOnlyMode()
+	if !readOnly {
+		blueprint.Status.ObservedGeneration = blueprint.GetGeneration()
+	}
 	// reset blueprint state
 	blueprint.Status.ObservedState.Ready = false
 	blueprint.Status.ObservedState.Error = ""
 	blueprint.Status.ObservedState.DataAccessInstructions = ""
+	blueprint.Status.ObservedState.Health = nil
+	blueprint.Status.ObservedState.ExternalHostnames = nil
 	if blueprint.Status.Releases == nil {
 		blueprint.Status.Releases = map[string]int64{}
 	}
+	// the reruns of failed jobs are counted until the spec changes
+	previousSteps := blueprint.Status.Steps
+	if updateRequired {
+		previousSteps = nil
+	}
+	blueprint.Status.Steps = map[string]app.StepResources{}
+	// the delay before rerunning failed jobs, zero if no job is rerun
+	var rerunAfter time.Duration
+
+	// release names are shortened using a hash, make sure that each step is deployed by a separate release
+	if _, err := utils.GetReleaseNames(blueprint.Labels[app.ApplicationNameLabel], blueprint.Labels[app.ApplicationNamespaceLabel],
+		blueprint.Spec.Flow.Steps); err != nil {
+		blueprint.Status.ObservedState.Error = err.Error()
+		return ctrl.Result{}, err
+	}
+
+	// the governance decisions are published to the modules before their charts are applied
+	if !readOnly {
+		if err := r.applyDecisions(ctx, blueprint); err != nil {
+			blueprint.Status.ObservedState.Error = err.Error()
+			return ctrl.Result{}, err
+		}
+	}
 
 	// count the overall number of Helm releases and how many of them are ready
 	numReleases, numReady := 0, 0
@@ -256,34 +321,85 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, log logr.Logger, bl
 		if err != nil {
 			return ctrl.Result{}, errors.WithMessage(err, "Blueprint step arguments are invalid")
 		}
+		// the governance sidecars are injected in the rendered manifests rather than passed to the chart
+		delete(args, "sidecars")
+		setDecisionsValues(args, blueprint)
 		releaseName := utils.GetReleaseName(blueprint.Labels[app.ApplicationNameLabel], blueprint.Labels[app.ApplicationNamespaceLabel], step)
 		log.V(0).Info("Release name: " + releaseName)
 		numReleases++
+		readiness := stepReadiness(&step)
+		stepResources := app.StepResources{Release: releaseName, Namespace: blueprint.Namespace, Readiness: readiness,
+			State: app.StepPending, Reruns: previousSteps[step.Name].Reruns}
+		// read and write modules are registered in an external DNS if a domain is configured
+		externalHostname := ""
+		accessedByWorkload := len(step.Arguments.Read) > 0 || len(step.Arguments.Write) > 0
+		if domain := utils.GetExternalDNSDomain(); domain != "" && accessedByWorkload {
+			externalHostname = utils.GenerateModuleExternalHostname(releaseName, domain)
+			SetMapField(args, "externalDNS.hostname", externalHostname)
+		}
 		// check the release status
 		rel, err := r.Helmer.Status(blueprint.Namespace, releaseName)
 		// unexisting release or a failed release - re-apply the chart
 		if updateRequired || err != nil || rel == nil || rel.Info.Status == release.StatusFailed {
+			if readOnly {
+				log.V(0).Info("Not applying the chart in read-only mode", "release", releaseName)
+				continue
+			}
 			// Process templates with arguments
 			chart := templateSpec.Chart
-			if _, err := r.applyChartResource(log, chart, args, blueprint, releaseName); err != nil {
+			if _, err := r.applyChartResource(log, chart, args, step.Arguments.Sidecars, blueprint, releaseName); err != nil {
 				blueprint.Status.ObservedState.Error += errors.Wrap(err, "ChartDeploymentFailure: ").Error() + "\n"
 			}
 		} else if rel.Info.Status == release.StatusDeployed {
-			if len(step.Arguments.Read) > 0 {
+			if accessedByWorkload {
 				blueprint.Status.ObservedState.DataAccessInstructions += rel.Info.Notes
 			}
-			status, errMsg := r.checkReleaseStatus(releaseName, blueprint.Namespace)
+			if externalHostname != "" {
+				// the hostname is reported to the application only once the service of the module is registered
+				if registered, err := r.registerExternalHostname(ctx, blueprint.Namespace, releaseName, externalHostname, readOnly); err != nil {
+					log.V(0).Info("Could not register the external hostname of release " + releaseName + " : " + err.Error())
+				} else if registered {
+					if blueprint.Status.ObservedState.ExternalHostnames == nil {
+						blueprint.Status.ObservedState.ExternalHostnames = map[string]string{}
+					}
+					blueprint.Status.ObservedState.ExternalHostnames[releaseName] = externalHostname
+				}
+			}
+			status, errMsg, resources := r.checkReleaseStatus(releaseName, blueprint.Namespace, readiness)
+			stepResources.Workloads = workloadNames(resources)
+			stepResources.State = stepState(status)
+			stepResources.Health = r.stepHealth(blueprint.Namespace, resources)
+			addAssetHealth(&blueprint.Status.ObservedState, &step, stepResources.Health)
 			if status == corev1.ConditionFalse {
-				blueprint.Status.ObservedState.Error += "ResourceAllocationFailure: " + errMsg + "\n"
+				// the failed jobs of a step run to completion are run again by installing its release again.
+				// The step is pending while it is rerun, the error is reported only if it cannot be rerun.
+				rerun := false
+				if readiness == app.CompletionReadiness && stepResources.Reruns < app.MaxStepReruns && !readOnly {
+					if _, err := r.Helmer.Uninstall(blueprint.Namespace, releaseName); err != nil {
+						log.V(0).Info("Error uninstalling failed release " + releaseName + " : " + err.Error())
+					} else {
+						if delay := rerunDelay(stepResources.Reruns); rerunAfter == 0 || delay < rerunAfter {
+							rerunAfter = delay
+						}
+						stepResources.Reruns++
+						stepResources.State = app.StepPending
+						rerun = true
+						log.V(0).Info("Rerunning the failed jobs of release " + releaseName + ": " + errMsg)
+					}
+				}
+				if !rerun {
+					blueprint.Status.ObservedState.Error += "ResourceAllocationFailure: " + errMsg + "\n"
+				}
 			} else if status == corev1.ConditionTrue {
 				numReady++
 			}
 		}
 		blueprint.Status.Releases[releaseName] = blueprint.Status.ObservedGeneration
+		blueprint.Status.Steps[step.Name] = stepResources
 	}
 	// clean-up
 	for release, version := range blueprint.Status.Releases {
-		if version != blueprint.Status.ObservedGeneration {
+		if version != blueprint.Status.ObservedGeneration && !readOnly {
 			_, err := r.Helmer.Uninstall(blueprint.Namespace, release)
 			if err != nil {
 				log.V(0).Info("Error uninstalling release " + release + " : " + err.Error())
@@ -296,9 +412,14 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, log logr.Logger, bl
 	if numReady == numReleases {
 		// all modules have been orhestrated successfully - the data is ready for use
 		blueprint.Status.ObservedState.Ready = true
+		// the health of the pods of the modules is checked again when the pods change
 		return ctrl.Result{}, nil
 	}
 
+	// failed jobs are run again after a delay
+	if rerunAfter > 0 {
+		return ctrl.Result{RequeueAfter: rerunAfter}, nil
+	}
 	// the status is unknown yet - continue polling
 	if blueprint.Status.ObservedState.Error == "" {
 		return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
@@ -306,6 +427,32 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, log logr.Logger, bl
 	return ctrl.Result{}, nil
 }
 
+// registerExternalHostname annotates the service of a read module with the hostname to be registered by external-dns.
+// The DNS record is removed by external-dns once the service is deleted together with the release.
+// Charts that expose the module differently (e.g. by an ingress) receive the hostname in the externalDNS.hostname value.
+// It returns whether the service is registered, i.e. false if the chart does not create a service named after the release.
+// In read-only mode the service is not annotated, only an existing registration is reported.
+func (r *BlueprintReconciler) registerExternalHostname(ctx context.Context, namespace string, releaseName string, hostname string, readOnly bool) (bool, error) {
+	service := &corev1.Service{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: releaseName}, service); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if service.Annotations[ExternalDNSHostnameAnnotation] == hostname {
+		return true, nil
+	}
+	if readOnly {
+		return false, nil
+	}
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[ExternalDNSHostnameAnnotation] = hostname
+	if err := r.Update(ctx, service); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func findComponentTemplateByName(templates []app.ComponentTemplate, name string) (*app.ComponentTemplate, error) {
 	// TODO(roee.shlomo): BlueprintSpec#Templates should probably be a map from name to the module spec. Then we can remove this function.
 	for _, template := range templates {
@@ -334,16 +481,28 @@ func (r *BlueprintReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// If it's not then it is a rogue event created by someone outside of the control plane.
 	p := predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
-			return e.Object.GetNamespace() == BlueprintNamespace
+			return e.Object.GetNamespace() == utils.BlueprintNamespace
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return e.ObjectOld.GetNamespace() == BlueprintNamespace
+			return e.ObjectOld.GetNamespace() == utils.BlueprintNamespace
 		},
 	}
 
+	// the pods of the modules are watched in the blueprint namespace only, rather than by a cluster-wide informer
+	pods, err := cache.New(mgr.GetConfig(), cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper(), Namespace: utils.BlueprintNamespace})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(pods); err != nil {
+		return err
+	}
+	r.Pods = pods
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&app.Blueprint{}).
+		Watches(source.NewKindWithCache(&corev1.Pod{}, pods), handler.EnqueueRequestsFromMapFunc(r.podBlueprints)).
 		WithEventFilter(p).
+		WithOptions(r.Options.controllerOptions(r)).
 		Complete(r)
 }
 
@@ -365,8 +524,9 @@ func (r *BlueprintReconciler) getExpectedResults(kind string) (*app.ResourceStat
 	return nil, nil
 }
 
-// checkResourceStatus returns the computed state and an error message if exists
-func (r *BlueprintReconciler) checkResourceStatus(res *unstructured.Unstructured) (corev1.ConditionStatus, string) {
+// checkResourceStatus returns the computed state and an error message if exists.
+// The status indicators of the modules take precedence over the readiness semantics of the step.
+func (r *BlueprintReconciler) checkResourceStatus(res *unstructured.Unstructured, readiness app.StepReadiness) (corev1.ConditionStatus, string) {
 	// get indications how to compute the resource status based on a module spec
 	expected, err := r.getExpectedResults(res.GetKind())
 	if err != nil {
@@ -374,6 +534,10 @@ func (r *BlueprintReconciler) checkResourceStatus(res *unstructured.Unstructured
 		return corev1.ConditionUnknown, ""
 	}
 	if expected == nil {
+		// jobs and deployments are ready according to the readiness semantics of the step
+		if status, msg, defined := readinessStatus(res, readiness); defined {
+			return status, msg
+		}
 		// use kstatus to compute the status of the resources for them the expected results have not been specified
 		// Current status of a deployed release indicates that the resource has been successfully reconciled
 		// Failed status indicates a failure
@@ -428,27 +592,49 @@ func (r *BlueprintReconciler) matchesCondition(res *unstructured.Unstructured, c
 	return true
 }
 
-func (r *BlueprintReconciler) checkReleaseStatus(releaseName string, namespace string) (corev1.ConditionStatus, string) {
+func (r *BlueprintReconciler) checkReleaseStatus(releaseName string, namespace string, readiness app.StepReadiness) (corev1.ConditionStatus, string, []*unstructured.Unstructured) {
 	// get all resources for the given helm release in their current state
 	resources, err := r.Helmer.GetResources(namespace, releaseName)
 	if err != nil {
 		r.Log.V(0).Info("Error getting resources: " + err.Error())
-		return corev1.ConditionUnknown, ""
+		return corev1.ConditionUnknown, "", nil
 	}
 	// return True if all resources are ready, False - if any resource failed, Unknown - otherwise
 	numReady := 0
 	for _, res := range resources {
-		state, errMsg := r.checkResourceStatus(res)
+		state, errMsg := r.checkResourceStatus(res, readiness)
 		r.Log.V(0).Info("Status of " + res.GetKind() + " " + res.GetName() + " is " + string(state))
 		if state == corev1.ConditionFalse {
-			return state, errMsg
+			return state, errMsg, resources
 		}
 		if state == corev1.ConditionTrue {
 			numReady++
 		}
 	}
 	if numReady == len(resources) {
-		return corev1.ConditionTrue, ""
+		return corev1.ConditionTrue, "", resources
 	}
-	return corev1.ConditionUnknown, ""
+	return corev1.ConditionUnknown, "", resources
+}
+
+// workloadKinds are the kinds of the resources listed as the workloads of a release
+var workloadKinds = map[string]bool{
+	"Deployment":     true,
+	"StatefulSet":    true,
+	"DaemonSet":      true,
+	"Job":            true,
+	"CronJob":        true,
+	"BatchTransfer":  true,
+	"StreamTransfer": true,
+}
+
+// workloadNames returns the workloads among the resources of a release as <kind>/<name>
+func workloadNames(resources []*unstructured.Unstructured) []string {
+	var names []string
+	for _, res := range resources {
+		if workloadKinds[res.GetKind()] {
+			names = append(names, res.GetKind()+"/"+res.GetName())
+		}
+	}
+	return names
 }
@@ -11,9 +11,12 @@ import (
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
 	"github.com/mesh-for-data/mesh-for-data/pkg/helm"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	"github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -80,6 +83,13 @@ func TestBlueprintReconcile(t *testing.T) {
 	g.Expect(blueprint.Status.Releases).To(gomega.HaveLen(2))
 	g.Expect(blueprint.Status.Releases).Should(gomega.HaveKeyWithValue("notebook-default-notebook-copy-batch", blueprint.Status.ObservedGeneration))
 	g.Expect(blueprint.Status.Releases).Should(gomega.HaveKeyWithValue("notebook-default-notebook-read-module", blueprint.Status.ObservedGeneration))
+
+	// the governance decisions are published in a ConfigMap owned by the blueprint
+	decisions := &corev1.ConfigMap{}
+	g.Expect(cl.Get(context.Background(), client.ObjectKey{Namespace: blueprint.Namespace, Name: decisionsConfigMapName(blueprint)}, decisions)).To(gomega.Succeed())
+	g.Expect(decisions.Data).To(gomega.HaveKeyWithValue(DecisionsKey, `{"xyz":{"read":[]}}`))
+	g.Expect(decisions.OwnerReferences).To(gomega.HaveLen(1))
+	g.Expect(decisions.OwnerReferences[0].Name).To(gomega.Equal(blueprint.Name))
 }
 
 // This test checks that a short release name is not truncated
@@ -139,3 +149,133 @@ func TestLongReleaseName(t *testing.T) {
 	g.Expect(relName2).To(gomega.Equal("my-app-default-ohandnottoforgettheflowstepnamet-a7569"))
 	g.Expect(relName2).To(gomega.HaveLen(53))
 }
+
+// This test checks that the release names of different steps do not collide after shortening
+func TestReleaseNamesAreUnique(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	steps := []app.FlowStep{
+		{Name: "ohandnottoforgettheflowstepnamethatincludesthetemplatenameandotherstuff-1", Template: "template"},
+		{Name: "ohandnottoforgettheflowstepnamethatincludesthetemplatenameandotherstuff-2", Template: "template"},
+	}
+	names, err := utils.GetReleaseNames("my-app", "default", steps)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(names).To(gomega.HaveLen(2))
+	g.Expect(names[steps[0].Name]).NotTo(gomega.Equal(names[steps[1].Name]))
+	g.Expect(names[steps[0].Name]).To(gomega.HaveLen(53))
+}
+
+// This test checks that a release installed for another application is not upgraded
+func TestReleaseOwnedByAnotherApplication(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	blueprint, err := readBlueprint("../../testdata/blueprint.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read blueprint file for test")
+	existing := &release.Release{
+		Info: &release.Info{Status: release.StatusDeployed},
+		Config: map[string]interface{}{
+			"labels": map[string]interface{}{
+				app.ApplicationNameLabel:      "other-app",
+				app.ApplicationNamespaceLabel: blueprint.Labels[app.ApplicationNamespaceLabel],
+			},
+		},
+	}
+	appName, appNamespace, owned := releaseOwner(existing)
+	g.Expect(owned).To(gomega.BeTrue())
+	g.Expect(appName).To(gomega.Equal("other-app"))
+	g.Expect(appNamespace).To(gomega.Equal(blueprint.Labels[app.ApplicationNamespaceLabel]))
+
+	r := &BlueprintReconciler{
+		Name:   "BlueprintTestController",
+		Log:    ctrl.Log.WithName("test-blueprint-controller"),
+		Helmer: helm.NewFake(existing, nil),
+	}
+	step := blueprint.Spec.Flow.Steps[0]
+	releaseName := utils.GetReleaseName(blueprint.Labels[app.ApplicationNameLabel], blueprint.Labels[app.ApplicationNamespaceLabel], step)
+	_, err = r.applyChartResource(r.Log, app.ChartSpec{Name: "chart"}, map[string]interface{}{}, nil, blueprint, releaseName)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+// This test checks that the service of a read module is annotated with the hostname registered by external-dns
+func TestRegisterExternalHostname(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "read-release", Namespace: utils.BlueprintNamespace},
+	}
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, service)
+	r := &BlueprintReconciler{
+		Client: cl,
+		Name:   "BlueprintTestController",
+		Log:    ctrl.Log.WithName("test-blueprint-controller"),
+	}
+	hostname := utils.GenerateModuleExternalHostname("read-release", "data.example.com")
+	// in read-only mode the service is not annotated
+	registered, err := r.registerExternalHostname(context.Background(), utils.BlueprintNamespace, "read-release", hostname, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(registered).To(gomega.BeFalse())
+	registered, err = r.registerExternalHostname(context.Background(), utils.BlueprintNamespace, "read-release", hostname, false)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(registered).To(gomega.BeTrue())
+
+	annotated := &corev1.Service{}
+	g.Expect(cl.Get(context.Background(), client.ObjectKey{Namespace: utils.BlueprintNamespace, Name: "read-release"}, annotated)).To(gomega.Succeed())
+	g.Expect(annotated.Annotations).To(gomega.HaveKeyWithValue(ExternalDNSHostnameAnnotation, "read-release.data.example.com"))
+
+	// a release whose chart does not create a service with the release name is not registered
+	registered, err = r.registerExternalHostname(context.Background(), utils.BlueprintNamespace, "other-release", hostname, false)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(registered).To(gomega.BeFalse())
+}
+
+// This test checks that the labels propagated from the application are passed to the chart with the labels identifying the application
+func TestPropagatedLabels(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	blueprint, err := readBlueprint("../../testdata/blueprint.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read blueprint file for test")
+	r := &BlueprintReconciler{
+		Name:   "BlueprintTestController",
+		Log:    ctrl.Log.WithName("test-blueprint-controller"),
+		Helmer: helm.NewFake(nil, nil),
+	}
+	args := map[string]interface{}{
+		"labels": map[string]interface{}{
+			"team":                   "analytics",
+			app.ApplicationNameLabel: "spoofed",
+		},
+	}
+	_, err = r.applyChartResource(r.Log, app.ChartSpec{Name: "chart", ValuesContract: app.ValuesContractV2}, args, nil, blueprint, "release")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	rel, err := r.Helmer.Status(blueprint.Namespace, "release")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	labels, ok := rel.Config["labels"].(map[string]string)
+	g.Expect(ok).To(gomega.BeTrue())
+	g.Expect(labels).To(gomega.HaveKeyWithValue("team", "analytics"))
+	g.Expect(labels).To(gomega.HaveKeyWithValue(app.ApplicationNameLabel, blueprint.Labels[app.ApplicationNameLabel]))
+}
+
+// This test checks that the workloads of a release are listed in the blueprint status
+func TestWorkloadNames(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	newResource := func(kind string, name string) *unstructured.Unstructured {
+		res := &unstructured.Unstructured{}
+		res.SetKind(kind)
+		res.SetName(name)
+		return res
+	}
+	resources := []*unstructured.Unstructured{
+		newResource("Service", "read-release"),
+		newResource("Deployment", "read-release"),
+		newResource("BatchTransfer", "copy-release"),
+	}
+	g.Expect(workloadNames(resources)).To(gomega.Equal([]string{"Deployment/read-release", "BatchTransfer/copy-release"}))
+	g.Expect(workloadNames(nil)).To(gomega.BeEmpty())
+}
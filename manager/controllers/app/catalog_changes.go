@@ -0,0 +1,79 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sync"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// minCatalogCheckInterval is the minimal interval between two comparisons of the metadata of the datasets of an application
+// with the data catalog, if the catalog check interval is not configured
+const minCatalogCheckInterval = time.Minute
+
+// catalogChecks records when the catalog metadata of the datasets of each application has last been compared,
+// such that the data catalog is not queried by every reconcile of the applications
+type catalogChecks struct {
+	mutex   sync.Mutex
+	checked map[types.NamespacedName]time.Time
+}
+
+// due returns true if the catalog metadata of the application has not been compared for the given interval,
+// in which case the comparison is recorded now. A tenth of the interval is tolerated, for the requeues after the interval.
+func (c *catalogChecks) due(id types.NamespacedName, interval time.Duration, now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if checked, found := c.checked[id]; found && now.Sub(checked) < interval-interval/10 {
+		return false
+	}
+	if c.checked == nil {
+		c.checked = make(map[types.NamespacedName]time.Time)
+	}
+	c.checked[id] = now
+	return true
+}
+
+func (c *catalogChecks) remove(id types.NamespacedName) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.checked, id)
+}
+
+// catalogCheckInterval returns the minimal interval between two comparisons of the metadata of an application
+func catalogCheckInterval() time.Duration {
+	if interval := utils.GetCatalogCheckInterval(); interval > 0 {
+		return interval
+	}
+	return minCatalogCheckInterval
+}
+
+// catalogChanged returns true if the metadata of a dataset in the data catalog differs from the metadata used by the last evaluation
+func (r *M4DApplicationReconciler) catalogChanged(application *app.M4DApplication) (bool, error) {
+	evaluator := r.newEvaluator()
+	for _, dataCtx := range application.Spec.Data {
+		previous, found := application.Status.CatalogHashes[dataCtx.DataSetID]
+		if !found {
+			continue
+		}
+		req := modules.DataInfo{Context: dataCtx.DeepCopy()}
+		if err := evaluator.ConstructDataInfo(&req, application); err != nil {
+			return false, err
+		}
+		current, err := evaluate.CatalogHash(req.DataDetails)
+		if err != nil {
+			return false, err
+		}
+		if current != previous {
+			r.Log.V(0).Info("The catalog metadata of " + dataCtx.DataSetID + " has changed")
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestCatalogChecks checks that the catalog metadata of an application is compared at most once per interval
+func TestCatalogChecks(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	checks := &catalogChecks{}
+	id := types.NamespacedName{Namespace: "default", Name: "notebook"}
+	now := time.Now()
+	g.Expect(checks.due(id, time.Minute, now)).To(gomega.BeTrue())
+	g.Expect(checks.due(id, time.Minute, now.Add(10*time.Second))).To(gomega.BeFalse())
+	// the applications are checked independently
+	g.Expect(checks.due(types.NamespacedName{Namespace: "default", Name: "other"}, time.Minute, now)).To(gomega.BeTrue())
+	// the requeues made after the interval are tolerated to be slightly early
+	g.Expect(checks.due(id, time.Minute, now.Add(58*time.Second))).To(gomega.BeTrue())
+	g.Expect(checks.due(id, time.Minute, now.Add(70*time.Second))).To(gomega.BeFalse())
+	checks.remove(id)
+	g.Expect(checks.due(id, time.Minute, now.Add(70*time.Second))).To(gomega.BeTrue())
+}
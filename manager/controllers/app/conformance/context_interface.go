@@ -0,0 +1,121 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+	This package contains a reusable test suite that every implementation of app.ContextInterface
+	(Plotter, Blueprint or any other orchestration backend) is expected to pass.
+	The suite verifies:
+	- resource reference creation
+	- create/update idempotency
+	- propagation of the observed status of the generated resource
+	- detection of the modifications and the deletion of the generated resource by others
+	- deletion
+*/
+
+package conformance
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	contextapi "github.com/mesh-for-data/mesh-for-data/manager/controllers/app"
+	"github.com/onsi/gomega"
+)
+
+// StatusSetter imitates the backend controller by setting the observed state of the generated resource
+type StatusSetter func(ref *app.ResourceReference, state app.ObservedState) error
+
+// Options hold the input of the conformance suite
+type Options struct {
+	// Owner is the M4DApplication identity used for generating resources
+	Owner *app.ResourceReference
+	// Blueprints are the initial blueprint specifications mapped by cluster
+	Blueprints map[string]app.BlueprintSpec
+	// UpdatedBlueprints are the blueprint specifications used for testing an update
+	UpdatedBlueprints map[string]app.BlueprintSpec
+	// SetStatus updates the status of the generated resource
+	SetStatus StatusSetter
+	// ModifySpec imitates a modification of the spec of the generated resource by others
+	ModifySpec func(ref *app.ResourceReference) error
+}
+
+// RunContextInterfaceTests runs the conformance suite against the given ContextInterface implementation
+func RunContextInterfaceTests(t *testing.T, impl contextapi.ContextInterface, opts Options) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(impl.GetManagedObject()).NotTo(gomega.BeNil(), "the managed object type should be defined")
+
+	// resource reference
+	ref := impl.CreateResourceReference(opts.Owner)
+	g.Expect(ref).NotTo(gomega.BeNil())
+	g.Expect(ref.Name).NotTo(gomega.BeEmpty(), "the generated resource should be named")
+	g.Expect(ref.Kind).NotTo(gomega.BeEmpty(), "the generated resource kind should be set")
+	g.Expect(ref.AppVersion).To(gomega.Equal(opts.Owner.AppVersion), "the generated resource should record the application generation")
+	g.Expect(impl.CreateResourceReference(opts.Owner)).To(gomega.Equal(ref), "the resource reference should be deterministic")
+
+	// non-existing resources
+	g.Expect(impl.ResourceExists(nil)).To(gomega.BeFalse())
+	g.Expect(impl.ResourceExists(ref)).To(gomega.BeFalse())
+	state, err := impl.GetResourceStatus(nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(state).To(gomega.Equal(app.ObservedState{}))
+
+	// creation
+	g.Expect(impl.CreateOrUpdateResource(opts.Owner, ref, opts.Blueprints, nil)).To(gomega.Succeed())
+	g.Expect(impl.ResourceExists(ref)).To(gomega.BeTrue(), "the resource should exist after creation")
+
+	// status propagation
+	ready := app.ObservedState{Ready: true, DataAccessInstructions: "conformance"}
+	g.Expect(opts.SetStatus(ref, ready)).To(gomega.Succeed())
+	state, err = impl.GetResourceStatus(ref)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(state).To(gomega.Equal(ready), "the observed state should be propagated")
+
+	// idempotency: an update with the same specification keeps the resource and its status
+	g.Expect(impl.CreateOrUpdateResource(opts.Owner, ref, opts.Blueprints, nil)).To(gomega.Succeed())
+	g.Expect(impl.ResourceExists(ref)).To(gomega.BeTrue())
+	state, err = impl.GetResourceStatus(ref)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(state).To(gomega.Equal(ready), "a repeated update should not reset the observed state")
+
+	// update
+	g.Expect(impl.CreateOrUpdateResource(opts.Owner, ref, opts.UpdatedBlueprints, nil)).To(gomega.Succeed())
+	g.Expect(impl.ResourceExists(ref)).To(gomega.BeTrue())
+	failure := app.ObservedState{Error: "conformance failure"}
+	g.Expect(opts.SetStatus(ref, failure)).To(gomega.Succeed())
+	state, err = impl.GetResourceStatus(ref)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(state).To(gomega.Equal(failure), "errors should be propagated")
+
+	// drift: the modifications of the resource by others are detected, and repaired by an update
+	drift, err := impl.DetectDrift(ref)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(drift).To(gomega.BeEmpty(), "the resource as written by the implementation should not drift")
+	g.Expect(opts.ModifySpec(ref)).To(gomega.Succeed())
+	drift, err = impl.DetectDrift(ref)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(drift).NotTo(gomega.BeEmpty(), "a modification of the spec should be detected")
+	g.Expect(impl.CreateOrUpdateResource(opts.Owner, ref, opts.UpdatedBlueprints, nil)).To(gomega.Succeed())
+	drift, err = impl.DetectDrift(ref)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(drift).To(gomega.BeEmpty(), "an update should repair the resource")
+
+	// a resource generated for a newer application version does not propagate its status to older references
+	newer := *opts.Owner
+	newer.AppVersion++
+	newerRef := impl.CreateResourceReference(&newer)
+	g.Expect(impl.CreateOrUpdateResource(&newer, newerRef, opts.UpdatedBlueprints, nil)).To(gomega.Succeed())
+	state, err = impl.GetResourceStatus(ref)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(state).To(gomega.Equal(app.ObservedState{}), "the status of a newer generation should not be propagated")
+	state, err = impl.GetResourceStatus(newerRef)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(state).To(gomega.Equal(failure), "the status should be propagated to the generation of the resource")
+
+	// deletion
+	g.Expect(impl.DeleteResource(ref)).To(gomega.Succeed())
+	g.Expect(impl.ResourceExists(ref)).To(gomega.BeFalse(), "the resource should not exist after deletion")
+	drift, err = impl.DetectDrift(ref)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(drift).NotTo(gomega.BeEmpty(), "the deletion of the resource should be detected")
+}
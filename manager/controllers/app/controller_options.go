@@ -0,0 +1,64 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ControllerOptions tunes the throughput of a controller. The zero value keeps the defaults of controller-runtime.
+type ControllerOptions struct {
+	// MaxConcurrentReconciles is the maximum number of requests reconciled concurrently, 1 if not set
+	MaxConcurrentReconciles int
+	// BaseDelay and MaxDelay bound the exponential backoff of the requests that are requeued after a failure.
+	// The default rate limiter of controller-runtime is used if both are not set.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// concurrency returns the maximum number of requests reconciled concurrently
+func (o ControllerOptions) concurrency() int {
+	if o.MaxConcurrentReconciles < 1 {
+		return 1
+	}
+	return o.MaxConcurrentReconciles
+}
+
+// rateLimiter returns the rate limiter of the work queue, or nil for the default one.
+// As in the default one, the backoff of the requests is combined with an overall limit of 10 requests per second
+// with bursts of 100, such that many failing requests do not flood the API server.
+func (o ControllerOptions) rateLimiter() workqueue.RateLimiter {
+	if o.BaseDelay <= 0 && o.MaxDelay <= 0 {
+		return nil
+	}
+	baseDelay, maxDelay := o.BaseDelay, o.MaxDelay
+	// the delays of the default rate limiter of controller-runtime are used for the unset bound
+	if baseDelay <= 0 {
+		baseDelay = 5 * time.Millisecond
+	}
+	if maxDelay <= 0 {
+		maxDelay = 1000 * time.Second
+	}
+	if maxDelay < baseDelay {
+		maxDelay = baseDelay
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+// controllerOptions returns the options of a controller running the given reconciler
+func (o ControllerOptions) controllerOptions(r reconcile.Reconciler) controller.Options {
+	return controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: o.concurrency(),
+		RateLimiter:             o.rateLimiter(),
+	}
+}
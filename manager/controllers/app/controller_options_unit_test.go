@@ -0,0 +1,42 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestControllerOptions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	// the defaults of controller-runtime are kept
+	options := ControllerOptions{}.controllerOptions(nil)
+	g.Expect(options.MaxConcurrentReconciles).To(gomega.Equal(1))
+	g.Expect(options.RateLimiter).To(gomega.BeNil())
+
+	// the requests that fail are requeued with an exponential backoff between the given delays
+	limiter := ControllerOptions{MaxConcurrentReconciles: 4, BaseDelay: time.Second, MaxDelay: 3 * time.Second}.rateLimiter()
+	g.Expect(limiter.When("app")).To(gomega.Equal(time.Second))
+	g.Expect(limiter.When("app")).To(gomega.Equal(2 * time.Second))
+	g.Expect(limiter.When("app")).To(gomega.Equal(3 * time.Second))
+	g.Expect(limiter.When("other")).To(gomega.Equal(time.Second))
+	limiter.Forget("app")
+	g.Expect(limiter.When("app")).To(gomega.Equal(time.Second))
+
+	// the maximum delay is at least the base delay
+	limiter = ControllerOptions{BaseDelay: 10 * time.Second, MaxDelay: time.Second}.rateLimiter()
+	g.Expect(limiter.When("app")).To(gomega.Equal(10 * time.Second))
+	g.Expect(limiter.When("app")).To(gomega.Equal(10 * time.Second))
+
+	// the requests are limited overall once the burst is exhausted, whatever their backoff
+	limiter = ControllerOptions{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}.rateLimiter()
+	for i := 0; i < 100; i++ {
+		g.Expect(limiter.When("app")).To(gomega.Equal(time.Millisecond))
+	}
+	g.Expect(limiter.When("app")).To(gomega.BeNumerically(">", time.Millisecond))
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+)
+
+// copyCleanupPolicy returns the policy applied to the copies when the application is deleted
+func copyCleanupPolicy(application *app.M4DApplication) app.CopyCleanupPolicy {
+	if application.Spec.CopyCleanupPolicy == "" {
+		return app.RetainRegisteredCopies
+	}
+	return application.Spec.CopyCleanupPolicy
+}
+
+// isCopyRetained returns true if the copy of the dataset is kept when the application is deleted
+func isCopyRetained(application *app.M4DApplication, datasetID string) bool {
+	switch copyCleanupPolicy(application) {
+	case app.RetainCopies:
+		return true
+	case app.DeleteCopies:
+		return false
+	default:
+		_, cataloged := application.Status.CatalogedAssets[datasetID]
+		return cataloged
+	}
+}
+
+// retainedCopies maps the datasets whose copies are kept when the application is deleted to the provisioned buckets
+func retainedCopies(application *app.M4DApplication) map[string]string {
+	var retained map[string]string
+	for datasetID, details := range application.Status.ProvisionedStorage {
+		if !isCopyRetained(application, datasetID) {
+			continue
+		}
+		if retained == nil {
+			retained = make(map[string]string)
+		}
+		retained[datasetID] = details.DatasetRef
+	}
+	return retained
+}
+
+// applyCopyCleanupPolicy marks the buckets of the copies that are kept upon the application deletion as persistent,
+// and the others as removable, so that deleting the Dataset resources only removes the latter.
+func (r *M4DApplicationReconciler) applyCopyCleanupPolicy(application *app.M4DApplication) error {
+	for datasetID, details := range application.Status.ProvisionedStorage {
+		retained := isCopyRetained(application, datasetID)
+		if err := storage.ForType(r.Provision, details.StorageType).SetPersistent(getBucketResourceRef(details.DatasetRef), retained); err != nil {
+			return err
+		}
+		if assetID, cataloged := application.Status.CatalogedAssets[datasetID]; cataloged && !retained {
+			r.Log.V(0).Info("Deleting the copy of " + datasetID + " registered as " + assetID + ", the catalog entry is not removed")
+		}
+	}
+	return nil
+}
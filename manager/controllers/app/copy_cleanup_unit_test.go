@@ -0,0 +1,34 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+)
+
+func TestRetainedCopies(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{
+		Status: app.M4DApplicationStatus{
+			ProvisionedStorage: map[string]app.DatasetDetails{
+				"s3/allow-dataset":   {DatasetRef: "bucket-1"},
+				"db2/redact-dataset": {DatasetRef: "bucket-2"},
+			},
+			CatalogedAssets: map[string]string{"s3/allow-dataset": "new-asset"},
+		},
+	}
+	// only the registered copies are retained by default
+	g.Expect(retainedCopies(application)).To(gomega.Equal(map[string]string{"s3/allow-dataset": "bucket-1"}))
+
+	application.Spec.CopyCleanupPolicy = app.RetainCopies
+	g.Expect(retainedCopies(application)).To(gomega.HaveLen(2))
+
+	application.Spec.CopyCleanupPolicy = app.DeleteCopies
+	g.Expect(retainedCopies(application)).To(gomega.BeNil())
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PromotionAnnotation references the M4DCopyPromotion, as <namespace>/<name>, that created the secret of a promoted copy
+const PromotionAnnotation = "m4d.ibm.com/promotion"
+
+// promoteCopies detaches the copies referenced by the M4DCopyPromotion resources of the application from the application.
+// Copies are promoted once they have been registered in the data catalog. The status of the promotions is updated.
+func (r *M4DApplicationReconciler) promoteCopies(ctx context.Context, application *app.M4DApplication) error {
+	var promotions app.M4DCopyPromotionList
+	if err := r.List(ctx, &promotions, client.InNamespace(application.Namespace)); err != nil {
+		return err
+	}
+	for i := range promotions.Items {
+		promotion := &promotions.Items[i]
+		if promotion.Spec.Application != application.Name {
+			continue
+		}
+		observedStatus := promotion.Status.DeepCopy()
+		if !promotion.Status.Promoted {
+			if err := r.promoteCopy(ctx, application, promotion); err != nil {
+				return err
+			}
+		}
+		// the application no longer manages the copy, also if its status could not be updated after the promotion
+		if promotion.Status.Promoted {
+			detachCopy(application, promotion)
+		}
+		if !equality.Semantic.DeepEqual(&promotion.Status, observedStatus) {
+			if err := r.Client.Status().Update(ctx, promotion); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// promoteCopy transfers the ownership of the storage of the copy from the application to the standalone dataset.
+// The credentials of the storage account are copied to a secret of the dataset, and the Dataset resource provisioning
+// the storage is made persistent and no longer references the application.
+func (r *M4DApplicationReconciler) promoteCopy(ctx context.Context, application *app.M4DApplication, promotion *app.M4DCopyPromotion) error {
+	datasetID := promotion.Spec.DataSetID
+	details, found := application.Status.ProvisionedStorage[datasetID]
+	if !found {
+		promotion.Status.Message = "the application has no copy of dataset " + datasetID
+		return nil
+	}
+	assetID, cataloged := application.Status.CatalogedAssets[datasetID]
+	if !cataloged || !application.Status.Ready {
+		promotion.Status.Message = "waiting for the copy of dataset " + datasetID + " to be registered in the data catalog"
+		return nil
+	}
+	secretRef, err := r.copyCredentials(ctx, details, promotion)
+	if err != nil {
+		return err
+	}
+	if err := storage.ForType(r.Provision, details.StorageType).Detach(getBucketResourceRef(details.DatasetRef), secretRef); err != nil {
+		return err
+	}
+	r.Log.V(0).Info("The copy of " + datasetID + " registered as " + assetID + " has been promoted to a standalone dataset")
+	promotion.Status = app.M4DCopyPromotionStatus{
+		Promoted:    true,
+		AssetID:     assetID,
+		DatasetRef:  details.DatasetRef,
+		StorageType: details.StorageType,
+		SecretRef:   secretRef.Name,
+	}
+	return nil
+}
+
+// copyCredentials copies the secret of the storage account holding the copy to a secret of the promoted dataset,
+// named after its Dataset resource, so that the dataset remains accessible when the storage account changes.
+// An existing secret of the same name is only reused if it has been created for the same promotion, and its
+// contents are updated if they differ from the secret of the storage account.
+func (r *M4DApplicationReconciler) copyCredentials(ctx context.Context, details app.DatasetDetails, promotion *app.M4DCopyPromotion) (types.NamespacedName, error) {
+	accountSecret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: details.SecretRef, Namespace: utils.GetSystemNamespace()}, accountSecret); err != nil {
+		return types.NamespacedName{}, err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        details.DatasetRef + "-credentials",
+			Namespace:   utils.GetSystemNamespace(),
+			Annotations: map[string]string{PromotionAnnotation: promotion.Namespace + "/" + promotion.Name},
+		},
+		Type: accountSecret.Type,
+		Data: accountSecret.Data,
+	}
+	err := r.Create(ctx, secret)
+	if err == nil {
+		return client.ObjectKeyFromObject(secret), nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return types.NamespacedName{}, err
+	}
+	existing := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(secret), existing); err != nil {
+		return types.NamespacedName{}, err
+	}
+	if existing.Annotations[PromotionAnnotation] != secret.Annotations[PromotionAnnotation] {
+		return types.NamespacedName{}, errors.Errorf("the secret %s/%s already exists and has not been created for the promotion %s",
+			existing.Namespace, existing.Name, secret.Annotations[PromotionAnnotation])
+	}
+	if !equality.Semantic.DeepEqual(existing.Data, secret.Data) {
+		existing.Data = secret.Data
+		if err := r.Update(ctx, existing); err != nil {
+			return types.NamespacedName{}, err
+		}
+	}
+	return client.ObjectKeyFromObject(existing), nil
+}
+
+// detachCopy removes a promoted copy from the storage provisioned for the application, so that it is neither deleted
+// with the application nor reused to copy the dataset again
+func detachCopy(application *app.M4DApplication, promotion *app.M4DCopyPromotion) {
+	datasetID := promotion.Spec.DataSetID
+	if details, found := application.Status.ProvisionedStorage[datasetID]; found && details.DatasetRef == promotion.Status.DatasetRef {
+		delete(application.Status.ProvisionedStorage, datasetID)
+	}
+	if application.Status.PromotedCopies == nil {
+		application.Status.PromotedCopies = make(map[string]string)
+	}
+	application.Status.PromotedCopies[datasetID] = promotion.Status.DatasetRef
+}
+
+// applicationPromotingCopy maps a copy promotion to the application that made the copy
+func applicationPromotingCopy(a client.Object) []reconcile.Request {
+	promotion, ok := a.(*app.M4DCopyPromotion)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: promotion.Spec.Application, Namespace: promotion.Namespace}}}
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestPromoteCopies checks that a registered copy is detached from the ingest application
+func TestPromoteCopies(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingest", Namespace: "default"},
+		Status: app.M4DApplicationStatus{
+			ProvisionedStorage: map[string]app.DatasetDetails{
+				"s3/allow-dataset": {DatasetRef: "bucket-1", SecretRef: "account-secret"},
+			},
+		},
+	}
+	promotion := &app.M4DCopyPromotion{
+		ObjectMeta: metav1.ObjectMeta{Name: "promote-allow-dataset", Namespace: "default"},
+		Spec:       app.M4DCopyPromotionSpec{Application: "ingest", DataSetID: "s3/allow-dataset"},
+	}
+	accountSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "account-secret", Namespace: utils.GetSystemNamespace()},
+		Data:       map[string][]byte{"accessKeyID": []byte("ak"), "secretAccessKey": []byte("sk")},
+	}
+	provision := storage.NewProvisionTest()
+	bucketRef := getBucketResourceRef("bucket-1")
+	owner := types.NamespacedName{Name: "ingest", Namespace: "default"}
+	g.Expect(provision.CreateDataset(bucketRef, &storage.ProvisionedStorage{Name: "bucket-1"}, &owner)).To(gomega.Succeed())
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), promotion, accountSecret)
+	r := &M4DApplicationReconciler{Client: cl, Log: ctrl.Log.WithName("test"), Provision: provision}
+
+	// the copy is promoted once it has been registered
+	g.Expect(r.promoteCopies(context.Background(), application)).To(gomega.Succeed())
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Name: promotion.Name, Namespace: promotion.Namespace}, promotion)).To(gomega.Succeed())
+	g.Expect(promotion.Status.Promoted).To(gomega.BeFalse())
+	g.Expect(promotion.Status.Message).NotTo(gomega.BeEmpty())
+	g.Expect(application.Status.ProvisionedStorage).To(gomega.HaveKey("s3/allow-dataset"))
+
+	application.Status.Ready = true
+	application.Status.CatalogedAssets = map[string]string{"s3/allow-dataset": "new-asset"}
+	g.Expect(r.promoteCopies(context.Background(), application)).To(gomega.Succeed())
+	// the promotion is fetched into a new object, since the message omitted from the stored promotion is not reset by Get
+	promotion = &app.M4DCopyPromotion{ObjectMeta: metav1.ObjectMeta{Name: promotion.Name, Namespace: promotion.Namespace}}
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Name: promotion.Name, Namespace: promotion.Namespace}, promotion)).To(gomega.Succeed())
+	g.Expect(promotion.Status).To(gomega.Equal(app.M4DCopyPromotionStatus{
+		Promoted:   true,
+		AssetID:    "new-asset",
+		DatasetRef: "bucket-1",
+		SecretRef:  "bucket-1-credentials",
+	}))
+	g.Expect(application.Status.ProvisionedStorage).To(gomega.BeEmpty())
+	g.Expect(application.Status.PromotedCopies).To(gomega.Equal(map[string]string{"s3/allow-dataset": "bucket-1"}))
+
+	// the credentials are copied to the secret of the dataset, which is no longer owned by the application
+	secret := &corev1.Secret{}
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Name: "bucket-1-credentials", Namespace: utils.GetSystemNamespace()}, secret)).To(gomega.Succeed())
+	g.Expect(secret.Data).To(gomega.Equal(accountSecret.Data))
+	statuses, err := provision.ListDatasetStatuses(&owner)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(statuses).To(gomega.BeEmpty())
+
+	// the secret of the same promotion is brought up to date, the secret of another promotion is not taken over
+	details := app.DatasetDetails{DatasetRef: "bucket-1", SecretRef: "account-secret"}
+	secret.Data = map[string][]byte{"accessKeyID": []byte("stale")}
+	g.Expect(cl.Update(context.Background(), secret)).To(gomega.Succeed())
+	_, err = r.copyCredentials(context.Background(), details, promotion)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Name: "bucket-1-credentials", Namespace: utils.GetSystemNamespace()}, secret)).To(gomega.Succeed())
+	g.Expect(secret.Data).To(gomega.Equal(accountSecret.Data))
+	other := promotion.DeepCopy()
+	other.Name = "other-promotion"
+	_, err = r.copyCredentials(context.Background(), details, other)
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("has not been created for the promotion default/other-promotion")))
+}
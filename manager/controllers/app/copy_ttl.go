@@ -0,0 +1,89 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+)
+
+// copyExpiry returns the time at which the copy of the dataset expires, or the zero time if it never expires
+func copyExpiry(application *app.M4DApplication, dataCtx *app.DataContext) time.Time {
+	ttl := dataCtx.Requirements.Copy.TTL
+	if ttl == nil {
+		return time.Time{}
+	}
+	details, found := application.Status.ProvisionedStorage[dataCtx.DataSetID]
+	if !found || details.CopiedAt == nil {
+		return time.Time{}
+	}
+	return details.CopiedAt.Add(ttl.Duration)
+}
+
+// expireCopies deletes the copies whose TTL has expired together with their provisioned storage,
+// and reports them in the status so that they are not made again.
+// It returns true if a copy has been deleted, in which case the modules have to be orchestrated again.
+func (r *M4DApplicationReconciler) expireCopies(application *app.M4DApplication) (bool, error) {
+	// forget the expired copies of the datasets that are no longer requested
+	var expiredCopies []string
+	for _, datasetID := range application.Status.ExpiredCopies {
+		for _, dataCtx := range application.Spec.Data {
+			if dataCtx.DataSetID == datasetID {
+				expiredCopies = append(expiredCopies, datasetID)
+				break
+			}
+		}
+	}
+	application.Status.ExpiredCopies = expiredCopies
+
+	expired := false
+	now := time.Now()
+	for i := range application.Spec.Data {
+		dataCtx := &application.Spec.Data[i]
+		expiry := copyExpiry(application, dataCtx)
+		if expiry.IsZero() || now.Before(expiry) {
+			continue
+		}
+		details := application.Status.ProvisionedStorage[dataCtx.DataSetID]
+		provision := storage.ForType(r.Provision, details.StorageType)
+		// the storage is removed together with the Dataset resource even if the copy has been registered
+		if err := provision.SetPersistent(getBucketResourceRef(details.DatasetRef), false); err != nil {
+			r.Log.V(1).Info("could not mark the storage of an expired copy as removable: " + err.Error())
+		}
+		if err := provision.DeleteDataset(getBucketResourceRef(details.DatasetRef)); err != nil {
+			return expired, err
+		}
+		r.Log.V(0).Info("The copy of " + dataCtx.DataSetID + " has expired and has been deleted")
+		delete(application.Status.ProvisionedStorage, dataCtx.DataSetID)
+		if !evaluate.IsCopyExpired(application, dataCtx.DataSetID) {
+			application.Status.ExpiredCopies = append(application.Status.ExpiredCopies, dataCtx.DataSetID)
+		}
+		expired = true
+	}
+	return expired, nil
+}
+
+// nextCopyExpiry returns the duration until the earliest expiry of the copies, zero if no copy expires
+func nextCopyExpiry(application *app.M4DApplication) time.Duration {
+	var next time.Duration
+	now := time.Now()
+	for i := range application.Spec.Data {
+		expiry := copyExpiry(application, &application.Spec.Data[i])
+		if expiry.IsZero() {
+			continue
+		}
+		interval := expiry.Sub(now)
+		if interval <= 0 {
+			// requeue immediately
+			interval = time.Second
+		}
+		if next == 0 || interval < next {
+			next = interval
+		}
+	}
+	return next
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestExpireCopies(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	provision := storage.NewProvisionTest()
+	owner := &types.NamespacedName{Name: "notebook", Namespace: "default"}
+	for _, name := range []string{"bucket-1", "bucket-2"} {
+		g.Expect(provision.CreateDataset(getBucketResourceRef(name), &storage.ProvisionedStorage{Name: name}, owner)).To(gomega.Succeed())
+	}
+	r := &M4DApplicationReconciler{Log: ctrl.Log.WithName("test"), Provision: provision}
+
+	copiedAt := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	application := &app.M4DApplication{
+		Spec: app.M4DApplicationSpec{
+			Data: []app.DataContext{
+				{
+					DataSetID:    "s3/allow-dataset",
+					Requirements: app.DataRequirements{Copy: app.CopyRequirements{TTL: &metav1.Duration{Duration: time.Hour}}},
+				},
+				{
+					DataSetID:    "db2/redact-dataset",
+					Requirements: app.DataRequirements{Copy: app.CopyRequirements{TTL: &metav1.Duration{Duration: 3 * time.Hour}}},
+				},
+			},
+		},
+		Status: app.M4DApplicationStatus{
+			ProvisionedStorage: map[string]app.DatasetDetails{
+				"s3/allow-dataset":   {DatasetRef: "bucket-1", CopiedAt: &copiedAt},
+				"db2/redact-dataset": {DatasetRef: "bucket-2", CopiedAt: &copiedAt},
+			},
+			ExpiredCopies: []string{"s3/removed-dataset"},
+		},
+	}
+	// the expired copy requires an immediate reconcile
+	next := nextCopyExpiry(application)
+	g.Expect(next).To(gomega.BeNumerically("<=", time.Second))
+
+	// only the copy whose TTL has expired is deleted
+	expired, err := r.expireCopies(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(expired).To(gomega.BeTrue())
+	g.Expect(application.Status.ExpiredCopies).To(gomega.Equal([]string{"s3/allow-dataset"}))
+	g.Expect(application.Status.ProvisionedStorage).NotTo(gomega.HaveKey("s3/allow-dataset"))
+	g.Expect(application.Status.ProvisionedStorage).To(gomega.HaveKey("db2/redact-dataset"))
+	_, err = provision.GetDatasetStatus(getBucketResourceRef("bucket-1"))
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = provision.GetDatasetStatus(getBucketResourceRef("bucket-2"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(evaluate.IsCopyExpired(application, "s3/allow-dataset")).To(gomega.BeTrue())
+	g.Expect(evaluate.IsCopyExpired(application, "db2/redact-dataset")).To(gomega.BeFalse())
+
+	// the next expiry is the one of the remaining copy
+	next = nextCopyExpiry(application)
+	g.Expect(next).To(gomega.BeNumerically(">", 59*time.Minute))
+	g.Expect(next).To(gomega.BeNumerically("<=", time.Hour))
+
+	// nothing is deleted until then
+	expired, err = r.expireCopies(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(expired).To(gomega.BeFalse())
+}
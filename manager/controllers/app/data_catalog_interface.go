@@ -7,17 +7,22 @@ import (
 	"context"
 
 	"encoding/json"
+	"time"
 
+	"emperror.dev/errors"
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
 	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
 	"github.com/mesh-for-data/mesh-for-data/pkg/vault"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// RegisterAsset registers a new asset in the specified catalog
+// RegisterAsset registers a copy of a dataset as a new asset in the specified catalog.
+// The details of the copy include the metadata of the source asset, which is propagated to the new asset
+// by the catalogs supporting the registration.
 // Input arguments:
 // - catalogID: the destination catalog identifier
 // - info: connection and credential details
@@ -30,6 +35,10 @@ func (r *M4DApplicationReconciler) RegisterAsset(catalogID string, info *app.Dat
 	if err != nil {
 		return "", err
 	}
+	// the connection is registered as described by the provisioner of the storage
+	if datasetDetails.DataStore == nil {
+		return "", errors.New("the connection to the provisioned storage " + info.DatasetRef + " is missing")
+	}
 
 	var creds *pb.Credentials
 	if creds, err = SecretToCredentials(r.Client, types.NamespacedName{Name: info.SecretRef, Namespace: utils.GetSystemNamespace()}); err != nil {
@@ -40,12 +49,14 @@ func (r *M4DApplicationReconciler) RegisterAsset(catalogID string, info *app.Dat
 		credentialPath = utils.GetVaultAddress() + vault.PathForReadingKubeSecret(input.Namespace, input.Spec.SecretRef)
 	}
 
+	start := time.Now()
 	response, err := r.DataCatalog.RegisterDatasetInfo(context.Background(), &pb.RegisterAssetRequest{
 		Creds:                creds,
 		DatasetDetails:       datasetDetails,
 		DestinationCatalogId: catalogID,
 		CredentialPath:       credentialPath,
 	})
+	evaluate.ObserveConnectorLatency(evaluate.CatalogConnector, "RegisterDatasetInfo", start)
 	if err != nil {
 		return "", err
 	}
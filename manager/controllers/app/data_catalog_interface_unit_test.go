@@ -0,0 +1,69 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestRegisterAsset checks that a copy is registered with its connection, its credentials and the metadata of the source asset
+func TestRegisterAsset(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "ingest", Namespace: "default"}}
+	accountSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "account-secret", Namespace: utils.GetSystemNamespace()},
+		Data:       map[string][]byte{"accessKeyID": []byte("ak"), "secretAccessKey": []byte("sk")},
+	}
+	catalog := mockup.NewTestCatalog()
+	r := &M4DApplicationReconciler{
+		Client:      fake.NewFakeClientWithScheme(utils.NewScheme(g), accountSecret),
+		Log:         ctrl.Log.WithName("test"),
+		DataCatalog: catalog,
+	}
+	metadata := &pb.DatasetMetadata{
+		DatasetTags:        []string{"PI"},
+		ComponentsMetadata: map[string]*pb.DataComponentMetadata{"nameOrig": {ComponentType: "column", Tags: []string{"PII"}}},
+	}
+	details := &pb.DatasetDetails{
+		Name:       "xxx",
+		DataFormat: "parquet",
+		Geo:        "theshire",
+		DataStore: &pb.DataStore{
+			Type: pb.DataStore_S3,
+			Name: "xxx-copy",
+			S3:   &pb.S3DataStore{Endpoint: "http://minio:9000", Bucket: "bucket-1", ObjectKey: "xxx.parquet"},
+		},
+		Metadata: metadata,
+	}
+	info := &app.DatasetDetails{DatasetRef: "bucket-1", SecretRef: "account-secret", Details: *serde.NewArbitrary(details)}
+
+	assetID, err := r.RegisterAsset("enterprise", info, application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(assetID).To(gomega.Equal("enterprise/xxx-copy"))
+	g.Expect(catalog.Registered).To(gomega.HaveKey(assetID))
+	request := catalog.Registered[assetID]
+	g.Expect(request.GetCreds().GetAccessKey()).To(gomega.Equal("ak"))
+	g.Expect(request.GetCreds().GetSecretKey()).To(gomega.Equal("sk"))
+	g.Expect(request.GetDatasetDetails().GetDataStore().GetS3().GetBucket()).To(gomega.Equal("bucket-1"))
+	g.Expect(request.GetDatasetDetails().GetMetadata().GetDatasetTags()).To(gomega.Equal(metadata.DatasetTags))
+	g.Expect(request.GetDatasetDetails().GetMetadata().GetComponentsMetadata()).To(gomega.HaveKey("nameOrig"))
+
+	// the connection to the copy is required
+	info.Details = *serde.NewArbitrary(&pb.DatasetDetails{Name: "xxx"})
+	_, err = r.RegisterAsset("enterprise", info, application)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// DecisionsKey is the key of the governance decisions in the decisions ConfigMap of a blueprint
+const DecisionsKey = "decisions.json"
+
+// decisionsConfigMapName returns the name of the ConfigMap holding the governance decisions of the blueprint
+func decisionsConfigMapName(blueprint *app.Blueprint) string {
+	return blueprint.Name + "-decisions"
+}
+
+// blueprintDecisions returns the governance actions enforced by the modules of the blueprint, by asset and by flow.
+// The assets of copy steps are identified by the annotations of the steps.
+func blueprintDecisions(blueprint *app.Blueprint) map[string]map[app.ModuleFlow][]serde.Arbitrary {
+	decisions := map[string]map[app.ModuleFlow][]serde.Arbitrary{}
+	add := func(assetID string, flow app.ModuleFlow, actions []serde.Arbitrary) {
+		if decisions[assetID] == nil {
+			decisions[assetID] = map[app.ModuleFlow][]serde.Arbitrary{}
+		}
+		if decisions[assetID][flow] == nil {
+			// assets without actions are listed with an empty list of actions
+			decisions[assetID][flow] = []serde.Arbitrary{}
+		}
+		decisions[assetID][flow] = append(decisions[assetID][flow], actions...)
+	}
+	for i := range blueprint.Spec.Flow.Steps {
+		step := &blueprint.Spec.Flow.Steps[i]
+		if step.Arguments.Copy != nil {
+			if assetID := step.Arguments.Annotations[app.DatasetIDsAnnotation]; assetID != "" {
+				add(assetID, app.Copy, step.Arguments.Copy.Transformations)
+			}
+		}
+		for _, read := range step.Arguments.Read {
+			add(read.AssetID, app.Read, read.Transformations)
+		}
+		for _, write := range step.Arguments.Write {
+			add(write.AssetID, app.Write, write.Transformations)
+		}
+	}
+	return decisions
+}
+
+// applyDecisions creates or updates the ConfigMap holding the governance decisions of the blueprint, which the modules
+// declaring the v6 values contract mount or read at startup. The ConfigMap is updated in place whenever the policies
+// are evaluated again, such that modules watching the mounted decisions reload them without being restarted.
+// The ConfigMap is owned by the blueprint and deleted together with it.
+func (r *BlueprintReconciler) applyDecisions(ctx context.Context, blueprint *app.Blueprint) error {
+	data, err := json.Marshal(blueprintDecisions(blueprint))
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: decisionsConfigMapName(blueprint), Namespace: blueprint.Namespace}}
+	_, err = ctrl.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.Labels = map[string]string{
+			app.ApplicationNamespaceLabel: blueprint.Labels[app.ApplicationNamespaceLabel],
+			app.ApplicationNameLabel:      blueprint.Labels[app.ApplicationNameLabel],
+			app.BlueprintNameLabel:        blueprint.Name,
+		}
+		configMap.Data = map[string]string{DecisionsKey: string(data)}
+		return ctrl.SetControllerReference(blueprint, configMap, r.Scheme)
+	})
+	return err
+}
+
+// setDecisionsValues passes to the module the reference to the decisions ConfigMap of the blueprint
+func setDecisionsValues(args map[string]interface{}, blueprint *app.Blueprint) {
+	SetMapField(args, "decisions", map[string]interface{}{
+		"configMap": decisionsConfigMapName(blueprint),
+		"key":       DecisionsKey,
+	})
+}
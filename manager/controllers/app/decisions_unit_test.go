@@ -0,0 +1,47 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/onsi/gomega"
+)
+
+func TestBlueprintDecisions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	redact := serde.Arbitrary{Data: map[string]interface{}{"name": "redact"}}
+	encrypt := serde.Arbitrary{Data: map[string]interface{}{"name": "encrypt"}}
+	blueprint := &app.Blueprint{Spec: app.BlueprintSpec{Flow: app.DataFlow{Steps: []app.FlowStep{
+		{Name: "copy", Arguments: app.ModuleArguments{
+			Copy:        &app.CopyModuleArgs{Transformations: []serde.Arbitrary{encrypt}},
+			Annotations: map[string]string{app.DatasetIDsAnnotation: "s3/allow-dataset"},
+		}},
+		{Name: "read", Arguments: app.ModuleArguments{Read: []app.ReadModuleArgs{
+			{AssetID: "s3/allow-dataset", Transformations: []serde.Arbitrary{redact}},
+			{AssetID: "s3/other-dataset"},
+		}}},
+		// the asset of a copy step without annotations is unknown
+		{Name: "unannotated", Arguments: app.ModuleArguments{Copy: &app.CopyModuleArgs{}}},
+	}}}}
+
+	decisions := blueprintDecisions(blueprint)
+	g.Expect(decisions).To(gomega.HaveLen(2))
+	g.Expect(decisions["s3/allow-dataset"]).To(gomega.Equal(map[app.ModuleFlow][]serde.Arbitrary{
+		app.Copy: {encrypt},
+		app.Read: {redact},
+	}))
+	g.Expect(decisions["s3/other-dataset"][app.Read]).To(gomega.BeEmpty())
+
+	// the modules receive the reference to the decisions
+	args := map[string]interface{}{}
+	blueprint.Name = "notebook"
+	setDecisionsValues(args, blueprint)
+	g.Expect(args).To(gomega.HaveKeyWithValue("decisions",
+		map[string]interface{}{"configMap": "notebook-decisions", "key": DecisionsKey}))
+}
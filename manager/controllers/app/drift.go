@@ -0,0 +1,38 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sort"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// detectDrift compares the resources generated for the application with their state in the cluster, and describes
+// how they have drifted: the plotter has been deleted or its spec has been modified by others, or the Dataset resources
+// provisioning the storage of the copies have been deleted. The drifts are repaired by a reconcile of the application.
+func (r *M4DApplicationReconciler) detectDrift(applicationContext *app.M4DApplication) []string {
+	var drifts []string
+	if drift, err := r.ResourceInterface.DetectDrift(applicationContext.Status.Generated); err != nil {
+		r.Log.V(0).Info("Could not compare the generated resource with its state in the cluster: " + err.Error())
+	} else if drift != "" {
+		drifts = append(drifts, drift)
+	}
+	datasetIDs := make([]string, 0, len(applicationContext.Status.ProvisionedStorage))
+	for datasetID := range applicationContext.Status.ProvisionedStorage {
+		datasetIDs = append(datasetIDs, datasetID)
+	}
+	sort.Strings(datasetIDs)
+	for _, datasetID := range datasetIDs {
+		details := applicationContext.Status.ProvisionedStorage[datasetID]
+		// other errors, e.g. an unsupported type of storage, are reported when the storage is provisioned
+		_, err := storage.ForType(r.Provision, details.StorageType).GetDatasetStatus(getBucketResourceRef(details.DatasetRef))
+		if apierrors.IsNotFound(err) {
+			drifts = append(drifts, "Dataset "+details.DatasetRef+" provisioning the copy of "+datasetID+" has been deleted")
+		}
+	}
+	return drifts
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDriftDetection(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g))
+	provision := storage.NewProvisionTest()
+	r := &M4DApplicationReconciler{
+		Client:            cl,
+		Log:               ctrl.Log.WithName("test"),
+		Provision:         provision,
+		ResourceInterface: NewPlotterInterface(cl),
+	}
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default", Generation: 1}}
+	owner := &types.NamespacedName{Name: application.Name, Namespace: application.Namespace}
+	g.Expect(provision.CreateDataset(getBucketResourceRef("bucket-1"), &storage.ProvisionedStorage{Name: "bucket-1"}, owner)).To(gomega.Succeed())
+	blueprints := map[string]app.BlueprintSpec{"thegreendragon": {
+		Entrypoint: "notebook",
+		Templates:  []app.ComponentTemplate{{Name: "read-module", Kind: "M4DModule", Chart: app.ChartSpec{Name: "read-chart"}}},
+	}}
+	ownerRef := &app.ResourceReference{Name: application.Name, Namespace: application.Namespace, AppVersion: 1}
+	resourceRef := r.ResourceInterface.CreateResourceReference(ownerRef)
+	g.Expect(r.ResourceInterface.CreateOrUpdateResource(ownerRef, resourceRef, blueprints, nil)).To(gomega.Succeed())
+	g.Expect(resourceRef.SpecDigest).NotTo(gomega.BeEmpty())
+	application.Status.Generated = resourceRef
+	application.Status.ProvisionedStorage = map[string]app.DatasetDetails{"s3/allow-dataset": {DatasetRef: "bucket-1"}}
+
+	// the generated resources are as written by the manager
+	g.Expect(r.detectDrift(application)).To(gomega.BeEmpty())
+
+	// a manual edit of the plotter spec is detected, and repaired by writing the plotter again
+	plotter := &app.Plotter{}
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: resourceRef.Namespace, Name: resourceRef.Name}, plotter)).To(gomega.Succeed())
+	plotter.Spec.Blueprints["thegreendragon"].Templates[0].Chart.Name = "edited-chart"
+	g.Expect(cl.Update(context.Background(), plotter)).To(gomega.Succeed())
+	g.Expect(r.detectDrift(application)).To(gomega.ConsistOf(gomega.ContainSubstring("has been modified")))
+	g.Expect(r.ResourceInterface.CreateOrUpdateResource(ownerRef, resourceRef, blueprints, nil)).To(gomega.Succeed())
+	g.Expect(r.detectDrift(application)).To(gomega.BeEmpty())
+
+	// a plotter recorded by an older manager has no digest, its modifications are not detected
+	recorded := *resourceRef
+	recorded.SpecDigest = ""
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: resourceRef.Namespace, Name: resourceRef.Name}, plotter)).To(gomega.Succeed())
+	plotter.Spec.Blueprints["thegreendragon"].Templates[0].Chart.Name = "edited-chart"
+	g.Expect(cl.Update(context.Background(), plotter)).To(gomega.Succeed())
+	application.Status.Generated = &recorded
+	g.Expect(r.detectDrift(application)).To(gomega.BeEmpty())
+
+	// the deletion of the plotter and of the Dataset resources is detected
+	g.Expect(r.ResourceInterface.DeleteResource(resourceRef)).To(gomega.Succeed())
+	g.Expect(provision.DeleteDataset(getBucketResourceRef("bucket-1"))).To(gomega.Succeed())
+	g.Expect(r.detectDrift(application)).To(gomega.Equal([]string{
+		"Plotter " + resourceRef.Namespace + "/" + resourceRef.Name + " has been deleted",
+		"Dataset bucket-1 provisioning the copy of s3/allow-dataset has been deleted",
+	}))
+}
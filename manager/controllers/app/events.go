@@ -0,0 +1,33 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sort"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+)
+
+// emitStatusEvents exports the events corresponding to the changes of the application status since the observed status
+func emitStatusEvents(emitter events.Emitter, application *app.M4DApplication, observed *app.M4DApplicationStatus) {
+	if emitter == nil {
+		return
+	}
+	if application.Status.Ready && !observed.Ready {
+		evaluate.EmitEvent(emitter, application, events.Event{Type: events.DataPlaneReady})
+	}
+	revoked := make([]string, 0, len(application.Status.RevokedDatasets))
+	for datasetID := range application.Status.RevokedDatasets {
+		if _, found := observed.RevokedDatasets[datasetID]; !found {
+			revoked = append(revoked, datasetID)
+		}
+	}
+	sort.Strings(revoked)
+	for _, datasetID := range revoked {
+		evaluate.EmitEvent(emitter, application, events.Event{Type: events.DatasetRevoked, DatasetID: datasetID,
+			Reason: application.Status.RevokedDatasets[datasetID]})
+	}
+}
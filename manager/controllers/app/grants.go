@@ -0,0 +1,84 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"strings"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// grantedCopiesChanged returns true if a copy read by the application is no longer granted, or has been replaced
+// by the application that made it. The application is then evaluated again to make its own copy.
+func (r *M4DApplicationReconciler) grantedCopiesChanged(application *app.M4DApplication) bool {
+	if len(application.Status.GrantedCopies) == 0 {
+		return false
+	}
+	var grantList app.M4DGrantList
+	if err := r.List(context.Background(), &grantList); err != nil {
+		r.Log.V(0).Info("Error while listing grants: " + err.Error())
+		return false
+	}
+	for datasetID, grantedCopy := range application.Status.GrantedCopies {
+		granted := false
+		for _, grant := range grantList.Items {
+			if grant.Spec.Grantee == application.Namespace && grant.Spec.DataSetID == datasetID &&
+				grant.Namespace+"/"+grant.Spec.Application == grantedCopy.Application {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			return true
+		}
+		granter := strings.SplitN(grantedCopy.Application, "/", 2)
+		if len(granter) != 2 {
+			return true
+		}
+		details, _, found := evaluate.CopyOf(r.Client, types.NamespacedName{Namespace: granter[0], Name: granter[1]}, datasetID)
+		if !found || details.DatasetRef != grantedCopy.DatasetRef {
+			return true
+		}
+	}
+	return false
+}
+
+// applicationsReadingGrantedCopies maps a grant, or an application that made a copy, to the applications reading
+// the copies shared by the grant or made by the application
+func (r *M4DApplicationReconciler) applicationsReadingGrantedCopies(a client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	listOptions := []client.ListOption{}
+	var granter string
+	switch obj := a.(type) {
+	case *app.M4DGrant:
+		granter = obj.Namespace + "/" + obj.Spec.Application
+		listOptions = append(listOptions, client.InNamespace(obj.Spec.Grantee))
+	case *app.M4DApplication:
+		if len(obj.Status.ProvisionedStorage) == 0 {
+			return requests
+		}
+		granter = obj.Namespace + "/" + obj.Name
+	default:
+		return requests
+	}
+	var applications app.M4DApplicationList
+	if err := r.List(context.Background(), &applications, listOptions...); err != nil {
+		r.Log.V(0).Info("Error while listing applications: " + err.Error())
+		return requests
+	}
+	for _, application := range applications.Items {
+		for _, grantedCopy := range application.Status.GrantedCopies {
+			if grantedCopy.Application == granter {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&application)})
+				break
+			}
+		}
+	}
+	return requests
+}
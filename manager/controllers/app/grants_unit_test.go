@@ -0,0 +1,84 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// This test checks that an application reads the copy shared by another application instead of making its own copy,
+// and that the copy is made again once the grant is deleted
+func TestGrantedCopy(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	const datasetID = "s3-csv/allow-dataset"
+
+	// the application that made the copy
+	granter := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/m4dcopyapp-csv.yaml", granter)).NotTo(gomega.HaveOccurred())
+	granter.Spec.Data[0].DataSetID = datasetID
+	details := serde.NewArbitrary(&pb.DatasetDetails{
+		Name:       "small.csv",
+		DataFormat: "csv",
+		DataStore: &pb.DataStore{
+			Type: pb.DataStore_S3,
+			S3:   &pb.S3DataStore{Endpoint: "s3.eu.cloud-object-storage.appdomain.cloud", Bucket: "notebook-default-copy", ObjectKey: "small.csv"},
+		},
+	})
+	granter.Status = app.M4DApplicationStatus{
+		Ready: true,
+		ProvisionedStorage: map[string]app.DatasetDetails{
+			datasetID: {DatasetRef: "notebook-default-copy", SecretRef: "credentials-theshire", Details: *details},
+		},
+	}
+	grant := &app.M4DGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "share-small-csv", Namespace: "default"},
+		Spec:       app.M4DGrantSpec{Application: "notebook", DataSetID: datasetID, Grantee: "analytics"},
+	}
+	// the application reading the copy
+	grantee := granter.DeepCopy()
+	grantee.Namespace = "analytics"
+	grantee.Status = app.M4DApplicationStatus{}
+
+	readModule := &app.M4DModule{}
+	copyModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/implicit-copy-batch-module-csv.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", readModule)).NotTo(gomega.HaveOccurred())
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, granter, grantee, grant, readModule, copyModule)
+	r := createTestM4DApplicationController(cl, s)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: grantee.Name, Namespace: grantee.Namespace}}
+	_, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, grantee)).To(gomega.Succeed())
+	g.Expect(grantee.Status.ProvisionedStorage).To(gomega.BeEmpty())
+	g.Expect(grantee.Status.GrantedCopies).To(gomega.HaveKeyWithValue(datasetID,
+		app.GrantedCopy{Application: "default/notebook", DatasetRef: "notebook-default-copy"}))
+	plotter := &app.Plotter{}
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Name: "notebook-analytics", Namespace: "m4d-system"}, plotter)).To(gomega.Succeed())
+	steps := plotter.Spec.Blueprints["thegreendragon"].Flow.Steps
+	g.Expect(steps).To(gomega.HaveLen(1))
+	source := &pb.DataStore{}
+	g.Expect(steps[0].Arguments.Read[0].Source.Connection.Into(source)).To(gomega.Succeed())
+	g.Expect(source.S3.Bucket).To(gomega.Equal("notebook-default-copy"))
+
+	// the grantee is reconciled when the grant is deleted, and copies the dataset again
+	g.Expect(r.applicationsReadingGrantedCopies(grant)).To(gomega.ConsistOf(req))
+	g.Expect(r.grantedCopiesChanged(grantee)).To(gomega.BeFalse())
+	g.Expect(cl.Delete(context.Background(), grant)).To(gomega.Succeed())
+	g.Expect(r.grantedCopiesChanged(grantee)).To(gomega.BeTrue())
+}
@@ -0,0 +1,223 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// inPlacePolicyName returns the name of the network policy restricting the egress of the workload of an application
+func inPlacePolicyName(application *app.M4DApplication) string {
+	return utils.K8sConformName(application.Name + "-in-place")
+}
+
+// reconcileInPlacePolicy creates or updates the network policy restricting the egress of the workload to the sources
+// of the datasets accessed in place, and deletes it once no dataset is accessed in place.
+// The policy is owned by the application, such that it is deleted together with the application.
+func (r *M4DApplicationReconciler) reconcileInPlacePolicy(application *app.M4DApplication) error {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: inPlacePolicyName(application), Namespace: application.Namespace}
+	policy := &networkingv1.NetworkPolicy{}
+	if err := r.Get(ctx, key, policy); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		policy = nil
+	}
+	if policy != nil && !metav1.IsControlledBy(policy, application) {
+		return errors.Errorf("the network policy %s exists and is not owned by the application", key.Name)
+	}
+	if len(application.Status.DirectAccess) == 0 {
+		if policy == nil {
+			return nil
+		}
+		return client.IgnoreNotFound(r.Delete(ctx, policy))
+	}
+	spec, err := inPlacePolicySpec(application)
+	if err != nil {
+		return err
+	}
+	if policy != nil {
+		if equality.Semantic.DeepEqual(policy.Spec, *spec) {
+			return nil
+		}
+		policy.Spec = *spec
+		return r.Update(ctx, policy)
+	}
+	policy = &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels: map[string]string{
+				app.ApplicationNameLabel:      application.Name,
+				app.ApplicationNamespaceLabel: application.Namespace,
+			},
+		},
+		Spec: *spec,
+	}
+	if err := ctrl.SetControllerReference(application, policy, r.Scheme); err != nil {
+		return err
+	}
+	return r.Create(ctx, policy)
+}
+
+// inPlacePolicySpec returns the spec of the network policy of the workload of an application accessing data in place.
+// The workload may still reach the pods of the cluster, e.g. the modules serving its other datasets, and the DNS servers,
+// while its egress outside of the cluster is restricted to the addresses of the sources, as resolved when the application is reconciled.
+func inPlacePolicySpec(application *app.M4DApplication) (*networkingv1.NetworkPolicySpec, error) {
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	dns := intstr.FromInt(53)
+	spec := &networkingv1.NetworkPolicySpec{
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		Egress: []networkingv1.NetworkPolicyEgressRule{
+			{To: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}},
+			{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &dns}, {Protocol: &tcp, Port: &dns}}},
+		},
+	}
+	application.Spec.Selector.WorkloadSelector.DeepCopyInto(&spec.PodSelector)
+	datasets := make([]string, 0, len(application.Status.DirectAccess))
+	for datasetID := range application.Status.DirectAccess {
+		datasets = append(datasets, datasetID)
+	}
+	sort.Strings(datasets)
+	for _, datasetID := range datasets {
+		connection := application.Status.DirectAccess[datasetID].Connection
+		var details map[string]interface{}
+		if err := connection.Into(&details); err != nil {
+			return nil, errors.WithMessagef(err, "could not read the connection of %s", datasetID)
+		}
+		endpoints, err := sourceEndpoints(details)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not read the connection of %s", datasetID)
+		}
+		if len(endpoints) == 0 {
+			return nil, errors.Errorf("the connection of %s does not include the address of its source", datasetID)
+		}
+		for _, endpoint := range endpoints {
+			rule, err := endpoint.egressRule()
+			if err != nil {
+				return nil, err
+			}
+			spec.Egress = append(spec.Egress, rule)
+		}
+	}
+	return spec, nil
+}
+
+// endpointKeys are the keys of the connection details holding the addresses of the sources,
+// e.g. the endpoint of S3 buckets, the URL of databases and the bootstrap servers of Kafka
+var endpointKeys = map[string]bool{"endpoint": true, "url": true, "bootstrap_servers": true, "host": true}
+
+// defaultPorts are the ports of the sources addressed by URLs without ports
+var defaultPorts = map[string]int{"http": 80, "https": 443}
+
+// sourceEndpoint is the address of the source of a dataset, any port of the host may be accessed if the port is not known
+type sourceEndpoint struct {
+	host string
+	port int
+}
+
+// sourceEndpoints returns the addresses found in the connection details, which are nested by the type of the data store
+func sourceEndpoints(details map[string]interface{}) ([]sourceEndpoint, error) {
+	port := 0
+	if value, found := details["port"]; found {
+		port, _ = strconv.Atoi(fmt.Sprint(value))
+	}
+	keys := make([]string, 0, len(details))
+	for key := range details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var endpoints []sourceEndpoint
+	for _, key := range keys {
+		switch value := details[key].(type) {
+		case map[string]interface{}:
+			nested, err := sourceEndpoints(value)
+			if err != nil {
+				return nil, err
+			}
+			endpoints = append(endpoints, nested...)
+		case string:
+			if !endpointKeys[key] || value == "" {
+				continue
+			}
+			for _, address := range strings.Split(value, ",") {
+				endpoint, err := parseEndpoint(strings.TrimSpace(address), port)
+				if err != nil {
+					return nil, err
+				}
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+// parseEndpoint parses a URL, e.g. jdbc:postgresql://postgres.example.com:5432/sales, or a host with an optional port
+func parseEndpoint(address string, port int) (sourceEndpoint, error) {
+	address = strings.TrimPrefix(address, "jdbc:")
+	if strings.Contains(address, "://") {
+		parsed, err := url.Parse(address)
+		if err != nil || parsed.Hostname() == "" {
+			return sourceEndpoint{}, errors.Errorf("invalid source address %s", address)
+		}
+		if parsed.Port() != "" {
+			port, _ = strconv.Atoi(parsed.Port())
+		} else if port == 0 {
+			port = defaultPorts[parsed.Scheme]
+		}
+		return sourceEndpoint{host: parsed.Hostname(), port: port}, nil
+	}
+	if host, hostPort, err := net.SplitHostPort(address); err == nil {
+		port, _ = strconv.Atoi(hostPort)
+		return sourceEndpoint{host: host, port: port}, nil
+	}
+	return sourceEndpoint{host: address, port: port}, nil
+}
+
+// egressRule resolves the host of the source and returns the rule allowing the egress to its addresses
+func (e sourceEndpoint) egressRule() (networkingv1.NetworkPolicyEgressRule, error) {
+	rule := networkingv1.NetworkPolicyEgressRule{}
+	ips, err := net.LookupIP(e.host)
+	if err != nil {
+		return rule, errors.Wrapf(err, "could not resolve the source %s", e.host)
+	}
+	cidrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			cidrs = append(cidrs, ip.String()+"/32")
+		} else {
+			cidrs = append(cidrs, ip.String()+"/128")
+		}
+	}
+	sort.Strings(cidrs)
+	for _, cidr := range cidrs {
+		rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+	if e.port > 0 {
+		tcp := corev1.ProtocolTCP
+		port := intstr.FromInt(e.port)
+		rule.Ports = []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port}}
+	}
+	return rule, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestInPlacePolicy checks that the egress of the workload is restricted to the sources of the datasets accessed in place,
+// and that the network policy is deleted once no dataset is accessed in place.
+func TestInPlacePolicy(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Status.DirectAccess = map[string]app.DirectAccessDetails{
+		"db2/allow-dataset": {Connection: *serde.NewArbitrary(&pb.DataStore{
+			Type: pb.DataStore_DB2,
+			Db2:  &pb.Db2DataStore{Url: "10.0.0.1", Port: "50000", Table: "SALES"},
+		})},
+		"jdbc/allow-dataset": {Connection: *serde.NewArbitrary(&pb.DataStore{
+			Type: pb.DataStore_JDBC,
+			Jdbc: &pb.JdbcDataStore{Url: "jdbc:postgresql://10.0.0.2:5432/sales", Table: "SALES"},
+		})},
+	}
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, application)
+	r := &M4DApplicationReconciler{Client: cl, Log: ctrl.Log.WithName("test"), Scheme: s}
+
+	g.Expect(r.reconcileInPlacePolicy(application)).To(gomega.Succeed())
+	policy := &networkingv1.NetworkPolicy{}
+	key := types.NamespacedName{Name: "read-test-in-place", Namespace: application.Namespace}
+	g.Expect(cl.Get(context.Background(), key, policy)).To(gomega.Succeed())
+	g.Expect(metav1.IsControlledBy(policy, application)).To(gomega.BeTrue())
+	g.Expect(policy.Spec.PodSelector).To(gomega.Equal(application.Spec.Selector.WorkloadSelector))
+	g.Expect(policy.Spec.PolicyTypes).To(gomega.ConsistOf(networkingv1.PolicyTypeEgress))
+	// the pods of the cluster, DNS and the two sources
+	g.Expect(policy.Spec.Egress).To(gomega.HaveLen(4))
+	g.Expect(policy.Spec.Egress[2].To[0].IPBlock.CIDR).To(gomega.Equal("10.0.0.1/32"))
+	g.Expect(policy.Spec.Egress[2].Ports[0].Port.IntValue()).To(gomega.Equal(50000))
+	g.Expect(policy.Spec.Egress[3].To[0].IPBlock.CIDR).To(gomega.Equal("10.0.0.2/32"))
+	g.Expect(policy.Spec.Egress[3].Ports[0].Port.IntValue()).To(gomega.Equal(5432))
+
+	// the policy follows the sources
+	delete(application.Status.DirectAccess, "jdbc/allow-dataset")
+	g.Expect(r.reconcileInPlacePolicy(application)).To(gomega.Succeed())
+	g.Expect(cl.Get(context.Background(), key, policy)).To(gomega.Succeed())
+	g.Expect(policy.Spec.Egress).To(gomega.HaveLen(3))
+
+	// the policy is deleted once no dataset is accessed in place
+	application.Status.DirectAccess = nil
+	g.Expect(r.reconcileInPlacePolicy(application)).To(gomega.Succeed())
+	g.Expect(apierrors.IsNotFound(cl.Get(context.Background(), key, policy))).To(gomega.BeTrue())
+	g.Expect(r.reconcileInPlacePolicy(application)).To(gomega.Succeed())
+}
+
+// TestSourceEndpoints checks that the addresses of the sources are found in the connection details of the data stores
+func TestSourceEndpoints(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	endpoints, err := sourceEndpoints(map[string]interface{}{
+		"kafka": map[string]interface{}{"bootstrap_servers": "10.0.0.1:9093, 10.0.0.2:9093", "topic_name": "sales"},
+		"s3":    map[string]interface{}{"endpoint": "https://10.0.0.3", "bucket": "sales"},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(endpoints).To(gomega.Equal([]sourceEndpoint{
+		{host: "10.0.0.1", port: 9093}, {host: "10.0.0.2", port: 9093}, {host: "10.0.0.3", port: 443},
+	}))
+	_, err = sourceEndpoints(map[string]interface{}{"s3": map[string]interface{}{"endpoint": "https://:443"}})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InventoryPath is the path of the manager endpoint that serves the inventory of the control plane
+const InventoryPath = "/inventory"
+
+// Inventory summarizes the resources of the control plane, as needed by a dashboard or a command line status
+type Inventory struct {
+	Modules         []ModuleSummary         `json:"modules"`
+	StorageAccounts []StorageAccountSummary `json:"storageAccounts"`
+	Clusters        []ClusterSummary        `json:"clusters"`
+	// Applications is the number of applications in each state: ready, denied, error or pending
+	Applications map[string]int `json:"applications"`
+}
+
+// ModuleSummary describes an installed module
+type ModuleSummary struct {
+	Name      string           `json:"name"`
+	Namespace string           `json:"namespace"`
+	Flows     []app.ModuleFlow `json:"flows"`
+	Chart     string           `json:"chart"`
+}
+
+// StorageAccountSummary describes a storage account and whether its credentials are valid
+type StorageAccountSummary struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type,omitempty"`
+	Endpoint string   `json:"endpoint"`
+	Regions  []string `json:"regions"`
+	Healthy  bool     `json:"healthy"`
+	Message  string   `json:"message,omitempty"`
+}
+
+// ClusterSummary describes a cluster in which modules are deployed
+type ClusterSummary struct {
+	Name   string `json:"name"`
+	Region string `json:"region,omitempty"`
+	Zone   string `json:"zone,omitempty"`
+}
+
+// InventoryHandler serves the inventory of the control plane.
+// The resources are listed from the cache of the manager, so that serving the inventory does not load the API server.
+type InventoryHandler struct {
+	Client        client.Reader
+	ClusterLister multicluster.ClusterLister
+	Log           logr.Logger
+}
+
+// NewInventoryHandler creates a new handler of the inventory endpoint
+func NewInventoryHandler(c client.Reader, clusterLister multicluster.ClusterLister, log logr.Logger) *InventoryHandler {
+	return &InventoryHandler{Client: c, ClusterLister: clusterLister, Log: log}
+}
+
+func (h *InventoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	inventory, err := h.Inventory(r.Context())
+	if err != nil {
+		h.Log.Error(err, "unable to build the inventory")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(inventory); err != nil {
+		h.Log.Error(err, "unable to write the inventory")
+	}
+}
+
+// Inventory lists the modules, storage accounts, clusters and applications
+func (h *InventoryHandler) Inventory(ctx context.Context) (*Inventory, error) {
+	inventory := &Inventory{
+		Modules:         []ModuleSummary{},
+		StorageAccounts: []StorageAccountSummary{},
+		Clusters:        []ClusterSummary{},
+		Applications:    map[string]int{readyState: 0, deniedState: 0, errorState: 0, pendingState: 0},
+	}
+
+	var modules app.M4DModuleList
+	if err := h.Client.List(ctx, &modules); err != nil {
+		return nil, err
+	}
+	for _, module := range modules.Items {
+		inventory.Modules = append(inventory.Modules, ModuleSummary{
+			Name:      module.Name,
+			Namespace: module.Namespace,
+			Flows:     module.Spec.Flows,
+			Chart:     module.Spec.Chart.Name,
+		})
+	}
+
+	var accounts app.M4DStorageAccountList
+	if err := h.Client.List(ctx, &accounts); err != nil {
+		return nil, err
+	}
+	for _, account := range accounts.Items {
+		message := account.InvalidCredentials()
+		inventory.StorageAccounts = append(inventory.StorageAccounts, StorageAccountSummary{
+			Name:     account.Name,
+			Type:     account.Spec.Type,
+			Endpoint: account.Spec.Endpoint,
+			Regions:  account.Spec.Regions,
+			Healthy:  message == "",
+			Message:  message,
+		})
+	}
+
+	clusters, err := h.ClusterLister.GetClusters()
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		inventory.Clusters = append(inventory.Clusters, ClusterSummary{
+			Name:   cluster.Name,
+			Region: cluster.Metadata.Region,
+			Zone:   cluster.Metadata.Zone,
+		})
+	}
+
+	var applications app.M4DApplicationList
+	if err := h.Client.List(ctx, &applications); err != nil {
+		return nil, err
+	}
+	for i := range applications.Items {
+		inventory.Applications[applicationState(&applications.Items[i])]++
+	}
+	return inventory, nil
+}
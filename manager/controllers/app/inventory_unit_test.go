@@ -0,0 +1,57 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestInventory checks the summary of the modules, storage accounts, clusters and applications served by the manager
+func TestInventory(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	account := &app.M4DStorageAccount{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/account-theshire.yaml", account)).NotTo(gomega.HaveOccurred())
+	account.Status.Conditions = []app.Condition{{Type: app.CredentialsValidCondition, Status: corev1.ConditionFalse, Message: "missing secret"}}
+	ready := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/m4dcopyapp-csv.yaml", ready)).NotTo(gomega.HaveOccurred())
+	ready.Status.Ready = true
+	pending := ready.DeepCopy()
+	pending.Name = "pending"
+	pending.Status = app.M4DApplicationStatus{}
+
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), readModule, account, ready, pending)
+	handler := NewInventoryHandler(cl, &mockup.ClusterLister{}, ctrl.Log.WithName("inventory"))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, InventoryPath, nil))
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+
+	inventory := &Inventory{}
+	g.Expect(json.Unmarshal(recorder.Body.Bytes(), inventory)).To(gomega.Succeed())
+	g.Expect(inventory.Modules).To(gomega.HaveLen(1))
+	g.Expect(inventory.Modules[0].Name).To(gomega.Equal(readModule.Name))
+	g.Expect(inventory.StorageAccounts).To(gomega.ConsistOf(StorageAccountSummary{
+		Name:     account.Name,
+		Endpoint: account.Spec.Endpoint,
+		Regions:  account.Spec.Regions,
+		Healthy:  false,
+		Message:  "missing secret",
+	}))
+	g.Expect(inventory.Clusters).To(gomega.HaveLen(2))
+	g.Expect(inventory.Applications).To(gomega.Equal(map[string]int{readyState: 1, deniedState: 0, errorState: 0, pendingState: 1}))
+}
@@ -5,59 +5,72 @@ package app
 
 import (
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Helper functions to manage conditions
 
-func resetConditions(application *app.M4DApplication) {
-	application.Status.Conditions = make([]app.Condition, 2)
-	application.Status.Conditions[app.ErrorConditionIndex] = app.Condition{Type: app.ErrorCondition, Status: corev1.ConditionFalse}
-	application.Status.Conditions[app.FailureConditionIndex] = app.Condition{Type: app.FailureCondition, Status: corev1.ConditionFalse}
-}
-
-func setCondition(application *app.M4DApplication, assetID string, msg string, fatalError bool) {
-	if len(application.Status.Conditions) == 0 {
-		resetConditions(application)
-	}
-	errMsg := "An error was received"
-	if assetID != "" {
-		errMsg += " for asset " + assetID + " . "
+// setReadyCondition reports the ready field of the status in the ready condition before the status is updated.
+// The transition times of the conditions whose status is the same as in the observed status are kept.
+func setReadyCondition(application *app.M4DApplication, observed *app.M4DApplicationStatus) {
+	switch {
+	case application.Status.Ready:
+		evaluate.SetStatusCondition(application, app.ReadyCondition, metav1.ConditionTrue, app.OrchestratedReason, "")
+	case evaluate.HasError(application):
+		evaluate.SetStatusCondition(application, app.ReadyCondition, metav1.ConditionFalse, app.FailedReason, "")
+	default:
+		evaluate.SetStatusCondition(application, app.ReadyCondition, metav1.ConditionFalse, app.PendingReason, "")
 	}
-	if !fatalError {
-		errMsg += "If the error persists, please contact an operator.\n"
+	for i := range application.Status.Conditions {
+		condition := &application.Status.Conditions[i]
+		previous := meta.FindStatusCondition(observed.Conditions, condition.Type)
+		if previous != nil && previous.Status == condition.Status && !previous.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = previous.LastTransitionTime
+		}
 	}
-	errMsg += "Error description: " + msg + "\n"
-	var ind int64
-	if fatalError {
-		ind = app.FailureConditionIndex
-	} else {
-		ind = app.ErrorConditionIndex
-	}
-	application.Status.Conditions[ind].Status = corev1.ConditionTrue
-	application.Status.Conditions[ind].Message += errMsg
 }
 
-func hasError(application *app.M4DApplication) bool {
-	// check if the conditions have been initialized
-	if len(application.Status.Conditions) == 0 {
-		return false
-	}
-	return (application.Status.Conditions[app.ErrorConditionIndex].Status == corev1.ConditionTrue ||
-		application.Status.Conditions[app.FailureConditionIndex].Status == corev1.ConditionTrue)
+// setStorageProvisioningCondition reports that the storage for the copies is not provisioned yet, with the reason
+// ProvisioningInProgress or ProvisioningFailed
+func setStorageProvisioningCondition(application *app.M4DApplication, reason string, msg string) {
+	evaluate.SetStatusCondition(application, app.ProvisionedCondition, metav1.ConditionFalse, reason, msg)
 }
 
-func getErrorMessages(application *app.M4DApplication) string {
-	var errMsg string
-	// check if the conditions have been initialized
-	if len(application.Status.Conditions) == 0 {
-		return errMsg
-	}
-	if application.Status.Conditions[app.ErrorConditionIndex].Status == corev1.ConditionTrue {
-		errMsg += application.Status.Conditions[app.ErrorConditionIndex].Message
-	}
-	if application.Status.Conditions[app.FailureConditionIndex].Status == corev1.ConditionTrue {
-		errMsg += application.Status.Conditions[app.FailureConditionIndex].Message
-	}
-	return errMsg
+// isProvisioningStorage returns true if the application waits for the provisioning of storage
+func isProvisioningStorage(application *app.M4DApplication) bool {
+	return meta.IsStatusConditionFalse(application.Status.Conditions, app.ProvisionedCondition)
+}
+
+// setPolicyManagerUnavailableCondition reports that the governance policies could not be evaluated, with the reason
+// FailClosed or FailOpen
+func setPolicyManagerUnavailableCondition(application *app.M4DApplication, reason string, msg string) {
+	evaluate.SetStatusCondition(application, app.PolicyManagerUnavailableCondition, metav1.ConditionTrue, reason, msg)
+}
+
+// isPolicyManagerUnavailable returns true if the status reports that the policies could not be evaluated in the last reconcile
+func isPolicyManagerUnavailable(status *app.M4DApplicationStatus) bool {
+	return meta.IsStatusConditionTrue(status.Conditions, app.PolicyManagerUnavailableCondition)
+}
+
+// setReadOnlyModeCondition reports that the changes of the application are pending while the manager is in read-only mode.
+// The condition is dropped with the next reset of the conditions, once the changes are applied.
+func setReadOnlyModeCondition(application *app.M4DApplication) {
+	evaluate.SetStatusCondition(application, app.ReadOnlyModeCondition, metav1.ConditionTrue, app.ChangesPendingReason, app.ReadOnlyMode)
+}
+
+// setRetriesExhaustedCondition marks the failure of the application as terminal, since the orchestration of
+// the modules has been given up according to the retry policy of the application
+func setRetriesExhaustedCondition(application *app.M4DApplication) {
+	evaluate.SetCondition(application, "", app.RetriesExhausted, true)
+	condition := meta.FindStatusCondition(application.Status.Conditions, app.DeniedCondition)
+	evaluate.SetStatusCondition(application, app.DeniedCondition, metav1.ConditionTrue, app.RetriesExhaustedReason, condition.Message)
+}
+
+// retriesExhausted returns true if the application is in a terminal failure and should not be reconciled
+// until its spec changes
+func retriesExhausted(application *app.M4DApplication) bool {
+	condition := meta.FindStatusCondition(application.Status.Conditions, app.DeniedCondition)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.Reason == app.RetriesExhaustedReason
 }
@@ -0,0 +1,43 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadyCondition(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default", Generation: 3}}
+	evaluate.ResetConditions(application)
+	evaluate.SetCondition(application, "s3/deny-dataset", app.ReadAccessDenied, true)
+	evaluate.SetCondition(application, "s3/allow-dataset", "connection refused", false)
+	setReadyCondition(application, &app.M4DApplicationStatus{})
+	ready := meta.FindStatusCondition(application.Status.Conditions, app.ReadyCondition)
+	g.Expect(ready.Status).To(gomega.Equal(metav1.ConditionFalse))
+	g.Expect(ready.Reason).To(gomega.Equal(app.FailedReason))
+
+	// the transition times are kept if the status of the conditions is unchanged since the observed status
+	observed := application.Status.DeepCopy()
+	transitionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	for i := range observed.Conditions {
+		observed.Conditions[i].LastTransitionTime = transitionTime
+	}
+	evaluate.ResetConditions(application)
+	evaluate.SetCondition(application, "s3/deny-dataset", app.ReadAccessDenied, true)
+	application.Status.Ready = true
+	setReadyCondition(application, observed)
+	g.Expect(meta.FindStatusCondition(application.Status.Conditions, app.DeniedCondition).LastTransitionTime).To(gomega.Equal(transitionTime))
+	g.Expect(meta.FindStatusCondition(application.Status.Conditions, app.ErrorCondition).LastTransitionTime).NotTo(gomega.Equal(transitionTime))
+	g.Expect(meta.IsStatusConditionTrue(application.Status.Conditions, app.ReadyCondition)).To(gomega.BeTrue())
+}
@@ -6,31 +6,39 @@ package app
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
-	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
 
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
-	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/audit"
+	"github.com/mesh-for-data/mesh-for-data/pkg/capabilities"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
 	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
 	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
 	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
-	"github.com/mesh-for-data/mesh-for-data/pkg/vault"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -47,6 +55,38 @@ type M4DApplicationReconciler struct {
 	ResourceInterface ContextInterface
 	ClusterManager    multicluster.ClusterLister
 	Provision         storage.ProvisionInterface
+	// ActionTaxonomy defines the actions that may be returned by the policy manager and declared by modules
+	ActionTaxonomy taxonomy.Actions
+	// PrivacyLevels defines the target privacy levels that may be requested for the datasets, and the actions achieving them
+	PrivacyLevels taxonomy.PrivacyLevels
+	// Events exports the lifecycle events of the applications, if set
+	Events events.Emitter
+	// Audit records the policy decisions and the construction of the data plane of the applications, if set
+	Audit audit.Logger
+	// Recorder records the Kubernetes events of the applications, if set
+	Recorder record.EventRecorder
+	// Selections caches the modules selected for the datasets whose inputs are unchanged, if set
+	Selections *evaluate.SelectionCache
+	// DatasetIDs normalizes the dataset IDs of the applications, if set
+	DatasetIDs *app.DatasetIDNormalizer
+	// Namespaces restricts the namespaces whose applications are reconciled, if set
+	Namespaces *NamespaceScope
+	// Options tunes the concurrency and the retries of the controller
+	Options ControllerOptions
+	// Sidecars are the governance agents defined by the administrators, by their names
+	Sidecars map[string]app.Sidecar
+	// Scheduling is the scheduling of the pods of the modules set by the administrators, per capability
+	Scheduling map[app.ModuleFlow]app.SchedulingPolicy
+	// watchesDatasets is set if the Dataset resources provisioning the buckets are watched
+	watchesDatasets bool
+	// policiesInvalidated holds the applications whose policies have changed since their last evaluation
+	policiesInvalidated invalidatedApplications
+	// catalogChecks limits the rate at which the catalog metadata of the applications is compared with the data catalog
+	catalogChecks catalogChecks
+	// policyInvalidations enqueues the applications whose policies have changed
+	policyInvalidations chan event.GenericEvent
+	// priorities orders the reconciles of the applications by their priority
+	priorities *priorityQueue
 }
 
 // Reconcile reconciles M4DApplication CRD
@@ -54,10 +94,20 @@ type M4DApplicationReconciler struct {
 // The outcome is either a single Blueprint running on the same cluster or a Plotter containing multiple Blueprints that may run on different clusters
 func (r *M4DApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("m4dapplication", req.NamespacedName)
+	// the applications outside of the scope of the manager may still be enqueued, e.g. by the watches of other resources
+	if !r.Namespaces.Includes(req.Namespace) {
+		log.V(1).Info("The application is not in the namespaces reconciled by the manager")
+		return ctrl.Result{}, nil
+	}
+	start := time.Now()
+	defer func() { reconcileDuration.Observe(time.Since(start).Seconds()) }()
 	// obtain M4DApplication resource
 	applicationContext := &app.M4DApplication{}
 	if err := r.Get(ctx, req.NamespacedName, applicationContext); err != nil {
 		log.V(0).Info("The reconciled object was not found")
+		applicationStates.remove(req.NamespacedName)
+		evaluate.Forget(req.NamespacedName)
+		r.catalogChecks.remove(req.NamespacedName)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	if err := r.reconcileFinalizers(applicationContext); err != nil {
@@ -68,27 +118,94 @@ func (r *M4DApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// If the object has a scheduled deletion time, update status and return
 	if !applicationContext.DeletionTimestamp.IsZero() {
 		// The object is being deleted
+		applicationStates.remove(req.NamespacedName)
+		evaluate.Forget(req.NamespacedName)
+		r.catalogChecks.remove(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
 	observedStatus := applicationContext.Status.DeepCopy()
 	appVersion := applicationContext.GetGeneration()
+	// the dataset IDs keying the status are only normalized here, the webhook normalizing the spec alone, as are the
+	// applications admitted before the normalization was enabled or without the webhooks.
+	// The status entries of the datasets are moved to the normalized IDs with the next update of the status.
+	if r.DatasetIDs != nil {
+		r.DatasetIDs.NormalizeApplication(applicationContext)
+	}
 
 	// check if reconcile is required
-	// reconcile is required if the spec has been changed, or the previous reconcile has failed to allocate a Plotter resource
+	// reconcile is required if the spec has been changed, the previous reconcile has failed to allocate a Plotter resource,
+	// or the access to some of the datasets has been revoked or restored
 	generationComplete := r.ResourceInterface.ResourceExists(observedStatus.Generated) && (observedStatus.Generated.AppVersion == appVersion)
-	if (!generationComplete) || (observedStatus.ObservedGeneration != appVersion) {
+	revoked, err := r.newEvaluator().GetRevokedDatasets(applicationContext)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	revocationsChanged := len(revoked) != len(observedStatus.RevokedDatasets) ||
+		(len(revoked) > 0 && !reflect.DeepEqual(revoked, observedStatus.RevokedDatasets))
+	reconcileRequired := (!generationComplete) || (observedStatus.ObservedGeneration != appVersion) || revocationsChanged
+	// reconcile is also required if copies have been deleted once their TTL has expired
+	if !utils.IsReadOnlyMode() {
+		expired, err := r.expireCopies(applicationContext)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		reconcileRequired = reconcileRequired || expired
+	}
+	// reconcile is also required until the policies can be evaluated again by the policy manager
+	reconcileRequired = reconcileRequired || isPolicyManagerUnavailable(observedStatus)
+	// reconcile is also required if the policies on the datasets have changed since the last evaluation
+	reconcileRequired = reconcileRequired || r.policiesInvalidated.has(req.NamespacedName)
+	// reconcile is also required if a copy made by another application is no longer shared with the application
+	reconcileRequired = reconcileRequired || r.grantedCopiesChanged(applicationContext)
+	// reconcile is also required if the modules deployed for the application have been upgraded, according to their upgrade policy
+	reconcileRequired = reconcileRequired || r.modulesUpgraded(applicationContext)
+	// reconcile is also required if the catalog metadata of the datasets has changed since the last evaluation.
+	// The data catalog is queried at most once per catalog check interval, or per minute if the interval is not configured.
+	if !reconcileRequired && len(observedStatus.CatalogHashes) > 0 && r.catalogChecks.due(req.NamespacedName, catalogCheckInterval(), time.Now()) {
+		if changed, err := r.catalogChanged(applicationContext); err != nil {
+			log.V(0).Info("Could not compare the catalog metadata: " + err.Error())
+		} else {
+			reconcileRequired = changed
+		}
+	}
+	// reconcile is also required if the resources generated for the ready application have been deleted or modified by others
+	if observedStatus.Ready && observedStatus.ObservedGeneration == appVersion {
+		if drifts := r.detectDrift(applicationContext); len(drifts) > 0 {
+			log.V(0).Info("Reconcile: the generated resources have drifted: " + strings.Join(drifts, "; "))
+			utils.RecordEvent(r.Recorder, applicationContext, corev1.EventTypeWarning, DriftDetectedReason,
+				"The generated resources have drifted: %s", strings.Join(drifts, "; "))
+			reconcileRequired = true
+		}
+	}
+	if reconcileRequired && utils.IsReadOnlyMode() {
+		// no plotter is written and no storage is provisioned, the changes are applied once the read-only mode is turned off.
+		// The status keeps reporting the state of the application before the changes.
+		log.V(0).Info("Reconcile: the manager is in read-only mode, changes are not applied")
+		setReadOnlyModeCondition(applicationContext)
+	} else if reconcileRequired {
 		if result, err := r.reconcile(applicationContext); err != nil {
 			// another attempt will be done
 			// users should be informed in case of errors
+			setReadyCondition(applicationContext, observedStatus)
 			if !equality.Semantic.DeepEqual(&applicationContext.Status, observedStatus) {
 				// ignore an update error, a new reconcile will be made in any case
 				_ = r.Client.Status().Update(ctx, applicationContext)
 			}
+			applicationStates.set(req.NamespacedName, applicationState(applicationContext))
 			return result, err
 		}
+		r.policiesInvalidated.remove(req.NamespacedName)
+		if observedStatus.ObservedGeneration != appVersion {
+			updateSpecChanges(applicationContext)
+			if len(applicationContext.Status.SpecChanges) > 0 {
+				log.V(0).Info("Reconcile: spec changes since the previous generation", "changes", applicationContext.Status.SpecChanges)
+			}
+		}
 		applicationContext.Status.ObservedGeneration = appVersion
-	} else {
+	}
+	var stalenessCheckInterval time.Duration
+	if !reconcileRequired {
 		resourceStatus, err := r.ResourceInterface.GetResourceStatus(applicationContext.Status.Generated)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -96,23 +213,73 @@ func (r *M4DApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		if err = r.checkReadiness(applicationContext, resourceStatus); err != nil {
 			return ctrl.Result{}, err
 		}
+		if applicationContext.Status.Ready {
+			if stalenessCheckInterval, err = r.checkStaleness(applicationContext); err != nil {
+				log.V(0).Info("Could not check the freshness of the copies: " + err.Error())
+			}
+		}
+	}
+
+	// copies promoted to standalone datasets are detached from the application
+	if !utils.IsReadOnlyMode() {
+		if err := r.promoteCopies(ctx, applicationContext); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	applicationContext.Status.RetainedCopies = retainedCopies(applicationContext)
+	r.pruneStatus(applicationContext)
+	// the resources created by reconciles whose outcome was not recorded are deleted once the application is reconciled
+	// without errors, such that the resources of datasets failing to be reconciled are not created again and again
+	if !utils.IsReadOnlyMode() && !evaluate.HasError(applicationContext) {
+		r.collectOrphanedResources(applicationContext)
 	}
 
 	// Update CRD status in case of change (other than deletion, which was handled separately)
+	setReadyCondition(applicationContext, observedStatus)
 	if !equality.Semantic.DeepEqual(&applicationContext.Status, observedStatus) && applicationContext.DeletionTimestamp.IsZero() {
 		log.V(0).Info("Reconcile: Updating status for desired generation " + fmt.Sprint(applicationContext.GetGeneration()))
 		if err := r.Client.Status().Update(ctx, applicationContext); err != nil {
 			return ctrl.Result{}, err
 		}
+		emitStatusEvents(r.Events, applicationContext, observedStatus)
 	}
-	if hasError(applicationContext) {
-		log.Info("Reconciled with errors: " + getErrorMessages(applicationContext))
+	if evaluate.HasError(applicationContext) {
+		log.Info("Reconciled with errors: " + evaluate.GetErrorMessages(applicationContext))
 	}
+	applicationStates.set(req.NamespacedName, applicationState(applicationContext))
 
 	// trigger a new reconcile if required (the m4dapplication is not ready)
 	if !applicationContext.Status.Ready {
+		if retriesExhausted(applicationContext) {
+			// the failure is terminal, the application is reconciled again when its spec changes
+			return ctrl.Result{}, nil
+		}
+		if r.watchesProvisionedStorage(applicationContext) {
+			// the application is reconciled when the status of the Dataset resources changes
+			return ctrl.Result{}, nil
+		}
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
+	// a data plane kept in the fail-open mode is reconciled until the policy manager is available again
+	if isPolicyManagerUnavailable(&applicationContext.Status) {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	// check the freshness of the copies, the catalog metadata and the generated resources periodically
+	requeueAfter := utils.GetCatalogCheckInterval()
+	if stalenessCheckInterval > 0 && (requeueAfter == 0 || stalenessCheckInterval < requeueAfter) {
+		requeueAfter = stalenessCheckInterval
+	}
+	// repair the generated resources that have been deleted or modified by others
+	if interval := utils.GetDriftCheckInterval(); interval > 0 && (requeueAfter == 0 || interval < requeueAfter) {
+		requeueAfter = interval
+	}
+	// delete the copies once their TTL expires
+	if expiry := nextCopyExpiry(applicationContext); expiry > 0 && (requeueAfter == 0 || expiry < requeueAfter) {
+		requeueAfter = expiry
+	}
+	if requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -120,16 +287,104 @@ func getBucketResourceRef(name string) *types.NamespacedName {
 	return &types.NamespacedName{Name: name, Namespace: utils.GetSystemNamespace()}
 }
 
+// checkProvisionedStorage sets the storage provisioning condition according to the status of the storage provisioned
+// for the application, and returns true if all the storage has been provisioned
+func (r *M4DApplicationReconciler) checkProvisionedStorage(applicationContext *app.M4DApplication) bool {
+	pending := []string{}
+	failed := []string{}
+	listed := r.listProvisionedStorage(applicationContext)
+	for id, details := range applicationContext.Status.ProvisionedStorage {
+		var res *storage.ProvisionedStorageStatus
+		var err error
+		if statuses := listed[details.StorageType]; statuses != nil {
+			if res = statuses[details.DatasetRef]; res == nil {
+				err = errors.New("dataset " + details.DatasetRef + " has not been found")
+			}
+		} else {
+			res, err = storage.ForType(r.Provision, details.StorageType).GetDatasetStatus(getBucketResourceRef(details.DatasetRef))
+		}
+		switch {
+		case err != nil:
+			// the storage has just been requested, e.g. the Dataset resource is not in the cache yet
+			pending = append(pending, id)
+		case res.Provisioned:
+		case res.ErrorMsg != "":
+			failed = append(failed, id+": "+res.ErrorMsg)
+		default:
+			pending = append(pending, id)
+		}
+	}
+	sort.Strings(pending)
+	sort.Strings(failed)
+	if len(failed) > 0 {
+		msg := "Storage could not be provisioned for " + strings.Join(failed, "; ")
+		r.Log.V(0).Info(msg)
+		setStorageProvisioningCondition(applicationContext, app.ProvisioningFailed, msg)
+		return false
+	}
+	if len(pending) > 0 {
+		msg := "Storage is being provisioned for " + strings.Join(pending, ", ")
+		r.Log.V(0).Info(msg)
+		setStorageProvisioningCondition(applicationContext, app.ProvisioningInProgress, msg)
+		return false
+	}
+	return true
+}
+
+// listProvisionedStorage lists the status of the storage provisioned for the application with a single call per type of storage.
+// The status of the storage of a type that could not be listed is missing, and read one by one instead.
+func (r *M4DApplicationReconciler) listProvisionedStorage(applicationContext *app.M4DApplication) map[string]map[string]*storage.ProvisionedStorageStatus {
+	owner := &types.NamespacedName{Name: applicationContext.Name, Namespace: applicationContext.Namespace}
+	listed := make(map[string]map[string]*storage.ProvisionedStorageStatus)
+	for _, details := range applicationContext.Status.ProvisionedStorage {
+		if _, done := listed[details.StorageType]; done {
+			continue
+		}
+		statuses, err := storage.ForType(r.Provision, details.StorageType).ListDatasetStatuses(owner)
+		if err != nil {
+			r.Log.V(1).Info("could not list the provisioned storage: " + err.Error())
+		}
+		listed[details.StorageType] = statuses
+	}
+	return listed
+}
+
+// watchesProvisionedStorage returns true if the application waits for storage whose status is watched,
+// i.e. S3 buckets provisioned by Dataset resources
+func (r *M4DApplicationReconciler) watchesProvisionedStorage(applicationContext *app.M4DApplication) bool {
+	if !r.watchesDatasets || !isProvisioningStorage(applicationContext) {
+		return false
+	}
+	for _, details := range applicationContext.Status.ProvisionedStorage {
+		if details.StorageType != "" && details.StorageType != storage.S3 {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *M4DApplicationReconciler) checkReadiness(applicationContext *app.M4DApplication, status app.ObservedState) error {
 	applicationContext.Status.DataAccessInstructions = ""
 	applicationContext.Status.Ready = false
-	resetConditions(applicationContext)
+	// the error of the plotter is recorded once, when it is first reported
+	previousErrors := evaluate.GetErrorMessages(applicationContext)
+	evaluate.ResetConditions(applicationContext)
 	if applicationContext.Status.CatalogedAssets == nil {
 		applicationContext.Status.CatalogedAssets = make(map[string]string)
 	}
+	// the health of the modules is reported also while they fail, e.g. when their containers crash
+	setAssetsHealth(applicationContext, status.Health)
+	setExternalHostnames(applicationContext, status.ExternalHostnames)
 
 	if status.Error != "" {
-		setCondition(applicationContext, "", status.Error, true)
+		if !strings.Contains(previousErrors, status.Error) {
+			utils.RecordEvent(r.Recorder, applicationContext, corev1.EventTypeWarning, PlotterErroredReason,
+				"The plotter reports an error: %s", status.Error)
+		}
+		evaluate.SetCondition(applicationContext, "", status.Error, true)
+		if status.Failed {
+			setRetriesExhaustedCondition(applicationContext)
+		}
 		return nil
 	}
 	if !status.Ready {
@@ -151,12 +406,16 @@ func (r *M4DApplicationReconciler) checkReadiness(applicationContext *app.M4DApp
 				r.Log.V(0).Info(message)
 				return errors.New(message)
 			}
-			if err := r.Provision.SetPersistent(getBucketResourceRef(provisionedBucketRef.DatasetRef), true); err != nil {
+			if err := storage.ForType(r.Provision, provisionedBucketRef.StorageType).SetPersistent(getBucketResourceRef(provisionedBucketRef.DatasetRef), true); err != nil {
 				return err
 			}
-			// register the asset: experimental feature
+			// register the asset
 			if newAssetID, err := r.RegisterAsset(dataCtx.Requirements.Copy.Catalog.CatalogID, &provisionedBucketRef, applicationContext); err == nil {
 				applicationContext.Status.CatalogedAssets[dataCtx.DataSetID] = newAssetID
+			} else if connectors.IsRegistrationUnsupported(err) {
+				// registering again will not succeed
+				evaluate.SetCondition(applicationContext, dataCtx.DataSetID, "The data catalog does not support registering assets: "+err.Error(), true)
+				return nil
 			} else {
 				// log an error and make a new attempt to register the asset
 				r.Log.V(0).Info("Error while registering an asset: " + err.Error())
@@ -166,6 +425,7 @@ func (r *M4DApplicationReconciler) checkReadiness(applicationContext *app.M4DApp
 	}
 	applicationContext.Status.Ready = true
 	applicationContext.Status.DataAccessInstructions = status.DataAccessInstructions
+	setCopyTimes(applicationContext)
 	return nil
 }
 
@@ -179,6 +439,9 @@ func (r *M4DApplicationReconciler) reconcileFinalizers(applicationContext *app.M
 	if !applicationContext.DeletionTimestamp.IsZero() {
 		// The object is being deleted
 		if hasFinalizer { // Finalizer was created when the object was created
+			if utils.IsReadOnlyMode() {
+				return errors.New("the manager is in read-only mode, the allocated resources can not be deleted")
+			}
 			// the finalizer is present - delete the allocated resources
 			if err := r.deleteExternalResources(applicationContext); err != nil {
 				return err
@@ -204,12 +467,19 @@ func (r *M4DApplicationReconciler) reconcileFinalizers(applicationContext *app.M
 }
 
 func (r *M4DApplicationReconciler) deleteExternalResources(applicationContext *app.M4DApplication) error {
+	// the copy cleanup policy decides which buckets are kept when the application is deleted
+	if !applicationContext.DeletionTimestamp.IsZero() {
+		if err := r.applyCopyCleanupPolicy(applicationContext); err != nil {
+			return err
+		}
+	}
+	referenced := referencedResources(applicationContext)
 	// clear provisioned storage
 	// References to buckets (Dataset resources) are deleted. Buckets that are persistent will not be removed upon Dataset deletion.
 	var deletedKeys []string
 	var errMsgs []string
 	for datasetID, datasetDetails := range applicationContext.Status.ProvisionedStorage {
-		if err := r.Provision.DeleteDataset(getBucketResourceRef(datasetDetails.DatasetRef)); err != nil {
+		if err := storage.ForType(r.Provision, datasetDetails.StorageType).DeleteDataset(getBucketResourceRef(datasetDetails.DatasetRef)); err != nil {
 			errMsgs = append(errMsgs, err.Error())
 		} else {
 			deletedKeys = append(deletedKeys, datasetID)
@@ -222,15 +492,26 @@ func (r *M4DApplicationReconciler) deleteExternalResources(applicationContext *a
 		return errors.New(strings.Join(errMsgs, ";"))
 	}
 	// delete the generated resource
-	if applicationContext.Status.Generated == nil {
-		return nil
+	if applicationContext.Status.Generated != nil {
+		r.Log.V(0).Info("Reconcile: M4DApplication is deleting the generated " + applicationContext.Status.Generated.Kind)
+		if err := r.ResourceInterface.DeleteResource(applicationContext.Status.Generated); err != nil {
+			return err
+		}
+		applicationContext.Status.Generated = nil
 	}
-
-	r.Log.V(0).Info("Reconcile: M4DApplication is deleting the generated " + applicationContext.Status.Generated.Kind)
-	if err := r.ResourceInterface.DeleteResource(applicationContext.Status.Generated); err != nil {
-		return err
+	// delete the resources created by reconciles whose outcome was not recorded, e.g. when the manager restarted
+	for _, resource := range applicationContext.Status.OwnedResources {
+		if evaluate.ContainsResource(referenced, resource) || isDetached(applicationContext, resource) {
+			continue
+		}
+		if err := r.deleteOwnedResource(resource); err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		}
+	}
+	if len(errMsgs) != 0 {
+		return errors.New(strings.Join(errMsgs, ";"))
 	}
-	applicationContext.Status.Generated = nil
+	applicationContext.Status.OwnedResources = nil
 	return nil
 }
 
@@ -240,19 +521,12 @@ func setReadModulesEndpoints(applicationContext *app.M4DApplication, blueprintsM
 	var foundReadEndpoints = false
 	for _, blueprintSpec := range blueprintsMap {
 		for _, step := range blueprintSpec.Flow.Steps {
-			if step.Arguments.Read != nil {
+			// the modules of transformation chains are read by other modules, not by the workload
+			if step.Arguments.Read != nil && step.Arguments.Annotations[app.ChainedToAnnotation] == "" {
 				// We found a read module
 				foundReadEndpoints = true
-				releaseName := utils.GetReleaseName(applicationContext.ObjectMeta.Name, applicationContext.ObjectMeta.Namespace, step)
-				moduleName := step.Template
-				originalEndpointSpec := moduleMap[moduleName].Spec.Capabilities.API.Endpoint
-				fqdn := utils.GenerateModuleEndpointFQDN(releaseName, BlueprintNamespace)
 				for _, arg := range step.Arguments.Read {
-					applicationContext.Status.ReadEndpointsMap[arg.AssetID] = app.EndpointSpec{
-						Hostname: fqdn,
-						Port:     originalEndpointSpec.Port,
-						Scheme:   originalEndpointSpec.Scheme,
-					}
+					applicationContext.Status.ReadEndpointsMap[arg.AssetID] = moduleEndpoint(applicationContext, step, moduleMap, arg.AssetID, app.Read)
 				}
 			}
 		}
@@ -263,22 +537,96 @@ func setReadModulesEndpoints(applicationContext *app.M4DApplication, blueprintsM
 	}
 }
 
+// setWriteModulesEndpoints populates the WriteEndpointsMap map in the status of the m4dapplication
+// Write modules run in the cluster of the user's workload, as read modules do
+func setWriteModulesEndpoints(applicationContext *app.M4DApplication, blueprintsMap map[string]app.BlueprintSpec, moduleMap map[string]*app.M4DModule) {
+	for _, blueprintSpec := range blueprintsMap {
+		for _, step := range blueprintSpec.Flow.Steps {
+			if step.Arguments.Write == nil {
+				continue
+			}
+			for _, arg := range step.Arguments.Write {
+				applicationContext.Status.WriteEndpointsMap[arg.AssetID] = moduleEndpoint(applicationContext, step, moduleMap, arg.AssetID, app.Write)
+			}
+		}
+	}
+}
+
+// moduleEndpoint returns the endpoint through which the workload accesses an asset served by the module instance of a step.
+// The endpoint of the module API matching the interface requested for the asset in the flow is returned, or the endpoint of its default API.
+func moduleEndpoint(applicationContext *app.M4DApplication, step app.FlowStep, moduleMap map[string]*app.M4DModule, assetID string, flow app.ModuleFlow) app.EndpointSpec {
+	releaseName := utils.GetReleaseName(applicationContext.ObjectMeta.Name, applicationContext.ObjectMeta.Namespace, step)
+	module := moduleMap[step.Template]
+	api := capabilities.GetMatchingAPI(module, evaluate.RequestedInterface(applicationContext, assetID, flow))
+	if api == nil {
+		api = module.Spec.Capabilities.API
+	}
+	originalEndpointSpec := app.EndpointSpec{}
+	if api != nil {
+		originalEndpointSpec = api.Endpoint
+	}
+	// the endpoints registered in an external DNS are reported once the modules are registered, see setExternalHostnames
+	fqdn := utils.GenerateModuleEndpointFQDN(releaseName, utils.BlueprintNamespace)
+	return app.EndpointSpec{
+		Hostname: fqdn,
+		Name:     originalEndpointSpec.Name,
+		Port:     originalEndpointSpec.Port,
+		Scheme:   originalEndpointSpec.Scheme,
+	}
+}
+
+// setExternalHostnames reports the endpoints of the modules whose services are registered in an external DNS under
+// their stable hostnames outside the cluster, and the other endpoints under their hostnames inside the cluster
+func setExternalHostnames(applicationContext *app.M4DApplication, registered map[string]string) {
+	internalSuffix := "." + utils.BlueprintNamespace + ".svc.cluster.local"
+	externalSuffix := ""
+	if domain := utils.GetExternalDNSDomain(); domain != "" {
+		externalSuffix = "." + domain
+	}
+	for _, endpoints := range []map[string]app.EndpointSpec{applicationContext.Status.ReadEndpointsMap, applicationContext.Status.WriteEndpointsMap} {
+		for assetID, endpoint := range endpoints {
+			var releaseName string
+			switch {
+			case strings.HasSuffix(endpoint.Hostname, internalSuffix):
+				releaseName = strings.TrimSuffix(endpoint.Hostname, internalSuffix)
+			case externalSuffix != "" && strings.HasSuffix(endpoint.Hostname, externalSuffix):
+				releaseName = strings.TrimSuffix(endpoint.Hostname, externalSuffix)
+			default:
+				continue
+			}
+			endpoint.Hostname = utils.GenerateModuleEndpointFQDN(releaseName, utils.BlueprintNamespace)
+			if hostname, found := registered[releaseName]; found {
+				endpoint.Hostname = hostname
+			}
+			endpoints[assetID] = endpoint
+		}
+	}
+}
+
 // reconcile receives either M4DApplication CRD
 // or a status update from the generated resource
 func (r *M4DApplicationReconciler) reconcile(applicationContext *app.M4DApplication) (ctrl.Result, error) {
 	utils.PrintStructure(applicationContext.Spec, r.Log, "M4DApplication")
 	// Data User created or updated the M4DApplication
+	// the status of the previously granted generation is kept if the policy manager is unavailable in the fail-open mode
+	previous := applicationContext.Status.DeepCopy()
 
 	// clear status
-	resetConditions(applicationContext)
+	evaluate.ResetConditions(applicationContext)
 	applicationContext.Status.DataAccessInstructions = ""
 	applicationContext.Status.Ready = false
 	if applicationContext.Status.ProvisionedStorage == nil {
 		applicationContext.Status.ProvisionedStorage = make(map[string]app.DatasetDetails)
 	}
 	applicationContext.Status.ReadEndpointsMap = make(map[string]app.EndpointSpec)
+	applicationContext.Status.WriteEndpointsMap = make(map[string]app.EndpointSpec)
 
 	if len(applicationContext.Spec.Data) == 0 {
+		evaluate.SetRevokedDatasets(applicationContext, nil)
+		applicationContext.Status.DirectAccess = nil
+		if err := r.reconcileInPlacePolicy(applicationContext); err != nil {
+			return ctrl.Result{}, err
+		}
 		if err := r.deleteExternalResources(applicationContext); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -287,158 +635,135 @@ func (r *M4DApplicationReconciler) reconcile(applicationContext *app.M4DApplicat
 		return ctrl.Result{}, nil
 	}
 
-	clusters, err := r.ClusterManager.GetClusters()
+	// the outcome of the evaluation is audited when the reconcile returns, whether the data plane is constructed or not,
+	// including the reconciles in which the evaluation itself fails
+	var evaluation *evaluate.Evaluation
+	defer func() { r.auditReconcile(applicationContext, evaluation) }()
+	evaluation, err := r.newEvaluator().Evaluate(applicationContext)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	// create a list of requirements for creating a data flow (actions, interface to app, data format) per a single data set
-	var requirements []modules.DataInfo
-	for _, dataset := range applicationContext.Spec.Data {
-		req := modules.DataInfo{
-			Context: dataset.DeepCopy(),
-		}
-		if err := r.constructDataInfo(&req, applicationContext, clusters); err != nil {
-			return ctrl.Result{}, err
-		}
-		requirements = append(requirements, req)
-	}
-	// check for errors
-	if hasError(applicationContext) {
+	applicationContext.Status.CatalogHashes = evaluation.CatalogHashes
+	if evaluation.PolicyManagerUnavailable && r.applyPolicyManagerFailMode(applicationContext, previous) {
 		return ctrl.Result{}, nil
 	}
-
-	// create a module manager that will select modules to be orchestrated based on user requirements and module capabilities
-	moduleMap, err := r.GetAllModules()
-	if err != nil {
+	// the egress of the workload is restricted to the sources of the datasets accessed in place, whether others have errors or not
+	if err := r.reconcileInPlacePolicy(applicationContext); err != nil {
 		return ctrl.Result{}, err
 	}
-	objectKey := client.ObjectKeyFromObject(applicationContext)
-	moduleManager := &ModuleManager{
-		Client:             r.Client,
-		Log:                r.Log,
-		Modules:            moduleMap,
-		Clusters:           clusters,
-		Owner:              objectKey,
-		PolicyManager:      r.PolicyManager,
-		Provision:          r.Provision,
-		ProvisionedStorage: make(map[string]NewAssetInfo),
-	}
-	instances := make([]modules.ModuleInstanceSpec, 0)
-	for _, item := range requirements {
-		instancesPerDataset, err := moduleManager.SelectModuleInstances(item, applicationContext)
-		if err != nil {
-			setCondition(applicationContext, item.Context.DataSetID, err.Error(), true)
-		}
-		instances = append(instances, instancesPerDataset...)
-	}
 	// check for errors
-	if hasError(applicationContext) {
+	if evaluate.HasError(applicationContext) {
 		return ctrl.Result{}, nil
 	}
 	// update allocated storage in the status
 	// clean irrelevant buckets
 	for datasetID, details := range applicationContext.Status.ProvisionedStorage {
-		if _, found := moduleManager.ProvisionedStorage[datasetID]; !found {
-			_ = r.Provision.DeleteDataset(getBucketResourceRef(details.DatasetRef))
+		if _, found := evaluation.ProvisionedStorage[datasetID]; !found {
+			_ = storage.ForType(r.Provision, details.StorageType).DeleteDataset(getBucketResourceRef(details.DatasetRef))
 			delete(applicationContext.Status.ProvisionedStorage, datasetID)
 		}
 	}
 	// add or update new buckets
-	for datasetID, info := range moduleManager.ProvisionedStorage {
-		raw := serde.NewArbitrary(info.Details)
-		applicationContext.Status.ProvisionedStorage[datasetID] = app.DatasetDetails{
-			DatasetRef: info.Storage.Name,
-			SecretRef:  info.Storage.SecretRef.Name,
-			Details:    *raw,
+	for datasetID, info := range evaluation.ProvisionedStorage {
+		raw := serde.NewCompactArbitrary(info.Details, utils.GetCompactEncoding())
+		details := app.DatasetDetails{
+			DatasetRef:  info.Storage.Name,
+			StorageType: info.Storage.Type,
+			SecretRef:   info.Storage.SecretRef.Name,
+			Details:     *raw,
 		}
-	}
-	ready := true
-	var allocErr error
-	// check that the buckets have been created successfully using Dataset status
-	for id, details := range applicationContext.Status.ProvisionedStorage {
-		res, err := r.Provision.GetDatasetStatus(getBucketResourceRef(details.DatasetRef))
-		if err != nil {
-			ready = false
-			break
-		}
-		if !res.Provisioned {
-			ready = false
-			r.Log.V(0).Info("No bucket has been provisioned for " + id)
-			// TODO(shlomitk1): analyze the error
-			if res.ErrorMsg != "" {
-				allocErr = errors.New(res.ErrorMsg)
-			}
-			break
+		// keep the time of a copy made to the same bucket
+		if previous, found := applicationContext.Status.ProvisionedStorage[datasetID]; found && previous.DatasetRef == details.DatasetRef {
+			details.CopiedAt = previous.CopiedAt
 		}
+		applicationContext.Status.ProvisionedStorage[datasetID] = details
 	}
-	if !ready {
-		return ctrl.Result{RequeueAfter: 2 * time.Second}, allocErr
+	// the plotter is generated once the storage has been provisioned.
+	// The application is reconciled again when the status of the storage changes.
+	if !r.checkProvisionedStorage(applicationContext) {
+		return ctrl.Result{}, nil
 	}
-	// generate blueprint specifications (per cluster)
-	blueprintPerClusterMap := r.GenerateBlueprints(instances, applicationContext)
-	setReadModulesEndpoints(applicationContext, blueprintPerClusterMap, moduleMap)
+	blueprintPerClusterMap := evaluation.Blueprints
+	if len(blueprintPerClusterMap) == 0 {
+		// all the datasets are accessed in place
+		if err := r.deleteExternalResources(applicationContext); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Log.V(0).Info("no blueprint will be generated since all the datasets are accessed in place")
+		applicationContext.Status.Ready = true
+		return ctrl.Result{}, nil
+	}
+	setReadModulesEndpoints(applicationContext, blueprintPerClusterMap, evaluation.Modules)
+	setWriteModulesEndpoints(applicationContext, blueprintPerClusterMap, evaluation.Modules)
 	ownerRef := &app.ResourceReference{Name: applicationContext.Name, Namespace: applicationContext.Namespace, AppVersion: applicationContext.GetGeneration()}
 	resourceRef := r.ResourceInterface.CreateResourceReference(ownerRef)
-	if err := r.ResourceInterface.CreateOrUpdateResource(ownerRef, resourceRef, blueprintPerClusterMap); err != nil {
+	if err := r.persistOwnedResources(applicationContext, ownedGeneratedResource(resourceRef)); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.ResourceInterface.CreateOrUpdateResource(ownerRef, resourceRef, blueprintPerClusterMap, applicationContext.Spec.RetryPolicy); err != nil {
 		r.Log.V(0).Info("Error creating " + resourceRef.Kind + " : " + err.Error())
 		if err.Error() == app.InvalidClusterConfiguration {
-			setCondition(applicationContext, "", app.InvalidClusterConfiguration, true)
+			evaluate.SetCondition(applicationContext, "", app.InvalidClusterConfiguration, true)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	if previous.Generated == nil || previous.Generated.AppVersion != resourceRef.AppVersion {
+		utils.RecordEvent(r.Recorder, applicationContext, corev1.EventTypeNormal, PlotterCreatedReason,
+			"Created %s %s/%s for generation %d", resourceRef.Kind, resourceRef.Namespace, resourceRef.Name, resourceRef.AppVersion)
+	} else {
+		utils.RecordEvent(r.Recorder, applicationContext, corev1.EventTypeNormal, PlotterUpdatedReason,
+			"Updated %s %s/%s", resourceRef.Kind, resourceRef.Namespace, resourceRef.Name)
+	}
 	applicationContext.Status.Generated = resourceRef
 	r.Log.V(0).Info("Created " + resourceRef.Kind + " successfully!")
 	return ctrl.Result{}, nil
 }
 
-func (r *M4DApplicationReconciler) constructDataInfo(req *modules.DataInfo, input *app.M4DApplication, clusters []multicluster.Cluster) error {
-	var err error
-
-	// Call the DataCatalog service to get info about the dataset
-	var response *pb.CatalogDatasetInfo
-	var credentialPath string
-	if input.Spec.SecretRef != "" {
-		credentialPath = utils.GetVaultAddress() + vault.PathForReadingKubeSecret(input.Namespace, input.Spec.SecretRef)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
-
-	if response, err = r.DataCatalog.GetDatasetInfo(ctx, &pb.CatalogDatasetRequest{
-		CredentialPath: credentialPath,
-		DatasetId:      req.Context.DataSetID,
-	}); err != nil {
-		return err
+// newEvaluator creates an evaluate.Evaluator sharing the connectors of the reconciler
+func (r *M4DApplicationReconciler) newEvaluator() *evaluate.Evaluator {
+	return &evaluate.Evaluator{
+		Client:              r.Client,
+		Log:                 r.Log,
+		PolicyManager:       r.PolicyManager,
+		DataCatalog:         r.DataCatalog,
+		ClusterManager:      r.ClusterManager,
+		Provision:           r.Provision,
+		GovernedCopyMaxSize: utils.GetGovernedCopyMaxSize(),
+		ActionTaxonomy:      r.ActionTaxonomy,
+		PrivacyLevels:       r.PrivacyLevels,
+		Events:              r.Events,
+		Recorder:            r.Recorder,
+		Selections:          r.Selections,
+		Concurrency:         utils.GetEvaluationConcurrency(),
+		ModuleVersions:      evaluate.AdminModuleVersions(r.Log),
+		Sidecars:            r.Sidecars,
+		Scheduling:          r.Scheduling,
+		OwnResources:        r.persistOwnedResources,
 	}
-
-	details := response.GetDetails()
-	dataDetails, err := modules.CatalogDatasetToDataDetails(response)
-	if err != nil {
-		return err
-	}
-	req.DataDetails = dataDetails
-	req.VaultSecretPath = ""
-	if details.CredentialsInfo != nil {
-		req.VaultSecretPath = details.CredentialsInfo.VaultSecretPath
-	}
-
-	return nil
 }
 
 // NewM4DApplicationReconciler creates a new reconciler for M4DApplications
 func NewM4DApplicationReconciler(mgr ctrl.Manager, name string,
 	policyManager connectors.PolicyManager, catalog connectors.DataCatalog, cm multicluster.ClusterLister, provision storage.ProvisionInterface) *M4DApplicationReconciler {
+	log := ctrl.Log.WithName("controllers").WithName(name)
 	return &M4DApplicationReconciler{
 		Client:            mgr.GetClient(),
 		Name:              name,
-		Log:               ctrl.Log.WithName("controllers").WithName(name),
+		Log:               log,
 		Scheme:            mgr.GetScheme(),
 		PolicyManager:     policyManager,
 		ResourceInterface: NewPlotterInterface(mgr.GetClient()),
 		ClusterManager:    cm,
 		Provision:         provision,
 		DataCatalog:       catalog,
+		Recorder:          mgr.GetEventRecorderFor("m4dapplication-controller"),
+		DatasetIDs:        utils.GetDatasetIDNormalizer(),
+		Namespaces:        NewNamespaceScope(utils.GetWatchedNamespaces(), utils.GetIgnoredNamespaces()),
+		Sidecars:          evaluate.AdminSidecars(log),
+		Scheduling:        evaluate.AdminScheduling(log),
+		// buffered, so that the policy invalidation endpoint is not blocked while the controller is busy
+		policyInvalidations: make(chan event.GenericEvent, 100),
 	}
 }
 
@@ -461,11 +786,86 @@ func (r *M4DApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			}},
 		}
 	}
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&app.M4DApplication{}).
-		Watches(&source.Kind{
-			Type: &app.Plotter{},
-		}, handler.EnqueueRequestsFromMapFunc(mapFn)).Complete(r)
+	// the requests go through a priority queue, such that applications of high priority are reconciled first
+	// when the controller is saturated. As many requests are released to the work queue as can be reconciled concurrently.
+	r.priorities = newPriorityQueue(r.applicationPriority, r.Options.concurrency())
+	c, err := controller.New("m4dapplication", mgr, r.Options.controllerOptions(r.priorities.Reconciler(r)))
+	if err != nil {
+		return err
+	}
+	type watch struct {
+		source  source.Source
+		handler handler.EventHandler
+	}
+	watches := []watch{
+		{&source.Kind{Type: &app.M4DApplication{}}, &handler.EnqueueRequestForObject{}},
+		{&source.Kind{Type: &app.Plotter{}}, handler.EnqueueRequestsFromMapFunc(mapFn)},
+		{&source.Kind{Type: &app.M4DDatasetRevocation{}}, handler.EnqueueRequestsFromMapFunc(r.applicationsReferencingDataset)},
+		{&source.Kind{Type: &app.M4DGrant{}}, handler.EnqueueRequestsFromMapFunc(r.applicationsReadingGrantedCopies)},
+		{&source.Kind{Type: &app.M4DApplication{}}, handler.EnqueueRequestsFromMapFunc(r.applicationsReadingGrantedCopies)},
+		{&source.Kind{Type: &app.M4DCopyPromotion{}}, handler.EnqueueRequestsFromMapFunc(applicationPromotingCopy)},
+		{&source.Kind{Type: &app.M4DModule{}}, handler.EnqueueRequestsFromMapFunc(r.applicationsRunningModule)},
+	}
+	if r.policyInvalidations != nil {
+		watches = append(watches, watch{&source.Channel{Source: r.policyInvalidations}, &handler.EnqueueRequestForObject{}})
+	}
+	// the status of the provisioned buckets is polled if the Dataset CRD is not installed,
+	// or if the buckets are created through the S3 API
+	if _, ok := storage.ForType(r.Provision, storage.S3).(*storage.S3APIProvisioner); ok {
+		r.Log.V(0).Info("Dataset resources are not watched: the buckets are provisioned through the S3 API")
+	} else if _, err := mgr.GetRESTMapper().RESTMapping(storage.GroupVersion.WithKind(storage.DatasetKind).GroupKind(), storage.GroupVersion.Version); err == nil {
+		watches = append(watches, watch{&source.Kind{Type: storage.NewDataset()}, handler.EnqueueRequestsFromMapFunc(applicationOwningStorage)})
+		r.watchesDatasets = true
+	} else {
+		r.Log.V(0).Info("Dataset resources are not watched: " + err.Error())
+	}
+	for _, w := range watches {
+		if err := c.Watch(w.source, r.priorities.Handler(w.handler)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applicationPriority returns the priority of an application, or 0 if it is not found
+func (r *M4DApplicationReconciler) applicationPriority(key types.NamespacedName) int32 {
+	application := &app.M4DApplication{}
+	if err := r.Get(context.Background(), key, application); err != nil {
+		return 0
+	}
+	return application.Spec.Priority
+}
+
+// applicationOwningStorage maps a Dataset resource to the application for which the bucket has been provisioned
+func applicationOwningStorage(a client.Object) []reconcile.Request {
+	owner, found := storage.OwnerOf(a)
+	if !found {
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{{NamespacedName: owner}}
+}
+
+// applicationsReferencingDataset maps a dataset revocation to the applications referencing the revoked dataset
+func (r *M4DApplicationReconciler) applicationsReferencingDataset(a client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	revocation, ok := a.(*app.M4DDatasetRevocation)
+	if !ok || revocation.Namespace != utils.GetSystemNamespace() {
+		return requests
+	}
+	var applications app.M4DApplicationList
+	if err := r.List(context.Background(), &applications); err != nil {
+		r.Log.V(0).Info("Error while listing applications: " + err.Error())
+		return requests
+	}
+	for _, application := range applications.Items {
+		for _, dataset := range application.Spec.Data {
+			if dataset.DataSetID == revocation.Spec.DataSetID {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&application)})
+				break
+			}
+		}
+	}
+	return requests
 }
 
 // AnalyzeError analyzes whether the given error is fatal, or a retrial attempt can be made.
@@ -475,10 +875,10 @@ func AnalyzeError(app *app.M4DApplication, log logr.Logger, assetID string, err
 	errStatus, _ := status.FromError(err)
 	log.V(0).Info(errStatus.Message())
 	if errStatus.Code() == codes.InvalidArgument {
-		setCondition(app, assetID, errStatus.Message(), true)
+		evaluate.SetCondition(app, assetID, errStatus.Message(), true)
 		return nil
 	}
-	setCondition(app, assetID, errStatus.Message(), false)
+	evaluate.SetCondition(app, assetID, errStatus.Message(), false)
 	return err
 }
 
@@ -491,18 +891,5 @@ func ownerLabels(id types.NamespacedName) map[string]string {
 
 // GetAllModules returns all CRDs of the kind M4DModule mapped by their name
 func (r *M4DApplicationReconciler) GetAllModules() (map[string]*app.M4DModule, error) {
-	ctx := context.Background()
-
-	moduleMap := make(map[string]*app.M4DModule)
-	var moduleList app.M4DModuleList
-	if err := r.List(ctx, &moduleList, client.InNamespace(utils.GetSystemNamespace())); err != nil {
-		r.Log.V(0).Info("Error while listing modules: " + err.Error())
-		return moduleMap, err
-	}
-	r.Log.Info("Listing all modules")
-	for _, module := range moduleList.Items {
-		r.Log.Info(module.GetName())
-		moduleMap[module.Name] = module.DeepCopy()
-	}
-	return moduleMap, nil
+	return r.newEvaluator().GetAllModules()
 }
@@ -6,14 +6,18 @@ package app
 import (
 	"context"
 	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
 	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
 
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
@@ -212,7 +216,11 @@ func TestDenyOnRead(t *testing.T) {
 	err = cl.Get(context.TODO(), req.NamespacedName, application)
 	g.Expect(err).To(gomega.BeNil(), "Cannot fetch m4dapplication")
 	// Expect an error
-	g.Expect(getErrorMessages(application)).To(gomega.ContainSubstring(app.ReadAccessDenied))
+	g.Expect(evaluate.GetErrorMessages(application)).To(gomega.ContainSubstring(app.ReadAccessDenied))
+	// the denial is recorded in the state of the asset
+	g.Expect(application.Status.AssetStates["s3/deny-dataset"].Actions).To(gomega.HaveLen(1))
+	g.Expect(application.Status.AssetStates["s3/deny-dataset"].Actions[0].Name).To(gomega.Equal(evaluate.DenyAction))
+	g.Expect(application.Status.AssetStates["s3/deny-dataset"].Actions[0].Flow).To(gomega.Equal(app.Read))
 }
 
 // Tests selection of read-path module
@@ -262,8 +270,52 @@ func TestNoReadPath(t *testing.T) {
 	err = cl.Get(context.TODO(), req.NamespacedName, application)
 	g.Expect(err).To(gomega.BeNil(), "Cannot fetch m4dapplication")
 	// Expect an error
-	g.Expect(getErrorMessages(application)).To(gomega.ContainSubstring(app.ModuleNotFound))
-	g.Expect(getErrorMessages(application)).To(gomega.ContainSubstring("read"))
+	g.Expect(evaluate.GetErrorMessages(application)).To(gomega.ContainSubstring(app.ModuleNotFound))
+	g.Expect(evaluate.GetErrorMessages(application)).To(gomega.ContainSubstring("read"))
+}
+
+// This test checks that a Kafka dataset is read by a module streaming the topic directly,
+// rather than being copied to S3 first.
+// Assumptions on response from connectors:
+// Kafka dataset, enforcement action: Allow
+// Read modules: a streaming kafka module and a parquet module, both exposing the requested API
+// Result: a single read step of the streaming module, no storage is provisioned
+func TestKafkaStreamingRead(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID:    "kafka/allow-dataset",
+			Requirements: app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+		},
+	}
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	streamModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-kafka-stream.yaml", streamModule)).NotTo(gomega.HaveOccurred())
+	copyModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/copy-db2-parquet.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, application, readModule, streamModule, copyModule)
+	r := createTestM4DApplicationController(cl, s)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: application.Name, Namespace: application.Namespace}}
+	_, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, application)).To(gomega.Succeed())
+	g.Expect(evaluate.GetErrorMessages(application)).To(gomega.BeEmpty())
+	g.Expect(application.Status.ProvisionedStorage).To(gomega.BeEmpty())
+	g.Expect(application.Status.Generated).ToNot(gomega.BeNil())
+	plotter := &app.Plotter{}
+	plotterObjectKey := types.NamespacedName{Namespace: application.Status.Generated.Namespace, Name: application.Status.Generated.Name}
+	g.Expect(cl.Get(context.Background(), plotterObjectKey, plotter)).To(gomega.Succeed())
+	steps := plotter.Spec.Blueprints["thegreendragon"].Flow.Steps
+	g.Expect(steps).To(gomega.HaveLen(1))
+	g.Expect(steps[0].Template).To(gomega.Equal(streamModule.Name))
 }
 
 // Tests finding a module for copy
@@ -327,8 +379,8 @@ func TestWrongCopyModule(t *testing.T) {
 	err = cl.Get(context.TODO(), req.NamespacedName, application)
 	g.Expect(err).To(gomega.BeNil(), "Cannot fetch m4dapplication")
 	// Expect an error
-	g.Expect(getErrorMessages(application)).To(gomega.ContainSubstring(app.ModuleNotFound))
-	g.Expect(getErrorMessages(application)).To(gomega.ContainSubstring("copy"))
+	g.Expect(evaluate.GetErrorMessages(application)).To(gomega.ContainSubstring(app.ModuleNotFound))
+	g.Expect(evaluate.GetErrorMessages(application)).To(gomega.ContainSubstring("copy"))
 }
 
 // Tests finding a module for copy supporting actions
@@ -384,7 +436,7 @@ func TestActionSupport(t *testing.T) {
 	err = cl.Get(context.TODO(), req.NamespacedName, application)
 	g.Expect(err).To(gomega.BeNil(), "Cannot fetch m4dapplication")
 	// Expect an error
-	g.Expect(getErrorMessages(application)).To(gomega.ContainSubstring(app.ModuleNotFound))
+	g.Expect(evaluate.GetErrorMessages(application)).To(gomega.ContainSubstring(app.ModuleNotFound))
 }
 
 // Assumptions on response from connectors:
@@ -458,6 +510,11 @@ func TestMultipleDatasets(t *testing.T) {
 	g.Expect(err).To(gomega.BeNil(), "Cannot fetch m4dapplication")
 	// check provisioned storage
 	g.Expect(application.Status.ProvisionedStorage["db2/redact-dataset"].DatasetRef).ToNot(gomega.BeEmpty(), "No storage provisioned")
+	// the redaction applied by the copy module is recorded, the dataset read as is is not listed
+	g.Expect(application.Status.AssetStates).To(gomega.HaveLen(1))
+	g.Expect(application.Status.AssetStates["db2/redact-dataset"].Actions).To(gomega.Equal([]app.AppliedAction{
+		{Name: "redact", Level: "COLUMN", Args: map[string]string{"column": "SSN"}, Flow: app.Copy, Module: "implicit-copy-batch-db2"},
+	}))
 	// check plotter creation
 	g.Expect(application.Status.Generated).ToNot(gomega.BeNil())
 	plotterObjectKey := types.NamespacedName{
@@ -678,7 +735,7 @@ func TestCopyDataNotAllowed(t *testing.T) {
 	// check provisioned storage
 	g.Expect(application.Status.ProvisionedStorage).To(gomega.BeEmpty())
 	// check errors
-	g.Expect(getErrorMessages(application)).NotTo(gomega.BeEmpty())
+	g.Expect(evaluate.GetErrorMessages(application)).NotTo(gomega.BeEmpty())
 }
 
 // This test checks that the plotter state propagates into the m4dapp state
@@ -747,7 +804,7 @@ func TestPlotterUpdate(t *testing.T) {
 	g.Expect(err).To(gomega.BeNil())
 	err = cl.Get(context.Background(), req.NamespacedName, application)
 	g.Expect(err).To(gomega.BeNil(), "Cannot fetch m4dapplication")
-	g.Expect(getErrorMessages(application)).To(gomega.ContainSubstring(errorMsg))
+	g.Expect(evaluate.GetErrorMessages(application)).To(gomega.ContainSubstring(errorMsg))
 
 	// mark the plotter as ready
 	plotter.Status.ObservedState.Error = ""
@@ -810,7 +867,7 @@ func TestSyncWithPlotter(t *testing.T) {
 	newApp := &app.M4DApplication{}
 	err = cl.Get(context.Background(), req.NamespacedName, newApp)
 	g.Expect(err).To(gomega.BeNil(), "Cannot fetch m4dapplication")
-	g.Expect(getErrorMessages(newApp)).NotTo(gomega.BeEmpty())
+	g.Expect(evaluate.GetErrorMessages(newApp)).NotTo(gomega.BeEmpty())
 	g.Expect(newApp.Status.Ready).NotTo(gomega.BeTrue())
 }
 
@@ -852,6 +909,191 @@ func TestM4DApplicationWithNoDatasets(t *testing.T) {
 	newApp := &app.M4DApplication{}
 	err = cl.Get(context.Background(), req.NamespacedName, newApp)
 	g.Expect(err).To(gomega.BeNil(), "Cannot fetch m4dapplication")
-	g.Expect(getErrorMessages(newApp)).To(gomega.BeEmpty())
+	g.Expect(evaluate.GetErrorMessages(newApp)).To(gomega.BeEmpty())
 	g.Expect(newApp.Status.Ready).To(gomega.BeTrue())
 }
+
+// This test checks that each asset read by a module exposing several APIs is served
+// by the endpoint of the API matching the interface requested for the asset
+func TestReadEndpointsOfModuleAPIs(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	arrowFlight := app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	s3Parquet := app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet}
+	module := &app.M4DModule{
+		ObjectMeta: metav1.ObjectMeta{Name: "read-module"},
+		Spec: app.M4DModuleSpec{
+			Capabilities: app.Capability{
+				API:  &app.ModuleAPI{InterfaceDetails: arrowFlight, Endpoint: app.EndpointSpec{Name: "flight", Port: 80, Scheme: "grpc"}},
+				APIs: []app.ModuleAPI{{InterfaceDetails: s3Parquet, Endpoint: app.EndpointSpec{Name: "s3", Port: 9000, Scheme: "https"}}},
+			},
+		},
+	}
+	application := &app.M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: app.M4DApplicationSpec{
+			Data: []app.DataContext{
+				{DataSetID: "s3/flight-dataset", Requirements: app.DataRequirements{Interface: arrowFlight}},
+				{DataSetID: "s3/parquet-dataset"},
+			},
+		},
+	}
+	application.Status.NegotiatedInterfaces = map[string]app.InterfaceDetails{"s3/parquet-dataset": s3Parquet}
+	application.Status.ReadEndpointsMap = make(map[string]app.EndpointSpec)
+	step := app.FlowStep{
+		Name:     "read",
+		Template: module.Name,
+		Arguments: app.ModuleArguments{
+			Read: []app.ReadModuleArgs{{AssetID: "s3/flight-dataset"}, {AssetID: "s3/parquet-dataset"}},
+		},
+	}
+	blueprints := map[string]app.BlueprintSpec{"thegreendragon": {Flow: app.DataFlow{Steps: []app.FlowStep{step}}}}
+
+	setReadModulesEndpoints(application, blueprints, map[string]*app.M4DModule{module.Name: module})
+	hostname := utils.GenerateModuleEndpointFQDN(utils.GetReleaseName(application.Name, application.Namespace, step), utils.BlueprintNamespace)
+	g.Expect(application.Status.ReadEndpointsMap).To(gomega.Equal(map[string]app.EndpointSpec{
+		"s3/flight-dataset":  {Hostname: hostname, Name: "flight", Port: 80, Scheme: "grpc"},
+		"s3/parquet-dataset": {Hostname: hostname, Name: "s3", Port: 9000, Scheme: "https"},
+	}))
+}
+
+// This test checks that the plotter is generated once the bucket of an implicit copy has been provisioned,
+// and that the provisioning status is reported in the conditions of the application in the meantime
+func TestProvisionedCondition(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID:    "db2/redact-dataset",
+			Requirements: app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+		},
+	}
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	copyModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/copy-db2-parquet.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+	dummySecret := &corev1.Secret{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/credentials-theshire.yaml", dummySecret)).NotTo(gomega.HaveOccurred())
+	account := &app.M4DStorageAccount{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/account-theshire.yaml", account)).NotTo(gomega.HaveOccurred())
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, application, readModule, copyModule, dummySecret, account)
+	r := createTestM4DApplicationController(cl, s)
+	r.Provision = storage.NewProvisionImpl(cl)
+	r.watchesDatasets = true
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(application)}
+
+	// the Dataset resource has no status yet
+	result, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(result).To(gomega.Equal(ctrl.Result{}), "the application should wait for the status of the Dataset")
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, application)).To(gomega.Succeed())
+	g.Expect(application.Status.Generated).To(gomega.BeNil())
+	condition := meta.FindStatusCondition(application.Status.Conditions, app.ProvisionedCondition)
+	g.Expect(condition.Status).To(gomega.Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(gomega.Equal(app.ProvisioningInProgress))
+	g.Expect(evaluate.HasError(application)).To(gomega.BeFalse())
+
+	dataset := storage.NewDataset()
+	datasetKey := getBucketResourceRef(application.Status.ProvisionedStorage["db2/redact-dataset"].DatasetRef)
+	g.Expect(cl.Get(context.Background(), *datasetKey, dataset)).To(gomega.Succeed())
+	g.Expect(applicationOwningStorage(dataset)).To(gomega.ConsistOf(req))
+	setProvisionStatus := func(status string, info string) {
+		g.Expect(unstructured.SetNestedStringMap(dataset.Object, map[string]string{"status": status, "info": info}, "status", "provision")).To(gomega.Succeed())
+		g.Expect(cl.Update(context.Background(), dataset)).To(gomega.Succeed())
+	}
+
+	// the provisioning fails
+	setProvisionStatus("ERR", "bucket quota exceeded")
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, application)).To(gomega.Succeed())
+	condition = meta.FindStatusCondition(application.Status.Conditions, app.ProvisionedCondition)
+	g.Expect(condition.Reason).To(gomega.Equal(app.ProvisioningFailed))
+	g.Expect(condition.Message).To(gomega.ContainSubstring("bucket quota exceeded"))
+
+	// the bucket is provisioned
+	setProvisionStatus("OK", "")
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, application)).To(gomega.Succeed())
+	g.Expect(application.Status.Generated).ToNot(gomega.BeNil())
+	g.Expect(meta.IsStatusConditionTrue(application.Status.Conditions, app.ProvisionedCondition)).To(gomega.BeTrue())
+}
+
+// This test checks that the endpoints are reported under their external hostnames only once the services
+// of the modules are registered in the external DNS
+func TestSetExternalHostnames(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(os.Setenv(utils.ExternalDNSDomainKey, "data.example.com")).To(gomega.Succeed())
+	defer os.Unsetenv(utils.ExternalDNSDomainKey)
+
+	application := &app.M4DApplication{
+		Status: app.M4DApplicationStatus{
+			ReadEndpointsMap: map[string]app.EndpointSpec{
+				"s3/allow-dataset":  {Hostname: utils.GenerateModuleEndpointFQDN("read-allow", utils.BlueprintNamespace), Port: 80},
+				"s3/redact-dataset": {Hostname: utils.GenerateModuleEndpointFQDN("read-redact", utils.BlueprintNamespace), Port: 80},
+			},
+			WriteEndpointsMap: map[string]app.EndpointSpec{
+				"s3/new-dataset": {Hostname: utils.GenerateModuleEndpointFQDN("write-new", utils.BlueprintNamespace), Port: 80},
+			},
+		},
+	}
+	setExternalHostnames(application, map[string]string{"read-allow": "read-allow.data.example.com", "write-new": "write-new.data.example.com"})
+	g.Expect(application.Status.ReadEndpointsMap["s3/allow-dataset"]).To(gomega.Equal(app.EndpointSpec{Hostname: "read-allow.data.example.com", Port: 80}))
+	g.Expect(application.Status.WriteEndpointsMap["s3/new-dataset"].Hostname).To(gomega.Equal("write-new.data.example.com"))
+	// the module whose chart does not create a service named after the release is accessed inside the cluster
+	g.Expect(application.Status.ReadEndpointsMap["s3/redact-dataset"].Hostname).To(gomega.Equal(
+		utils.GenerateModuleEndpointFQDN("read-redact", utils.BlueprintNamespace)))
+
+	// the endpoint is reported inside the cluster again once its service is no longer registered
+	setExternalHostnames(application, map[string]string{"write-new": "write-new.data.example.com"})
+	g.Expect(application.Status.ReadEndpointsMap["s3/allow-dataset"].Hostname).To(gomega.Equal(
+		utils.GenerateModuleEndpointFQDN("read-allow", utils.BlueprintNamespace)))
+}
+
+// This test checks that the changes of a ready application are not applied while the manager is in read-only mode,
+// and that its status keeps reporting the application as ready with the changes pending.
+// The test modifies the environment and thus does not run in parallel.
+func TestReadOnlyModeKeepsStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+	g.Expect(os.Setenv(utils.ReadOnlyModeKey, "true")).To(gomega.Succeed())
+	defer os.Unsetenv(utils.ReadOnlyModeKey)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data[0].DataSetID = "s3/allow-dataset"
+	// the previous generation of the application is ready
+	application.Generation = 2
+	application.Status.ObservedGeneration = 1
+	application.Status.Ready = true
+	meta.SetStatusCondition(&application.Status.Conditions, metav1.Condition{
+		Type: app.ReadyCondition, Status: metav1.ConditionTrue, Reason: app.OrchestratedReason, ObservedGeneration: 1,
+	})
+	meta.SetStatusCondition(&application.Status.Conditions, metav1.Condition{
+		Type: app.ErrorCondition, Status: metav1.ConditionFalse, Reason: app.NominalReason, ObservedGeneration: 1,
+	})
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, application)
+	r := createTestM4DApplicationController(cl, s)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: application.Name, Namespace: application.Namespace}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, application)).To(gomega.Succeed())
+	g.Expect(application.Status.Ready).To(gomega.BeTrue())
+	g.Expect(application.Status.ObservedGeneration).To(gomega.BeEquivalentTo(1))
+	g.Expect(meta.IsStatusConditionTrue(application.Status.Conditions, app.ReadyCondition)).To(gomega.BeTrue())
+	g.Expect(meta.IsStatusConditionFalse(application.Status.Conditions, app.ErrorCondition)).To(gomega.BeTrue())
+	condition := meta.FindStatusCondition(application.Status.Conditions, app.ReadOnlyModeCondition)
+	g.Expect(condition).NotTo(gomega.BeNil())
+	g.Expect(condition.Status).To(gomega.Equal(metav1.ConditionTrue))
+	g.Expect(condition.Message).To(gomega.Equal(app.ReadOnlyMode))
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sync"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// States of the applications reported by the m4d_applications metric
+const (
+	readyState   = "ready"
+	deniedState  = "denied"
+	errorState   = "error"
+	pendingState = "pending"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "m4d_application_reconcile_duration_seconds",
+		Help:    "Duration of the reconciliation of M4DApplication resources",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+	})
+	applicationsByState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "m4d_applications",
+		Help: "Number of M4DApplication resources per state (ready, denied, error or pending)",
+	}, []string{"state"})
+
+	applicationStates = newStateTracker(applicationsByState)
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDuration, applicationsByState)
+}
+
+// applicationState returns the state of an application reported by the metrics.
+// Applications denied by the governance policies are distinguished from applications failing for other reasons.
+func applicationState(application *app.M4DApplication) string {
+	if evaluate.HasError(application) {
+		denied := meta.FindStatusCondition(application.Status.Conditions, app.DeniedCondition)
+		if denied != nil && denied.Status == metav1.ConditionTrue && denied.Reason == app.PolicyDeniedReason {
+			return deniedState
+		}
+		return errorState
+	}
+	if application.Status.Ready {
+		return readyState
+	}
+	return pendingState
+}
+
+// stateTracker keeps the last known state of each application to maintain the number of applications per state
+type stateTracker struct {
+	mutex  sync.Mutex
+	states map[types.NamespacedName]string
+	gauge  *prometheus.GaugeVec
+}
+
+func newStateTracker(gauge *prometheus.GaugeVec) *stateTracker {
+	for _, state := range []string{readyState, deniedState, errorState, pendingState} {
+		gauge.WithLabelValues(state).Set(0)
+	}
+	return &stateTracker{
+		states: make(map[types.NamespacedName]string),
+		gauge:  gauge,
+	}
+}
+
+// set records the current state of an application
+func (t *stateTracker) set(key types.NamespacedName, state string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if previous, found := t.states[key]; found {
+		if previous == state {
+			return
+		}
+		t.gauge.WithLabelValues(previous).Dec()
+	}
+	t.states[key] = state
+	t.gauge.WithLabelValues(state).Inc()
+}
+
+// remove forgets a deleted application
+func (t *stateTracker) remove(key types.NamespacedName) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if previous, found := t.states[key]; found {
+		t.gauge.WithLabelValues(previous).Dec()
+		delete(t.states, key)
+	}
+}
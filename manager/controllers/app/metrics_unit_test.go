@@ -0,0 +1,57 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestApplicationState(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(applicationState(application)).To(gomega.Equal(pendingState))
+	application.Status.Ready = true
+	g.Expect(applicationState(application)).To(gomega.Equal(readyState))
+
+	application.Status.Ready = false
+	evaluate.SetCondition(application, "s3/deny-dataset", app.ReadAccessDenied, true)
+	g.Expect(applicationState(application)).To(gomega.Equal(deniedState))
+
+	evaluate.ResetConditions(application)
+	evaluate.SetCondition(application, "s3/allow-dataset", "connection refused", false)
+	g.Expect(applicationState(application)).To(gomega.Equal(errorState))
+}
+
+func TestStateTracker(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_applications"}, []string{"state"})
+	tracker := newStateTracker(gauge)
+	first := types.NamespacedName{Name: "notebook", Namespace: "default"}
+	second := types.NamespacedName{Name: "batch", Namespace: "default"}
+
+	tracker.set(first, pendingState)
+	tracker.set(second, pendingState)
+	g.Expect(testutil.ToFloat64(gauge.WithLabelValues(pendingState))).To(gomega.Equal(2.0))
+
+	tracker.set(first, readyState)
+	tracker.set(first, readyState)
+	g.Expect(testutil.ToFloat64(gauge.WithLabelValues(pendingState))).To(gomega.Equal(1.0))
+	g.Expect(testutil.ToFloat64(gauge.WithLabelValues(readyState))).To(gomega.Equal(1.0))
+
+	tracker.remove(first)
+	tracker.remove(first)
+	g.Expect(testutil.ToFloat64(gauge.WithLabelValues(readyState))).To(gomega.Equal(0.0))
+	g.Expect(testutil.ToFloat64(gauge.WithLabelValues(deniedState))).To(gomega.Equal(0.0))
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// stepHealth aggregates the health of the pods of the workloads of a step, selected by the label selectors of the
+// workloads. It returns nil if the workloads run no pods, e.g. data transfers.
+// The pods of completed jobs are not counted.
+func (r *BlueprintReconciler) stepHealth(namespace string, resources []*unstructured.Unstructured) *app.ModuleHealth {
+	var health *app.ModuleHealth
+	counted := map[types.UID]bool{}
+	for _, res := range resources {
+		if !workloadKinds[res.GetKind()] {
+			continue
+		}
+		selector, found, err := unstructured.NestedStringMap(res.Object, "spec", "selector", "matchLabels")
+		if err != nil || !found || len(selector) == 0 {
+			continue
+		}
+		pods := &corev1.PodList{}
+		if err := r.podReader().List(context.Background(), pods, client.InNamespace(namespace), client.MatchingLabels(selector)); err != nil {
+			r.Log.V(0).Info("Could not list the pods of " + res.GetKind() + " " + res.GetName() + ": " + err.Error())
+			continue
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if counted[pod.UID] || pod.Status.Phase == corev1.PodSucceeded {
+				continue
+			}
+			counted[pod.UID] = true
+			if health == nil {
+				health = &app.ModuleHealth{}
+			}
+			health.Add(podHealth(pod))
+		}
+	}
+	return health
+}
+
+// podReader returns the reader of the pods of the modules, i.e. the cache restricted to the blueprint namespace
+func (r *BlueprintReconciler) podReader() client.Reader {
+	if r.Pods != nil {
+		return r.Pods
+	}
+	return r.Client
+}
+
+// podBlueprints maps a pod of a module to the blueprints deploying the workload that owns it,
+// such that the health of the modules is checked again when their pods change
+func (r *BlueprintReconciler) podBlueprints(pod client.Object) []reconcile.Request {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil
+	}
+	workloads := map[string]bool{owner.Kind + "/" + owner.Name: true}
+	// the pods of deployments and cron jobs are owned by the replica sets and the jobs that these create,
+	// whose names are suffixed by a hash or a timestamp
+	if i := strings.LastIndex(owner.Name, "-"); i > 0 {
+		switch owner.Kind {
+		case "ReplicaSet":
+			workloads["Deployment/"+owner.Name[:i]] = true
+		case "Job":
+			workloads["CronJob/"+owner.Name[:i]] = true
+		}
+	}
+	blueprints := &app.BlueprintList{}
+	if err := r.List(context.Background(), blueprints, client.InNamespace(pod.GetNamespace())); err != nil {
+		r.Log.V(0).Info("Could not list the blueprints of pod " + pod.GetName() + ": " + err.Error())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range blueprints.Items {
+		blueprint := &blueprints.Items[i]
+	steps:
+		for _, step := range blueprint.Status.Steps {
+			for _, workload := range step.Workloads {
+				if workloads[workload] {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{Namespace: blueprint.Namespace, Name: blueprint.Name}})
+					break steps
+				}
+			}
+		}
+	}
+	return requests
+}
+
+// podHealth returns the health of a single pod, as reported by the probes of its containers
+func podHealth(pod *corev1.Pod) *app.ModuleHealth {
+	health := &app.ModuleHealth{Pods: 1}
+	for _, status := range pod.Status.ContainerStatuses {
+		health.Restarts += status.RestartCount
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			health.ReadyPods = 1
+			return health
+		}
+	}
+	health.Message = unreadyMessage(pod)
+	return health
+}
+
+// unreadyMessage describes why a pod is not ready
+func unreadyMessage(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		switch {
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+			return "pod " + pod.Name + ": container " + status.Name + " is waiting: " + status.State.Waiting.Reason
+		case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+			return "pod " + pod.Name + ": container " + status.Name + " has terminated: " + status.State.Terminated.Reason
+		case !status.Ready:
+			return "pod " + pod.Name + ": container " + status.Name + " is not ready"
+		}
+	}
+	if pod.Status.Reason != "" {
+		return "pod " + pod.Name + ": " + pod.Status.Reason
+	}
+	return "pod " + pod.Name + " is " + string(pod.Status.Phase)
+}
+
+// stepAssets returns the assets served by a step, as annotated by the application controller
+func stepAssets(step *app.FlowStep) []string {
+	if assetID := step.Arguments.Annotations[app.DatasetIDsAnnotation]; assetID != "" {
+		return []string{assetID}
+	}
+	var assets []string
+	for _, read := range step.Arguments.Read {
+		assets = append(assets, read.AssetID)
+	}
+	for _, write := range step.Arguments.Write {
+		assets = append(assets, write.AssetID)
+	}
+	return assets
+}
+
+// addAssetHealth aggregates the health of a step into the health of the assets that the step serves
+func addAssetHealth(state *app.ObservedState, step *app.FlowStep, health *app.ModuleHealth) {
+	if health == nil {
+		return
+	}
+	for _, assetID := range stepAssets(step) {
+		if state.Health == nil {
+			state.Health = make(map[string]app.ModuleHealth)
+		}
+		aggregated := state.Health[assetID]
+		aggregated.Add(health)
+		state.Health[assetID] = aggregated
+	}
+}
+
+// aggregateBlueprintsHealth aggregates the health of the assets reported by the blueprints of all the clusters
+func aggregateBlueprintsHealth(blueprints map[string]app.MetaBlueprint) map[string]app.ModuleHealth {
+	clusters := make([]string, 0, len(blueprints))
+	for cluster := range blueprints {
+		clusters = append(clusters, cluster)
+	}
+	// the message of the first cluster is kept
+	sort.Strings(clusters)
+	var aggregated map[string]app.ModuleHealth
+	for _, cluster := range clusters {
+		for assetID, health := range blueprints[cluster].Status.ObservedState.Health {
+			if aggregated == nil {
+				aggregated = make(map[string]app.ModuleHealth)
+			}
+			assetHealth := aggregated[assetID]
+			assetHealth.Add(&health)
+			aggregated[assetID] = assetHealth
+		}
+	}
+	return aggregated
+}
+
+// setAssetsHealth reports in the asset states of the application the health of the modules serving the assets
+func setAssetsHealth(appContext *app.M4DApplication, health map[string]app.ModuleHealth) {
+	for assetID, state := range appContext.Status.AssetStates {
+		if _, found := health[assetID]; found {
+			continue
+		}
+		state.Health = nil
+		if len(state.Actions) == 0 {
+			delete(appContext.Status.AssetStates, assetID)
+		} else {
+			appContext.Status.AssetStates[assetID] = state
+		}
+	}
+	for assetID, assetHealth := range health {
+		if appContext.Status.AssetStates == nil {
+			appContext.Status.AssetStates = make(map[string]app.AssetState)
+		}
+		state := appContext.Status.AssetStates[assetID]
+		assetHealth := assetHealth
+		state.Health = &assetHealth
+		appContext.Status.AssetStates[assetID] = state
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func modulePod(name string, ready bool, restarts int32, waiting string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: utils.BlueprintNamespace, UID: types.UID(name),
+			Labels: map[string]string{"app": "arrow-flight"}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	status := corev1.ContainerStatus{Name: "server", Ready: ready, RestartCount: restarts}
+	if waiting != "" {
+		status.State.Waiting = &corev1.ContainerStateWaiting{Reason: waiting}
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{status}
+	condition := corev1.ConditionFalse
+	if ready {
+		condition = corev1.ConditionTrue
+	}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: condition}}
+	return pod
+}
+
+func TestModuleHealth(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	completed := modulePod("completed", false, 0, "")
+	completed.Status.Phase = corev1.PodSucceeded
+	other := modulePod("other", true, 0, "")
+	other.Labels = map[string]string{"app": "other"}
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g),
+		modulePod("ready", true, 1, ""), modulePod("crashing", false, 4, "CrashLoopBackOff"), completed, other)
+	r := &BlueprintReconciler{Client: cl, Log: ctrl.Log.WithName("test")}
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "arrow-flight"}}},
+	}}
+	deployment.SetKind("Deployment")
+	deployment.SetName("arrow-flight")
+	transfer := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	transfer.SetKind("BatchTransfer")
+	transfer.SetName("copy")
+
+	// the pods are aggregated, except those of completed jobs and of other workloads
+	health := r.stepHealth(utils.BlueprintNamespace, []*unstructured.Unstructured{deployment, transfer})
+	g.Expect(health).NotTo(gomega.BeNil())
+	g.Expect(health.Pods).To(gomega.Equal(int32(2)))
+	g.Expect(health.ReadyPods).To(gomega.Equal(int32(1)))
+	g.Expect(health.Restarts).To(gomega.Equal(int32(5)))
+	g.Expect(health.Message).To(gomega.Equal("pod crashing: container server is waiting: CrashLoopBackOff"))
+	// data transfers run no pods of their own
+	g.Expect(r.stepHealth(utils.BlueprintNamespace, []*unstructured.Unstructured{transfer})).To(gomega.BeNil())
+
+	// the health of the steps is aggregated per asset, then across the clusters
+	step := &app.FlowStep{Arguments: app.ModuleArguments{Annotations: map[string]string{app.DatasetIDsAnnotation: "s3/allow-dataset"}}}
+	state := &app.ObservedState{}
+	addAssetHealth(state, step, nil)
+	g.Expect(state.Health).To(gomega.BeNil())
+	addAssetHealth(state, step, health)
+	addAssetHealth(state, step, &app.ModuleHealth{Pods: 1, ReadyPods: 1})
+	g.Expect(state.Health).To(gomega.HaveKeyWithValue("s3/allow-dataset",
+		app.ModuleHealth{Pods: 3, ReadyPods: 2, Restarts: 5, Message: health.Message}))
+	blueprints := map[string]app.MetaBlueprint{
+		"neverland-cluster": {Status: app.BlueprintStatus{ObservedState: *state}},
+		"thegreendragon": {Status: app.BlueprintStatus{ObservedState: app.ObservedState{
+			Health: map[string]app.ModuleHealth{"s3/allow-dataset": {Pods: 1, ReadyPods: 1}, "s3/other-dataset": {Pods: 1}},
+		}}},
+	}
+	aggregated := aggregateBlueprintsHealth(blueprints)
+	g.Expect(aggregated).To(gomega.HaveLen(2))
+	g.Expect(aggregated["s3/allow-dataset"].Pods).To(gomega.Equal(int32(4)))
+	g.Expect(aggregated["s3/allow-dataset"].Message).To(gomega.Equal(health.Message))
+
+	// the health is reported in the asset states, which are dropped once they have neither actions nor health
+	application := &app.M4DApplication{Status: app.M4DApplicationStatus{AssetStates: map[string]app.AssetState{
+		"s3/redact-dataset":  {Actions: []app.AppliedAction{{Name: "redact", Flow: app.Read}}, Health: &app.ModuleHealth{Pods: 1}},
+		"s3/removed-dataset": {Health: &app.ModuleHealth{Pods: 1}},
+	}}}
+	setAssetsHealth(application, aggregated)
+	g.Expect(application.Status.AssetStates).To(gomega.HaveLen(3))
+	g.Expect(application.Status.AssetStates["s3/allow-dataset"].Health.ReadyPods).To(gomega.Equal(int32(3)))
+	g.Expect(application.Status.AssetStates["s3/other-dataset"].Health.ReadyPods).To(gomega.BeZero())
+	g.Expect(application.Status.AssetStates["s3/redact-dataset"].Health).To(gomega.BeNil())
+	g.Expect(application.Status.AssetStates["s3/redact-dataset"].Actions).To(gomega.HaveLen(1))
+}
+
+// TestPodBlueprints checks that the changes of the pods of the modules are mapped to the blueprints deploying their workloads
+func TestPodBlueprints(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	blueprint := &app.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: utils.BlueprintNamespace},
+		Status: app.BlueprintStatus{Steps: map[string]app.StepResources{
+			"read":  {Workloads: []string{"Deployment/arrow-flight"}},
+			"copy":  {Workloads: []string{"CronJob/sync", "BatchTransfer/copy"}},
+			"other": {Workloads: []string{"Deployment/arrow-flight"}},
+		}},
+	}
+	other := &app.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: utils.BlueprintNamespace}}
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), blueprint, other)
+	r := &BlueprintReconciler{Client: cl, Log: ctrl.Log.WithName("test")}
+
+	owned := func(kind, name string) *corev1.Pod {
+		pod := modulePod(name+"-pod", true, 0, "")
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+		return pod
+	}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: utils.BlueprintNamespace, Name: "notebook"}}
+	// the blueprint is enqueued once, whatever the number of its steps deploying the workload
+	g.Expect(r.podBlueprints(owned("ReplicaSet", "arrow-flight-5d8f7b"))).To(gomega.Equal([]ctrl.Request{request}))
+	g.Expect(r.podBlueprints(owned("Job", "sync-27182818"))).To(gomega.Equal([]ctrl.Request{request}))
+	g.Expect(r.podBlueprints(owned("ReplicaSet", "unknown-5d8f7b"))).To(gomega.BeEmpty())
+	g.Expect(r.podBlueprints(modulePod("orphan", true, 0, ""))).To(gomega.BeEmpty())
+}
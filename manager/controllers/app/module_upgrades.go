@@ -0,0 +1,69 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// modulesUpgraded returns true if a module deployed for the application has been upgraded with the Immediate policy,
+// or with the Manual policy if its upgrade has been approved or its installed version is not reported yet in the status
+func (r *M4DApplicationReconciler) modulesUpgraded(application *app.M4DApplication) bool {
+	if len(application.Status.DeployedModules) == 0 {
+		return false
+	}
+	moduleMap, err := r.GetAllModules()
+	if err != nil {
+		return false
+	}
+	approved := evaluate.ApprovedUpgrades(application)
+	for name, spec := range application.Status.DeployedModules {
+		spec := spec
+		module, found := moduleMap[name]
+		if !found || !evaluate.ModuleUpgraded(&spec, &module.Spec) {
+			continue
+		}
+		switch module.Spec.UpgradePolicy {
+		case app.ImmediateUpgrade:
+			return true
+		case app.ManualUpgrade:
+			if outdated, reported := application.Status.OutdatedModules[name]; approved[name] || !reported || outdated.Generation != module.Generation {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applicationsRunningModule maps a module to the applications deployed with another spec than its installed one,
+// if the module is upgraded immediately or manually. The applications are migrated at once with the Immediate policy,
+// and their status lists the outdated module with the Manual policy.
+func (r *M4DApplicationReconciler) applicationsRunningModule(a client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	module, ok := a.(*app.M4DModule)
+	if !ok || module.Namespace != utils.GetSystemNamespace() {
+		return requests
+	}
+	if module.Spec.UpgradePolicy != app.ImmediateUpgrade && module.Spec.UpgradePolicy != app.ManualUpgrade {
+		return requests
+	}
+	var applications app.M4DApplicationList
+	if err := r.List(context.Background(), &applications); err != nil {
+		r.Log.V(0).Info("Error while listing applications: " + err.Error())
+		return requests
+	}
+	for i := range applications.Items {
+		application := &applications.Items[i]
+		if spec, found := application.Status.DeployedModules[module.Name]; found && evaluate.ModuleUpgraded(&spec, &module.Spec) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(application)})
+		}
+	}
+	return requests
+}
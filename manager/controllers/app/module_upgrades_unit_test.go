@@ -0,0 +1,72 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestModulesUpgraded(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g))
+	r := &M4DApplicationReconciler{
+		Client: cl,
+		Log:    ctrl.Log.WithName("test"),
+	}
+	module := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-parquet.yaml", module)).NotTo(gomega.HaveOccurred())
+	module.Namespace = utils.GetSystemNamespace()
+	previous := *module.Spec.DeepCopy()
+	module.Spec.Chart = app.ChartSpec{Name: "ghcr.io/mesh-for-data/read-parquet:0.2.0"}
+	g.Expect(cl.Create(context.TODO(), module)).To(gomega.Succeed())
+
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"}}
+	application.Status.DeployedModules = map[string]app.M4DModuleSpec{module.Name: previous}
+	g.Expect(cl.Create(context.TODO(), application)).To(gomega.Succeed())
+
+	// the applications are not reconciled with the NextReconcile policy
+	g.Expect(r.modulesUpgraded(application)).To(gomega.BeFalse())
+	g.Expect(r.applicationsRunningModule(module)).To(gomega.BeEmpty())
+
+	// they are reconciled at once with the Immediate policy
+	module.Spec.UpgradePolicy = app.ImmediateUpgrade
+	g.Expect(cl.Update(context.TODO(), module)).To(gomega.Succeed())
+	g.Expect(r.modulesUpgraded(application)).To(gomega.BeTrue())
+	g.Expect(r.applicationsRunningModule(module)).To(gomega.HaveLen(1))
+
+	// with the Manual policy, they are reconciled until the outdated module is reported, and once the upgrade is approved
+	module.Spec.UpgradePolicy = app.ManualUpgrade
+	g.Expect(cl.Update(context.TODO(), module)).To(gomega.Succeed())
+	g.Expect(r.modulesUpgraded(application)).To(gomega.BeTrue())
+	application.Status.OutdatedModules = map[string]app.OutdatedModule{
+		module.Name: {Chart: module.Spec.Chart.Name, Generation: module.Generation}}
+	g.Expect(r.modulesUpgraded(application)).To(gomega.BeFalse())
+
+	// another upgrade keeping the chart is reported too
+	module.Spec.Chart.Values = map[string]string{"replicas": "2"}
+	module.Generation++
+	g.Expect(cl.Update(context.TODO(), module)).To(gomega.Succeed())
+	g.Expect(r.modulesUpgraded(application)).To(gomega.BeTrue())
+	application.Status.OutdatedModules[module.Name] = app.OutdatedModule{Chart: module.Spec.Chart.Name, Generation: module.Generation}
+	g.Expect(r.modulesUpgraded(application)).To(gomega.BeFalse())
+
+	application.Annotations = map[string]string{app.UpgradeModulesAnnotation: module.Name}
+	g.Expect(r.modulesUpgraded(application)).To(gomega.BeTrue())
+
+	// the applications running the installed spec are not reconciled, whatever the upgrade policy
+	application.Status.DeployedModules = map[string]app.M4DModuleSpec{module.Name: *module.Spec.DeepCopy()}
+	g.Expect(cl.Update(context.TODO(), application)).To(gomega.Succeed())
+	g.Expect(r.modulesUpgraded(application)).To(gomega.BeFalse())
+	g.Expect(r.applicationsRunningModule(module)).To(gomega.BeEmpty())
+}
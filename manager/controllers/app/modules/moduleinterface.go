@@ -5,20 +5,26 @@ package modules
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
 
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/capabilities"
 	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
 	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
 )
 
 // DataDetails is the information received from the catalog connector
 type DataDetails struct {
 	// Name of the asset
 	Name string
-	// Interface is the protocol and format
+	// Interface is the protocol and format of the asset in its location, the source of the data that is read
+	// and the destination of the data that is written
 	Interface app.InterfaceDetails
 	// Geography is the geo-location of the asset
 	Geography string
@@ -26,6 +32,10 @@ type DataDetails struct {
 	Connection serde.Arbitrary
 	// Metadata
 	Metadata *pb.DatasetMetadata
+	// SizeBytes is the size of the asset in bytes, 0 if unknown
+	SizeBytes int64
+	// LastModified is the time of the last modification of the asset, zero if unknown
+	LastModified time.Time
 }
 
 // DataInfo defines all the information about the given data set that comes from the m4dapplication spec and from the connectors.
@@ -34,7 +44,8 @@ type DataInfo struct {
 	DataDetails *DataDetails
 	// The path to Vault secret which holds the dataset credentials
 	VaultSecretPath string
-	// Pointer to the relevant data context in the M4D application spec
+	// Pointer to the relevant data context in the M4D application spec.
+	// The context of a dataset accessed in distinct flows is specific to each flow, with the interface requested in the flow.
 	Context *app.DataContext
 }
 
@@ -44,6 +55,17 @@ type ModuleInstanceSpec struct {
 	Args        *app.ModuleArguments
 	AssetID     string
 	ClusterName string
+	// ChainedTo is the name of the module reading the output of a module of a transformation chain.
+	// The endpoints of chained modules are not exposed to the workload.
+	ChainedTo string
+}
+
+// ChainLink is a module of a transformation chain, applying some of the actions required on read
+// before the data is passed on through its API
+type ChainLink struct {
+	Module  *app.M4DModule
+	API     *app.ModuleAPI
+	Actions []*pb.EnforcementAction
 }
 
 // Selector is responsible for finding an appropriate module
@@ -58,6 +80,21 @@ type Selector struct {
 	Actions []*pb.EnforcementAction
 	// Geography where the module will be orchestrated
 	Geo string
+	// PerformanceClass is the preferred class of the module, if any
+	PerformanceClass app.PerformanceClass
+	// ClassMismatch describes the class of the selected module if no module of the preferred class is available
+	ClassMismatch string
+	// Streaming restricts the selection of a read module to modules streaming the Source directly
+	Streaming bool
+	// Rejections explain why the examined modules have not been selected, as "<module>: <reason>"
+	Rejections []string
+	// Pinned is the name of the module pinned by an administrator, the only module that may be selected
+	Pinned string
+	// Chain lists the modules transforming the data before it is read by the read module, in the order of the data flow
+	Chain []ChainLink
+	// Versions maps the repositories of the charts of the modules to the semantic version ranges that may be selected.
+	// The pinned module is not subject to the ranges.
+	Versions map[string]string
 }
 
 // TODO: Add function to check if module supports recurrence type
@@ -102,15 +139,7 @@ func (m *Selector) AddModuleInstances(args *app.ModuleArguments, item DataInfo,
 func (m *Selector) SupportsGovernanceActions(module *app.M4DModule, actions []*pb.EnforcementAction) bool {
 	// Check that the governance actions match
 	for _, action := range actions {
-		supportsAction := false
-		for j := range module.Spec.Capabilities.Actions {
-			transformation := &module.Spec.Capabilities.Actions[j]
-			if transformation.ID == action.Id && transformation.Level == action.Level {
-				supportsAction = true
-				break
-			}
-		}
-		if !supportsAction {
+		if !m.SupportsGovernanceAction(module, action) {
 			return false
 		}
 	}
@@ -122,13 +151,31 @@ func (m *Selector) SupportsGovernanceAction(module *app.M4DModule, action *pb.En
 	// Check that the governance actions match
 	for j := range module.Spec.Capabilities.Actions {
 		transformation := &module.Spec.Capabilities.Actions[j]
-		if transformation.ID == action.Id && transformation.Level == action.Level {
+		if transformation.ID == action.Id && transformation.Level == action.Level && supportsActionArgs(transformation, action) {
 			return true
 		}
 	}
 	return false
 }
 
+// supportsActionArgs checks that the arguments of the action are valid against the schema declared by the module, if any.
+// The schema is compiled once, the same schemas are checked by every module selection.
+func supportsActionArgs(transformation *app.SupportedAction, action *pb.EnforcementAction) bool {
+	if transformation.ArgsSchema == nil || transformation.ArgsSchema.Data == nil {
+		return true
+	}
+	validator, err := taxonomy.CachedSchemaValidator(transformation.ArgsSchema.Data)
+	if err != nil {
+		// an invalid schema is not satisfied by any arguments
+		return false
+	}
+	args := action.Args
+	if args == nil {
+		args = map[string]string{}
+	}
+	return validator.Validate(args) == nil
+}
+
 // SupportsDependencies checks whether the module supports the dependency requirements
 func (m *Selector) SupportsDependencies(module *app.M4DModule, moduleMap map[string]*app.M4DModule) bool {
 	// check dependencies
@@ -151,46 +198,123 @@ func (m *Selector) SupportsDependencies(module *app.M4DModule, moduleMap map[str
 // SupportsInterface indicates whether the module supports interface requirements and dependencies
 func (m *Selector) SupportsInterface(module *app.M4DModule) bool {
 	// Check if the module supports the flow
-	if !utils.SupportsFlow(module.Spec.Flows, m.Flow) {
+	if !capabilities.SupportsFlow(module.Spec.Flows, m.Flow) {
 		return false
 	}
 	// Check if the source and sink protocols requested are supported
-	supportsInterface := false
-	if m.Flow == app.Read {
-		supportsInterface = module.Spec.Capabilities.API.DataFormat == m.Destination.DataFormat && module.Spec.Capabilities.API.Protocol == m.Destination.Protocol
-	} else if m.Flow == app.Copy {
-		for _, inter := range module.Spec.Capabilities.SupportedInterfaces {
-			if inter.Flow != m.Flow {
-				continue
-			}
-			if inter.Source.DataFormat != m.Source.DataFormat || inter.Source.Protocol != m.Source.Protocol {
-				continue
-			}
-			if inter.Sink.DataFormat != m.Destination.DataFormat || inter.Sink.Protocol != m.Destination.Protocol {
-				continue
-			}
-			supportsInterface = true
-			break
+	switch m.Flow {
+	case app.Read:
+		if m.Streaming {
+			return capabilities.SupportsStreamingRead(module, m.Source, m.Destination)
+		}
+		return capabilities.SupportsAPI(module, m.Destination)
+	case app.Copy:
+		return capabilities.SupportsCopy(module, m.Source, m.Destination)
+	case app.Write:
+		return capabilities.SupportsWrite(module, m.Source, m.Destination)
+	}
+	return false
+}
+
+// interfaceMismatch describes why the module does not support the interface requirements, or returns "" if it does
+func (m *Selector) interfaceMismatch(module *app.M4DModule) string {
+	if m.SupportsInterface(module) {
+		return ""
+	}
+	if !capabilities.SupportsFlow(module.Spec.Flows, m.Flow) {
+		return "does not support the " + string(m.Flow) + " flow"
+	}
+	switch m.Flow {
+	case app.Read:
+		if m.Streaming {
+			return "does not stream " + describeInterface(m.Source) + " to " + describeInterface(m.Destination)
+		}
+		return "exposes no API matching " + describeInterface(m.Destination)
+	case app.Copy:
+		return "does not copy " + describeInterface(m.Source) + " to " + describeInterface(m.Destination)
+	case app.Write:
+		return "does not write " + describeInterface(m.Source) + " to " + describeInterface(m.Destination)
+	}
+	return "does not support the " + string(m.Flow) + " flow"
+}
+
+// unsupportedAction returns the first of the actions that the module does not support, or nil if it supports all of them
+func (m *Selector) unsupportedAction(module *app.M4DModule, actions []*pb.EnforcementAction) *pb.EnforcementAction {
+	for _, action := range actions {
+		if !m.SupportsGovernanceAction(module, action) {
+			return action
 		}
 	}
-	return supportsInterface
+	return nil
 }
 
-// SelectModule finds the module that fits the requirements
+func (m *Selector) reject(module *app.M4DModule, reason string) {
+	m.Rejections = append(m.Rejections, module.Name+": "+reason)
+}
+
+func describeInterface(inter *app.InterfaceDetails) string {
+	if inter == nil {
+		return "any interface"
+	}
+	return inter.Protocol + "/" + inter.DataFormat
+}
+
+// SelectModule finds the module that fits the requirements.
+// Modules whose version is not in the range of their chart are rejected, and the latest version of a chart is preferred.
+// Modules of the preferred performance class are selected first. Otherwise a module of another class is selected,
+// and the mismatch is described by ClassMismatch.
+// The reasons for rejecting the other modules are recorded in Rejections.
 func (m *Selector) SelectModule(moduleMap map[string]*app.M4DModule) bool {
 	m.Message = ""
-	for _, module := range moduleMap {
-		if !m.SupportsInterface(module) {
+	m.ClassMismatch = ""
+	m.Rejections = nil
+	defer func() { sort.Strings(m.Rejections) }()
+	var mismatched []*app.M4DModule
+	if m.Pinned != "" && moduleMap[m.Pinned] == nil {
+		m.Message += string(m.Flow) + " : the pinned module " + m.Pinned + " is not installed"
+		return false
+	}
+	for _, module := range sortedModules(moduleMap) {
+		if m.Pinned != "" && module.Name != m.Pinned {
+			continue
+		}
+		if m.Pinned == "" {
+			if reason := m.VersionMismatch(module); reason != "" {
+				m.reject(module, reason)
+				continue
+			}
+		}
+		if reason := m.interfaceMismatch(module); reason != "" {
+			m.reject(module, reason)
 			continue
 		}
-		if !m.SupportsGovernanceActions(module, m.Actions) {
+		if action := m.unsupportedAction(module, m.Actions); action != nil {
+			m.reject(module, "does not support the action "+action.Id+" at the "+action.Level.String()+" level with the given arguments")
+			continue
+		}
+		if m.PerformanceClass != "" && module.Spec.Capabilities.PerformanceClass != m.PerformanceClass {
+			mismatched = append(mismatched, module)
 			continue
 		}
 		if !m.SupportsDependencies(module, moduleMap) {
+			m.reject(module, "has missing dependencies")
 			continue
 		}
 		return true
 	}
+	for _, module := range mismatched {
+		if !m.SupportsDependencies(module, moduleMap) {
+			m.reject(module, "has missing dependencies")
+			continue
+		}
+		class := string(module.Spec.Capabilities.PerformanceClass)
+		if class == "" {
+			class = "unspecified"
+		}
+		m.ClassMismatch = fmt.Sprintf("no %s module of class %s is available, module %s of class %s has been selected",
+			m.Flow, m.PerformanceClass, module.Name, class)
+		return true
+	}
 	m.Message += string(m.Flow) + " : " + app.ModuleNotFound
 	return false
 }
@@ -221,19 +345,56 @@ func CheckDependencies(module *app.M4DModule, moduleMap map[string]*app.M4DModul
 // Read is done at target (processing geography)
 // Copy is done at source when transformations are required, and at target - otherwise
 // Write is done at target
-func (m *Selector) SelectCluster(item DataInfo, clusters []multicluster.Cluster) (string, error) {
+// Among the clusters in the geography, the cluster with the lowest score is selected.
+func (m *Selector) SelectCluster(item DataInfo, clusters []multicluster.Cluster, scoring ClusterScoring) (string, error) {
 	geo := item.DataDetails.Geography
-	if m.Flow == app.Read {
+	if m.Flow == app.Read || m.Flow == app.Write {
 		geo = m.Geo
 	} else if m.Flow == app.Copy && len(m.Actions) == 0 {
 		geo = m.Geo
 	}
+	// the modules serving a dataset with a required geography run in that geography, even if they transform the data
+	if required := item.Context.Requirements.Geography; required != "" {
+		geo = required
+	}
+	selected := ""
+	var lowest float64
 	for _, cluster := range clusters {
-		if cluster.Metadata.Region == geo {
-			return cluster.Name, nil
+		if cluster.Metadata.Region != geo {
+			continue
 		}
+		// the first of the clusters with the same score is selected
+		if score := scoring.Score(cluster); selected == "" || score < lowest {
+			selected = cluster.Name
+			lowest = score
+		}
+	}
+	if selected == "" {
+		return "", errors.New(app.InvalidClusterConfiguration + "\nNo clusters have been found for running " + m.Module.Name + " in " + geo)
 	}
-	return "", errors.New(app.InvalidClusterConfiguration + "\nNo clusters have been found for running " + m.Module.Name + " in " + geo)
+	return selected, nil
+}
+
+// ClusterScoring weighs the cost and the capacity of the clusters in which a module can run
+type ClusterScoring struct {
+	// CostTier is the weight of the relative price of the compute resources of a cluster
+	CostTier float64
+	// Capacity is the weight of the free capacity of a cluster, which lowers its score
+	Capacity float64
+	// Egress is the weight of the price of the network traffic leaving a cluster
+	Egress float64
+}
+
+// DefaultClusterScoring weighs the price of the compute resources and of the network traffic equally,
+// and ignores the capacity of the clusters
+func DefaultClusterScoring() ClusterScoring {
+	return ClusterScoring{CostTier: 1, Capacity: 0, Egress: 1}
+}
+
+// Score returns the score of a cluster. Clusters with lower scores are cheaper.
+func (s ClusterScoring) Score(cluster multicluster.Cluster) float64 {
+	cost := cluster.Metadata.Cost
+	return s.CostTier*cost.Tier + s.Egress*cost.Egress - s.Capacity*cost.Capacity
 }
 
 // Transforms a CatalogDatasetInfo into a DataDetails struct
@@ -248,7 +409,11 @@ func CatalogDatasetToDataDetails(response *pb.CatalogDatasetInfo) (*DataDetails,
 		return nil, err
 	}
 	format := details.DataFormat
-	connection := serde.NewArbitrary(details.DataStore)
+	connection := serde.NewCompactArbitrary(details.DataStore, utils.GetCompactEncoding())
+	var lastModified time.Time
+	if details.LastModified > 0 {
+		lastModified = time.Unix(details.LastModified, 0)
+	}
 
 	return &DataDetails{
 		Name: details.Name,
@@ -256,8 +421,10 @@ func CatalogDatasetToDataDetails(response *pb.CatalogDatasetInfo) (*DataDetails,
 			Protocol:   protocol,
 			DataFormat: format,
 		},
-		Geography:  details.Geo,
-		Connection: *connection,
-		Metadata:   details.Metadata,
+		Geography:    details.Geo,
+		Connection:   *connection,
+		Metadata:     details.Metadata,
+		SizeBytes:    details.SizeBytes,
+		LastModified: lastModified,
 	}, nil
 }
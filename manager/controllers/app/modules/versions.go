@@ -0,0 +1,91 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package modules
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+)
+
+// ChartVersion splits the chart of a module into its repository and its tag,
+// e.g. ghcr.io/mesh-for-data/arrow-flight-module and 0.2.0.
+// The tag is empty if the chart is referenced by digest or has no tag.
+func ChartVersion(chart string) (string, string) {
+	if at := strings.LastIndex(chart, "@"); at >= 0 {
+		return chart[:at], ""
+	}
+	colon := strings.LastIndex(chart, ":")
+	if colon < 0 || colon < strings.LastIndex(chart, "/") {
+		return chart, ""
+	}
+	return chart[:colon], chart[colon+1:]
+}
+
+// moduleVersion returns the repository of the chart of a module and its semantic version, nil if its tag is not one
+func moduleVersion(module *app.M4DModule) (string, *semver.Version) {
+	repository, tag := ChartVersion(module.Spec.Chart.Name)
+	version, err := semver.NewVersion(tag)
+	if err != nil {
+		return repository, nil
+	}
+	return repository, version
+}
+
+// VersionMismatch returns the reason for which the version of a module does not satisfy the version range
+// of its chart, or an empty string if it does or if no range is set for its chart
+func (m *Selector) VersionMismatch(module *app.M4DModule) string {
+	repository, version := moduleVersion(module)
+	versions, found := m.Versions[repository]
+	if !found {
+		return ""
+	}
+	constraints, err := semver.NewConstraint(versions)
+	if err != nil {
+		return "the version range " + versions + " of its chart is invalid"
+	}
+	if version == nil {
+		return "its chart has no semantic version, as required by the range " + versions
+	}
+	if !constraints.Check(version) {
+		return "version " + version.Original() + " is not in the range " + versions
+	}
+	return ""
+}
+
+// sortedModules orders the modules by the repositories of their charts, and the versions of a chart from the latest,
+// such that the latest version of a module is selected among the ones satisfying the requirements.
+// Modules without a semantic version follow the other versions of their chart.
+func sortedModules(moduleMap map[string]*app.M4DModule) []*app.M4DModule {
+	type versionedModule struct {
+		module     *app.M4DModule
+		repository string
+		version    *semver.Version
+	}
+	sorted := make([]versionedModule, 0, len(moduleMap))
+	for _, module := range moduleMap {
+		repository, version := moduleVersion(module)
+		sorted = append(sorted, versionedModule{module: module, repository: repository, version: version})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.repository != b.repository {
+			return a.repository < b.repository
+		}
+		if a.version != nil && b.version != nil && !a.version.Equal(b.version) {
+			return a.version.GreaterThan(b.version)
+		}
+		if (a.version == nil) != (b.version == nil) {
+			return a.version != nil
+		}
+		return a.module.Name < b.module.Name
+	})
+	result := make([]*app.M4DModule, len(sorted))
+	for i := range sorted {
+		result[i] = sorted[i].module
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// NamespaceScope restricts the namespaces whose applications are reconciled by the manager, such that several scoped
+// control planes may share a cluster, or the applications of system namespaces are left alone.
+type NamespaceScope struct {
+	// Watched lists the namespaces whose applications are reconciled, all the namespaces if empty
+	Watched []string
+	// Ignored lists the namespaces whose applications are never reconciled
+	Ignored []string
+}
+
+// NewNamespaceScope returns the scope of the watched and ignored namespaces, or nil if all the namespaces are reconciled
+func NewNamespaceScope(watched []string, ignored []string) *NamespaceScope {
+	if len(watched) == 0 && len(ignored) == 0 {
+		return nil
+	}
+	return &NamespaceScope{Watched: watched, Ignored: ignored}
+}
+
+// Includes returns true if the applications of the namespace are reconciled. A nil scope includes all the namespaces.
+func (s *NamespaceScope) Includes(namespace string) bool {
+	if s == nil {
+		return true
+	}
+	for _, ignored := range s.Ignored {
+		if ignored == namespace {
+			return false
+		}
+	}
+	if len(s.Watched) == 0 {
+		return true
+	}
+	for _, watched := range s.Watched {
+		if watched == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCache returns a cache limited to the watched namespaces and to the namespaces of the control plane, e.g. the
+// system namespace holding the modules, or nil if all the namespaces are watched.
+// The ignored namespaces are filtered out by the reconciler, since a cache can not exclude namespaces.
+func (s *NamespaceScope) NewCache(controlPlaneNamespaces ...string) cache.NewCacheFunc {
+	if s == nil || len(s.Watched) == 0 {
+		return nil
+	}
+	namespaces := []string{}
+	seen := map[string]bool{}
+	for _, namespace := range append(append([]string{}, s.Watched...), controlPlaneNamespaces...) {
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	return cache.MultiNamespacedCacheBuilder(namespaces)
+}
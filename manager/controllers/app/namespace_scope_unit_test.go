@@ -0,0 +1,42 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestNamespaceScope(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	var all *NamespaceScope
+	g.Expect(NewNamespaceScope(nil, nil)).To(gomega.BeNil())
+	g.Expect(all.Includes("default")).To(gomega.BeTrue())
+	g.Expect(all.NewCache("m4d-system")).To(gomega.BeNil())
+
+	ignoring := NewNamespaceScope(nil, []string{"kube-system"})
+	g.Expect(ignoring.Includes("default")).To(gomega.BeTrue())
+	g.Expect(ignoring.Includes("kube-system")).To(gomega.BeFalse())
+	// a cache can not exclude namespaces
+	g.Expect(ignoring.NewCache("m4d-system")).To(gomega.BeNil())
+
+	watching := NewNamespaceScope([]string{"team-a", "team-b"}, []string{"team-b"})
+	g.Expect(watching.Includes("team-a")).To(gomega.BeTrue())
+	g.Expect(watching.Includes("team-b")).To(gomega.BeFalse())
+	g.Expect(watching.Includes("default")).To(gomega.BeFalse())
+	g.Expect(watching.NewCache("m4d-system", utils.BlueprintNamespace, "")).NotTo(gomega.BeNil())
+
+	// the applications outside of the scope are not reconciled
+	r := &M4DApplicationReconciler{Log: ctrl.Log.WithName("test"), Namespaces: watching}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "notebook", Namespace: "default"}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(result).To(gomega.Equal(ctrl.Result{}))
+}
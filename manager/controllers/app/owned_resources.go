@@ -0,0 +1,112 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ownedGeneratedResource returns the reference of the resource generated for an application, e.g. a Plotter
+func ownedGeneratedResource(ref *app.ResourceReference) app.OwnedResource {
+	return app.OwnedResource{Kind: ref.Kind, Name: ref.Name, Namespace: ref.Namespace}
+}
+
+// referencedResources returns the resources that the status of the application references,
+// i.e. the generated resource and the Dataset resources of the provisioned storage
+func referencedResources(application *app.M4DApplication) []app.OwnedResource {
+	var resources []app.OwnedResource
+	if application.Status.Generated != nil {
+		resources = append(resources, ownedGeneratedResource(application.Status.Generated))
+	}
+	datasetIDs := make([]string, 0, len(application.Status.ProvisionedStorage))
+	for datasetID := range application.Status.ProvisionedStorage {
+		datasetIDs = append(datasetIDs, datasetID)
+	}
+	sort.Strings(datasetIDs)
+	for _, datasetID := range datasetIDs {
+		details := application.Status.ProvisionedStorage[datasetID]
+		resources = append(resources, evaluate.OwnedDataset(details.DatasetRef, details.StorageType))
+	}
+	return resources
+}
+
+// isDetached returns true if the resource is no longer owned by the application although it is not referenced,
+// i.e. the Dataset resource of a copy promoted to a standalone dataset
+func isDetached(application *app.M4DApplication, resource app.OwnedResource) bool {
+	if resource.Kind != storage.DatasetKind {
+		return false
+	}
+	for _, datasetRef := range application.Status.PromotedCopies {
+		if datasetRef == resource.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// persistOwnedResources records the resources in the status of the application before they are created.
+// Only the owned resources are patched, the rest of the status is updated at the end of the reconcile.
+func (r *M4DApplicationReconciler) persistOwnedResources(application *app.M4DApplication, resources ...app.OwnedResource) error {
+	base := application.DeepCopy()
+	if !evaluate.AddOwnedResources(application, resources...) {
+		return nil
+	}
+	patched := base.DeepCopy()
+	patched.Status.OwnedResources = append([]app.OwnedResource{}, application.Status.OwnedResources...)
+	if err := r.Client.Status().Patch(context.Background(), patched, client.MergeFrom(base)); err != nil {
+		return err
+	}
+	// the final update of the status does not conflict with the patch
+	application.ResourceVersion = patched.ResourceVersion
+	return nil
+}
+
+// deleteOwnedResource deletes a resource owned by the application. A resource that is already deleted is ignored.
+func (r *M4DApplicationReconciler) deleteOwnedResource(resource app.OwnedResource) error {
+	var err error
+	if resource.Kind == storage.DatasetKind {
+		ref := &types.NamespacedName{Name: resource.Name, Namespace: resource.Namespace}
+		err = storage.ForType(r.Provision, resource.StorageType).DeleteDataset(ref)
+	} else {
+		err = r.ResourceInterface.DeleteResource(&app.ResourceReference{Name: resource.Name, Namespace: resource.Namespace, Kind: resource.Kind})
+	}
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// collectOrphanedResources deletes the owned resources that the application no longer references, e.g. the Dataset
+// resources created before the manager restarted in the middle of a reconcile, or provisioned by an evaluation
+// that has failed, and records the referenced resources as owned.
+// The resources that can not be deleted remain owned, and their deletion is retried in the next reconcile.
+func (r *M4DApplicationReconciler) collectOrphanedResources(application *app.M4DApplication) {
+	referenced := referencedResources(application)
+	owned := append([]app.OwnedResource{}, referenced...)
+	for _, resource := range application.Status.OwnedResources {
+		if evaluate.ContainsResource(referenced, resource) || isDetached(application, resource) {
+			continue
+		}
+		if err := r.deleteOwnedResource(resource); err != nil {
+			r.Log.V(0).Info("Could not delete the orphaned "+resource.Kind+" "+resource.Namespace+"/"+resource.Name+": "+err.Error(),
+				"m4dapplication", application.Namespace+"/"+application.Name)
+			owned = append(owned, resource)
+			continue
+		}
+		r.Log.V(0).Info("Deleted the orphaned "+resource.Kind+" "+resource.Namespace+"/"+resource.Name,
+			"m4dapplication", application.Namespace+"/"+application.Name)
+	}
+	if len(owned) == 0 {
+		owned = nil
+	}
+	application.Status.OwnedResources = owned
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOwnedResources(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"}}
+	plotter := &app.Plotter{ObjectMeta: metav1.ObjectMeta{Name: "notebook-default", Namespace: utils.GetSystemNamespace()}}
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), application, plotter)
+	provision := storage.NewProvisionTest()
+	owner := &types.NamespacedName{Name: application.Name, Namespace: application.Namespace}
+	for _, name := range []string{"bucket-1", "bucket-2", "bucket-3"} {
+		g.Expect(provision.CreateDataset(getBucketResourceRef(name), &storage.ProvisionedStorage{Name: name}, owner)).To(gomega.Succeed())
+	}
+	r := &M4DApplicationReconciler{
+		Client:            cl,
+		Log:               ctrl.Log.WithName("test"),
+		Provision:         provision,
+		ResourceInterface: NewPlotterInterface(cl),
+	}
+	g.Expect(cl.Get(context.Background(), *owner, application)).To(gomega.Succeed())
+
+	// the resources are recorded in the status before they are created, and only once
+	resourceRef := r.ResourceInterface.CreateResourceReference(&app.ResourceReference{Name: application.Name, Namespace: application.Namespace})
+	resources := []app.OwnedResource{
+		ownedGeneratedResource(resourceRef), evaluate.OwnedDataset("bucket-1", ""), evaluate.OwnedDataset("bucket-2", ""), evaluate.OwnedDataset("bucket-3", ""),
+	}
+	g.Expect(r.persistOwnedResources(application, resources...)).To(gomega.Succeed())
+	g.Expect(r.persistOwnedResources(application, resources[0])).To(gomega.Succeed())
+	stored := &app.M4DApplication{}
+	g.Expect(cl.Get(context.Background(), *owner, stored)).To(gomega.Succeed())
+	g.Expect(stored.Status.OwnedResources).To(gomega.Equal(resources))
+	g.Expect(application.ResourceVersion).To(gomega.Equal(stored.ResourceVersion))
+
+	// the orphaned resources are deleted, except the copies promoted to standalone datasets
+	application.Status.Generated = resourceRef
+	application.Status.ProvisionedStorage = map[string]app.DatasetDetails{"s3/allow-dataset": {DatasetRef: "bucket-1"}}
+	application.Status.PromotedCopies = map[string]string{"s3/promoted-dataset": "bucket-3"}
+	r.collectOrphanedResources(application)
+	g.Expect(application.Status.OwnedResources).To(gomega.Equal(resources[:2]))
+	_, err := provision.GetDatasetStatus(getBucketResourceRef("bucket-2"))
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = provision.GetDatasetStatus(getBucketResourceRef("bucket-3"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// a resource that has already been deleted is no longer owned
+	evaluate.AddOwnedResources(application, evaluate.OwnedDataset("bucket-2", ""))
+	r.collectOrphanedResources(application)
+	g.Expect(application.Status.OwnedResources).To(gomega.Equal(resources[:2]))
+
+	// all the owned resources are deleted with the application
+	evaluate.AddOwnedResources(application, evaluate.OwnedDataset("bucket-4", ""))
+	g.Expect(provision.CreateDataset(getBucketResourceRef("bucket-4"), &storage.ProvisionedStorage{Name: "bucket-4"}, owner)).To(gomega.Succeed())
+	g.Expect(r.deleteExternalResources(application)).To(gomega.Succeed())
+	g.Expect(application.Status.OwnedResources).To(gomega.BeNil())
+	g.Expect(application.Status.Generated).To(gomega.BeNil())
+	g.Expect(r.ResourceInterface.ResourceExists(resourceRef)).To(gomega.BeFalse())
+	for _, name := range []string{"bucket-1", "bucket-4"} {
+		_, err = provision.GetDatasetStatus(getBucketResourceRef(name))
+		g.Expect(err).To(gomega.HaveOccurred(), name)
+	}
+	_, err = provision.GetDatasetStatus(getBucketResourceRef("bucket-3"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
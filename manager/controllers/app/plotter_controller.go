@@ -7,12 +7,15 @@ import (
 	"context"
 	"math"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
 	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,10 +32,16 @@ type PlotterReconciler struct {
 	Log            logr.Logger
 	Scheme         *runtime.Scheme
 	ClusterManager multicluster.ClusterManager
+	// Options tunes the concurrency and the retries of the controller
+	Options ControllerOptions
 }
 
-// BlueprintNamespace defines a namespace where blueprints and associated resources will be allocated
-const BlueprintNamespace = "m4d-blueprints"
+const (
+	// defaultRetryBackoff is the delay before the first retry of failing blueprints if the retry policy does not set one
+	defaultRetryBackoff = 5 * time.Second
+	// maxRetryBackoff bounds the exponential backoff between retries
+	maxRetryBackoff = 5 * time.Minute
+)
 
 // Reconcile receives a Plotter CRD
 //nolint:dupl
@@ -88,6 +97,9 @@ func (r *PlotterReconciler) reconcileFinalizers(plotter *app.Plotter) error {
 	if !plotter.DeletionTimestamp.IsZero() {
 		// The object is being deleted
 		if hasFinalizer { // Finalizer was created when the object was created
+			if utils.IsReadOnlyMode() {
+				return errors.New("the manager is in read-only mode, the blueprints can not be deleted")
+			}
 			// the finalizer is present - delete the allocated resources
 
 			for cluster, blueprint := range plotter.Status.Blueprints {
@@ -117,111 +129,268 @@ func (r *PlotterReconciler) reconcileFinalizers(plotter *app.Plotter) error {
 	return nil
 }
 
-func (r *PlotterReconciler) reconcile(plotter *app.Plotter) (ctrl.Result, []error) {
-	if plotter.Status.Blueprints == nil {
-		plotter.Status.Blueprints = make(map[string]app.MetaBlueprint)
+// blueprintResult is the outcome of reconciling the blueprint of a single cluster
+type blueprintResult struct {
+	cluster string
+	// metaBlueprint is the new blueprint status, or nil if the status should not be changed
+	metaBlueprint *app.MetaBlueprint
+	ready         bool
+	// observedError is the error reported by the remote blueprint
+	observedError string
+	// rerunning is true if the failed jobs of the remote blueprint are still to be run again
+	rerunning bool
+	// err is the error received while creating, updating or fetching the remote blueprint
+	err error
+}
+
+// reconcileBlueprint creates or updates the remote blueprint of the given cluster and collects its status.
+// The existing blueprints of the clusters that have not been changed by the spec are only read, since their spec
+// and their status do not depend on the application version.
+// It does not modify the plotter, as blueprints of different clusters are reconciled concurrently.
+// The blueprints of a plotter whose retries are exhausted are only read, as in the read-only mode.
+func (r *PlotterReconciler) reconcileBlueprint(plotter *app.Plotter, cluster string, blueprintSpec app.BlueprintSpec, changed bool) blueprintResult {
+	result := blueprintResult{cluster: cluster}
+	readOnly := utils.IsReadOnlyMode() || plotter.Status.ObservedState.Failed
+	r.Log.V(1).Info("Handling spec for cluster " + cluster)
+	releaseNames, err := utils.GetReleaseNames(plotter.Labels[app.ApplicationNameLabel], plotter.Labels[app.ApplicationNamespaceLabel],
+		blueprintSpec.Flow.Steps)
+	if err != nil {
+		r.Log.Error(err, "Release names of the blueprint are not unique", "cluster", cluster)
+		result.err = err
+		return result
 	}
+	// the blueprints of unreachable clusters are not handled, and the failed health check is reported for the cluster
+	if checker, ok := r.ClusterManager.(multicluster.ClusterHealthChecker); ok {
+		if err := checker.CheckHealth(cluster); err != nil {
+			r.Log.Error(err, "Cluster is unhealthy", "cluster", cluster)
+			result.err = err
+			return result
+		}
+	}
+	if blueprint, exists := plotter.Status.Blueprints[cluster]; exists {
+		r.Log.V(2).Info("Found status for cluster " + cluster)
+
+		remoteBlueprint, err := r.ClusterManager.GetBlueprint(cluster, blueprint.Namespace, blueprint.Name)
+		if err != nil {
+			r.Log.Error(err, "Could not fetch blueprint", "name", blueprint.Name)
+			result.err = err
+			return result
+		}
 
-	plotter.Status.ObservedState.Error = "" // Reset error state
-	// Reconciliation loop per cluster
-	isReady := true
+		if remoteBlueprint == nil {
+			r.Log.Info("Could not yet find remote blueprint")
+			return result
+		}
 
-	var errorCollection []error
-	for cluster, blueprintSpec := range plotter.Spec.Blueprints {
-		r.Log.V(1).Info("Handling spec for cluster " + cluster)
-		if blueprint, exists := plotter.Status.Blueprints[cluster]; exists {
-			r.Log.V(2).Info("Found status for cluster " + cluster)
+		r.Log.V(2).Info("Remote blueprint: ", "rbp", remoteBlueprint)
 
-			remoteBlueprint, err := r.ClusterManager.GetBlueprint(cluster, blueprint.Namespace, blueprint.Name)
-			if err != nil {
-				r.Log.Error(err, "Could not fetch blueprint", "name", blueprint.Name)
-				errorCollection = append(errorCollection, err)
-				isReady = false
-				continue
+		if !reflect.DeepEqual(blueprintSpec, remoteBlueprint.Spec) {
+			r.Log.V(1).Info("Blueprint specs differ",
+				"plotter.generation", plotter.Generation,
+				"plotter.observedGeneration", plotter.Status.ObservedGeneration)
+			if plotter.Generation != plotter.Status.ObservedGeneration && changed {
+				if readOnly {
+					r.Log.V(1).Info("Not updating blueprint in read-only mode")
+					return result
+				}
+				r.Log.V(1).Info("Updating blueprint...")
+				remoteBlueprint.Spec = blueprintSpec
+				remoteBlueprint.ObjectMeta.Annotations = map[string]string(nil) // reset annotations
+				setApplicationVersion(remoteBlueprint, plotter)
+				err := r.ClusterManager.UpdateBlueprint(cluster, remoteBlueprint)
+				if err != nil {
+					r.Log.Error(err, "Could not update blueprint", "newSpec", blueprintSpec)
+					result.err = err
+					return result
+				}
+				// Update meta blueprint without state as changes occur
+				// Plotter cannot be ready if changes were just applied
+				metaBlueprint := app.CreateMetaBlueprintWithoutState(remoteBlueprint)
+				metaBlueprint.ReleaseNames = releaseNames
+				result.metaBlueprint = &metaBlueprint
+				return result
 			}
+			r.Log.V(1).Info("Not updating blueprint as generation did not change")
+			return result
+		}
 
-			if remoteBlueprint == nil {
-				r.Log.Info("Could not yet find remote blueprint")
-				isReady = false
-				continue // Continue with next blueprint
+		// the status of a changed blueprint is propagated once the blueprint has been generated for the application version
+		// of the plotter, and its latest spec has been observed
+		if appVersion := plotter.Labels[app.ApplicationVersionLabel]; changed && remoteBlueprint.Labels[app.ApplicationVersionLabel] != appVersion {
+			r.Log.V(1).Info("Blueprint was generated for another application version", "appVersion", appVersion,
+				"blueprint.appVersion", remoteBlueprint.Labels[app.ApplicationVersionLabel])
+			if readOnly {
+				return result
 			}
-
-			r.Log.V(2).Info("Remote blueprint: ", "rbp", remoteBlueprint)
-
-			if !reflect.DeepEqual(blueprintSpec, remoteBlueprint.Spec) {
-				r.Log.V(1).Info("Blueprint specs differ",
-					"plotter.generation", plotter.Generation,
-					"plotter.observedGeneration", plotter.Status.ObservedGeneration)
-				if plotter.Generation != plotter.Status.ObservedGeneration {
-					r.Log.V(1).Info("Updating blueprint...")
-					remoteBlueprint.Spec = blueprintSpec
-					remoteBlueprint.ObjectMeta.Annotations = map[string]string(nil) // reset annotations
-					err := r.ClusterManager.UpdateBlueprint(cluster, remoteBlueprint)
-					if err != nil {
-						r.Log.Error(err, "Could not update blueprint", "newSpec", blueprintSpec)
-						errorCollection = append(errorCollection, err)
-						isReady = false
-						continue
-					}
-					// Update meta blueprint without state as changes occur
-					plotter.Status.Blueprints[cluster] = app.CreateMetaBlueprintWithoutState(remoteBlueprint)
-					// Plotter cannot be ready if changes were just applied
-					isReady = false
-					continue // Continue with next blueprint
-				}
-				r.Log.V(1).Info("Not updating blueprint as generation did not change")
-				isReady = false
-				continue
+			setApplicationVersion(remoteBlueprint, plotter)
+			if err := r.ClusterManager.UpdateBlueprint(cluster, remoteBlueprint); err != nil {
+				r.Log.Error(err, "Could not update the application version of blueprint", "name", remoteBlueprint.Name)
+				result.err = err
+				return result
 			}
+			metaBlueprint := app.CreateMetaBlueprintWithoutState(remoteBlueprint)
+			metaBlueprint.ReleaseNames = releaseNames
+			result.metaBlueprint = &metaBlueprint
+			return result
+		}
+		if remoteBlueprint.Status.ObservedGeneration != remoteBlueprint.Generation {
+			r.Log.V(1).Info("Status of remote blueprint is of an older generation",
+				"blueprint.generation", remoteBlueprint.Generation, "blueprint.observedGeneration", remoteBlueprint.Status.ObservedGeneration)
+			metaBlueprint := app.CreateMetaBlueprintWithoutState(remoteBlueprint)
+			metaBlueprint.ReleaseNames = releaseNames
+			result.metaBlueprint = &metaBlueprint
+			return result
+		}
 
-			r.Log.V(2).Info("Status of remote blueprint ", "status", remoteBlueprint.Status)
+		r.Log.V(2).Info("Status of remote blueprint ", "status", remoteBlueprint.Status)
 
-			plotter.Status.Blueprints[cluster] = app.CreateMetaBlueprint(remoteBlueprint)
+		metaBlueprint := app.CreateMetaBlueprint(remoteBlueprint)
+		metaBlueprint.ReleaseNames = releaseNames
+		result.metaBlueprint = &metaBlueprint
+		result.ready = remoteBlueprint.Status.ObservedState.Ready
+		// If Blueprint has an error set it as status of plotter
+		result.observedError = remoteBlueprint.Status.ObservedState.Error
+		result.rerunning = rerunsPending(&remoteBlueprint.Status)
+		return result
+	}
 
-			if !remoteBlueprint.Status.ObservedState.Ready {
-				isReady = false
-			}
+	r.Log.V(2).Info("Found no status for cluster " + cluster)
+	if readOnly {
+		r.Log.V(1).Info("Not creating blueprint in read-only mode", "cluster", cluster)
+		return result
+	}
+	blueprint := &app.Blueprint{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Blueprint",
+			APIVersion: "app.m4d.ibm.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        plotter.Name,
+			Namespace:   utils.BlueprintNamespace,
+			ClusterName: cluster,
+			Labels: map[string]string{
+				"razee/watch-resource":        "debug",
+				app.ApplicationNameLabel:      plotter.Labels[app.ApplicationNameLabel],
+				app.ApplicationNamespaceLabel: plotter.Labels[app.ApplicationNamespaceLabel],
+			},
+		},
+		Spec: blueprintSpec,
+	}
+	setApplicationVersion(blueprint, plotter)
 
-			// If Blueprint has an error set it as status of plotter
-			if remoteBlueprint.Status.ObservedState.Error != "" {
-				plotter.Status.ObservedState.Error = remoteBlueprint.Status.ObservedState.Error
-			}
-		} else {
-			r.Log.V(2).Info("Found no status for cluster " + cluster)
-			blueprint := &app.Blueprint{
-				TypeMeta: metav1.TypeMeta{
-					Kind:       "Blueprint",
-					APIVersion: "app.m4d.ibm.com/v1alpha1",
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name:        plotter.Name,
-					Namespace:   BlueprintNamespace,
-					ClusterName: cluster,
-					Labels: map[string]string{
-						"razee/watch-resource":        "debug",
-						app.ApplicationNameLabel:      plotter.Labels[app.ApplicationNameLabel],
-						app.ApplicationNamespaceLabel: plotter.Labels[app.ApplicationNamespaceLabel],
-					},
-				},
-				Spec: blueprintSpec,
-			}
+	err = r.ClusterManager.CreateBlueprint(cluster, blueprint)
+	if err != nil {
+		r.Log.Error(err, "Could not create blueprint for cluster", "cluster", cluster)
+		result.err = err
+		return result
+	}
 
-			err := r.ClusterManager.CreateBlueprint(cluster, blueprint)
-			if err != nil {
-				errorCollection = append(errorCollection, err)
-				r.Log.Error(err, "Could not create blueprint for cluster", "cluster", cluster)
-				continue
-			}
+	metaBlueprint := app.CreateMetaBlueprintWithoutState(blueprint)
+	metaBlueprint.ReleaseNames = releaseNames
+	result.metaBlueprint = &metaBlueprint
+	return result
+}
+
+// setApplicationVersion records the application version of the plotter on the blueprint
+func setApplicationVersion(blueprint *app.Blueprint, plotter *app.Plotter) {
+	appVersion, found := plotter.Labels[app.ApplicationVersionLabel]
+	if !found {
+		delete(blueprint.Labels, app.ApplicationVersionLabel)
+		return
+	}
+	if blueprint.Labels == nil {
+		blueprint.Labels = map[string]string{}
+	}
+	blueprint.Labels[app.ApplicationVersionLabel] = appVersion
+}
 
-			plotter.Status.Blueprints[cluster] = app.CreateMetaBlueprintWithoutState(blueprint)
+func (r *PlotterReconciler) reconcile(plotter *app.Plotter) (ctrl.Result, []error) {
+	if plotter.Status.Blueprints == nil {
+		plotter.Status.Blueprints = make(map[string]app.MetaBlueprint)
+	}
+
+	// failed blueprints are given a new chance once the spec changes
+	if plotter.Generation != plotter.Status.ObservedGeneration {
+		plotter.Status.BlueprintFailures = nil
+		plotter.Status.ObservedState.Failed = false
+		plotter.Status.ChangedBlueprints = changedBlueprints(plotter)
+	}
+	// the blueprints of a failed plotter are still observed, such that a failure that has been fixed since,
+	// e.g. by a rerun of the failed jobs, is no longer reported
+	if plotter.Status.ObservedState.Failed {
+		r.Log.V(1).Info("Retries of the plotter have been exhausted, observing the blueprints until a spec change", "plotter", plotter.Name)
+	}
+
+	plotter.Status.ObservedState.Error = "" // Reset error state
+	// Reconciliation loop per cluster
+	// Blueprints are handled concurrently so that a slow or unreachable cluster does not block the others
+	changed := map[string]bool{}
+	for _, cluster := range plotter.Status.ChangedBlueprints {
+		changed[cluster] = true
+	}
+	resultsChannel := make(chan blueprintResult, len(plotter.Spec.Blueprints))
+	var wg sync.WaitGroup
+	for cluster, blueprintSpec := range plotter.Spec.Blueprints {
+		wg.Add(1)
+		go func(cluster string, blueprintSpec app.BlueprintSpec) {
+			defer wg.Done()
+			resultsChannel <- r.reconcileBlueprint(plotter, cluster, blueprintSpec, changed[cluster])
+		}(cluster, blueprintSpec)
+	}
+	wg.Wait()
+	close(resultsChannel)
+
+	// Process the results in a deterministic order
+	results := make([]blueprintResult, 0, len(plotter.Spec.Blueprints))
+	for result := range resultsChannel {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].cluster < results[j].cluster })
+
+	isReady := true
+	var errorCollection []error
+	plotter.Status.BlueprintErrors = nil
+	for _, result := range results {
+		if result.metaBlueprint != nil {
+			plotter.Status.Blueprints[result.cluster] = *result.metaBlueprint
+		}
+		if !result.ready {
 			isReady = false
 		}
+		if result.observedError != "" {
+			plotter.Status.ObservedState.Error = result.observedError
+		}
+		if result.err != nil {
+			errorCollection = append(errorCollection, result.err)
+			if plotter.Status.BlueprintErrors == nil {
+				plotter.Status.BlueprintErrors = make(map[string]string)
+			}
+			plotter.Status.BlueprintErrors[result.cluster] = result.err.Error()
+		}
+		// the reruns of the failed jobs of a blueprint are not counted as retries of the plotter,
+		// and the failures are counted until the blueprint is observed without error
+		switch {
+		case result.err != nil || (result.observedError != "" && !result.rerunning):
+			if plotter.Status.BlueprintFailures == nil {
+				plotter.Status.BlueprintFailures = make(map[string]int32)
+			}
+			plotter.Status.BlueprintFailures[result.cluster]++
+		case result.observedError == "" && result.metaBlueprint != nil:
+			delete(plotter.Status.BlueprintFailures, result.cluster)
+		}
+	}
+	if len(plotter.Status.BlueprintFailures) == 0 {
+		plotter.Status.BlueprintFailures = nil
 	}
 
 	// Tidy up blueprints that have been deployed but are not in the spec any more
 	// E.g. after a plotter has been updated
 	for cluster, remoteBlueprint := range plotter.Status.Blueprints {
 		if _, exists := plotter.Spec.Blueprints[cluster]; !exists {
+			if utils.IsReadOnlyMode() || plotter.Status.ObservedState.Failed {
+				isReady = false
+				continue
+			}
 			err := r.ClusterManager.DeleteBlueprint(cluster, remoteBlueprint.Namespace, remoteBlueprint.Name)
 			if err != nil {
 				if !strings.HasPrefix(err.Error(), "Query channelByName error. Could not find the channel with name") {
@@ -239,9 +408,18 @@ func (r *PlotterReconciler) reconcile(plotter *app.Plotter) (ctrl.Result, []erro
 		}
 	}
 
+	// the health of the modules serving the assets is aggregated across the clusters
+	plotter.Status.ObservedState.Health = aggregateBlueprintsHealth(plotter.Status.Blueprints)
+	// the hostnames registered in the external DNS are reported to the application as the hostnames of its endpoints
+	plotter.Status.ObservedState.ExternalHostnames = aggregateExternalHostnames(plotter.Status.Blueprints)
+
 	// Update observed generation
-	plotter.Status.ObservedGeneration = plotter.ObjectMeta.Generation
+	// In read-only mode the spec changes have not been applied yet, they will be applied once the mode is turned off
+	if !utils.IsReadOnlyMode() {
+		plotter.Status.ObservedGeneration = plotter.ObjectMeta.Generation
+	}
 	plotter.Status.ObservedState.Ready = isReady
+	plotter.Status.ObservedState.Failed = false
 
 	if isReady {
 		if plotter.Status.ReadyTimestamp == nil {
@@ -280,10 +458,68 @@ func (r *PlotterReconciler) reconcile(plotter *app.Plotter) (ctrl.Result, []erro
 		plotter.Status.ObservedState.Error = aggregatedError
 	}
 
+	// The retries of failing blueprints are limited by the retry policy of the application.
+	// The errors have been recorded in the status, thus they are not returned to avoid the default requeue.
+	// Once the retries are exhausted, the blueprints are observed at the maximal backoff until they succeed.
+	if policy := plotter.Spec.RetryPolicy; policy != nil {
+		var failures int32
+		for _, count := range plotter.Status.BlueprintFailures {
+			if count > failures {
+				failures = count
+			}
+		}
+		if failures > policy.MaxRetries {
+			r.Log.Info("Giving up the plotter after consecutive failures", "plotter", plotter.Name,
+				"failures", failures, "maxRetries", policy.MaxRetries)
+			plotter.Status.ObservedState.Failed = true
+			return ctrl.Result{RequeueAfter: maxRetryBackoff}, nil
+		}
+		if failures > 0 {
+			requeueAfter := retryBackoff(policy, failures)
+			r.Log.Info("Retrying failed blueprints", "plotter", plotter.Name, "failures", failures, "requeueAfter", requeueAfter)
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+	}
+
 	// TODO Once a better notification mechanism exists in razee switch to that
 	return ctrl.Result{RequeueAfter: 5 * time.Second}, errorCollection
 }
 
+// changedBlueprints returns the clusters whose blueprints have been changed by the updates of the plotter spec,
+// as recorded by the M4DApplication controller. All the clusters are considered changed if the update is not recorded.
+func changedBlueprints(plotter *app.Plotter) []string {
+	if recorded, found := plotter.Annotations[app.ChangedBlueprintsAnnotation]; found {
+		return splitClusters(recorded)
+	}
+	clusters := make([]string, 0, len(plotter.Spec.Blueprints))
+	for cluster := range plotter.Spec.Blueprints {
+		clusters = append(clusters, cluster)
+	}
+	for cluster := range plotter.Status.Blueprints {
+		if _, exists := plotter.Spec.Blueprints[cluster]; !exists {
+			clusters = append(clusters, cluster)
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// retryBackoff returns the delay before the next attempt to orchestrate failing blueprints:
+// the backoff of the policy is doubled after each consecutive failure, up to maxRetryBackoff
+func retryBackoff(policy *app.RetryPolicy, failures int32) time.Duration {
+	backoff := defaultRetryBackoff
+	if policy.Backoff != nil && policy.Backoff.Duration > 0 {
+		backoff = policy.Backoff.Duration
+	}
+	for i := int32(1); i < failures && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	return backoff
+}
+
 // NewPlotterReconciler creates a new reconciler for Plotter resources
 func NewPlotterReconciler(mgr ctrl.Manager, name string, manager multicluster.ClusterManager) *PlotterReconciler {
 	return &PlotterReconciler{
@@ -299,5 +535,20 @@ func NewPlotterReconciler(mgr ctrl.Manager, name string, manager multicluster.Cl
 func (r *PlotterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&app.Plotter{}).
+		WithOptions(r.Options.controllerOptions(r)).
 		Complete(r)
 }
+
+// aggregateExternalHostnames collects the hostnames registered in the external DNS by the blueprints of all the clusters
+func aggregateExternalHostnames(blueprints map[string]app.MetaBlueprint) map[string]string {
+	var hostnames map[string]string
+	for _, blueprint := range blueprints {
+		for release, hostname := range blueprint.Status.ObservedState.ExternalHostnames {
+			if hostnames == nil {
+				hostnames = make(map[string]string)
+			}
+			hostnames[release] = hostname
+		}
+	}
+	return hostnames
+}
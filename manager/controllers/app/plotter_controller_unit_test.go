@@ -6,13 +6,16 @@ package app
 import (
 	"context"
 	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
 
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster/dummy"
 	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -87,7 +90,7 @@ func TestPlotterController(t *testing.T) {
 	g.Expect(plotter.Status.Blueprints).To(gomega.HaveKey("thegreendragon"))
 	blueprintMeta := plotter.Status.Blueprints["thegreendragon"]
 	g.Expect(blueprintMeta.Name).To(gomega.Equal(plotter.Name))
-	g.Expect(blueprintMeta.Namespace).To(gomega.Equal(BlueprintNamespace))
+	g.Expect(blueprintMeta.Namespace).To(gomega.Equal(utils.BlueprintNamespace))
 
 	// Simulate that blueprint changes state to Ready=true
 	dummyManager.DeployedBlueprints["thegreendragon"].Status.ObservedState.Ready = true
@@ -109,3 +112,342 @@ func TestPlotterController(t *testing.T) {
 	g.Expect(plotter.Status.ObservedState.Ready).To(gomega.BeTrue(), "Plotter is ready")
 	g.Expect(plotter.Status.ObservedState.DataAccessInstructions).To(gomega.Equal("nop\n"), "Plotter is ready")
 }
+
+// This test checks that a failure to create a blueprint in one cluster does not block the other clusters
+// and that the failure is attributed to the failing cluster in the plotter status.
+func TestPlotterPartialFailure(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	plotterYAML, err := ioutil.ReadFile("../../testdata/plotter.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	plotter := &app.Plotter{}
+	err = yaml.Unmarshal(plotterYAML, plotter)
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	blueprint := plotter.Spec.Blueprints["thegreendragon"]
+	plotter.Spec.Blueprints["neverland-cluster"] = *blueprint.DeepCopy()
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, plotter)
+	dummyManager := &dummy.ClusterManager{
+		DeployedBlueprints: make(map[string]*app.Blueprint),
+		FailingClusters:    []string{"neverland-cluster"},
+	}
+	r := &PlotterReconciler{
+		Client:         cl,
+		Log:            ctrl.Log.WithName("test-controller"),
+		Scheme:         s,
+		ClusterManager: dummyManager,
+	}
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      plotter.Name,
+			Namespace: plotter.Namespace,
+		},
+	}
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	err = cl.Get(context.TODO(), req.NamespacedName, plotter)
+	g.Expect(err).To(gomega.BeNil(), "Can fetch plotter")
+
+	// the healthy cluster got its blueprint
+	g.Expect(dummyManager.DeployedBlueprints).To(gomega.HaveKey("thegreendragon"))
+	g.Expect(plotter.Status.Blueprints).To(gomega.HaveKey("thegreendragon"))
+	// the failing cluster is reported
+	g.Expect(plotter.Status.Blueprints).NotTo(gomega.HaveKey("neverland-cluster"))
+	g.Expect(plotter.Status.BlueprintErrors).To(gomega.HaveKey("neverland-cluster"))
+	g.Expect(plotter.Status.BlueprintErrors).NotTo(gomega.HaveKey("thegreendragon"))
+	g.Expect(plotter.Status.ObservedState.Ready).To(gomega.BeFalse())
+}
+
+// TestPlotterReadOnlyMode checks that no blueprint is created while the manager is in read-only mode.
+// The test modifies the environment and thus does not run in parallel.
+func TestPlotterReadOnlyMode(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	g.Expect(os.Setenv(utils.ReadOnlyModeKey, "true")).To(gomega.Succeed())
+	defer os.Unsetenv(utils.ReadOnlyModeKey)
+
+	plotterYAML, err := ioutil.ReadFile("../../testdata/plotter.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	plotter := &app.Plotter{}
+	err = yaml.Unmarshal(plotterYAML, plotter)
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, plotter)
+	dummyManager := &dummy.ClusterManager{
+		DeployedBlueprints: make(map[string]*app.Blueprint),
+	}
+	r := &PlotterReconciler{
+		Client:         cl,
+		Log:            ctrl.Log.WithName("test-controller"),
+		Scheme:         s,
+		ClusterManager: dummyManager,
+	}
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      plotter.Name,
+			Namespace: plotter.Namespace,
+		},
+	}
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+
+	err = cl.Get(context.TODO(), req.NamespacedName, plotter)
+	g.Expect(err).To(gomega.BeNil(), "Can fetch plotter")
+	g.Expect(dummyManager.DeployedBlueprints).To(gomega.BeEmpty())
+	g.Expect(plotter.Status.ObservedState.Ready).To(gomega.BeFalse())
+}
+
+// This test checks that the status of a blueprint generated for an older application version
+// does not propagate into the plotter status
+func TestPlotterIgnoresStaleBlueprintStatus(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	plotterYAML, err := ioutil.ReadFile("../../testdata/plotter.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	plotter := &app.Plotter{}
+	g.Expect(yaml.Unmarshal(plotterYAML, plotter)).To(gomega.Succeed())
+	plotter.Labels[app.ApplicationVersionLabel] = "1"
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, plotter)
+	dummyManager := &dummy.ClusterManager{
+		DeployedBlueprints: make(map[string]*app.Blueprint),
+	}
+	r := &PlotterReconciler{
+		Client:         cl,
+		Log:            ctrl.Log.WithName("test-controller"),
+		Scheme:         s,
+		ClusterManager: dummyManager,
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: plotter.Name, Namespace: plotter.Namespace}}
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	blueprint := dummyManager.DeployedBlueprints["thegreendragon"]
+	g.Expect(blueprint.Labels).To(gomega.HaveKeyWithValue(app.ApplicationVersionLabel, "1"))
+	blueprint.Status.ObservedState.Ready = true
+
+	// a new application version records the blueprint as changed, although its spec ends up the same,
+	// e.g. after a change that has been reverted before the plotter was reconciled
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, plotter)).To(gomega.Succeed())
+	plotter.Labels[app.ApplicationVersionLabel] = "2"
+	plotter.Annotations = map[string]string{app.ChangedBlueprintsAnnotation: "thegreendragon"}
+	plotter.Generation++
+	g.Expect(cl.Update(context.Background(), plotter)).To(gomega.Succeed())
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	// the plotter is fetched into a new object, since the fields omitted from the stored plotter are not reset by Get
+	plotter = &app.Plotter{}
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, plotter)).To(gomega.Succeed())
+	g.Expect(plotter.Status.ObservedState.Ready).To(gomega.BeFalse(), "the blueprint status is of an older application version")
+	g.Expect(blueprint.Labels).To(gomega.HaveKeyWithValue(app.ApplicationVersionLabel, "2"))
+
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, plotter)).To(gomega.Succeed())
+	g.Expect(plotter.Status.ObservedState.Ready).To(gomega.BeTrue())
+}
+
+// This test checks that a failing blueprint is retried with a backoff according to the retry policy,
+// that the plotter is given up once the retries are exhausted, that the reruns of failed jobs are not counted
+// as retries, and that the plotter is no longer failed once its blueprints succeed
+func TestPlotterRetryPolicy(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	plotterYAML, err := ioutil.ReadFile("../../testdata/plotter.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	plotter := &app.Plotter{}
+	g.Expect(yaml.Unmarshal(plotterYAML, plotter)).To(gomega.Succeed())
+	plotter.Spec.RetryPolicy = &app.RetryPolicy{MaxRetries: 1, Backoff: &metav1.Duration{Duration: time.Second}}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, plotter)
+	dummyManager := &dummy.ClusterManager{
+		DeployedBlueprints: make(map[string]*app.Blueprint),
+		FailingClusters:    []string{"thegreendragon"},
+	}
+	r := &PlotterReconciler{
+		Client:         cl,
+		Log:            ctrl.Log.WithName("test-controller"),
+		Scheme:         s,
+		ClusterManager: dummyManager,
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: plotter.Name, Namespace: plotter.Namespace}}
+	// the plotter is fetched into a new object, since the fields omitted from the stored plotter are not reset by Get
+	fetchPlotter := func() {
+		plotter = &app.Plotter{}
+		g.Expect(cl.Get(context.Background(), req.NamespacedName, plotter)).To(gomega.Succeed())
+	}
+
+	// the first failure is retried after the backoff
+	res, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(res.RequeueAfter).To(gomega.Equal(time.Second))
+	fetchPlotter()
+	g.Expect(plotter.Status.BlueprintFailures).To(gomega.HaveKeyWithValue("thegreendragon", int32(1)))
+	g.Expect(plotter.Status.ObservedState.Failed).To(gomega.BeFalse())
+
+	// the retry fails as well and the plotter is given up, its blueprints are only observed from then on
+	res, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(res).To(gomega.Equal(ctrl.Result{RequeueAfter: maxRetryBackoff}))
+	fetchPlotter()
+	g.Expect(plotter.Status.BlueprintFailures).To(gomega.HaveKeyWithValue("thegreendragon", int32(2)))
+	g.Expect(plotter.Status.ObservedState.Failed).To(gomega.BeTrue())
+	g.Expect(plotter.Status.ObservedState.Error).NotTo(gomega.BeEmpty())
+
+	// no further attempt is made until the spec changes
+	dummyManager.FailingClusters = nil
+	res, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(res).To(gomega.Equal(ctrl.Result{RequeueAfter: maxRetryBackoff}))
+	g.Expect(dummyManager.DeployedBlueprints).To(gomega.BeEmpty())
+	fetchPlotter()
+	g.Expect(plotter.Status.ObservedState.Failed).To(gomega.BeTrue())
+
+	plotter.Spec.RetryPolicy.MaxRetries = 3
+	plotter.Generation++
+	g.Expect(cl.Update(context.Background(), plotter)).To(gomega.Succeed())
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	fetchPlotter()
+	g.Expect(plotter.Status.ObservedState.Failed).To(gomega.BeFalse())
+	g.Expect(plotter.Status.BlueprintFailures).To(gomega.BeEmpty())
+	g.Expect(dummyManager.DeployedBlueprints).To(gomega.HaveKey("thegreendragon"))
+
+	// the failed jobs of the blueprint are rerun, which is not counted as a retry
+	blueprint := dummyManager.DeployedBlueprints["thegreendragon"]
+	blueprint.Status.ObservedGeneration = blueprint.Generation
+	blueprint.Status.ObservedState.Error = "ResourceAllocationFailure: job copy has failed"
+	blueprint.Status.Steps = map[string]app.StepResources{
+		"copy": {Readiness: app.CompletionReadiness, State: app.StepFailed, Reruns: 1},
+	}
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	fetchPlotter()
+	g.Expect(plotter.Status.BlueprintFailures).To(gomega.BeEmpty())
+
+	// the reruns are exhausted as well, and the plotter is given up
+	blueprint.Status.Steps["copy"] = app.StepResources{Readiness: app.CompletionReadiness, State: app.StepFailed, Reruns: app.MaxStepReruns}
+	for i := int32(0); i <= plotter.Spec.RetryPolicy.MaxRetries; i++ {
+		_, err = r.Reconcile(context.Background(), req)
+		g.Expect(err).To(gomega.BeNil())
+	}
+	fetchPlotter()
+	g.Expect(plotter.Status.ObservedState.Failed).To(gomega.BeTrue())
+
+	// the blueprint eventually succeeds, e.g. once its jobs have been run again by hand
+	blueprint.Status.ObservedState.Error = ""
+	blueprint.Status.ObservedState.Ready = true
+	blueprint.Status.Steps["copy"] = app.StepResources{Readiness: app.CompletionReadiness, State: app.StepReady}
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	fetchPlotter()
+	g.Expect(plotter.Status.ObservedState.Failed).To(gomega.BeFalse())
+	g.Expect(plotter.Status.ObservedState.Ready).To(gomega.BeTrue())
+	g.Expect(plotter.Status.BlueprintFailures).To(gomega.BeEmpty())
+
+	// the backoff is doubled after each failure
+	g.Expect(retryBackoff(&app.RetryPolicy{}, 1)).To(gomega.Equal(defaultRetryBackoff))
+	g.Expect(retryBackoff(plotter.Spec.RetryPolicy, 3)).To(gomega.Equal(4 * time.Second))
+	g.Expect(retryBackoff(plotter.Spec.RetryPolicy, 30)).To(gomega.Equal(maxRetryBackoff))
+}
+
+// This test checks that only the blueprints of the changed clusters are written to the plotter and to the clusters,
+// and that the changed clusters are reported in the plotter status
+func TestPlotterChangedBlueprints(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	plotterYAML, err := ioutil.ReadFile("../../testdata/plotter.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	plotter := &app.Plotter{}
+	g.Expect(yaml.Unmarshal(plotterYAML, plotter)).To(gomega.Succeed())
+	blueprint := plotter.Spec.Blueprints["thegreendragon"]
+	changed := blueprint.DeepCopy()
+	changed.Entrypoint = "MyOtherApp"
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+	dummyManager := &dummy.ClusterManager{
+		DeployedBlueprints: make(map[string]*app.Blueprint),
+	}
+	r := &PlotterReconciler{
+		Client:         cl,
+		Log:            ctrl.Log.WithName("test-controller"),
+		Scheme:         s,
+		ClusterManager: dummyManager,
+	}
+	plotterInterface := NewPlotterInterface(cl)
+	owner := &app.ResourceReference{Name: "notebook", Namespace: "default", AppVersion: 1}
+	ref := &app.ResourceReference{Name: plotter.Name, Namespace: plotter.Namespace, Kind: "Plotter", AppVersion: 1}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: plotter.Name, Namespace: plotter.Namespace}}
+
+	// apply generates the plotter and reconciles it, increasing the generation as the API server does on spec changes
+	apply := func(blueprints map[string]app.BlueprintSpec) *app.Plotter {
+		g.Expect(plotterInterface.CreateOrUpdateResource(owner, ref, blueprints, nil)).To(gomega.Succeed())
+		applied := &app.Plotter{}
+		g.Expect(cl.Get(context.Background(), req.NamespacedName, applied)).To(gomega.Succeed())
+		applied.Generation++
+		g.Expect(cl.Update(context.Background(), applied)).To(gomega.Succeed())
+		_, err := r.Reconcile(context.Background(), req)
+		g.Expect(err).To(gomega.BeNil())
+		g.Expect(cl.Get(context.Background(), req.NamespacedName, applied)).To(gomega.Succeed())
+		return applied
+	}
+
+	applied := apply(map[string]app.BlueprintSpec{"cluster-a": blueprint, "cluster-b": blueprint})
+	g.Expect(applied.Annotations).To(gomega.HaveKeyWithValue(app.ChangedBlueprintsAnnotation, "cluster-a,cluster-b"))
+	g.Expect(applied.Status.ChangedBlueprints).To(gomega.Equal([]string{"cluster-a", "cluster-b"}))
+	g.Expect(dummyManager.DeployedBlueprints).To(gomega.HaveLen(2))
+
+	// the blueprint of a single cluster changes, the blueprint of the other cluster is not written
+	owner.AppVersion, ref.AppVersion = 2, 2
+	applied = apply(map[string]app.BlueprintSpec{"cluster-a": blueprint, "cluster-b": *changed})
+	g.Expect(applied.Status.ChangedBlueprints).To(gomega.Equal([]string{"cluster-b"}))
+	g.Expect(applied.Spec.Blueprints["cluster-a"].Entrypoint).To(gomega.Equal(blueprint.Entrypoint))
+	g.Expect(applied.Spec.Blueprints["cluster-b"].Entrypoint).To(gomega.Equal(changed.Entrypoint))
+	g.Expect(dummyManager.DeployedBlueprints["cluster-a"].Labels).To(gomega.HaveKeyWithValue(app.ApplicationVersionLabel, "1"))
+	g.Expect(dummyManager.DeployedBlueprints["cluster-b"].Labels).To(gomega.HaveKeyWithValue(app.ApplicationVersionLabel, "2"))
+
+	// applying the same blueprints again does not change the plotter
+	g.Expect(plotterInterface.CreateOrUpdateResource(owner, ref, map[string]app.BlueprintSpec{"cluster-a": blueprint, "cluster-b": *changed}, nil)).To(gomega.Succeed())
+	unchanged := &app.Plotter{}
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, unchanged)).To(gomega.Succeed())
+	g.Expect(unchanged.ResourceVersion).To(gomega.Equal(applied.ResourceVersion))
+
+	// the blueprint of a cluster is removed
+	applied = apply(map[string]app.BlueprintSpec{"cluster-b": *changed})
+	g.Expect(applied.Status.ChangedBlueprints).To(gomega.Equal([]string{"cluster-a"}))
+	g.Expect(applied.Spec.Blueprints).To(gomega.HaveLen(1))
+	g.Expect(dummyManager.DeployedBlueprints).NotTo(gomega.HaveKey("cluster-a"))
+
+	// the changes are accumulated until the plotter controller observes them
+	g.Expect(plotterInterface.CreateOrUpdateResource(owner, ref, map[string]app.BlueprintSpec{"cluster-b": *changed, "cluster-c": blueprint}, nil)).To(gomega.Succeed())
+	pending := &app.Plotter{}
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, pending)).To(gomega.Succeed())
+	pending.Generation++
+	g.Expect(cl.Update(context.Background(), pending)).To(gomega.Succeed())
+	applied = apply(map[string]app.BlueprintSpec{"cluster-b": blueprint, "cluster-c": blueprint})
+	g.Expect(applied.Annotations).To(gomega.HaveKeyWithValue(app.ChangedBlueprintsAnnotation, "cluster-b,cluster-c"))
+	g.Expect(applied.Status.ChangedBlueprints).To(gomega.Equal([]string{"cluster-b", "cluster-c"}))
+
+	// the blueprints added by others are left untouched
+	applied.Spec.Blueprints["cluster-x"] = blueprint
+	g.Expect(cl.Update(context.Background(), applied)).To(gomega.Succeed())
+	applied = apply(map[string]app.BlueprintSpec{"cluster-b": blueprint})
+	g.Expect(applied.Status.ChangedBlueprints).To(gomega.Equal([]string{"cluster-c"}))
+	g.Expect(applied.Spec.Blueprints).To(gomega.HaveKey("cluster-x"))
+	g.Expect(applied.Spec.Blueprints).NotTo(gomega.HaveKey("cluster-c"))
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	contextapi "github.com/mesh-for-data/mesh-for-data/manager/controllers/app"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/conformance"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestPlotterInterfaceConformance runs the ContextInterface conformance suite against PlotterInterface
+func TestPlotterInterfaceConformance(t *testing.T) {
+	t.Parallel()
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(nil))
+
+	blueprint := app.BlueprintSpec{
+		Entrypoint: "notebook",
+		Flow:       app.DataFlow{Name: "notebook", Steps: []app.FlowStep{{Name: "read", Template: "read-module"}}},
+		Templates:  []app.ComponentTemplate{{Name: "read-module", Kind: "M4DModule", Chart: app.ChartSpec{Name: "read-chart"}}},
+	}
+	updated := *blueprint.DeepCopy()
+	updated.Templates[0].Chart.Name = "read-chart-v2"
+
+	conformance.RunContextInterfaceTests(t, contextapi.NewPlotterInterface(cl), conformance.Options{
+		Owner:             &app.ResourceReference{Name: "notebook", Namespace: "default", AppVersion: 1},
+		Blueprints:        map[string]app.BlueprintSpec{"thegreendragon": blueprint},
+		UpdatedBlueprints: map[string]app.BlueprintSpec{"thegreendragon": updated},
+		SetStatus: func(ref *app.ResourceReference, state app.ObservedState) error {
+			plotter := &app.Plotter{}
+			if err := cl.Get(context.Background(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, plotter); err != nil {
+				return err
+			}
+			plotter.Status.ObservedState = state
+			return cl.Status().Update(context.Background(), plotter)
+		},
+		ModifySpec: func(ref *app.ResourceReference) error {
+			plotter := &app.Plotter{}
+			if err := cl.Get(context.Background(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, plotter); err != nil {
+				return err
+			}
+			plotter.Spec.Blueprints["thegreendragon"] = blueprint
+			return cl.Update(context.Background(), plotter)
+		},
+	})
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+)
+
+// applyPolicyManagerFailMode handles an application whose governance policies could not be evaluated since the policy manager
+// is unavailable, according to the mode configured by the administrators of the cluster.
+// In the fail-open-with-audit mode, the previously granted generation of the application remains deployed: its status
+// is restored, the unavailability is recorded in the conditions and an audit event is emitted, and true is returned.
+// Otherwise, or if no generation has been granted, the application is blocked with the errors of the evaluation.
+func (r *M4DApplicationReconciler) applyPolicyManagerFailMode(application *app.M4DApplication, previous *app.M4DApplicationStatus) bool {
+	if utils.GetPolicyManagerFailMode() != utils.FailOpenWithAudit || previous.Generated == nil ||
+		!r.ResourceInterface.ResourceExists(previous.Generated) {
+		setPolicyManagerUnavailableCondition(application, app.FailClosedReason, app.PolicyManagerUnavailable)
+		return false
+	}
+	failedOpen := isPolicyManagerUnavailable(previous)
+	application.Status = *previous
+	msg := fmt.Sprintf("%s Generation %d of the application remains deployed.", app.PolicyManagerUnavailable, previous.Generated.AppVersion)
+	setPolicyManagerUnavailableCondition(application, app.FailOpenReason, msg)
+	if !failedOpen {
+		r.Log.Info("Audit: the policy manager is unavailable, keeping the previously granted data plane",
+			"application", application.Namespace+"/"+application.Name, "generation", previous.Generated.AppVersion)
+		evaluate.EmitEvent(r.Events, application, events.Event{Type: events.FailedOpen, Reason: msg})
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"os"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestPolicyManagerFailMode modifies the environment and thus does not run in parallel.
+func TestPolicyManagerFailMode(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	defer os.Unsetenv(utils.PolicyManagerFailModeKey)
+
+	plotter := &app.Plotter{ObjectMeta: metav1.ObjectMeta{Name: "notebook-default", Namespace: "m4d-system"}}
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), plotter)
+	r := &M4DApplicationReconciler{
+		Log:               ctrl.Log.WithName("test"),
+		ResourceInterface: &PlotterInterface{Client: cl},
+	}
+	previous := &app.M4DApplicationStatus{
+		Ready:     true,
+		Generated: &app.ResourceReference{Name: "notebook-default", Namespace: "m4d-system", Kind: "Plotter", AppVersion: 1},
+	}
+	newApplication := func() *app.M4DApplication {
+		application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default", Generation: 2}}
+		evaluate.ResetConditions(application)
+		evaluate.SetCondition(application, "s3/allow-dataset", "policy manager connection refused", true)
+		return application
+	}
+
+	// the application is blocked in the default fail-closed mode
+	application := newApplication()
+	g.Expect(r.applyPolicyManagerFailMode(application, previous)).To(gomega.BeFalse())
+	g.Expect(application.Status.Ready).To(gomega.BeFalse())
+	g.Expect(evaluate.HasError(application)).To(gomega.BeTrue())
+	condition := meta.FindStatusCondition(application.Status.Conditions, app.PolicyManagerUnavailableCondition)
+	g.Expect(condition.Status).To(gomega.Equal(metav1.ConditionTrue))
+	g.Expect(condition.Reason).To(gomega.Equal(app.FailClosedReason))
+
+	// the previously granted generation remains in the fail-open mode
+	g.Expect(os.Setenv(utils.PolicyManagerFailModeKey, utils.FailOpenWithAudit)).To(gomega.Succeed())
+	application = newApplication()
+	g.Expect(r.applyPolicyManagerFailMode(application, previous)).To(gomega.BeTrue())
+	g.Expect(application.Status.Ready).To(gomega.BeTrue())
+	g.Expect(evaluate.HasError(application)).To(gomega.BeFalse())
+	g.Expect(application.Status.Generated).To(gomega.Equal(previous.Generated))
+	condition = meta.FindStatusCondition(application.Status.Conditions, app.PolicyManagerUnavailableCondition)
+	g.Expect(condition.Reason).To(gomega.Equal(app.FailOpenReason))
+	g.Expect(isPolicyManagerUnavailable(&application.Status)).To(gomega.BeTrue())
+
+	// an application that has never been granted is blocked in the fail-open mode
+	application = newApplication()
+	g.Expect(r.applyPolicyManagerFailMode(application, &app.M4DApplicationStatus{})).To(gomega.BeFalse())
+	g.Expect(evaluate.HasError(application)).To(gomega.BeTrue())
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// PolicyInvalidationPath is the path of the manager endpoint that policy services call when the policies change
+const PolicyInvalidationPath = "/policies/invalidate"
+
+// PolicyInvalidation is the request of the policy invalidation endpoint.
+// The decisions on all the datasets are invalidated if no dataset is given.
+type PolicyInvalidation struct {
+	Datasets []string `json:"datasets,omitempty"`
+}
+
+// PolicyInvalidationResult is the response of the policy invalidation endpoint
+type PolicyInvalidationResult struct {
+	// Decisions is the number of cached decisions that have been removed
+	Decisions int `json:"decisions"`
+	// Applications is the number of applications whose policies are evaluated again
+	Applications int `json:"applications"`
+}
+
+// PolicyCache caches the decisions of the policy manager
+type PolicyCache interface {
+	// Invalidate removes the cached decisions on the given datasets, or all of them if no dataset is given
+	Invalidate(datasetIDs ...string) int
+}
+
+// invalidatedApplications tracks the applications whose policies have to be evaluated again
+type invalidatedApplications struct {
+	mutex        sync.Mutex
+	applications map[types.NamespacedName]bool
+}
+
+func (s *invalidatedApplications) add(id types.NamespacedName) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.applications == nil {
+		s.applications = make(map[types.NamespacedName]bool)
+	}
+	s.applications[id] = true
+}
+
+func (s *invalidatedApplications) has(id types.NamespacedName) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.applications[id]
+}
+
+func (s *invalidatedApplications) remove(id types.NamespacedName) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.applications, id)
+}
+
+// PolicyInvalidationHandler returns the handler of the policy invalidation endpoint.
+// The cached decisions on the datasets are removed, and the applications using them are reconciled again.
+func (r *M4DApplicationReconciler) PolicyInvalidationHandler(cache PolicyCache) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		invalidation := PolicyInvalidation{}
+		if req.ContentLength != 0 {
+			if err := json.NewDecoder(req.Body).Decode(&invalidation); err != nil {
+				http.Error(w, "invalid policy invalidation request: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		result := PolicyInvalidationResult{Decisions: cache.Invalidate(invalidation.Datasets...)}
+		applications, err := r.invalidatePolicies(req.Context(), invalidation.Datasets)
+		if err != nil {
+			r.Log.Error(err, "unable to reconcile the applications after a policy change")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		result.Applications = applications
+		r.Log.V(0).Info("Policies invalidated", "datasets", invalidation.Datasets, "decisions", result.Decisions, "applications", result.Applications)
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(&result); err != nil {
+			r.Log.Error(err, "unable to write the policy invalidation result")
+		}
+	})
+}
+
+// invalidatePolicies marks the applications using the given datasets, or all the applications if no dataset is given,
+// so that their policies are evaluated again, and enqueues them for reconciliation. It returns the number of applications.
+func (r *M4DApplicationReconciler) invalidatePolicies(ctx context.Context, datasetIDs []string) (int, error) {
+	// the modules selected for the datasets are selected again according to the new policies
+	r.Selections.Invalidate(datasetIDs...)
+	var applications app.M4DApplicationList
+	if err := r.List(ctx, &applications); err != nil {
+		return 0, err
+	}
+	datasets := make(map[string]bool, len(datasetIDs))
+	for _, datasetID := range datasetIDs {
+		datasets[datasetID] = true
+	}
+	count := 0
+	for i := range applications.Items {
+		application := &applications.Items[i]
+		if len(datasets) > 0 && !usesAnyDataset(application, datasets) {
+			continue
+		}
+		r.policiesInvalidated.add(client.ObjectKeyFromObject(application))
+		count++
+		if r.policyInvalidations == nil {
+			continue
+		}
+		select {
+		case r.policyInvalidations <- event.GenericEvent{Object: application}:
+		case <-ctx.Done():
+			return count, ctx.Err()
+		}
+	}
+	return count, nil
+}
+
+// usesAnyDataset returns true if the application requires one of the datasets
+func usesAnyDataset(application *app.M4DApplication, datasets map[string]bool) bool {
+	for _, dataCtx := range application.Spec.Data {
+		if datasets[dataCtx.DataSetID] {
+			return true
+		}
+	}
+	return false
+}
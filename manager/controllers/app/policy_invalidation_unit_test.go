@@ -0,0 +1,73 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// fakePolicyCache records the invalidated datasets
+type fakePolicyCache struct {
+	invalidated []string
+}
+
+func (c *fakePolicyCache) Invalidate(datasetIDs ...string) int {
+	c.invalidated = append(c.invalidated, datasetIDs...)
+	return len(datasetIDs)
+}
+
+// TestPolicyInvalidation checks that the applications using the invalidated datasets are reconciled again
+func TestPolicyInvalidation(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/m4dcopyapp-csv.yaml", application)).NotTo(gomega.HaveOccurred())
+	other := application.DeepCopy()
+	other.Name = "other"
+	other.Spec.Data[0].DataSetID = "s3/other-dataset"
+
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), application, other)
+	r := &M4DApplicationReconciler{
+		Client:              cl,
+		Log:                 ctrl.Log.WithName("test"),
+		policyInvalidations: make(chan event.GenericEvent, 10),
+	}
+	cache := &fakePolicyCache{}
+	recorder := httptest.NewRecorder()
+	body := `{"datasets": ["` + application.Spec.Data[0].DataSetID + `"]}`
+	r.PolicyInvalidationHandler(cache).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PolicyInvalidationPath, strings.NewReader(body)))
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+
+	result := &PolicyInvalidationResult{}
+	g.Expect(json.Unmarshal(recorder.Body.Bytes(), result)).To(gomega.Succeed())
+	g.Expect(*result).To(gomega.Equal(PolicyInvalidationResult{Decisions: 1, Applications: 1}))
+	g.Expect(cache.invalidated).To(gomega.ConsistOf(application.Spec.Data[0].DataSetID))
+	g.Expect(r.policyInvalidations).To(gomega.HaveLen(1))
+	g.Expect((<-r.policyInvalidations).Object.GetName()).To(gomega.Equal(application.Name))
+	g.Expect(r.policiesInvalidated.has(types.NamespacedName{Name: application.Name, Namespace: application.Namespace})).To(gomega.BeTrue())
+	g.Expect(r.policiesInvalidated.has(types.NamespacedName{Name: other.Name, Namespace: other.Namespace})).To(gomega.BeFalse())
+
+	// all the applications are reconciled again if no dataset is given
+	recorder = httptest.NewRecorder()
+	r.PolicyInvalidationHandler(cache).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PolicyInvalidationPath, nil))
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.policyInvalidations).To(gomega.HaveLen(2))
+
+	recorder = httptest.NewRecorder()
+	r.PolicyInvalidationHandler(cache).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, PolicyInvalidationPath, nil))
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusMethodNotAllowed))
+}
@@ -0,0 +1,213 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// priorityQueue holds the requests of a controller in a FIFO queue per priority, and releases them to the work queue
+// of the controller in a weighted fair order: while requests of several priorities are pending, each priority p
+// is released in proportion to p+1. At most maxReleased requests are released and not yet reconciled at any time,
+// such that the work queue of the controller stays short and the order of the reconciles follows the priorities.
+// Requests of objects that are being reconciled are passed directly to the work queue, which reconciles them again once done.
+type priorityQueue struct {
+	priorityOf  func(types.NamespacedName) int32
+	maxReleased int
+
+	mutex sync.Mutex
+	// queue is the work queue of the controller, known from the first event
+	queue workqueue.RateLimitingInterface
+	// buckets holds the pending requests of each priority
+	buckets map[int32]*priorityBucket
+	// pending holds the priorities of the pending requests
+	pending map[reconcile.Request]int32
+	// released holds the requests released to the work queue that have not been reconciled yet
+	released map[reconcile.Request]bool
+	// virtualTime is the tag of the last released request
+	virtualTime float64
+}
+
+// priorityBucket is the FIFO queue of the pending requests of a priority, with the virtual finish tags of the requests
+type priorityBucket struct {
+	requests []reconcile.Request
+	tags     []float64
+	lastTag  float64
+}
+
+// newPriorityQueue creates a priority queue releasing at most maxReleased requests at a time
+func newPriorityQueue(priorityOf func(types.NamespacedName) int32, maxReleased int) *priorityQueue {
+	if maxReleased < 1 {
+		maxReleased = 1
+	}
+	return &priorityQueue{
+		priorityOf:  priorityOf,
+		maxReleased: maxReleased,
+		buckets:     make(map[int32]*priorityBucket),
+		pending:     make(map[reconcile.Request]int32),
+		released:    make(map[reconcile.Request]bool),
+	}
+}
+
+// add adds a request to the pending requests, or to the work queue if it has been released
+func (p *priorityQueue) add(queue workqueue.RateLimitingInterface, req reconcile.Request) {
+	priority := p.priorityOf(req.NamespacedName)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.queue = queue
+	if p.released[req] {
+		queue.Add(req)
+		return
+	}
+	if _, found := p.pending[req]; found {
+		return
+	}
+	bucket, found := p.buckets[priority]
+	if !found {
+		bucket = &priorityBucket{}
+		p.buckets[priority] = bucket
+	}
+	// weighted fair queueing: the tag of a request is the virtual time at which it would be done
+	// if each priority were served at a rate proportional to its weight
+	start := bucket.lastTag
+	if start < p.virtualTime {
+		start = p.virtualTime
+	}
+	bucket.lastTag = start + 1/float64(priority+1)
+	bucket.requests = append(bucket.requests, req)
+	bucket.tags = append(bucket.tags, bucket.lastTag)
+	p.pending[req] = priority
+	p.release()
+}
+
+// release moves the pending requests with the lowest tags to the work queue, up to maxReleased released requests.
+// Requests of higher priorities are released first among equal tags.
+func (p *priorityQueue) release() {
+	for len(p.released) < p.maxReleased && len(p.pending) > 0 {
+		var next *priorityBucket
+		var nextPriority int32
+		for priority, bucket := range p.buckets {
+			if len(bucket.requests) == 0 {
+				continue
+			}
+			if next == nil || bucket.tags[0] < next.tags[0] || (bucket.tags[0] == next.tags[0] && priority > nextPriority) {
+				next, nextPriority = bucket, priority
+			}
+		}
+		req := next.requests[0]
+		p.virtualTime = next.tags[0]
+		next.requests = next.requests[1:]
+		next.tags = next.tags[1:]
+		if len(next.requests) == 0 {
+			delete(p.buckets, nextPriority)
+		}
+		delete(p.pending, req)
+		p.released[req] = true
+		p.queue.Add(req)
+	}
+}
+
+// done marks a released request as reconciled and releases the next pending requests
+func (p *priorityQueue) done(req reconcile.Request) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if !p.released[req] {
+		return
+	}
+	delete(p.released, req)
+	if p.queue != nil {
+		p.release()
+	}
+}
+
+// Handler wraps an event handler such that the requests it enqueues are added to the priority queue
+func (p *priorityQueue) Handler(inner handler.EventHandler) handler.EventHandler {
+	return &priorityHandler{inner: inner, queue: p}
+}
+
+// Reconciler wraps a reconciler such that the reconciled requests are marked done, and the requests to reconcile again
+// go through the priority queue. Requests failing with an error are reconciled again by the controller with a backoff.
+func (p *priorityQueue) Reconciler(inner reconcile.Reconciler) reconcile.Reconciler {
+	return &priorityReconciler{inner: inner, queue: p}
+}
+
+// priorityReconciler marks the requests reconciled by the wrapped reconciler done in the priority queue
+type priorityReconciler struct {
+	inner reconcile.Reconciler
+	queue *priorityQueue
+}
+
+func (r *priorityReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.inner.Reconcile(ctx, req)
+	p := r.queue
+	p.mutex.Lock()
+	queue := p.queue
+	p.mutex.Unlock()
+	p.done(req)
+	if err != nil || queue == nil {
+		return result, err
+	}
+	switch {
+	case result.RequeueAfter > 0:
+		time.AfterFunc(result.RequeueAfter, func() { p.add(queue, req) })
+	case result.Requeue:
+		p.add(queue, req)
+	default:
+		return result, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// priorityHandler passes the events to the wrapped handler with a work queue adding the requests to the priority queue
+type priorityHandler struct {
+	inner handler.EventHandler
+	queue *priorityQueue
+}
+
+func (h *priorityHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.inner.Create(evt, &priorityAdder{RateLimitingInterface: q, queue: h.queue})
+}
+
+func (h *priorityHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.inner.Update(evt, &priorityAdder{RateLimitingInterface: q, queue: h.queue})
+}
+
+func (h *priorityHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.inner.Delete(evt, &priorityAdder{RateLimitingInterface: q, queue: h.queue})
+}
+
+func (h *priorityHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.inner.Generic(evt, &priorityAdder{RateLimitingInterface: q, queue: h.queue})
+}
+
+// priorityAdder is the work queue of the controller, in which the added requests go through the priority queue
+type priorityAdder struct {
+	workqueue.RateLimitingInterface
+	queue *priorityQueue
+}
+
+func (a *priorityAdder) Add(item interface{}) {
+	req, ok := item.(reconcile.Request)
+	if !ok {
+		a.RateLimitingInterface.Add(item)
+		return
+	}
+	a.queue.add(a.RateLimitingInterface, req)
+}
+
+func (a *priorityAdder) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		a.Add(item)
+		return
+	}
+	time.AfterFunc(duration, func() { a.Add(item) })
+}
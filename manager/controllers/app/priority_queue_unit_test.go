@@ -0,0 +1,137 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newPriorityTestQueue(priorities map[string]int32) *priorityQueue {
+	return newPriorityQueue(func(key types.NamespacedName) int32 {
+		return priorities[key.Name]
+	}, 1)
+}
+
+func priorityRequest(name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}
+}
+
+// reconcileNext takes the next request from the work queue and marks it reconciled
+func reconcileNext(p *priorityQueue, queue workqueue.RateLimitingInterface) string {
+	item, _ := queue.Get()
+	queue.Done(item)
+	req := item.(reconcile.Request)
+	p.done(req)
+	return req.Name
+}
+
+// TestPriorityQueueOrder checks that pending applications of high priority are reconciled first
+func TestPriorityQueueOrder(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	p := newPriorityTestQueue(map[string]int32{"prod-1": 9, "prod-2": 9})
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	for _, name := range []string{"batch-1", "batch-2", "batch-3", "prod-1", "prod-2"} {
+		p.add(queue, priorityRequest(name))
+	}
+	// a single request is released at a time
+	g.Expect(queue.Len()).To(gomega.Equal(1))
+
+	var order []string
+	for i := 0; i < 5; i++ {
+		order = append(order, reconcileNext(p, queue))
+	}
+	g.Expect(order).To(gomega.Equal([]string{"batch-1", "prod-1", "prod-2", "batch-2", "batch-3"}))
+	g.Expect(queue.Len()).To(gomega.Equal(0))
+}
+
+// TestPriorityQueueFairness checks that applications of low priority are not starved
+func TestPriorityQueueFairness(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	priorities := map[string]int32{}
+	p := newPriorityTestQueue(priorities)
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	p.add(queue, priorityRequest("first"))
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		priorities["high-"+name] = 1
+		p.add(queue, priorityRequest("high-"+name))
+		p.add(queue, priorityRequest("low-"+name))
+	}
+	g.Expect(reconcileNext(p, queue)).To(gomega.Equal("first"))
+
+	// priority 1 is served twice as often as priority 0
+	counts := map[int32]int{}
+	for i := 0; i < 6; i++ {
+		counts[priorities[reconcileNext(p, queue)]]++
+	}
+	g.Expect(counts).To(gomega.Equal(map[int32]int{1: 4, 0: 2}))
+}
+
+// TestPriorityQueueDuplicates checks that pending requests are not duplicated, and that the requests of applications
+// being reconciled are passed to the work queue
+func TestPriorityQueueDuplicates(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	p := newPriorityTestQueue(map[string]int32{})
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	p.add(queue, priorityRequest("app-1"))
+	p.add(queue, priorityRequest("app-2"))
+	p.add(queue, priorityRequest("app-2"))
+	g.Expect(p.pending).To(gomega.HaveLen(1))
+
+	item, _ := queue.Get()
+	g.Expect(item).To(gomega.Equal(priorityRequest("app-1")))
+	// app-1 changes while it is reconciled
+	p.add(queue, priorityRequest("app-1"))
+	queue.Done(item)
+	p.done(priorityRequest("app-1"))
+	g.Expect(queue.Len()).To(gomega.Equal(2))
+	g.Expect(reconcileNext(p, queue)).To(gomega.Equal("app-1"))
+	g.Expect(reconcileNext(p, queue)).To(gomega.Equal("app-2"))
+}
+
+// TestPriorityReconciler checks that the requests to reconcile again go through the priority queue
+func TestPriorityReconciler(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	p := newPriorityTestQueue(map[string]int32{"prod": 9})
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	reconciler := p.Reconciler(reconcilerFunc(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{Requeue: req.Name == "batch"}, nil
+	}))
+	p.add(queue, priorityRequest("batch"))
+	p.add(queue, priorityRequest("prod"))
+
+	item, _ := queue.Get()
+	result, err := reconciler.Reconcile(context.Background(), item.(reconcile.Request))
+	queue.Done(item)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(result).To(gomega.Equal(reconcile.Result{}))
+	// the requeued request is pending behind the application of high priority
+	g.Expect(p.pending).To(gomega.HaveKey(priorityRequest("batch")))
+	item, _ = queue.Get()
+	g.Expect(item).To(gomega.Equal(priorityRequest("prod")))
+}
+
+// reconcilerFunc implements a reconciler with a function
+type reconcilerFunc func(context.Context, reconcile.Request) (reconcile.Result, error)
+
+func (f reconcilerFunc) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return f(ctx, req)
+}
@@ -0,0 +1,17 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+// Reasons of the Kubernetes events recorded for M4DApplications, shown by kubectl describe
+const (
+	// PlotterCreatedReason is recorded when the plotter of a new generation of the application has been created
+	PlotterCreatedReason string = "PlotterCreated"
+	// PlotterUpdatedReason is recorded when the plotter of the application has been updated
+	PlotterUpdatedReason string = "PlotterUpdated"
+	// PlotterErroredReason is recorded when the plotter of the application reports an error
+	PlotterErroredReason string = "PlotterErrored"
+	// DriftDetectedReason is recorded when the resources generated for the application have been deleted or modified
+	// by others, in which case they are created or repaired again
+	DriftDetectedReason string = "DriftDetected"
+)
@@ -0,0 +1,37 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestPlotterErroredEvent(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	recorder := record.NewFakeRecorder(10)
+	r := &M4DApplicationReconciler{Log: ctrl.Log.WithName("test"), Recorder: recorder}
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"}}
+	evaluate.ResetConditions(application)
+
+	// the error is recorded when it is first reported
+	g.Expect(r.checkReadiness(application, app.ObservedState{Error: "image pull failed"})).To(gomega.Succeed())
+	g.Expect(recorder.Events).To(gomega.Receive(gomega.Equal("Warning PlotterErrored The plotter reports an error: image pull failed")))
+
+	// the same error is not recorded again
+	g.Expect(r.checkReadiness(application, app.ObservedState{Error: "image pull failed"})).To(gomega.Succeed())
+	g.Expect(recorder.Events).NotTo(gomega.Receive())
+
+	// a missing recorder is ignored
+	utils.RecordEvent(nil, application, "Normal", PlotterErroredReason, "Selected modules %s", "arrow-flight-module")
+}
@@ -5,7 +5,12 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -20,11 +25,13 @@ import (
 // ContextInterface is an interface for communication with a generated resource (e.g. Blueprint)
 type ContextInterface interface {
 	ResourceExists(ref *app.ResourceReference) bool
-	CreateOrUpdateResource(owner *app.ResourceReference, ref *app.ResourceReference, blueprintPerClusterMap map[string]app.BlueprintSpec) error
+	CreateOrUpdateResource(owner *app.ResourceReference, ref *app.ResourceReference, blueprintPerClusterMap map[string]app.BlueprintSpec,
+		retryPolicy *app.RetryPolicy) error
 	DeleteResource(ref *app.ResourceReference) error
 	GetResourceStatus(ref *app.ResourceReference) (app.ObservedState, error)
 	CreateResourceReference(owner *app.ResourceReference) *app.ResourceReference
 	GetManagedObject() runtime.Object
+	DetectDrift(ref *app.ResourceReference) (string, error)
 }
 
 // Interface for managing Plotter resources
@@ -72,25 +79,162 @@ func (c *PlotterInterface) GetResourceSignature(ref *app.ResourceReference) *app
 	}
 }
 
-// CreateOrUpdateResource creates a new Plotter resource or updates an existing one
-func (c *PlotterInterface) CreateOrUpdateResource(owner *app.ResourceReference, ref *app.ResourceReference, blueprintPerClusterMap map[string]app.BlueprintSpec) error {
+// CreateOrUpdateResource creates a new Plotter resource or updates an existing one.
+// The desired blueprints are diffed against the blueprints of the observed Plotter spec and the blueprints last applied
+// to it, and only the blueprints of the clusters that have been added, changed or removed are written, so that the
+// deployments of the other clusters are left untouched. The changed clusters are recorded in an annotation, accumulated
+// until the Plotter controller observes the spec and reports them in its status.
+// The retry policy of the application is passed to the Plotter controller, which orchestrates the blueprints.
+// The digest of the written spec is recorded in the reference, to detect later modifications of the Plotter by others.
+func (c *PlotterInterface) CreateOrUpdateResource(owner *app.ResourceReference, ref *app.ResourceReference, blueprintPerClusterMap map[string]app.BlueprintSpec,
+	retryPolicy *app.RetryPolicy) error {
 	plotter := c.GetResourceSignature(ref)
+	labels := ownerLabels(types.NamespacedName{Namespace: owner.Namespace, Name: owner.Name})
+	labels[app.ApplicationVersionLabel] = strconv.FormatInt(owner.AppVersion, 10)
 	if err := c.Client.Get(context.Background(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, plotter); err == nil {
-		if equality.Semantic.DeepEqual(&plotter.Spec.Blueprints, &blueprintPerClusterMap) {
+		if len(diffBlueprints(appliedBlueprints(plotter), plotter.Spec.Blueprints, blueprintPerClusterMap)) == 0 &&
+			plotter.Annotations[app.AppliedBlueprintsAnnotation] == strings.Join(sortedClusters(blueprintPerClusterMap), ",") &&
+			equality.Semantic.DeepEqual(plotter.Labels, labels) && equality.Semantic.DeepEqual(plotter.Spec.RetryPolicy, retryPolicy) {
 			// nothing needs to be done
+			ref.SpecDigest = specDigest(&plotter.Spec)
 			return nil
 		}
 	}
 	if _, err := ctrl.CreateOrUpdate(context.Background(), c.Client, plotter, func() error {
-		plotter.Spec.Blueprints = blueprintPerClusterMap
-		plotter.Labels = ownerLabels(types.NamespacedName{Namespace: owner.Namespace, Name: owner.Name})
+		// the diff is computed against the plotter read by CreateOrUpdate, which has no blueprints if it does not exist yet
+		changed := diffBlueprints(appliedBlueprints(plotter), plotter.Spec.Blueprints, blueprintPerClusterMap)
+		if plotter.Annotations == nil {
+			plotter.Annotations = map[string]string{}
+		}
+		if len(changed) > 0 || !equality.Semantic.DeepEqual(plotter.Spec.RetryPolicy, retryPolicy) {
+			reported := changed
+			// the changes of the generations that the Plotter controller has not observed yet are still to be reported
+			if plotter.Generation != plotter.Status.ObservedGeneration {
+				reported = mergeClusters(reported, splitClusters(plotter.Annotations[app.ChangedBlueprintsAnnotation]))
+			}
+			plotter.Annotations[app.ChangedBlueprintsAnnotation] = strings.Join(reported, ",")
+		}
+		plotter.Annotations[app.AppliedBlueprintsAnnotation] = strings.Join(sortedClusters(blueprintPerClusterMap), ",")
+		if plotter.Spec.Blueprints == nil {
+			plotter.Spec.Blueprints = make(map[string]app.BlueprintSpec, len(blueprintPerClusterMap))
+		}
+		for _, cluster := range changed {
+			if blueprint, found := blueprintPerClusterMap[cluster]; found {
+				plotter.Spec.Blueprints[cluster] = blueprint
+			} else {
+				delete(plotter.Spec.Blueprints, cluster)
+			}
+		}
+		plotter.Spec.RetryPolicy = retryPolicy
+		plotter.Labels = labels
 		return nil
 	}); err != nil {
 		return err
 	}
+	// the plotter holds the spec returned by the API server
+	ref.SpecDigest = specDigest(&plotter.Spec)
 	return nil
 }
 
+// specDigest returns a digest of the parts of a Plotter spec written by the manager, i.e. the blueprints and the retry policy
+func specDigest(spec *app.PlotterSpec) string {
+	data, err := json.Marshal(&app.PlotterSpec{Blueprints: spec.Blueprints, RetryPolicy: spec.RetryPolicy})
+	if err != nil {
+		return ""
+	}
+	return utils.Hash(string(data), 20)
+}
+
+// DetectDrift compares the generated Plotter resource with the state recorded in the reference, and describes how
+// the Plotter has drifted from it: the Plotter has been deleted, or its spec has been modified by others.
+// An empty description is returned if the Plotter has not drifted, or the reference records no digest of its spec.
+func (c *PlotterInterface) DetectDrift(ref *app.ResourceReference) (string, error) {
+	if ref == nil || ref.Namespace == "" {
+		return "", nil
+	}
+	plotter := c.GetResourceSignature(ref)
+	if err := c.Client.Get(context.Background(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, plotter); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "Plotter " + ref.Namespace + "/" + ref.Name + " has been deleted", nil
+		}
+		return "", err
+	}
+	if ref.SpecDigest != "" && specDigest(&plotter.Spec) != ref.SpecDigest {
+		return "the spec of Plotter " + ref.Namespace + "/" + ref.Name + " has been modified", nil
+	}
+	return "", nil
+}
+
+// diffBlueprints returns the sorted clusters whose blueprints have to be created, updated or removed, by a three-way diff
+// of the desired blueprints, the observed ones and the clusters whose blueprints have been last applied:
+// the desired blueprints that differ from the observed ones are written, and the observed blueprints that are not
+// desired any more are removed if they have been applied. The blueprints added by others are left untouched.
+// All the observed blueprints are considered applied if the applied clusters are unknown, i.e. nil.
+func diffBlueprints(applied map[string]bool, observed map[string]app.BlueprintSpec, desired map[string]app.BlueprintSpec) []string {
+	var changed []string
+	for cluster, blueprint := range desired {
+		if current, found := observed[cluster]; !found || !equality.Semantic.DeepEqual(&current, &blueprint) {
+			changed = append(changed, cluster)
+		}
+	}
+	for cluster := range observed {
+		if _, found := desired[cluster]; !found && (applied == nil || applied[cluster]) {
+			changed = append(changed, cluster)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// appliedBlueprints returns the clusters whose blueprints have been last applied to the plotter,
+// or nil if they are not recorded, e.g. for plotters generated by older versions of the manager
+func appliedBlueprints(plotter *app.Plotter) map[string]bool {
+	recorded, found := plotter.Annotations[app.AppliedBlueprintsAnnotation]
+	if !found {
+		return nil
+	}
+	applied := map[string]bool{}
+	for _, cluster := range splitClusters(recorded) {
+		applied[cluster] = true
+	}
+	return applied
+}
+
+// sortedClusters returns the sorted clusters of the blueprints
+func sortedClusters(blueprints map[string]app.BlueprintSpec) []string {
+	clusters := make([]string, 0, len(blueprints))
+	for cluster := range blueprints {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// splitClusters returns the clusters of a comma separated list
+func splitClusters(list string) []string {
+	var clusters []string
+	for _, cluster := range strings.Split(list, ",") {
+		if cluster != "" {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+// mergeClusters returns the sorted union of the given clusters
+func mergeClusters(clusters []string, others []string) []string {
+	merged := map[string]bool{}
+	for _, cluster := range append(append([]string{}, clusters...), others...) {
+		merged[cluster] = true
+	}
+	union := make([]string, 0, len(merged))
+	for cluster := range merged {
+		union = append(union, cluster)
+	}
+	sort.Strings(union)
+	return union
+}
+
 // DeleteResource deletes the generated Plotter resource
 func (c *PlotterInterface) DeleteResource(ref *app.ResourceReference) error {
 	resource := c.GetResourceSignature(ref)
@@ -100,7 +244,9 @@ func (c *PlotterInterface) DeleteResource(ref *app.ResourceReference) error {
 	return nil
 }
 
-// GetResourceStatus returns the generated Plotter status
+// GetResourceStatus returns the generated Plotter status.
+// An empty status is returned while the Plotter has not been generated for the application version of the reference,
+// or the Plotter controller has not observed the latest Plotter spec, since the status belongs to an older generation.
 func (c *PlotterInterface) GetResourceStatus(ref *app.ResourceReference) (app.ObservedState, error) {
 	if ref == nil || ref.Namespace == "" {
 		return app.ObservedState{}, nil
@@ -109,6 +255,10 @@ func (c *PlotterInterface) GetResourceStatus(ref *app.ResourceReference) (app.Ob
 	if err := c.Client.Get(context.Background(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, resource); err != nil {
 		return app.ObservedState{}, err
 	}
+	if resource.Labels[app.ApplicationVersionLabel] != strconv.FormatInt(ref.AppVersion, 10) ||
+		resource.Status.ObservedGeneration != resource.Generation {
+		return app.ObservedState{}, nil
+	}
 	return resource.Status.ObservedState, nil
 }
 
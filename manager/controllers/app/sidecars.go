@@ -0,0 +1,130 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"emperror.dev/errors"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"helm.sh/helm/v3/pkg/postrender"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// sidecarInjector is a Helm post-renderer adding the governance agents to the pod templates of the rendered manifests,
+// such that the charts of the modules do not have to render them
+type sidecarInjector struct {
+	sidecars []app.Sidecar
+}
+
+// newSidecarInjector returns a post-renderer injecting the given sidecars, or nil if there are none
+func newSidecarInjector(sidecars []app.Sidecar) postrender.PostRenderer {
+	if len(sidecars) == 0 {
+		return nil
+	}
+	return &sidecarInjector{sidecars: sidecars}
+}
+
+// Run adds the sidecars to the pods of the workloads among the rendered manifests
+func (s *sidecarInjector) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(renderedManifests, 4096)
+	modified := &bytes.Buffer{}
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "could not parse the rendered manifests")
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if err := s.inject(obj); err != nil {
+			return nil, err
+		}
+		manifest, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		modified.WriteString("---\n")
+		modified.Write(manifest)
+	}
+	return modified, nil
+}
+
+// inject adds the sidecars to the pod template of a workload.
+// The pods of jobs run to completion, the sidecars are thus added as init containers that are restarted always,
+// i.e. native sidecars that are stopped once the containers of the job have completed.
+func (s *sidecarInjector) inject(obj *unstructured.Unstructured) error {
+	var path []string
+	completes := false
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+		path = []string{"spec", "template", "spec"}
+	case "Job":
+		path, completes = []string{"spec", "template", "spec"}, true
+	case "CronJob":
+		path, completes = []string{"spec", "jobTemplate", "spec", "template", "spec"}, true
+	case "Pod":
+		path = []string{"spec"}
+		policy, _, _ := unstructured.NestedString(obj.Object, "spec", "restartPolicy")
+		completes = policy == "Never" || policy == "OnFailure"
+	default:
+		return nil
+	}
+	field := "containers"
+	if completes {
+		field = "initContainers"
+	}
+	containers, _, err := unstructured.NestedSlice(obj.Object, append(path, field)...)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pod template in %s %s", obj.GetKind(), obj.GetName())
+	}
+	for i := range s.sidecars {
+		containers = append(containers, sidecarContainer(&s.sidecars[i], completes))
+	}
+	return unstructured.SetNestedSlice(obj.Object, containers, append(path, field)...)
+}
+
+// sidecarContainer returns the container of a governance agent
+func sidecarContainer(sidecar *app.Sidecar, native bool) map[string]interface{} {
+	container := map[string]interface{}{
+		"name":  sidecar.Name,
+		"image": sidecar.Image,
+	}
+	if native {
+		container["restartPolicy"] = "Always"
+	}
+	if len(sidecar.Args) > 0 {
+		args := make([]interface{}, 0, len(sidecar.Args))
+		for _, arg := range sidecar.Args {
+			args = append(args, arg)
+		}
+		container["args"] = args
+	}
+	if len(sidecar.Env) > 0 {
+		names := make([]string, 0, len(sidecar.Env))
+		for name := range sidecar.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		env := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			env = append(env, map[string]interface{}{"name": name, "value": sidecar.Env[name]})
+		}
+		container["env"] = env
+	}
+	if sidecar.Resources != nil {
+		if resources, err := runtime.DefaultUnstructuredConverter.ToUnstructured(sidecar.Resources); err == nil {
+			container["resources"] = resources
+		}
+	}
+	return container
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// TestInjectSidecars checks that the governance sidecars are added to the pods rendered by the chart of a module,
+// as native sidecars in the pods of jobs, which would otherwise never complete
+func TestInjectSidecars(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(newSidecarInjector(nil)).To(gomega.BeNil())
+	injector := newSidecarInjector([]app.Sidecar{
+		{Name: "audit-logger", Image: "audit-logger:0.1.0", Args: []string{"--sink", "http://audit"}, Env: map[string]string{"LOG_LEVEL": "info"}},
+	})
+	manifests := bytes.NewBufferString(`---
+# Source: module/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: read-module
+spec:
+  template:
+    spec:
+      containers:
+      - name: server
+        image: server:1.0.0
+---
+# Source: module/templates/job.yaml
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: copy-module
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: copy
+        image: copy:1.0.0
+---
+# Source: module/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: read-module
+spec:
+  ports:
+  - port: 80
+`)
+	rendered, err := injector.Run(manifests)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(rendered, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			break
+		}
+		objects = append(objects, obj)
+	}
+	g.Expect(objects).To(gomega.HaveLen(3))
+
+	containers, _, _ := unstructured.NestedSlice(objects[0].Object, "spec", "template", "spec", "containers")
+	g.Expect(containers).To(gomega.HaveLen(2))
+	g.Expect(containers[1]).To(gomega.Equal(map[string]interface{}{
+		"name":  "audit-logger",
+		"image": "audit-logger:0.1.0",
+		"args":  []interface{}{"--sink", "http://audit"},
+		"env":   []interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "info"}},
+	}))
+
+	containers, _, _ = unstructured.NestedSlice(objects[1].Object, "spec", "template", "spec", "containers")
+	g.Expect(containers).To(gomega.HaveLen(1))
+	initContainers, _, _ := unstructured.NestedSlice(objects[1].Object, "spec", "template", "spec", "initContainers")
+	g.Expect(initContainers).To(gomega.HaveLen(1))
+	g.Expect(initContainers[0]).To(gomega.HaveKeyWithValue("restartPolicy", "Always"))
+
+	g.Expect(objects[2].GetKind()).To(gomega.Equal("Service"))
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+)
+
+// getDataRequirements maps the datasets of the application to their requirements
+func getDataRequirements(application *app.M4DApplication) map[string]app.DataRequirements {
+	requirements := make(map[string]app.DataRequirements, len(application.Spec.Data))
+	for _, dataCtx := range application.Spec.Data {
+		requirements[dataCtx.DataSetID] = dataCtx.Requirements
+	}
+	return requirements
+}
+
+// getSpecChanges returns a summary of the differences between the data requirements of two generations.
+// Datasets are reported in the order of the current spec, followed by the removed datasets sorted by identifier.
+func getSpecChanges(application *app.M4DApplication, previous map[string]app.DataRequirements) []string {
+	var changes []string
+	current := getDataRequirements(application)
+	for _, dataCtx := range application.Spec.Data {
+		id := dataCtx.DataSetID
+		old, found := previous[id]
+		if !found {
+			changes = append(changes, fmt.Sprintf("dataset %s has been added", id))
+			continue
+		}
+		if old.Interface != dataCtx.Requirements.Interface {
+			changes = append(changes, fmt.Sprintf("dataset %s: interface changed from %s/%s to %s/%s", id,
+				old.Interface.Protocol, old.Interface.DataFormat,
+				dataCtx.Requirements.Interface.Protocol, dataCtx.Requirements.Interface.DataFormat))
+		}
+		if !reflect.DeepEqual(old.WriteInterface, dataCtx.Requirements.WriteInterface) {
+			changes = append(changes, fmt.Sprintf("dataset %s: write interface changed", id))
+		}
+		if !reflect.DeepEqual(old.FallbackInterfaces, dataCtx.Requirements.FallbackInterfaces) {
+			changes = append(changes, fmt.Sprintf("dataset %s: fallback interfaces changed", id))
+		}
+		if !reflect.DeepEqual(old.Copy, dataCtx.Requirements.Copy) {
+			changes = append(changes, fmt.Sprintf("dataset %s: copy requirements changed", id))
+		}
+		if old.PerformanceClass != dataCtx.Requirements.PerformanceClass {
+			changes = append(changes, fmt.Sprintf("dataset %s: performance class changed from %q to %q", id,
+				old.PerformanceClass, dataCtx.Requirements.PerformanceClass))
+		}
+	}
+	var removed []string
+	for id := range previous {
+		if _, found := current[id]; !found {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		changes = append(changes, fmt.Sprintf("dataset %s has been removed", id))
+	}
+	return changes
+}
+
+// updateSpecChanges records the changes relative to the previously reconciled generation, if there was one,
+// and stores the data requirements of the current generation for the next comparison.
+func updateSpecChanges(application *app.M4DApplication) {
+	if application.Status.ObservedData != nil {
+		application.Status.SpecChanges = getSpecChanges(application, application.Status.ObservedData)
+	}
+	application.Status.ObservedData = getDataRequirements(application)
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+)
+
+func TestSpecChanges(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	arrow := app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	s3 := app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet}
+
+	application := &app.M4DApplication{}
+	application.Spec.Data = []app.DataContext{
+		{DataSetID: "s3/allow-dataset", Requirements: app.DataRequirements{Interface: arrow}},
+		{DataSetID: "db2/redact-dataset", Requirements: app.DataRequirements{Interface: arrow}},
+	}
+	// the first generation has nothing to compare with
+	updateSpecChanges(application)
+	g.Expect(application.Status.SpecChanges).To(gomega.BeEmpty())
+	g.Expect(application.Status.ObservedData).To(gomega.HaveLen(2))
+
+	application.Spec.Data = []app.DataContext{
+		{DataSetID: "s3/allow-dataset", Requirements: app.DataRequirements{Interface: s3}},
+		{DataSetID: "s3/redact-dataset", Requirements: app.DataRequirements{Interface: arrow}},
+	}
+	updateSpecChanges(application)
+	g.Expect(application.Status.SpecChanges).To(gomega.Equal([]string{
+		"dataset s3/allow-dataset: interface changed from m4d-arrow-flight/arrow to s3/parquet",
+		"dataset s3/redact-dataset has been added",
+		"dataset db2/redact-dataset has been removed",
+	}))
+
+	// no changes
+	updateSpecChanges(application)
+	g.Expect(application.Status.SpecChanges).To(gomega.BeEmpty())
+
+	application.Spec.Data[1].Requirements.WriteInterface = &s3
+	updateSpecChanges(application)
+	g.Expect(application.Status.SpecChanges).To(gomega.Equal([]string{"dataset s3/redact-dataset: write interface changed"}))
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// minStalenessCheckInterval bounds the frequency of the data catalog queries made to check the freshness of the copies
+const minStalenessCheckInterval = time.Minute
+
+// setCopyTimes records the current time as the copy time of the provisioned storage that has no copy time yet.
+// It is called once the application has become ready, i.e. the copies have been completed.
+func setCopyTimes(application *app.M4DApplication) {
+	now := metav1.Now()
+	for datasetID, details := range application.Status.ProvisionedStorage {
+		if details.CopiedAt == nil {
+			details.CopiedAt = &now
+			application.Status.ProvisionedStorage[datasetID] = details
+		}
+	}
+}
+
+// checkStaleness reports in the status the datasets whose copies have been out of date for longer than the allowed
+// staleness, according to the modification time of the source assets in the data catalog.
+// It returns the interval after which the freshness has to be checked again, zero if no dataset requires it.
+func (r *M4DApplicationReconciler) checkStaleness(application *app.M4DApplication) (time.Duration, error) {
+	var stale []string
+	var interval time.Duration
+	evaluator := r.newEvaluator()
+	for _, dataCtx := range application.Spec.Data {
+		maxStaleness := dataCtx.Requirements.Copy.MaxStaleness
+		if maxStaleness == nil {
+			continue
+		}
+		details, found := application.Status.ProvisionedStorage[dataCtx.DataSetID]
+		if !found || details.CopiedAt == nil {
+			continue
+		}
+		if interval == 0 || maxStaleness.Duration < interval {
+			interval = maxStaleness.Duration
+		}
+		req := modules.DataInfo{Context: dataCtx.DeepCopy()}
+		if err := evaluator.ConstructDataInfo(&req, application); err != nil {
+			return minStalenessCheckInterval, err
+		}
+		if isStale(details.CopiedAt.Time, req.DataDetails.LastModified, maxStaleness.Duration, time.Now()) {
+			r.Log.V(0).Info("The copy of " + dataCtx.DataSetID + " is stale")
+			stale = append(stale, dataCtx.DataSetID)
+		}
+	}
+	application.Status.StaleDatasets = stale
+	if interval > 0 && interval < minStalenessCheckInterval {
+		interval = minStalenessCheckInterval
+	}
+	return interval, nil
+}
+
+// isStale returns true if the source has been modified after the copy has been made, and the copy
+// has been out of date since then for longer than maxStaleness
+func isStale(copiedAt time.Time, lastModified time.Time, maxStaleness time.Duration, now time.Time) bool {
+	return !lastModified.IsZero() && lastModified.After(copiedAt) && now.Sub(lastModified) > maxStaleness
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsStale(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	copiedAt := now.Add(-2 * time.Hour)
+	// the source has not been modified since the copy
+	g.Expect(isStale(copiedAt, copiedAt.Add(-time.Hour), time.Hour, now)).To(gomega.BeFalse())
+	// the modification time is unknown
+	g.Expect(isStale(copiedAt, time.Time{}, time.Hour, now)).To(gomega.BeFalse())
+	// the source has been modified recently, within the allowed staleness
+	g.Expect(isStale(copiedAt, now.Add(-10*time.Minute), time.Hour, now)).To(gomega.BeFalse())
+	// the copy has been out of date for longer than the allowed staleness
+	g.Expect(isStale(copiedAt, now.Add(-90*time.Minute), time.Hour, now)).To(gomega.BeTrue())
+}
+
+func TestSetCopyTimes(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	previous := metav1.NewTime(time.Now().Add(-time.Hour))
+	application := &app.M4DApplication{
+		Status: app.M4DApplicationStatus{
+			ProvisionedStorage: map[string]app.DatasetDetails{
+				"s3/allow-dataset":   {DatasetRef: "bucket-1", CopiedAt: &previous},
+				"db2/redact-dataset": {DatasetRef: "bucket-2"},
+			},
+		},
+	}
+	setCopyTimes(application)
+	g.Expect(application.Status.ProvisionedStorage["s3/allow-dataset"].CopiedAt).To(gomega.Equal(&previous))
+	g.Expect(application.Status.ProvisionedStorage["db2/redact-dataset"].CopiedAt).NotTo(gomega.BeNil())
+}
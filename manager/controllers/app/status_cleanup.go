@@ -0,0 +1,55 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sort"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pruneStatus removes the entries of the read endpoints and of the cataloged assets of the datasets that are no longer
+// requested by the application, once its current generation has been reconciled.
+// The status is the only record of the registration of the copies in the catalog, thus the pruned cataloged assets
+// are exported as audit events if configured.
+func (r *M4DApplicationReconciler) pruneStatus(application *app.M4DApplication) {
+	if application.Status.ObservedGeneration != application.Generation {
+		return
+	}
+	// the data plane of the previously granted generation remains deployed in the fail-open mode
+	if condition := meta.FindStatusCondition(application.Status.Conditions, app.PolicyManagerUnavailableCondition); condition != nil &&
+		condition.Status == metav1.ConditionTrue && condition.Reason == app.FailOpenReason {
+		return
+	}
+	requested := make(map[string]bool, len(application.Spec.Data))
+	for _, dataCtx := range application.Spec.Data {
+		requested[dataCtx.DataSetID] = true
+	}
+	for datasetID := range application.Status.ReadEndpointsMap {
+		if !requested[datasetID] {
+			delete(application.Status.ReadEndpointsMap, datasetID)
+		}
+	}
+	pruned := []string{}
+	for datasetID := range application.Status.CatalogedAssets {
+		if !requested[datasetID] {
+			pruned = append(pruned, datasetID)
+		}
+	}
+	sort.Strings(pruned)
+	for _, datasetID := range pruned {
+		assetID := application.Status.CatalogedAssets[datasetID]
+		delete(application.Status.CatalogedAssets, datasetID)
+		r.Log.V(0).Info("Pruning the cataloged asset of a dataset no longer requested", "dataset", datasetID, "asset", assetID)
+		if utils.AuditPrunedAssets() {
+			evaluate.EmitEvent(r.Events, application, events.Event{Type: events.CatalogedAssetPruned, DatasetID: datasetID,
+				Reason: "The copy of the dataset is registered as " + assetID})
+		}
+	}
+}
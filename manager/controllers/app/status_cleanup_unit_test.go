@@ -0,0 +1,76 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"os"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// recordedEvents keeps the emitted events
+type recordedEvents []events.Event
+
+func (e *recordedEvents) Emit(event events.Event) {
+	*e = append(*e, event)
+}
+
+func TestPruneStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(os.Setenv(utils.AuditPrunedAssetsKey, "true")).To(gomega.Succeed())
+	defer os.Unsetenv(utils.AuditPrunedAssetsKey)
+
+	emitted := &recordedEvents{}
+	r := &M4DApplicationReconciler{Log: ctrl.Log.WithName("test"), Events: emitted}
+	newApplication := func() *app.M4DApplication {
+		application := &app.M4DApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default", Generation: 2},
+			Spec:       app.M4DApplicationSpec{Data: []app.DataContext{{DataSetID: "s3/allow-dataset"}}},
+			Status: app.M4DApplicationStatus{
+				ObservedGeneration: 2,
+				ReadEndpointsMap: map[string]app.EndpointSpec{
+					"s3/allow-dataset":   {Hostname: "read-allow"},
+					"db2/redact-dataset": {Hostname: "read-redact"},
+				},
+				CatalogedAssets: map[string]string{
+					"s3/allow-dataset":   "asset-1",
+					"db2/redact-dataset": "asset-2",
+				},
+			},
+		}
+		evaluate.ResetConditions(application)
+		return application
+	}
+
+	// the entries of the datasets removed from the spec are pruned
+	application := newApplication()
+	r.pruneStatus(application)
+	g.Expect(application.Status.ReadEndpointsMap).To(gomega.HaveKey("s3/allow-dataset"))
+	g.Expect(application.Status.ReadEndpointsMap).To(gomega.HaveLen(1))
+	g.Expect(application.Status.CatalogedAssets).To(gomega.Equal(map[string]string{"s3/allow-dataset": "asset-1"}))
+	g.Expect(*emitted).To(gomega.HaveLen(1))
+	g.Expect((*emitted)[0].Type).To(gomega.Equal(events.CatalogedAssetPruned))
+	g.Expect((*emitted)[0].DatasetID).To(gomega.Equal("db2/redact-dataset"))
+	g.Expect((*emitted)[0].Reason).To(gomega.ContainSubstring("asset-2"))
+
+	// the status is kept until the current generation has been reconciled
+	application = newApplication()
+	application.Generation = 3
+	r.pruneStatus(application)
+	g.Expect(application.Status.CatalogedAssets).To(gomega.HaveLen(2))
+
+	// the status is kept while the previously granted data plane remains deployed
+	application = newApplication()
+	setPolicyManagerUnavailableCondition(application, app.FailOpenReason, app.PolicyManagerUnavailable)
+	r.pruneStatus(application)
+	g.Expect(application.Status.ReadEndpointsMap).To(gomega.HaveLen(2))
+	g.Expect(*emitted).To(gomega.HaveLen(1))
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// stepRerunDelay is the delay before the first rerun of the failed jobs of a step, doubled after each rerun
+const stepRerunDelay = 10 * time.Second
+
+// stepReadiness returns the readiness semantics of a step: copy steps are run to completion,
+// while read and write steps serve the workload as long as they are available
+func stepReadiness(step *app.FlowStep) app.StepReadiness {
+	if step.Arguments.Copy != nil {
+		return app.CompletionReadiness
+	}
+	return app.AvailabilityReadiness
+}
+
+// stepState converts the aggregated status of the resources of a step into its state
+func stepState(status corev1.ConditionStatus) app.StepState {
+	switch status {
+	case corev1.ConditionTrue:
+		return app.StepReady
+	case corev1.ConditionFalse:
+		return app.StepFailed
+	default:
+		return app.StepPending
+	}
+}
+
+// rerunDelay returns the delay before rerunning the failed jobs of a step that have already been rerun the given number of times
+func rerunDelay(reruns int32) time.Duration {
+	return stepRerunDelay << reruns
+}
+
+// rerunsPending returns true if the failed jobs of a step of the blueprint are still to be run again,
+// i.e. the failure of the blueprint is not final yet
+func rerunsPending(status *app.BlueprintStatus) bool {
+	for _, step := range status.Steps {
+		if step.Readiness == app.CompletionReadiness && step.State == app.StepFailed && step.Reruns < app.MaxStepReruns {
+			return true
+		}
+	}
+	return false
+}
+
+// readinessStatus computes the status of the jobs and deployments of a step according to its readiness semantics.
+// It returns false if the readiness of the resource is not defined by the step semantics.
+func readinessStatus(res *unstructured.Unstructured, readiness app.StepReadiness) (corev1.ConditionStatus, string, bool) {
+	switch res.GetKind() {
+	case "Job":
+		// a running job of a step that is not run to completion, e.g. an initialization job, does not block its readiness
+		if readiness != app.CompletionReadiness {
+			return "", "", false
+		}
+		status, msg := jobStatus(res)
+		return status, msg, true
+	case "Deployment":
+		status, msg := deploymentStatus(res)
+		return status, msg, true
+	}
+	return "", "", false
+}
+
+// jobStatus returns True once the job has succeeded, False if it has failed, and Unknown while it is running
+func jobStatus(res *unstructured.Unstructured) (corev1.ConditionStatus, string) {
+	if status, _ := findCondition(res, "Complete"); status == corev1.ConditionTrue {
+		return corev1.ConditionTrue, ""
+	}
+	if status, msg := findCondition(res, "Failed"); status == corev1.ConditionTrue {
+		return corev1.ConditionFalse, "job " + res.GetName() + " has failed: " + msg
+	}
+	return corev1.ConditionUnknown, ""
+}
+
+// deploymentStatus returns True once all the replicas of the deployment have been updated and are available,
+// False if the deployment has exceeded its progress deadline, and Unknown otherwise
+func deploymentStatus(res *unstructured.Unstructured) (corev1.ConditionStatus, string) {
+	if status, msg := findCondition(res, "Progressing"); status == corev1.ConditionFalse {
+		return corev1.ConditionFalse, "deployment " + res.GetName() + " is not progressing: " + msg
+	}
+	if status, _ := findCondition(res, "Available"); status != corev1.ConditionTrue {
+		return corev1.ConditionUnknown, ""
+	}
+	replicas, found, _ := unstructured.NestedInt64(res.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	updated, _, _ := unstructured.NestedInt64(res.Object, "status", "updatedReplicas")
+	available, _, _ := unstructured.NestedInt64(res.Object, "status", "availableReplicas")
+	if updated < replicas || available < replicas {
+		return corev1.ConditionUnknown, ""
+	}
+	return corev1.ConditionTrue, ""
+}
+
+// findCondition returns the status and the message of a condition of the resource, an empty status if it is not found
+func findCondition(res *unstructured.Unstructured, conditionType string) (corev1.ConditionStatus, string) {
+	conditions, _, _ := unstructured.NestedSlice(res.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		msg, _ := condition["message"].(string)
+		return corev1.ConditionStatus(status), msg
+	}
+	return "", ""
+}
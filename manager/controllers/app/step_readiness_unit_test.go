@@ -0,0 +1,87 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func resourceWithStatus(kind string, spec map[string]interface{}, status map[string]interface{}) *unstructured.Unstructured {
+	res := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec, "status": status}}
+	res.SetKind(kind)
+	res.SetName("module")
+	return res
+}
+
+func resourceCondition(conditionType string, status string) interface{} {
+	return map[string]interface{}{"type": conditionType, "status": status, "message": conditionType + " is " + status}
+}
+
+func TestStepReadiness(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(stepReadiness(&app.FlowStep{Arguments: app.ModuleArguments{Copy: &app.CopyModuleArgs{}}})).To(gomega.Equal(app.CompletionReadiness))
+	g.Expect(stepReadiness(&app.FlowStep{Arguments: app.ModuleArguments{Read: []app.ReadModuleArgs{{}}}})).To(gomega.Equal(app.AvailabilityReadiness))
+	g.Expect(rerunDelay(0)).To(gomega.Equal(stepRerunDelay))
+	g.Expect(rerunDelay(2)).To(gomega.Equal(4 * stepRerunDelay))
+}
+
+func TestJobReadiness(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	running := resourceWithStatus("Job", nil, map[string]interface{}{"active": int64(1)})
+	succeeded := resourceWithStatus("Job", nil, map[string]interface{}{"conditions": []interface{}{resourceCondition("Complete", "True")}})
+	failed := resourceWithStatus("Job", nil, map[string]interface{}{"conditions": []interface{}{resourceCondition("Failed", "True")}})
+
+	// a copy step is ready once its job has succeeded
+	status, _, defined := readinessStatus(running, app.CompletionReadiness)
+	g.Expect(defined).To(gomega.BeTrue())
+	g.Expect(status).To(gomega.Equal(corev1.ConditionUnknown))
+	status, _, _ = readinessStatus(succeeded, app.CompletionReadiness)
+	g.Expect(status).To(gomega.Equal(corev1.ConditionTrue))
+	status, msg, _ := readinessStatus(failed, app.CompletionReadiness)
+	g.Expect(status).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(msg).To(gomega.ContainSubstring("job module has failed"))
+
+	// the jobs of the other steps are left to kstatus
+	_, _, defined = readinessStatus(running, app.AvailabilityReadiness)
+	g.Expect(defined).To(gomega.BeFalse())
+}
+
+func TestDeploymentReadiness(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	spec := map[string]interface{}{"replicas": int64(2)}
+	rollingOut := resourceWithStatus("Deployment", spec, map[string]interface{}{
+		"conditions":        []interface{}{resourceCondition("Available", "True"), resourceCondition("Progressing", "True")},
+		"updatedReplicas":   int64(1),
+		"availableReplicas": int64(2),
+	})
+	available := resourceWithStatus("Deployment", spec, map[string]interface{}{
+		"conditions":        []interface{}{resourceCondition("Available", "True"), resourceCondition("Progressing", "True")},
+		"updatedReplicas":   int64(2),
+		"availableReplicas": int64(2),
+	})
+	stuck := resourceWithStatus("Deployment", spec, map[string]interface{}{
+		"conditions": []interface{}{resourceCondition("Available", "False"), resourceCondition("Progressing", "False")},
+	})
+
+	status, _, defined := readinessStatus(rollingOut, app.AvailabilityReadiness)
+	g.Expect(defined).To(gomega.BeTrue())
+	g.Expect(status).To(gomega.Equal(corev1.ConditionUnknown))
+	status, _, _ = readinessStatus(available, app.AvailabilityReadiness)
+	g.Expect(status).To(gomega.Equal(corev1.ConditionTrue))
+	status, msg, _ := readinessStatus(stuck, app.AvailabilityReadiness)
+	g.Expect(status).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(msg).To(gomega.ContainSubstring("deployment module is not progressing"))
+	g.Expect(stepState(status)).To(gomega.Equal(app.StepFailed))
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Alternative secret keys of the credentials of S3 storage accounts
+var (
+	accessKeys = []string{"accessKeyID", "accessKey", "access_key"}
+	secretKeys = []string{"secretAccessKey", "secretKey", "SecretKey", "secret_key"}
+	apiKeys    = []string{"apiKey", "api_key"}
+)
+
+// StorageAccountReconciler checks that the secrets referenced by M4DStorageAccount resources contain the expected
+// credentials and that their regions are valid, and reports the results as conditions in the status of the storage account
+type StorageAccountReconciler struct {
+	client.Client
+	Name string
+	Log  logr.Logger
+}
+
+// Reconcile validates the credentials and the regions of a M4DStorageAccount
+func (r *StorageAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	account := &app.M4DStorageAccount{}
+	if err := r.Get(ctx, req.NamespacedName, account); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	observedStatus := account.Status.DeepCopy()
+	condition := app.Condition{Type: app.CredentialsValidCondition, Status: corev1.ConditionTrue}
+	if err := r.validateCredentials(ctx, account); err != nil {
+		if !isInvalidCredentials(err) {
+			return ctrl.Result{}, err
+		}
+		condition.Status = corev1.ConditionFalse
+		condition.Message = err.Error()
+		r.Log.V(0).Info("Invalid storage account credentials", "account", req.NamespacedName, "reason", condition.Message)
+	}
+	// the regions of the accounts created before they were validated upon admission are reported, not rejected
+	regions := app.Condition{Type: app.RegionsValidCondition, Status: corev1.ConditionTrue}
+	if err := account.ValidateRegions(); err != nil {
+		regions.Status = corev1.ConditionFalse
+		regions.Message = err.Error()
+		r.Log.V(0).Info("Invalid storage account regions", "account", req.NamespacedName, "reason", regions.Message)
+	}
+	account.Status.Conditions = []app.Condition{condition, regions}
+	if equality.Semantic.DeepEqual(&account.Status, observedStatus) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.Client.Status().Update(ctx, account)
+}
+
+// invalidCredentials is returned when the secret of a storage account exists but does not contain the expected keys
+type invalidCredentials struct {
+	message string
+}
+
+func (e *invalidCredentials) Error() string {
+	return e.message
+}
+
+func isInvalidCredentials(err error) bool {
+	_, ok := err.(*invalidCredentials)
+	return ok
+}
+
+// validateCredentials checks that the endpoint of the storage account is well-formed and that the referenced secret
+// exists. The secret of S3 accounts must contain either an access key and a secret key, or an API key.
+// The keys expected by other types of storage are checked by their provisioners.
+func (r *StorageAccountReconciler) validateCredentials(ctx context.Context, account *app.M4DStorageAccount) error {
+	endpoint := account.Spec.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	if parsed, err := url.Parse(endpoint); err != nil || parsed.Host == "" {
+		return &invalidCredentials{message: fmt.Sprintf("invalid endpoint %q", account.Spec.Endpoint)}
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: account.Spec.SecretRef, Namespace: account.Namespace}
+	if err := r.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &invalidCredentials{message: "secret " + account.Spec.SecretRef + " does not exist"}
+		}
+		return err
+	}
+	if account.Spec.Type != "" && account.Spec.Type != storage.S3 {
+		return nil
+	}
+	if hasAnyKey(secret, apiKeys) || (hasAnyKey(secret, accessKeys) && hasAnyKey(secret, secretKeys)) {
+		return nil
+	}
+	return &invalidCredentials{message: fmt.Sprintf("secret %s must contain an access key (%s) and a secret key (%s), or an API key (%s)",
+		account.Spec.SecretRef, strings.Join(accessKeys, ", "), strings.Join(secretKeys, ", "), strings.Join(apiKeys, ", "))}
+}
+
+// hasAnyKey returns true if the secret contains a non-empty value for one of the keys
+func hasAnyKey(secret *corev1.Secret, keys []string) bool {
+	for _, key := range keys {
+		if len(secret.Data[key]) > 0 || secret.StringData[key] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStorageAccountReconciler creates a new reconciler for M4DStorageAccount resources
+func NewStorageAccountReconciler(mgr ctrl.Manager, name string) *StorageAccountReconciler {
+	return &StorageAccountReconciler{
+		Client: mgr.GetClient(),
+		Name:   name,
+		Log:    ctrl.Log.WithName("controllers").WithName(name),
+	}
+}
+
+// SetupWithManager registers the M4DStorageAccount controller.
+// Storage accounts are validated again when the secrets they reference are modified.
+func (r *StorageAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&app.M4DStorageAccount{}).
+		Watches(&source.Kind{
+			Type: &corev1.Secret{},
+		}, handler.EnqueueRequestsFromMapFunc(r.accountsReferencingSecret)).Complete(r)
+}
+
+// accountsReferencingSecret maps a secret of the control plane namespace to the storage accounts referencing it
+func (r *StorageAccountReconciler) accountsReferencingSecret(a client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	if a.GetNamespace() != utils.GetSystemNamespace() {
+		return requests
+	}
+	var accounts app.M4DStorageAccountList
+	if err := r.List(context.Background(), &accounts, client.InNamespace(a.GetNamespace())); err != nil {
+		r.Log.V(0).Info("Error while listing storage accounts: " + err.Error())
+		return requests
+	}
+	for _, account := range accounts.Items {
+		if account.Spec.SecretRef == a.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: account.Name, Namespace: account.Namespace},
+			})
+		}
+	}
+	return requests
+}
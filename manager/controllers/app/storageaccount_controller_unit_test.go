@@ -0,0 +1,72 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TestStorageAccountCredentials checks the condition reported for the secret referenced by a storage account
+func TestStorageAccountCredentials(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	account := &app.M4DStorageAccount{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/account-theshire.yaml", account)).NotTo(gomega.HaveOccurred())
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: account.Spec.SecretRef, Namespace: account.Namespace},
+		Data:       map[string][]byte{"accessKeyID": []byte("access")},
+	}
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), []runtime.Object{account, secret}...)
+	r := &StorageAccountReconciler{
+		Client: cl,
+		Name:   "TestReconciler",
+		Log:    ctrl.Log.WithName("test-controller"),
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: account.Name, Namespace: account.Namespace}}
+	reconcileAccount := func() *app.M4DStorageAccount {
+		_, err := r.Reconcile(context.Background(), req)
+		g.Expect(err).To(gomega.BeNil())
+		result := &app.M4DStorageAccount{}
+		g.Expect(cl.Get(context.Background(), req.NamespacedName, result)).To(gomega.Succeed())
+		return result
+	}
+
+	// the secret key is missing
+	g.Expect(reconcileAccount().InvalidCredentials()).To(gomega.ContainSubstring("secret key"))
+
+	secret.Data["secretAccessKey"] = []byte("secret")
+	g.Expect(cl.Update(context.Background(), secret)).To(gomega.Succeed())
+	result := reconcileAccount()
+	g.Expect(result.InvalidCredentials()).To(gomega.BeEmpty())
+	g.Expect(result.Status.Conditions).To(gomega.HaveLen(2))
+	g.Expect(result.Status.Conditions[0].Status).To(gomega.Equal(corev1.ConditionTrue))
+	g.Expect(result.Status.Conditions[1].Type).To(gomega.Equal(app.RegionsValidCondition))
+	g.Expect(result.Status.Conditions[1].Status).To(gomega.Equal(corev1.ConditionTrue))
+
+	// the regions of an account created before they were validated are reported in its status
+	result.Spec.Regions = []string{"theshire "}
+	g.Expect(cl.Update(context.Background(), result)).To(gomega.Succeed())
+	result = reconcileAccount()
+	g.Expect(result.Status.Conditions[1].Status).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(result.Status.Conditions[1].Message).To(gomega.ContainSubstring("whitespace"))
+
+	g.Expect(cl.Delete(context.Background(), secret)).To(gomega.Succeed())
+	g.Expect(reconcileAccount().InvalidCredentials()).To(gomega.ContainSubstring("does not exist"))
+
+	// the storage account referencing the secret is reconciled when the secret changes
+	g.Expect(r.accountsReferencingSecret(secret)).To(gomega.ConsistOf(req))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 
 	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
 )
@@ -16,6 +17,10 @@ import (
 type DataCatalogDummy struct {
 	pb.UnimplementedDataCatalogServiceServer
 	dataDetails map[string]pb.CatalogDatasetInfo
+
+	// Registered holds the requests of the registered assets by their identifiers
+	Registered map[string]*pb.RegisterAssetRequest
+	mutex      sync.Mutex
 }
 
 func (d *DataCatalogDummy) GetDatasetInfo(ctx context.Context, in *pb.CatalogDatasetRequest) (*pb.CatalogDatasetInfo, error) {
@@ -37,6 +42,16 @@ func (d *DataCatalogDummy) GetDatasetInfo(ctx context.Context, in *pb.CatalogDat
 	return nil, errors.New("could not find data details")
 }
 
+// RegisterDatasetInfo records the request of a new asset, identified by the destination catalog and the name of its data store
+func (d *DataCatalogDummy) RegisterDatasetInfo(ctx context.Context, in *pb.RegisterAssetRequest) (*pb.RegisterAssetResponse, error) {
+	assetID := in.GetDestinationCatalogId() + "/" + in.GetDatasetDetails().GetDataStore().GetName()
+	log.Printf("MockDataCatalog.RegisterDatasetInfo called with asset " + assetID)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.Registered[assetID] = in
+	return &pb.RegisterAssetResponse{AssetId: assetID}, nil
+}
+
 func (d *DataCatalogDummy) Close() error {
 	return nil
 }
@@ -44,6 +59,7 @@ func (d *DataCatalogDummy) Close() error {
 func NewTestCatalog() *DataCatalogDummy {
 	dummyCatalog := DataCatalogDummy{
 		dataDetails: make(map[string]pb.CatalogDatasetInfo),
+		Registered:  make(map[string]*pb.RegisterAssetRequest),
 	}
 	dummyCatalog.dataDetails["s3-external"] = pb.CatalogDatasetInfo{
 		DatasetId: "s3-external",
@@ -8,18 +8,39 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
 	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// MockPolicyManager is a mock for PolicyManager interface used in tests
+// MockPolicyManager is a mock for PolicyManager interface used in tests.
+// The zero value answers immediately. Latency, intermittent failures and oversized responses can be simulated
+// to test the behavior of the manager under realistic connector conditions.
 type MockPolicyManager struct {
 	connectors.PolicyManager
+
+	// Latency delays each response. The request fails if its context is done before the delay has elapsed.
+	Latency time.Duration
+
+	// FailEvery makes every n-th request fail with an Unavailable error. Zero disables the failures.
+	FailEvery uint64
+
+	// ResponsePadding is the number of bytes added to each response, e.g. to exceed the maximal message size of the client
+	ResponsePadding int
+
+	// requests counts the received requests
+	requests uint64
 }
 
 // GetPoliciesDecisions implements the PolicyCompiler interface
 func (s *MockPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	if err := s.simulateConditions(ctx); err != nil {
+		return nil, err
+	}
 	log.Printf("Received: ")
 	log.Printf("ProcessingGeography: " + in.AppInfo.GetProcessingGeography())
 	log.Printf("Secret: " + in.GetCredentialPath())
@@ -94,6 +115,25 @@ func (s *MockPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.App
 			Decisions: operationDecisions})
 	}
 
+	if s.ResponsePadding > 0 {
+		externalComponents = append(externalComponents, &pb.ComponentVersion{Id: "padding", Name: strings.Repeat("x", s.ResponsePadding)})
+	}
 	return &pb.PoliciesDecisions{ComponentVersions: externalComponents,
 		DatasetDecisions: dataSetWithActions}, nil
 }
+
+// simulateConditions applies the configured latency and failures to a request
+func (s *MockPolicyManager) simulateConditions(ctx context.Context) error {
+	count := atomic.AddUint64(&s.requests, 1)
+	if s.Latency > 0 {
+		select {
+		case <-time.After(s.Latency):
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if s.FailEvery > 0 && count%s.FailEvery == 0 {
+		return status.Errorf(codes.Unavailable, "simulated failure of request %d", count)
+	}
+	return nil
+}
@@ -6,8 +6,11 @@ package utils
 import (
 	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	"github.com/onsi/ginkgo"
 )
 
@@ -16,6 +19,50 @@ const (
 	CatalogConnectorServiceAddressKey string = "CATALOG_CONNECTOR_URL"
 	VaultAddressKey                   string = "VAULT_ADDRESS"
 	VaultModulesRole                  string = "VAULT_MODULES_ROLE"
+	ReadOnlyModeKey                   string = "READ_ONLY_MODE"
+	GovernedCopyMaxSizeKey            string = "GOVERNED_COPY_MAX_SIZE_BYTES"
+	CatalogCheckIntervalKey           string = "CATALOG_CHECK_INTERVAL"
+	DriftCheckIntervalKey             string = "DRIFT_CHECK_INTERVAL"
+	ExternalDNSDomainKey              string = "EXTERNAL_DNS_DOMAIN"
+	CatalogTaxonomySourceKey          string = "CATALOG_TAXONOMY_SOURCE"
+	CatalogTaxonomyChecksumKey        string = "CATALOG_TAXONOMY_CHECKSUM"
+	ModuleTaxonomySourceKey           string = "MODULE_TAXONOMY_SOURCE"
+	ModuleTaxonomyChecksumKey         string = "MODULE_TAXONOMY_CHECKSUM"
+	PrivacyLevelsSourceKey            string = "PRIVACY_LEVELS_SOURCE"
+	PrivacyLevelsChecksumKey          string = "PRIVACY_LEVELS_CHECKSUM"
+	EventSinkTypeKey                  string = "EVENT_SINK_TYPE"
+	EventSinkURLKey                   string = "EVENT_SINK_URL"
+	EventSinkTopicKey                 string = "EVENT_SINK_KAFKA_TOPIC"
+	AuditSinkTypeKey                  string = "AUDIT_SINK_TYPE"
+	AuditSinkTargetKey                string = "AUDIT_SINK_TARGET"
+	PinningAdminGroupsKey             string = "PINNING_ADMIN_GROUPS"
+	ModulesSchedulingKey              string = "MODULES_SCHEDULING"
+	GovernanceSidecarsKey             string = "GOVERNANCE_SIDECARS"
+	PolicyManagerFailModeKey          string = "POLICY_MANAGER_FAIL_MODE"
+	PolicyCacheTTLKey                 string = "POLICY_CACHE_TTL"
+	ApplicationAPIAddressKey          string = "APPLICATION_API_ADDRESS"
+	ApplicationAPICertDirKey          string = "APPLICATION_API_CERT_DIR"
+	EvaluationConcurrencyKey          string = "EVALUATION_CONCURRENCY"
+	CompactEncodingKey                string = "COMPACT_ENCODING"
+	NormalizeDatasetIDsKey            string = "NORMALIZE_DATASET_IDS"
+	CaseInsensitiveCatalogsKey        string = "CASE_INSENSITIVE_CATALOGS"
+	AuditPrunedAssetsKey              string = "AUDIT_PRUNED_ASSETS"
+	ModuleVersionsKey                 string = "MODULE_VERSIONS"
+	WatchedNamespacesKey              string = "WATCHED_NAMESPACES"
+	IgnoredNamespacesKey              string = "IGNORED_NAMESPACES"
+	BucketProvisionerKey              string = "BUCKET_PROVISIONER"
+)
+
+// BlueprintNamespace defines a namespace where blueprints and associated resources will be allocated
+const BlueprintNamespace = "m4d-blueprints"
+
+// Modes of handling the unavailability of the policy manager
+const (
+	// FailClosed reports an error for the applications whose policies can not be evaluated
+	FailClosed string = "fail-closed"
+	// FailOpenWithAudit keeps the previously granted data plane of the applications whose policies can not be evaluated,
+	// and records this in their status and in the audit events
+	FailOpenWithAudit string = "fail-open-with-audit"
 )
 
 // GetSystemNamespace returns the namespace of control plane
@@ -39,6 +86,219 @@ func GetVaultAddress() string {
 	return os.Getenv(VaultAddressKey)
 }
 
+// GetPinningAdminGroups returns the groups whose members may pin the modules of applications,
+// given as a comma separated list. nil is returned if no group is configured.
+func GetPinningAdminGroups() []string {
+	var groups []string
+	for _, group := range strings.Split(os.Getenv(PinningAdminGroupsKey), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// GetModulesScheduling returns the scheduling of the pods of the modules set by the administrators,
+// as a JSON object mapping the capabilities (copy, read, write) to scheduling policies.
+// An empty string is returned if it is not configured.
+func GetModulesScheduling() string {
+	return strings.TrimSpace(os.Getenv(ModulesSchedulingKey))
+}
+
+// GetGovernanceSidecars returns the governance agents injected as sidecars in the pods of the modules requiring them,
+// set by the administrators as a JSON object mapping the names of the agents to their containers.
+// An empty string is returned if it is not configured.
+func GetGovernanceSidecars() string {
+	return strings.TrimSpace(os.Getenv(GovernanceSidecarsKey))
+}
+
+// GetModuleVersions returns the semantic version ranges of the modules that may be selected, set by the administrators
+// as a JSON object mapping the repositories of the charts of the modules to the ranges.
+// An empty string is returned if it is not configured.
+func GetModuleVersions() string {
+	return strings.TrimSpace(os.Getenv(ModuleVersionsKey))
+}
+
+// Provisioners of the S3 buckets of the storage accounts
+const (
+	// DatasetProvisioner provisions the buckets through the Dataset resources of Datashim
+	DatasetProvisioner string = "dataset"
+	// S3APIProvisioner creates the buckets directly through the S3 API of the storage accounts
+	S3APIProvisioner string = "s3-api"
+)
+
+// GetBucketProvisioner returns the provisioner of the S3 buckets set by the administrators, either dataset or s3-api.
+// An empty string is returned if it is not configured, in which case the Dataset resources are used if their CRD
+// is installed.
+func GetBucketProvisioner() string {
+	return strings.TrimSpace(os.Getenv(BucketProvisionerKey))
+}
+
+// GetPolicyManagerFailMode returns the mode of handling the unavailability of the policy manager, fail-closed by default
+func GetPolicyManagerFailMode() string {
+	if strings.TrimSpace(os.Getenv(PolicyManagerFailModeKey)) == FailOpenWithAudit {
+		return FailOpenWithAudit
+	}
+	return FailClosed
+}
+
+// GetPolicyCacheTTL returns the duration for which the decisions of the policy manager are cached.
+// Zero is returned if the TTL is not configured, in which case the decisions are not cached.
+func GetPolicyCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv(PolicyCacheTTLKey))
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// GetEvaluationConcurrency returns the maximal number of datasets of an application whose catalog lookups,
+// policy decisions and module selections are processed concurrently. One is returned if it is not configured, in which case
+// the datasets are processed sequentially.
+func GetEvaluationConcurrency() int {
+	concurrency, err := strconv.Atoi(os.Getenv(EvaluationConcurrencyKey))
+	if err != nil || concurrency < 1 {
+		return 1
+	}
+	return concurrency
+}
+
+// GetCompactEncoding returns the encoding of the connections stored in the plotters and in the status of the applications,
+// e.g. "protobuf" or "gzip". The connections are stored as plain JSON if it is not configured.
+func GetCompactEncoding() string {
+	return os.Getenv(CompactEncodingKey)
+}
+
+// AuditPrunedAssets returns true if the cataloged assets pruned from the status of the applications, once their datasets
+// are no longer requested, are exported as audit events
+func AuditPrunedAssets() bool {
+	return os.Getenv(AuditPrunedAssetsKey) == "true"
+}
+
+// GetWatchedNamespaces returns the namespaces whose applications are reconciled by the manager, given as a comma
+// separated list. nil is returned if all the namespaces are watched.
+func GetWatchedNamespaces() []string {
+	return getList(WatchedNamespacesKey)
+}
+
+// GetIgnoredNamespaces returns the namespaces whose applications are never reconciled by the manager,
+// given as a comma separated list
+func GetIgnoredNamespaces() []string {
+	return getList(IgnoredNamespacesKey)
+}
+
+// getList returns the trimmed, non-empty items of a comma separated list
+func getList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// GetDatasetIDNormalizer returns the normalizer of the dataset IDs of the applications, with the case-insensitive
+// catalogs given as a comma separated list. nil is returned if the normalization is not enabled.
+func GetDatasetIDNormalizer() *app.DatasetIDNormalizer {
+	if os.Getenv(NormalizeDatasetIDsKey) != "true" {
+		return nil
+	}
+	normalizer := &app.DatasetIDNormalizer{}
+	for _, catalog := range strings.Split(os.Getenv(CaseInsensitiveCatalogsKey), ",") {
+		if catalog = strings.TrimSpace(catalog); catalog != "" {
+			normalizer.CaseInsensitiveCatalogs = append(normalizer.CaseInsensitiveCatalogs, catalog)
+		}
+	}
+	return normalizer
+}
+
+// IsReadOnlyMode returns true if the manager must not perform mutating operations, e.g. during upgrades or incident response.
+// In read-only mode the controllers only update the status of the resources they own.
+func IsReadOnlyMode() bool {
+	return os.Getenv(ReadOnlyModeKey) == "true"
+}
+
+// GetGovernedCopyMaxSize returns the size in bytes up to which datasets that have to be transformed on read
+// are copied with the transformations applied, instead of being transformed by the read module on every access.
+// Zero is returned if the size is not configured, in which case such copies are not created.
+func GetGovernedCopyMaxSize() int64 {
+	size, err := strconv.ParseInt(os.Getenv(GovernedCopyMaxSizeKey), 10, 64)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
+// GetCatalogCheckInterval returns the interval at which the metadata of the datasets used by ready applications
+// is compared with the data catalog. Zero is returned if the interval is not configured, in which case
+// the metadata is only compared when an application is reconciled for other reasons, at most once per minute.
+func GetCatalogCheckInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv(CatalogCheckIntervalKey))
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return interval
+}
+
+// GetDriftCheckInterval returns the interval at which the resources generated for ready applications are compared
+// with their state in the cluster. Zero is returned if the interval is not configured, in which case the resources
+// are only compared when an application is reconciled for other reasons, e.g. when its plotter is modified.
+func GetDriftCheckInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv(DriftCheckIntervalKey))
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return interval
+}
+
+// GetExternalDNSDomain returns the domain under which read endpoints are exposed outside the cluster.
+// An empty string is returned if read endpoints are only exposed inside the cluster.
+func GetExternalDNSDomain() string {
+	return strings.TrimSuffix(strings.TrimSpace(os.Getenv(ExternalDNSDomainKey)), ".")
+}
+
+// GetCatalogTaxonomySource returns the location of the catalog values taxonomy layer (a file, an HTTPS URL or an OCI artifact),
+// and the checksum pinning its content. An empty location is returned if the taxonomy files installed with the manager are used.
+func GetCatalogTaxonomySource() (string, string) {
+	return os.Getenv(CatalogTaxonomySourceKey), os.Getenv(CatalogTaxonomyChecksumKey)
+}
+
+// GetModuleTaxonomySource returns the location of the module values taxonomy layer, defining the governance actions,
+// and the checksum pinning its content. An empty location is returned if the taxonomy files installed with the manager are used.
+func GetModuleTaxonomySource() (string, string) {
+	return os.Getenv(ModuleTaxonomySourceKey), os.Getenv(ModuleTaxonomyChecksumKey)
+}
+
+// GetPrivacyLevelsSource returns the location of the privacy levels taxonomy layer and the checksum pinning its content.
+// An empty location is returned if the taxonomy files installed with the manager are used.
+func GetPrivacyLevelsSource() (string, string) {
+	return os.Getenv(PrivacyLevelsSourceKey), os.Getenv(PrivacyLevelsChecksumKey)
+}
+
+// GetEventSink returns the kind of the sink to which the lifecycle events of applications are exported (webhook or kafka),
+// its URL and the kafka topic. An empty kind is returned if the events are not exported.
+func GetEventSink() (string, string, string) {
+	return os.Getenv(EventSinkTypeKey), os.Getenv(EventSinkURLKey), os.Getenv(EventSinkTopicKey)
+}
+
+// GetAuditSink returns the type of the sink of the audit records (stdout, file or webhook) and its target,
+// i.e. the path of the file or the URL of the webhook. An empty type is returned if the audit log is disabled.
+func GetAuditSink() (string, string) {
+	return strings.TrimSpace(os.Getenv(AuditSinkTypeKey)), strings.TrimSpace(os.Getenv(AuditSinkTargetKey))
+}
+
+// GetApplicationAPIAddress returns the address on which the application API is served to SDKs.
+// An empty string is returned if the API is disabled.
+func GetApplicationAPIAddress() string {
+	return strings.TrimSpace(os.Getenv(ApplicationAPIAddressKey))
+}
+
+// GetApplicationAPICertDir returns the directory holding the serving certificate of the application API
+func GetApplicationAPICertDir() string {
+	return strings.TrimSpace(os.Getenv(ApplicationAPICertDirKey))
+}
+
 // GetDataCatalogServiceAddress returns the address where data catalog is running
 func GetDataCatalogServiceAddress() string {
 	return os.Getenv(CatalogConnectorServiceAddressKey)
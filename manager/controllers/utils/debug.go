@@ -4,17 +4,36 @@
 package utils
 
 import (
+	"bytes"
+	"sync"
+
 	"github.com/go-logr/logr"
 	"gopkg.in/yaml.v2"
 )
 
+// bufferPool reuses the buffers for printing structures, which are printed on every reconcile
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
 // PrintStructure prints the structure in a textual format
 func PrintStructure(argStruct interface{}, log logr.Logger, argName string) {
 	log.Info(argName + ":")
-	yaml, err := yaml.Marshal(argStruct)
-	if err != nil {
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buffer.Reset()
+		bufferPool.Put(buffer)
+	}()
+	encoder := yaml.NewEncoder(buffer)
+	if err := encoder.Encode(argStruct); err != nil {
+		log.Info("\t Error printing " + argName + "\n")
+		return
+	}
+	if err := encoder.Close(); err != nil {
 		log.Info("\t Error printing " + argName + "\n")
 		return
 	}
-	log.Info("\t" + string(yaml))
+	log.Info("\t" + buffer.String())
 }
@@ -0,0 +1,17 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// RecordEvent records a Kubernetes event for the object if a recorder has been set
+func RecordEvent(recorder record.EventRecorder, object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(object, eventtype, reason, messageFmt, args...)
+}
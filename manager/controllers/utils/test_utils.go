@@ -6,11 +6,12 @@ import (
 	"github.com/onsi/gomega"
 	kbatch "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // Creates a scheme that can be used in unit tests
-// The scheme will have the core and batch apis from K8s registered as well as
+// The scheme will have the core, batch and networking apis from K8s registered as well as
 // the app and motion apis from M4D.
 // This function can be tested with a gomega environment if passed or otherwise (if nil is passed) it will ignore tests.
 func NewScheme(g *gomega.WithT) *runtime.Scheme {
@@ -23,6 +24,10 @@ func NewScheme(g *gomega.WithT) *runtime.Scheme {
 	if g != nil {
 		g.Expect(err).NotTo(gomega.HaveOccurred())
 	}
+	err = networkingv1.AddToScheme(s)
+	if g != nil {
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+	}
 	err = app.AddToScheme(s)
 	if g != nil {
 		g.Expect(err).NotTo(gomega.HaveOccurred())
@@ -11,9 +11,11 @@ import (
 	"fmt"
 	"runtime"
 	"sort"
+	"strings"
 
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	dc "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
 )
 
 // GetProtocol returns the existing data protocol
@@ -25,10 +27,28 @@ func GetProtocol(info *dc.DatasetDetails) (string, error) {
 		return app.Kafka, nil
 	case dc.DataStore_DB2:
 		return app.JdbcDb2, nil
+	case dc.DataStore_JDBC:
+		// the protocol is named after the database product, e.g. jdbc-postgresql,
+		// which is taken from the URL, e.g. jdbc:postgresql://host:5432/db, if the vendor is not set
+		if vendor := jdbcVendor(info.DataStore.GetJdbc()); vendor != "" {
+			return "jdbc-" + strings.ToLower(vendor), nil
+		}
 	}
 	return "", errors.New("unknown protocol")
 }
 
+// jdbcVendor returns the database product of a JDBC data store, either set explicitly or the subprotocol of its URL
+func jdbcVendor(store *dc.JdbcDataStore) string {
+	if vendor := store.GetVendor(); vendor != "" {
+		return vendor
+	}
+	parts := strings.SplitN(store.GetUrl(), ":", 3)
+	if len(parts) == 3 && strings.EqualFold(parts[0], "jdbc") {
+		return parts[1]
+	}
+	return ""
+}
+
 // IsTransformation returns true if the data transformation is required
 func IsTransformation(actionName string) bool {
 	return (actionName != "Allow") // TODO FIX THIS
@@ -44,6 +64,12 @@ func IsDenied(actionName string) bool {
 	return (actionName == "Deny") // TODO FIX THIS
 }
 
+// IsStorageRestriction returns true if the action restricts the storage accounts in which the data may be copied
+// to those having the tags given as arguments of the action
+func IsStorageRestriction(actionName string) bool {
+	return actionName == "RestrictStorage"
+}
+
 // StructToMap converts a struct to a map using JSON marshal
 func StructToMap(data interface{}) (map[string]interface{}, error) {
 	dataBytes, err := json.Marshal(data)
@@ -55,7 +81,16 @@ func StructToMap(data interface{}) (map[string]interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return mapData, nil
+	// data stored in a compact encoding is passed as plain JSON, including data that is itself an envelope
+	expanded, err := serde.Expand(mapData)
+	if err != nil {
+		return nil, err
+	}
+	expandedMap, ok := expanded.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the expanded data is a %T, not an object", expanded)
+	}
+	return expandedMap, nil
 }
 
 // Hash generates a name based on the unique identifier
@@ -93,6 +128,23 @@ func GetReleaseName(applicationName string, namespace string, step app.FlowStep)
 	return GetReleaseNameByStepName(applicationName, namespace, step.Name)
 }
 
+// GetReleaseNames maps the steps of a flow to their release names.
+// Long release names are shortened using a deterministic hash, thus an error is returned if two different steps
+// would be deployed by the same release.
+func GetReleaseNames(applicationName string, namespace string, steps []app.FlowStep) (map[string]string, error) {
+	names := make(map[string]string, len(steps))
+	owners := make(map[string]string, len(steps))
+	for _, step := range steps {
+		releaseName := GetReleaseName(applicationName, namespace, step)
+		if owner, found := owners[releaseName]; found && owner != step.Name {
+			return nil, fmt.Errorf("steps %s and %s are deployed by the same release %s", owner, step.Name, releaseName)
+		}
+		owners[releaseName] = step.Name
+		names[step.Name] = releaseName
+	}
+	return names, nil
+}
+
 // Generate release name from step name
 func GetReleaseNameByStepName(applicationName string, namespace string, stepName string) string {
 	fullName := applicationName + "-" + namespace + "-" + stepName
@@ -104,6 +156,11 @@ func GenerateModuleEndpointFQDN(releaseName string, blueprintNamespace string) s
 	return releaseName + "." + blueprintNamespace + ".svc.cluster.local"
 }
 
+// Generate the hostname under which a module is exposed outside the cluster
+func GenerateModuleExternalHostname(releaseName string, domain string) string {
+	return releaseName + "." + domain
+}
+
 // Some k8s objects only allow for a length of 63 characters.
 // This method shortens the name keeping a prefix and using the last 5 characters of the
 // new name for the hash of the postfix.
@@ -146,22 +203,17 @@ func ListeningAddress(port int) string {
 	return address
 }
 
-// SupportsInterface returns true iff the protocol/format list contains the given protocol/format interface
-func SupportsInterface(array []*app.InterfaceDetails, element *app.InterfaceDetails) bool {
-	for _, item := range array {
-		if item.DataFormat == element.DataFormat && item.Protocol == element.Protocol {
-			return true
+// CopyMap copies a map
+func CopyMap(m map[string]interface{}) map[string]interface{} {
+	cp := make(map[string]interface{})
+	for k, v := range m {
+		vm, ok := v.(map[string]interface{})
+		if ok {
+			cp[k] = CopyMap(vm)
+		} else {
+			cp[k] = v
 		}
 	}
-	return false
-}
 
-// SupportsFlow checks whether the given flow element can be found inside the array of flows
-func SupportsFlow(array []app.ModuleFlow, element app.ModuleFlow) bool {
-	for _, flow := range array {
-		if flow == element {
-			return true
-		}
-	}
-	return false
+	return cp
 }
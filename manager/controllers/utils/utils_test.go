@@ -0,0 +1,54 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	dc "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/onsi/gomega"
+)
+
+// TestGetProtocol checks that the protocol of a JDBC data store is named after its vendor, or after the subprotocol
+// of its URL if the vendor is not set
+func TestGetProtocol(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	jdbc := func(store *dc.JdbcDataStore) *dc.DatasetDetails {
+		return &dc.DatasetDetails{DataStore: &dc.DataStore{Type: dc.DataStore_JDBC, Jdbc: store}}
+	}
+	g.Expect(GetProtocol(jdbc(&dc.JdbcDataStore{Vendor: "PostgreSQL"}))).To(gomega.Equal("jdbc-postgresql"))
+	g.Expect(GetProtocol(jdbc(&dc.JdbcDataStore{Url: "jdbc:mysql://mysql.example.com:3306/sales"}))).To(gomega.Equal("jdbc-mysql"))
+	// the vendor takes precedence over the URL
+	g.Expect(GetProtocol(jdbc(&dc.JdbcDataStore{Vendor: "postgresql", Url: "jdbc:mysql://mysql:3306/sales"}))).To(gomega.Equal("jdbc-postgresql"))
+
+	_, err := GetProtocol(jdbc(&dc.JdbcDataStore{Url: "postgres.example.com:5432"}))
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = GetProtocol(jdbc(nil))
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	g.Expect(GetProtocol(&dc.DatasetDetails{DataStore: &dc.DataStore{Type: dc.DataStore_S3}})).To(gomega.Equal(app.S3))
+}
+
+// TestStructToMap checks that the data held in a compact encoding is expanded, whether it is nested in the structure
+// or is the structure itself
+func TestStructToMap(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	args := map[string]interface{}{"columns": []interface{}{"nameOrig"}}
+	nested, err := StructToMap(map[string]interface{}{"args": serde.NewCompactArbitrary(args, serde.GzipEncoding)})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(nested).To(gomega.Equal(map[string]interface{}{"args": args}))
+
+	envelope, err := StructToMap(serde.NewCompactArbitrary(args, serde.GzipEncoding))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(envelope).To(gomega.Equal(args))
+
+	_, err = StructToMap(serde.NewCompactArbitrary([]interface{}{"nameOrig"}, serde.GzipEncoding))
+	g.Expect(err).To(gomega.HaveOccurred())
+}
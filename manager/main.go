@@ -4,8 +4,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -13,27 +15,41 @@ import (
 	"emperror.dev/errors"
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/mesh-for-data/mesh-for-data/pkg/audit"
 	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
+	"github.com/mesh-for-data/mesh-for-data/pkg/diagnostics"
+	"github.com/mesh-for-data/mesh-for-data/pkg/evaluate"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
 	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster/argocd"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster/kubeconfig"
 	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster/local"
 	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster/razee"
 	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	"github.com/mesh-for-data/mesh-for-data/pkg/webhookcerts"
 
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/motion"
 
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	appv1 "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	motionv1 "github.com/mesh-for-data/mesh-for-data/manager/apis/motion/v1alpha1"
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app"
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
 	"github.com/mesh-for-data/mesh-for-data/pkg/helm"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
 	kapps "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	kbatch "k8s.io/api/batch/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 var (
@@ -47,25 +63,73 @@ func init() {
 	_ = corev1.AddToScheme(scheme)
 	_ = kbatch.AddToScheme(scheme)
 	_ = kapps.AddToScheme(scheme)
+	_ = admissionv1.AddToScheme(scheme)
+	_ = authenticationv1.AddToScheme(scheme)
+	_ = authorizationv1.AddToScheme(scheme)
+	_ = networkingv1.AddToScheme(scheme)
+}
+
+// diagnosticsOptions configures the profiling endpoints and the watchdog of the manager
+type diagnosticsOptions struct {
+	profilingAddr    string
+	watchdogInterval time.Duration
+	maxHeapMB        uint64
+	maxGoroutines    int
+}
+
+// throughputOptions tunes the controllers and the rate of their requests to the Kubernetes API server
+type throughputOptions struct {
+	controller app.ControllerOptions
+	qps        float64
+	burst      int
 }
 
 func run(namespace string, metricsAddr string, enableLeaderElection bool,
-	enableApplicationController, enableBlueprintController, enablePlotterController, enableMotionController bool) int {
+	enableApplicationController, enableBlueprintController, enablePlotterController, enableMotionController bool,
+	diagnosticsOpts diagnosticsOptions, throughputOpts throughputOptions) int {
 	setupLog.Info("creating manager")
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	options := ctrl.Options{
 		Scheme:             scheme,
 		Namespace:          namespace,
 		MetricsBindAddress: metricsAddr,
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "m4d-operator-leader-election",
 		Port:               9443,
-	})
+	}
+	// the cache holds the resources of the watched namespaces and of the namespaces of the control plane
+	if scope := app.NewNamespaceScope(utils.GetWatchedNamespaces(), utils.GetIgnoredNamespaces()); scope != nil && namespace == "" {
+		setupLog.Info("restricting the namespaces of the applications", "watched", scope.Watched, "ignored", scope.Ignored)
+		options.NewCache = scope.NewCache(utils.GetSystemNamespace(), utils.BlueprintNamespace, os.Getenv("ARGOCD_NAMESPACE"))
+	}
+	config := ctrl.GetConfigOrDie()
+	if throughputOpts.qps > 0 {
+		config.QPS = float32(throughputOpts.qps)
+	}
+	if throughputOpts.burst > 0 {
+		config.Burst = throughputOpts.burst
+	}
+	mgr, err := ctrl.NewManager(config, options)
 
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		return 1
 	}
 
+	if diagnosticsOpts.profilingAddr != "" {
+		if err := mgr.Add(diagnostics.NewProfilingServer(diagnosticsOpts.profilingAddr, ctrl.Log.WithName("profiling"))); err != nil {
+			setupLog.Error(err, "unable to add profiling server")
+			return 1
+		}
+	}
+	if diagnosticsOpts.watchdogInterval > 0 {
+		watchdog := diagnostics.NewWatchdog(diagnosticsOpts.watchdogInterval, diagnosticsOpts.maxHeapMB*1024*1024,
+			diagnosticsOpts.maxGoroutines, ctrl.Log.WithName("watchdog"))
+		if err := mgr.Add(watchdog); err != nil {
+			setupLog.Error(err, "unable to add watchdog")
+			return 1
+		}
+	}
+
 	// Initialize ClusterManager
 	setupLog.Info("creating cluster manager")
 	var clusterManager multicluster.ClusterManager
@@ -80,41 +144,183 @@ func run(namespace string, metricsAddr string, enableLeaderElection bool,
 	if enableApplicationController {
 		setupLog.Info("creating M4DApplication controller")
 
-		// Initialize PolicyManager interface
-		policyManager, err := newPolicyManager()
+		// Initialize DataCatalog interface
+		catalog, err := newDataCatalog()
 		if err != nil {
-			setupLog.Error(err, "unable to create policy manager facade", "controller", "M4DApplication")
+			setupLog.Error(err, "unable to create data catalog facade", "controller", "M4DApplication")
 			return 1
 		}
 		defer func() {
-			if err := policyManager.Close(); err != nil {
-				setupLog.Error(err, "unable to close policy manager facade", "controller", "M4DApplication")
+			if err := catalog.Close(); err != nil {
+				setupLog.Error(err, "unable to close data catalog facade", "controller", "M4DApplication")
 			}
 		}()
 
-		// Initialize DataCatalog interface
-		catalog, err := newDataCatalog()
+		// Initialize PolicyManager interface
+		policyManager, err := newPolicyManager(catalog)
 		if err != nil {
-			setupLog.Error(err, "unable to create data catalog facade", "controller", "M4DApplication")
+			setupLog.Error(err, "unable to create policy manager facade", "controller", "M4DApplication")
 			return 1
 		}
+		var policyCache *connectors.CachedPolicyManager
+		policyCacheTTL := utils.GetPolicyCacheTTL()
+		if policyCacheTTL > 0 {
+			setupLog.Info("caching policy manager decisions", "TTL", policyCacheTTL)
+			policyCache = connectors.NewCachedPolicyManager(policyManager, policyCacheTTL)
+			policyManager = policyCache
+		}
 		defer func() {
-			if err := catalog.Close(); err != nil {
-				setupLog.Error(err, "unable to close data catalog facade", "controller", "M4DApplication")
+			if err := policyManager.Close(); err != nil {
+				setupLog.Error(err, "unable to close policy manager facade", "controller", "M4DApplication")
 			}
 		}()
 
+		registerBucketProvisioner(mgr)
+
 		// Initiate the M4DApplication Controller
-		applicationController := app.NewM4DApplicationReconciler(mgr, "M4DApplication", policyManager, catalog, clusterManager, storage.NewProvisionImpl(mgr.GetClient()))
+		applicationController := app.NewM4DApplicationReconciler(mgr, "M4DApplication", policyManager, catalog, clusterManager, storage.NewRegistry(mgr.GetClient()))
+		moduleTaxonomySource, moduleTaxonomyChecksum := utils.GetModuleTaxonomySource()
+		if moduleTaxonomySource == "" {
+			moduleTaxonomySource = taxonomy.DefaultModuleValuesFile
+		}
+		if actions, err := taxonomy.LoadActionsFrom(context.Background(), moduleTaxonomySource, moduleTaxonomyChecksum); err != nil {
+			setupLog.Info("action taxonomy is not available, actions are not validated", "error", err.Error())
+		} else {
+			applicationController.ActionTaxonomy = actions
+		}
+		privacyLevelsSource, privacyLevelsChecksum := utils.GetPrivacyLevelsSource()
+		if privacyLevelsSource == "" {
+			privacyLevelsSource = taxonomy.DefaultPrivacyLevelsFile
+		}
+		if privacyLevels, err := taxonomy.LoadPrivacyLevelsFrom(context.Background(), privacyLevelsSource, privacyLevelsChecksum); err != nil {
+			setupLog.Info("privacy levels taxonomy is not available, privacy levels can not be requested", "error", err.Error())
+		} else {
+			applicationController.PrivacyLevels = privacyLevels
+		}
+		if sinkType, sinkURL, sinkTopic := utils.GetEventSink(); sinkType != "" {
+			sink, err := events.NewSink(sinkType, sinkURL, sinkTopic)
+			if err != nil {
+				setupLog.Error(err, "unable to create event sink", "controller", "M4DApplication")
+				return 1
+			}
+			exporter := events.NewExporter(sink, 1000, ctrl.Log.WithName("events"))
+			if err := mgr.Add(exporter); err != nil {
+				setupLog.Error(err, "unable to add event exporter", "controller", "M4DApplication")
+				return 1
+			}
+			setupLog.Info("exporting application events", "sink", sinkType, "url", sinkURL)
+			applicationController.Events = exporter
+		}
+		if sinkType, sinkTarget := utils.GetAuditSink(); sinkType != "" {
+			auditLogger, err := audit.NewLogger(sinkType, sinkTarget, ctrl.Log.WithName("audit"))
+			if err != nil {
+				setupLog.Error(err, "unable to create audit logger", "controller", "M4DApplication")
+				return 1
+			}
+			if runnable, ok := auditLogger.(manager.Runnable); ok {
+				if err := mgr.Add(runnable); err != nil {
+					setupLog.Error(err, "unable to add audit logger", "controller", "M4DApplication")
+					return 1
+				}
+			}
+			setupLog.Info("auditing policy decisions and data planes", "sink", sinkType, "target", sinkTarget)
+			applicationController.Audit = auditLogger
+		}
+		applicationController.Options = throughputOpts.controller
 		if err := applicationController.SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "M4DApplication")
 			return 1
 		}
+		if err := app.NewStorageAccountReconciler(mgr, "M4DStorageAccount").SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "M4DStorageAccount")
+			return 1
+		}
+		if policyCache != nil {
+			// the module selections of unchanged datasets are reused as long as the policy decisions are cached
+			applicationController.Selections = evaluate.NewSelectionCache(policyCacheTTL)
+			if err := mgr.AddMetricsExtraHandler(app.PolicyInvalidationPath, applicationController.PolicyInvalidationHandler(policyCache)); err != nil {
+				setupLog.Error(err, "unable to add policy invalidation endpoint")
+				return 1
+			}
+		}
+		inventory := app.NewInventoryHandler(mgr.GetClient(), clusterManager, ctrl.Log.WithName("inventory"))
+		if err := mgr.AddMetricsExtraHandler(app.InventoryPath, inventory); err != nil {
+			setupLog.Error(err, "unable to add inventory endpoint")
+			return 1
+		}
+		if apiAddress := utils.GetApplicationAPIAddress(); apiAddress != "" {
+			adminGroups := utils.GetPinningAdminGroups()
+			if adminGroups == nil {
+				adminGroups = appv1.DefaultPinningAdminGroups
+			}
+			impersonator := &app.KubeImpersonator{Config: mgr.GetConfig(), Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()}
+			applicationAPI := app.NewApplicationAPI(mgr.GetClient(), &app.KubeAccessReviewer{Client: mgr.GetClient()},
+				impersonator, adminGroups, ctrl.Log.WithName("application-api"))
+			server := &app.ApplicationAPIServer{Address: apiAddress, CertDir: utils.GetApplicationAPICertDir(),
+				Handler: applicationAPI, Log: ctrl.Log.WithName("application-api")}
+			if err := mgr.Add(server); err != nil {
+				setupLog.Error(err, "unable to add application API server")
+				return 1
+			}
+		}
 		if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 			if err := (&appv1.M4DApplication{}).SetupWebhookWithManager(mgr); err != nil {
 				setupLog.Error(err, "unable to create webhook", "webhook", "M4DApplication")
 				return 1
 			}
+			appv1.EnableProfiles(mgr.GetAPIReader())
+			if normalizer := utils.GetDatasetIDNormalizer(); normalizer != nil {
+				setupLog.Info("enabling normalization of dataset IDs", "webhook", "M4DApplication",
+					"caseInsensitiveCatalogs", normalizer.CaseInsensitiveCatalogs)
+				appv1.EnableDatasetIDNormalization(normalizer)
+			}
+			adminGroups := utils.GetPinningAdminGroups()
+			if adminGroups == nil {
+				adminGroups = appv1.DefaultPinningAdminGroups
+			}
+			mgr.GetWebhookServer().Register(appv1.PinnedModulesWebhookPath,
+				&webhook.Admission{Handler: &appv1.PinnedModulesValidator{AdminGroups: adminGroups}})
+			if err := (&appv1.M4DStorageAccount{}).SetupWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "M4DStorageAccount")
+				return 1
+			}
+			// the CA is injected by the manager unless the certificate is managed by cert-manager
+			systemNamespace := utils.GetSystemNamespace()
+			caInjector := webhookcerts.NewCAInjector(mgr.GetAPIReader(), mgr.GetClient(), webhookcerts.DefaultCAFile,
+				[]string{systemNamespace + "-mutating-webhook"}, []string{systemNamespace + "-validating-webhook"},
+				time.Minute, ctrl.Log.WithName("ca-injector"))
+			if err := mgr.Add(caInjector); err != nil {
+				setupLog.Error(err, "unable to add webhook CA injector")
+				return 1
+			}
+			// the geographies of the taxonomy files of the chart are examples, which do not include the regions of the
+			// clusters, the geographies are thus only enforced if the administrators set the catalog taxonomy
+			taxonomySource, taxonomyChecksum := utils.GetCatalogTaxonomySource()
+			if taxonomySource == "" {
+				setupLog.Info("catalog taxonomy is not set, storage account regions are only checked for well-formedness")
+			} else if geographies, err := taxonomy.LoadGeographiesFrom(context.Background(), taxonomySource, taxonomyChecksum); err != nil {
+				setupLog.Info("geography taxonomy is not available, storage account regions are only checked for well-formedness", "error", err.Error())
+			} else {
+				appv1.SetGeographies(geographies)
+			}
+			if validator, err := taxonomy.CachedValidator(taxonomy.DefaultStorageValuesFile, "storage_tags"); err != nil {
+				setupLog.Info("storage taxonomy is not available, the tags of storage accounts are not validated", "error", err.Error())
+			} else {
+				appv1.EnableTagsValidation(validator)
+			}
+			if os.Getenv("ENABLE_INTERFACE_VALIDATION") == "true" {
+				setupLog.Info("enabling validation of requested interfaces against installed modules", "webhook", "M4DApplication")
+				appv1.EnableInterfaceValidation(mgr.GetAPIReader(), utils.GetSystemNamespace())
+			}
+			if os.Getenv("ENABLE_APPINFO_VALIDATION") == "true" {
+				validator, err := taxonomy.CachedValidator(taxonomy.DefaultApplicationValuesFile, "app_info")
+				if err != nil {
+					setupLog.Error(err, "unable to compile the application taxonomy", "webhook", "M4DApplication")
+					return 1
+				}
+				setupLog.Info("enabling validation of application details against the taxonomy", "webhook", "M4DApplication")
+				appv1.EnableAppInfoValidation(validator)
+			}
 		}
 	}
 
@@ -122,6 +328,7 @@ func run(namespace string, metricsAddr string, enableLeaderElection bool,
 		// Initiate the Plotter Controller
 		setupLog.Info("creating Plotter controller")
 		plotterController := app.NewPlotterReconciler(mgr, "Plotter", clusterManager)
+		plotterController.Options = throughputOpts.controller
 		if err := plotterController.SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", plotterController.Name)
 			return 1
@@ -132,6 +339,7 @@ func run(namespace string, metricsAddr string, enableLeaderElection bool,
 		// Initiate the Blueprint Controller
 		setupLog.Info("creating Blueprint controller")
 		blueprintController := app.NewBlueprintReconciler(mgr, "Blueprint", new(helm.Impl))
+		blueprintController.Options = throughputOpts.controller
 		if err := blueprintController.SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", blueprintController.Name)
 			return 1
@@ -165,6 +373,8 @@ func main() {
 	var enablePlotterController bool
 	var enableMotionController bool
 	var enableAllControllers bool
+	var diagnosticsOpts diagnosticsOptions
+	var throughputOpts throughputOptions
 	address := utils.ListeningAddress(8085)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-addr", address, "The address the metric endpoint binds to.")
@@ -181,6 +391,24 @@ func main() {
 	flag.BoolVar(&enableAllControllers, "enable-all-controllers", false,
 		"Enables all controllers.")
 	flag.StringVar(&namespace, "namespace", "", "The namespace to which this controller manager is limited.")
+	flag.StringVar(&diagnosticsOpts.profilingAddr, "pprof-bind-addr", "",
+		"The address the pprof endpoints bind to. Profiling is disabled if empty.")
+	flag.DurationVar(&diagnosticsOpts.watchdogInterval, "watchdog-interval", time.Minute,
+		"The interval in which memory and goroutine usage is sampled. The watchdog is disabled if 0.")
+	flag.Uint64Var(&diagnosticsOpts.maxHeapMB, "watchdog-max-heap-mb", 0,
+		"Heap usage in MB above which the watchdog logs a warning. No limit if 0.")
+	flag.IntVar(&diagnosticsOpts.maxGoroutines, "watchdog-max-goroutines", 0,
+		"Number of goroutines above which the watchdog logs a warning. No limit if 0.")
+	flag.IntVar(&throughputOpts.controller.MaxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"Maximum number of resources reconciled concurrently by each of the application, plotter and blueprint controllers.")
+	flag.DurationVar(&throughputOpts.controller.BaseDelay, "rate-limiter-base-delay", 0,
+		"Delay before requeuing a resource whose reconcile failed, doubled on each failure. The controller-runtime default if 0.")
+	flag.DurationVar(&throughputOpts.controller.MaxDelay, "rate-limiter-max-delay", 0,
+		"Maximum delay before requeuing a resource whose reconcile failed. The controller-runtime default if 0.")
+	flag.Float64Var(&throughputOpts.qps, "kube-api-qps", 0,
+		"Maximum queries per second of the manager to the Kubernetes API server. The client-go default if 0.")
+	flag.IntVar(&throughputOpts.burst, "kube-api-burst", 0,
+		"Maximum burst of queries of the manager to the Kubernetes API server. The client-go default if 0.")
 	flag.Parse()
 
 	if enableAllControllers {
@@ -198,17 +426,23 @@ func main() {
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 
 	os.Exit(run(namespace, metricsAddr, enableLeaderElection,
-		enableApplicationController, enableBlueprintController, enablePlotterController, enableMotionController))
+		enableApplicationController, enableBlueprintController, enablePlotterController, enableMotionController, diagnosticsOpts, throughputOpts))
 }
 
+// newDataCatalog creates the data catalog facade. The catalog is accessed through its REST API
+// if CATALOG_SERVER_URL is set, and otherwise through the gRPC catalog connector.
 func newDataCatalog() (connectors.DataCatalog, error) {
 	connectionTimeout, err := getConnectionTimeout()
 	if err != nil {
 		return nil, err
 	}
 	providerName := os.Getenv("CATALOG_PROVIDER_NAME")
+	if serverURL := os.Getenv("CATALOG_SERVER_URL"); serverURL != "" {
+		setupLog.Info("setting REST data catalog client", "Name", providerName, "URL", serverURL, "Timeout", connectionTimeout)
+		return connectors.NewHTTPDataCatalog(providerName, serverURL, os.Getenv("CATALOG_AUTH_HEADER"), connectionTimeout)
+	}
 	connectorURL := os.Getenv("CATALOG_CONNECTOR_URL")
-	connector, err := connectors.NewGrpcDataCatalog(providerName, connectorURL, connectionTimeout)
+	connector, err := connectors.NewGrpcDataCatalog(providerName, connectorURL, connectionTimeout, getConnectorSecurity("CATALOG_CONNECTOR"))
 	setupLog.Info("setting data catalog client", "Name", providerName, "URL", connectorURL, "Timeout", connectionTimeout)
 	if err != nil {
 		return nil, err
@@ -216,16 +450,26 @@ func newDataCatalog() (connectors.DataCatalog, error) {
 	return connector, nil
 }
 
-func newPolicyManager() (connectors.PolicyManager, error) {
+// newPolicyManager creates the policy manager facade. Policies are evaluated directly by the OPA server
+// if OPA_SERVER_URL is set, and otherwise by the connector of the main policy manager.
+func newPolicyManager(catalog connectors.DataCatalog) (connectors.PolicyManager, error) {
 	connectionTimeout, err := getConnectionTimeout()
 	if err != nil {
 		return nil, err
 	}
 
 	mainPolicyManagerName := os.Getenv("MAIN_POLICY_MANAGER_NAME")
-	mainPolicyManagerURL := os.Getenv("MAIN_POLICY_MANAGER_CONNECTOR_URL")
-	setupLog.Info("setting main policy manager client", "Name", mainPolicyManagerName, "URL", mainPolicyManagerURL, "Timeout", connectionTimeout)
-	policyManager, err := connectors.NewGrpcPolicyManager(mainPolicyManagerName, mainPolicyManagerURL, connectionTimeout)
+	var policyManager connectors.PolicyManager
+	if opaServerURL := os.Getenv("OPA_SERVER_URL"); opaServerURL != "" {
+		opaPolicyPath := os.Getenv("OPA_POLICY_PATH")
+		setupLog.Info("setting main policy manager to OPA", "Name", mainPolicyManagerName, "URL", opaServerURL, "Policy", opaPolicyPath, "Timeout", connectionTimeout)
+		policyManager, err = connectors.NewOpaPolicyManager(mainPolicyManagerName, opaServerURL, opaPolicyPath, catalog, connectionTimeout)
+	} else {
+		mainPolicyManagerURL := os.Getenv("MAIN_POLICY_MANAGER_CONNECTOR_URL")
+		setupLog.Info("setting main policy manager client", "Name", mainPolicyManagerName, "URL", mainPolicyManagerURL, "Timeout", connectionTimeout)
+		policyManager, err = connectors.NewGrpcPolicyManager(mainPolicyManagerName, mainPolicyManagerURL, connectionTimeout,
+			getConnectorSecurity("MAIN_POLICY_MANAGER_CONNECTOR"))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +479,8 @@ func newPolicyManager() (connectors.PolicyManager, error) {
 		extensionPolicyManagerName := os.Getenv("EXTENSIONS_POLICY_MANAGER_NAME")
 		extensionPolicyManagerURL := os.Getenv("EXTENSIONS_POLICY_MANAGER_CONNECTOR_URL")
 		setupLog.Info("setting extension policy manager client", "Name", extensionPolicyManagerName, "URL", extensionPolicyManagerURL, "Timeout", connectionTimeout)
-		extensionPolicyManager, err := connectors.NewGrpcPolicyManager(extensionPolicyManagerName, extensionPolicyManagerURL, connectionTimeout)
+		extensionPolicyManager, err := connectors.NewGrpcPolicyManager(extensionPolicyManagerName, extensionPolicyManagerURL, connectionTimeout,
+			getConnectorSecurity("EXTENSIONS_POLICY_MANAGER_CONNECTOR"))
 		if err != nil {
 			return nil, err
 		}
@@ -263,12 +508,52 @@ func newClusterManager(mgr manager.Manager) (multicluster.ClusterManager, error)
 
 		razeeURL := strings.TrimSpace(os.Getenv("RAZEE_URL"))
 		return razee.NewRazeeOAuthManager(strings.TrimSpace(razeeURL), strings.TrimSpace(apiKey), multiClusterGroup)
+	} else if _, kubeconfigSecrets := os.LookupEnv("MULTICLUSTER_KUBECONFIG"); kubeconfigSecrets {
+		setupLog.Info("Using the kubeconfig secrets of the remote clusters")
+		return kubeconfig.NewManager(mgr.GetClient(), utils.GetSystemNamespace())
+	} else if _, argoCD := os.LookupEnv("MULTICLUSTER_ARGOCD"); argoCD {
+		setupLog.Info("Using git repositories synced by ArgoCD")
+		return argocd.NewManager(mgr.GetClient(), utils.GetSystemNamespace(), os.Getenv("ARGOCD_NAMESPACE"),
+			filepath.Join(os.TempDir(), "m4d-gitops"))
 	} else {
 		setupLog.Info("Using local cluster manager")
 		return local.NewManager(mgr.GetClient(), utils.GetSystemNamespace())
 	}
 }
 
+// registerBucketProvisioner provisions the S3 buckets through the S3 API of the storage accounts if configured,
+// or if the Dataset CRD of Datashim is not installed and no provisioner is configured
+func registerBucketProvisioner(mgr manager.Manager) {
+	switch utils.GetBucketProvisioner() {
+	case utils.DatasetProvisioner:
+		return
+	case utils.S3APIProvisioner:
+	default:
+		gvk := storage.GroupVersion.WithKind(storage.DatasetKind)
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			return
+		}
+	}
+	setupLog.Info("Provisioning the S3 buckets through the S3 API")
+	storage.Register(storage.S3, func(c client.Client) storage.ProvisionInterface { return storage.NewS3APIProvisioner(c) })
+}
+
+// getConnectorSecurity returns the security of the connection to a gRPC connector, configured by the environment
+// variables starting with the prefix, e.g. CATALOG_CONNECTOR_TLS_CA_FILE. The connection is not secured if none is set.
+func getConnectorSecurity(prefix string) *connectors.GrpcSecurity {
+	security := &connectors.GrpcSecurity{
+		CAFile:     os.Getenv(prefix + "_TLS_CA_FILE"),
+		CertFile:   os.Getenv(prefix + "_TLS_CERT_FILE"),
+		KeyFile:    os.Getenv(prefix + "_TLS_KEY_FILE"),
+		ServerName: os.Getenv(prefix + "_TLS_SERVER_NAME"),
+		TokenFile:  os.Getenv(prefix + "_TOKEN_FILE"),
+	}
+	if *security == (connectors.GrpcSecurity{}) {
+		return nil
+	}
+	return security
+}
+
 func getConnectionTimeout() (time.Duration, error) {
 	connectionTimeout := os.Getenv("CONNECTION_TIMEOUT")
 	timeOutInSeconds, err := strconv.Atoi(connectionTimeout)
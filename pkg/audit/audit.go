@@ -0,0 +1,85 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package audit records the policy decisions and the construction of the data plane of the applications as structured
+// audit records, written as JSON lines for ingestion by security information and event management (SIEM) systems.
+// See site/docs/reference/audit.md for the schema of the records.
+package audit
+
+import (
+	"time"
+)
+
+// SchemaVersion identifies the schema of the audit records. Fields are only added within a version.
+const SchemaVersion = "m4d.audit/v1"
+
+// Outcomes of the reconciles of the applications
+const (
+	// Granted indicates that the data plane of the application has been constructed, or is accessed in place
+	Granted string = "Granted"
+	// Denied indicates that the governance policies deny some of the operations requested by the application
+	Denied string = "Denied"
+	// Failed indicates that the data plane could not be constructed, e.g. since no module supports the required actions
+	Failed string = "Failed"
+)
+
+// Record is the audit record of a reconcile of an application in which its governance policies are evaluated
+type Record struct {
+	Schema      string      `json:"schema"`
+	Time        time.Time   `json:"time"`
+	Application Application `json:"application"`
+	Requester   Requester   `json:"requester"`
+	// Decisions are the actions required by the governance policies and the denied operations, by dataset
+	Decisions []Decision `json:"decisions"`
+	// Modules are the module instances constructing the data plane, in the clusters in which they are deployed
+	Modules []Module `json:"modules"`
+	// Generated references the resource generated for the data plane, e.g. a Plotter
+	Generated string `json:"generated,omitempty"`
+	// Outcome is one of Granted, Denied or Failed
+	Outcome string `json:"outcome"`
+	// Message describes the errors and denials, if any
+	Message string `json:"message,omitempty"`
+}
+
+// Application identifies the application
+type Application struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	UID        string `json:"uid"`
+	Generation int64  `json:"generation"`
+}
+
+// Requester describes who requests the data: the workload selected by the application and the declared purpose
+type Requester struct {
+	Cluster          string            `json:"cluster,omitempty"`
+	WorkloadSelector map[string]string `json:"workloadSelector,omitempty"`
+	AppInfo          map[string]string `json:"appInfo,omitempty"`
+}
+
+// Decision is an action required by the governance policies on a dataset, or a denied operation
+type Decision struct {
+	DatasetID   string            `json:"datasetID"`
+	Flow        string            `json:"flow"`
+	Action      string            `json:"action"`
+	Level       string            `json:"level,omitempty"`
+	Args        map[string]string `json:"args,omitempty"`
+	Destination string            `json:"destination,omitempty"`
+	// Denied is set if the operation is denied, in which case the action is Deny
+	Denied bool `json:"denied,omitempty"`
+	// Module is the module enforcing the action
+	Module string `json:"module,omitempty"`
+}
+
+// Module is an instance of a module in the data plane
+type Module struct {
+	Name      string `json:"name"`
+	Cluster   string `json:"cluster"`
+	Flow      string `json:"flow"`
+	Step      string `json:"step"`
+	DatasetID string `json:"datasetID,omitempty"`
+}
+
+// Logger records audit records
+type Logger interface {
+	Log(record Record)
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestJSONLines(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewJSONLinesLogger(buffer, ctrl.Log.WithName("audit"))
+	logger.Log(Record{Application: Application{Name: "notebook", Namespace: "default"}, Outcome: Granted})
+	logger.Log(Record{Application: Application{Name: "other", Namespace: "default"}, Outcome: Denied,
+		Decisions: []Decision{{DatasetID: "s3/deny-dataset", Flow: "write", Action: "Deny", Denied: true}}})
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	record := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, SchemaVersion, record["schema"])
+	assert.NotEmpty(t, record["time"])
+	assert.Equal(t, Denied, record["outcome"])
+	decision := record["decisions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, true, decision["denied"])
+
+	_, err := NewLogger(FileSinkType, "", ctrl.Log)
+	assert.NotNil(t, err)
+	_, err = NewLogger(FileSinkType, filepath.Join(t.TempDir(), "audit.jsonl"), ctrl.Log)
+	assert.Nil(t, err)
+	_, err = NewLogger("syslog", "", ctrl.Log)
+	assert.NotNil(t, err)
+}
+
+func TestWebhook(t *testing.T) {
+	received := make(chan Record, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		record := Record{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&record))
+		received <- record
+	}))
+	defer server.Close()
+
+	logger, err := NewLogger(WebhookSinkType, server.URL, ctrl.Log.WithName("audit"))
+	assert.Nil(t, err)
+	webhook := logger.(*WebhookLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = webhook.Start(ctx) }()
+
+	logger.Log(Record{Application: Application{Name: "notebook", Namespace: "default"},
+		Modules: []Module{{Name: "arrow-flight-module", Cluster: "thegreendragon", Flow: "read"}}, Outcome: Granted})
+	record := <-received
+	assert.Equal(t, SchemaVersion, record.Schema)
+	assert.Equal(t, "thegreendragon", record.Modules[0].Cluster)
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+)
+
+// Kinds of sinks of the audit records
+const (
+	StdoutSinkType  = "stdout"
+	FileSinkType    = "file"
+	WebhookSinkType = "webhook"
+)
+
+// NewLogger creates a logger writing to the given kind of sink: stdout, a file at the target path,
+// or a webhook at the target URL to which each record is posted
+func NewLogger(kind string, target string, log logr.Logger) (Logger, error) {
+	switch kind {
+	case StdoutSinkType:
+		return NewJSONLinesLogger(os.Stdout, log), nil
+	case FileSinkType:
+		if target == "" {
+			return nil, errors.New("a path is required for writing the audit records to a file")
+		}
+		file, err := os.OpenFile(target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		return NewJSONLinesLogger(file, log), nil
+	case WebhookSinkType:
+		if target == "" {
+			return nil, errors.New("a URL is required for posting the audit records to a webhook")
+		}
+		return NewWebhookLogger(target, 1000, log), nil
+	default:
+		return nil, errors.New("unsupported audit sink " + kind + ": should be one of " + StdoutSinkType + ", " +
+			FileSinkType + ", " + WebhookSinkType)
+	}
+}
+
+// JSONLinesLogger writes each record as a line of JSON
+type JSONLinesLogger struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	log    logr.Logger
+}
+
+// NewJSONLinesLogger creates a logger writing to the writer
+func NewJSONLinesLogger(writer io.Writer, log logr.Logger) *JSONLinesLogger {
+	return &JSONLinesLogger{writer: writer, log: log}
+}
+
+// Log implements Logger
+func (l *JSONLinesLogger) Log(record Record) {
+	line, err := marshal(record)
+	if err != nil {
+		l.log.Info("could not marshal audit record", "error", err.Error())
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, err := l.writer.Write(append(line, '\n')); err != nil {
+		l.log.Info("could not write audit record", "error", err.Error())
+	}
+}
+
+// WebhookLogger posts the records to a URL in the background, so that reconciles never wait for the webhook.
+// Records logged while the buffer is full are dropped.
+type WebhookLogger struct {
+	URL     string
+	Client  *http.Client
+	records chan Record
+	log     logr.Logger
+}
+
+// NewWebhookLogger creates a logger buffering up to bufferSize records
+func NewWebhookLogger(url string, bufferSize int, log logr.Logger) *WebhookLogger {
+	return &WebhookLogger{
+		URL:     url,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		records: make(chan Record, bufferSize),
+		log:     log,
+	}
+}
+
+// Log implements Logger
+func (l *WebhookLogger) Log(record Record) {
+	select {
+	case l.records <- record:
+	default:
+		l.log.Info("audit buffer is full, dropping audit record", "application", record.Application)
+	}
+}
+
+// Start posts the logged records until the context is done.
+// Start implements manager.Runnable so that the logger is run by the controller manager.
+func (l *WebhookLogger) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case record := <-l.records:
+			if err := l.post(ctx, record); err != nil {
+				l.log.Info("could not post audit record", "error", err.Error())
+			}
+		}
+	}
+}
+
+func (l *WebhookLogger) post(ctx context.Context, record Record) error {
+	body, err := marshal(record)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("audit webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// marshal encodes a record, setting its schema and time if missing
+func marshal(record Record) ([]byte, error) {
+	if record.Schema == "" {
+		record.Schema = SchemaVersion
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+	return json.Marshal(record)
+}
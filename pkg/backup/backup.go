@@ -0,0 +1,235 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Redacted replaces the values of the exported secrets
+const Redacted = "REDACTED"
+
+// Bundle contains the control plane resources exported from a cluster.
+// Secrets referenced by the resources are included with their values redacted, so that the bundle can be stored safely.
+type Bundle struct {
+	// SystemNamespace is the namespace of the control plane, holding the secrets of the promoted copies
+	SystemNamespace string                      `json:"systemNamespace,omitempty"`
+	Profiles        []app.M4DApplicationProfile `json:"profiles,omitempty"`
+	Modules         []app.M4DModule             `json:"modules,omitempty"`
+	StorageAccounts []app.M4DStorageAccount     `json:"storageAccounts,omitempty"`
+	Revocations     []app.M4DDatasetRevocation  `json:"revocations,omitempty"`
+	Applications    []app.M4DApplication        `json:"applications,omitempty"`
+	Grants          []app.M4DGrant              `json:"grants,omitempty"`
+	Promotions      []app.M4DCopyPromotion      `json:"promotions,omitempty"`
+	Plotters        []app.Plotter               `json:"plotters,omitempty"`
+	Secrets         []corev1.Secret             `json:"secrets,omitempty"`
+}
+
+// Export reads the control plane resources of all namespaces, together with the secrets they reference.
+// The secrets of the promoted copies are read in the namespace of the control plane.
+func Export(ctx context.Context, cl client.Reader, systemNamespace string) (*Bundle, error) {
+	bundle := &Bundle{SystemNamespace: systemNamespace}
+	var profiles app.M4DApplicationProfileList
+	if err := cl.List(ctx, &profiles); err != nil {
+		return nil, errors.WithMessage(err, "failed listing application profiles")
+	}
+	bundle.Profiles = profiles.Items
+	var modules app.M4DModuleList
+	if err := cl.List(ctx, &modules); err != nil {
+		return nil, errors.WithMessage(err, "failed listing modules")
+	}
+	bundle.Modules = modules.Items
+	var accounts app.M4DStorageAccountList
+	if err := cl.List(ctx, &accounts); err != nil {
+		return nil, errors.WithMessage(err, "failed listing storage accounts")
+	}
+	bundle.StorageAccounts = accounts.Items
+	var revocations app.M4DDatasetRevocationList
+	if err := cl.List(ctx, &revocations); err != nil {
+		return nil, errors.WithMessage(err, "failed listing dataset revocations")
+	}
+	bundle.Revocations = revocations.Items
+	var applications app.M4DApplicationList
+	if err := cl.List(ctx, &applications); err != nil {
+		return nil, errors.WithMessage(err, "failed listing applications")
+	}
+	bundle.Applications = applications.Items
+	var grants app.M4DGrantList
+	if err := cl.List(ctx, &grants); err != nil {
+		return nil, errors.WithMessage(err, "failed listing grants")
+	}
+	bundle.Grants = grants.Items
+	var promotions app.M4DCopyPromotionList
+	if err := cl.List(ctx, &promotions); err != nil {
+		return nil, errors.WithMessage(err, "failed listing copy promotions")
+	}
+	bundle.Promotions = promotions.Items
+	var plotters app.PlotterList
+	if err := cl.List(ctx, &plotters); err != nil {
+		return nil, errors.WithMessage(err, "failed listing plotters")
+	}
+	bundle.Plotters = plotters.Items
+
+	for _, ref := range secretReferences(bundle) {
+		secret := corev1.Secret{}
+		if err := cl.Get(ctx, ref, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.WithMessage(err, "failed reading secret "+ref.String())
+		}
+		bundle.Secrets = append(bundle.Secrets, redactSecret(&secret))
+	}
+	return bundle, nil
+}
+
+// ImportOptions configure the fix-ups applied to the resources when they are imported
+type ImportOptions struct {
+	// Namespaces maps the namespaces of the exported cluster to the namespaces of the target cluster,
+	// e.g. when the control plane is installed in a different namespace. Unmapped namespaces are kept.
+	Namespaces map[string]string
+	// SkipPlotters leaves the plotters to be generated again by the manager from the imported applications
+	SkipPlotters bool
+}
+
+// Import creates the resources of the bundle in the target cluster. Existing resources are left unchanged.
+// The status of the copy promotions is imported as well, since it locates the storage of the promoted copies.
+// Redacted secrets are not created: the returned list contains the referenced secrets that do not exist in the target cluster
+// and have to be created before the imported resources become usable.
+func Import(ctx context.Context, cl client.Client, bundle *Bundle, options ImportOptions) ([]client.ObjectKey, error) {
+	var objects []client.Object
+	for i := range bundle.Profiles {
+		objects = append(objects, &bundle.Profiles[i])
+	}
+	for i := range bundle.Modules {
+		objects = append(objects, &bundle.Modules[i])
+	}
+	for i := range bundle.StorageAccounts {
+		objects = append(objects, &bundle.StorageAccounts[i])
+	}
+	for i := range bundle.Revocations {
+		objects = append(objects, &bundle.Revocations[i])
+	}
+	for i := range bundle.Applications {
+		bundle.Applications[i].Status = app.M4DApplicationStatus{}
+		objects = append(objects, &bundle.Applications[i])
+	}
+	for i := range bundle.Grants {
+		// the grantee is a namespace as well
+		if namespace, found := options.Namespaces[bundle.Grants[i].Spec.Grantee]; found {
+			bundle.Grants[i].Spec.Grantee = namespace
+		}
+		objects = append(objects, &bundle.Grants[i])
+	}
+	if !options.SkipPlotters {
+		for i := range bundle.Plotters {
+			bundle.Plotters[i].Status = app.PlotterStatus{}
+			objects = append(objects, &bundle.Plotters[i])
+		}
+	}
+	for _, obj := range objects {
+		fixupObject(obj, options.Namespaces)
+		if err := cl.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, errors.WithMessage(err, "failed creating "+obj.GetNamespace()+"/"+obj.GetName())
+		}
+	}
+	for i := range bundle.Promotions {
+		if err := importPromotion(ctx, cl, &bundle.Promotions[i], options.Namespaces); err != nil {
+			return nil, err
+		}
+	}
+	if namespace, found := options.Namespaces[bundle.SystemNamespace]; found {
+		bundle.SystemNamespace = namespace
+	}
+
+	var missing []client.ObjectKey
+	for _, ref := range secretReferences(bundle) {
+		secret := corev1.Secret{}
+		if err := cl.Get(ctx, ref, &secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, errors.WithMessage(err, "failed reading secret "+ref.String())
+			}
+			missing = append(missing, ref)
+		}
+	}
+	return missing, nil
+}
+
+// importPromotion creates a copy promotion and restores its status, which is not set on creation
+func importPromotion(ctx context.Context, cl client.Client, promotion *app.M4DCopyPromotion, namespaces map[string]string) error {
+	status := promotion.Status
+	fixupObject(promotion, namespaces)
+	if err := cl.Create(ctx, promotion); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return errors.WithMessage(err, "failed creating "+promotion.Namespace+"/"+promotion.Name)
+	}
+	promotion.Status = status
+	return errors.WithMessage(cl.Status().Update(ctx, promotion), "failed restoring the status of "+promotion.Namespace+"/"+promotion.Name)
+}
+
+// secretReferences returns the secrets referenced by the storage accounts, the applications and the copy promotions of the bundle
+func secretReferences(bundle *Bundle) []client.ObjectKey {
+	var refs []client.ObjectKey
+	for _, account := range bundle.StorageAccounts {
+		refs = append(refs, client.ObjectKey{Namespace: account.Namespace, Name: account.Spec.SecretRef})
+	}
+	for _, application := range bundle.Applications {
+		if application.Spec.SecretRef != "" {
+			refs = append(refs, client.ObjectKey{Namespace: application.Namespace, Name: application.Spec.SecretRef})
+		}
+	}
+	for _, promotion := range bundle.Promotions {
+		if promotion.Status.SecretRef != "" && bundle.SystemNamespace != "" {
+			refs = append(refs, client.ObjectKey{Namespace: bundle.SystemNamespace, Name: promotion.Status.SecretRef})
+		}
+	}
+	return refs
+}
+
+// redactSecret returns a copy of the secret in which the values are replaced, keeping the keys to document the expected content
+func redactSecret(secret *corev1.Secret) corev1.Secret {
+	redacted := corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: secret.Namespace, Labels: secret.Labels},
+		Type:       secret.Type,
+		StringData: map[string]string{},
+	}
+	for key := range secret.Data {
+		redacted.StringData[key] = Redacted
+	}
+	for key := range secret.StringData {
+		redacted.StringData[key] = Redacted
+	}
+	return redacted
+}
+
+// fixupObject removes the metadata assigned by the exported cluster and maps the namespace of the object
+// and the application namespace label of generated resources
+func fixupObject(obj client.Object, namespaces map[string]string) {
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetDeletionTimestamp(nil)
+	obj.SetManagedFields(nil)
+	obj.SetOwnerReferences(nil)
+	obj.SetFinalizers(nil)
+	if namespace, found := namespaces[obj.GetNamespace()]; found {
+		obj.SetNamespace(namespace)
+	}
+	labels := obj.GetLabels()
+	if namespace, found := namespaces[labels[app.ApplicationNamespaceLabel]]; found {
+		labels[app.ApplicationNamespaceLabel] = namespace
+		obj.SetLabels(labels)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newScheme(g *gomega.WithT) *runtime.Scheme {
+	s := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(s)).To(gomega.Succeed())
+	g.Expect(app.AddToScheme(s)).To(gomega.Succeed())
+	return s
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	account := &app.M4DStorageAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "account", Namespace: "m4d-system", ResourceVersion: "7"},
+		Spec:       app.M4DStorageAccountSpec{SecretRef: "credentials", Endpoint: "http://endpoint", Regions: []string{"theshire"}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "m4d-system"},
+		Data:       map[string][]byte{"accessKey": []byte("key"), "secretKey": []byte("secret")},
+	}
+	application := &app.M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default", Finalizers: []string{"finalizer"}},
+		Spec:       app.M4DApplicationSpec{AppInfo: app.ApplicationDetails{"intent": "Testing"}, SecretRef: "user-credentials"},
+		Status:     app.M4DApplicationStatus{Ready: true},
+	}
+	grant := &app.M4DGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "share", Namespace: "default"},
+		Spec:       app.M4DGrantSpec{Application: "notebook", DataSetID: "s3/allow-dataset", Grantee: "analytics"},
+	}
+	promotion := &app.M4DCopyPromotion{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingest", Namespace: "default"},
+		Spec:       app.M4DCopyPromotionSpec{Application: "notebook", DataSetID: "s3/allow-dataset"},
+		Status:     app.M4DCopyPromotionStatus{Promoted: true, DatasetRef: "copy-dataset", SecretRef: "copy-credentials"},
+	}
+	promotionSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "copy-credentials", Namespace: "m4d-system"},
+		Data:       map[string][]byte{"accessKey": []byte("key"), "secretKey": []byte("secret")},
+	}
+	source := fake.NewFakeClientWithScheme(newScheme(g), account, secret, application, grant, promotion, promotionSecret)
+	bundle, err := Export(context.Background(), source, "m4d-system")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(bundle.StorageAccounts).To(gomega.HaveLen(1))
+	g.Expect(bundle.Applications).To(gomega.HaveLen(1))
+	g.Expect(bundle.Grants).To(gomega.HaveLen(1))
+	g.Expect(bundle.Promotions).To(gomega.HaveLen(1))
+	// the secret values are redacted, the missing application secret is skipped
+	g.Expect(bundle.Secrets).To(gomega.HaveLen(2))
+	g.Expect(bundle.Secrets[0].Data).To(gomega.BeEmpty())
+	g.Expect(bundle.Secrets[0].StringData).To(gomega.HaveKeyWithValue("secretKey", Redacted))
+
+	target := fake.NewFakeClientWithScheme(newScheme(g))
+	missing, err := Import(context.Background(), target, bundle,
+		ImportOptions{Namespaces: map[string]string{"m4d-system": "m4d-dr", "analytics": "analytics-dr"}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(missing).To(gomega.ConsistOf(
+		client.ObjectKey{Namespace: "m4d-dr", Name: "credentials"},
+		client.ObjectKey{Namespace: "default", Name: "user-credentials"},
+		client.ObjectKey{Namespace: "m4d-dr", Name: "copy-credentials"}))
+
+	imported := &app.M4DStorageAccount{}
+	g.Expect(target.Get(context.Background(), client.ObjectKey{Namespace: "m4d-dr", Name: "account"}, imported)).To(gomega.Succeed())
+	g.Expect(imported.Spec).To(gomega.Equal(account.Spec))
+	importedApp := &app.M4DApplication{}
+	g.Expect(target.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "notebook"}, importedApp)).To(gomega.Succeed())
+	g.Expect(importedApp.Finalizers).To(gomega.BeEmpty())
+	g.Expect(importedApp.Status.Ready).To(gomega.BeFalse())
+	// the copies are shared with the mapped namespaces
+	importedGrant := &app.M4DGrant{}
+	g.Expect(target.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "share"}, importedGrant)).To(gomega.Succeed())
+	g.Expect(importedGrant.Spec.Grantee).To(gomega.Equal("analytics-dr"))
+	// the promoted copies keep their storage
+	importedPromotion := &app.M4DCopyPromotion{}
+	g.Expect(target.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "ingest"}, importedPromotion)).To(gomega.Succeed())
+	g.Expect(importedPromotion.Status).To(gomega.Equal(promotion.Status))
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+	This package provides the semantics of matching module capabilities against the interfaces requested by applications.
+	It is used by the manager for module selection, and can be used by module authors and external tools
+	to verify that a module specification matches the expected applications.
+
+	Matching rules:
+	- A requested interface matches a capability interface if both the protocol and the data format match.
+	- A module may declare the wildcard "*" as a protocol or a data format to indicate that any value is supported.
+	  The interfaces are matched by pkg/capabilities/match, which the admission checks of applications also use.
+	- A module may declare several capability entries for the same flow; it supports an interface if any of them matches.
+	- A module may expose several APIs on distinct endpoints; the first API matching the requested interface serves the data.
+	- A module may declare that it streams a read source, e.g. a Kafka topic, in which case the source is read without a copy.
+*/
+
+package capabilities
+
+import (
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/capabilities/match"
+)
+
+// Wildcard matches any protocol or data format when declared by a module
+const Wildcard = match.Wildcard
+
+// GetModuleCapabilities returns the capability entries of the module for the given flow.
+// An empty list is returned if the module does not support the flow.
+func GetModuleCapabilities(module *app.M4DModule, flow app.ModuleFlow) []app.ModuleInOut {
+	var list []app.ModuleInOut
+	if !SupportsFlow(module.Spec.Flows, flow) {
+		return list
+	}
+	return declaredInterfaces(module, flow)
+}
+
+// declaredInterfaces returns the capability entries that the module declares for the given flow,
+// whether or not the flow is listed in the flows of the module
+func declaredInterfaces(module *app.M4DModule, flow app.ModuleFlow) []app.ModuleInOut {
+	var list []app.ModuleInOut
+	for _, inter := range module.Spec.Capabilities.SupportedInterfaces {
+		if inter.Flow == flow {
+			list = append(list, inter)
+		}
+	}
+	return list
+}
+
+// GetSupportedReadSources returns a list of supported READ interfaces of a module.
+// The sources declared for the read flow are returned even if the module does not list the read flow,
+// e.g. to find the interfaces that copy modules have to produce for it.
+func GetSupportedReadSources(module *app.M4DModule) []*app.InterfaceDetails {
+	var list []*app.InterfaceDetails
+	for _, inter := range declaredInterfaces(module, app.Read) {
+		if inter.Source != nil {
+			list = append(list, inter.Source)
+		}
+	}
+	return list
+}
+
+// GetStreamingReadSources returns a list of the READ interfaces that a module streams without a copy
+func GetStreamingReadSources(module *app.M4DModule) []*app.InterfaceDetails {
+	var list []*app.InterfaceDetails
+	for _, inter := range GetModuleCapabilities(module, app.Read) {
+		if inter.Streaming && inter.Source != nil {
+			list = append(list, inter.Source)
+		}
+	}
+	return list
+}
+
+// GetSupportedWriteSinks returns a list of supported WRITE interfaces of a module
+func GetSupportedWriteSinks(module *app.M4DModule) []*app.InterfaceDetails {
+	var list []*app.InterfaceDetails
+	for _, inter := range GetModuleCapabilities(module, app.Write) {
+		if inter.Sink != nil {
+			list = append(list, inter.Sink)
+		}
+	}
+	return list
+}
+
+// MatchesInterface returns true if the interface declared by a module supports the requested interface
+func MatchesInterface(declared *app.InterfaceDetails, requested *app.InterfaceDetails) bool {
+	if declared == nil || requested == nil {
+		return false
+	}
+	return match.Interface(declared.Protocol, declared.DataFormat, requested.Protocol, requested.DataFormat)
+}
+
+// SupportsInterface returns true iff the protocol/format list contains an interface matching the requested one
+func SupportsInterface(array []*app.InterfaceDetails, requested *app.InterfaceDetails) bool {
+	for _, item := range array {
+		if MatchesInterface(item, requested) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsFlow checks whether the given flow element can be found inside the array of flows
+func SupportsFlow(array []app.ModuleFlow, element app.ModuleFlow) bool {
+	for _, flow := range array {
+		if flow == element {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsAPI returns true if the module exposes an API matching the interface requested by the application
+func SupportsAPI(module *app.M4DModule, requested *app.InterfaceDetails) bool {
+	return GetMatchingAPI(module, requested) != nil
+}
+
+// GetModuleAPIs returns the APIs exposed by the module, starting with its default API
+func GetModuleAPIs(module *app.M4DModule) []*app.ModuleAPI {
+	var list []*app.ModuleAPI
+	if module.Spec.Capabilities.API != nil {
+		list = append(list, module.Spec.Capabilities.API)
+	}
+	for i := range module.Spec.Capabilities.APIs {
+		list = append(list, &module.Spec.Capabilities.APIs[i])
+	}
+	return list
+}
+
+// GetMatchingAPI returns the first API of the module matching the interface requested by the application,
+// or nil if there is none
+func GetMatchingAPI(module *app.M4DModule, requested *app.InterfaceDetails) *app.ModuleAPI {
+	for _, api := range GetModuleAPIs(module) {
+		if MatchesInterface(&api.InterfaceDetails, requested) {
+			return api
+		}
+	}
+	return nil
+}
+
+// SupportsCopy returns true if the module is able to copy data from the source interface to the sink interface
+func SupportsCopy(module *app.M4DModule, source *app.InterfaceDetails, sink *app.InterfaceDetails) bool {
+	for _, inter := range GetModuleCapabilities(module, app.Copy) {
+		if MatchesInterface(inter.Source, source) && MatchesInterface(inter.Sink, sink) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsStreamingRead returns true if the module streams the source interface to the requested interface
+func SupportsStreamingRead(module *app.M4DModule, source *app.InterfaceDetails, requested *app.InterfaceDetails) bool {
+	return SupportsAPI(module, requested) && SupportsInterface(GetStreamingReadSources(module), source)
+}
+
+// SupportsWrite returns true if the module exposes the requested interface to the application and writes the data to the sink interface
+func SupportsWrite(module *app.M4DModule, requested *app.InterfaceDetails, sink *app.InterfaceDetails) bool {
+	return SupportsAPI(module, requested) && SupportsInterface(GetSupportedWriteSinks(module), sink)
+}
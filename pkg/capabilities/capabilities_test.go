@@ -0,0 +1,132 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package capabilities
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+)
+
+func testModule() *app.M4DModule {
+	return &app.M4DModule{
+		Spec: app.M4DModuleSpec{
+			Flows: []app.ModuleFlow{app.Read, app.Copy},
+			Capabilities: app.Capability{
+				API: &app.ModuleAPI{
+					InterfaceDetails: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow},
+				},
+				SupportedInterfaces: []app.ModuleInOut{
+					{
+						Flow:   app.Read,
+						Source: &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet},
+					},
+					{
+						Flow:   app.Read,
+						Source: &app.InterfaceDetails{Protocol: app.Kafka, DataFormat: Wildcard},
+					},
+					{
+						Flow:   app.Copy,
+						Source: &app.InterfaceDetails{Protocol: app.JdbcDb2, DataFormat: app.Table},
+						Sink:   &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet},
+					},
+					{
+						Flow:   app.Write,
+						Source: &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetModuleCapabilities(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	module := testModule()
+
+	g.Expect(GetModuleCapabilities(module, app.Read)).To(gomega.HaveLen(2))
+	g.Expect(GetModuleCapabilities(module, app.Copy)).To(gomega.HaveLen(1))
+	// write is declared as an interface but not as a flow
+	g.Expect(GetModuleCapabilities(module, app.Write)).To(gomega.BeEmpty())
+	g.Expect(GetSupportedReadSources(module)).To(gomega.HaveLen(2))
+	// the read sources do not depend on the flows of the module
+	module.Spec.Flows = []app.ModuleFlow{app.Copy}
+	g.Expect(GetModuleCapabilities(module, app.Read)).To(gomega.BeEmpty())
+	g.Expect(GetSupportedReadSources(module)).To(gomega.HaveLen(2))
+}
+
+func TestMatchesInterface(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	s3Parquet := &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet}
+	s3CSV := &app.InterfaceDetails{Protocol: app.S3, DataFormat: "csv"}
+	anyS3 := &app.InterfaceDetails{Protocol: app.S3, DataFormat: Wildcard}
+
+	g.Expect(MatchesInterface(s3Parquet, s3Parquet)).To(gomega.BeTrue())
+	g.Expect(MatchesInterface(s3Parquet, s3CSV)).To(gomega.BeFalse())
+	g.Expect(MatchesInterface(anyS3, s3CSV)).To(gomega.BeTrue())
+	// the wildcard is only honored when declared by a module
+	g.Expect(MatchesInterface(s3CSV, anyS3)).To(gomega.BeFalse())
+	g.Expect(MatchesInterface(nil, s3CSV)).To(gomega.BeFalse())
+}
+
+func TestSupportsInterface(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	module := testModule()
+	sources := GetSupportedReadSources(module)
+
+	g.Expect(SupportsInterface(sources, &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet})).To(gomega.BeTrue())
+	g.Expect(SupportsInterface(sources, &app.InterfaceDetails{Protocol: app.Kafka, DataFormat: "json"})).To(gomega.BeTrue())
+	g.Expect(SupportsInterface(sources, &app.InterfaceDetails{Protocol: app.JdbcDb2, DataFormat: app.Table})).To(gomega.BeFalse())
+
+	g.Expect(SupportsAPI(module, &app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow})).To(gomega.BeTrue())
+	g.Expect(SupportsAPI(module, &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet})).To(gomega.BeFalse())
+
+	g.Expect(SupportsCopy(module, &app.InterfaceDetails{Protocol: app.JdbcDb2, DataFormat: app.Table},
+		&app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet})).To(gomega.BeTrue())
+	g.Expect(SupportsCopy(module, &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet},
+		&app.InterfaceDetails{Protocol: app.JdbcDb2, DataFormat: app.Table})).To(gomega.BeFalse())
+}
+
+func TestSupportsWrite(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	module := testModule()
+	api := &app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	s3Parquet := &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet}
+
+	// write is not declared as a flow of the test module
+	g.Expect(SupportsWrite(module, api, s3Parquet)).To(gomega.BeFalse())
+
+	module.Spec.Flows = append(module.Spec.Flows, app.Write)
+	module.Spec.Capabilities.SupportedInterfaces = append(module.Spec.Capabilities.SupportedInterfaces,
+		app.ModuleInOut{Flow: app.Write, Sink: s3Parquet})
+	g.Expect(GetSupportedWriteSinks(module)).To(gomega.HaveLen(1))
+	g.Expect(SupportsWrite(module, api, s3Parquet)).To(gomega.BeTrue())
+	g.Expect(SupportsWrite(module, s3Parquet, s3Parquet)).To(gomega.BeFalse())
+	g.Expect(SupportsWrite(module, api, &app.InterfaceDetails{Protocol: app.JdbcDb2, DataFormat: app.Table})).To(gomega.BeFalse())
+}
+
+func TestGetMatchingAPI(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	module := testModule()
+	arrow := &app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	s3Parquet := &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet}
+
+	g.Expect(GetMatchingAPI(module, arrow)).To(gomega.Equal(module.Spec.Capabilities.API))
+	g.Expect(SupportsAPI(module, s3Parquet)).To(gomega.BeFalse())
+
+	module.Spec.Capabilities.APIs = []app.ModuleAPI{{
+		InterfaceDetails: *s3Parquet,
+		Endpoint:         app.EndpointSpec{Name: "s3", Port: 9000, Scheme: "http"},
+	}}
+	g.Expect(GetModuleAPIs(module)).To(gomega.HaveLen(2))
+	g.Expect(SupportsAPI(module, s3Parquet)).To(gomega.BeTrue())
+	g.Expect(GetMatchingAPI(module, s3Parquet).Endpoint.Name).To(gomega.Equal("s3"))
+	g.Expect(GetMatchingAPI(module, arrow)).To(gomega.Equal(module.Spec.Capabilities.API))
+}
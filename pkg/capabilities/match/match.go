@@ -0,0 +1,23 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+	This package matches the interfaces declared by modules against the interfaces requested by applications.
+	It does not depend on the APIs of the manager, such that both the API package, e.g. for the admission checks
+	of applications, and pkg/capabilities, for module selection, apply the same matching rules.
+*/
+
+package match
+
+// Wildcard matches any protocol or data format when declared by a module
+const Wildcard = "*"
+
+// Value returns true if the protocol or data format declared by a module supports the requested one
+func Value(declared string, requested string) bool {
+	return declared == Wildcard || declared == requested
+}
+
+// Interface returns true if the protocol and the data format declared by a module support the requested ones
+func Interface(declaredProtocol string, declaredFormat string, requestedProtocol string, requestedFormat string) bool {
+	return Value(declaredProtocol, requestedProtocol) && Value(declaredFormat, requestedFormat)
+}
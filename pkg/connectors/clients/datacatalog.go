@@ -3,11 +3,25 @@ package clients
 import (
 	"io"
 
+	"emperror.dev/errors"
 	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // DataCatalog is an interface of a facade to a data catalog.
+// Besides providing the details of datasets, a data catalog registers the copies of datasets as new assets
+// with RegisterDatasetInfo. A catalog that does not support registering assets returns an Unimplemented error.
 type DataCatalog interface {
 	pb.DataCatalogServiceServer
 	io.Closer
 }
+
+// IsRegistrationUnsupported returns true if the error reports that the catalog does not support registering assets
+func IsRegistrationUnsupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	errStatus, ok := status.FromError(errors.Cause(err))
+	return ok && errStatus.Code() == codes.Unimplemented
+}
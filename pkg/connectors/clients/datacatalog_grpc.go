@@ -21,11 +21,16 @@ type grpcDataCatalog struct {
 }
 
 // NewGrpcDataCatalog creates a DataCatalog facade that connects to a GRPC service
+// The connection is secured according to security, and is not encrypted if security is nil.
 // You must call .Close() when you are done using the created instance
-func NewGrpcDataCatalog(name string, connectionURL string, connectionTimeout time.Duration) (DataCatalog, error) {
+func NewGrpcDataCatalog(name string, connectionURL string, connectionTimeout time.Duration, security *GrpcSecurity) (DataCatalog, error) {
+	options, err := security.dialOptions()
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("NewGrpcDataCatalog failed to secure the connection to %s", connectionURL))
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
 	defer cancel()
-	connection, err := grpc.DialContext(ctx, connectionURL, grpc.WithInsecure(), grpc.WithBlock())
+	connection, err := grpc.DialContext(ctx, connectionURL, append(options, grpc.WithBlock())...)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("NewGrpcDataCatalog failed when connecting to %s", connectionURL))
 	}
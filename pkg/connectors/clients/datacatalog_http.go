@@ -0,0 +1,117 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Paths of the operations of the REST data catalog API, relative to the base URL of the catalog service.
+// The API is described in pkg/connectors/openapi/datacatalog.yaml.
+const (
+	GetDatasetInfoPath      = "/getDatasetInfo"
+	RegisterDatasetInfoPath = "/registerDatasetInfo"
+)
+
+// Ensure that httpDataCatalog implements the DataCatalog interface
+var _ DataCatalog = (*httpDataCatalog)(nil)
+
+type httpDataCatalog struct {
+	name          string
+	url           string
+	authorization string
+	client        *http.Client
+}
+
+// NewHTTPDataCatalog creates a DataCatalog facade that connects to a catalog service implementing the REST data catalog API.
+// The request and response bodies are the JSON mapping of the messages of the gRPC API.
+// If authorization is not empty, it is sent as the Authorization header of every request.
+func NewHTTPDataCatalog(name string, baseURL string, authorization string, connectionTimeout time.Duration) (DataCatalog, error) {
+	if baseURL == "" {
+		return nil, errors.New("NewHTTPDataCatalog requires the URL of the catalog service")
+	}
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+	return &httpDataCatalog{
+		name:          name,
+		url:           strings.TrimSuffix(baseURL, "/"),
+		authorization: authorization,
+		client:        &http.Client{Timeout: connectionTimeout},
+	}, nil
+}
+
+func (m *httpDataCatalog) GetDatasetInfo(ctx context.Context, in *pb.CatalogDatasetRequest) (*pb.CatalogDatasetInfo, error) {
+	result := &pb.CatalogDatasetInfo{}
+	err := m.post(ctx, GetDatasetInfoPath, in, result)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("get dataset info from %s failed", m.name))
+	}
+	return result, nil
+}
+
+func (m *httpDataCatalog) RegisterDatasetInfo(ctx context.Context, in *pb.RegisterAssetRequest) (*pb.RegisterAssetResponse, error) {
+	result := &pb.RegisterAssetResponse{}
+	err := m.post(ctx, RegisterDatasetInfoPath, in, result)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("register dataset info in %s failed", m.name))
+	}
+	return result, nil
+}
+
+func (m *httpDataCatalog) Close() error {
+	m.client.CloseIdleConnections()
+	return nil
+}
+
+// post sends the request message to the operation of the catalog service, and decodes the response into result
+func (m *httpDataCatalog) post(ctx context.Context, path string, request proto.Message, result proto.Message) error {
+	body, err := protojson.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("Accept", "application/json")
+	if m.authorization != "" {
+		req.Header.Set("Authorization", m.authorization)
+	}
+	res, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode == http.StatusNotImplemented {
+		// the operation is not supported by the catalog, as reported by the GRPC connectors
+		return status.Errorf(codes.Unimplemented, "%s is not implemented: %s", path, strings.TrimSpace(string(data)))
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %s: %s", path, res.Status, strings.TrimSpace(string(data)))
+	}
+	// fields added to the API by newer catalog services are ignored
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, result); err != nil {
+		return errors.Wrap(err, "unknown format of the catalog response")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+var _ = Describe("REST DataCatalog", func() {
+	var requests []map[string]interface{}
+	var headers []http.Header
+	var status int
+	var response string
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		requests = nil
+		headers = nil
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			request := map[string]interface{}{"path": r.URL.Path}
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+			requests = append(requests, request)
+			headers = append(headers, r.Header)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(response))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newCatalog := func(authorization string) clients.DataCatalog {
+		catalog, err := clients.NewHTTPDataCatalog("rest", server.URL+"/", authorization, time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		return catalog
+	}
+
+	It("should read the dataset details", func() {
+		response = `{"datasetId": "s3/small", "details": {"name": "small.csv", "dataFormat": "csv", "geo": "theshire",
+			"dataStore": {"type": "S3", "s3": {"bucket": "m4d-test", "objectKey": "small.csv"}}, "sizeBytes": "1024", "lineage": "unknown"}}`
+		catalog := newCatalog("Bearer token")
+		info, err := catalog.GetDatasetInfo(context.Background(), &pb.CatalogDatasetRequest{DatasetId: "s3/small", CredentialPath: "/v1/creds"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0]).To(HaveKeyWithValue("path", clients.GetDatasetInfoPath))
+		Expect(requests[0]).To(HaveKeyWithValue("datasetId", "s3/small"))
+		Expect(requests[0]).To(HaveKeyWithValue("credentialPath", "/v1/creds"))
+		Expect(headers[0].Get("Authorization")).To(Equal("Bearer token"))
+		Expect(info.GetDetails().GetGeo()).To(Equal("theshire"))
+		Expect(info.GetDetails().GetSizeBytes()).To(Equal(int64(1024)))
+		Expect(info.GetDetails().GetDataStore().GetType()).To(Equal(pb.DataStore_S3))
+		Expect(info.GetDetails().GetDataStore().GetS3().GetBucket()).To(Equal("m4d-test"))
+		Expect(catalog.Close()).To(Succeed())
+	})
+
+	It("should register an asset", func() {
+		response = `{"assetId": "s3/small-copy"}`
+		catalog := newCatalog("")
+		result, err := catalog.RegisterDatasetInfo(context.Background(), &pb.RegisterAssetRequest{
+			DestinationCatalogId: "default",
+			DatasetDetails:       &pb.DatasetDetails{Name: "small.csv", DataFormat: "csv"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.GetAssetId()).To(Equal("s3/small-copy"))
+		Expect(requests[0]).To(HaveKeyWithValue("path", clients.RegisterDatasetInfoPath))
+		Expect(requests[0]).To(HaveKeyWithValue("destinationCatalogId", "default"))
+		Expect(headers[0].Get("Authorization")).To(BeEmpty())
+	})
+
+	It("should report that the registration is not supported", func() {
+		status = http.StatusNotImplemented
+		response = "read-only catalog"
+		_, err := newCatalog("").RegisterDatasetInfo(context.Background(), &pb.RegisterAssetRequest{
+			DestinationCatalogId: "default",
+			DatasetDetails:       &pb.DatasetDetails{Name: "small.csv", DataFormat: "csv"},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(clients.IsRegistrationUnsupported(err)).To(BeTrue())
+	})
+
+	It("should fail if the dataset is not found", func() {
+		status = http.StatusNotFound
+		response = "no such dataset"
+		_, err := newCatalog("").GetDatasetInfo(context.Background(), &pb.CatalogDatasetRequest{DatasetId: "s3/missing"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no such dataset"))
+		Expect(clients.IsRegistrationUnsupported(err)).To(BeFalse())
+	})
+
+	It("should require the URL of the catalog service", func() {
+		_, err := clients.NewHTTPDataCatalog("rest", "", "", time.Minute)
+		Expect(err).To(HaveOccurred())
+	})
+})
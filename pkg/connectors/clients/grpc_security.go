@@ -0,0 +1,105 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"strings"
+
+	"emperror.dev/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// GrpcSecurity configures the security of the connection to a gRPC connector.
+// The connection is not encrypted if no certificate authority is set.
+type GrpcSecurity struct {
+	// CAFile is the PEM bundle of the certificate authorities trusted to verify the certificate of the connector
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key presented to the connector for mutual TLS, if set.
+	// They are read for every handshake, such that rotated certificates are used.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name verified in the certificate of the connector, the host of its URL by default
+	ServerName string
+	// TokenFile contains the bearer token sent in the authorization metadata of every request, if set.
+	// It is read for every request, such that rotated tokens are used. A token requires TLS, i.e. CAFile.
+	TokenFile string
+}
+
+// dialOptions returns the options securing the connection, or the insecure option if the security is not configured
+func (s *GrpcSecurity) dialOptions() ([]grpc.DialOption, error) {
+	if s == nil {
+		return []grpc.DialOption{grpc.WithInsecure()}, nil
+	}
+	var options []grpc.DialOption
+	secure := s.CAFile != ""
+	if secure {
+		config, err := s.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	} else {
+		if s.CertFile != "" || s.KeyFile != "" {
+			return nil, errors.New("a client certificate requires the certificate authority of the connector")
+		}
+		options = append(options, grpc.WithInsecure())
+	}
+	if s.TokenFile != "" {
+		// the token would otherwise be sent in clear text
+		if !secure {
+			return nil, errors.New("a bearer token requires the certificate authority of the connector")
+		}
+		options = append(options, grpc.WithPerRPCCredentials(&bearerToken{file: s.TokenFile}))
+	}
+	return options, nil
+}
+
+func (s *GrpcSecurity) tlsConfig() (*tls.Config, error) {
+	pem, err := ioutil.ReadFile(s.CAFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the certificate authority of the connector")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.Errorf("no certificate has been found in %s", s.CAFile)
+	}
+	config := &tls.Config{RootCAs: pool, ServerName: s.ServerName, MinVersion: tls.VersionTLS12}
+	if s.CertFile != "" || s.KeyFile != "" {
+		if s.CertFile == "" || s.KeyFile == "" {
+			return nil, errors.New("both the client certificate and its key are required for mutual TLS")
+		}
+		// the key pair is checked once, and loaded again for every handshake
+		if _, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile); err != nil {
+			return nil, errors.Wrap(err, "could not load the client certificate")
+		}
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			certificate, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+			return &certificate, err
+		}
+	}
+	return config, nil
+}
+
+// bearerToken injects the token read from a file in the authorization metadata of the requests
+type bearerToken struct {
+	file string
+}
+
+func (t *bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := ioutil.ReadFile(t.file)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the token of the connector")
+	}
+	return map[string]string{"authorization": "Bearer " + strings.TrimSpace(string(token))}, nil
+}
+
+// RequireTransportSecurity prevents sending the token over an unencrypted connection
+func (t *bearerToken) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// authorizationRecorder records the authorization metadata of the requests
+type authorizationRecorder struct {
+	pb.UnimplementedPolicyManagerServiceServer
+	authorizations []string
+}
+
+func (s *authorizationRecorder) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorizations = append(s.authorizations, md.Get("authorization")...)
+	return &pb.PoliciesDecisions{}, nil
+}
+
+// selfSignedCertificate returns a certificate of 127.0.0.1 signed by its own key, and writes it as a certificate authority
+func selfSignedCertificate(caFile string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "connector"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+var _ = Describe("gRPC connector security", func() {
+	var dir string
+	var server *grpc.Server
+	var recorder *authorizationRecorder
+	var address string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "connectors")
+		Expect(err).ToNot(HaveOccurred())
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		address = listener.Addr().String()
+		recorder = &authorizationRecorder{}
+		server = grpc.NewServer()
+		pb.RegisterPolicyManagerServiceServer(server, recorder)
+		go func() {
+			_ = server.Serve(listener)
+		}()
+	})
+
+	AfterEach(func() {
+		server.Stop()
+		os.RemoveAll(dir)
+	})
+
+	It("should send the current bearer token with every request", func() {
+		caFile := filepath.Join(dir, "ca.crt")
+		certificate := selfSignedCertificate(caFile)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		tlsServer := grpc.NewServer(grpc.Creds(credentials.NewServerTLSFromCert(&certificate)))
+		pb.RegisterPolicyManagerServiceServer(tlsServer, recorder)
+		go func() {
+			_ = tlsServer.Serve(listener)
+		}()
+		defer tlsServer.Stop()
+
+		tokenFile := filepath.Join(dir, "token")
+		Expect(ioutil.WriteFile(tokenFile, []byte("first\n"), 0600)).To(Succeed())
+		policyManager, err := clients.NewGrpcPolicyManager("secured", listener.Addr().String(), time.Minute,
+			&clients.GrpcSecurity{CAFile: caFile, TokenFile: tokenFile})
+		Expect(err).ToNot(HaveOccurred())
+		defer policyManager.Close()
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), &pb.ApplicationContext{})
+		Expect(err).ToNot(HaveOccurred())
+		// the rotated token is used
+		Expect(ioutil.WriteFile(tokenFile, []byte("second"), 0600)).To(Succeed())
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), &pb.ApplicationContext{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorder.authorizations).To(Equal([]string{"Bearer first", "Bearer second"}))
+	})
+
+	It("should refuse to send a bearer token without TLS", func() {
+		tokenFile := filepath.Join(dir, "token")
+		Expect(ioutil.WriteFile(tokenFile, []byte("token"), 0600)).To(Succeed())
+		_, err := clients.NewGrpcPolicyManager("insecure", address, time.Minute, &clients.GrpcSecurity{TokenFile: tokenFile})
+		Expect(err).To(MatchError(ContainSubstring("a bearer token requires the certificate authority")))
+		Expect(recorder.authorizations).To(BeEmpty())
+	})
+
+	It("should not send a token if the security is not configured", func() {
+		policyManager, err := clients.NewGrpcPolicyManager("insecure", address, time.Minute, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer policyManager.Close()
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), &pb.ApplicationContext{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorder.authorizations).To(BeEmpty())
+	})
+
+	It("should reject an invalid TLS configuration", func() {
+		caFile := filepath.Join(dir, "ca.crt")
+		Expect(ioutil.WriteFile(caFile, []byte("not a certificate"), 0600)).To(Succeed())
+		_, err := clients.NewGrpcDataCatalog("secured", address, time.Second, &clients.GrpcSecurity{CAFile: caFile})
+		Expect(err).To(MatchError(ContainSubstring("no certificate has been found")))
+
+		_, err = clients.NewGrpcDataCatalog("secured", address, time.Second, &clients.GrpcSecurity{CAFile: filepath.Join(dir, "missing.crt")})
+		Expect(err).To(MatchError(ContainSubstring("could not read the certificate authority")))
+
+		security := &clients.GrpcSecurity{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}
+		_, err = clients.NewGrpcDataCatalog("secured", address, time.Second, security)
+		Expect(err).To(MatchError(ContainSubstring("a client certificate requires the certificate authority")))
+	})
+})
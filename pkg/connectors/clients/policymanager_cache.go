@@ -0,0 +1,121 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+// CachedPolicyManager is a PolicyManager facade that caches the decisions of another policy manager for a TTL.
+// The decisions are keyed by the whole request, i.e. the datasets, the operations and the application context.
+// Errors are not cached. Cached decisions are invalidated when the policies change by calling Invalidate.
+// Decisions fetched while an invalidation happens are returned but not cached, since they may predate the change.
+type CachedPolicyManager struct {
+	pb.UnimplementedPolicyManagerServiceServer
+
+	manager PolicyManager
+	ttl     time.Duration
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*cachedDecisions
+	// generation is incremented by every invalidation
+	generation uint64
+}
+
+type cachedDecisions struct {
+	datasets  []string
+	decisions *pb.PoliciesDecisions
+	expires   time.Time
+}
+
+var _ PolicyManager = (*CachedPolicyManager)(nil)
+
+// NewCachedPolicyManager creates a PolicyManager facade caching the decisions of the given policy manager for the TTL
+// You must call .Close() when you are done using the created instance
+func NewCachedPolicyManager(manager PolicyManager, ttl time.Duration) *CachedPolicyManager {
+	return &CachedPolicyManager{
+		manager: manager,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*cachedDecisions),
+	}
+}
+
+func (m *CachedPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	key, err := proto.MarshalOptions{Deterministic: true}.Marshal(in)
+	if err != nil {
+		return m.manager.GetPoliciesDecisions(ctx, in)
+	}
+	now := m.now()
+	m.mutex.Lock()
+	entry, found := m.entries[string(key)]
+	generation := m.generation
+	m.mutex.Unlock()
+	if found && now.Before(entry.expires) {
+		return proto.Clone(entry.decisions).(*pb.PoliciesDecisions), nil
+	}
+
+	decisions, err := m.manager.GetPoliciesDecisions(ctx, in)
+	if err != nil || decisions == nil {
+		return decisions, err
+	}
+	entry = &cachedDecisions{decisions: proto.Clone(decisions).(*pb.PoliciesDecisions), expires: now.Add(m.ttl)}
+	for _, dataset := range in.GetDatasets() {
+		entry.datasets = append(entry.datasets, dataset.GetDataset().GetDatasetId())
+	}
+	m.mutex.Lock()
+	m.removeExpired(now)
+	if m.generation == generation {
+		m.entries[string(key)] = entry
+	}
+	m.mutex.Unlock()
+	return decisions, nil
+}
+
+// Invalidate removes the cached decisions on the given datasets, or all the cached decisions if no dataset is given.
+// It returns the number of removed decisions.
+func (m *CachedPolicyManager) Invalidate(datasetIDs ...string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.generation++
+	if len(datasetIDs) == 0 {
+		removed := len(m.entries)
+		m.entries = make(map[string]*cachedDecisions)
+		return removed
+	}
+	invalidated := make(map[string]bool, len(datasetIDs))
+	for _, datasetID := range datasetIDs {
+		invalidated[datasetID] = true
+	}
+	removed := 0
+	for key, entry := range m.entries {
+		for _, datasetID := range entry.datasets {
+			if invalidated[datasetID] {
+				delete(m.entries, key)
+				removed++
+				break
+			}
+		}
+	}
+	return removed
+}
+
+// removeExpired removes the expired decisions, so that the cache does not grow with the requests that are not repeated
+func (m *CachedPolicyManager) removeExpired(now time.Time) {
+	for key, entry := range m.entries {
+		if !now.Before(entry.expires) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+func (m *CachedPolicyManager) Close() error {
+	return m.manager.Close()
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// countingPolicyManager allows reading every dataset and counts the requests it receives
+type countingPolicyManager struct {
+	pb.UnimplementedPolicyManagerServiceServer
+	calls int
+	err   error
+	// onCall is called while a request is processed, if set
+	onCall func()
+}
+
+func (m *countingPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	m.calls++
+	if m.onCall != nil {
+		m.onCall()
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	datasetID := in.Datasets[0].Dataset.DatasetId
+	return getTemplate(datasetID, in.Datasets[0].Operation, &pb.EnforcementAction{Name: "Allow", Id: "allow-ID"}), nil
+}
+
+func (m *countingPolicyManager) Close() error {
+	return nil
+}
+
+func readRequest(datasetID string, intent string) *pb.ApplicationContext {
+	return &pb.ApplicationContext{
+		AppInfo: &pb.ApplicationDetails{ProcessingGeography: "theshire", Properties: map[string]string{"intent": intent}},
+		Datasets: []*pb.DatasetContext{{
+			Dataset:   &pb.DatasetIdentifier{DatasetId: datasetID},
+			Operation: &pb.AccessOperation{Type: pb.AccessOperation_READ},
+		}},
+	}
+}
+
+var _ = Describe("CachedPolicyManager", func() {
+	var (
+		manager *countingPolicyManager
+		cache   *clients.CachedPolicyManager
+	)
+
+	BeforeEach(func() {
+		manager = &countingPolicyManager{}
+		cache = clients.NewCachedPolicyManager(manager, time.Hour)
+	})
+
+	It("should answer repeated requests from the cache", func() {
+		first, err := cache.GetPoliciesDecisions(context.Background(), readRequest("s3/allow-dataset", "Fraud Detection"))
+		Expect(err).ToNot(HaveOccurred())
+		second, err := cache.GetPoliciesDecisions(context.Background(), readRequest("s3/allow-dataset", "Fraud Detection"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second.DatasetDecisions[0].Dataset.DatasetId).To(Equal(first.DatasetDecisions[0].Dataset.DatasetId))
+		Expect(manager.calls).To(Equal(1))
+	})
+
+	It("should key the decisions by the application context", func() {
+		_, err := cache.GetPoliciesDecisions(context.Background(), readRequest("s3/allow-dataset", "Fraud Detection"))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = cache.GetPoliciesDecisions(context.Background(), readRequest("s3/allow-dataset", "Marketing"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.calls).To(Equal(2))
+	})
+
+	It("should not cache errors", func() {
+		manager.err = errors.New("connection refused")
+		_, err := cache.GetPoliciesDecisions(context.Background(), readRequest("s3/allow-dataset", "Fraud Detection"))
+		Expect(err).To(HaveOccurred())
+		manager.err = nil
+		_, err = cache.GetPoliciesDecisions(context.Background(), readRequest("s3/allow-dataset", "Fraud Detection"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.calls).To(Equal(2))
+	})
+
+	It("should invalidate the decisions on the given datasets", func() {
+		for _, datasetID := range []string{"s3/allow-dataset", "s3/redact-dataset"} {
+			_, err := cache.GetPoliciesDecisions(context.Background(), readRequest(datasetID, "Fraud Detection"))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(cache.Invalidate("s3/allow-dataset")).To(Equal(1))
+		for _, datasetID := range []string{"s3/allow-dataset", "s3/redact-dataset"} {
+			_, err := cache.GetPoliciesDecisions(context.Background(), readRequest(datasetID, "Fraud Detection"))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(manager.calls).To(Equal(3))
+		Expect(cache.Invalidate()).To(Equal(2))
+	})
+
+	It("should not cache the decisions fetched during an invalidation", func() {
+		manager.onCall = func() { cache.Invalidate("s3/allow-dataset") }
+		_, err := cache.GetPoliciesDecisions(context.Background(), readRequest("s3/allow-dataset", "Fraud Detection"))
+		Expect(err).ToNot(HaveOccurred())
+		manager.onCall = nil
+		_, err = cache.GetPoliciesDecisions(context.Background(), readRequest("s3/allow-dataset", "Fraud Detection"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.calls).To(Equal(2))
+	})
+})
@@ -22,11 +22,16 @@ type grpcPolicyManager struct {
 }
 
 // NewGrpcPolicyManager creates a PolicyManager facade that connects to a GRPC service
+// The connection is secured according to security, and is not encrypted if security is nil.
 // You must call .Close() when you are done using the created instance
-func NewGrpcPolicyManager(name string, connectionURL string, connectionTimeout time.Duration) (PolicyManager, error) {
+func NewGrpcPolicyManager(name string, connectionURL string, connectionTimeout time.Duration, security *GrpcSecurity) (PolicyManager, error) {
+	options, err := security.dialOptions()
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("NewGrpcPolicyManager failed to secure the connection to %s", connectionURL))
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
 	defer cancel()
-	connection, err := grpc.DialContext(ctx, connectionURL, grpc.WithInsecure(), grpc.WithBlock())
+	connection, err := grpc.DialContext(ctx, connectionURL, append(options, grpc.WithBlock())...)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("NewGrpcPolicyManager failed when connecting to %s", connectionURL))
 	}
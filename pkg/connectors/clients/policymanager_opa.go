@@ -0,0 +1,219 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// DefaultOpaPolicyPath is the path of the OPA document that is evaluated by default
+const DefaultOpaPolicyPath = "dataapi/authz"
+
+var _ PolicyManager = (*opaPolicyManager)(nil)
+
+type opaPolicyManager struct {
+	name       string
+	url        string
+	policyPath string
+	catalog    DataCatalog
+	client     *http.Client
+}
+
+// NewOpaPolicyManager creates a PolicyManager facade that evaluates policies with the REST data API of an OPA server.
+// The metadata of the datasets in the OPA input is read from the data catalog.
+func NewOpaPolicyManager(name string, opaServerURL string, policyPath string, catalog DataCatalog, connectionTimeout time.Duration) (PolicyManager, error) {
+	if opaServerURL == "" {
+		return nil, errors.New("NewOpaPolicyManager requires the URL of the OPA server")
+	}
+	if !strings.HasPrefix(opaServerURL, "http://") && !strings.HasPrefix(opaServerURL, "https://") {
+		opaServerURL = "http://" + opaServerURL
+	}
+	if policyPath == "" {
+		policyPath = DefaultOpaPolicyPath
+	}
+	return &opaPolicyManager{
+		name:       name,
+		url:        strings.TrimSuffix(opaServerURL, "/") + "/v1/data/" + strings.Trim(policyPath, "/"),
+		policyPath: policyPath,
+		catalog:    catalog,
+		client:     &http.Client{Timeout: connectionTimeout},
+	}, nil
+}
+
+func (m *opaPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	appInfo, err := toMap(in.GetAppInfo())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode the application details")
+	}
+	result := &pb.PoliciesDecisions{}
+	metadata := map[string]*pb.CatalogDatasetInfo{}
+	for _, datasetContext := range in.GetDatasets() {
+		datasetID := datasetContext.GetDataset().GetDatasetId()
+		if _, found := metadata[datasetID]; !found {
+			info, err := m.catalog.GetDatasetInfo(ctx, &pb.CatalogDatasetRequest{CredentialPath: in.GetCredentialPath(), DatasetId: datasetID})
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("get metadata of %s for evaluating policies failed", datasetID))
+			}
+			metadata[datasetID] = info
+		}
+		input, err := opaInput(metadata[datasetID], datasetContext.GetOperation(), appInfo)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to build the OPA input for %s", datasetID))
+		}
+		decision, err := m.evaluate(ctx, input, datasetContext.GetOperation())
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("get policies decisions from %s failed", m.name))
+		}
+		result.DatasetDecisions = append(result.DatasetDecisions, &pb.DatasetDecision{
+			Dataset:   datasetContext.GetDataset(),
+			Decisions: []*pb.OperationDecision{decision},
+		})
+	}
+	return result, nil
+}
+
+func (m *opaPolicyManager) Close() error {
+	return nil
+}
+
+// opaInput combines the metadata of the dataset, the requested operation and the application details
+// into the input document of the policies, as the OPA connector does
+func opaInput(metadata *pb.CatalogDatasetInfo, operation *pb.AccessOperation, appInfo map[string]interface{}) (map[string]interface{}, error) {
+	input, err := toMap(metadata)
+	if err != nil {
+		return nil, err
+	}
+	operationMap, err := toMap(operation)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range operationMap {
+		input[k] = v
+	}
+	input["type"] = operation.GetType().String()
+	for k, v := range appInfo {
+		input[k] = v
+	}
+	return input, nil
+}
+
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return result, json.Unmarshal(data, &result)
+}
+
+// opaResponse is the response of OPA to the evaluation of the policies.
+// The result is missing if no policies are loaded, which allows the operation.
+type opaResponse struct {
+	Result *struct {
+		Deny      []opaRule `json:"deny"`
+		Transform []opaRule `json:"transform"`
+	} `json:"result"`
+}
+
+type opaRule struct {
+	ActionName string                 `json:"action_name"`
+	Arguments  map[string]interface{} `json:"arguments"`
+	UsedPolicy *struct {
+		Description string `json:"description"`
+	} `json:"used_policy"`
+}
+
+func (m *opaPolicyManager) evaluate(ctx context.Context, input map[string]interface{}, operation *pb.AccessOperation) (*pb.OperationDecision, error) {
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	res, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("evaluation of %s returned status %s", m.policyPath, res.Status)
+	}
+	response := &opaResponse{}
+	if err := json.NewDecoder(res.Body).Decode(response); err != nil {
+		return nil, errors.Wrap(err, "unknown format of OPA evaluation")
+	}
+	return operationDecision(response, operation)
+}
+
+// operationDecision translates the evaluation of the policies for an operation into enforcement actions
+func operationDecision(response *opaResponse, operation *pb.AccessOperation) (*pb.OperationDecision, error) {
+	decision := &pb.OperationDecision{Operation: operation}
+	if response.Result != nil {
+		if len(response.Result.Deny) > 0 {
+			decision.EnforcementActions = append(decision.EnforcementActions,
+				&pb.EnforcementAction{Name: "Deny", Id: "Deny-ID", Level: pb.EnforcementAction_DATASET, Args: map[string]string{}})
+			for _, rule := range response.Result.Deny {
+				decision.UsedPolicies = appendUsedPolicy(decision.UsedPolicies, rule)
+			}
+		}
+		for _, rule := range response.Result.Transform {
+			action, err := enforcementAction(rule)
+			if err != nil {
+				return nil, err
+			}
+			decision.EnforcementActions = append(decision.EnforcementActions, action)
+			decision.UsedPolicies = appendUsedPolicy(decision.UsedPolicies, rule)
+		}
+	}
+	if len(decision.EnforcementActions) == 0 {
+		decision.EnforcementActions = append(decision.EnforcementActions,
+			&pb.EnforcementAction{Name: "Allow", Id: "Allow-ID", Level: pb.EnforcementAction_DATASET, Args: map[string]string{}})
+	}
+	return decision, nil
+}
+
+// opaTransformations maps the transformations returned by the policies to enforcement actions and their arguments
+var opaTransformations = map[string]struct {
+	name      string
+	level     pb.EnforcementAction_EnforcementActionLevel
+	arguments []string
+}{
+	"remove column":     {"removed", pb.EnforcementAction_COLUMN, []string{"column_name"}},
+	"encrypt column":    {"encrypted", pb.EnforcementAction_COLUMN, []string{"column_name"}},
+	"redact column":     {"redact", pb.EnforcementAction_COLUMN, []string{"column_name"}},
+	"periodic blackout": {"periodic_blackout", pb.EnforcementAction_DATASET, []string{"monthly_days_end", "yearly_days_end"}},
+}
+
+func enforcementAction(rule opaRule) (*pb.EnforcementAction, error) {
+	transformation, found := opaTransformations[rule.ActionName]
+	if !found {
+		return nil, fmt.Errorf("unknown transform action %q", rule.ActionName)
+	}
+	for _, argument := range transformation.arguments {
+		if value, ok := rule.Arguments[argument].(string); ok {
+			return &pb.EnforcementAction{Name: transformation.name, Id: transformation.name + "-ID",
+				Level: transformation.level, Args: map[string]string{argument: value}}, nil
+		}
+	}
+	return nil, fmt.Errorf("transform action %q requires one of the arguments %s", rule.ActionName, strings.Join(transformation.arguments, ", "))
+}
+
+func appendUsedPolicy(policies []*pb.Policy, rule opaRule) []*pb.Policy {
+	if rule.UsedPolicy == nil {
+		return policies
+	}
+	return append(policies, &pb.Policy{Description: rule.UsedPolicy.Description})
+}
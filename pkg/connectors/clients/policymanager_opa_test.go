@@ -0,0 +1,118 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+type testCatalog struct {
+	pb.UnimplementedDataCatalogServiceServer
+}
+
+func (c *testCatalog) GetDatasetInfo(ctx context.Context, in *pb.CatalogDatasetRequest) (*pb.CatalogDatasetInfo, error) {
+	return &pb.CatalogDatasetInfo{DatasetId: in.DatasetId, Details: &pb.DatasetDetails{Name: "small.csv", Geo: "theshire"}}, nil
+}
+
+func (c *testCatalog) Close() error {
+	return nil
+}
+
+var _ = Describe("OPA PolicyManager", func() {
+	var inputs []map[string]interface{}
+	var response string
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		inputs = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.URL.Path).To(Equal("/v1/data/dataapi/authz"))
+			request := map[string]map[string]interface{}{}
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+			inputs = append(inputs, request["input"])
+			_, _ = w.Write([]byte(response))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	getDecision := func() (*pb.OperationDecision, error) {
+		policyManager, err := clients.NewOpaPolicyManager("opa", server.URL, "", &testCatalog{}, time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		decisions, err := policyManager.GetPoliciesDecisions(context.Background(), &pb.ApplicationContext{
+			AppInfo: &pb.ApplicationDetails{ProcessingGeography: "theshire", Properties: map[string]string{"intent": "fraud detection"}},
+			Datasets: []*pb.DatasetContext{{
+				Dataset:   &pb.DatasetIdentifier{DatasetId: "s3/small"},
+				Operation: &pb.AccessOperation{Type: pb.AccessOperation_READ},
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		Expect(decisions.DatasetDecisions).To(HaveLen(1))
+		Expect(decisions.DatasetDecisions[0].Decisions).To(HaveLen(1))
+		return decisions.DatasetDecisions[0].Decisions[0], nil
+	}
+
+	It("should send the dataset metadata, operation and application details", func() {
+		response = `{"result": {}}`
+		_, err := getDecision()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inputs).To(HaveLen(1))
+		Expect(inputs[0]).To(HaveKeyWithValue("type", "READ"))
+		Expect(inputs[0]).To(HaveKeyWithValue("dataset_id", "s3/small"))
+		Expect(inputs[0]).To(HaveKeyWithValue("processing_geography", "theshire"))
+		Expect(inputs[0]).To(HaveKey("details"))
+	})
+
+	It("should allow the operation if no policies are loaded", func() {
+		response = `{"decision_id": "1"}`
+		decision, err := getDecision()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decision.EnforcementActions).To(HaveLen(1))
+		Expect(decision.EnforcementActions[0].Name).To(Equal("Allow"))
+	})
+
+	It("should deny the operation", func() {
+		response = `{"result": {"deny": [{"used_policy": {"description": "no access to small datasets"}}], "transform": []}}`
+		decision, err := getDecision()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decision.EnforcementActions).To(HaveLen(1))
+		Expect(decision.EnforcementActions[0].Name).To(Equal("Deny"))
+		Expect(decision.UsedPolicies[0].Description).To(Equal("no access to small datasets"))
+	})
+
+	It("should translate transformations to enforcement actions", func() {
+		response = `{"result": {"transform": [
+			{"action_name": "redact column", "arguments": {"column_name": "nameOrig"}, "used_policy": {"description": "redact PII"}},
+			{"action_name": "periodic blackout", "arguments": {"yearly_days_end": "62"}}
+		]}}`
+		decision, err := getDecision()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decision.EnforcementActions).To(Equal([]*pb.EnforcementAction{
+			{Name: "redact", Id: "redact-ID", Level: pb.EnforcementAction_COLUMN, Args: map[string]string{"column_name": "nameOrig"}},
+			{Name: "periodic_blackout", Id: "periodic_blackout-ID", Level: pb.EnforcementAction_DATASET, Args: map[string]string{"yearly_days_end": "62"}},
+		}))
+		Expect(decision.UsedPolicies).To(HaveLen(1))
+	})
+
+	It("should fail on unknown transformations", func() {
+		response = `{"result": {"transform": [{"action_name": "shuffle column", "arguments": {"column_name": "nameOrig"}}]}}`
+		_, err := getDecision()
+		Expect(err).To(HaveOccurred())
+	})
+})
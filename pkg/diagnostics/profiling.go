@@ -0,0 +1,59 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package diagnostics
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// ProfilingServer serves the pprof endpoints under /debug/pprof/
+// It implements manager.Runnable so that it is started and stopped together with the manager.
+type ProfilingServer struct {
+	Address string
+	Log     logr.Logger
+}
+
+// NewProfilingServer creates a profiling server listening on the given address
+func NewProfilingServer(address string, log logr.Logger) *ProfilingServer {
+	return &ProfilingServer{Address: address, Log: log}
+}
+
+// Start serves the profiling endpoints until the context is done
+func (s *ProfilingServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	server := &http.Server{Addr: s.Address, Handler: mux}
+
+	errChan := make(chan error, 1)
+	go func() {
+		s.Log.Info("starting profiling server", "address", s.Address)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		return err
+	}
+}
+
+// NeedLeaderElection returns false as every replica of the manager should be profiled
+func (s *ProfilingServer) NeedLeaderElection() bool {
+	return false
+}
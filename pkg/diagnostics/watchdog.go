@@ -0,0 +1,95 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package diagnostics
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	heapBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "m4d_manager_heap_bytes",
+		Help: "Bytes of allocated heap objects of the manager",
+	})
+	goroutines = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "m4d_manager_goroutines",
+		Help: "Number of goroutines of the manager",
+	})
+	thresholdExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "m4d_manager_watchdog_threshold_exceeded_total",
+		Help: "Number of times the watchdog found a resource above its threshold",
+	}, []string{"resource"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(heapBytes, goroutines, thresholdExceeded)
+}
+
+// Watchdog periodically samples the memory and goroutine usage of the manager.
+// The samples are exposed as metrics, and a warning is logged if a threshold is exceeded.
+// A threshold of 0 disables the respective check.
+type Watchdog struct {
+	Interval      time.Duration
+	MaxHeapBytes  uint64
+	MaxGoroutines int
+	Log           logr.Logger
+}
+
+// NewWatchdog creates a new watchdog
+func NewWatchdog(interval time.Duration, maxHeapBytes uint64, maxGoroutines int, log logr.Logger) *Watchdog {
+	return &Watchdog{
+		Interval:      interval,
+		MaxHeapBytes:  maxHeapBytes,
+		MaxGoroutines: maxGoroutines,
+		Log:           log,
+	}
+}
+
+// Start samples the usage until the context is done
+func (w *Watchdog) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		w.Check()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check samples the current usage and reports the resources that exceed their threshold
+func (w *Watchdog) Check() []string {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	numGoroutines := runtime.NumGoroutine()
+	heapBytes.Set(float64(memStats.HeapAlloc))
+	goroutines.Set(float64(numGoroutines))
+
+	var exceeded []string
+	if w.MaxHeapBytes > 0 && memStats.HeapAlloc > w.MaxHeapBytes {
+		w.Log.Info("heap usage exceeds the threshold", "heapBytes", memStats.HeapAlloc, "threshold", w.MaxHeapBytes)
+		exceeded = append(exceeded, "heap")
+	}
+	if w.MaxGoroutines > 0 && numGoroutines > w.MaxGoroutines {
+		w.Log.Info("number of goroutines exceeds the threshold", "goroutines", numGoroutines, "threshold", w.MaxGoroutines)
+		exceeded = append(exceeded, "goroutines")
+	}
+	for _, resource := range exceeded {
+		thresholdExceeded.WithLabelValues(resource).Inc()
+	}
+	return exceeded
+}
+
+// NeedLeaderElection returns false as every replica of the manager should be monitored
+func (w *Watchdog) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package diagnostics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestWatchdogThresholds(t *testing.T) {
+	log := ctrl.Log.WithName("watchdog")
+
+	// no thresholds
+	watchdog := NewWatchdog(time.Minute, 0, 0, log)
+	assert.Empty(t, watchdog.Check())
+
+	// thresholds that are always exceeded
+	watchdog = NewWatchdog(time.Minute, 1, 1, log)
+	assert.ElementsMatch(t, []string{"heap", "goroutines"}, watchdog.Check())
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// lookupPolicyDecisions returns the actions required by the policy manager for the given operation.
+// An error is returned if an action is not defined by the action taxonomy, rather than failing later to match any module.
+func (m *ModuleManager) lookupPolicyDecisions(datasetID string, appContext *app.M4DApplication, op *pb.AccessOperation) ([]*pb.EnforcementAction, error) {
+	actions, err := LookupPolicyDecisions(datasetID, m.PolicyManager, appContext, op)
+	if err != nil {
+		if isPolicyDenied(err) {
+			emitPolicyDecision(m.Events, appContext, policyDecisionEvent(datasetID, op, nil, err.Error()))
+			recordDenial(appContext, datasetID, op)
+			utils.RecordEvent(m.Recorder, appContext, corev1.EventTypeWarning, AccessDeniedReason,
+				"Governance policies deny the %s operation on dataset %s", strings.ToLower(op.Type.String()), datasetID)
+		} else {
+			m.policyManagerUnavailable = true
+		}
+		return actions, err
+	}
+	emitPolicyDecision(m.Events, appContext, policyDecisionEvent(datasetID, op, actions, ""))
+	// storage restrictions are enforced by the manager when allocating the storage of copies, not by the modules
+	if actions, err = m.restrictStorage(datasetID, actions); err != nil {
+		return actions, err
+	}
+	for _, action := range actions {
+		if err := checkAction(m.ActionTaxonomy, action.Id, action.Level); err != nil {
+			return actions, fmt.Errorf("%s from policy manager", err.Error())
+		}
+	}
+	// the data that is read is also transformed to its target privacy level
+	if op.Type == pb.AccessOperation_READ {
+		actions = m.withPrivacyActions(datasetID, actions)
+	}
+	return actions, nil
+}
+
+// policyDecisionEvent describes the decision of the policy manager on an operation, which is either a denial or the required actions
+func policyDecisionEvent(datasetID string, op *pb.AccessOperation, actions []*pb.EnforcementAction, denial string) events.Event {
+	event := events.Event{
+		Type:        events.PolicyDecision,
+		DatasetID:   datasetID,
+		Operation:   strings.ToLower(op.Type.String()),
+		Destination: op.Destination,
+		Reason:      denial,
+	}
+	for _, action := range actions {
+		event.Actions = append(event.Actions, action.Id)
+	}
+	return event
+}
+
+// checkAction returns an error if the action identifier or its level are not defined by the action taxonomy
+func checkAction(actions taxonomy.Actions, id string, level pb.EnforcementAction_EnforcementActionLevel) error {
+	if !actions.Known(id) {
+		return fmt.Errorf("unknown action id %s", id)
+	}
+	if !actions.SupportsLevel(id, level) {
+		return fmt.Errorf("action id %s is not defined for level %s", id, level.String())
+	}
+	return nil
+}
+
+// modulesWithKnownActions returns the modules that declare only actions defined by the action taxonomy.
+// The other modules are not selected, since the actions they declare can never be required.
+func modulesWithKnownActions(moduleMap map[string]*app.M4DModule, actions taxonomy.Actions, log logr.Logger) map[string]*app.M4DModule {
+	selectable := make(map[string]*app.M4DModule, len(moduleMap))
+	for name, module := range moduleMap {
+		valid := true
+		for _, action := range module.Spec.Capabilities.Actions {
+			if err := checkAction(actions, action.ID, action.Level); err != nil {
+				log.Info(fmt.Sprintf("module %s is ignored: %s declared by the module", name, err.Error()))
+				valid = false
+				break
+			}
+		}
+		if valid {
+			selectable[name] = module
+		}
+	}
+	return selectable
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"reflect"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+)
+
+// DenyAction is the name of the action recorded in the asset states for an operation denied by the governance policies
+const DenyAction = "Deny"
+
+// recordAppliedActions reports in the status the governance actions that the module instances selected for a dataset
+// apply to its data
+func recordAppliedActions(appContext *app.M4DApplication, datasetID string, instances []modules.ModuleInstanceSpec) {
+	for _, instance := range instances {
+		if instance.Args == nil {
+			continue
+		}
+		if instance.Args.Copy != nil {
+			addAppliedActions(appContext, datasetID, app.Copy, instance.Module.Name, instance.Args.Copy.Transformations)
+		}
+		for _, read := range instance.Args.Read {
+			addAppliedActions(appContext, datasetID, app.Read, instance.Module.Name, read.Transformations)
+		}
+		for _, write := range instance.Args.Write {
+			addAppliedActions(appContext, datasetID, app.Write, instance.Module.Name, write.Transformations)
+		}
+	}
+}
+
+func addAppliedActions(appContext *app.M4DApplication, datasetID string, flow app.ModuleFlow, module string, transformations []serde.Arbitrary) {
+	for _, transformation := range transformations {
+		action, ok := transformation.Data.(*pb.EnforcementAction)
+		if !ok {
+			continue
+		}
+		addAssetAction(appContext, datasetID, app.AppliedAction{
+			Name:   action.Name,
+			Level:  action.Level.String(),
+			Args:   action.Args,
+			Flow:   flow,
+			Module: module,
+		})
+	}
+}
+
+// recordDenial reports in the status an operation on a dataset denied by the governance policies
+func recordDenial(appContext *app.M4DApplication, datasetID string, op *pb.AccessOperation) {
+	flow := app.Read
+	if op.Type == pb.AccessOperation_WRITE {
+		flow = app.Write
+	}
+	addAssetAction(appContext, datasetID, app.AppliedAction{
+		Name:        DenyAction,
+		Flow:        flow,
+		Destination: op.Destination,
+	})
+}
+
+// addAssetAction appends an action to the state of the asset, unless it has already been recorded
+func addAssetAction(appContext *app.M4DApplication, datasetID string, action app.AppliedAction) {
+	if appContext.Status.AssetStates == nil {
+		appContext.Status.AssetStates = make(map[string]app.AssetState)
+	}
+	state := appContext.Status.AssetStates[datasetID]
+	for _, recorded := range state.Actions {
+		if reflect.DeepEqual(recorded, action) {
+			return
+		}
+	}
+	state.Actions = append(state.Actions, action)
+	appContext.Status.AssetStates[datasetID] = state
+}
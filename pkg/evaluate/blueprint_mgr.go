@@ -0,0 +1,159 @@
+// Copyright 2020 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"sync"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	// Temporary - shouldn't have something specific to implicit copies
+)
+
+// indexPool reuses the maps indexing the module instances and the templates by their keys,
+// which are allocated for every cluster in every generation of the blueprints
+var indexPool = sync.Pool{
+	New: func() interface{} {
+		return map[string]int{}
+	},
+}
+
+// getIndex returns an empty index from the pool
+func getIndex() map[string]int {
+	return indexPool.Get().(map[string]int)
+}
+
+// putIndex empties the index and returns it to the pool
+func putIndex(index map[string]int) {
+	for key := range index {
+		delete(index, key)
+	}
+	indexPool.Put(index)
+}
+
+// RefineInstances collects all instances of the same read/write module and creates a new instance instead, with accumulated arguments.
+// Copy modules and the modules of transformation chains are left unchanged.
+func (e *Evaluator) RefineInstances(instances []modules.ModuleInstanceSpec) []modules.ModuleInstanceSpec {
+	newInstances := make([]modules.ModuleInstanceSpec, 0, len(instances))
+	// index of the instances to be unified, according to the cluster and module
+	instanceIndex := getIndex()
+	defer putIndex(instanceIndex)
+	for _, moduleInstance := range instances {
+		if moduleInstance.Args.Copy != nil || moduleInstance.ChainedTo != "" {
+			newInstances = append(newInstances, moduleInstance)
+			continue
+		}
+		key := moduleInstance.Module.GetName() + "," + moduleInstance.ClusterName
+		if i, ok := instanceIndex[key]; !ok {
+			instanceIndex[key] = len(newInstances)
+			newInstances = append(newInstances, moduleInstance)
+		} else {
+			instance := &newInstances[i]
+			instance.Args.Read = append(instance.Args.Read, moduleInstance.Args.Read...)
+			instance.Args.Write = append(instance.Args.Write, moduleInstance.Args.Write...)
+			// AssetID is used for step name generation
+			instance.AssetID += "," + moduleInstance.AssetID
+		}
+	}
+	return newInstances
+}
+
+// GenerateBlueprints creates Blueprint specs (one per cluster)
+func (e *Evaluator) GenerateBlueprints(instances []modules.ModuleInstanceSpec, appContext *app.M4DApplication, clusters []multicluster.Cluster) map[string]app.BlueprintSpec {
+	blueprintMap := make(map[string]app.BlueprintSpec)
+	instanceMap := make(map[string][]modules.ModuleInstanceSpec)
+	for _, moduleInstance := range instances {
+		instanceMap[moduleInstance.ClusterName] = append(instanceMap[moduleInstance.ClusterName], moduleInstance)
+	}
+	mirrors := registryMirrors(clusters)
+	for key, instanceList := range instanceMap {
+		// unite several instances of a read/write module
+		instances := e.RefineInstances(instanceList)
+		blueprintMap[key] = e.GenerateBlueprint(instances, appContext, mirrors[key])
+	}
+	utils.PrintStructure(blueprintMap, e.Log, "BlueprintMap")
+	return blueprintMap
+}
+
+// GenerateBlueprint creates the Blueprint spec based on the datasets and the governance actions required, which dictate the modules that must run in the m4d
+// Credentials for accessing data set are stored in a credential management system (such as vault) and the paths for accessing them are included in the blueprint.
+// The credentials themselves are not included in the blueprint.
+// Charts and images are pulled from the registry mirrors of the cluster, if any.
+func (e *Evaluator) GenerateBlueprint(instances []modules.ModuleInstanceSpec, appContext *app.M4DApplication, mirrors map[string]string) app.BlueprintSpec {
+	var spec app.BlueprintSpec
+
+	// Entrypoint is always the name of the application
+	appName := appContext.GetName()
+	spec.Entrypoint = appName
+	e.Log.V(0).Info("\tappName: " + appName)
+
+	// Define the flow structure, which indicates the flow of data between the components in the m4d
+	// Loop over the list of modules and create a step for each
+	// Also create a template for each module specification - i.e. there could be multiple instances of a module, each with different arguments
+	var flow app.DataFlow
+	flow.Name = appName
+	steps := make([]app.FlowStep, 0, len(instances))
+	templates := make([]app.ComponentTemplate, 0, len(instances))
+	templateIndex := getIndex()
+	defer putIndex(templateIndex)
+	for _, moduleInstance := range instances {
+		modulename := moduleInstance.Module.GetName()
+
+		// Create a flow step
+		var step app.FlowStep
+		step.Name = utils.CreateStepName(modulename, moduleInstance.AssetID) // Need unique name for each step so include ids for dataset
+		step.Template = modulename
+
+		step.Arguments = *moduleInstance.Args
+		// the resources of the module are labeled with the labels of the application for cost attribution,
+		// and annotated with the identifiers of the datasets that the module instance serves
+		step.Arguments.Labels = propagatedLabels(appContext)
+		step.Arguments.Annotations = map[string]string{app.DatasetIDsAnnotation: moduleInstance.AssetID}
+		if moduleInstance.ChainedTo != "" {
+			step.Arguments.Annotations[app.ChainedToAnnotation] = moduleInstance.ChainedTo
+		}
+		// the scheduling of the pods of the modules is set per capability by the modules and the administrators
+		step.Arguments.Scheduling = stepScheduling(moduleInstance.Module, ArgumentsFlow(&step.Arguments), e.Scheduling)
+		step.Arguments.Resources = stepResources(moduleInstance.Module, appContext, moduleInstance.AssetID)
+		// the governance sidecars required by the modules have been checked by the evaluation
+		sidecars, err := moduleSidecars(moduleInstance.Module, e.Sidecars)
+		if err != nil {
+			e.Log.Error(err, "Ignoring the governance sidecars of module "+modulename)
+		}
+		step.Arguments.Sidecars = sidecars
+
+		steps = append(steps, step)
+
+		// If one doesn't exist already, create a template
+		if _, found := templateIndex[modulename]; !found {
+			var template app.ComponentTemplate
+			template.Name = modulename
+			template.Kind = moduleInstance.Module.TypeMeta.Kind
+			template.Chart = mirrorChart(clusterChart(moduleInstance.Module.Spec.Chart, moduleInstance.ClusterName), mirrors)
+
+			templateIndex[modulename] = len(templates)
+			templates = append(templates, template)
+		}
+	}
+	flow.Steps = steps
+
+	spec.Flow = flow
+	spec.Templates = templates
+
+	return spec
+}
+
+// propagatedLabels returns the labels of the application to be propagated to the resources of the modules, e.g. team or cost center
+func propagatedLabels(appContext *app.M4DApplication) map[string]string {
+	if len(appContext.Labels) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(appContext.Labels))
+	for key, value := range appContext.Labels {
+		labels[key] = value
+	}
+	return labels
+}
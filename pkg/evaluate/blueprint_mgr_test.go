@@ -0,0 +1,40 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestRefineInstances checks that the instances of a read module in a cluster are unified in the order of the instances,
+// and that the pooled index does not leak between the refinements
+func TestRefineInstances(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	read := &app.M4DModule{ObjectMeta: metav1.ObjectMeta{Name: "arrow-flight"}}
+	copyModule := &app.M4DModule{ObjectMeta: metav1.ObjectMeta{Name: "implicit-copy"}}
+	newInstance := func(module *app.M4DModule, assetID string) modules.ModuleInstanceSpec {
+		args := &app.ModuleArguments{Read: []app.ReadModuleArgs{{AssetID: assetID}}}
+		if module == copyModule {
+			args = &app.ModuleArguments{Copy: &app.CopyModuleArgs{}}
+		}
+		return modules.ModuleInstanceSpec{Module: module, Args: args, AssetID: assetID, ClusterName: "cluster"}
+	}
+	evaluator := &Evaluator{}
+	for i := 0; i < 2; i++ {
+		refined := evaluator.RefineInstances([]modules.ModuleInstanceSpec{
+			newInstance(copyModule, "s3/a"), newInstance(read, "s3/a"), newInstance(read, "s3/b"),
+		})
+		g.Expect(refined).To(gomega.HaveLen(2))
+		g.Expect(refined[0].Module).To(gomega.Equal(copyModule))
+		g.Expect(refined[1].AssetID).To(gomega.Equal("s3/a,s3/b"))
+		g.Expect(refined[1].Args.Read).To(gomega.HaveLen(2))
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+)
+
+// CatalogHash computes a hash of the dataset metadata received from the data catalog.
+// The size and the modification time are excluded, since they change with the data without affecting the governance decisions.
+func CatalogHash(details *modules.DataDetails) (string, error) {
+	metadata := *details
+	metadata.SizeBytes = 0
+	metadata.LastModified = time.Time{}
+	bytes, err := json.Marshal(&metadata)
+	if err != nil {
+		return "", err
+	}
+	return utils.Hash(string(bytes), 20), nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/onsi/gomega"
+)
+
+func TestCatalogHash(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	details := &modules.DataDetails{
+		Name:      "xxx",
+		Interface: app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet},
+		Geography: "theshire",
+		Metadata:  &pb.DatasetMetadata{DatasetTags: []string{"PI"}},
+	}
+	hash, err := CatalogHash(details)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// modifications of the data do not change the hash
+	details.SizeBytes = 1024
+	details.LastModified = time.Now()
+	g.Expect(CatalogHash(details)).To(gomega.Equal(hash))
+
+	// modifications of the metadata change the hash
+	details.Geography = "neverland"
+	g.Expect(CatalogHash(details)).NotTo(gomega.Equal(hash))
+	details.Geography = "theshire"
+	details.Metadata = &pb.DatasetMetadata{DatasetTags: []string{"PI", "SPI"}}
+	g.Expect(CatalogHash(details)).NotTo(gomega.Equal(hash))
+}
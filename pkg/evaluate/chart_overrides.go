@@ -0,0 +1,61 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+)
+
+// mergeValues merges the overlay into a copy of the base values. Nested objects are merged recursively,
+// and the other values of the overlay replace those of the base.
+func mergeValues(base map[string]interface{}, overlay map[string]interface{}) map[string]interface{} {
+	merged := utils.CopyMap(base)
+	for key, value := range overlay {
+		baseObject, isBaseObject := merged[key].(map[string]interface{})
+		object, isObject := value.(map[string]interface{})
+		if isBaseObject && isObject {
+			merged[key] = mergeValues(baseObject, object)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// overridesOf returns the overrides as values, or nil if they are not an object
+func overridesOf(overrides *serde.Arbitrary) map[string]interface{} {
+	if overrides == nil {
+		return nil
+	}
+	values, _ := overrides.Data.(map[string]interface{})
+	return values
+}
+
+// clusterChart returns the chart of a module as deployed in the cluster: the overlay of the cluster is merged
+// into the overrides of the chart, and the overlays of the other clusters are dropped from the blueprint
+func clusterChart(chart app.ChartSpec, clusterName string) app.ChartSpec {
+	if len(chart.ClusterOverrides) == 0 {
+		return chart
+	}
+	resolved := *chart.DeepCopy()
+	resolved.ClusterOverrides = nil
+	if overlay, found := chart.ClusterOverrides[clusterName]; found {
+		overrides := mergeValues(overridesOf(chart.Overrides), overridesOf(&overlay))
+		resolved.Overrides = serde.NewArbitrary(overrides)
+	}
+	return resolved
+}
+
+// ApplyOverrides merges the arguments of a module into the overrides of its chart, such that the arguments take
+// precedence, e.g. the image pull policy or the tolerations of the pods of the module are set by the overrides
+// while the copy, read and write arguments are always those set by the manager
+func ApplyOverrides(args map[string]interface{}, chart *app.ChartSpec) map[string]interface{} {
+	overrides := overridesOf(chart.Overrides)
+	if len(overrides) == 0 {
+		return args
+	}
+	return mergeValues(overrides, args)
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/onsi/gomega"
+)
+
+func TestChartOverrides(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	chart := app.ChartSpec{
+		Name: "ghcr.io/mesh-for-data/arrow-flight-module:0.1.0",
+		Overrides: serde.NewArbitrary(map[string]interface{}{
+			"image":        map[string]interface{}{"pullPolicy": "IfNotPresent"},
+			"nodeSelector": map[string]interface{}{"pool": "data"},
+			"read":         "overridden by the arguments",
+		}),
+		ClusterOverrides: map[string]serde.Arbitrary{
+			"thegreendragon": *serde.NewArbitrary(map[string]interface{}{
+				"image":       map[string]interface{}{"pullPolicy": "Always"},
+				"tolerations": []interface{}{map[string]interface{}{"key": "dedicated", "operator": "Exists"}},
+			}),
+		},
+	}
+
+	// the overlay of the cluster is merged into the overrides
+	resolved := clusterChart(chart, "thegreendragon")
+	g.Expect(resolved.ClusterOverrides).To(gomega.BeNil())
+	g.Expect(resolved.Overrides.Data).To(gomega.Equal(map[string]interface{}{
+		"image":        map[string]interface{}{"pullPolicy": "Always"},
+		"nodeSelector": map[string]interface{}{"pool": "data"},
+		"read":         "overridden by the arguments",
+		"tolerations":  []interface{}{map[string]interface{}{"key": "dedicated", "operator": "Exists"}},
+	}))
+	g.Expect(chart.Overrides.Data).To(gomega.HaveKeyWithValue("image", map[string]interface{}{"pullPolicy": "IfNotPresent"}))
+	// the other clusters only receive the overrides
+	other := clusterChart(chart, "neverland-cluster")
+	g.Expect(other.ClusterOverrides).To(gomega.BeNil())
+	g.Expect(other.Overrides).To(gomega.Equal(chart.Overrides))
+
+	// the arguments take precedence over the overrides
+	args := map[string]interface{}{
+		"read":   []interface{}{map[string]interface{}{"assetID": "s3/allow-dataset"}},
+		"labels": map[string]interface{}{"team": "analytics"},
+		"image":  map[string]interface{}{"repository": "ghcr.io/mesh-for-data/arrow-flight-module"},
+	}
+	values := ApplyOverrides(args, &resolved)
+	g.Expect(values["read"]).To(gomega.Equal(args["read"]))
+	g.Expect(values["image"]).To(gomega.Equal(map[string]interface{}{
+		"pullPolicy": "Always",
+		"repository": "ghcr.io/mesh-for-data/arrow-flight-module",
+	}))
+	g.Expect(values).To(gomega.HaveKey("tolerations"))
+	g.Expect(ApplyOverrides(args, &app.ChartSpec{Name: "chart"})).To(gomega.Equal(args))
+}
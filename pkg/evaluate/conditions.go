@@ -0,0 +1,167 @@
+// Copyright 2020 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxConditionMessageLength is the maximum length of the message of a condition accepted by the API server
+const maxConditionMessageLength = 32768
+
+// truncatedSuffix ends the messages of the conditions that have been truncated
+const truncatedSuffix = "...\n"
+
+// conditionTypes lists the conditions always present in the status of an application
+var conditionTypes = []string{
+	app.ReadyCondition,
+	app.DeniedCondition,
+	app.ErrorCondition,
+	app.ProvisionedCondition,
+	app.RevokedCondition,
+	app.PolicyManagerUnavailableCondition,
+}
+
+// SetStatusCondition sets a condition of the application for its current generation.
+// The transition time of the condition is only changed when its status changes.
+func SetStatusCondition(application *app.M4DApplication, conditionType string, status metav1.ConditionStatus, reason string, msg string) {
+	meta.SetStatusCondition(&application.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            msg,
+		ObservedGeneration: application.Generation,
+	})
+	condition := meta.FindStatusCondition(application.Status.Conditions, conditionType)
+	condition.ObservedGeneration = application.Generation
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = metav1.Now()
+	}
+}
+
+// ResetConditions sets the conditions of the application to their nominal state before it is evaluated
+func ResetConditions(application *app.M4DApplication) {
+	// conditions of unknown types, e.g. reported by a previous version, are dropped
+	conditions := make([]metav1.Condition, 0, len(conditionTypes))
+	for _, conditionType := range conditionTypes {
+		if condition := meta.FindStatusCondition(application.Status.Conditions, conditionType); condition != nil {
+			conditions = append(conditions, *condition)
+		}
+	}
+	application.Status.Conditions = conditions
+	if meta.FindStatusCondition(conditions, app.ReadyCondition) == nil {
+		SetStatusCondition(application, app.ReadyCondition, metav1.ConditionFalse, app.PendingReason, "")
+	}
+	SetStatusCondition(application, app.DeniedCondition, metav1.ConditionFalse, app.NominalReason, "")
+	SetStatusCondition(application, app.ErrorCondition, metav1.ConditionFalse, app.NominalReason, "")
+	SetStatusCondition(application, app.ProvisionedCondition, metav1.ConditionTrue, app.NominalReason, "")
+	SetStatusCondition(application, app.PolicyManagerUnavailableCondition, metav1.ConditionFalse, app.NominalReason, "")
+	// revocations are kept until the next evaluation of the application
+	setRevokedCondition(application)
+}
+
+// setRevokedCondition sets the revoked condition according to the revoked datasets in the status
+func setRevokedCondition(application *app.M4DApplication) {
+	revoked := make([]string, 0, len(application.Status.RevokedDatasets))
+	for datasetID := range application.Status.RevokedDatasets {
+		revoked = append(revoked, datasetID)
+	}
+	if len(revoked) == 0 {
+		SetStatusCondition(application, app.RevokedCondition, metav1.ConditionFalse, app.NominalReason, "")
+		return
+	}
+	sort.Strings(revoked)
+	var msg string
+	for _, datasetID := range revoked {
+		msg += app.AccessRevoked + " Asset: " + datasetID
+		if reason := application.Status.RevokedDatasets[datasetID]; reason != "" {
+			msg += " Reason: " + reason
+		}
+		msg += "\n"
+	}
+	SetStatusCondition(application, app.RevokedCondition, metav1.ConditionTrue, app.AccessRevokedReason, msg)
+}
+
+// SetRevokedDatasets records the revoked datasets of the application in its status
+func SetRevokedDatasets(application *app.M4DApplication, revoked map[string]string) {
+	if len(revoked) == 0 {
+		revoked = nil
+	}
+	application.Status.RevokedDatasets = revoked
+	setRevokedCondition(application)
+}
+
+// SetCondition reports an error of the application, adding the message to the ones already reported unless it has
+// already been reported. Fatal errors set the denied condition, and the other errors set the error condition.
+func SetCondition(application *app.M4DApplication, assetID string, msg string, fatalError bool) {
+	if len(application.Status.Conditions) == 0 {
+		ResetConditions(application)
+	}
+	errMsg := "An error was received"
+	if assetID != "" {
+		errMsg += " for asset " + assetID + " . "
+	}
+	if !fatalError {
+		errMsg += "If the error persists, please contact an operator.\n"
+	}
+	errMsg += "Error description: " + msg + "\n"
+	conditionType, reason := app.ErrorCondition, app.ReconcileErrorReason
+	if fatalError {
+		conditionType, reason = app.DeniedCondition, app.InvalidRequestReason
+		if strings.Contains(msg, app.ReadAccessDenied) || strings.Contains(msg, app.WriteNotAllowed) ||
+			strings.Contains(msg, app.GeographyNotAllowed) {
+			reason = app.PolicyDeniedReason
+		}
+	}
+	if condition := meta.FindStatusCondition(application.Status.Conditions, conditionType); condition != nil &&
+		condition.Status == metav1.ConditionTrue {
+		if strings.Contains(condition.Message, errMsg) {
+			errMsg = condition.Message
+		} else {
+			errMsg = condition.Message + errMsg
+		}
+		// a denial by the governance policies is reported even if other errors follow
+		if condition.Reason == app.PolicyDeniedReason {
+			reason = condition.Reason
+		}
+	}
+	SetStatusCondition(application, conditionType, metav1.ConditionTrue, reason, truncateConditionMessage(errMsg))
+}
+
+// truncateConditionMessage cuts a message exceeding the maximum length of the message of a condition,
+// keeping the errors that have been reported first
+func truncateConditionMessage(msg string) string {
+	if len(msg) <= maxConditionMessageLength {
+		return msg
+	}
+	end := maxConditionMessageLength - len(truncatedSuffix)
+	for end > 0 && !utf8.RuneStart(msg[end]) {
+		end--
+	}
+	return msg[:end] + truncatedSuffix
+}
+
+// HasError returns true if the error or the denied condition of the application is set
+func HasError(application *app.M4DApplication) bool {
+	return meta.IsStatusConditionTrue(application.Status.Conditions, app.ErrorCondition) ||
+		meta.IsStatusConditionTrue(application.Status.Conditions, app.DeniedCondition)
+}
+
+// GetErrorMessages returns the messages of the error and the denied conditions of the application
+func GetErrorMessages(application *app.M4DApplication) string {
+	var errMsg string
+	for _, conditionType := range []string{app.ErrorCondition, app.DeniedCondition} {
+		if condition := meta.FindStatusCondition(application.Status.Conditions, conditionType); condition != nil &&
+			condition.Status == metav1.ConditionTrue {
+			errMsg += condition.Message
+		}
+	}
+	return errMsg
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"fmt"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConditions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default", Generation: 3}}
+	ResetConditions(application)
+	g.Expect(application.Status.Conditions).To(gomega.HaveLen(len(conditionTypes)))
+	for _, condition := range application.Status.Conditions {
+		g.Expect(condition.Reason).NotTo(gomega.BeEmpty(), condition.Type)
+		g.Expect(condition.ObservedGeneration).To(gomega.Equal(int64(3)), condition.Type)
+		g.Expect(condition.LastTransitionTime.IsZero()).To(gomega.BeFalse(), condition.Type)
+	}
+	g.Expect(HasError(application)).To(gomega.BeFalse())
+
+	// errors are accumulated, and a denial by the governance policies is reported as such
+	SetCondition(application, "s3/deny-dataset", app.ReadAccessDenied, true)
+	SetCondition(application, "s3/other-dataset", app.ModuleNotFound, true)
+	SetCondition(application, "s3/allow-dataset", "connection refused", false)
+	denied := meta.FindStatusCondition(application.Status.Conditions, app.DeniedCondition)
+	g.Expect(denied.Status).To(gomega.Equal(metav1.ConditionTrue))
+	g.Expect(denied.Reason).To(gomega.Equal(app.PolicyDeniedReason))
+	g.Expect(meta.FindStatusCondition(application.Status.Conditions, app.ErrorCondition).Reason).To(gomega.Equal(app.ReconcileErrorReason))
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring(app.ReadAccessDenied))
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring(app.ModuleNotFound))
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring("connection refused"))
+
+	// conditions of unknown types are dropped
+	application.Status.Conditions = append(application.Status.Conditions, metav1.Condition{Type: "Failure", Status: metav1.ConditionTrue})
+	ResetConditions(application)
+	g.Expect(meta.FindStatusCondition(application.Status.Conditions, "Failure")).To(gomega.BeNil())
+	g.Expect(application.Status.Conditions).To(gomega.HaveLen(len(conditionTypes)))
+}
+
+// TestConditionMessages checks that an error reported twice is not repeated in the message of the condition,
+// and that the message is cut to the maximum length accepted by the API server
+func TestConditionMessages(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	SetCondition(application, "s3/allow-dataset", "connection refused", false)
+	message := meta.FindStatusCondition(application.Status.Conditions, app.ErrorCondition).Message
+	SetCondition(application, "s3/allow-dataset", "connection refused", false)
+	g.Expect(meta.FindStatusCondition(application.Status.Conditions, app.ErrorCondition).Message).To(gomega.Equal(message))
+
+	for i := 0; i < 1000; i++ {
+		SetCondition(application, fmt.Sprintf("s3/dataset-%d", i), "connection refused", false)
+	}
+	message = meta.FindStatusCondition(application.Status.Conditions, app.ErrorCondition).Message
+	g.Expect(len(message)).To(gomega.BeNumerically("<=", maxConditionMessageLength))
+	g.Expect(message).To(gomega.HavePrefix("An error was received for asset s3/allow-dataset"))
+	g.Expect(message).To(gomega.HaveSuffix(truncatedSuffix))
+}
@@ -0,0 +1,18 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+)
+
+// IsCopyExpired returns true if the copy of the dataset has been deleted once its TTL has expired
+func IsCopyExpired(application *app.M4DApplication, datasetID string) bool {
+	for _, expired := range application.Status.ExpiredCopies {
+		if expired == datasetID {
+			return true
+		}
+	}
+	return false
+}
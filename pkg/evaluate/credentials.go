@@ -0,0 +1,37 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"fmt"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/vault"
+)
+
+// setCredentials sets how the modules retrieve the credentials of a data store, held at the given Vault path,
+// according to the secret backend of the deployment. Without Vault, only the credentials stored in Kubernetes secrets,
+// whose paths are the ones of the Vault plugin for reading Kubernetes secrets, can be retrieved.
+func setCredentials(dataStore *app.DataStore, vaultSecretPath string) error {
+	if vault.GetSecretBackend() != vault.KubernetesBackend {
+		dataStore.Vault = app.Vault{
+			SecretPath: vaultSecretPath,
+			Role:       utils.GetModulesRole(),
+			Address:    utils.GetVaultAddress(),
+		}
+		return nil
+	}
+	dataStore.CredentialsType = app.KubernetesCredentials
+	if vaultSecretPath == "" {
+		// the data store does not require credentials
+		return nil
+	}
+	ref, ok := vault.ParseKubeSecretPath(vaultSecretPath)
+	if !ok {
+		return fmt.Errorf("the credentials at %s can not be retrieved without Vault", vaultSecretPath)
+	}
+	dataStore.KubernetesSecret = &app.KubernetesSecretRef{Name: ref.Name, Namespace: ref.Namespace}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"os"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/vault"
+	"github.com/onsi/gomega"
+)
+
+// TestSetCredentials modifies the environment and thus does not run in parallel.
+func TestSetCredentials(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	defer os.Unsetenv(vault.SecretBackendKey)
+	secretPath := vault.PathForReadingKubeSecret("m4d-system", "creds-secret-name")
+
+	dataStore := &app.DataStore{}
+	g.Expect(setCredentials(dataStore, secretPath)).To(gomega.Succeed())
+	g.Expect(dataStore.Vault.SecretPath).To(gomega.Equal(secretPath))
+	g.Expect(dataStore.CredentialsType).To(gomega.BeEmpty())
+	g.Expect(dataStore.KubernetesSecret).To(gomega.BeNil())
+
+	g.Expect(os.Setenv(vault.SecretBackendKey, string(vault.KubernetesBackend))).To(gomega.Succeed())
+	dataStore = &app.DataStore{}
+	g.Expect(setCredentials(dataStore, secretPath)).To(gomega.Succeed())
+	g.Expect(dataStore.Vault.SecretPath).To(gomega.BeEmpty())
+	g.Expect(dataStore.CredentialsType).To(gomega.Equal(app.KubernetesCredentials))
+	g.Expect(dataStore.KubernetesSecret).To(gomega.Equal(&app.KubernetesSecretRef{Name: "creds-secret-name", Namespace: "m4d-system"}))
+
+	// credentials that are not held in Kubernetes secrets can not be retrieved without Vault
+	g.Expect(setCredentials(&app.DataStore{}, "/v1/m4d/test/123")).NotTo(gomega.Succeed())
+}
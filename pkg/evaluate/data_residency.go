@@ -0,0 +1,95 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+)
+
+// setDatasetGeography sets the geography in which the modules serving the dataset run: the geography required
+// for the dataset if it is set, and the geography of the workload otherwise
+func (m *ModuleManager) setDatasetGeography(item modules.DataInfo, appContext *app.M4DApplication) error {
+	var err error
+	if m.WorkloadGeography, err = m.GetProcessingGeography(appContext); err != nil {
+		return err
+	}
+	required := item.Context.Requirements.Geography
+	if required == "" {
+		return nil
+	}
+	key := statusKey(item.Context.DataSetID, item.Context.Flow)
+	err, checked := m.residencyChecks[key]
+	if !checked {
+		err = m.checkDataResidency(item, appContext)
+		if m.residencyChecks == nil {
+			m.residencyChecks = make(map[string]error)
+		}
+		m.residencyChecks[key] = err
+	}
+	if err != nil {
+		return err
+	}
+	m.WorkloadGeography = required
+	return nil
+}
+
+// checkDataResidency checks that the dataset may be processed in its required geography, i.e. that a cluster runs
+// in the geography and that the governance policies allow the operation on the dataset there.
+// A denial reports the geographies of the clusters that the policies allow, if any. A geography in which no cluster
+// runs is an invalid request of the user, not an invalid configuration of the clusters.
+func (m *ModuleManager) checkDataResidency(item modules.DataInfo, appContext *app.M4DApplication) error {
+	datasetID := item.Context.DataSetID
+	required := item.Context.Requirements.Geography
+	regions := clusterRegions(m.Clusters)
+	if !includesGeography(regions, required) {
+		return errors.New("No clusters have been found in the geography " + required + " required for " + datasetID +
+			". Geographies of the clusters: " + strings.Join(regions, ", ") + ".")
+	}
+	// datasets that are copied without a workload are written to the geography of the copy
+	operationType := pb.AccessOperation_READ
+	if item.Context.Flow == app.Write || appContext.Spec.Selector.WorkloadSelector.Size() == 0 {
+		operationType = pb.AccessOperation_WRITE
+	}
+	_, err := m.lookupPolicyDecisions(datasetID, appContext, &pb.AccessOperation{Type: operationType, Destination: required})
+	if err == nil || !isPolicyDenied(err) {
+		// other errors, e.g. the unavailability of the policy manager, are handled when the modules are selected
+		return nil
+	}
+	allowed := []string{}
+	for _, region := range regions {
+		if region == required {
+			continue
+		}
+		operation := &pb.AccessOperation{Type: operationType, Destination: region}
+		if _, err := LookupPolicyDecisions(datasetID, m.PolicyManager, appContext, operation); err == nil {
+			allowed = append(allowed, region)
+		}
+	}
+	msg := app.GeographyNotAllowed + " Required geography: " + required + "."
+	if len(allowed) == 0 {
+		msg += " The policies allow none of the geographies of the clusters."
+	} else {
+		msg += " Geographies allowed by the policies: " + strings.Join(allowed, ", ") + "."
+	}
+	return errors.New(msg)
+}
+
+// clusterRegions returns the distinct regions of the clusters, in sorted order
+func clusterRegions(clusters []multicluster.Cluster) []string {
+	regions := []string{}
+	for _, cluster := range clusters {
+		if region := cluster.Metadata.Region; region != "" && !includesGeography(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
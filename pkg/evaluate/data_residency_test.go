@@ -0,0 +1,42 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	"github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// TestDataResidencyCheckedOnce checks that the required geography of a dataset is checked once, although both the
+// interface negotiation and the module selection set the geography in which the dataset is processed
+func TestDataResidencyCheckedOnce(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	clusters, err := (&mockup.ClusterLister{}).GetClusters()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	policyManager := &countingPolicyManager{}
+	m := &ModuleManager{Log: ctrl.Log.WithName("test"), Clusters: clusters, PolicyManager: policyManager}
+
+	item := modules.DataInfo{Context: &app.DataContext{DataSetID: "s3/allow-dataset",
+		Requirements: app.DataRequirements{Geography: "neverland"}}}
+	g.Expect(m.setDatasetGeography(item, application)).To(gomega.Succeed())
+	g.Expect(m.WorkloadGeography).To(gomega.Equal("neverland"))
+	g.Expect(policyManager.calls).To(gomega.Equal(1))
+	g.Expect(m.setDatasetGeography(item, application)).To(gomega.Succeed())
+	g.Expect(m.fork().setDatasetGeography(item, application)).To(gomega.Succeed())
+	g.Expect(policyManager.calls).To(gomega.Equal(1))
+
+	// the outcome of a failed check is kept as well
+	item.Context = &app.DataContext{DataSetID: "s3/redact-dataset", Requirements: app.DataRequirements{Geography: "mordor"}}
+	g.Expect(m.setDatasetGeography(item, application)).NotTo(gomega.Succeed())
+	g.Expect(m.setDatasetGeography(item, application)).To(gomega.MatchError(gomega.ContainSubstring("mordor")))
+}
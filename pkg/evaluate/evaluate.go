@@ -0,0 +1,468 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package evaluate looks up the datasets of M4DApplications in the data catalog, evaluates the governance policies
+// on them and selects the modules serving them. The M4DApplication controller deploys the resulting blueprints.
+package evaluate
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	"github.com/mesh-for-data/mesh-for-data/pkg/vault"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Evaluator computes the data plane required by a M4DApplication: it looks up the datasets in the data catalog,
+// evaluates the governance policies and selects the modules and the clusters they run in.
+// Evaluator is used by the M4DApplication controller, and can be embedded by other tools (CI checks, portals)
+// to compute what the manager would do for a hypothetical application.
+// Evaluator reads modules and storage accounts from the cluster, and writes nothing unless a Provision implementation
+// that allocates storage is provided.
+type Evaluator struct {
+	Client         client.Client
+	Log            logr.Logger
+	PolicyManager  connectors.PolicyManager
+	DataCatalog    connectors.DataCatalog
+	ClusterManager multicluster.ClusterLister
+	// Provision allocates storage for implicit copies. An in-memory implementation is used if not set.
+	Provision storage.ProvisionInterface
+	// GovernedCopyMaxSize is the size in bytes up to which datasets that have to be transformed on read are copied
+	// with the transformations applied. Zero disables such copies.
+	GovernedCopyMaxSize int64
+	// ActionTaxonomy defines the actions that may be returned by the policy manager and declared by modules.
+	// The actions are not restricted if not set.
+	ActionTaxonomy taxonomy.Actions
+	// PrivacyLevels defines the target privacy levels that may be requested for the datasets, and the actions achieving them.
+	// Privacy levels can not be requested if not set.
+	PrivacyLevels taxonomy.PrivacyLevels
+	// Events exports the decisions of the policy manager, if set
+	Events events.Emitter
+	// Recorder records the Kubernetes events of the application, e.g. the denials of the policy manager, if set
+	Recorder record.EventRecorder
+	// Selections caches the modules selected for the datasets whose inputs are unchanged, if set
+	Selections *SelectionCache
+	// Concurrency is the maximal number of datasets whose catalog lookups, policy decisions and module selections are
+	// processed concurrently. The datasets are processed sequentially if it is lower than 2.
+	Concurrency int
+	// ModuleVersions maps the repositories of the charts of the modules to the semantic version ranges that may be
+	// selected, as configured by the administrators. The ranges of an application take precedence for its modules.
+	ModuleVersions map[string]string
+	// Sidecars are the governance agents defined by the administrators, by their names, injected in the pods of
+	// the modules requiring them
+	Sidecars map[string]app.Sidecar
+	// Scheduling is the scheduling of the pods of the modules set by the administrators per capability, overriding
+	// the scheduling set by the modules
+	Scheduling map[app.ModuleFlow]app.SchedulingPolicy
+	// OwnResources records the resources about to be created for the application in its status, such that they are
+	// deleted even if the manager restarts before the status is updated. The resources are only listed if not set.
+	OwnResources func(application *app.M4DApplication, resources ...app.OwnedResource) error
+}
+
+// Evaluation is the outcome of evaluating a M4DApplication
+type Evaluation struct {
+	// Modules are the installed modules mapped by their name
+	Modules map[string]*app.M4DModule
+	// Instances are the selected module instances
+	Instances []modules.ModuleInstanceSpec
+	// ProvisionedStorage maps a dataset to the storage allocated for its copy
+	ProvisionedStorage map[string]NewAssetInfo
+	// Blueprints are the generated blueprint specifications mapped by the cluster name
+	Blueprints map[string]app.BlueprintSpec
+	// CatalogHashes map a dataset to a hash of its metadata in the data catalog
+	CatalogHashes map[string]string
+	// PolicyManagerUnavailable is set if the governance policies of some of the datasets could not be evaluated
+	// since the policy manager is unavailable
+	PolicyManagerUnavailable bool
+}
+
+// NewEvaluator creates an Evaluator that does not provision storage
+func NewEvaluator(cl client.Client, policyManager connectors.PolicyManager, catalog connectors.DataCatalog, cm multicluster.ClusterLister) *Evaluator {
+	return &Evaluator{
+		Client:         cl,
+		Log:            ctrl.Log.WithName("evaluator"),
+		PolicyManager:  policyManager,
+		DataCatalog:    catalog,
+		ClusterManager: cm,
+		Provision:      storage.NewProvisionTest(),
+	}
+}
+
+// Evaluate computes the module instances and blueprints for the given application.
+// Errors concerning a specific dataset (e.g. access denied, no module found) are recorded as conditions
+// in the application status, in which case the returned evaluation is incomplete.
+// An error is returned when the evaluation could not be completed, e.g. due to connector failures.
+func (e *Evaluator) Evaluate(application *app.M4DApplication) (*Evaluation, error) {
+	evaluation := &Evaluation{ProvisionedStorage: make(map[string]NewAssetInfo), CatalogHashes: make(map[string]string)}
+	clusters, err := e.ClusterManager.GetClusters()
+	if err != nil {
+		return evaluation, err
+	}
+	// datasets whose access has been revoked are excluded from the data plane
+	revoked, err := e.GetRevokedDatasets(application)
+	if err != nil {
+		return evaluation, err
+	}
+	SetRevokedDatasets(application, revoked)
+	// create a list of requirements for creating a data flow (actions, interface to app, data format) per a single data set
+	var lookups []modules.DataInfo
+	for i, dataset := range application.Spec.Data {
+		// a dataset listed several times is processed once, provided that its requirements are the same
+		if first := application.FirstOccurrence(i); first != i {
+			if !application.HasSameRequirements(i, first) {
+				SetCondition(application, dataset.DataSetID, app.ConflictingRequirements, true)
+			}
+			continue
+		}
+		if _, isRevoked := revoked[dataset.DataSetID]; isRevoked {
+			e.Log.V(0).Info("Access to the dataset has been revoked", "dataset", dataset.DataSetID)
+			continue
+		}
+		lookups = append(lookups, modules.DataInfo{
+			Context: dataset.DeepCopy(),
+		})
+	}
+	// the datasets are looked up in the data catalog concurrently, and processed in the order of the application spec
+	lookupErrors := make([]error, len(lookups))
+	var lookupGroup errgroup.Group
+	slots := newSemaphore(e.Concurrency)
+	for i := range lookups {
+		i := i
+		_ = slots.Acquire(context.Background(), 1)
+		lookupGroup.Go(func() error {
+			defer slots.Release(1)
+			lookupErrors[i] = e.ConstructDataInfo(&lookups[i], application)
+			return nil
+		})
+	}
+	_ = lookupGroup.Wait()
+	var catalogErrors []error
+	for i, err := range lookupErrors {
+		if err != nil {
+			utils.RecordEvent(e.Recorder, application, corev1.EventTypeWarning, CatalogLookupFailedReason,
+				"Could not get the details of dataset %s from the data catalog: %s", lookups[i].Context.DataSetID, err.Error())
+			catalogErrors = append(catalogErrors, err)
+		}
+	}
+	if len(catalogErrors) > 0 {
+		return evaluation, errors.Combine(catalogErrors...)
+	}
+	var requirements []modules.DataInfo
+	for _, req := range lookups {
+		dataset := req.Context
+		hash, err := CatalogHash(req.DataDetails)
+		if err != nil {
+			return evaluation, err
+		}
+		evaluation.CatalogHashes[dataset.DataSetID] = hash
+		if len(dataset.Flows) == 0 && dataset.Requirements.WriteInterface == nil {
+			requirements = append(requirements, req)
+			continue
+		}
+		// a dataset that is both read and written is evaluated and served separately in each of its flows,
+		// each of them in the interface in which the data user accesses the dataset in the flow
+		for _, flow := range dataset.GetFlows() {
+			flowReq := req
+			flowReq.Context = req.Context.DeepCopy()
+			flowReq.Context.Flow = flow
+			flowReq.Context.Flows = nil
+			if flow == app.Write && dataset.Requirements.WriteInterface != nil {
+				flowReq.Context.Requirements.Interface = *dataset.Requirements.WriteInterface
+				flowReq.Context.Requirements.FallbackInterfaces = nil
+			}
+			flowReq.Context.Requirements.WriteInterface = nil
+			requirements = append(requirements, flowReq)
+		}
+	}
+	// check for errors
+	if HasError(application) {
+		return evaluation, nil
+	}
+
+	// create a module manager that will select modules to be orchestrated based on user requirements and module capabilities
+	if evaluation.Modules, err = e.GetAllModules(); err != nil {
+		return evaluation, err
+	}
+	provision := e.Provision
+	if provision == nil {
+		provision = storage.NewProvisionTest()
+	}
+	policyManager := e.PolicyManager
+	if e.Concurrency > 1 {
+		policyManager = newMemoPolicyManager(policyManager)
+	}
+	moduleManager := &ModuleManager{
+		Client:              e.Client,
+		Log:                 e.Log,
+		Modules:             modulesWithKnownActions(evaluation.Modules, e.ActionTaxonomy, e.Log),
+		Clusters:            clusters,
+		Owner:               client.ObjectKeyFromObject(application),
+		PolicyManager:       policyManager,
+		Provision:           provision,
+		ProvisionedStorage:  evaluation.ProvisionedStorage,
+		PromotedCopies:      application.Status.PromotedCopies,
+		GovernedCopyMaxSize: e.GovernedCopyMaxSize,
+		ActionTaxonomy:      e.ActionTaxonomy,
+		PrivacyLevels:       e.PrivacyLevels,
+		Events:              e.Events,
+		Recorder:            e.Recorder,
+		ModuleVersions:      moduleVersions(e.ModuleVersions, application),
+	}
+	defer func() { evaluation.PolicyManagerUnavailable = moduleManager.policyManagerUnavailable }()
+	// the actions applied to the datasets and the denied operations are recorded while selecting the modules
+	application.Status.AssetStates = nil
+	// the target privacy levels are translated into the actions achieving them before the policy decisions are looked up
+	moduleManager.translatePrivacyLevels(requirements, application)
+	// the policy decisions are prefetched concurrently, to be reused when the interfaces are negotiated
+	moduleManager.prefetchPolicyDecisions(requirements, application, e.Concurrency)
+	// datasets accessed in place do not require any module
+	direct := make(map[string]app.DirectAccessDetails)
+	var orchestrated []modules.DataInfo
+	for _, item := range requirements {
+		if !item.Context.Requirements.InPlace {
+			orchestrated = append(orchestrated, item)
+			continue
+		}
+		details, err := moduleManager.AccessInPlace(item, application)
+		if err != nil {
+			SetCondition(application, item.Context.DataSetID, err.Error(), true)
+			continue
+		}
+		direct[statusKey(item.Context.DataSetID, item.Context.Flow)] = *details
+	}
+	requirements = orchestrated
+	application.Status.DirectAccess = nil
+	if len(direct) > 0 {
+		application.Status.DirectAccess = direct
+	}
+	// negotiate the interfaces that have not been specified by the data user
+	negotiated := make(map[string]app.InterfaceDetails)
+	for i := range requirements {
+		item := &requirements[i]
+		if isInterfaceSpecified(&item.Context.Requirements) {
+			continue
+		}
+		inter, err := moduleManager.NegotiateInterface(*item, application)
+		if err != nil {
+			SetCondition(application, item.Context.DataSetID, err.Error(), true)
+			continue
+		}
+		item.Context.Requirements.Interface = *inter
+		negotiated[statusKey(item.Context.DataSetID, item.Context.Flow)] = *inter
+	}
+	application.Status.NegotiatedInterfaces = nil
+	if len(negotiated) > 0 {
+		application.Status.NegotiatedInterfaces = negotiated
+	}
+	if HasError(application) {
+		return evaluation, nil
+	}
+	instances := make([]modules.ModuleInstanceSpec, 0)
+	application.Status.MismatchedPerformanceClasses = nil
+	application.Status.DebugInfo = nil
+	application.Status.GrantedCopies = nil
+	owner := client.ObjectKeyFromObject(application)
+	version := modulesVersion(evaluation.Modules, moduleManager.ModuleVersions)
+	// the selection is reused if the inputs of the dataset are unchanged since the previous evaluation
+	digests := make([]string, len(requirements))
+	reused := make([]*cachedSelection, len(requirements))
+	for i := range requirements {
+		item := &requirements[i]
+		if e.Selections != nil {
+			if digests[i], err = selectionDigest(application, item, evaluation.CatalogHashes[item.Context.DataSetID], version, clusters); err != nil {
+				return evaluation, err
+			}
+		}
+		if cached, found := e.Selections.get(owner, item.Context.DataSetID, item.Context.Flow, digests[i]); found {
+			reused[i] = cached
+		}
+	}
+	// the other selections are made concurrently, and their outcomes are recorded in the order of the datasets
+	selections := moduleManager.selectModuleInstances(requirements, reused, application, e.Concurrency)
+	for i, item := range requirements {
+		datasetID := item.Context.DataSetID
+		// the status of each flow of a dataset that is both read and written is recorded separately
+		key := statusKey(datasetID, item.Context.Flow)
+		if cached := reused[i]; cached != nil {
+			e.Log.V(1).Info("Reusing the modules selected for the unchanged dataset " + datasetID)
+			instancesPerDataset := copyInstances(cached.instances)
+			recordAppliedActions(application, datasetID, instancesPerDataset)
+			if cached.classMismatch != "" {
+				if application.Status.MismatchedPerformanceClasses == nil {
+					application.Status.MismatchedPerformanceClasses = make(map[string]string)
+				}
+				application.Status.MismatchedPerformanceClasses[key] = cached.classMismatch
+			}
+			if cached.fallback != nil {
+				if application.Status.NegotiatedInterfaces == nil {
+					application.Status.NegotiatedInterfaces = make(map[string]app.InterfaceDetails)
+				}
+				application.Status.NegotiatedInterfaces[key] = *cached.fallback
+			}
+			instances = append(instances, instancesPerDataset...)
+			continue
+		}
+		selection := selections[i]
+		moduleManager.join(selection, application)
+		instancesPerDataset, fallback, err := selection.instances, selection.fallback, selection.err
+		selectionFailures.set(owner, key, err != nil)
+		if err != nil {
+			SetCondition(application, item.Context.DataSetID, err.Error(), true)
+		} else {
+			recordAppliedActions(application, item.Context.DataSetID, instancesPerDataset)
+			if len(instancesPerDataset) > 0 {
+				utils.RecordEvent(e.Recorder, application, corev1.EventTypeNormal, ModuleSelectedReason,
+					"Selected modules %s for dataset %s", instanceModules(instancesPerDataset), item.Context.DataSetID)
+			}
+		}
+		if fallback != nil {
+			if application.Status.NegotiatedInterfaces == nil {
+				application.Status.NegotiatedInterfaces = make(map[string]app.InterfaceDetails)
+			}
+			application.Status.NegotiatedInterfaces[key] = *fallback
+		}
+		// selections involving copies or the policy manager unavailability are not reused, since they have side effects
+		// on the storage and the status of the application
+		_, copied := moduleManager.ProvisionedStorage[key]
+		_, granted := application.Status.GrantedCopies[key]
+		if err == nil && len(instancesPerDataset) > 0 && !copied && !granted && !moduleManager.policyManagerUnavailable {
+			e.Selections.put(owner, datasetID, item.Context.Flow, &cachedSelection{
+				digest:        digests[i],
+				instances:     copyInstances(instancesPerDataset),
+				fallback:      fallback.DeepCopy(),
+				classMismatch: application.Status.MismatchedPerformanceClasses[key],
+			})
+		}
+		instances = append(instances, instancesPerDataset...)
+	}
+	// the storage of all the copies is provisioned at once, once the Dataset resources are recorded as owned
+	if pending := moduleManager.pendingResources(); len(pending) > 0 {
+		if e.OwnResources == nil {
+			AddOwnedResources(application, pending...)
+		} else if err := e.OwnResources(application, pending...); err != nil {
+			return evaluation, err
+		}
+	}
+	for datasetID, err := range moduleManager.ProvisionStorage() {
+		SetCondition(application, datasetID, err.Error(), true)
+		delete(evaluation.ProvisionedStorage, datasetID)
+	}
+	// the modules upgraded with the Manual policy keep the chart deployed for the application until the upgrade is approved
+	migrateModules(application, instances)
+	evaluation.Instances = instances
+	// the values passed to the selected modules must follow a contract supported by their charts,
+	// and include the governance sidecars that the modules require
+	for i := range instances {
+		if err := checkValuesContract(&instances[i]); err != nil {
+			SetCondition(application, instances[i].AssetID, err.Error(), true)
+		} else if err := checkSidecars(&instances[i], e.Sidecars); err != nil {
+			SetCondition(application, instances[i].AssetID, err.Error(), true)
+		}
+	}
+	// check for errors
+	if HasError(application) {
+		return evaluation, nil
+	}
+	// generate blueprint specifications (per cluster)
+	evaluation.Blueprints = e.GenerateBlueprints(instances, application, clusters)
+	return evaluation, nil
+}
+
+// statusKey returns the key of the status maps recording the outcome of a flow of a dataset.
+// The dataset identifier is used for the read flow, and suffixed with the flow name for the write flow,
+// so that the flows of a dataset that is both read and written do not overwrite each other's status.
+func statusKey(datasetID string, flow app.ModuleFlow) string {
+	if flow != app.Write {
+		return datasetID
+	}
+	return datasetID + "#" + string(flow)
+}
+
+// ConstructDataInfo fills in the dataset details received from the data catalog
+func (e *Evaluator) ConstructDataInfo(req *modules.DataInfo, input *app.M4DApplication) error {
+	var err error
+
+	// Call the DataCatalog service to get info about the dataset
+	var response *pb.CatalogDatasetInfo
+	var credentialPath string
+	if input.Spec.SecretRef != "" {
+		credentialPath = utils.GetVaultAddress() + vault.PathForReadingKubeSecret(input.Namespace, input.Spec.SecretRef)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	response, err = e.DataCatalog.GetDatasetInfo(ctx, &pb.CatalogDatasetRequest{
+		CredentialPath: credentialPath,
+		DatasetId:      req.Context.DataSetID,
+	})
+	ObserveConnectorLatency(CatalogConnector, "GetDatasetInfo", start)
+	if err != nil {
+		return err
+	}
+
+	details := response.GetDetails()
+	dataDetails, err := modules.CatalogDatasetToDataDetails(response)
+	if err != nil {
+		return err
+	}
+	req.DataDetails = dataDetails
+	req.VaultSecretPath = ""
+	if details.CredentialsInfo != nil {
+		req.VaultSecretPath = details.CredentialsInfo.VaultSecretPath
+	}
+
+	return nil
+}
+
+// GetRevokedDatasets returns the datasets of the application whose access has been revoked, mapped to the revocation reason
+func (e *Evaluator) GetRevokedDatasets(application *app.M4DApplication) (map[string]string, error) {
+	var revocationList app.M4DDatasetRevocationList
+	if err := e.Client.List(context.Background(), &revocationList, client.InNamespace(utils.GetSystemNamespace())); err != nil {
+		e.Log.V(0).Info("Error while listing dataset revocations: " + err.Error())
+		return nil, err
+	}
+	revoked := make(map[string]string)
+	for _, revocation := range revocationList.Items {
+		for _, dataset := range application.Spec.Data {
+			if dataset.DataSetID == revocation.Spec.DataSetID {
+				revoked[dataset.DataSetID] = revocation.Spec.Reason
+			}
+		}
+	}
+	return revoked, nil
+}
+
+// GetAllModules returns all CRDs of the kind M4DModule mapped by their name
+func (e *Evaluator) GetAllModules() (map[string]*app.M4DModule, error) {
+	ctx := context.Background()
+
+	moduleMap := make(map[string]*app.M4DModule)
+	var moduleList app.M4DModuleList
+	if err := e.Client.List(ctx, &moduleList, client.InNamespace(utils.GetSystemNamespace())); err != nil {
+		e.Log.V(0).Info("Error while listing modules: " + err.Error())
+		return moduleMap, err
+	}
+	e.Log.Info("Listing all modules")
+	for _, module := range moduleList.Items {
+		e.Log.Info(module.GetName())
+		moduleMap[module.Name] = module.DeepCopy()
+	}
+	return moduleMap, nil
+}
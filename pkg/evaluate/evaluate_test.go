@@ -0,0 +1,701 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+)
+
+// Read utility
+func readObjectFromFile(f string, obj interface{}) error {
+	bytes, err := ioutil.ReadFile(f)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(bytes, obj)
+}
+
+// This test checks that an application is evaluated without creating resources in the cluster
+// Two datasets, one of them requires a copy
+// Result: blueprint specs are computed, no plotter and no storage are created
+func TestEvaluateWithoutClusterWrites(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID:    "s3/allow-dataset",
+			Requirements: app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+		},
+		{
+			DataSetID:    "db2/redact-dataset",
+			Requirements: app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+		},
+	}
+
+	// Register operator types with the runtime scheme.
+	s := utils.NewScheme(g)
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClientWithScheme(s)
+
+	// Read and copy modules
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+	copyModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/copy-db2-parquet.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), copyModule)).NotTo(gomega.HaveOccurred(), "the copy module could not be created")
+	// Create storage account
+	dummySecret := &corev1.Secret{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/credentials-theshire.yaml", dummySecret)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.Background(), dummySecret)).NotTo(gomega.HaveOccurred())
+	account := &app.M4DStorageAccount{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/account-theshire.yaml", account)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.Background(), account)).NotTo(gomega.HaveOccurred())
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(evaluation.ProvisionedStorage).To(gomega.HaveKey("db2/redact-dataset"))
+	g.Expect(evaluation.Blueprints).To(gomega.HaveLen(1))
+	g.Expect(evaluation.Blueprints["thegreendragon"].Flow.Steps).NotTo(gomega.BeEmpty())
+
+	// nothing has been written to the cluster
+	plotters := &app.PlotterList{}
+	g.Expect(cl.List(context.Background(), plotters)).NotTo(gomega.HaveOccurred())
+	g.Expect(plotters.Items).To(gomega.BeEmpty())
+}
+
+// This test checks that a revoked dataset is excluded from the data plane
+// Two datasets, access to the one requiring a copy is revoked
+// Result: the Revoked condition is set, no storage is allocated and no error is reported
+func TestEvaluateWithRevokedDataset(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID:    "s3/allow-dataset",
+			Requirements: app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+		},
+		{
+			DataSetID:    "db2/redact-dataset",
+			Requirements: app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+		},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+	revocation := &app.M4DDatasetRevocation{
+		ObjectMeta: metav1.ObjectMeta{Name: "revoke-redact-dataset", Namespace: utils.GetSystemNamespace()},
+		Spec:       app.M4DDatasetRevocationSpec{DataSetID: "db2/redact-dataset", Reason: "data breach"},
+	}
+	g.Expect(cl.Create(context.TODO(), revocation)).NotTo(gomega.HaveOccurred())
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(application.Status.RevokedDatasets).To(gomega.HaveKeyWithValue("db2/redact-dataset", "data breach"))
+	g.Expect(meta.IsStatusConditionTrue(application.Status.Conditions, app.RevokedCondition)).To(gomega.BeTrue())
+	g.Expect(evaluation.ProvisionedStorage).To(gomega.BeEmpty())
+	g.Expect(evaluation.Blueprints).To(gomega.HaveLen(1))
+}
+
+// This test checks that an interface is negotiated for a dataset whose requirements do not specify one
+// A single dataset stored in s3 as parquet, a read module exposing arrow-flight
+// Result: the interface of the read module is negotiated and recorded in the status
+func TestEvaluateNegotiatesInterface(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID:    "s3/allow-dataset",
+			Requirements: app.DataRequirements{},
+		},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(application.Status.NegotiatedInterfaces).To(gomega.HaveKeyWithValue("s3/allow-dataset",
+		app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}))
+	// the spec is left unchanged
+	g.Expect(application.Spec.Data[0].Requirements.Interface).To(gomega.Equal(app.InterfaceDetails{}))
+	g.Expect(evaluation.Blueprints).To(gomega.HaveLen(1))
+}
+
+// This test checks that the fallback interfaces of a dataset are tried in order if the requested interface can not be served
+// A single dataset stored in s3 as parquet, a read module exposing arrow-flight
+// Result: the second fallback interface is satisfied and recorded in the status
+func TestEvaluateFallbackInterfaces(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	arrow := app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID: "s3/allow-dataset",
+			Requirements: app.DataRequirements{
+				Interface:          app.InterfaceDetails{Protocol: app.Kafka, DataFormat: "json"},
+				FallbackInterfaces: []app.InterfaceDetails{{Protocol: app.S3, DataFormat: "csv"}, arrow},
+			},
+		},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(application.Status.NegotiatedInterfaces).To(gomega.HaveKeyWithValue("s3/allow-dataset", arrow))
+	g.Expect(RequestedInterface(application, "s3/allow-dataset", app.Read)).To(gomega.Equal(&arrow))
+	g.Expect(evaluation.Blueprints).To(gomega.HaveLen(1))
+	// the modules rejected for the interfaces that are not satisfied are not reported
+	g.Expect(application.Status.DebugInfo).NotTo(gomega.HaveKey("s3/allow-dataset"))
+}
+
+// This test checks that the performance class mismatch recorded by a failed selection of modules is rolled back,
+// such that the interface eventually satisfied does not report the mismatch of another interface
+func TestRollbackClassMismatch(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{Status: app.M4DApplicationStatus{
+		MismatchedPerformanceClasses: map[string]string{"s3/other-dataset": "other mismatch"}}}
+	rollback := recordedClassMismatch(application, "s3/allow-dataset")
+	application.Status.MismatchedPerformanceClasses["s3/allow-dataset"] = "stale mismatch"
+	rollback()
+	g.Expect(application.Status.MismatchedPerformanceClasses).To(gomega.Equal(map[string]string{"s3/other-dataset": "other mismatch"}))
+
+	rollback = recordedClassMismatch(application, "s3/other-dataset")
+	application.Status.MismatchedPerformanceClasses["s3/other-dataset"] = "stale mismatch"
+	rollback()
+	g.Expect(application.Status.MismatchedPerformanceClasses).To(gomega.HaveKeyWithValue("s3/other-dataset", "other mismatch"))
+}
+
+// This test checks that a write module is selected for a dataset written by the workload
+// A single dataset stored in s3 as parquet, a write module exposing arrow-flight and a read module
+// Result: the write module is selected, writing to the location of the dataset
+func TestEvaluateWriteFlow(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID: "s3/allow-dataset",
+			Flow:      app.Write,
+			Requirements: app.DataRequirements{
+				Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow},
+			},
+		},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+	writeModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-write-parquet.yaml", writeModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), writeModule)).NotTo(gomega.HaveOccurred(), "the write module could not be created")
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(evaluation.Instances).To(gomega.HaveLen(1))
+	instance := evaluation.Instances[0]
+	g.Expect(instance.Module.Name).To(gomega.Equal("write-parquet"))
+	g.Expect(instance.Args.Read).To(gomega.BeEmpty())
+	g.Expect(instance.Args.Write).To(gomega.HaveLen(1))
+	g.Expect(instance.Args.Write[0].Destination.Format).To(gomega.Equal(app.Parquet))
+	g.Expect(evaluation.ProvisionedStorage).To(gomega.BeEmpty())
+	g.Expect(evaluation.Blueprints).To(gomega.HaveLen(1))
+}
+
+// This test checks that the modules of both flows are selected for a dataset that is read and written by the workload
+// A single dataset stored in s3 as parquet, a read module and a write module exposing arrow-flight
+// Result: the policies of both flows are evaluated, and the read and write modules are selected
+func TestEvaluateReadWriteFlows(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID: "s3/allow-dataset",
+			Flows:     []app.ModuleFlow{app.Read, app.Write},
+			Requirements: app.DataRequirements{
+				Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow},
+			},
+		},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+	writeModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-write-parquet.yaml", writeModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), writeModule)).NotTo(gomega.HaveOccurred(), "the write module could not be created")
+
+	policyManager := &countingPolicyManager{}
+	evaluator := NewEvaluator(cl, policyManager, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluator.Selections = NewSelectionCache(time.Hour)
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(policyManager.operations).To(gomega.ConsistOf(pb.AccessOperation_READ, pb.AccessOperation_WRITE))
+	g.Expect(evaluation.Instances).To(gomega.HaveLen(2))
+	selected := map[string]*app.ModuleArguments{}
+	for _, instance := range evaluation.Instances {
+		selected[instance.Module.Name] = instance.Args
+	}
+	g.Expect(selected).To(gomega.HaveKey("read-parquet"))
+	g.Expect(selected["read-parquet"].Read).To(gomega.HaveLen(1))
+	g.Expect(selected).To(gomega.HaveKey("write-parquet"))
+	g.Expect(selected["write-parquet"].Write).To(gomega.HaveLen(1))
+	g.Expect(evaluation.Blueprints).To(gomega.HaveLen(1))
+
+	// the selections of both flows are reused
+	calls := policyManager.calls
+	evaluation, err = evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(policyManager.calls).To(gomega.Equal(calls))
+	g.Expect(evaluation.Instances).To(gomega.HaveLen(2))
+}
+
+// This test checks that a dataset read and written by the workload is served in a distinct interface in each flow
+// A single dataset stored in s3 as parquet, read through arrow-flight and written through s3, and write modules exposing both
+// Result: the read module and the write module exposing s3 are selected
+func TestEvaluateWriteInterface(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	arrow := app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	s3 := app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet}
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID: "s3/allow-dataset",
+			Flows:     []app.ModuleFlow{app.Read, app.Write},
+			Requirements: app.DataRequirements{
+				Interface:      arrow,
+				WriteInterface: &s3,
+			},
+		},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+	writeModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-write-parquet.yaml", writeModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), writeModule)).NotTo(gomega.HaveOccurred(), "the write module could not be created")
+	s3WriteModule := writeModule.DeepCopy()
+	s3WriteModule.ResourceVersion = ""
+	s3WriteModule.Name = "write-s3"
+	s3WriteModule.Spec.Capabilities.API.InterfaceDetails = s3
+	g.Expect(cl.Create(context.TODO(), s3WriteModule)).NotTo(gomega.HaveOccurred(), "the write module could not be created")
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(evaluation.Instances).To(gomega.HaveLen(2))
+	selected := map[string]*app.ModuleArguments{}
+	for _, instance := range evaluation.Instances {
+		selected[instance.Module.Name] = instance.Args
+	}
+	g.Expect(selected).To(gomega.HaveKey("read-parquet"))
+	g.Expect(selected).To(gomega.HaveKey("write-s3"))
+	g.Expect(selected["write-s3"].Write).To(gomega.HaveLen(1))
+	g.Expect(selected["write-s3"].Write[0].Destination.Format).To(gomega.Equal(app.Parquet))
+	// the endpoints of the flows are those of the APIs matching their interfaces
+	g.Expect(RequestedInterface(application, "s3/allow-dataset", app.Read)).To(gomega.Equal(&arrow))
+	g.Expect(RequestedInterface(application, "s3/allow-dataset", app.Write)).To(gomega.Equal(&s3))
+}
+
+// This test checks that the status of each flow of a dataset that is both read and written is recorded separately
+// A single dataset stored in s3 as parquet, requested through kafka with s3 and arrow-flight fallbacks, a read module
+// exposing arrow-flight and a write module exposing s3
+// Result: the read flow falls back to arrow-flight and the write flow to s3, and both fallbacks are recorded
+func TestEvaluateReadWriteFallbacks(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	arrow := app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	s3 := app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet}
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID: "s3/allow-dataset",
+			Flows:     []app.ModuleFlow{app.Read, app.Write},
+			Requirements: app.DataRequirements{
+				Interface:          app.InterfaceDetails{Protocol: app.Kafka, DataFormat: "json"},
+				FallbackInterfaces: []app.InterfaceDetails{s3, arrow},
+			},
+		},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+	writeModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-write-parquet.yaml", writeModule)).NotTo(gomega.HaveOccurred())
+	writeModule.Name = "write-s3"
+	writeModule.Spec.Capabilities.API.InterfaceDetails = s3
+	g.Expect(cl.Create(context.TODO(), writeModule)).NotTo(gomega.HaveOccurred(), "the write module could not be created")
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(evaluation.Instances).To(gomega.HaveLen(2))
+	g.Expect(application.Status.NegotiatedInterfaces).To(gomega.HaveKeyWithValue("s3/allow-dataset", arrow))
+	g.Expect(application.Status.NegotiatedInterfaces).To(gomega.HaveKeyWithValue("s3/allow-dataset#write", s3))
+	g.Expect(RequestedInterface(application, "s3/allow-dataset", app.Read)).To(gomega.Equal(&arrow))
+	g.Expect(RequestedInterface(application, "s3/allow-dataset", app.Write)).To(gomega.Equal(&s3))
+}
+
+// This test checks that read modules of the requested performance class are preferred
+// A single dataset requiring an interactive read module, a batch read module is deployed first
+// Result: the batch module is selected and the mismatch is reported, an interactive module is selected once deployed
+func TestEvaluatePerformanceClass(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID: "s3/allow-dataset",
+			Requirements: app.DataRequirements{
+				Interface:        app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow},
+				PerformanceClass: app.InteractivePerformance,
+			},
+		},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+
+	batchModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", batchModule)).NotTo(gomega.HaveOccurred())
+	batchModule.Spec.Capabilities.PerformanceClass = app.BatchPerformance
+	g.Expect(cl.Create(context.TODO(), batchModule)).NotTo(gomega.HaveOccurred(), "the batch module could not be created")
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(evaluation.Instances).To(gomega.HaveLen(1))
+	g.Expect(evaluation.Instances[0].Module.Name).To(gomega.Equal("read-parquet"))
+	g.Expect(application.Status.MismatchedPerformanceClasses).To(gomega.HaveKey("s3/allow-dataset"))
+
+	interactiveModule := batchModule.DeepCopy()
+	interactiveModule.ResourceVersion = ""
+	interactiveModule.Name = "read-parquet-interactive"
+	interactiveModule.Spec.Capabilities.PerformanceClass = app.InteractivePerformance
+	g.Expect(cl.Create(context.TODO(), interactiveModule)).NotTo(gomega.HaveOccurred(), "the interactive module could not be created")
+
+	evaluation, err = evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(evaluation.Instances).To(gomega.HaveLen(1))
+	g.Expect(evaluation.Instances[0].Module.Name).To(gomega.Equal("read-parquet-interactive"))
+	g.Expect(application.Status.MismatchedPerformanceClasses).To(gomega.BeEmpty())
+}
+
+// This test checks that datasets may be accessed in place only by trusted workloads and if no governance action is required
+// Two datasets requested in place by a trusted workload, one of them has to be redacted, then the namespace is no longer trusted
+// Result: the connection of the allowed dataset is reported, the other dataset is rejected, as is any dataset requested by untrusted workloads
+func TestEvaluateAccessInPlace(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{
+			DataSetID:    "s3/allow-dataset",
+			Requirements: app.DataRequirements{InPlace: true},
+		},
+	}
+
+	// the workload runs in the cluster of the manager, in a trusted namespace
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   application.Namespace,
+		Labels: map[string]string{app.TrustedWorkloadsLabel: "true"},
+	}}
+	clusterMetadata := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-metadata", Namespace: utils.GetSystemNamespace()},
+		Data:       map[string]string{"ClusterName": "thegreendragon", "Region": "theshire"},
+	}
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, namespace, clusterMetadata)
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(application.Status.DirectAccess).To(gomega.HaveKey("s3/allow-dataset"))
+	g.Expect(application.Status.DirectAccess["s3/allow-dataset"].Interface.Protocol).To(gomega.Equal(app.S3))
+	// no module is deployed
+	g.Expect(evaluation.Blueprints).To(gomega.BeEmpty())
+
+	application.Spec.Data[0].DataSetID = "s3/redact-dataset"
+	_, err = evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring(app.InPlaceNotAllowed))
+	g.Expect(application.Status.DirectAccess).To(gomega.BeEmpty())
+
+	// the workload is no longer trusted
+	g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(namespace), namespace)).NotTo(gomega.HaveOccurred())
+	delete(namespace.Labels, app.TrustedWorkloadsLabel)
+	g.Expect(cl.Update(context.Background(), namespace)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data[0].DataSetID = "s3/allow-dataset"
+	ResetConditions(application)
+	_, err = evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring(app.InPlaceNotTrusted))
+	g.Expect(application.Status.DirectAccess).To(gomega.BeEmpty())
+
+	// the namespace is trusted, but the workload runs in another cluster
+	namespace.Labels[app.TrustedWorkloadsLabel] = "true"
+	g.Expect(cl.Update(context.Background(), namespace)).NotTo(gomega.HaveOccurred())
+	application.Spec.Selector.ClusterName = "neverland-cluster"
+	ResetConditions(application)
+	_, err = evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring(app.InPlaceNotTrusted))
+	g.Expect(application.Status.DirectAccess).To(gomega.BeEmpty())
+}
+
+// This test checks that a failure of the policy manager is reported in the application status
+// Result: an error condition is set and no blueprint is generated
+func TestEvaluateWithPolicyManagerFailure(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data[0].DataSetID = "s3/allow-dataset"
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+
+	policyManager := &mockup.MockPolicyManager{FailEvery: 1}
+	evaluator := NewEvaluator(cl, policyManager, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring("simulated failure"))
+	g.Expect(evaluation.Blueprints).To(gomega.BeEmpty())
+}
+
+// This test checks that an action required by the policy manager that is not defined by the action taxonomy is rejected
+// Result: an error condition is set and no blueprint is generated
+func TestEvaluateWithUnknownAction(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data[0].DataSetID = "s3/redact-dataset"
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	// the mock policy manager requires redact-ID, which the taxonomy does not define
+	evaluator.ActionTaxonomy = taxonomy.Actions{"removed-ID": {pb.EnforcementAction_COLUMN}}
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring("unknown action id redact-ID from policy manager"))
+	g.Expect(evaluation.Blueprints).To(gomega.BeEmpty())
+}
+
+// This test checks that a dataset listed twice is processed once if its requirements are the same,
+// and that the application is rejected if they differ
+func TestEvaluateWithDuplicateDataset(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	requirements := app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}}
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{
+		{DataSetID: "s3/allow-dataset", Requirements: requirements},
+		{DataSetID: "s3/allow-dataset", Requirements: requirements},
+	}
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(evaluation.Instances).To(gomega.HaveLen(1))
+
+	application.Status = app.M4DApplicationStatus{}
+	application.Spec.Data[1].Requirements.Copy.Required = true
+	evaluation, err = evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring(app.ConflictingRequirements))
+	g.Expect(evaluation.Blueprints).To(gomega.BeEmpty())
+}
+
+// This test checks that the modules serving a dataset run in the geography required for it, which the governance
+// policies have to allow, instead of the workload geography
+// A workload in theshire, datasets required to be processed in neverland, a read module reading s3/parquet
+// Result: the dataset allowed in neverland is read in neverland, the dataset allowed only in theshire is denied
+func TestEvaluateRequiredGeography(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s)
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Create(context.TODO(), readModule)).NotTo(gomega.HaveOccurred(), "the read module could not be created")
+	evaluator := NewEvaluator(cl, &mockup.MockPolicyManager{}, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+
+	newApplication := func(datasetID string, geography string) *app.M4DApplication {
+		application := &app.M4DApplication{}
+		g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+		application.Spec.Data = []app.DataContext{{
+			DataSetID: datasetID,
+			Requirements: app.DataRequirements{
+				Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow},
+				Geography: geography,
+			},
+		}}
+		return application
+	}
+
+	application := newApplication("s3/deny-theshire", "neverland")
+	evaluation, err := evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(HasError(application)).To(gomega.BeFalse(), GetErrorMessages(application))
+	g.Expect(evaluation.Blueprints).To(gomega.HaveLen(1))
+	g.Expect(evaluation.Blueprints).To(gomega.HaveKey("neverland-cluster"))
+
+	application = newApplication("s3/allow-theshire", "neverland")
+	_, err = evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	denied := meta.FindStatusCondition(application.Status.Conditions, app.DeniedCondition)
+	g.Expect(denied).NotTo(gomega.BeNil())
+	g.Expect(denied.Status).To(gomega.Equal(metav1.ConditionTrue))
+	g.Expect(denied.Reason).To(gomega.Equal(app.PolicyDeniedReason))
+	g.Expect(denied.Message).To(gomega.ContainSubstring(app.GeographyNotAllowed))
+	g.Expect(denied.Message).To(gomega.ContainSubstring("Geographies allowed by the policies: theshire"))
+
+	application = newApplication("s3/allow-dataset", "mordor")
+	_, err = evaluator.Evaluate(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(GetErrorMessages(application)).To(gomega.ContainSubstring("No clusters have been found in the geography mordor"))
+	// a geography in which no cluster runs is an invalid request, not an invalid configuration of the clusters
+	denied = meta.FindStatusCondition(application.Status.Conditions, app.DeniedCondition)
+	g.Expect(denied).NotTo(gomega.BeNil())
+	g.Expect(denied.Reason).To(gomega.Equal(app.InvalidRequestReason))
+	g.Expect(denied.Message).NotTo(gomega.ContainSubstring(app.InvalidClusterConfiguration))
+}
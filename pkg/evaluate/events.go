@@ -0,0 +1,68 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"strings"
+	"sync"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// policyDecisions keeps the last exported decisions of the policy manager for each application
+var policyDecisions = &decisionTracker{}
+
+// decisionTracker keeps the last exported decision of the policy manager on each operation of each application,
+// such that a decision is exported when it changes rather than each time the governance policies are evaluated
+type decisionTracker struct {
+	mutex     sync.Mutex
+	decisions map[types.NamespacedName]map[string]string
+}
+
+// changed records the decision of the event and returns whether it differs from the last decision on the same operation
+func (t *decisionTracker) changed(key types.NamespacedName, event *events.Event) bool {
+	operation := strings.Join([]string{event.DatasetID, event.Operation, event.Destination}, "|")
+	decision := strings.Join(event.Actions, ",") + "|" + event.Reason
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if previous, found := t.decisions[key][operation]; found && previous == decision {
+		return false
+	}
+	if t.decisions == nil {
+		t.decisions = make(map[types.NamespacedName]map[string]string)
+	}
+	if t.decisions[key] == nil {
+		t.decisions[key] = make(map[string]string)
+	}
+	t.decisions[key][operation] = decision
+	return true
+}
+
+// remove forgets the decisions of a deleted application
+func (t *decisionTracker) remove(key types.NamespacedName) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.decisions, key)
+}
+
+// EmitEvent exports a lifecycle event of the application if an event sink has been configured
+func EmitEvent(emitter events.Emitter, application *app.M4DApplication, event events.Event) {
+	if emitter == nil {
+		return
+	}
+	event.Application = events.ApplicationReference{Name: application.Name, Namespace: application.Namespace}
+	emitter.Emit(event)
+}
+
+// emitPolicyDecision exports a decision of the policy manager if it differs from the last exported decision on the operation
+func emitPolicyDecision(emitter events.Emitter, application *app.M4DApplication, event events.Event) {
+	if emitter == nil {
+		return
+	}
+	if policyDecisions.changed(types.NamespacedName{Name: application.Name, Namespace: application.Namespace}, &event) {
+		EmitEvent(emitter, application, event)
+	}
+}
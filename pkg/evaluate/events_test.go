@@ -0,0 +1,53 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// recordedEvents keeps the emitted events
+type recordedEvents []events.Event
+
+func (e *recordedEvents) Emit(event events.Event) {
+	*e = append(*e, event)
+}
+
+// This test checks that the decisions of the policy manager are exported when they change,
+// rather than each time the governance policies of the application are evaluated
+func TestEmitPolicyDecision(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	emitted := &recordedEvents{}
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "decisions", Namespace: "default"}}
+	read := events.Event{Type: events.PolicyDecision, DatasetID: "s3/allow-dataset", Operation: "read", Actions: []string{"redact-ID"}}
+	emitPolicyDecision(emitted, application, read)
+	emitPolicyDecision(emitted, application, read)
+	g.Expect(*emitted).To(gomega.HaveLen(1))
+	g.Expect((*emitted)[0].Application.Name).To(gomega.Equal("decisions"))
+
+	// the decisions on the other operations are tracked independently
+	write := events.Event{Type: events.PolicyDecision, DatasetID: "s3/allow-dataset", Operation: "write", Destination: "theshire"}
+	emitPolicyDecision(emitted, application, write)
+	g.Expect(*emitted).To(gomega.HaveLen(2))
+
+	// a changed decision is exported
+	read.Actions = nil
+	read.Reason = app.ReadAccessDenied
+	emitPolicyDecision(emitted, application, read)
+	g.Expect(*emitted).To(gomega.HaveLen(3))
+	g.Expect((*emitted)[2].Reason).To(gomega.Equal(app.ReadAccessDenied))
+
+	// the decisions are exported again once the application is recreated
+	policyDecisions.remove(types.NamespacedName{Name: "decisions", Namespace: "default"})
+	emitPolicyDecision(emitted, application, write)
+	g.Expect(*emitted).To(gomega.HaveLen(4))
+}
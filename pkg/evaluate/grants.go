@@ -0,0 +1,75 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"context"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	modules "github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/mesh-for-data/mesh-for-data/pkg/vault"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// grantedCopy returns the copy of the dataset shared with the namespace of the application by a M4DGrant,
+// which replaces the copy selected for the application, or nil if no such copy is available.
+// A copy is shared only if it is read by the workload, is not registered in the data catalog,
+// requires no transformations, and has the format of the selected copy.
+func (m *ModuleManager) grantedCopy(item modules.DataInfo, appContext *app.M4DApplication, copySelector *modules.Selector) (*app.DataStore, error) {
+	if appContext.Spec.Selector.WorkloadSelector.Size() == 0 || item.Context.Requirements.Copy.Catalog.CatalogID != "" ||
+		len(copySelector.Actions) > 0 || copySelector.Destination.Protocol != app.S3 {
+		return nil, nil
+	}
+	var grantList app.M4DGrantList
+	if err := m.Client.List(context.Background(), &grantList); err != nil {
+		return nil, err
+	}
+	for _, grant := range grantList.Items {
+		if grant.Spec.Grantee != appContext.Namespace || grant.Spec.DataSetID != item.Context.DataSetID {
+			continue
+		}
+		granter := types.NamespacedName{Name: grant.Spec.Application, Namespace: grant.Namespace}
+		// the copy is shared once it has been made
+		details, ready, found := CopyOf(m.Client, granter, item.Context.DataSetID)
+		if !found || !ready {
+			continue
+		}
+		datasetDetails := &pb.DatasetDetails{}
+		if err := details.Details.Into(datasetDetails); err != nil || datasetDetails.DataStore == nil ||
+			datasetDetails.DataStore.Type != pb.DataStore_S3 || datasetDetails.DataFormat != copySelector.Destination.DataFormat {
+			continue
+		}
+		m.Log.Info("Reading the copy of " + item.Context.DataSetID + " granted by " + granter.String())
+		if appContext.Status.GrantedCopies == nil {
+			appContext.Status.GrantedCopies = make(map[string]app.GrantedCopy)
+		}
+		appContext.Status.GrantedCopies[item.Context.DataSetID] = app.GrantedCopy{
+			Application: granter.String(),
+			DatasetRef:  details.DatasetRef,
+		}
+		source := &app.DataStore{
+			Connection: *serde.NewCompactArbitrary(datasetDetails.DataStore, utils.GetCompactEncoding()),
+			Format:     datasetDetails.DataFormat,
+		}
+		if err := setCredentials(source, vault.PathForReadingKubeSecret(utils.GetSystemNamespace(), details.SecretRef)); err != nil {
+			return nil, err
+		}
+		return source, nil
+	}
+	return nil, nil
+}
+
+// CopyOf returns the copy of a dataset made by an application that is not being deleted, and whether the application is ready
+func CopyOf(c client.Client, key types.NamespacedName, datasetID string) (app.DatasetDetails, bool, bool) {
+	granter := &app.M4DApplication{}
+	if err := c.Get(context.Background(), key, granter); err != nil || !granter.DeletionTimestamp.IsZero() {
+		return app.DatasetDetails{}, false, false
+	}
+	details, found := granter.Status.ProvisionedStorage[datasetID]
+	return details, granter.Status.Ready, found
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	local "github.com/mesh-for-data/mesh-for-data/pkg/multicluster/local"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// AccessInPlace checks that the workload may read the dataset at its source, in which case no module is deployed for it.
+// Access in place is allowed only for trusted workloads, i.e. workloads running in the cluster of the manager in a namespace
+// labeled as trusted by the administrators, if the governance policies do not require any action for reading the data
+// in the workload geography, and the requested interface, if specified, is the native interface of the dataset.
+// The policies for writing the data are checked instead for the write flow of the dataset.
+// The returned details include the source connection and the path of the dataset credentials, as received from the data catalog.
+// The egress of the workload is then restricted by a network policy to the sources of the datasets accessed in place.
+func (m *ModuleManager) AccessInPlace(item modules.DataInfo, appContext *app.M4DApplication) (*app.DirectAccessDetails, error) {
+	source := item.DataDetails.Interface
+	if isInterfaceSpecified(&item.Context.Requirements) && item.Context.Requirements.Interface != source {
+		return nil, errors.New("The dataset can not be accessed in place using " + item.Context.Requirements.Interface.Protocol + "/" +
+			item.Context.Requirements.Interface.DataFormat + ", its source interface is " + source.Protocol + "/" + source.DataFormat)
+	}
+	if !m.isTrustedWorkload(appContext) {
+		return nil, errors.New(app.InPlaceNotTrusted)
+	}
+	var err error
+	if m.WorkloadGeography, err = m.GetProcessingGeography(appContext); err != nil {
+		return nil, err
+	}
+	operation := &pb.AccessOperation{Type: pb.AccessOperation_READ, Destination: m.WorkloadGeography}
+	if item.Context.Flow == app.Write {
+		operation.Type = pb.AccessOperation_WRITE
+	}
+	actions, err := m.lookupPolicyDecisions(item.Context.DataSetID, appContext, operation)
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) > 0 {
+		return nil, errors.New(app.InPlaceNotAllowed)
+	}
+	m.Log.Info("The dataset " + item.Context.DataSetID + " is accessed in place")
+	details := &app.DirectAccessDetails{
+		Interface:       source,
+		CredentialsPath: item.VaultSecretPath,
+	}
+	item.DataDetails.Connection.DeepCopyInto(&details.Connection)
+	return details, nil
+}
+
+// isTrustedWorkload returns true if the namespace of the application is labeled as trusted by the administrators,
+// and the workload runs in the cluster of the manager, in which the network policy restricting its egress is created.
+func (m *ModuleManager) isTrustedWorkload(appContext *app.M4DApplication) bool {
+	namespace := &corev1.Namespace{}
+	if err := m.Client.Get(context.Background(), types.NamespacedName{Name: appContext.Namespace}, namespace); err != nil {
+		m.Log.Info("Could not read the namespace of the application: " + err.Error())
+		return false
+	}
+	if namespace.Labels[app.TrustedWorkloadsLabel] != "true" {
+		return false
+	}
+	clusterName := m.workloadClusterName(appContext)
+	if clusterName == "" {
+		return true
+	}
+	clusterManager, err := local.NewManager(m.Client, utils.GetSystemNamespace())
+	if err != nil {
+		return false
+	}
+	clusters, err := clusterManager.GetClusters()
+	if err != nil {
+		m.Log.Info("Could not detect the cluster of the manager: " + err.Error())
+		return false
+	}
+	return len(clusters) == 1 && clusters[0].Name == clusterName
+}
@@ -0,0 +1,178 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"sort"
+
+	"emperror.dev/errors"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/pkg/capabilities"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// isInterfaceSpecified returns true if the data user has specified the protocol or the format of the interface
+func isInterfaceSpecified(requirements *app.DataRequirements) bool {
+	return requirements.Interface != (app.InterfaceDetails{})
+}
+
+// NegotiateInterface selects the interface for a dataset whose requirements do not specify one.
+// If the application has a workload, the native interface of the dataset is preferred when the governance policies
+// allow reading it without transformations and a read module exposes it. Otherwise, the API of the most capable
+// read module supporting the dataset source is selected, i.e. the one supporting the largest number of actions.
+// Without a workload, the data is copied in its native interface if possible, or to the first supported sink otherwise.
+// The interface is not negotiated for datasets written by the workload.
+func (m *ModuleManager) NegotiateInterface(item modules.DataInfo, appContext *app.M4DApplication) (*app.InterfaceDetails, error) {
+	if item.Context.Flow == app.Write {
+		return nil, errors.New("the interface must be specified for writing " + item.Context.DataSetID)
+	}
+	source := &item.DataDetails.Interface
+	// modules are examined in the order of their names for the result to be stable
+	names := make([]string, 0, len(m.Modules))
+	for name := range m.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if appContext.Spec.Selector.WorkloadSelector.Size() == 0 {
+		var sinks []*app.InterfaceDetails
+		for _, name := range names {
+			for _, inter := range capabilities.GetModuleCapabilities(m.Modules[name], app.Copy) {
+				if inter.Source != nil && inter.Sink != nil && capabilities.MatchesInterface(inter.Source, source) {
+					sinks = append(sinks, resolveWildcards(inter.Sink, source))
+				}
+			}
+		}
+		if len(sinks) == 0 {
+			return nil, errors.New(app.ModuleNotFound + " for copying data from " + source.Protocol + "/" + source.DataFormat)
+		}
+		for _, sink := range sinks {
+			if *sink == *source {
+				return sink, nil
+			}
+		}
+		return sinks[0], nil
+	}
+
+	if err := m.setDatasetGeography(item, appContext); err != nil {
+		return nil, err
+	}
+	actions, err := m.lookupPolicyDecisions(item.Context.DataSetID, appContext,
+		&pb.AccessOperation{Type: pb.AccessOperation_READ, Destination: m.WorkloadGeography})
+	if err != nil {
+		return nil, err
+	}
+	var selected *app.M4DModule
+	for _, name := range names {
+		module := m.Modules[name]
+		if !capabilities.SupportsFlow(module.Spec.Flows, app.Read) || module.Spec.Capabilities.API == nil {
+			continue
+		}
+		if !capabilities.SupportsInterface(capabilities.GetSupportedReadSources(module), source) {
+			continue
+		}
+		if len(actions) == 0 && capabilities.SupportsAPI(module, source) {
+			m.Log.Info("Native access is negotiated for " + item.Context.DataSetID)
+			native := *source
+			return &native, nil
+		}
+		if selected == nil || len(module.Spec.Capabilities.Actions) > len(selected.Spec.Capabilities.Actions) {
+			selected = module
+		}
+	}
+	if selected == nil {
+		return nil, errors.New(app.ModuleNotFound + " for reading data from " + source.Protocol + "/" + source.DataFormat)
+	}
+	m.Log.Info("The interface of " + selected.Name + " is negotiated for " + item.Context.DataSetID)
+	return resolveWildcards(&selected.Spec.Capabilities.API.InterfaceDetails, source), nil
+}
+
+// SelectModuleInstancesWithFallbacks selects the module instances for a dataset using the interface of its requirements.
+// If the interface can not be served, the fallback interfaces are tried in order, and the first satisfied one is returned.
+// The error of the requested interface is returned if none of the fallback interfaces is satisfied either.
+// The performance class mismatch recorded by a failed selection is rolled back before the next interface is tried,
+// and the modules rejected for the failed interfaces are no longer reported once a fallback interface is satisfied.
+func (m *ModuleManager) SelectModuleInstancesWithFallbacks(item modules.DataInfo, appContext *app.M4DApplication) ([]modules.ModuleInstanceSpec, *app.InterfaceDetails, error) {
+	rollback := recordedClassMismatch(appContext, item.Context.DataSetID)
+	clearRejections := recordedRejections(appContext, item.Context.DataSetID)
+	instances, err := m.SelectModuleInstances(item, appContext)
+	if err == nil {
+		return instances, nil, nil
+	}
+	rollback()
+	for i := range item.Context.Requirements.FallbackInterfaces {
+		fallback := item.Context.Requirements.FallbackInterfaces[i]
+		fallbackItem := item
+		fallbackItem.Context = item.Context.DeepCopy()
+		fallbackItem.Context.Requirements.Interface = fallback
+		if fallbackInstances, fallbackErr := m.SelectModuleInstances(fallbackItem, appContext); fallbackErr == nil {
+			m.Log.Info("Fallback interface " + fallback.Protocol + "/" + fallback.DataFormat + " is satisfied for " + item.Context.DataSetID)
+			clearRejections()
+			return fallbackInstances, &fallback, nil
+		}
+		rollback()
+	}
+	return instances, nil, err
+}
+
+// recordedClassMismatch returns a function restoring the performance class mismatch recorded for the dataset
+func recordedClassMismatch(appContext *app.M4DApplication, datasetID string) func() {
+	mismatch, recorded := appContext.Status.MismatchedPerformanceClasses[datasetID]
+	return func() {
+		if recorded {
+			appContext.Status.MismatchedPerformanceClasses[datasetID] = mismatch
+		} else {
+			delete(appContext.Status.MismatchedPerformanceClasses, datasetID)
+		}
+	}
+}
+
+// recordedRejections returns a function removing the rejections of modules recorded for the dataset since it was called
+func recordedRejections(appContext *app.M4DApplication, datasetID string) func() {
+	recorded := len(appContext.Status.DebugInfo[datasetID])
+	return func() {
+		if len(appContext.Status.DebugInfo[datasetID]) == recorded {
+			return
+		}
+		if recorded == 0 {
+			delete(appContext.Status.DebugInfo, datasetID)
+			return
+		}
+		appContext.Status.DebugInfo[datasetID] = appContext.Status.DebugInfo[datasetID][:recorded]
+	}
+}
+
+// resolveWildcards replaces the wildcards of a declared interface with the values of the source interface
+func resolveWildcards(declared *app.InterfaceDetails, source *app.InterfaceDetails) *app.InterfaceDetails {
+	resolved := *declared
+	if resolved.Protocol == capabilities.Wildcard {
+		resolved.Protocol = source.Protocol
+	}
+	if resolved.DataFormat == capabilities.Wildcard {
+		resolved.DataFormat = source.DataFormat
+	}
+	return &resolved
+}
+
+// RequestedInterface returns the interface in which a dataset is accessed in the flow: the write interface specified
+// for writing it, the interface negotiated for it, including a satisfied fallback interface, or the interface specified in its requirements
+func RequestedInterface(applicationContext *app.M4DApplication, datasetID string, flow app.ModuleFlow) *app.InterfaceDetails {
+	if flow == app.Write {
+		for _, dataCtx := range applicationContext.Spec.Data {
+			if dataCtx.DataSetID == datasetID && dataCtx.Requirements.WriteInterface != nil {
+				return dataCtx.Requirements.WriteInterface
+			}
+		}
+	}
+	if negotiated, found := applicationContext.Status.NegotiatedInterfaces[statusKey(datasetID, flow)]; found {
+		return &negotiated
+	}
+	for _, dataCtx := range applicationContext.Spec.Data {
+		if dataCtx.DataSetID == datasetID && isInterfaceSpecified(&dataCtx.Requirements) {
+			return &dataCtx.Requirements.Interface
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Connectors and operations reported by the m4d_connector_request_duration_seconds metric
+const (
+	CatalogConnector = "catalog"
+	PolicyConnector  = "policy"
+)
+
+var (
+	moduleSelectionFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "m4d_application_module_selection_failures_total",
+		Help: "Number of times the selection of the modules of a dataset has started failing",
+	})
+	connectorLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "m4d_connector_request_duration_seconds",
+		Help:    "Duration of the requests made by the manager to the data catalog and policy manager connectors",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"connector", "operation"})
+
+	selectionFailures = &failureTracker{counter: moduleSelectionFailures}
+)
+
+func init() {
+	metrics.Registry.MustRegister(moduleSelectionFailures, connectorLatency)
+}
+
+// ObserveConnectorLatency records the duration of a connector request started at the given time
+func ObserveConnectorLatency(connector string, operation string, start time.Time) {
+	connectorLatency.WithLabelValues(connector, operation).Observe(time.Since(start).Seconds())
+}
+
+// failureTracker keeps the datasets of each application whose module selection fails, such that a failure is counted
+// when the selection starts failing rather than by every reconcile in which it still fails
+type failureTracker struct {
+	mutex    sync.Mutex
+	failures map[types.NamespacedName]map[string]bool
+	counter  prometheus.Counter
+}
+
+// set records whether the module selection of a dataset, identified by its status key, fails
+func (t *failureTracker) set(key types.NamespacedName, dataset string, failed bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !failed {
+		delete(t.failures[key], dataset)
+		return
+	}
+	if t.failures[key][dataset] {
+		return
+	}
+	if t.failures == nil {
+		t.failures = make(map[types.NamespacedName]map[string]bool)
+	}
+	if t.failures[key] == nil {
+		t.failures[key] = make(map[string]bool)
+	}
+	t.failures[key][dataset] = true
+	t.counter.Inc()
+}
+
+// remove forgets a deleted application
+func (t *failureTracker) remove(key types.NamespacedName) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.failures, key)
+}
+
+// Forget drops the module selection failures and the policy decisions tracked for a deleted application
+func Forget(key types.NamespacedName) {
+	selectionFailures.remove(key)
+	policyDecisions.remove(key)
+}
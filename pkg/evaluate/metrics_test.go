@@ -0,0 +1,37 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestFailureTracker checks that a failing module selection is counted once, until it succeeds again
+func TestFailureTracker(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_failures_total"})
+	tracker := &failureTracker{counter: counter}
+	notebook := types.NamespacedName{Name: "notebook", Namespace: "default"}
+
+	tracker.set(notebook, "s3/allow-dataset", true)
+	tracker.set(notebook, "s3/allow-dataset", true)
+	tracker.set(notebook, "s3/other-dataset", false)
+	g.Expect(testutil.ToFloat64(counter)).To(gomega.Equal(1.0))
+
+	tracker.set(notebook, "s3/allow-dataset", false)
+	tracker.set(notebook, "s3/allow-dataset", true)
+	g.Expect(testutil.ToFloat64(counter)).To(gomega.Equal(2.0))
+
+	// the failures of a deleted application are forgotten
+	tracker.remove(notebook)
+	tracker.set(notebook, "s3/allow-dataset", true)
+	g.Expect(testutil.ToFloat64(counter)).To(gomega.Equal(3.0))
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"strings"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// ApprovedUpgrades returns the modules whose upgrade is approved by the upgradeModules annotation of the application
+func ApprovedUpgrades(application *app.M4DApplication) map[string]bool {
+	approved := make(map[string]bool)
+	for _, name := range strings.Split(application.Annotations[app.UpgradeModulesAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			approved[name] = true
+		}
+	}
+	return approved
+}
+
+// ModuleUpgraded returns true if the installed spec of a module differs from the spec deployed for an application.
+// Changing only the upgrade policy is not an upgrade.
+func ModuleUpgraded(deployed *app.M4DModuleSpec, installed *app.M4DModuleSpec) bool {
+	previous := deployed.DeepCopy()
+	previous.UpgradePolicy = installed.UpgradePolicy
+	return !equality.Semantic.DeepEqual(previous, installed)
+}
+
+// migrateModules keeps the specs deployed for the application of the modules that have been upgraded with the Manual
+// policy, unless their upgrade is approved, and records the specs of the selected modules and the outdated modules
+// in the status. The modules with the other policies are deployed with their installed spec.
+func migrateModules(application *app.M4DApplication, instances []modules.ModuleInstanceSpec) {
+	deployed := application.Status.DeployedModules
+	approved := ApprovedUpgrades(application)
+	specs := make(map[string]app.M4DModuleSpec)
+	outdated := make(map[string]app.OutdatedModule)
+	for i := range instances {
+		module := instances[i].Module
+		spec, found := deployed[module.Name]
+		if found && module.Spec.UpgradePolicy == app.ManualUpgrade && !approved[module.Name] && ModuleUpgraded(&spec, &module.Spec) {
+			outdated[module.Name] = app.OutdatedModule{Chart: module.Spec.Chart.Name, Generation: module.Generation}
+			// the module is copied since it may be shared with the other applications
+			previous := module.DeepCopy()
+			previous.Spec = *spec.DeepCopy()
+			previous.Spec.UpgradePolicy = module.Spec.UpgradePolicy
+			instances[i].Module = previous
+		}
+		specs[module.Name] = *instances[i].Module.Spec.DeepCopy()
+	}
+	application.Status.DeployedModules = nil
+	if len(specs) > 0 {
+		application.Status.DeployedModules = specs
+	}
+	application.Status.OutdatedModules = nil
+	if len(outdated) > 0 {
+		application.Status.OutdatedModules = outdated
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMigrateModules(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	module := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", module)).NotTo(gomega.HaveOccurred())
+	previous := *module.Spec.DeepCopy()
+	module.Spec.Chart = app.ChartSpec{Name: "ghcr.io/mesh-for-data/read-parquet:0.2.0"}
+	module.Spec.Capabilities.SupportedInterfaces = nil
+	module.Generation = 2
+	newInstances := func() []modules.ModuleInstanceSpec {
+		return []modules.ModuleInstanceSpec{{Module: module, AssetID: "s3/allow-dataset"}}
+	}
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"}}
+
+	// the specs of the modules deployed for the first time are recorded
+	migrateModules(application, newInstances())
+	g.Expect(application.Status.DeployedModules).To(gomega.HaveKeyWithValue(module.Name, module.Spec))
+	g.Expect(application.Status.OutdatedModules).To(gomega.BeEmpty())
+
+	// the applications are migrated by default
+	application.Status.DeployedModules = map[string]app.M4DModuleSpec{module.Name: previous}
+	instances := newInstances()
+	migrateModules(application, instances)
+	g.Expect(instances[0].Module.Spec).To(gomega.Equal(module.Spec))
+	g.Expect(application.Status.DeployedModules[module.Name]).To(gomega.Equal(module.Spec))
+
+	// the whole previous spec is kept with the Manual policy, without modifying the module
+	module.Spec.UpgradePolicy = app.ManualUpgrade
+	application.Status.DeployedModules = map[string]app.M4DModuleSpec{module.Name: previous}
+	instances = newInstances()
+	migrateModules(application, instances)
+	g.Expect(instances[0].Module.Spec.Chart).To(gomega.Equal(previous.Chart))
+	g.Expect(instances[0].Module.Spec.Capabilities).To(gomega.Equal(previous.Capabilities))
+	g.Expect(module.Spec.Chart.Name).To(gomega.Equal("ghcr.io/mesh-for-data/read-parquet:0.2.0"))
+	g.Expect(application.Status.DeployedModules[module.Name].Chart).To(gomega.Equal(previous.Chart))
+	g.Expect(application.Status.OutdatedModules).To(gomega.HaveKeyWithValue(module.Name,
+		app.OutdatedModule{Chart: module.Spec.Chart.Name, Generation: 2}))
+
+	// until the upgrade is approved
+	application.Annotations = map[string]string{app.UpgradeModulesAnnotation: "other-module, " + module.Name}
+	instances = newInstances()
+	migrateModules(application, instances)
+	g.Expect(instances[0].Module.Spec).To(gomega.Equal(module.Spec))
+	g.Expect(application.Status.DeployedModules[module.Name]).To(gomega.Equal(module.Spec))
+	g.Expect(application.Status.OutdatedModules).To(gomega.BeEmpty())
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"encoding/json"
+
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+)
+
+// AdminModuleVersions parses the version ranges of the modules set by the administrators.
+// Invalid configurations are logged and ignored, such that the latest versions of the modules are selected.
+func AdminModuleVersions(log logr.Logger) map[string]string {
+	config := utils.GetModuleVersions()
+	if config == "" {
+		return nil
+	}
+	versions := map[string]string{}
+	if err := json.Unmarshal([]byte(config), &versions); err != nil {
+		log.Error(err, "invalid version ranges of the modules, the ranges are ignored")
+		return nil
+	}
+	return versions
+}
+
+// moduleVersions merges the version ranges of the modules set by the administrators with the ones of the application,
+// which take precedence for the same chart
+func moduleVersions(admin map[string]string, application *app.M4DApplication) map[string]string {
+	if len(application.Spec.ModuleVersions) == 0 {
+		return admin
+	}
+	versions := make(map[string]string, len(admin)+len(application.Spec.ModuleVersions))
+	for chart, versionRange := range admin {
+		versions[chart] = versionRange
+	}
+	for chart, versionRange := range application.Spec.ModuleVersions {
+		versions[chart] = versionRange
+	}
+	return versions
+}
@@ -0,0 +1,761 @@
+// Copyright 2020 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	modules "github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/capabilities"
+	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/events"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	local "github.com/mesh-for-data/mesh-for-data/pkg/multicluster/local"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	vault "github.com/mesh-for-data/mesh-for-data/pkg/vault"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewAssetInfo points to the provisoned storage and hold information about the new asset
+type NewAssetInfo struct {
+	Storage *storage.ProvisionedStorage
+	Details *pb.DatasetDetails
+}
+
+// ModuleManager builds a set of modules based on the requirements (governance actions, data location) and the existing set of M4DModules
+type ModuleManager struct {
+	Client             client.Client
+	Log                logr.Logger
+	Modules            map[string]*app.M4DModule
+	Clusters           []multicluster.Cluster
+	Owner              types.NamespacedName
+	PolicyManager      connectors.PolicyManager
+	WorkloadGeography  string
+	Provision          storage.ProvisionInterface
+	VaultConnection    vault.Interface
+	ProvisionedStorage map[string]NewAssetInfo
+	// PromotedCopies maps the datasets whose copies have been promoted to standalone datasets to their buckets
+	PromotedCopies map[string]string
+	// GovernedCopyMaxSize is the size in bytes up to which a copy with the transformations applied
+	// is preferred to transforming the data on every read. Zero disables such copies.
+	GovernedCopyMaxSize int64
+	// ActionTaxonomy defines the actions that may be returned by the policy manager
+	ActionTaxonomy taxonomy.Actions
+	// PrivacyLevels defines the target privacy levels that may be requested for the datasets, and the actions achieving them
+	PrivacyLevels taxonomy.PrivacyLevels
+	// Events exports the decisions of the policy manager, if set
+	Events events.Emitter
+	// ModuleVersions maps the repositories of the charts of the modules to the semantic version ranges that may be selected
+	ModuleVersions map[string]string
+	// clusterScoring weighs the clusters in which modules can run, read once from the cluster scoring configmap
+	clusterScoring *modules.ClusterScoring
+	// pendingDatasets maps the datasets to the storage requested for their copies, created by ProvisionStorage
+	pendingDatasets map[string]storage.DatasetRequest
+	// privacyActions maps the datasets to the actions achieving their target privacy levels, computed by translatePrivacyLevels
+	privacyActions map[string][]*pb.EnforcementAction
+	// residencyChecks maps the datasets and their flows to the outcome of checking their required geography, which is
+	// checked once although both the interface negotiation and the module selection set the geography of the datasets
+	residencyChecks map[string]error
+	// requiredTags maps the datasets to the tags of the storage accounts in which the governance policies allow copying them
+	requiredTags map[string]map[string]string
+	// Recorder records the Kubernetes events of the application, if set
+	Recorder record.EventRecorder
+	// policyManagerUnavailable is set if a call to the policy manager has failed
+	policyManagerUnavailable bool
+}
+
+// ClusterScoringConfigMapName is the name of the configmap in the control plane namespace
+// that sets the weights of the scoring of clusters
+const ClusterScoringConfigMapName = "cluster-scoring"
+
+// SelectModuleInstances builds a list of required modules with the relevant arguments
+/*
+
+Future (ingest & write) order of the lookup to support ingest is:
+- If no label selector assume ingest of external data (what about archive in future?)
+	- run Copy module close to destination (determined based on governance decisions)
+	- and register new data set in data catalog
+- If Data Context Flow=Write
+   - Write is always required, and always close to compute
+   - Implicit Copy is used on demand, e.g. if a write module does not support the existing source of data or governance actions
+   - Transformations are always done at workload location
+   - If not external data, then register in data catalog
+
+Updates to add ingest:
+- If no label selector assume ingest of external data
+	- run Copy module close to destination (determined based on governance decisions)
+	- and register new data set in data catalog
+- Otherwise assume workload wants to read from cataloged data
+   - Read is always required.
+   - Copy is used on demand, e.g. if a read module does not support the existing source of data or actions
+   - Transformations are always done at data source location
+   - Read module runs close to compute (in processing geography)
+   - All data sets are processed, even if an error is encountered in one or more, to provide a complete status at the end of the reconcile
+   - Dependencies are checked but not added yet to the blueprint
+*/
+
+// copyAllocation is the storage allocated for the copy of a dataset, recorded once the modules using it are selected
+type copyAllocation struct {
+	request storage.DatasetRequest
+	asset   NewAssetInfo
+}
+
+// getCopyDestination allocates a bucket for implicit copies or ingest.
+// The allocation is only recorded by recordCopyDestination once the selection of the modules using it succeeds, after which
+// the Dataset resource provisioning the bucket is created by ProvisionStorage, together with those of the other datasets.
+func (m *ModuleManager) getCopyDestination(item modules.DataInfo, destinationInterface *app.InterfaceDetails, geo string) (*app.DataStore, *copyAllocation, error) {
+	// provisioned storage for COPY
+	originalAssetName := item.DataDetails.Name
+	// the bucket of a promoted copy belongs to the standalone dataset, another bucket is allocated to copy the dataset again
+	bucketID := originalAssetName
+	if promoted, found := m.PromotedCopies[item.Context.DataSetID]; found {
+		bucketID += "/" + promoted
+	}
+	var bucket *storage.ProvisionedStorage
+	var err error
+	if bucket, err = AllocateBucket(m.Client, m.Log, m.Owner, bucketID, geo, m.requiredTags[item.Context.DataSetID]); err != nil {
+		m.Log.Info("Bucket allocation failed: " + err.Error())
+		return nil, nil, err
+	}
+	// the connection of the copy is described by the provisioner of the storage, and registered as is in the data catalog
+	datastore, err := storage.DescribeDataStore(m.Provision, bucket, originalAssetName+utils.Hash(m.Owner.Name+m.Owner.Namespace, 10))
+	if err != nil {
+		return nil, nil, err
+	}
+	connection := serde.NewCompactArbitrary(datastore, utils.GetCompactEncoding())
+	allocation := &copyAllocation{
+		request: storage.DatasetRequest{
+			Ref:     types.NamespacedName{Name: bucket.Name, Namespace: utils.GetSystemNamespace()},
+			Storage: bucket,
+			Owner:   m.Owner,
+		},
+	}
+	allocation.asset = NewAssetInfo{
+		Storage: bucket,
+		Details: &pb.DatasetDetails{
+			Name:       originalAssetName,
+			Geo:        item.DataDetails.Geography,
+			DataFormat: destinationInterface.DataFormat,
+			DataStore:  datastore,
+			Metadata:   item.DataDetails.Metadata,
+		}}
+
+	vaultSecretPath := vault.PathForReadingKubeSecret(bucket.SecretRef.Namespace, bucket.SecretRef.Name)
+	destination := &app.DataStore{
+		Connection: *connection,
+		Format:     destinationInterface.DataFormat,
+	}
+	if err := setCredentials(destination, vaultSecretPath); err != nil {
+		return nil, nil, err
+	}
+	return destination, allocation, nil
+}
+
+// recordCopyDestination records the storage allocated for the copy of a dataset whose modules have been selected
+func (m *ModuleManager) recordCopyDestination(datasetID string, allocation *copyAllocation) {
+	if m.pendingDatasets == nil {
+		m.pendingDatasets = make(map[string]storage.DatasetRequest)
+	}
+	m.pendingDatasets[datasetID] = allocation.request
+	m.ProvisionedStorage[datasetID] = allocation.asset
+	utils.PrintStructure(&allocation.asset, m.Log, "ProvisionedStorage element")
+}
+
+func (m *ModuleManager) selectReadModule(item modules.DataInfo, appContext *app.M4DApplication) (*modules.Selector, error) {
+	// read module is required if the workload exists
+	if appContext.Spec.Selector.WorkloadSelector.Size() == 0 {
+		return nil, nil
+	}
+	m.Log.Info("Select read path for " + item.Context.DataSetID)
+
+	// Read policies for data that is processed in the workload geography
+	var readActions []*pb.EnforcementAction
+	var err error
+	readActions, err = m.lookupPolicyDecisions(item.Context.DataSetID, appContext,
+		&pb.AccessOperation{Type: pb.AccessOperation_READ, Destination: m.WorkloadGeography})
+	if err != nil {
+		return nil, err
+	}
+	// select a read module that supports user interface requirements
+	// actions are not checked since they are not necessarily done by the read module
+	newReadSelector := func() *modules.Selector {
+		return &modules.Selector{Flow: app.Read,
+			Destination:      &item.Context.Requirements.Interface,
+			Actions:          []*pb.EnforcementAction{},
+			Source:           nil,
+			Dependencies:     []*app.M4DModule{},
+			Module:           nil,
+			Message:          "",
+			Geo:              m.WorkloadGeography,
+			PerformanceClass: item.Context.Requirements.PerformanceClass,
+			Pinned:           appContext.GetPinnedModule(item.Context.DataSetID, app.Read),
+			Versions:         m.ModuleVersions,
+		}
+	}
+	// a module streaming the data source directly, e.g. from Kafka, is preferred over copying the data to be read
+	readSelector := newReadSelector()
+	readSelector.Streaming = true
+	readSelector.Source = &item.DataDetails.Interface
+	if !readSelector.SelectModule(m.Modules) {
+		readSelector = newReadSelector()
+		if !readSelector.SelectModule(m.Modules) {
+			m.Log.Info(readSelector.GetError())
+			recordRejections(appContext, item.Context.DataSetID, readSelector.Flow, readSelector.Rejections)
+			return nil, errors.New(readSelector.GetError())
+		}
+	}
+	if readSelector.ClassMismatch != "" {
+		m.Log.Info(readSelector.ClassMismatch)
+		if appContext.Status.MismatchedPerformanceClasses == nil {
+			appContext.Status.MismatchedPerformanceClasses = make(map[string]string)
+		}
+		appContext.Status.MismatchedPerformanceClasses[item.Context.DataSetID] = readSelector.ClassMismatch
+	}
+	readSelector.Actions = readActions
+	return readSelector, nil
+}
+
+func (m *ModuleManager) selectCopyModule(item modules.DataInfo, appContext *app.M4DApplication, readSelector *modules.Selector) (*modules.Selector, error) {
+	// logic for deciding whether copy module is required
+	var interfaces []*app.InterfaceDetails
+	var copyRequired bool
+	additionalActions := []*pb.EnforcementAction{}
+	if readSelector != nil {
+		copyRequired, interfaces, additionalActions = m.getCopyRequirements(item, readSelector)
+	} else if item.Context.Requirements.Copy.Required {
+		copyRequired = true
+		interfaces = []*app.InterfaceDetails{&item.Context.Requirements.Interface}
+	}
+	if !copyRequired {
+		return nil, nil
+	}
+	if IsCopyExpired(appContext, item.Context.DataSetID) {
+		return nil, errors.New(app.CopyExpired)
+	}
+	actionsOnCopy := []*pb.EnforcementAction{}
+	geo := m.WorkloadGeography
+	// WRITE actions
+	if readSelector == nil {
+		var err error
+		actionsOnCopy, geo, err = m.enforceWritePolicies(appContext, item.Context.DataSetID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	actionsOnCopy = append(actionsOnCopy, additionalActions...)
+	m.Log.Info("Copy is required for " + item.Context.DataSetID)
+	var copySelector *modules.Selector
+	var rejections []string
+	// select a module that supports COPY, supports required governance actions, has the required dependencies, with source in module sources and a non-empty intersection between requested and supported interfaces.
+	for _, copyDest := range interfaces {
+		copySelector = &modules.Selector{
+			Flow:         app.Copy,
+			Source:       &item.DataDetails.Interface,
+			Actions:      actionsOnCopy,
+			Destination:  copyDest,
+			Dependencies: make([]*app.M4DModule, 0),
+			Module:       nil,
+			Geo:          geo,
+			Message:      "",
+			Pinned:       appContext.GetPinnedModule(item.Context.DataSetID, app.Copy),
+			Versions:     m.ModuleVersions,
+		}
+
+		if copySelector.SelectModule(m.Modules) {
+			break
+		}
+		rejections = append(rejections, copySelector.Rejections...)
+	}
+	if copySelector == nil {
+		return nil, errors.New("no copy module has been found supporting required source interface")
+	}
+	if copySelector.GetModule() == nil {
+		m.Log.Info("Could not find copy module for " + item.Context.DataSetID)
+		recordRejections(appContext, item.Context.DataSetID, app.Copy, rejections)
+		return nil, errors.New(copySelector.GetError())
+	}
+	return copySelector, nil
+}
+
+// selectWriteModule selects a module that exposes the requested interface to the workload and writes the data
+// to the location of the dataset, applying the actions required by the governance policies on writing
+func (m *ModuleManager) selectWriteModule(item modules.DataInfo, appContext *app.M4DApplication) (*modules.Selector, error) {
+	if appContext.Spec.Selector.WorkloadSelector.Size() == 0 {
+		return nil, errors.New("a workload is required for writing " + item.Context.DataSetID)
+	}
+	m.Log.Info("Select write path for " + item.Context.DataSetID)
+
+	// Write policies for data that is written to the location of the dataset
+	writeActions, err := m.lookupPolicyDecisions(item.Context.DataSetID, appContext,
+		&pb.AccessOperation{Type: pb.AccessOperation_WRITE, Destination: item.DataDetails.Geography})
+	if err != nil {
+		return nil, err
+	}
+	writeSelector := &modules.Selector{Flow: app.Write,
+		Source:       &item.Context.Requirements.Interface,
+		Destination:  &item.DataDetails.Interface,
+		Actions:      writeActions,
+		Dependencies: []*app.M4DModule{},
+		Module:       nil,
+		Message:      "",
+		Geo:          m.WorkloadGeography,
+		Pinned:       appContext.GetPinnedModule(item.Context.DataSetID, app.Write),
+		Versions:     m.ModuleVersions,
+	}
+	if !writeSelector.SelectModule(m.Modules) {
+		m.Log.Info(writeSelector.GetError())
+		recordRejections(appContext, item.Context.DataSetID, writeSelector.Flow, writeSelector.Rejections)
+		return nil, errors.New(writeSelector.GetError())
+	}
+	return writeSelector, nil
+}
+
+// setClusterVault sets the vault used by a module running in the given cluster to read the credentials of the data store.
+// Clusters with a local vault replica declare its address in their metadata, the other clusters use the vault of the control plane.
+func (m *ModuleManager) setClusterVault(dataStore *app.DataStore, clusterName string) {
+	if dataStore.CredentialsType == app.KubernetesCredentials {
+		return
+	}
+	for _, cluster := range m.Clusters {
+		if cluster.Name == clusterName {
+			dataStore.Vault.AuthPath = utils.GetAuthPath(cluster.Metadata.VaultAuthPath)
+			if cluster.Metadata.VaultAddress != "" {
+				dataStore.Vault.Address = cluster.Metadata.VaultAddress
+			}
+			return
+		}
+	}
+}
+
+// selectWriteInstances selects the write module for a dataset written by the workload
+func (m *ModuleManager) selectWriteInstances(item modules.DataInfo, appContext *app.M4DApplication, sinkDataStore *app.DataStore) ([]modules.ModuleInstanceSpec, error) {
+	writeSelector, err := m.selectWriteModule(item, appContext)
+	if err != nil {
+		m.Log.Info("Could not select a write module for " + item.Context.DataSetID + " : " + err.Error())
+		return nil, err
+	}
+	writeCluster, err := writeSelector.SelectCluster(item, m.Clusters, m.getClusterScoring())
+	if err != nil {
+		m.Log.Info("Could not determine the cluster for write: " + err.Error())
+		return nil, err
+	}
+	m.setClusterVault(sinkDataStore, writeCluster)
+	writeArgs := &app.ModuleArguments{
+		Write: []app.WriteModuleArgs{
+			{
+				Destination:     *sinkDataStore,
+				AssetID:         utils.CreateDataSetIdentifier(item.Context.DataSetID),
+				Transformations: actionsToArbitrary(writeSelector.Actions),
+			},
+		},
+	}
+	m.Log.Info("Adding write path")
+	return writeSelector.AddModuleInstances(writeArgs, item, writeCluster), nil
+}
+
+// recordRejections reports in the status why the installed modules have not been selected for a flow of a dataset
+func recordRejections(appContext *app.M4DApplication, datasetID string, flow app.ModuleFlow, rejections []string) {
+	if len(rejections) == 0 {
+		return
+	}
+	if appContext.Status.DebugInfo == nil {
+		appContext.Status.DebugInfo = make(map[string][]string)
+	}
+	for _, rejection := range rejections {
+		appContext.Status.DebugInfo[datasetID] = append(appContext.Status.DebugInfo[datasetID], string(flow)+": "+rejection)
+	}
+}
+
+// pendingResources returns the Dataset resources that ProvisionStorage is going to create, in the order of the datasets
+func (m *ModuleManager) pendingResources() []app.OwnedResource {
+	datasetIDs := make([]string, 0, len(m.pendingDatasets))
+	for datasetID := range m.pendingDatasets {
+		datasetIDs = append(datasetIDs, datasetID)
+	}
+	sort.Strings(datasetIDs)
+	resources := make([]app.OwnedResource, 0, len(datasetIDs))
+	for _, datasetID := range datasetIDs {
+		request := m.pendingDatasets[datasetID]
+		resources = append(resources, app.OwnedResource{
+			Kind:        storage.DatasetKind,
+			Name:        request.Ref.Name,
+			Namespace:   request.Ref.Namespace,
+			StorageType: request.Storage.Type,
+		})
+	}
+	return resources
+}
+
+// ProvisionStorage creates the Dataset resources of the buckets allocated by getCopyDestination in parallel,
+// and returns the errors of the datasets whose storage could not be provisioned
+func (m *ModuleManager) ProvisionStorage() map[string]error {
+	datasetIDs := make([]string, 0, len(m.pendingDatasets))
+	for datasetID := range m.pendingDatasets {
+		datasetIDs = append(datasetIDs, datasetID)
+	}
+	sort.Strings(datasetIDs)
+	requests := make([]storage.DatasetRequest, len(datasetIDs))
+	for i, datasetID := range datasetIDs {
+		requests[i] = m.pendingDatasets[datasetID]
+	}
+	m.pendingDatasets = nil
+	failures := make(map[string]error)
+	for i, err := range storage.CreateDatasets(m.Provision, requests, storage.DefaultBatchOptions()) {
+		if err != nil {
+			m.Log.Info("Dataset creation failed for " + datasetIDs[i] + ": " + err.Error())
+			failures[datasetIDs[i]] = err
+		}
+	}
+	return failures
+}
+
+// SelectModuleInstances selects the necessary read/copy/write modules for the blueprint for a given data set
+func (m *ModuleManager) SelectModuleInstances(item modules.DataInfo, appContext *app.M4DApplication) ([]modules.ModuleInstanceSpec, error) {
+	datasetID := item.Context.DataSetID
+	m.Log.Info("Select modules for " + datasetID)
+	instances := make([]modules.ModuleInstanceSpec, 0)
+	var err error
+	// the modules run in the geography required for the dataset, or in the workload geography
+	if err = m.setDatasetGeography(item, appContext); err != nil {
+		m.Log.Info("Could not determine the processing geography of " + datasetID + ": " + err.Error())
+		return nil, err
+	}
+
+	// Set the value received from the catalog connector.
+	vaultSecretPath := item.VaultSecretPath
+
+	// Each selector receives source/sink interface and relevant actions
+	// Starting with the data location interface for source and the required interface for sink
+	sourceDataStore := &app.DataStore{
+		Connection: item.DataDetails.Connection,
+		Format:     item.DataDetails.Interface.DataFormat,
+	}
+	if err := setCredentials(sourceDataStore, vaultSecretPath); err != nil {
+		return nil, err
+	}
+	// the data written by the workload is stored in the location of the dataset
+	if item.Context.Flow == app.Write {
+		return m.selectWriteInstances(item, appContext, sourceDataStore)
+	}
+	// DataStore for destination will be determined if an implicit copy is required
+	var sinkDataStore *app.DataStore
+
+	var readSelector, copySelector *modules.Selector
+	if readSelector, err = m.selectReadModule(item, appContext); err != nil {
+		m.Log.Info("Could not select a read module for " + datasetID + " : " + err.Error())
+		return instances, err
+	}
+	if copySelector, err = m.selectCopyModule(item, appContext, readSelector); err != nil {
+		m.Log.Info("Could not select a copy module for " + datasetID + " : " + err.Error())
+		return instances, err
+	}
+
+	// the copy made by another application is read if it has been shared with the application
+	if copySelector != nil {
+		if sinkDataStore, err = m.grantedCopy(item, appContext, copySelector); err != nil {
+			m.Log.Info("Could not look up the granted copies of " + datasetID + " : " + err.Error())
+			return instances, err
+		}
+		if sinkDataStore != nil {
+			copySelector = nil
+		}
+	}
+	// the storage allocated for the copy is recorded once all the modules are selected
+	var allocation *copyAllocation
+	if copySelector != nil {
+		m.Log.Info("Found copy module " + copySelector.GetModule().Name + " for " + datasetID)
+		// copy should be applied - allocate storage
+		if sinkDataStore, allocation, err = m.getCopyDestination(item, copySelector.Destination, copySelector.Geo); err != nil {
+			m.Log.Info("Allocation failed: " + err.Error())
+			return instances, err
+		}
+		// append moduleinstances to the list
+		actions := actionsToArbitrary(copySelector.Actions)
+		copyArgs := &app.ModuleArguments{
+			Copy: &app.CopyModuleArgs{
+				Source:          *sourceDataStore,
+				Destination:     *sinkDataStore,
+				Transformations: actions,
+			},
+		}
+		copyCluster, err := copySelector.SelectCluster(item, m.Clusters, m.getClusterScoring())
+		if err != nil {
+			m.Log.Info("Could not determine the cluster for copy: " + err.Error())
+			return instances, err
+		}
+		m.setClusterVault(&copyArgs.Copy.Destination, copyCluster)
+		m.setClusterVault(&copyArgs.Copy.Source, copyCluster)
+
+		m.Log.Info("Adding copy module")
+		instances = copySelector.AddModuleInstances(copyArgs, item, copyCluster)
+	}
+
+	if readSelector != nil {
+		m.Log.Info("Adding read path")
+		var readSource app.DataStore
+		if sinkDataStore == nil {
+			readSource = *sourceDataStore
+		} else {
+			readSource = *sinkDataStore
+		}
+
+		actions := actionsToArbitrary(readSelector.Actions)
+		readCluster, err := readSelector.SelectCluster(item, m.Clusters, m.getClusterScoring())
+		if err != nil {
+			m.Log.Info("Could not determine the cluster for read: " + err.Error())
+			return instances, err
+		}
+		m.setClusterVault(&readSource, readCluster)
+		// the modules of a transformation chain run in the cluster of the read module, which reads the last of them
+		if chain := readSelector.Chain; len(chain) > 0 {
+			m.Log.Info("Adding transformation chain")
+			instances = append(instances, chainedInstances(appContext, item, chain, readSource, readSelector.GetModule().Name, readCluster)...)
+			readSource = chainedDataStore(appContext, chain[len(chain)-1], item.Context.DataSetID)
+		}
+		readInstructions := []app.ReadModuleArgs{
+			{
+				Source:          readSource,
+				AssetID:         utils.CreateDataSetIdentifier(item.Context.DataSetID),
+				Transformations: actions,
+			},
+		}
+
+		readArgs := &app.ModuleArguments{
+			Read: readInstructions,
+		}
+
+		instances = append(instances, readSelector.AddModuleInstances(readArgs, item, readCluster)...)
+	}
+	if allocation != nil {
+		m.recordCopyDestination(datasetID, allocation)
+	}
+	return instances, nil
+}
+
+// check whether IMPLICIT copy is required
+// decide on actions performed on read (update readSelector)
+// copy is required in the following cases:
+// - specifically requested by the user
+// - the read module does not support data interface
+// - the read module does not support all governance actions, unless a transformation chain performs them
+// - transformations are required while the read module does not run at source location
+// - transformations are required and the dataset is small enough for a governed copy to be preferable (see GovernedCopyMaxSize)
+// output:
+// - true if copy is required, false - otherwise
+// - interface capabilities to match copy destination, based on read sources
+// - read actions that copy has to support
+func (m *ModuleManager) getCopyRequirements(item modules.DataInfo, readSelector *modules.Selector) (bool, []*app.InterfaceDetails, []*pb.EnforcementAction) {
+	m.Log.Info("Checking supported read sources")
+	sources := capabilities.GetSupportedReadSources(readSelector.GetModule())
+	// check if read sources include the data source
+	supportsDataSource := capabilities.SupportsInterface(sources, &item.DataDetails.Interface)
+	// check if read supports all governance actions
+	supportsAllActions := readSelector.SupportsGovernanceActions(readSelector.GetModule(), readSelector.Actions)
+	// Copy is required when data has to be transformed and read is done at another location
+	transformAtSource := len(readSelector.Actions) > 0 && item.DataDetails.Geography != readSelector.Geo
+	// Copy is preferred when a small dataset can be transformed once instead of on every read
+	governedCopy := !transformAtSource && m.prefersGovernedCopy(item, readSelector)
+	readActionsOnCopy := []*pb.EnforcementAction{}
+	if transformAtSource || governedCopy {
+		readActionsOnCopy = append(readActionsOnCopy, readSelector.Actions...)
+		readSelector.Actions = []*pb.EnforcementAction{}
+	} else {
+		// ensure that copy + read support all needed actions
+		// actions that the read module can not perform are required to be done during copy
+		readActionsOnRead := []*pb.EnforcementAction{}
+		for _, action := range readSelector.Actions {
+			if !readSelector.SupportsGovernanceAction(readSelector.GetModule(), action) {
+				readActionsOnCopy = append(readActionsOnCopy, action)
+			} else {
+				readActionsOnRead = append(readActionsOnRead, action)
+			}
+		}
+		readSelector.Actions = readActionsOnRead
+		// the actions that no copy module supports may be performed by a chain of modules reading the data source
+		if len(readActionsOnCopy) > 0 && !item.Context.Requirements.Copy.Required && !m.copySupportsActions(item, sources, readActionsOnCopy) {
+			if chain := m.buildTransformationChain(item, readSelector, readActionsOnCopy); chain != nil {
+				m.Log.Info("The actions unsupported by the read module are performed by a transformation chain")
+				readSelector.Chain = chain
+				readActionsOnCopy = []*pb.EnforcementAction{}
+				supportsDataSource = true
+				supportsAllActions = true
+			}
+		}
+	}
+	// debug info
+	if !supportsDataSource {
+		m.Log.Info("Copy is required to support read-path module interface")
+		utils.PrintStructure(sources, m.Log, "Read sources")
+	}
+	if !supportsAllActions {
+		m.Log.Info("Copy is required because the read-path does not support all actions")
+		utils.PrintStructure(readActionsOnCopy, m.Log, "Unsupported actions")
+	}
+	if transformAtSource {
+		m.Log.Info("Copy is required because " + readSelector.Geo + " does not match " + item.DataDetails.Geography)
+	}
+	if governedCopy {
+		m.Log.Info("Copy is preferred because the dataset has to be transformed and its size does not exceed the governed copy threshold")
+	}
+	if item.Context.Requirements.Copy.Required {
+		m.Log.Info("Copy has been explicitly requested")
+	}
+	copyRequired := !supportsDataSource || !supportsAllActions || transformAtSource || governedCopy || item.Context.Requirements.Copy.Required
+	return copyRequired, sources, readActionsOnCopy
+}
+
+// prefersGovernedCopy returns true if the dataset has to be transformed on read and its known size does not exceed
+// GovernedCopyMaxSize, provided that a copy module supports the data source and all the transformations.
+// Larger datasets, and datasets of unknown size, are streamed through the read module.
+func (m *ModuleManager) prefersGovernedCopy(item modules.DataInfo, readSelector *modules.Selector) bool {
+	size := item.DataDetails.SizeBytes
+	if m.GovernedCopyMaxSize <= 0 || size <= 0 || size > m.GovernedCopyMaxSize || len(readSelector.Actions) == 0 {
+		return false
+	}
+	return m.copySupportsActions(item, capabilities.GetSupportedReadSources(readSelector.GetModule()), readSelector.Actions)
+}
+
+// copySupportsActions returns true if a copy module supports the data source, one of the sinks and all the actions
+func (m *ModuleManager) copySupportsActions(item modules.DataInfo, sinks []*app.InterfaceDetails, actions []*pb.EnforcementAction) bool {
+	selector := &modules.Selector{Flow: app.Copy}
+	for _, module := range m.Modules {
+		if !selector.SupportsGovernanceActions(module, actions) {
+			continue
+		}
+		for _, sink := range sinks {
+			if capabilities.SupportsCopy(module, &item.DataDetails.Interface, sink) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *ModuleManager) enforceWritePolicies(appContext *app.M4DApplication, datasetID string) ([]*pb.EnforcementAction, string, error) {
+	var err error
+	actions := []*pb.EnforcementAction{}
+	//	if the cluster selector is non-empty, the write will be done to the specified geography if possible
+	if m.WorkloadGeography != "" {
+		if actions, err = m.lookupPolicyDecisions(datasetID, appContext,
+			&pb.AccessOperation{Type: pb.AccessOperation_WRITE, Destination: m.WorkloadGeography}); err == nil {
+			return actions, m.WorkloadGeography, nil
+		}
+	}
+	var excludedGeos string
+	for _, cluster := range m.Clusters {
+		operation := &pb.AccessOperation{Type: pb.AccessOperation_WRITE, Destination: cluster.Metadata.Region}
+		if actions, err = m.lookupPolicyDecisions(datasetID, appContext, operation); err == nil {
+			return actions, cluster.Metadata.Region, nil
+		}
+		if !isPolicyDenied(err) {
+			return actions, "", err
+		}
+		if excludedGeos != "" {
+			excludedGeos += ", "
+		}
+		excludedGeos += cluster.Metadata.Region
+	}
+	return actions, "", errors.New("writing to all geographies is denied: " + excludedGeos)
+}
+
+// GetProcessingGeography determines the geography of the workload cluster.
+// If no cluster has been specified or detected for a workload, a local cluster is assumed.
+func (m *ModuleManager) GetProcessingGeography(applicationContext *app.M4DApplication) (string, error) {
+	if applicationContext.Spec.Selector.WorkloadSelector.Size() == 0 && applicationContext.Spec.Selector.ClusterName == "" {
+		// no workload
+		return "", nil
+	}
+	clusterName := m.workloadClusterName(applicationContext)
+	if clusterName == "" {
+		// the workload runs in a local cluster
+		localClusterManager, err := local.NewManager(m.Client, utils.GetSystemNamespace())
+		if err != nil {
+			return "", err
+		}
+		clusters, err := localClusterManager.GetClusters()
+		if err != nil || len(clusters) != 1 {
+			return "", err
+		}
+		return clusters[0].Metadata.Region, nil
+	}
+	for _, cluster := range m.Clusters {
+		if cluster.Name == clusterName {
+			return cluster.Metadata.Region, nil
+		}
+	}
+	return "", errors.New("Unknown cluster: " + clusterName)
+}
+
+// workloadClusterName returns the cluster specified in the selector of the application.
+// Otherwise the cluster on which the application has been created is detected from the cluster label
+// of the application or of its namespace, as set by the agent syncing the applications of a multicluster deployment.
+// An empty name stands for the local cluster.
+func (m *ModuleManager) workloadClusterName(applicationContext *app.M4DApplication) string {
+	if clusterName := applicationContext.Spec.Selector.ClusterName; clusterName != "" {
+		return clusterName
+	}
+	if clusterName := applicationContext.Labels[app.ApplicationClusterLabel]; clusterName != "" {
+		m.Log.Info("Detected the workload cluster from the application labels", "cluster", clusterName)
+		return clusterName
+	}
+	namespace := &corev1.Namespace{}
+	if err := m.Client.Get(context.Background(), types.NamespacedName{Name: applicationContext.Namespace}, namespace); err != nil {
+		// the cluster is not detected if the namespace can not be read
+		return ""
+	}
+	if clusterName := namespace.Labels[app.ApplicationClusterLabel]; clusterName != "" {
+		m.Log.Info("Detected the workload cluster from the namespace labels", "cluster", clusterName)
+		return clusterName
+	}
+	return ""
+}
+
+// getClusterScoring returns the weights of the scoring of clusters set in the cluster scoring configmap.
+// The default weights are used if the configmap does not exist, and in place of invalid weights.
+func (m *ModuleManager) getClusterScoring() modules.ClusterScoring {
+	if m.clusterScoring != nil {
+		return *m.clusterScoring
+	}
+	scoring := modules.DefaultClusterScoring()
+	m.clusterScoring = &scoring
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: ClusterScoringConfigMapName, Namespace: utils.GetSystemNamespace()}
+	if err := m.Client.Get(context.Background(), key, configMap); err != nil {
+		return scoring
+	}
+	for name, weight := range map[string]*float64{"costTier": &scoring.CostTier, "capacity": &scoring.Capacity, "egress": &scoring.Egress} {
+		value, found := configMap.Data[name]
+		if !found {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			m.Log.Info("Ignoring the invalid weight of " + name + " in the cluster scoring: " + err.Error())
+			continue
+		}
+		*weight = parsed
+	}
+	return scoring
+}
+
+func actionsToArbitrary(actions []*pb.EnforcementAction) []serde.Arbitrary {
+	result := []serde.Arbitrary{}
+	for _, action := range actions {
+		raw := serde.NewArbitrary(action)
+		result = append(result, *raw)
+	}
+	return result
+}
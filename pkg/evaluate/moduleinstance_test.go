@@ -0,0 +1,433 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// This test checks the decision between a governed copy and transforming the data on read
+// A db2 dataset requiring redaction, a read module able to redact, and a copy module able to redact
+// Result: a copy is required only for a dataset whose size does not exceed the threshold
+func TestGovernedCopyDecision(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	db2 := app.InterfaceDetails{Protocol: app.JdbcDb2, DataFormat: app.Table}
+	parquet := app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet}
+	redact := app.SupportedAction{ID: "redact-ID", Level: pb.EnforcementAction_COLUMN}
+	readModule := &app.M4DModule{
+		ObjectMeta: metav1.ObjectMeta{Name: "read-db2"},
+		Spec: app.M4DModuleSpec{
+			Flows: []app.ModuleFlow{app.Read},
+			Capabilities: app.Capability{
+				API: &app.ModuleAPI{InterfaceDetails: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+				SupportedInterfaces: []app.ModuleInOut{
+					{Flow: app.Read, Source: &db2},
+					{Flow: app.Read, Source: &parquet},
+				},
+				Actions: []app.SupportedAction{redact},
+			},
+		},
+	}
+	copyModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/copy-db2-parquet.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+
+	m := &ModuleManager{
+		Log:                 ctrl.Log.WithName("test"),
+		Modules:             map[string]*app.M4DModule{readModule.Name: readModule, copyModule.Name: copyModule},
+		GovernedCopyMaxSize: 1000,
+	}
+	newReadSelector := func() *modules.Selector {
+		return &modules.Selector{
+			Flow:    app.Read,
+			Module:  readModule,
+			Actions: []*pb.EnforcementAction{{Name: "redact", Id: "redact-ID", Level: pb.EnforcementAction_COLUMN}},
+		}
+	}
+	item := modules.DataInfo{
+		Context:     &app.DataContext{DataSetID: "db2/redact-dataset"},
+		DataDetails: &modules.DataDetails{Interface: db2, SizeBytes: 100},
+	}
+
+	// a small dataset is copied with the transformations applied
+	readSelector := newReadSelector()
+	copyRequired, _, actionsOnCopy := m.getCopyRequirements(item, readSelector)
+	g.Expect(copyRequired).To(gomega.BeTrue())
+	g.Expect(actionsOnCopy).To(gomega.HaveLen(1))
+	g.Expect(readSelector.Actions).To(gomega.BeEmpty())
+
+	// a large dataset is transformed on read
+	item.DataDetails.SizeBytes = 10000
+	readSelector = newReadSelector()
+	copyRequired, _, actionsOnCopy = m.getCopyRequirements(item, readSelector)
+	g.Expect(copyRequired).To(gomega.BeFalse())
+	g.Expect(actionsOnCopy).To(gomega.BeEmpty())
+	g.Expect(readSelector.Actions).To(gomega.HaveLen(1))
+
+	// a dataset of unknown size is transformed on read
+	item.DataDetails.SizeBytes = 0
+	readSelector = newReadSelector()
+	copyRequired, _, _ = m.getCopyRequirements(item, readSelector)
+	g.Expect(copyRequired).To(gomega.BeFalse())
+}
+
+// This test checks the detection of the workload cluster when no cluster is specified in the selector
+func TestWorkloadClusterDetection(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "remote-apps",
+		Labels: map[string]string{app.ApplicationClusterLabel: "neverland-cluster"},
+	}}
+	clusters, _ := (&mockup.ClusterLister{}).GetClusters()
+	m := &ModuleManager{
+		Client:   fake.NewFakeClientWithScheme(utils.NewScheme(g), namespace),
+		Log:      ctrl.Log.WithName("test"),
+		Clusters: clusters,
+	}
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "remote-apps"}}
+	application.Spec.Selector.WorkloadSelector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "notebook"}}
+
+	// the cluster is detected from the namespace of the application
+	geo, err := m.GetProcessingGeography(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(geo).To(gomega.Equal("neverland"))
+
+	// the label of the application takes precedence over the label of its namespace
+	application.Labels = map[string]string{app.ApplicationClusterLabel: "thegreendragon"}
+	geo, err = m.GetProcessingGeography(application)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(geo).To(gomega.Equal("theshire"))
+
+	// the cluster specified in the selector takes precedence over the detected cluster
+	application.Spec.Selector.ClusterName = "neverland-cluster"
+	g.Expect(m.GetProcessingGeography(application)).To(gomega.Equal("neverland"))
+
+	// a detected cluster must be registered
+	application.Spec.Selector.ClusterName = ""
+	application.Labels[app.ApplicationClusterLabel] = "mordor"
+	_, err = m.GetProcessingGeography(application)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+// This test checks that the cheapest of the clusters in the processing geography is selected,
+// with the weights of the scoring read from the cluster scoring configmap
+func TestCostAwareClusterSelection(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	clusters := []multicluster.Cluster{
+		{Name: "bagend", Metadata: multicluster.ClusterMetadata{Region: "theshire", Cost: multicluster.ClusterCost{Tier: 2, Capacity: 64}}},
+		{Name: "thegreendragon", Metadata: multicluster.ClusterMetadata{Region: "theshire", Cost: multicluster.ClusterCost{Tier: 1, Egress: 0.5}}},
+		{Name: "neverland-cluster", Metadata: multicluster.ClusterMetadata{Region: "neverland"}},
+	}
+	item := modules.DataInfo{Context: &app.DataContext{}, DataDetails: &modules.DataDetails{Geography: "neverland"}}
+	readSelector := &modules.Selector{Flow: app.Read, Geo: "theshire", Module: &app.M4DModule{}}
+
+	m := &ModuleManager{
+		Client:   fake.NewFakeClientWithScheme(utils.NewScheme(g)),
+		Log:      ctrl.Log.WithName("test"),
+		Clusters: clusters,
+	}
+	g.Expect(readSelector.SelectCluster(item, m.Clusters, m.getClusterScoring())).To(gomega.Equal("thegreendragon"))
+
+	// a large capacity outweighs a higher cost tier
+	scoring := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ClusterScoringConfigMapName, Namespace: utils.GetSystemNamespace()},
+		Data:       map[string]string{"capacity": "0.1", "egress": "invalid"},
+	}
+	m = &ModuleManager{
+		Client:   fake.NewFakeClientWithScheme(utils.NewScheme(g), scoring),
+		Log:      ctrl.Log.WithName("test"),
+		Clusters: clusters,
+	}
+	g.Expect(m.getClusterScoring()).To(gomega.Equal(modules.ClusterScoring{CostTier: 1, Capacity: 0.1, Egress: 1}))
+	g.Expect(readSelector.SelectCluster(item, m.Clusters, m.getClusterScoring())).To(gomega.Equal("bagend"))
+
+	// clusters outside of the processing geography are not selected
+	readSelector.Geo = "mordor"
+	_, err := readSelector.SelectCluster(item, m.Clusters, m.getClusterScoring())
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+// This test checks that modules running in a cluster with a local vault replica read the credentials from it,
+// while modules of the other clusters use the vault of the control plane
+func TestClusterVault(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	m := &ModuleManager{
+		Log: ctrl.Log.WithName("test"),
+		Clusters: []multicluster.Cluster{
+			{Name: "thegreendragon", Metadata: multicluster.ClusterMetadata{Region: "theshire", VaultAuthPath: "kubernetes"}},
+			{Name: "neverland-cluster", Metadata: multicluster.ClusterMetadata{Region: "neverland", VaultAuthPath: "neverland",
+				VaultAddress: "http://vault.neverland:8200"}},
+		},
+	}
+	dataStore := &app.DataStore{Vault: app.Vault{Address: "http://vault.m4d-system:8200", SecretPath: "/v1/secret"}}
+
+	local := dataStore.DeepCopy()
+	m.setClusterVault(local, "thegreendragon")
+	g.Expect(local.Vault.Address).To(gomega.Equal("http://vault.m4d-system:8200"))
+	g.Expect(local.Vault.AuthPath).To(gomega.Equal("/v1/auth/kubernetes/login"))
+
+	remote := dataStore.DeepCopy()
+	m.setClusterVault(remote, "neverland-cluster")
+	g.Expect(remote.Vault.Address).To(gomega.Equal("http://vault.neverland:8200"))
+	g.Expect(remote.Vault.AuthPath).To(gomega.Equal("/v1/auth/neverland/login"))
+	g.Expect(remote.Vault.SecretPath).To(gomega.Equal("/v1/secret"))
+}
+
+// This test checks that a module is selected for an action only if it supports the arguments of the action
+func TestSupportedActionArgs(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	module := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/copy-db2-parquet.yaml", module)).NotTo(gomega.HaveOccurred())
+	redact := func(args map[string]string) *pb.EnforcementAction {
+		return &pb.EnforcementAction{Id: "redact-ID", Level: pb.EnforcementAction_COLUMN, Args: args}
+	}
+	selector := &modules.Selector{}
+	// without a schema any arguments are supported
+	g.Expect(selector.SupportsGovernanceAction(module, redact(map[string]string{"column_name": "nameOrig", "redaction_char": "#"}))).To(gomega.BeTrue())
+
+	module.Spec.Capabilities.Actions[0].ArgsSchema = serde.NewArbitrary(map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"column_name"},
+		"properties": map[string]interface{}{
+			"redaction_char": map[string]interface{}{"enum": []interface{}{"*", "X"}},
+		},
+	})
+	g.Expect(selector.SupportsGovernanceAction(module, redact(map[string]string{"column_name": "nameOrig"}))).To(gomega.BeTrue())
+	g.Expect(selector.SupportsGovernanceAction(module, redact(map[string]string{"column_name": "nameOrig", "redaction_char": "X"}))).To(gomega.BeTrue())
+	g.Expect(selector.SupportsGovernanceAction(module, redact(map[string]string{"column_name": "nameOrig", "redaction_char": "#"}))).To(gomega.BeFalse())
+	g.Expect(selector.SupportsGovernanceAction(module, redact(nil))).To(gomega.BeFalse())
+	g.Expect(selector.SupportsGovernanceActions(module, []*pb.EnforcementAction{
+		redact(map[string]string{"column_name": "nameOrig"}),
+		{Id: "removed-ID", Level: pb.EnforcementAction_COLUMN, Args: map[string]string{"column_name": "nameDest"}},
+	})).To(gomega.BeTrue())
+}
+
+// This test checks that the selector explains why each module has been rejected
+func TestSelectorRejections(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	copyModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/copy-db2-parquet.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+	moduleMap := map[string]*app.M4DModule{readModule.Name: readModule, copyModule.Name: copyModule}
+
+	selector := &modules.Selector{
+		Flow:        app.Copy,
+		Source:      &app.InterfaceDetails{Protocol: app.JdbcDb2, DataFormat: app.Table},
+		Destination: &app.InterfaceDetails{Protocol: app.S3, DataFormat: app.Parquet},
+		Actions:     []*pb.EnforcementAction{{Id: "encrypted-ID", Level: pb.EnforcementAction_COLUMN}},
+	}
+	g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeFalse())
+	g.Expect(selector.Rejections).To(gomega.Equal([]string{
+		"implicit-copy-batch-db2: does not support the action encrypted-ID at the COLUMN level with the given arguments",
+		"read-parquet: does not support the copy flow",
+	}))
+
+	selector = &modules.Selector{Flow: app.Read, Destination: &app.InterfaceDetails{Protocol: app.S3, DataFormat: "csv"}}
+	g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeFalse())
+	g.Expect(selector.Rejections).To(gomega.ContainElement("read-parquet: exposes no API matching s3/csv"))
+
+	application := &app.M4DApplication{}
+	recordRejections(application, "s3/allow-dataset", selector.Flow, selector.Rejections)
+	g.Expect(application.Status.DebugInfo["s3/allow-dataset"]).To(gomega.ContainElement("read: read-parquet: exposes no API matching s3/csv"))
+
+	// the rejections recorded for the interfaces that are not satisfied are cleared once a fallback interface is
+	recorded := append([]string{}, application.Status.DebugInfo["s3/allow-dataset"]...)
+	clearRejections := recordedRejections(application, "s3/allow-dataset")
+	recordRejections(application, "s3/allow-dataset", app.Write, []string{"write-s3: does not support the write flow"})
+	clearRejections()
+	g.Expect(application.Status.DebugInfo["s3/allow-dataset"]).To(gomega.Equal(recorded))
+	clearRejections = recordedRejections(application, "s3/other-dataset")
+	recordRejections(application, "s3/other-dataset", selector.Flow, selector.Rejections)
+	clearRejections()
+	g.Expect(application.Status.DebugInfo).NotTo(gomega.HaveKey("s3/other-dataset"))
+}
+
+// This test checks that only the module pinned by an administrator may be selected,
+// and that it still has to support the requested interface
+func TestPinnedModule(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	newReadModule := readModule.DeepCopy()
+	newReadModule.Name = "read-parquet-v2"
+	copyModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/copy-db2-parquet.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+	moduleMap := map[string]*app.M4DModule{readModule.Name: readModule, newReadModule.Name: newReadModule, copyModule.Name: copyModule}
+
+	application := &app.M4DApplication{Spec: app.M4DApplicationSpec{
+		PinnedModules: []app.PinnedModule{{DataSetID: "s3/allow-dataset", Flow: app.Read, Module: newReadModule.Name}},
+	}}
+	g.Expect(application.GetPinnedModule("s3/allow-dataset", app.Read)).To(gomega.Equal(newReadModule.Name))
+	g.Expect(application.GetPinnedModule("s3/allow-dataset", app.Copy)).To(gomega.BeEmpty())
+
+	destination := &app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	for i := 0; i < 5; i++ {
+		selector := &modules.Selector{Flow: app.Read, Destination: destination, Pinned: newReadModule.Name}
+		g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeTrue())
+		g.Expect(selector.GetModule().Name).To(gomega.Equal(newReadModule.Name))
+	}
+
+	// a pinned module that does not meet the requirements is not replaced by another module
+	selector := &modules.Selector{Flow: app.Read, Destination: destination, Pinned: copyModule.Name}
+	g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeFalse())
+	g.Expect(selector.Rejections).To(gomega.Equal([]string{"implicit-copy-batch-db2: does not support the read flow"}))
+
+	selector = &modules.Selector{Flow: app.Read, Destination: destination, Pinned: "read-parquet-v3"}
+	g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeFalse())
+	g.Expect(selector.GetError()).To(gomega.ContainSubstring("read-parquet-v3 is not installed"))
+}
+
+// This test checks that the latest version of a module satisfying the version range of its chart is selected,
+// and that the ranges of the application take precedence over the ones set by the administrators
+func TestModuleVersions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	const chart = "ghcr.io/mesh-for-data/arrow-flight-module"
+	repository, tag := modules.ChartVersion(chart + ":0.2.0")
+	g.Expect(repository).To(gomega.Equal(chart))
+	g.Expect(tag).To(gomega.Equal("0.2.0"))
+	repository, tag = modules.ChartVersion("localhost:5000/arrow-flight-module@sha256:0123")
+	g.Expect(repository).To(gomega.Equal("localhost:5000/arrow-flight-module"))
+	g.Expect(tag).To(gomega.BeEmpty())
+	repository, tag = modules.ChartVersion("localhost:5000/arrow-flight-module")
+	g.Expect(repository).To(gomega.Equal("localhost:5000/arrow-flight-module"))
+	g.Expect(tag).To(gomega.BeEmpty())
+
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	moduleMap := map[string]*app.M4DModule{}
+	for name, version := range map[string]string{"arrow-flight-v1": "0.1.0", "arrow-flight-v2": "0.2.0", "arrow-flight-dev": "latest"} {
+		module := readModule.DeepCopy()
+		module.Name = name
+		module.Spec.Chart.Name = chart + ":" + version
+		moduleMap[name] = module
+	}
+	destination := &app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+
+	selector := &modules.Selector{Flow: app.Read, Destination: destination}
+	g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeTrue())
+	g.Expect(selector.GetModule().Name).To(gomega.Equal("arrow-flight-v2"))
+
+	selector = &modules.Selector{Flow: app.Read, Destination: destination, Versions: map[string]string{chart: "<0.2.0"}}
+	g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeTrue())
+	g.Expect(selector.GetModule().Name).To(gomega.Equal("arrow-flight-v1"))
+	g.Expect(selector.Rejections).To(gomega.Equal([]string{"arrow-flight-v2: version 0.2.0 is not in the range <0.2.0"}))
+
+	selector = &modules.Selector{Flow: app.Read, Destination: destination, Versions: map[string]string{chart: ">=0.3.0"}}
+	g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeFalse())
+	g.Expect(selector.Rejections).To(gomega.ContainElement(
+		"arrow-flight-dev: its chart has no semantic version, as required by the range >=0.3.0"))
+
+	// a pinned module is selected regardless of the version ranges
+	selector = &modules.Selector{Flow: app.Read, Destination: destination, Versions: map[string]string{chart: ">=0.3.0"}, Pinned: "arrow-flight-dev"}
+	g.Expect(selector.SelectModule(moduleMap)).To(gomega.BeTrue())
+
+	application := &app.M4DApplication{Spec: app.M4DApplicationSpec{ModuleVersions: map[string]string{chart: "~0.1"}}}
+	g.Expect(moduleVersions(map[string]string{chart: "^0.2", "ghcr.io/mesh-for-data/implicit-copy": "1.x"}, application)).To(gomega.Equal(
+		map[string]string{chart: "~0.1", "ghcr.io/mesh-for-data/implicit-copy": "1.x"}))
+	g.Expect(moduleVersions(nil, &app.M4DApplication{})).To(gomega.BeNil())
+}
+
+// This test checks that the actions that no single module supports are performed by a chain of modules
+// A db2 dataset requiring redaction and encryption, a module redacting db2 data, a module encrypting arrow data,
+// and a read module performing no action
+// Result: the redacting module feeds the encrypting module, which is read by the read module
+func TestTransformationChain(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	db2 := app.InterfaceDetails{Protocol: app.JdbcDb2, DataFormat: app.Table}
+	arrow := app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}
+	newModule := func(name string, source app.InterfaceDetails, actions ...app.SupportedAction) *app.M4DModule {
+		return &app.M4DModule{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: app.M4DModuleSpec{
+				Flows: []app.ModuleFlow{app.Read},
+				Capabilities: app.Capability{
+					API: &app.ModuleAPI{InterfaceDetails: arrow, Endpoint: app.EndpointSpec{Port: 80, Scheme: "grpc"}},
+					SupportedInterfaces: []app.ModuleInOut{
+						{Flow: app.Read, Source: source.DeepCopy()},
+					},
+					Actions: actions,
+				},
+			},
+		}
+	}
+	readModule := newModule("read-arrow", arrow)
+	redactModule := newModule("redact-db2", db2, app.SupportedAction{ID: "redact-ID", Level: pb.EnforcementAction_COLUMN})
+	encryptModule := newModule("encrypt-arrow", arrow, app.SupportedAction{ID: "encrypt-ID", Level: pb.EnforcementAction_COLUMN})
+	m := &ModuleManager{
+		Log:     ctrl.Log.WithName("test"),
+		Modules: map[string]*app.M4DModule{readModule.Name: readModule, redactModule.Name: redactModule, encryptModule.Name: encryptModule},
+	}
+	item := modules.DataInfo{
+		Context:     &app.DataContext{DataSetID: "db2/redact-dataset"},
+		DataDetails: &modules.DataDetails{Interface: db2},
+	}
+	readSelector := &modules.Selector{
+		Flow:   app.Read,
+		Module: readModule,
+		Actions: []*pb.EnforcementAction{
+			{Name: "redact", Id: "redact-ID", Level: pb.EnforcementAction_COLUMN},
+			{Name: "encrypt", Id: "encrypt-ID", Level: pb.EnforcementAction_COLUMN},
+		},
+	}
+	copyRequired, _, actionsOnCopy := m.getCopyRequirements(item, readSelector)
+	g.Expect(copyRequired).To(gomega.BeFalse())
+	g.Expect(actionsOnCopy).To(gomega.BeEmpty())
+	g.Expect(readSelector.Actions).To(gomega.BeEmpty())
+	g.Expect(readSelector.Chain).To(gomega.HaveLen(2))
+	g.Expect(readSelector.Chain[0].Module.Name).To(gomega.Equal(redactModule.Name))
+	g.Expect(readSelector.Chain[0].Actions).To(gomega.HaveLen(1))
+	g.Expect(readSelector.Chain[1].Module.Name).To(gomega.Equal(encryptModule.Name))
+
+	// each module of the chain reads the previous one, and the read module reads the last one
+	application := &app.M4DApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"}}
+	instances := chainedInstances(application, item, readSelector.Chain, app.DataStore{Format: app.Table}, readModule.Name, "cluster1")
+	g.Expect(instances).To(gomega.HaveLen(2))
+	g.Expect(instances[0].ChainedTo).To(gomega.Equal(encryptModule.Name))
+	g.Expect(instances[0].Args.Read[0].Source.Format).To(gomega.Equal(app.Table))
+	g.Expect(instances[1].ChainedTo).To(gomega.Equal(readModule.Name))
+	g.Expect(instances[1].Args.Read[0].Source.Format).To(gomega.Equal(app.Arrow))
+
+	// no chain is built if a module is missing
+	delete(m.Modules, encryptModule.Name)
+	readSelector.Actions = []*pb.EnforcementAction{
+		{Name: "redact", Id: "redact-ID", Level: pb.EnforcementAction_COLUMN},
+		{Name: "encrypt", Id: "encrypt-ID", Level: pb.EnforcementAction_COLUMN},
+	}
+	readSelector.Chain = nil
+	copyRequired, _, actionsOnCopy = m.getCopyRequirements(item, readSelector)
+	g.Expect(copyRequired).To(gomega.BeTrue())
+	g.Expect(actionsOnCopy).To(gomega.HaveLen(2))
+	g.Expect(readSelector.Chain).To(gomega.BeEmpty())
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+)
+
+// OwnedDataset returns the reference of a Dataset resource provisioning storage for an application
+func OwnedDataset(datasetRef string, storageType string) app.OwnedResource {
+	return app.OwnedResource{Kind: storage.DatasetKind, Name: datasetRef, Namespace: utils.GetSystemNamespace(), StorageType: storageType}
+}
+
+// sameResource returns true if two references identify the same resource
+func sameResource(a app.OwnedResource, b app.OwnedResource) bool {
+	return a.Kind == b.Kind && a.Name == b.Name && a.Namespace == b.Namespace
+}
+
+// ContainsResource returns true if the resource is listed
+func ContainsResource(resources []app.OwnedResource, resource app.OwnedResource) bool {
+	for _, listed := range resources {
+		if sameResource(listed, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddOwnedResources adds the resources to the owned resources of the application,
+// and returns true if some of them were not owned yet
+func AddOwnedResources(application *app.M4DApplication, resources ...app.OwnedResource) bool {
+	added := false
+	for _, resource := range resources {
+		if !ContainsResource(application.Status.OwnedResources, resource) {
+			application.Status.OwnedResources = append(application.Status.OwnedResources, resource)
+			added = true
+		}
+	}
+	return added
+}
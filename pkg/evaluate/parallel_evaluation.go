@@ -0,0 +1,215 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"context"
+	"sync"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	connectors "github.com/mesh-for-data/mesh-for-data/pkg/connectors/clients"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/storage"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/protobuf/proto"
+)
+
+// newSemaphore returns a semaphore bounding the number of calls running concurrently during an evaluation.
+// A concurrency lower than 2 makes the calls sequentially, in the order in which the semaphore is acquired.
+func newSemaphore(concurrency int) *semaphore.Weighted {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return semaphore.NewWeighted(int64(concurrency))
+}
+
+// memoPolicyManager memoizes the decisions of the policy manager for the duration of an evaluation.
+// The decisions prefetched concurrently for all the datasets are thus reused when the modules are selected,
+// and a request issued several times during the evaluation (e.g. to negotiate the interface and to select the modules)
+// is sent once. Failed requests are not memoized, they are sent again.
+type memoPolicyManager struct {
+	connectors.PolicyManager
+	mutex     sync.Mutex
+	decisions map[string]*pb.PoliciesDecisions
+}
+
+func newMemoPolicyManager(policyManager connectors.PolicyManager) *memoPolicyManager {
+	return &memoPolicyManager{PolicyManager: policyManager, decisions: make(map[string]*pb.PoliciesDecisions)}
+}
+
+// GetPoliciesDecisions returns the memoized decisions for the request, or forwards the request to the policy manager
+func (m *memoPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	bytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(in)
+	if err != nil {
+		return m.PolicyManager.GetPoliciesDecisions(ctx, in)
+	}
+	key := string(bytes)
+	m.mutex.Lock()
+	decisions, found := m.decisions[key]
+	m.mutex.Unlock()
+	if found {
+		return decisions, nil
+	}
+	decisions, err = m.PolicyManager.GetPoliciesDecisions(ctx, in)
+	if err != nil {
+		return decisions, err
+	}
+	m.mutex.Lock()
+	m.decisions[key] = decisions
+	m.mutex.Unlock()
+	return decisions, nil
+}
+
+// prefetchPolicyDecisions queries the policy manager concurrently for the operations in the workload geography,
+// or in the geography required for a dataset, that the module selection of the datasets starts with, i.e. reading
+// the datasets or writing them.
+// The answers are memoized by the policy manager of the module manager, which must be a memoPolicyManager,
+// and reused when the interfaces are negotiated and the modules are selected.
+// Nothing is prefetched if the workload geography can not be determined, in which case the selection reports the error.
+func (m *ModuleManager) prefetchPolicyDecisions(requirements []modules.DataInfo, application *app.M4DApplication, concurrency int) {
+	// the datasets are neither read nor written without a workload
+	if concurrency < 2 || application.Spec.Selector.WorkloadSelector.Size() == 0 {
+		return
+	}
+	geography, err := m.GetProcessingGeography(application)
+	if err != nil {
+		return
+	}
+	var group errgroup.Group
+	slots := newSemaphore(concurrency)
+	for i := range requirements {
+		item := requirements[i]
+		operation := &pb.AccessOperation{Type: pb.AccessOperation_READ, Destination: geography}
+		if item.Context.Requirements.Geography != "" {
+			operation.Destination = item.Context.Requirements.Geography
+		}
+		if item.Context.Flow == app.Write {
+			operation.Type = pb.AccessOperation_WRITE
+		}
+		_ = slots.Acquire(context.Background(), 1)
+		group.Go(func() error {
+			defer slots.Release(1)
+			_, err := m.PolicyManager.GetPoliciesDecisions(context.Background(), ConstructApplicationContext(item.Context.DataSetID, application, operation))
+			return err
+		})
+	}
+	// the failed requests are sent again by the module selection, which reports their errors
+	_ = group.Wait()
+}
+
+// moduleSelection is the outcome of the selection of the modules of a dataset in a flow. The selections of the datasets
+// are made concurrently, each of them on its own copies of the application and of the module manager, in which it
+// records its side effects until they are joined in the order of the datasets.
+type moduleSelection struct {
+	instances []modules.ModuleInstanceSpec
+	fallback  *app.InterfaceDetails
+	err       error
+	// application is the copy of the application in whose status the selection records its outcome
+	application *app.M4DApplication
+	// manager is the copy of the module manager in which the selection records the storage of the copies
+	manager *ModuleManager
+}
+
+// selectModuleInstances selects the modules of the datasets concurrently, with at most concurrency selections running
+// at a time, except for the datasets whose previous selection is reused, i.e. whose reused entry is set.
+// The selections must then be joined in the order of the datasets.
+func (m *ModuleManager) selectModuleInstances(requirements []modules.DataInfo, reused []*cachedSelection,
+	application *app.M4DApplication, concurrency int) []*moduleSelection {
+	// the cluster scoring is read once, before it is shared by the selections
+	m.getClusterScoring()
+	selections := make([]*moduleSelection, len(requirements))
+	var group errgroup.Group
+	slots := newSemaphore(concurrency)
+	for i := range requirements {
+		if reused[i] != nil {
+			continue
+		}
+		item := requirements[i]
+		selection := &moduleSelection{application: forkApplication(application), manager: m.fork()}
+		selections[i] = selection
+		_ = slots.Acquire(context.Background(), 1)
+		group.Go(func() error {
+			defer slots.Release(1)
+			selection.instances, selection.fallback, selection.err = selection.manager.SelectModuleInstancesWithFallbacks(item, selection.application)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return selections
+}
+
+// forkApplication returns a copy of the application whose status records the outcome of a single module selection
+func forkApplication(application *app.M4DApplication) *app.M4DApplication {
+	forked := application.DeepCopy()
+	forked.Status.AssetStates = nil
+	forked.Status.MismatchedPerformanceClasses = nil
+	forked.Status.GrantedCopies = nil
+	forked.Status.DebugInfo = nil
+	return forked
+}
+
+// fork returns a copy of the module manager for a single module selection
+func (m *ModuleManager) fork() *ModuleManager {
+	forked := *m
+	forked.ProvisionedStorage = make(map[string]NewAssetInfo, len(m.ProvisionedStorage))
+	for datasetID, asset := range m.ProvisionedStorage {
+		forked.ProvisionedStorage[datasetID] = asset
+	}
+	forked.pendingDatasets = nil
+	forked.residencyChecks = make(map[string]error, len(m.residencyChecks))
+	for key, err := range m.residencyChecks {
+		forked.residencyChecks[key] = err
+	}
+	forked.requiredTags = nil
+	forked.policyManagerUnavailable = false
+	return &forked
+}
+
+// join records the side effects of a module selection in the module manager and in the status of the application
+func (m *ModuleManager) join(selection *moduleSelection, application *app.M4DApplication) {
+	forked := selection.manager
+	for datasetID, request := range forked.pendingDatasets {
+		if m.pendingDatasets == nil {
+			m.pendingDatasets = make(map[string]storage.DatasetRequest)
+		}
+		m.pendingDatasets[datasetID] = request
+	}
+	for datasetID, asset := range forked.ProvisionedStorage {
+		m.ProvisionedStorage[datasetID] = asset
+	}
+	for datasetID, tags := range forked.requiredTags {
+		if m.requiredTags == nil {
+			m.requiredTags = make(map[string]map[string]string)
+		}
+		m.requiredTags[datasetID] = tags
+	}
+	m.policyManagerUnavailable = m.policyManagerUnavailable || forked.policyManagerUnavailable
+
+	status := &selection.application.Status
+	for datasetID, state := range status.AssetStates {
+		for _, action := range state.Actions {
+			addAssetAction(application, datasetID, action)
+		}
+	}
+	for key, mismatch := range status.MismatchedPerformanceClasses {
+		if application.Status.MismatchedPerformanceClasses == nil {
+			application.Status.MismatchedPerformanceClasses = make(map[string]string)
+		}
+		application.Status.MismatchedPerformanceClasses[key] = mismatch
+	}
+	for key, grantedCopy := range status.GrantedCopies {
+		if application.Status.GrantedCopies == nil {
+			application.Status.GrantedCopies = make(map[string]app.GrantedCopy)
+		}
+		application.Status.GrantedCopies[key] = grantedCopy
+	}
+	for datasetID, rejections := range status.DebugInfo {
+		if application.Status.DebugInfo == nil {
+			application.Status.DebugInfo = make(map[string][]string)
+		}
+		application.Status.DebugInfo[datasetID] = append(application.Status.DebugInfo[datasetID], rejections...)
+	}
+}
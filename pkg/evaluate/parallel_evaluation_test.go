@@ -0,0 +1,136 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// slowPolicyManager allows all the operations after a delay, and records the maximal number of concurrent requests
+type slowPolicyManager struct {
+	mockup.MockPolicyManager
+	mutex       sync.Mutex
+	inFlight    int
+	maxInFlight int
+	requests    map[string]int
+}
+
+func (m *slowPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	m.mutex.Lock()
+	m.inFlight++
+	if m.inFlight > m.maxInFlight {
+		m.maxInFlight = m.inFlight
+	}
+	for _, dataset := range in.GetDatasets() {
+		m.requests[dataset.GetDataset().GetDatasetId()+"/"+dataset.GetOperation().GetType().String()]++
+	}
+	m.mutex.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	m.mutex.Lock()
+	m.inFlight--
+	m.mutex.Unlock()
+	decisions := &pb.PoliciesDecisions{}
+	for _, dataset := range in.GetDatasets() {
+		decisions.DatasetDecisions = append(decisions.DatasetDecisions, &pb.DatasetDecision{
+			Dataset: dataset.GetDataset(),
+			Decisions: []*pb.OperationDecision{{
+				Operation:          dataset.GetOperation(),
+				EnforcementActions: []*pb.EnforcementAction{{Name: "Allow", Id: "Allow-ID"}},
+			}},
+		})
+	}
+	return decisions, nil
+}
+
+// failingPolicyManager fails the first request, and allows the operations afterwards
+type failingPolicyManager struct {
+	slowPolicyManager
+	calls int
+}
+
+func (m *failingPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	m.calls++
+	if m.calls == 1 {
+		return nil, errors.New("policy manager is unavailable")
+	}
+	return m.slowPolicyManager.GetPoliciesDecisions(ctx, in)
+}
+
+// TestMemoPolicyManager checks that the decisions are memoized, and that the failed requests are sent again
+func TestMemoPolicyManager(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	policyManager := &failingPolicyManager{slowPolicyManager: slowPolicyManager{requests: map[string]int{}}}
+	memo := newMemoPolicyManager(policyManager)
+	operation := &pb.AccessOperation{Type: pb.AccessOperation_READ, Destination: "Germany"}
+	in := ConstructApplicationContext("s3/dataset", &app.M4DApplication{}, operation)
+
+	_, err := memo.GetPoliciesDecisions(context.Background(), in)
+	g.Expect(err).To(gomega.HaveOccurred())
+	decisions, err := memo.GetPoliciesDecisions(context.Background(), in)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(decisions.GetDatasetDecisions()).To(gomega.HaveLen(1))
+	memoized, err := memo.GetPoliciesDecisions(context.Background(), in)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(memoized).To(gomega.BeIdenticalTo(decisions))
+	g.Expect(policyManager.calls).To(gomega.Equal(2))
+}
+
+// TestEvaluateConcurrently checks that the policy decisions of the datasets are requested concurrently,
+// once per operation, and that the modules selected concurrently are the same as when the datasets are evaluated sequentially
+func TestEvaluateConcurrently(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = nil
+	for i := 0; i < 6; i++ {
+		application.Spec.Data = append(application.Spec.Data, app.DataContext{
+			DataSetID:    fmt.Sprintf("s3/dataset-%d", i),
+			Requirements: app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+		})
+	}
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), readModule)
+
+	evaluate := func(concurrency int) ([]string, *slowPolicyManager) {
+		policyManager := &slowPolicyManager{requests: map[string]int{}}
+		evaluator := NewEvaluator(cl, policyManager, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+		evaluator.Concurrency = concurrency
+		evaluated := application.DeepCopy()
+		evaluation, err := evaluator.Evaluate(evaluated)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(HasError(evaluated)).To(gomega.BeFalse(), GetErrorMessages(evaluated))
+		var assets []string
+		for _, instance := range evaluation.Instances {
+			assets = append(assets, instance.AssetID)
+		}
+		return assets, policyManager
+	}
+
+	sequential, policyManager := evaluate(1)
+	g.Expect(policyManager.maxInFlight).To(gomega.Equal(1))
+	concurrent, policyManager := evaluate(4)
+	g.Expect(concurrent).To(gomega.Equal(sequential))
+	g.Expect(policyManager.maxInFlight).To(gomega.BeNumerically(">", 1))
+	g.Expect(policyManager.maxInFlight).To(gomega.BeNumerically("<=", 4))
+	for _, dataset := range application.Spec.Data {
+		g.Expect(policyManager.requests).To(gomega.HaveKeyWithValue(dataset.DataSetID+"/READ", 1))
+	}
+}
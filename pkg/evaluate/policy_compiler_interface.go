@@ -1,10 +1,11 @@
 // Copyright 2020 IBM Corp.
 // SPDX-License-Identifier: Apache-2.0
 
-package app
+package evaluate
 
 import (
 	"context"
+	"time"
 
 	"emperror.dev/errors"
 	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
@@ -20,10 +21,18 @@ func ConstructApplicationContext(datasetID string, input *app.M4DApplication, op
 	if input.Spec.SecretRef != "" {
 		credentialPath = utils.GetVaultAddress() + vault.PathForReadingKubeSecret(input.Namespace, input.Spec.SecretRef)
 	}
+	properties := input.Spec.AppInfo
+	if privacyLevel := privacyLevelOf(input, datasetID); privacyLevel != "" && operation.Type == pb.AccessOperation_READ {
+		properties = make(map[string]string, len(input.Spec.AppInfo)+1)
+		for key, value := range input.Spec.AppInfo {
+			properties[key] = value
+		}
+		properties[PrivacyLevelProperty] = privacyLevel
+	}
 	return &pb.ApplicationContext{
 		AppInfo: &pb.ApplicationDetails{
 			ProcessingGeography: operation.Destination,
-			Properties:          input.Spec.AppInfo,
+			Properties:          properties,
 		},
 		CredentialPath: credentialPath,
 		Datasets: []*pb.DatasetContext{{
@@ -35,11 +44,29 @@ func ConstructApplicationContext(datasetID string, input *app.M4DApplication, op
 	}
 }
 
+// policyDeniedError is returned when the governance policies deny an operation on a dataset.
+// Its message is either ReadAccessDenied or WriteNotAllowed.
+type policyDeniedError struct {
+	message string
+}
+
+func (e *policyDeniedError) Error() string {
+	return e.message
+}
+
+// isPolicyDenied returns true if the error reports the denial of an operation by the governance policies
+func isPolicyDenied(err error) bool {
+	var denied *policyDeniedError
+	return errors.As(err, &denied)
+}
+
 // LookupPolicyDecisions provides a list of governance actions for the given dataset and the given operation
 func LookupPolicyDecisions(datasetID string, policyManager connectors.PolicyManager, input *app.M4DApplication, op *pb.AccessOperation) ([]*pb.EnforcementAction, error) {
 	// call external policy manager to get governance instructions for this operation
 	appContext := ConstructApplicationContext(datasetID, input, op)
+	start := time.Now()
 	pcresponse, err := policyManager.GetPoliciesDecisions(context.Background(), appContext)
+	ObserveConnectorLatency(PolicyConnector, "GetPoliciesDecisions", start)
 	actions := []*pb.EnforcementAction{}
 	if err != nil {
 		return actions, err
@@ -61,7 +88,7 @@ func LookupPolicyDecisions(datasetID string, policyManager connectors.PolicyMana
 					case pb.AccessOperation_WRITE:
 						message = app.WriteNotAllowed
 					}
-					return actions, errors.New(message)
+					return actions, &policyDeniedError{message: message}
 				}
 				// Check if this is a real action (i.e. not Allow)
 				if utils.IsAction(action.GetName()) {
@@ -0,0 +1,91 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"fmt"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// PrivacyLevelProperty is the application property in which the target privacy level of a dataset is passed
+// to the policy manager, whose policies may require further actions to achieve it
+const PrivacyLevelProperty = "privacyLevel"
+
+// privacyLevelOf returns the target privacy level of the dataset, or an empty string if none is requested
+func privacyLevelOf(application *app.M4DApplication, datasetID string) string {
+	for i := range application.Spec.Data {
+		if application.Spec.Data[i].DataSetID == datasetID {
+			return application.Spec.Data[i].Requirements.PrivacyLevel
+		}
+	}
+	return ""
+}
+
+// translatePrivacyLevels computes the actions achieving the target privacy levels of the datasets that are read,
+// according to the privacy levels taxonomy and the column tags in the data catalog.
+// The datasets whose privacy level can not be translated are marked with an error condition.
+func (m *ModuleManager) translatePrivacyLevels(requirements []modules.DataInfo, application *app.M4DApplication) {
+	m.privacyActions = make(map[string][]*pb.EnforcementAction)
+	for _, item := range requirements {
+		privacyLevel := item.Context.Requirements.PrivacyLevel
+		if privacyLevel == "" || item.Context.Flow == app.Write {
+			continue
+		}
+		actions, err := m.privacyLevelActions(privacyLevel, item.DataDetails.Metadata)
+		if err != nil {
+			SetCondition(application, item.Context.DataSetID, err.Error(), true)
+			continue
+		}
+		m.privacyActions[item.Context.DataSetID] = actions
+	}
+}
+
+// privacyLevelActions returns the actions achieving the privacy level for a dataset with the given metadata.
+// An error is returned if an action is not defined by the action taxonomy.
+func (m *ModuleManager) privacyLevelActions(privacyLevel string, metadata *pb.DatasetMetadata) ([]*pb.EnforcementAction, error) {
+	actions, err := m.PrivacyLevels.Actions(privacyLevel, metadata)
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		if err := checkAction(m.ActionTaxonomy, action.Id, action.Level); err != nil {
+			return nil, fmt.Errorf("%s for privacy level %s", err.Error(), privacyLevel)
+		}
+	}
+	return actions, nil
+}
+
+// withPrivacyActions adds the actions achieving the target privacy level of the dataset to the actions required
+// by the governance policies, unless the policies already require them
+func (m *ModuleManager) withPrivacyActions(datasetID string, actions []*pb.EnforcementAction) []*pb.EnforcementAction {
+	for _, privacyAction := range m.privacyActions[datasetID] {
+		required := false
+		for _, action := range actions {
+			if action.Id == privacyAction.Id && action.Level == privacyAction.Level && sameArgs(action.Args, privacyAction.Args) {
+				required = true
+				break
+			}
+		}
+		if !required {
+			actions = append(actions, privacyAction)
+		}
+	}
+	return actions
+}
+
+// sameArgs returns true if the action arguments are equal, a nil map being equal to an empty one
+func sameArgs(args map[string]string, other map[string]string) bool {
+	if len(args) != len(other) {
+		return false
+	}
+	for key, value := range args {
+		if otherValue, found := other[key]; !found || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/taxonomy"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// propertiesPolicyManager records the application properties passed to the policy manager
+type propertiesPolicyManager struct {
+	mockup.MockPolicyManager
+	properties []map[string]string
+}
+
+func (m *propertiesPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	m.properties = append(m.properties, in.GetAppInfo().GetProperties())
+	return m.MockPolicyManager.GetPoliciesDecisions(ctx, in)
+}
+
+// TestPrivacyLevels checks that the target privacy level of a dataset is translated into the actions achieving it,
+// which are required on top of the actions of the governance policies when the dataset is read
+func TestPrivacyLevels(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Spec: app.M4DApplicationSpec{
+			AppInfo: map[string]string{"intent": "Fraud Detection"},
+			Data: []app.DataContext{
+				{DataSetID: "s3/allow-dataset", Requirements: app.DataRequirements{PrivacyLevel: "anonymized"}},
+				{DataSetID: "s3/other-dataset", Requirements: app.DataRequirements{PrivacyLevel: "unknown"}},
+			},
+		},
+	}
+	metadata := &pb.DatasetMetadata{ComponentsMetadata: map[string]*pb.DataComponentMetadata{
+		"nameOrig": {ComponentType: "column", Tags: []string{"PII"}},
+		"amount":   {ComponentType: "column"},
+	}}
+	requirements := []modules.DataInfo{
+		{Context: &application.Spec.Data[0], DataDetails: &modules.DataDetails{Metadata: metadata}},
+		{Context: &application.Spec.Data[1], DataDetails: &modules.DataDetails{Metadata: metadata}},
+	}
+	policyManager := &propertiesPolicyManager{}
+	m := &ModuleManager{
+		PolicyManager: policyManager,
+		PrivacyLevels: taxonomy.PrivacyLevels{
+			"anonymized": {{ID: "redact-ID", Name: "redact", Level: "COLUMN", Tags: []string{"PII"}}},
+		},
+	}
+	m.translatePrivacyLevels(requirements, application)
+	g.Expect(HasError(application)).To(gomega.BeTrue(), "the privacy level of s3/other-dataset is not defined")
+	g.Expect(m.privacyActions).NotTo(gomega.HaveKey("s3/other-dataset"))
+
+	actions, err := m.lookupPolicyDecisions("s3/allow-dataset", application, &pb.AccessOperation{Type: pb.AccessOperation_READ})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(actions).To(gomega.HaveLen(1))
+	g.Expect(actions[0].Id).To(gomega.Equal("redact-ID"))
+	g.Expect(actions[0].Args).To(gomega.Equal(map[string]string{"column_name": "nameOrig"}))
+	g.Expect(policyManager.properties[0]).To(gomega.HaveKeyWithValue(PrivacyLevelProperty, "anonymized"))
+	g.Expect(application.Spec.AppInfo).NotTo(gomega.HaveKey(PrivacyLevelProperty))
+
+	// actions already required by the policies are not duplicated
+	g.Expect(m.withPrivacyActions("s3/allow-dataset", actions)).To(gomega.HaveLen(1))
+
+	// the privacy level does not apply to written data
+	actions, err = m.lookupPolicyDecisions("s3/allow-dataset", application, &pb.AccessOperation{Type: pb.AccessOperation_WRITE})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(actions).To(gomega.BeEmpty())
+	g.Expect(policyManager.properties[1]).NotTo(gomega.HaveKey(PrivacyLevelProperty))
+}
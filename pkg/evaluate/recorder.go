@@ -0,0 +1,35 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+)
+
+// Reasons of the Kubernetes events recorded for M4DApplications while they are evaluated, shown by kubectl describe
+const (
+	// CatalogLookupFailedReason is recorded when the details of a dataset can not be obtained from the data catalog
+	CatalogLookupFailedReason string = "CatalogLookupFailed"
+	// AccessDeniedReason is recorded when the governance policies deny an operation on a dataset
+	AccessDeniedReason string = "AccessDenied"
+	// ModuleSelectedReason is recorded when the modules serving a dataset have been selected
+	ModuleSelectedReason string = "ModuleSelected"
+)
+
+// instanceModules lists the names of the modules of the instances, sorted and without duplicates
+func instanceModules(instances []modules.ModuleInstanceSpec) string {
+	names := make([]string, 0, len(instances))
+	seen := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		if name := instance.Module.GetName(); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"strings"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+)
+
+// registryMirrors returns the registry mirrors of each cluster mapped by the cluster name
+func registryMirrors(clusters []multicluster.Cluster) map[string]map[string]string {
+	mirrors := make(map[string]map[string]string)
+	for _, cluster := range clusters {
+		if len(cluster.Metadata.RegistryMirrors) > 0 {
+			mirrors[cluster.Name] = cluster.Metadata.RegistryMirrors
+		}
+	}
+	return mirrors
+}
+
+// mirrorReference replaces the longest registry prefix of the reference that has a mirror by the prefix of the mirror
+func mirrorReference(reference string, mirrors map[string]string) string {
+	longest := ""
+	for prefix := range mirrors {
+		if strings.HasPrefix(reference, prefix) && len(prefix) > len(longest) {
+			longest = prefix
+		}
+	}
+	if longest == "" {
+		return reference
+	}
+	return mirrors[longest] + strings.TrimPrefix(reference, longest)
+}
+
+// mirrorChart returns the chart of a module as pulled by a cluster with the given registry mirrors.
+// Both the chart and the chart values that reference a mirrored registry, such as images, are rewritten.
+func mirrorChart(chart app.ChartSpec, mirrors map[string]string) app.ChartSpec {
+	if len(mirrors) == 0 {
+		return chart
+	}
+	mirrored := *chart.DeepCopy()
+	mirrored.Name = mirrorReference(chart.Name, mirrors)
+	for key, value := range mirrored.Values {
+		mirrored.Values[key] = mirrorReference(value, mirrors)
+	}
+	if mirrored.Overrides != nil {
+		mirrored.Overrides.Data = mirrorValues(mirrored.Overrides.Data, mirrors)
+	}
+	return mirrored
+}
+
+// mirrorValues rewrites the strings of structured values that reference a mirrored registry
+func mirrorValues(value interface{}, mirrors map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return mirrorReference(v, mirrors)
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = mirrorValues(nested, mirrors)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = mirrorValues(nested, mirrors)
+		}
+	}
+	return value
+}
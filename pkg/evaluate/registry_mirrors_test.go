@@ -0,0 +1,43 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+	"github.com/onsi/gomega"
+)
+
+func TestMirrorChart(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	chart := app.ChartSpec{
+		Name:   "ghcr.io/mesh-for-data/m4d-implicit-copy-batch:0.1.0",
+		Values: map[string]string{"image": "ghcr.io/mesh-for-data/mover:latest", "image.pullPolicy": "Always"},
+		Overrides: serde.NewArbitrary(map[string]interface{}{
+			"sidecar": map[string]interface{}{"images": []interface{}{"docker.io/library/busybox:1.33"}},
+		}),
+	}
+	mirrors := map[string]string{
+		"ghcr.io/":               "mirror.local/ghcr/",
+		"ghcr.io/mesh-for-data/": "mirror.local/m4d/",
+		"docker.io/library/":     "mirror.local/library/",
+	}
+	mirrored := mirrorChart(chart, mirrors)
+	g.Expect(mirrored.Name).To(gomega.Equal("mirror.local/m4d/m4d-implicit-copy-batch:0.1.0"))
+	g.Expect(mirrored.Values).To(gomega.HaveKeyWithValue("image", "mirror.local/m4d/mover:latest"))
+	g.Expect(mirrored.Values).To(gomega.HaveKeyWithValue("image.pullPolicy", "Always"))
+	g.Expect(mirrored.Overrides.Data).To(gomega.Equal(map[string]interface{}{
+		"sidecar": map[string]interface{}{"images": []interface{}{"mirror.local/library/busybox:1.33"}},
+	}))
+	// the chart of the module is not modified
+	g.Expect(chart.Values).To(gomega.HaveKeyWithValue("image", "ghcr.io/mesh-for-data/mover:latest"))
+	g.Expect(chart.Overrides.Data).To(gomega.HaveKeyWithValue("sidecar",
+		map[string]interface{}{"images": []interface{}{"docker.io/library/busybox:1.33"}}))
+
+	g.Expect(mirrorChart(chart, nil)).To(gomega.Equal(chart))
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"strings"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// stepResources returns the compute resources requested by the pods of a module instance serving the given datasets,
+// separated by commas. The defaults of the module are overridden by the requirements of the datasets in the application.
+// If the datasets of a united instance override the same resource, the largest quantity is used.
+// nil is returned if nothing is set.
+func stepResources(module *app.M4DModule, appContext *app.M4DApplication, assetIDs string) *corev1.ResourceRequirements {
+	var overrides []*corev1.ResourceRequirements
+	for _, datasetID := range strings.Split(assetIDs, ",") {
+		for i := range appContext.Spec.Data {
+			dataCtx := &appContext.Spec.Data[i]
+			if dataCtx.DataSetID == datasetID && dataCtx.Requirements.Resources != nil {
+				overrides = append(overrides, dataCtx.Requirements.Resources)
+			}
+		}
+	}
+	if module.Spec.Resources == nil && len(overrides) == 0 {
+		return nil
+	}
+	resources := &corev1.ResourceRequirements{}
+	if module.Spec.Resources != nil {
+		resources = module.Spec.Resources.DeepCopy()
+	}
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+	for _, override := range overrides {
+		maxResources(requests, override.Requests)
+		maxResources(limits, override.Limits)
+	}
+	resources.Requests = overrideResources(resources.Requests, requests)
+	resources.Limits = overrideResources(resources.Limits, limits)
+	return resources
+}
+
+// maxResources sets each resource of the list to the largest of its quantity and the one in the other list
+func maxResources(list corev1.ResourceList, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, found := list[name]; !found || quantity.Cmp(current) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// overrideResources returns the defaults with the quantities of the overriding resources
+func overrideResources(defaults corev1.ResourceList, overrides corev1.ResourceList) corev1.ResourceList {
+	if len(overrides) == 0 {
+		return defaults
+	}
+	if defaults == nil {
+		defaults = corev1.ResourceList{}
+	}
+	for name, quantity := range overrides {
+		defaults[name] = quantity
+	}
+	return defaults
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestStepResources(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	module := &app.M4DModule{Spec: app.M4DModuleSpec{Resources: &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+	}}}
+	appContext := &app.M4DApplication{Spec: app.M4DApplicationSpec{Data: []app.DataContext{
+		{DataSetID: "s3/small-dataset"},
+		{DataSetID: "s3/large-dataset", Requirements: app.DataRequirements{Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+		}}},
+		{DataSetID: "s3/medium-dataset", Requirements: app.DataRequirements{Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		}}},
+	}}}
+
+	// the defaults of the module are used if the dataset does not override them
+	g.Expect(stepResources(module, appContext, "s3/small-dataset")).To(gomega.Equal(module.Spec.Resources))
+
+	resources := stepResources(module, appContext, "s3/small-dataset,s3/medium-dataset,s3/large-dataset")
+	g.Expect(resources.Requests.Cpu().String()).To(gomega.Equal("2"))
+	g.Expect(resources.Requests.Memory().String()).To(gomega.Equal("256Mi"))
+	g.Expect(resources.Limits.Memory().String()).To(gomega.Equal("4Gi"))
+	// the module defaults are not modified
+	g.Expect(module.Spec.Resources.Requests.Cpu().String()).To(gomega.Equal("100m"))
+
+	resources = stepResources(&app.M4DModule{}, appContext, "s3/medium-dataset")
+	g.Expect(resources.Requests.Cpu().String()).To(gomega.Equal("1"))
+	g.Expect(resources.Limits).To(gomega.BeNil())
+	g.Expect(stepResources(&app.M4DModule{}, appContext, "s3/small-dataset")).To(gomega.BeNil())
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"encoding/json"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+)
+
+// AdminScheduling returns the scheduling of the pods of the modules set by the administrators, per capability.
+// It is called once when the manager starts. An invalid scheduling is logged and ignored.
+func AdminScheduling(log logr.Logger) map[app.ModuleFlow]app.SchedulingPolicy {
+	scheduling, err := parseScheduling(utils.GetModulesScheduling())
+	if err != nil {
+		log.Error(err, "the scheduling of the modules set by the administrators is ignored")
+		return nil
+	}
+	return scheduling
+}
+
+// parseScheduling parses the scheduling of the pods of the modules as a JSON object mapping the capabilities to their scheduling
+func parseScheduling(config string) (map[app.ModuleFlow]app.SchedulingPolicy, error) {
+	if config == "" {
+		return nil, nil
+	}
+	scheduling := map[app.ModuleFlow]app.SchedulingPolicy{}
+	if err := json.Unmarshal([]byte(config), &scheduling); err != nil {
+		return nil, errors.Wrap(err, "invalid scheduling of the modules")
+	}
+	return scheduling, nil
+}
+
+// ArgumentsFlow returns the capability in which a module is used according to its arguments
+func ArgumentsFlow(args *app.ModuleArguments) app.ModuleFlow {
+	switch {
+	case args.Copy != nil:
+		return app.Copy
+	case len(args.Write) > 0:
+		return app.Write
+	default:
+		return app.Read
+	}
+}
+
+// stepScheduling returns the scheduling of the pods of a module used in the given capability.
+// Each setting of the administrators overrides the one of the module. nil is returned if nothing is set.
+func stepScheduling(module *app.M4DModule, flow app.ModuleFlow, admin map[app.ModuleFlow]app.SchedulingPolicy) *app.SchedulingPolicy {
+	moduleScheduling, moduleSet := module.Spec.Scheduling[flow]
+	adminPolicy, adminSet := admin[flow]
+	if !moduleSet && !adminSet {
+		return nil
+	}
+	scheduling := moduleScheduling.DeepCopy()
+	if adminPolicy.PriorityClassName != "" {
+		scheduling.PriorityClassName = adminPolicy.PriorityClassName
+	}
+	if adminPolicy.SchedulerName != "" {
+		scheduling.SchedulerName = adminPolicy.SchedulerName
+	}
+	if len(adminPolicy.TopologySpreadConstraints) > 0 {
+		scheduling.TopologySpreadConstraints = adminPolicy.DeepCopy().TopologySpreadConstraints
+	}
+	return scheduling
+}
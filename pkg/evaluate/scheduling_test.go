@@ -0,0 +1,60 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"os"
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestStepScheduling(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	spread := []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway}}
+	module := &app.M4DModule{Spec: app.M4DModuleSpec{Scheduling: map[app.ModuleFlow]app.SchedulingPolicy{
+		app.Copy: {PriorityClassName: "batch", SchedulerName: "volcano"},
+		app.Read: {PriorityClassName: "interactive", TopologySpreadConstraints: spread},
+	}}}
+	admin := map[app.ModuleFlow]app.SchedulingPolicy{
+		app.Copy:  {PriorityClassName: "m4d-batch-low"},
+		app.Write: {SchedulerName: "custom-scheduler"},
+	}
+
+	// the settings of the administrators override the ones of the module
+	g.Expect(stepScheduling(module, app.Copy, admin)).To(gomega.Equal(&app.SchedulingPolicy{PriorityClassName: "m4d-batch-low", SchedulerName: "volcano"}))
+	g.Expect(stepScheduling(module, app.Read, admin)).To(gomega.Equal(&app.SchedulingPolicy{PriorityClassName: "interactive", TopologySpreadConstraints: spread}))
+	g.Expect(stepScheduling(module, app.Write, admin)).To(gomega.Equal(&app.SchedulingPolicy{SchedulerName: "custom-scheduler"}))
+	g.Expect(stepScheduling(module, app.Write, nil)).To(gomega.BeNil())
+
+	g.Expect(ArgumentsFlow(&app.ModuleArguments{Copy: &app.CopyModuleArgs{}})).To(gomega.Equal(app.Copy))
+	g.Expect(ArgumentsFlow(&app.ModuleArguments{Write: []app.WriteModuleArgs{{}}})).To(gomega.Equal(app.Write))
+	g.Expect(ArgumentsFlow(&app.ModuleArguments{Read: []app.ReadModuleArgs{{}}})).To(gomega.Equal(app.Read))
+}
+
+// TestAdminScheduling modifies the environment and thus does not run in parallel.
+func TestAdminScheduling(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	defer os.Unsetenv(utils.ModulesSchedulingKey)
+	log := ctrl.Log.WithName("test")
+
+	g.Expect(AdminScheduling(log)).To(gomega.BeNil())
+
+	g.Expect(os.Setenv(utils.ModulesSchedulingKey, `{"copy": {"priorityClassName": "m4d-batch-low"}}`)).To(gomega.Succeed())
+	g.Expect(AdminScheduling(log)).To(gomega.Equal(map[app.ModuleFlow]app.SchedulingPolicy{
+		app.Copy: {PriorityClassName: "m4d-batch-low"},
+	}))
+
+	// an invalid scheduling is ignored
+	g.Expect(os.Setenv(utils.ModulesSchedulingKey, `{"copy": "low"}`)).To(gomega.Succeed())
+	g.Expect(AdminScheduling(log)).To(gomega.BeNil())
+	_, err := parseScheduling(`{"copy": "low"}`)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
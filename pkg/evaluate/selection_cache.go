@@ -0,0 +1,154 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SelectionCache keeps the modules selected for the datasets of the applications, so that the selection is not
+// computed again for the datasets whose inputs are unchanged, e.g. when only one of many datasets of an application changes.
+// The inputs are digested from the catalog metadata of the dataset, its requirements, the rest of the application spec,
+// the installed modules and the clusters. The governance decisions are not part of the inputs: selections expire
+// after the TTL of the cached policy decisions, and are invalidated together with them when the policies change.
+type SelectionCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mutex   sync.Mutex
+	entries map[selectionKey]*cachedSelection
+}
+
+type selectionKey struct {
+	application types.NamespacedName
+	datasetID   string
+	// flow distinguishes the selections of a dataset that is both read and written
+	flow app.ModuleFlow
+}
+
+// cachedSelection is the outcome of selecting the modules for a dataset
+type cachedSelection struct {
+	digest    string
+	instances []modules.ModuleInstanceSpec
+	// fallback is the interface negotiated when the requested interface could not be served
+	fallback *app.InterfaceDetails
+	// classMismatch is the performance class of the read module if it differs from the requested one
+	classMismatch string
+	expires       time.Time
+}
+
+// NewSelectionCache creates a cache of module selections expiring after the TTL
+func NewSelectionCache(ttl time.Duration) *SelectionCache {
+	return &SelectionCache{ttl: ttl, now: time.Now, entries: make(map[selectionKey]*cachedSelection)}
+}
+
+// get returns the selection cached for the flow of the dataset of the application if its inputs have the given digest
+func (c *SelectionCache) get(application types.NamespacedName, datasetID string, flow app.ModuleFlow, digest string) (*cachedSelection, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, found := c.entries[selectionKey{application: application, datasetID: datasetID, flow: flow}]
+	if !found || entry.digest != digest || !c.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// put caches the selection for the flow of the dataset of the application, replacing the previous one
+func (c *SelectionCache) put(application types.NamespacedName, datasetID string, flow app.ModuleFlow, selection *cachedSelection) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	selection.expires = now.Add(c.ttl)
+	c.entries[selectionKey{application: application, datasetID: datasetID, flow: flow}] = selection
+}
+
+// Invalidate removes the selections for the given datasets, or all the selections if no dataset is given.
+// It returns the number of removed selections.
+func (c *SelectionCache) Invalidate(datasetIDs ...string) int {
+	if c == nil {
+		return 0
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(datasetIDs) == 0 {
+		removed := len(c.entries)
+		c.entries = make(map[selectionKey]*cachedSelection)
+		return removed
+	}
+	removed := 0
+	for _, datasetID := range datasetIDs {
+		for key := range c.entries {
+			if key.datasetID == datasetID {
+				delete(c.entries, key)
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
+// selectionDigest digests the inputs of the module selection for a dataset
+func selectionDigest(application *app.M4DApplication, item *modules.DataInfo, CatalogHash string, modulesVersion string, clusters []multicluster.Cluster) (string, error) {
+	spec := application.Spec.DeepCopy()
+	spec.Data = nil
+	inputs := struct {
+		Spec            *app.M4DApplicationSpec
+		Context         *app.DataContext
+		CatalogHash     string
+		VaultSecretPath string
+		Modules         string
+		Clusters        []multicluster.Cluster
+	}{spec, item.Context, CatalogHash, item.VaultSecretPath, modulesVersion, clusters}
+	bytes, err := json.Marshal(&inputs)
+	if err != nil {
+		return "", err
+	}
+	return utils.Hash(string(bytes), 40), nil
+}
+
+// modulesVersion identifies the versions of the installed modules, and the version ranges of their charts that may be selected
+func modulesVersion(moduleMap map[string]*app.M4DModule, ranges map[string]string) string {
+	versions := make([]string, 0, len(moduleMap))
+	for name, module := range moduleMap {
+		versions = append(versions, name+"@"+module.ResourceVersion+"/"+strconv.FormatInt(module.Generation, 10))
+	}
+	sort.Strings(versions)
+	bytes, _ := json.Marshal(struct {
+		Versions []string
+		Ranges   map[string]string
+	}{versions, ranges})
+	return utils.Hash(string(bytes), 40)
+}
+
+// copyInstances deep copies module instances, so that cached selections are not modified by the blueprint generation
+func copyInstances(instances []modules.ModuleInstanceSpec) []modules.ModuleInstanceSpec {
+	copied := make([]modules.ModuleInstanceSpec, len(instances))
+	for i, instance := range instances {
+		copied[i] = instance
+		copied[i].Module = instance.Module.DeepCopy()
+		copied[i].Args = instance.Args.DeepCopy()
+	}
+	return copied
+}
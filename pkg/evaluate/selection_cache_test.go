@@ -0,0 +1,83 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// countingPolicyManager counts the requests to the policy manager, and records the requested operations
+type countingPolicyManager struct {
+	mockup.MockPolicyManager
+	calls      int
+	operations []pb.AccessOperation_AccessType
+}
+
+func (m *countingPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	m.calls++
+	for _, dataset := range in.GetDatasets() {
+		m.operations = append(m.operations, dataset.GetOperation().GetType())
+	}
+	return m.MockPolicyManager.GetPoliciesDecisions(ctx, in)
+}
+
+// TestSelectionCache checks that the modules are not selected again for a dataset whose inputs are unchanged
+func TestSelectionCache(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &app.M4DApplication{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data = []app.DataContext{{
+		DataSetID:    "s3/allow-dataset",
+		Requirements: app.DataRequirements{Interface: app.InterfaceDetails{Protocol: app.ArrowFlight, DataFormat: app.Arrow}},
+	}}
+	readModule := &app.M4DModule{}
+	g.Expect(readObjectFromFile("../../manager/testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g), readModule)
+
+	policyManager := &countingPolicyManager{}
+	evaluator := NewEvaluator(cl, policyManager, mockup.NewTestCatalog(), &mockup.ClusterLister{})
+	evaluator.Selections = NewSelectionCache(time.Hour)
+	evaluate := func(app *app.M4DApplication) *Evaluation {
+		evaluation, err := evaluator.Evaluate(app)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(HasError(app)).To(gomega.BeFalse(), GetErrorMessages(app))
+		return evaluation
+	}
+
+	first := evaluate(application.DeepCopy())
+	calls := policyManager.calls
+	g.Expect(calls).To(gomega.BeNumerically(">", 0))
+	g.Expect(first.Instances).NotTo(gomega.BeEmpty())
+
+	// the cached selection is reused for the unchanged dataset
+	second := evaluate(application.DeepCopy())
+	g.Expect(policyManager.calls).To(gomega.Equal(calls))
+	g.Expect(second.Instances).To(gomega.Equal(first.Instances))
+	g.Expect(second.Blueprints).To(gomega.Equal(first.Blueprints))
+
+	// the modules are selected again if the requirements of the dataset change
+	changed := application.DeepCopy()
+	changed.Spec.Data[0].Requirements.Interface.DataFormat = app.Parquet
+	changed.Spec.Data[0].Requirements.Interface.Protocol = app.S3
+	_, err := evaluator.Evaluate(changed)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(policyManager.calls).To(gomega.BeNumerically(">", calls))
+
+	// or if the policies change
+	calls = policyManager.calls
+	g.Expect(evaluator.Selections.Invalidate("s3/allow-dataset")).To(gomega.Equal(1))
+	evaluate(application.DeepCopy())
+	g.Expect(policyManager.calls).To(gomega.BeNumerically(">", calls))
+}
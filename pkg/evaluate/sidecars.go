@@ -0,0 +1,66 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+)
+
+// AdminSidecars returns the governance agents defined by the administrators, by their names.
+// It is called once when the manager starts. An invalid definition is logged and ignored, such that the modules
+// requiring the agents are not deployed.
+func AdminSidecars(log logr.Logger) map[string]app.Sidecar {
+	sidecars, err := parseSidecars(utils.GetGovernanceSidecars())
+	if err != nil {
+		log.Error(err, "the governance sidecars are ignored")
+		return nil
+	}
+	return sidecars
+}
+
+// parseSidecars parses the governance agents defined by the administrators as a JSON object mapping their names to their containers
+func parseSidecars(config string) (map[string]app.Sidecar, error) {
+	if config == "" {
+		return nil, nil
+	}
+	sidecars := map[string]app.Sidecar{}
+	if err := json.Unmarshal([]byte(config), &sidecars); err != nil {
+		return nil, errors.Wrap(err, "invalid governance sidecars")
+	}
+	for name, sidecar := range sidecars {
+		// the containers are named after the agents, unless the administrators name them
+		if sidecar.Name == "" {
+			sidecar.Name = name
+			sidecars[name] = sidecar
+		}
+	}
+	return sidecars, nil
+}
+
+// moduleSidecars returns the governance agents required by a module, in the order of its capabilities.
+// An error is returned if an agent is not defined by the administrators.
+func moduleSidecars(module *app.M4DModule, defined map[string]app.Sidecar) ([]app.Sidecar, error) {
+	var sidecars []app.Sidecar
+	for _, name := range module.Spec.Capabilities.Sidecars {
+		sidecar, found := defined[name]
+		if !found {
+			return nil, fmt.Errorf("module %s requires the governance sidecar %s, which is not defined by the administrators", module.Name, name)
+		}
+		sidecars = append(sidecars, *sidecar.DeepCopy())
+	}
+	return sidecars, nil
+}
+
+// checkSidecars returns an error if the governance agents required by the module instance are not defined
+func checkSidecars(instance *modules.ModuleInstanceSpec, defined map[string]app.Sidecar) error {
+	_, err := moduleSidecars(instance.Module, defined)
+	return err
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestGovernanceSidecars checks that the governance agents required by a module are resolved from the definitions of the administrators
+func TestGovernanceSidecars(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	defined, err := parseSidecars(`{"audit-logger":{"image":"audit-logger:0.1.0","env":{"LOG_LEVEL":"info"}},` +
+		`"policy-proxy":{"name":"proxy","image":"policy-proxy:1.0.0","args":["--port","8443"]}}`)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	// the containers are named after the agents, unless named by the administrators
+	g.Expect(defined["audit-logger"].Name).To(gomega.Equal("audit-logger"))
+	g.Expect(defined["policy-proxy"].Name).To(gomega.Equal("proxy"))
+
+	module := &app.M4DModule{
+		ObjectMeta: metav1.ObjectMeta{Name: "arrow-flight"},
+		Spec: app.M4DModuleSpec{
+			Capabilities: app.Capability{Sidecars: []string{"policy-proxy", "audit-logger"}},
+			Chart:        app.ChartSpec{Name: "chart"},
+		},
+	}
+	sidecars, err := moduleSidecars(module, defined)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(sidecars).To(gomega.Equal([]app.Sidecar{
+		{Name: "proxy", Image: "policy-proxy:1.0.0", Args: []string{"--port", "8443"}},
+		{Name: "audit-logger", Image: "audit-logger:0.1.0", Env: map[string]string{"LOG_LEVEL": "info"}},
+	}))
+	// the sidecars of the modules are not shared
+	sidecars[1].Env["LOG_LEVEL"] = "debug"
+	g.Expect(defined["audit-logger"].Env["LOG_LEVEL"]).To(gomega.Equal("info"))
+
+	// the sidecars are injected whatever the values contract of the chart
+	instance := &modules.ModuleInstanceSpec{Module: module, AssetID: "s3/allow-dataset"}
+	g.Expect(checkSidecars(instance, defined)).To(gomega.Succeed())
+	// the agents must be defined by the administrators
+	module.Spec.Capabilities.Sidecars = []string{"unknown-agent"}
+	g.Expect(checkSidecars(instance, defined)).To(gomega.MatchError(gomega.ContainSubstring("unknown-agent")))
+	// modules that do not require sidecars are not checked
+	module.Spec.Capabilities.Sidecars = nil
+	g.Expect(checkSidecars(instance, nil)).To(gomega.Succeed())
+
+	_, err = parseSidecars("not json")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
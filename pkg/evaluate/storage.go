@@ -1,7 +1,7 @@
 // Copyright 2020 IBM Corp.
 // SPDX-License-Identifier: Apache-2.0
 
-package app
+package evaluate
 
 import (
 	"context"
@@ -26,10 +26,10 @@ func includesGeography(array []string, element string) bool {
 	return false
 }
 
-// AllocateBucket allocates a bucket in the relevant geo
+// AllocateBucket allocates a bucket in the relevant geo, in a storage account having the required tags, if any
 // The buckets are created as temporary, i.e. to be removed after the owner Dataset is deleted
 // After a successful copy and registering a dataset, the bucket will become persistent
-func AllocateBucket(c client.Client, log logr.Logger, owner types.NamespacedName, id string, geo string) (*storage.ProvisionedBucket, error) {
+func AllocateBucket(c client.Client, log logr.Logger, owner types.NamespacedName, id string, geo string, requiredTags map[string]string) (*storage.ProvisionedStorage, error) {
 	ctx := context.Background()
 	log.Info("Searching for a storage account matching the geography " + geo)
 	var accountList app.M4DStorageAccountList
@@ -37,18 +37,49 @@ func AllocateBucket(c client.Client, log logr.Logger, owner types.NamespacedName
 		log.Info(err.Error())
 		return nil, err
 	}
+	var invalidAccounts []string
+	var untaggedAccounts []string
+	var invalidRegions []string
 	for _, account := range accountList.Items {
 		utils.PrintStructure(account, log, "Account ")
+		// accounts created before the validation was enabled may have invalid regions, which are reported in their status.
+		// The accounts are still used in the regions matching the geography.
+		if err := account.ValidateRegions(); err != nil {
+			invalidRegions = append(invalidRegions, account.Name)
+		}
+		if reason := account.InvalidCredentials(); reason != "" {
+			log.Info("Skipping storage account with invalid credentials", "account", account.Name, "reason", reason)
+			invalidAccounts = append(invalidAccounts, account.Name)
+			continue
+		}
 		if !includesGeography(account.Spec.Regions, geo) {
 			continue
 		}
+		if !hasTags(account.Spec.Tags, requiredTags) {
+			untaggedAccounts = append(untaggedAccounts, account.Name)
+			continue
+		}
 		genName := generateDatasetName(owner, id)
-		return &storage.ProvisionedBucket{
+		return &storage.ProvisionedStorage{
 			Name:      genName,
+			Type:      account.Spec.Type,
 			Endpoint:  account.Spec.Endpoint,
 			SecretRef: types.NamespacedName{Name: account.Spec.SecretRef, Namespace: utils.GetSystemNamespace()},
+			Details:   account.Spec.Details,
 		}, nil
 	}
+	if len(untaggedAccounts) > 0 {
+		return nil, fmt.Errorf("could not allocate a bucket in %s, the governance policies require the tags %s which storage accounts %s do not have",
+			geo, formatTags(requiredTags), strings.Join(untaggedAccounts, ", "))
+	}
+	if len(invalidAccounts) > 0 {
+		return nil, fmt.Errorf("could not allocate a bucket in %s, storage accounts with invalid credentials were skipped: %s",
+			geo, strings.Join(invalidAccounts, ", "))
+	}
+	if len(invalidRegions) > 0 {
+		return nil, fmt.Errorf("could not allocate a bucket in %s, the regions of storage accounts %s are invalid",
+			geo, strings.Join(invalidRegions, ", "))
+	}
 	return nil, fmt.Errorf("could not allocate a bucket in %s", geo)
 }
 
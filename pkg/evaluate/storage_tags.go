@@ -0,0 +1,60 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// restrictStorage removes the actions restricting the storage accounts in which the dataset may be copied,
+// and records the tags they require. An error is returned if the policies require different values of a tag.
+func (m *ModuleManager) restrictStorage(datasetID string, actions []*pb.EnforcementAction) ([]*pb.EnforcementAction, error) {
+	remaining := make([]*pb.EnforcementAction, 0, len(actions))
+	for _, action := range actions {
+		if !utils.IsStorageRestriction(action.GetName()) {
+			remaining = append(remaining, action)
+			continue
+		}
+		if m.requiredTags == nil {
+			m.requiredTags = make(map[string]map[string]string)
+		}
+		tags := m.requiredTags[datasetID]
+		if tags == nil {
+			tags = make(map[string]string)
+			m.requiredTags[datasetID] = tags
+		}
+		for key, value := range action.GetArgs() {
+			if required, found := tags[key]; found && required != value {
+				return remaining, fmt.Errorf("the governance policies require conflicting values of the storage tag %s: %s and %s", key, required, value)
+			}
+			tags[key] = value
+		}
+	}
+	return remaining, nil
+}
+
+// hasTags returns true if the tags include all the required tags
+func hasTags(tags map[string]string, required map[string]string) bool {
+	for key, value := range required {
+		if tags[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// formatTags lists the tags as key=value pairs, sorted by key
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
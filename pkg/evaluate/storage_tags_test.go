@@ -0,0 +1,72 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestrictStorage(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	m := &ModuleManager{}
+	redact := &pb.EnforcementAction{Name: "redact", Id: "redact-ID", Level: pb.EnforcementAction_COLUMN}
+	restrict := func(tags map[string]string) *pb.EnforcementAction {
+		return &pb.EnforcementAction{Name: "RestrictStorage", Level: pb.EnforcementAction_DATASET, Args: tags}
+	}
+
+	actions, err := m.restrictStorage("s3/redact-dataset", []*pb.EnforcementAction{redact, restrict(map[string]string{"cost_tier": "archive"})})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(actions).To(gomega.ConsistOf(redact))
+	_, err = m.restrictStorage("s3/redact-dataset", []*pb.EnforcementAction{restrict(map[string]string{"compliance_certification": "hipaa"})})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(m.requiredTags["s3/redact-dataset"]).To(gomega.Equal(map[string]string{"cost_tier": "archive", "compliance_certification": "hipaa"}))
+	g.Expect(m.requiredTags).NotTo(gomega.HaveKey("s3/allow-dataset"))
+
+	_, err = m.restrictStorage("s3/redact-dataset", []*pb.EnforcementAction{restrict(map[string]string{"cost_tier": "standard"})})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestAllocateBucketWithTags(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	newAccount := func(name string, tags map[string]string) *app.M4DStorageAccount {
+		return &app.M4DStorageAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: utils.GetSystemNamespace()},
+			Spec: app.M4DStorageAccountSpec{
+				SecretRef: "credentials-" + name,
+				Endpoint:  "http://s3.eu.cloud-object-storage.appdomain.cloud",
+				Regions:   []string{"theshire"},
+				Tags:      tags,
+			},
+		}
+	}
+	cl := fake.NewFakeClientWithScheme(utils.NewScheme(g),
+		newAccount("standard", map[string]string{"cost_tier": "standard"}),
+		newAccount("archive", map[string]string{"cost_tier": "archive", "compliance_certification": "hipaa"}))
+	owner := types.NamespacedName{Name: "notebook", Namespace: "default"}
+	log := ctrl.Log.WithName("test")
+
+	bucket, err := AllocateBucket(cl, log, owner, "s3/allow-dataset", "theshire", map[string]string{"compliance_certification": "hipaa"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(bucket.SecretRef.Name).To(gomega.Equal("credentials-archive"))
+
+	_, err = AllocateBucket(cl, log, owner, "s3/allow-dataset", "theshire", map[string]string{"compliance_certification": "pci-dss"})
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("compliance_certification=pci-dss"))
+
+	_, err = AllocateBucket(cl, log, owner, "s3/allow-dataset", "theshire", nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"sort"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
+	"github.com/mesh-for-data/mesh-for-data/pkg/capabilities"
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+)
+
+// maxChainLength is the maximal number of modules transforming the data before the read module
+const maxChainLength = 2
+
+// buildTransformationChain composes read modules that together perform the actions that neither the read module
+// nor a single other module supports, e.g. a module redacting columns feeding a module masking them.
+// The first module of the chain reads the dataset, each module reads the API of the previous one,
+// and the read module reads the API of the last one.
+// Modules with dependencies are not chained. Nil is returned if no chain performs all the actions.
+func (m *ModuleManager) buildTransformationChain(item modules.DataInfo, readSelector *modules.Selector, actions []*pb.EnforcementAction) []modules.ChainLink {
+	readModule := readSelector.GetModule()
+	sinks := capabilities.GetSupportedReadSources(readModule)
+	// modules are examined in the order of their names for the chain to be stable
+	names := make([]string, 0, len(m.Modules))
+	for name, module := range m.Modules {
+		if name == readModule.Name || !capabilities.SupportsFlow(module.Spec.Flows, app.Read) || readSelector.VersionMismatch(module) != "" {
+			continue
+		}
+		if found, missing := modules.CheckDependencies(module, m.Modules); len(found) > 0 || len(missing) > 0 {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var search func(source *app.InterfaceDetails, remaining []*pb.EnforcementAction, chain []modules.ChainLink) []modules.ChainLink
+	search = func(source *app.InterfaceDetails, remaining []*pb.EnforcementAction, chain []modules.ChainLink) []modules.ChainLink {
+		if len(remaining) == 0 {
+			if capabilities.SupportsInterface(sinks, source) {
+				return chain
+			}
+			return nil
+		}
+		if len(chain) == maxChainLength {
+			return nil
+		}
+		for _, name := range names {
+			module := m.Modules[name]
+			if isChained(chain, name) || !capabilities.SupportsInterface(capabilities.GetSupportedReadSources(module), source) {
+				continue
+			}
+			var performed, rest []*pb.EnforcementAction
+			for _, action := range remaining {
+				if readSelector.SupportsGovernanceAction(module, action) {
+					performed = append(performed, action)
+				} else {
+					rest = append(rest, action)
+				}
+			}
+			if len(performed) == 0 {
+				continue
+			}
+			for _, api := range capabilities.GetModuleAPIs(module) {
+				link := modules.ChainLink{Module: module, API: api, Actions: performed}
+				if found := search(&api.InterfaceDetails, rest, append(chain[:len(chain):len(chain)], link)); found != nil {
+					return found
+				}
+			}
+		}
+		return nil
+	}
+	return search(&item.DataDetails.Interface, actions, nil)
+}
+
+func isChained(chain []modules.ChainLink, moduleName string) bool {
+	for _, link := range chain {
+		if link.Module.Name == moduleName {
+			return true
+		}
+	}
+	return false
+}
+
+// chainedDataStore describes the connection to the API of a module of a transformation chain,
+// served by the release of the module in the blueprint namespace
+func chainedDataStore(appContext *app.M4DApplication, link modules.ChainLink, datasetID string) app.DataStore {
+	releaseName := utils.GetReleaseNameByStepName(appContext.Name, appContext.Namespace, utils.CreateStepName(link.Module.Name, datasetID))
+	protocol := link.API.Protocol
+	connection := serde.NewArbitrary(map[string]interface{}{
+		"name": protocol,
+		protocol: map[string]interface{}{
+			"hostname": utils.GenerateModuleEndpointFQDN(releaseName, utils.BlueprintNamespace),
+			"port":     link.API.Endpoint.Port,
+			"scheme":   link.API.Endpoint.Scheme,
+		},
+	})
+	return app.DataStore{
+		Connection: *connection,
+		Format:     link.API.DataFormat,
+	}
+}
+
+// chainedInstances creates the module instances of a transformation chain, all running in the cluster of the read module
+func chainedInstances(appContext *app.M4DApplication, item modules.DataInfo, chain []modules.ChainLink, source app.DataStore,
+	readModule string, cluster string) []modules.ModuleInstanceSpec {
+	instances := make([]modules.ModuleInstanceSpec, 0, len(chain))
+	for i, link := range chain {
+		next := readModule
+		if i+1 < len(chain) {
+			next = chain[i+1].Module.Name
+		}
+		instances = append(instances, modules.ModuleInstanceSpec{
+			Module: link.Module,
+			Args: &app.ModuleArguments{
+				Read: []app.ReadModuleArgs{
+					{
+						Source:          source,
+						AssetID:         utils.CreateDataSetIdentifier(item.Context.DataSetID),
+						Transformations: actionsToArbitrary(link.Actions),
+					},
+				},
+			},
+			AssetID:     item.Context.DataSetID,
+			ClusterName: cluster,
+			ChainedTo:   next,
+		})
+		source = chainedDataStore(appContext, link, item.Context.DataSetID)
+	}
+	return instances
+}
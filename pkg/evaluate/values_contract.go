@@ -0,0 +1,69 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"fmt"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+)
+
+// valuesIntroduced maps each version of the values contract to the top-level values it has introduced
+var valuesIntroduced = map[string][]string{
+	app.ValuesContractV1: {},
+	app.ValuesContractV2: {"annotations", "externalDNS"},
+	app.ValuesContractV3: {"scheduling"},
+	app.ValuesContractV4: {"resources"},
+	app.ValuesContractV5: {},
+	app.ValuesContractV6: {"decisions"},
+}
+
+// contractOrder lists the versions of the values contract supported by the manager, oldest first
+var contractOrder = []string{app.ValuesContractV1, app.ValuesContractV2, app.ValuesContractV3, app.ValuesContractV4,
+	app.ValuesContractV5, app.ValuesContractV6}
+
+// valuesContract returns the version of the values contract declared by the chart
+func valuesContract(chart *app.ChartSpec) string {
+	if chart.ValuesContract == "" {
+		return app.ValuesContractV1
+	}
+	return chart.ValuesContract
+}
+
+// IsValuesContractSupported returns true if the manager can pass values following the contract declared by the chart
+func IsValuesContractSupported(chart *app.ChartSpec) bool {
+	_, supported := valuesIntroduced[valuesContract(chart)]
+	return supported
+}
+
+// checkValuesContract returns an error if the chart of the module instance declares a values contract that is not supported
+func checkValuesContract(instance *modules.ModuleInstanceSpec) error {
+	chart := &instance.Module.Spec.Chart
+	if IsValuesContractSupported(chart) {
+		return nil
+	}
+	return fmt.Errorf("the chart of module %s requires values contract %s, which is not supported by the manager",
+		instance.Module.Name, chart.ValuesContract)
+}
+
+// AdaptValues removes the values that have been introduced by versions of the contract newer than the one declared by the chart.
+// Charts following the first version of the contract only receive the labels identifying the application.
+func AdaptValues(args map[string]interface{}, chart *app.ChartSpec) {
+	declared := valuesContract(chart)
+	newer := false
+	for _, version := range contractOrder {
+		if newer {
+			for _, key := range valuesIntroduced[version] {
+				delete(args, key)
+			}
+		}
+		if version == declared {
+			newer = true
+		}
+	}
+	if declared == app.ValuesContractV1 {
+		delete(args, "labels")
+	}
+}
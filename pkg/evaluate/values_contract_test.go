@@ -0,0 +1,81 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluate
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/manager/controllers/app/modules"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdaptValues(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	newArgs := func() map[string]interface{} {
+		return map[string]interface{}{
+			"read":        []interface{}{},
+			"labels":      map[string]interface{}{"team": "analytics"},
+			"annotations": map[string]interface{}{app.DatasetIDsAnnotation: "s3/allow-dataset"},
+			"externalDNS": map[string]interface{}{"hostname": "release.data.example.com"},
+			"scheduling":  map[string]interface{}{"priorityClassName": "interactive"},
+			"resources":   map[string]interface{}{"requests": map[string]interface{}{"cpu": "500m"}},
+			"decisions":   map[string]interface{}{"configMap": "blueprint-decisions", "key": "decisions.json"},
+		}
+	}
+	// charts that do not declare a contract follow the first version
+	args := newArgs()
+	AdaptValues(args, &app.ChartSpec{Name: "chart"})
+	g.Expect(args).To(gomega.HaveKey("read"))
+	g.Expect(args).NotTo(gomega.HaveKey("labels"))
+	g.Expect(args).NotTo(gomega.HaveKey("annotations"))
+	g.Expect(args).NotTo(gomega.HaveKey("externalDNS"))
+	g.Expect(args).NotTo(gomega.HaveKey("scheduling"))
+	g.Expect(args).NotTo(gomega.HaveKey("resources"))
+	g.Expect(args).NotTo(gomega.HaveKey("decisions"))
+
+	args = newArgs()
+	AdaptValues(args, &app.ChartSpec{Name: "chart", ValuesContract: app.ValuesContractV2})
+	g.Expect(args).To(gomega.HaveKey("externalDNS"))
+	g.Expect(args).NotTo(gomega.HaveKey("scheduling"))
+
+	args = newArgs()
+	AdaptValues(args, &app.ChartSpec{Name: "chart", ValuesContract: app.ValuesContractV3})
+	g.Expect(args).To(gomega.HaveKey("scheduling"))
+	g.Expect(args).NotTo(gomega.HaveKey("resources"))
+
+	args = newArgs()
+	AdaptValues(args, &app.ChartSpec{Name: "chart", ValuesContract: app.ValuesContractV4})
+	g.Expect(args).To(gomega.HaveKey("resources"))
+	g.Expect(args).NotTo(gomega.HaveKey("decisions"))
+
+	args = newArgs()
+	AdaptValues(args, &app.ChartSpec{Name: "chart", ValuesContract: app.ValuesContractV5})
+	g.Expect(args).To(gomega.HaveKey("resources"))
+	g.Expect(args).NotTo(gomega.HaveKey("decisions"))
+
+	args = newArgs()
+	AdaptValues(args, &app.ChartSpec{Name: "chart", ValuesContract: app.ValuesContractV6})
+	g.Expect(args).To(gomega.Equal(newArgs()))
+}
+
+func TestCheckValuesContract(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	module := &app.M4DModule{
+		ObjectMeta: metav1.ObjectMeta{Name: "future-module"},
+		Spec:       app.M4DModuleSpec{Chart: app.ChartSpec{Name: "chart", ValuesContract: "v99"}},
+	}
+	instance := &modules.ModuleInstanceSpec{Module: module, AssetID: "s3/allow-dataset"}
+	g.Expect(checkValuesContract(instance)).To(gomega.HaveOccurred())
+
+	module.Spec.Chart.ValuesContract = app.ValuesContractV2
+	g.Expect(checkValuesContract(instance)).To(gomega.Succeed())
+	module.Spec.Chart.ValuesContract = ""
+	g.Expect(checkValuesContract(instance)).To(gomega.Succeed())
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Types of the lifecycle events of applications
+const (
+	// PolicyDecision is emitted when the decision of the policy manager on an operation on a dataset changes
+	PolicyDecision string = "PolicyDecision"
+	// DataPlaneReady is emitted when the data plane of an application becomes ready
+	DataPlaneReady string = "DataPlaneReady"
+	// DatasetRevoked is emitted when the access of an application to a dataset is revoked
+	DatasetRevoked string = "DatasetRevoked"
+	// FailedOpen is emitted when the previously granted data plane of an application is kept
+	// although its governance policies can not be evaluated since the policy manager is unavailable
+	FailedOpen string = "FailedOpen"
+	// CatalogedAssetPruned is emitted when the registration of the copy of a dataset in the catalog is removed from
+	// the status of an application, since the dataset is no longer requested
+	CatalogedAssetPruned string = "CatalogedAssetPruned"
+)
+
+// ApplicationReference identifies the application concerned by an event
+type ApplicationReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// Event is a lifecycle event of an application, as exported to external sinks.
+// See site/docs/reference/events.md for the JSON schema.
+type Event struct {
+	Type        string               `json:"type"`
+	Time        time.Time            `json:"time"`
+	Application ApplicationReference `json:"application"`
+	// DatasetID is set for events concerning a single dataset
+	DatasetID string `json:"datasetID,omitempty"`
+	// Operation is the operation decided upon by the policy manager (read or write)
+	Operation string `json:"operation,omitempty"`
+	// Destination is the geography in which the data is processed or written
+	Destination string `json:"destination,omitempty"`
+	// Actions are the identifiers of the actions required by the policy manager. Empty if access is allowed as is.
+	Actions []string `json:"actions,omitempty"`
+	// Reason explains the event, e.g. a denial or the reason of a revocation
+	Reason string `json:"reason,omitempty"`
+}
+
+// Emitter accepts events for export
+type Emitter interface {
+	Emit(event Event)
+}
+
+// Sink delivers events to an external system
+type Sink interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// Exporter forwards the emitted events to a sink in the background, so that reconciles never wait for the sink.
+// Events emitted while the buffer is full are dropped.
+type Exporter struct {
+	sink   Sink
+	events chan Event
+	log    logr.Logger
+}
+
+// NewExporter creates an exporter buffering up to bufferSize events
+func NewExporter(sink Sink, bufferSize int, log logr.Logger) *Exporter {
+	return &Exporter{sink: sink, events: make(chan Event, bufferSize), log: log}
+}
+
+// Emit queues an event for export
+func (e *Exporter) Emit(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	select {
+	case e.events <- event:
+	default:
+		e.log.Info("event buffer is full, dropping event", "type", event.Type, "application", event.Application)
+	}
+}
+
+// Start sends the emitted events to the sink until the context is done.
+// Start implements manager.Runnable so that the exporter is run by the controller manager.
+func (e *Exporter) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-e.events:
+			if err := e.sink.Send(ctx, event); err != nil {
+				e.log.Info("could not export event", "type", event.Type, "error", err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestExportToKafka(t *testing.T) {
+	received := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/m4d-events", r.URL.Path)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		body := map[string]interface{}{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		received <- body
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(KafkaSinkType, server.URL, "m4d-events")
+	assert.Nil(t, err)
+	exporter := NewExporter(sink, 10, ctrl.Log.WithName("events"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = exporter.Start(ctx) }()
+
+	exporter.Emit(Event{Type: DatasetRevoked, Application: ApplicationReference{Name: "notebook", Namespace: "default"},
+		DatasetID: "s3/allow-dataset", Reason: "data breach"})
+	body := <-received
+	records := body["records"].([]interface{})
+	assert.Len(t, records, 1)
+	record := records[0].(map[string]interface{})
+	assert.Equal(t, "default/notebook", record["key"])
+	value := record["value"].(map[string]interface{})
+	assert.Equal(t, DatasetRevoked, value["type"])
+	assert.Equal(t, "data breach", value["reason"])
+	assert.NotEmpty(t, value["time"])
+
+	_, err = NewSink(KafkaSinkType, server.URL, "")
+	assert.NotNil(t, err)
+	_, err = NewSink("syslog", server.URL, "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Kinds of sinks
+const (
+	WebhookSinkType = "webhook"
+	KafkaSinkType   = "kafka"
+)
+
+// NewSink creates a sink of the given kind. Kafka topics are written through a Kafka REST proxy at the given URL.
+func NewSink(kind string, url string, topic string) (Sink, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch kind {
+	case WebhookSinkType:
+		return &WebhookSink{URL: url, Client: client}, nil
+	case KafkaSinkType:
+		if topic == "" {
+			return nil, errors.New("a topic is required for exporting events to kafka")
+		}
+		return &KafkaSink{URL: url, Topic: topic, Client: client}, nil
+	default:
+		return nil, errors.New("unsupported event sink " + kind + ": should be one of " + WebhookSinkType + ", " + KafkaSinkType)
+	}
+}
+
+// WebhookSink posts each event as a JSON document to a URL
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// Send implements Sink
+func (s *WebhookSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.Client, s.URL, "application/json", body)
+}
+
+// KafkaSink produces each event as a record of a topic through a Kafka REST proxy.
+// The record key is the namespaced name of the application, so that the events of an application are ordered.
+type KafkaSink struct {
+	URL    string
+	Topic  string
+	Client *http.Client
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+// Send implements Sink
+func (s *KafkaSink) Send(ctx context.Context, event Event) error {
+	records := struct {
+		Records []kafkaRecord `json:"records"`
+	}{Records: []kafkaRecord{{Key: event.Application.Namespace + "/" + event.Application.Name, Value: event}}}
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.Client, strings.TrimSuffix(s.URL, "/")+"/topics/"+s.Topic, "application/vnd.kafka.json.v2+json", body)
+}
+
+func post(ctx context.Context, client *http.Client, url string, contentType string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", contentType)
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: %s", url, response.Status)
+	}
+	return nil
+}
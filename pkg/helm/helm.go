@@ -13,6 +13,7 @@ import (
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/release"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -54,8 +55,8 @@ func ChartRef(hostname string, namespace string, name string, tagname string) st
 // Interface of a helm chart
 type Interface interface {
 	Uninstall(kubeNamespace string, releaseName string) (*release.UninstallReleaseResponse, error)
-	Install(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}) (*release.Release, error)
-	Upgrade(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}) (*release.Release, error)
+	Install(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}, postRenderer postrender.PostRenderer) (*release.Release, error)
+	Upgrade(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}, postRenderer postrender.PostRenderer) (*release.Release, error)
 	Status(kubeNamespace string, releaseName string) (*release.Release, error)
 	RegistryLogin(hostname string, username string, password string, insecure bool) error
 	RegistryLogout(hostname string) error
@@ -81,19 +82,21 @@ func (r *Fake) Uninstall(kubeNamespace string, releaseName string) (*release.Uni
 }
 
 // Install helm release
-func (r *Fake) Install(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}) (*release.Release, error) {
+func (r *Fake) Install(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}, postRenderer postrender.PostRenderer) (*release.Release, error) {
 	r.release = &release.Release{
-		Name: releaseName,
-		Info: &release.Info{Status: release.StatusDeployed},
+		Name:   releaseName,
+		Info:   &release.Info{Status: release.StatusDeployed},
+		Config: vals,
 	}
 	return r.release, nil
 }
 
 // Upgrade helm release
-func (r *Fake) Upgrade(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}) (*release.Release, error) {
+func (r *Fake) Upgrade(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}, postRenderer postrender.PostRenderer) (*release.Release, error) {
 	r.release = &release.Release{
-		Name: releaseName,
-		Info: &release.Info{Status: release.StatusDeployed},
+		Name:   releaseName,
+		Info:   &release.Info{Status: release.StatusDeployed},
+		Config: vals,
 	}
 	return r.release, nil
 }
@@ -171,8 +174,8 @@ func (r *Impl) Uninstall(kubeNamespace string, releaseName string) (*release.Uni
 	return uninstall.Run(releaseName)
 }
 
-// Install helm release
-func (r *Impl) Install(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}) (*release.Release, error) {
+// Install helm release, passing the rendered manifests to the post-renderer if not nil
+func (r *Impl) Install(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}, postRenderer postrender.PostRenderer) (*release.Release, error) {
 	cfg, err := getConfig(kubeNamespace)
 	if err != nil {
 		return nil, err
@@ -180,17 +183,19 @@ func (r *Impl) Install(chart *chart.Chart, kubeNamespace string, releaseName str
 	install := action.NewInstall(cfg)
 	install.ReleaseName = releaseName
 	install.Namespace = kubeNamespace
+	install.PostRenderer = postRenderer
 	return install.Run(chart, vals)
 }
 
-// Upgrade helm release
-func (r *Impl) Upgrade(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}) (*release.Release, error) {
+// Upgrade helm release, passing the rendered manifests to the post-renderer if not nil
+func (r *Impl) Upgrade(chart *chart.Chart, kubeNamespace string, releaseName string, vals map[string]interface{}, postRenderer postrender.PostRenderer) (*release.Release, error) {
 	cfg, err := getConfig(kubeNamespace)
 	if err != nil {
 		return nil, err
 	}
 	upgrade := action.NewUpgrade(cfg)
 	upgrade.Namespace = kubeNamespace
+	upgrade.PostRenderer = postRenderer
 	return upgrade.Run(releaseName, chart, vals)
 }
 
@@ -127,11 +127,11 @@ func TestHelmRelease(t *testing.T) {
 			"key": "value1",
 		},
 	}
-	_, err = impl.Install(origChart, kubeNamespace, releaseName, vals)
+	_, err = impl.Install(origChart, kubeNamespace, releaseName, vals, nil)
 	assert.Nil(t, err)
 	Log(t, "install", err)
 
-	_, err = impl.Upgrade(origChart, kubeNamespace, releaseName, vals)
+	_, err = impl.Upgrade(origChart, kubeNamespace, releaseName, vals, nil)
 	assert.Nil(t, err)
 	Log(t, "upgrade", err)
 
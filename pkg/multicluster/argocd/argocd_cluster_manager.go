@@ -0,0 +1,302 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ClusterLabel marks the configmaps of the control plane namespace registering a cluster synced by ArgoCD
+	ClusterLabel string = "m4d.ibm.com/argocd-cluster"
+
+	// DefaultBranch is the branch to which the manifests are written if the configmap of the cluster does not set one
+	DefaultBranch string = "main"
+)
+
+// ApplicationGVK is the kind of the ArgoCD Application resources syncing the repositories to the clusters
+var ApplicationGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}
+
+// clusterConfig is a cluster read from its configmap
+type clusterConfig struct {
+	cluster    multicluster.Cluster
+	repository Repository
+	// path is the directory of the repository holding the manifests of the cluster
+	path string
+	// application is the ArgoCD Application syncing the path to the cluster
+	application client.ObjectKey
+}
+
+// ClusterManager writes the blueprints of the clusters to git repositories synced by ArgoCD, as a GitOps alternative
+// to Razee. Each cluster is registered by a configmap labeled with ClusterLabel in the control plane namespace,
+// holding the cluster metadata, the repository, branch and path of its manifests, and the name of the ArgoCD
+// Application syncing them. The status of the blueprints is read from the Application.
+type ClusterManager struct {
+	Client    client.Client
+	Namespace string
+	// ArgoCDNamespace is the namespace of the Applications whose configmap does not set one
+	ArgoCDNamespace string
+	Git             GitClient
+	Log             logr.Logger
+}
+
+// GetClusters returns the clusters registered by valid configmaps
+func (cm *ClusterManager) GetClusters() ([]multicluster.Cluster, error) {
+	configs, err := cm.clusterConfigs()
+	if err != nil {
+		return nil, err
+	}
+	clusters := make([]multicluster.Cluster, 0, len(configs))
+	for _, config := range configs {
+		clusters = append(clusters, config.cluster)
+	}
+	return clusters, nil
+}
+
+// clusterConfigs reads the configmaps of the clusters, skipping the invalid ones
+func (cm *ClusterManager) clusterConfigs() ([]*clusterConfig, error) {
+	configmaps := &corev1.ConfigMapList{}
+	if err := cm.Client.List(context.Background(), configmaps, client.InNamespace(cm.Namespace),
+		client.MatchingLabels{ClusterLabel: "true"}); err != nil {
+		return nil, errors.Wrap(err, "error in GetClusters")
+	}
+	sort.Slice(configmaps.Items, func(i, j int) bool { return configmaps.Items[i].Name < configmaps.Items[j].Name })
+
+	var configs []*clusterConfig
+	found := map[string]bool{}
+	for i := range configmaps.Items {
+		configmap := &configmaps.Items[i]
+		config, err := cm.parseClusterConfig(configmap)
+		if err != nil {
+			cm.Log.Error(err, "Skipping invalid cluster configmap", "configmap", configmap.Name)
+			continue
+		}
+		if found[config.cluster.Name] {
+			cm.Log.Info("Skipping cluster registered by several configmaps", "cluster", config.cluster.Name, "configmap", configmap.Name)
+			continue
+		}
+		found[config.cluster.Name] = true
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// parseClusterConfig decodes the configmap of a cluster
+func (cm *ClusterManager) parseClusterConfig(configmap *corev1.ConfigMap) (*clusterConfig, error) {
+	data := configmap.Data
+	if data["ClusterName"] == "" {
+		return nil, errors.New("no ClusterName in cluster configmap")
+	}
+	if data["Repository"] == "" {
+		return nil, errors.New("no Repository in cluster configmap")
+	}
+	mirrors, err := multicluster.ParseRegistryMirrors(data["RegistryMirrors"])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid registry mirrors")
+	}
+	cost, err := multicluster.ParseClusterCost(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cluster cost")
+	}
+	config := &clusterConfig{
+		cluster: multicluster.Cluster{
+			Name: data["ClusterName"],
+			Metadata: multicluster.ClusterMetadata{
+				Region:          data["Region"],
+				Zone:            data["Zone"],
+				VaultAuthPath:   data["VaultAuthPath"],
+				VaultAddress:    data["VaultAddress"],
+				RegistryMirrors: mirrors,
+				Cost:            cost,
+			},
+		},
+		repository: Repository{URL: data["Repository"], Branch: data["Branch"]},
+		path:       data["Path"],
+		application: client.ObjectKey{
+			Name:      data["Application"],
+			Namespace: data["ApplicationNamespace"],
+		},
+	}
+	if config.repository.Branch == "" {
+		config.repository.Branch = DefaultBranch
+	}
+	if config.path == "" {
+		config.path = config.cluster.Name
+	}
+	if config.application.Name == "" {
+		config.application.Name = config.cluster.Name
+	}
+	if config.application.Namespace == "" {
+		config.application.Namespace = cm.ArgoCDNamespace
+	}
+	if secretName := data["CredentialsSecret"]; secretName != "" {
+		secret := &corev1.Secret{}
+		if err := cm.Client.Get(context.Background(), client.ObjectKey{Name: secretName, Namespace: cm.Namespace}, secret); err != nil {
+			return nil, errors.Wrap(err, "could not read the credentials of the repository")
+		}
+		config.repository.Username = string(secret.Data["username"])
+		config.repository.Password = string(secret.Data["password"])
+	}
+	return config, nil
+}
+
+// clusterConfig returns the configuration of a registered cluster
+func (cm *ClusterManager) clusterConfig(cluster string) (*clusterConfig, error) {
+	configs, err := cm.clusterConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		if config.cluster.Name == cluster {
+			return config, nil
+		}
+	}
+	return nil, fmt.Errorf("unregistered cluster: %s", cluster)
+}
+
+// manifestPath returns the path of the manifest of a blueprint in the repository of its cluster
+func manifestPath(config *clusterConfig, namespace string, name string) string {
+	return path.Join(config.path, namespace, name+".yaml")
+}
+
+// GetBlueprint returns the blueprint written to the repository of the cluster, or nil if it does not exist.
+// The blueprint is observed once the Application has synced the revision at the head of the branch, and its status
+// is set from the sync status and the health of the blueprint reported by the Application.
+func (cm *ClusterManager) GetBlueprint(cluster string, namespace string, name string) (*v1alpha1.Blueprint, error) {
+	config, err := cm.clusterConfig(cluster)
+	if err != nil {
+		return nil, err
+	}
+	content, revision, err := cm.Git.ReadFile(config.repository, manifestPath(config, namespace, name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read blueprint from the repository of cluster %s", cluster)
+	}
+	if content == nil {
+		return nil, nil
+	}
+	blueprint := &v1alpha1.Blueprint{}
+	if err := yaml.Unmarshal(content, blueprint); err != nil {
+		return nil, errors.Wrapf(err, "invalid blueprint in the repository of cluster %s", cluster)
+	}
+	// the manifest in the repository is the only generation of the blueprint known to the manager
+	blueprint.Generation = 1
+
+	application := &unstructured.Unstructured{}
+	application.SetGroupVersionKind(ApplicationGVK)
+	if err := cm.Client.Get(context.Background(), config.application, application); err != nil {
+		if apierrors.IsNotFound(err) {
+			cm.Log.Info("ArgoCD application not found", "cluster", cluster, "application", config.application)
+			return blueprint, nil
+		}
+		return nil, err
+	}
+	setBlueprintStatus(blueprint, application, revision)
+	return blueprint, nil
+}
+
+// setBlueprintStatus sets the status of a blueprint from the Application syncing it
+func setBlueprintStatus(blueprint *v1alpha1.Blueprint, application *unstructured.Unstructured, revision string) {
+	if syncedRevision, _, _ := unstructured.NestedString(application.Object, "status", "sync", "revision"); syncedRevision != revision {
+		return
+	}
+	resources, _, _ := unstructured.NestedSlice(application.Object, "status", "resources")
+	for _, item := range resources {
+		resource, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(resource, "group")
+		kind, _, _ := unstructured.NestedString(resource, "kind")
+		namespace, _, _ := unstructured.NestedString(resource, "namespace")
+		name, _, _ := unstructured.NestedString(resource, "name")
+		if group != v1alpha1.GroupVersion.Group || kind != "Blueprint" || namespace != blueprint.Namespace || name != blueprint.Name {
+			continue
+		}
+		if status, _, _ := unstructured.NestedString(resource, "status"); status != "Synced" {
+			return
+		}
+		blueprint.Status.ObservedGeneration = blueprint.Generation
+		health, _, _ := unstructured.NestedString(resource, "health", "status")
+		message, _, _ := unstructured.NestedString(resource, "health", "message")
+		switch health {
+		case "Healthy":
+			blueprint.Status.ObservedState.Ready = true
+			blueprint.Status.ObservedState.DataAccessInstructions = message
+		case "Degraded":
+			blueprint.Status.ObservedState.Error = message
+		}
+		return
+	}
+}
+
+// CreateBlueprint writes a blueprint to the repository of the cluster
+func (cm *ClusterManager) CreateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
+	return cm.UpdateBlueprint(cluster, blueprint)
+}
+
+// UpdateBlueprint writes the spec and the labels of a blueprint to the repository of the cluster
+func (cm *ClusterManager) UpdateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
+	config, err := cm.clusterConfig(cluster)
+	if err != nil {
+		return err
+	}
+	manifest := &v1alpha1.Blueprint{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Blueprint",
+			APIVersion: v1alpha1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      blueprint.Name,
+			Namespace: blueprint.Namespace,
+			Labels:    blueprint.Labels,
+		},
+		Spec: blueprint.Spec,
+	}
+	content, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{manifestPath(config, blueprint.Namespace, blueprint.Name): content}
+	message := fmt.Sprintf("Update blueprint %s/%s", blueprint.Namespace, blueprint.Name)
+	return errors.Wrapf(cm.Git.Commit(config.repository, files, message), "could not write blueprint to the repository of cluster %s", cluster)
+}
+
+// DeleteBlueprint removes a blueprint from the repository of the cluster
+func (cm *ClusterManager) DeleteBlueprint(cluster string, namespace string, name string) error {
+	config, err := cm.clusterConfig(cluster)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{manifestPath(config, namespace, name): nil}
+	message := fmt.Sprintf("Delete blueprint %s/%s", namespace, name)
+	return errors.Wrapf(cm.Git.Commit(config.repository, files, message), "could not delete blueprint from the repository of cluster %s", cluster)
+}
+
+// NewManager creates a new ClusterManager for the clusters registered in the given namespace, cloning the
+// repositories in the given directory
+func NewManager(client client.Client, namespace string, argoCDNamespace string, dir string) (multicluster.ClusterManager, error) {
+	return &ClusterManager{
+		Client:          client,
+		Namespace:       namespace,
+		ArgoCDNamespace: argoCDNamespace,
+		Git:             NewCLIClient(dir),
+		Log:             ctrl.Log.WithName("ArgoCDManager"),
+	}, nil
+}
@@ -0,0 +1,227 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ multicluster.ClusterManager = &ClusterManager{}
+var _ GitClient = &CLIClient{}
+
+// memoryGit holds the files of the repositories in memory, with a revision counting the commits of each branch
+type memoryGit struct {
+	files     map[string][]byte
+	revisions map[string]int
+}
+
+func (g *memoryGit) ReadFile(repo Repository, path string) ([]byte, string, error) {
+	return g.files[repo.URL+"#"+repo.Branch+"/"+path], fmt.Sprint(g.revisions[repo.URL+"#"+repo.Branch]), nil
+}
+
+func (g *memoryGit) Commit(repo Repository, files map[string][]byte, message string) error {
+	for path, content := range files {
+		key := repo.URL + "#" + repo.Branch + "/" + path
+		if content == nil {
+			delete(g.files, key)
+		} else {
+			g.files[key] = content
+		}
+	}
+	g.revisions[repo.URL+"#"+repo.Branch]++
+	return nil
+}
+
+func clusterConfigmap(name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "m4d-system",
+			Labels:    map[string]string{ClusterLabel: "true"},
+		},
+		Data: data,
+	}
+}
+
+// argoApplication creates an ArgoCD Application that synced the given revision, reporting the health of a blueprint
+func argoApplication(revision string, health string, message string) *unstructured.Unstructured {
+	application := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "remote-cluster", "namespace": "argocd"},
+		"status": map[string]interface{}{
+			"sync": map[string]interface{}{"status": "Synced", "revision": revision},
+			"resources": []interface{}{
+				map[string]interface{}{
+					"group":     "app.m4d.ibm.com",
+					"kind":      "Blueprint",
+					"namespace": "m4d-blueprints",
+					"name":      "notebook",
+					"status":    "Synced",
+					"health":    map[string]interface{}{"status": health, "message": message},
+				},
+			},
+		},
+	}}
+	application.SetGroupVersionKind(ApplicationGVK)
+	return application
+}
+
+func TestArgoCDClusterManager(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	controlPlane := fake.NewFakeClientWithScheme(scheme,
+		clusterConfigmap("cluster-a", map[string]string{
+			"ClusterName":       "remote-cluster",
+			"Region":            "Region-1",
+			"Repository":        "https://git.example.com/gitops.git",
+			"Path":              "clusters/remote",
+			"CredentialsSecret": "gitops-credentials",
+		}),
+		clusterConfigmap("cluster-b", map[string]string{"ClusterName": "no-repository"}),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "gitops-credentials", Namespace: "m4d-system"},
+			Data:       map[string][]byte{"username": []byte("m4d"), "password": []byte("secret")},
+		},
+	)
+	git := &memoryGit{files: map[string][]byte{}, revisions: map[string]int{}}
+	cm := &ClusterManager{
+		Client:          controlPlane,
+		Namespace:       "m4d-system",
+		ArgoCDNamespace: "argocd",
+		Git:             git,
+		Log:             ctrl.Log.WithName("test"),
+	}
+
+	// only the cluster with a repository is listed
+	clusters, err := cm.GetClusters()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(clusters).To(gomega.Equal([]multicluster.Cluster{
+		{Name: "remote-cluster", Metadata: multicluster.ClusterMetadata{Region: "Region-1"}},
+	}))
+	config, err := cm.clusterConfig("remote-cluster")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(config.repository).To(gomega.Equal(Repository{URL: "https://git.example.com/gitops.git", Branch: DefaultBranch,
+		Username: "m4d", Password: "secret"}))
+	g.Expect(config.application).To(gomega.Equal(client.ObjectKey{Name: "remote-cluster", Namespace: "argocd"}))
+
+	// blueprints are written to the path of the cluster in the repository
+	blueprint := &v1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "m4d-blueprints", Labels: map[string]string{"app": "notebook"}},
+		Spec:       v1alpha1.BlueprintSpec{Entrypoint: "notebook"},
+	}
+	g.Expect(cm.CreateBlueprint("remote-cluster", blueprint)).To(gomega.Succeed())
+	g.Expect(cm.CreateBlueprint("other-cluster", blueprint)).NotTo(gomega.Succeed())
+	g.Expect(git.files).To(gomega.HaveKey("https://git.example.com/gitops.git#main/clusters/remote/m4d-blueprints/notebook.yaml"))
+
+	// the blueprint is not observed before the application is synced
+	remoteBlueprint, err := cm.GetBlueprint("remote-cluster", "m4d-blueprints", "notebook")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(remoteBlueprint.Spec.Entrypoint).To(gomega.Equal("notebook"))
+	g.Expect(remoteBlueprint.Labels).To(gomega.Equal(blueprint.Labels))
+	g.Expect(remoteBlueprint.Status.ObservedGeneration).NotTo(gomega.Equal(remoteBlueprint.Generation))
+
+	// the status is read from the application once it has synced the last revision
+	application := argoApplication("0", "Progressing", "")
+	g.Expect(controlPlane.Create(context.Background(), application)).To(gomega.Succeed())
+	remoteBlueprint, err = cm.GetBlueprint("remote-cluster", "m4d-blueprints", "notebook")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(remoteBlueprint.Status.ObservedGeneration).NotTo(gomega.Equal(remoteBlueprint.Generation))
+
+	g.Expect(controlPlane.Delete(context.Background(), application)).To(gomega.Succeed())
+	g.Expect(controlPlane.Create(context.Background(), argoApplication("1", "Healthy", "read from s3"))).To(gomega.Succeed())
+	remoteBlueprint, err = cm.GetBlueprint("remote-cluster", "m4d-blueprints", "notebook")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(remoteBlueprint.Status.ObservedGeneration).To(gomega.Equal(remoteBlueprint.Generation))
+	g.Expect(remoteBlueprint.Status.ObservedState.Ready).To(gomega.BeTrue())
+	g.Expect(remoteBlueprint.Status.ObservedState.DataAccessInstructions).To(gomega.Equal("read from s3"))
+
+	g.Expect(cm.DeleteBlueprint("remote-cluster", "m4d-blueprints", "notebook")).To(gomega.Succeed())
+	remoteBlueprint, err = cm.GetBlueprint("remote-cluster", "m4d-blueprints", "notebook")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(remoteBlueprint).To(gomega.BeNil())
+}
+
+func TestDegradedBlueprint(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	blueprint := &v1alpha1.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "m4d-blueprints", Generation: 1}}
+	setBlueprintStatus(blueprint, argoApplication("abc", "Degraded", "chart not found"), "abc")
+	g.Expect(blueprint.Status.ObservedGeneration).To(gomega.Equal(int64(1)))
+	g.Expect(blueprint.Status.ObservedState.Ready).To(gomega.BeFalse())
+	g.Expect(blueprint.Status.ObservedState.Error).To(gomega.Equal("chart not found"))
+}
+
+func TestCLIClient(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	g := gomega.NewGomegaWithT(t)
+
+	// a bare repository with a main branch stands for the remote repository
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote.git")
+	seed := filepath.Join(dir, "seed")
+	for _, args := range [][]string{
+		{"init", "--quiet", "--bare", remote},
+		{"init", "--quiet", seed},
+		{"-C", seed, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "init"},
+		{"-C", seed, "push", "--quiet", remote, "HEAD:refs/heads/main"},
+	} {
+		g.Expect(exec.Command("git", args...).Run()).To(gomega.Succeed(), "git %v", args)
+	}
+	repo := Repository{URL: remote, Branch: "main"}
+	c := NewCLIClient(filepath.Join(dir, "clones"))
+
+	content, initial, err := c.ReadFile(repo, "cluster/ns/notebook.yaml")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(content).To(gomega.BeNil())
+
+	g.Expect(c.Commit(repo, map[string][]byte{"cluster/ns/notebook.yaml": []byte("kind: Blueprint\n")}, "Update")).To(gomega.Succeed())
+	content, revision, err := c.ReadFile(repo, "cluster/ns/notebook.yaml")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(content)).To(gomega.Equal("kind: Blueprint\n"))
+	g.Expect(revision).NotTo(gomega.Equal(initial))
+
+	// unchanged files are not committed again
+	g.Expect(c.Commit(repo, map[string][]byte{"cluster/ns/notebook.yaml": []byte("kind: Blueprint\n")}, "Update")).To(gomega.Succeed())
+	_, unchanged, err := c.ReadFile(repo, "cluster/ns/notebook.yaml")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(unchanged).To(gomega.Equal(revision))
+
+	g.Expect(c.Commit(repo, map[string][]byte{"cluster/ns/notebook.yaml": nil}, "Delete")).To(gomega.Succeed())
+	content, deleted, err := c.ReadFile(repo, "cluster/ns/notebook.yaml")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(content).To(gomega.BeNil())
+
+	// the changes pushed by others are read once the branch is fetched again
+	for _, args := range [][]string{
+		{"-C", seed, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "other"},
+		{"-C", seed, "push", "--quiet", "--force", remote, "HEAD:refs/heads/main"},
+	} {
+		g.Expect(exec.Command("git", args...).Run()).To(gomega.Succeed(), "git %v", args)
+	}
+	_, cached, err := c.ReadFile(repo, "cluster/ns/notebook.yaml")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cached).To(gomega.Equal(deleted))
+	c.FetchInterval = 0
+	_, fetched, err := c.ReadFile(repo, "cluster/ns/notebook.yaml")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(fetched).NotTo(gomega.Equal(deleted))
+}
@@ -0,0 +1,253 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package argocd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// maxPushAttempts is the number of times a commit is rebuilt and pushed when the branch has moved in the meantime
+const maxPushAttempts = 3
+
+// defaultFetchInterval is the minimum time between two fetches of a branch to read its files
+const defaultFetchInterval = 30 * time.Second
+
+// Repository identifies the branch of a git repository holding the manifests of a cluster
+type Repository struct {
+	URL    string
+	Branch string
+	// Username and Password authenticate to repositories served over HTTP(S)
+	Username string
+	Password string
+}
+
+// authURL returns the URL of the repository including its credentials
+func (r Repository) authURL() string {
+	if r.Password == "" {
+		return r.URL
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return r.URL
+	}
+	u.User = url.UserPassword(r.Username, r.Password)
+	return u.String()
+}
+
+// GitClient reads and changes the files of the branches of git repositories
+type GitClient interface {
+	// ReadFile returns the content of a file at the head of the branch, or nil if the file does not exist,
+	// together with the revision of the head of the branch
+	ReadFile(repo Repository, path string) ([]byte, string, error)
+	// Commit writes the given files, or deletes the files whose content is nil, and pushes the change to the branch.
+	// Nothing is committed if the files are unchanged.
+	Commit(repo Repository, files map[string][]byte, message string) error
+}
+
+// CLIClient implements GitClient with the git command line client, using a clone of each branch in Dir.
+// The operations on a branch are serialized, while different branches are used concurrently.
+type CLIClient struct {
+	Dir         string
+	AuthorName  string
+	AuthorEmail string
+	// FetchInterval is the minimum time between two fetches of a branch to read its files.
+	// The blueprints are polled frequently, their files are read from the clone in the meantime.
+	FetchInterval time.Duration
+	mutex         sync.Mutex
+	clones        map[string]*clone
+}
+
+// clone is the local clone of a branch
+type clone struct {
+	mutex sync.Mutex
+	dir   string
+	// fetched is the time at which the clone was last synced with the head of the branch
+	fetched time.Time
+}
+
+// NewCLIClient creates a git client cloning the repositories in the given directory
+func NewCLIClient(dir string) *CLIClient {
+	return &CLIClient{Dir: dir, AuthorName: "Mesh for Data", AuthorEmail: "m4d@mesh-for-data.io",
+		FetchInterval: defaultFetchInterval}
+}
+
+// clone returns the clone of the branch of the repository, which is not necessarily checked out yet
+func (c *CLIClient) clone(repo Repository) *clone {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := repo.URL + "#" + repo.Branch
+	if cl, found := c.clones[key]; found {
+		return cl
+	}
+	if c.clones == nil {
+		c.clones = map[string]*clone{}
+	}
+	hash := sha256.Sum256([]byte(key))
+	cl := &clone{dir: filepath.Join(c.Dir, hex.EncodeToString(hash[:8]))}
+	c.clones[key] = cl
+	return cl
+}
+
+// git runs a git command in the given directory and returns its output.
+// The password of the repository is removed from the error messages.
+func (c *CLIClient) git(repo Repository, dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if repo.Password != "" {
+			message = strings.ReplaceAll(message, repo.Password, "***")
+		}
+		return "", errors.WithMessagef(err, "git %s failed: %s", args[0], message)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// checkout clones the branch of the repository, or resets the existing clone to the head of the branch.
+// It is called with the lock of the clone held.
+func (c *CLIClient) checkout(repo Repository, cl *clone) (string, error) {
+	dir := cl.dir
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(c.Dir, 0700); err != nil {
+			return "", err
+		}
+		if _, err := c.git(repo, c.Dir, "clone", "--quiet", "--single-branch", "--branch", repo.Branch, repo.authURL(), dir); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", err
+		}
+		// the credentials are not kept in the configuration of the clone
+		if _, err := c.git(repo, dir, "remote", "set-url", "origin", repo.URL); err != nil {
+			return "", err
+		}
+		cl.fetched = time.Now()
+		return dir, nil
+	}
+	if _, err := c.git(repo, dir, "fetch", "--quiet", repo.authURL(), repo.Branch); err != nil {
+		return "", err
+	}
+	if _, err := c.git(repo, dir, "reset", "--quiet", "--hard", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	if _, err := c.git(repo, dir, "clean", "-fdq"); err != nil {
+		return "", err
+	}
+	cl.fetched = time.Now()
+	return dir, nil
+}
+
+// ReadFile returns the content of a file at the head of the branch.
+// The branch is fetched at most once per FetchInterval, the file is read from the clone otherwise.
+func (c *CLIClient) ReadFile(repo Repository, path string) ([]byte, string, error) {
+	cl := c.clone(repo)
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	dir := cl.dir
+	if time.Since(cl.fetched) >= c.FetchInterval {
+		var err error
+		if dir, err = c.checkout(repo, cl); err != nil {
+			return nil, "", err
+		}
+	}
+	revision, err := c.git(repo, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, "", err
+	}
+	content, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if os.IsNotExist(err) {
+		return nil, revision, nil
+	}
+	return content, revision, err
+}
+
+// Commit commits the changed files and pushes them to the branch. If the push is rejected because the branch
+// has moved, the commit is rebuilt on the new head of the branch.
+func (c *CLIClient) Commit(repo Repository, files map[string][]byte, message string) error {
+	cl := c.clone(repo)
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	err := c.commit(repo, cl, files, message)
+	if err != nil {
+		// the clone may hold changes that are not pushed, it is reset before the files are read again
+		cl.fetched = time.Time{}
+	}
+	return err
+}
+
+// commit pushes the changed files, retrying on the new head of the branch. It is called with the lock of the clone held.
+func (c *CLIClient) commit(repo Repository, cl *clone, files map[string][]byte, message string) error {
+	for attempt := 1; ; attempt++ {
+		dir, err := c.checkout(repo, cl)
+		if err != nil {
+			return err
+		}
+		changed, err := c.writeFiles(repo, dir, files)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		if _, err := c.git(repo, dir, "-c", "user.name="+c.AuthorName, "-c", "user.email="+c.AuthorEmail,
+			"commit", "--quiet", "-m", message); err != nil {
+			return err
+		}
+		_, err = c.git(repo, dir, "push", "--quiet", repo.authURL(), "HEAD:refs/heads/"+repo.Branch)
+		if err == nil || attempt == maxPushAttempts {
+			return err
+		}
+	}
+}
+
+// writeFiles writes or deletes the files in the clone and stages them. It returns whether anything has changed.
+func (c *CLIClient) writeFiles(repo Repository, dir string, files map[string][]byte) (bool, error) {
+	var paths []string
+	for path, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(path))
+		if content == nil {
+			if err := os.Remove(file); err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return false, err
+			}
+		} else {
+			if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+				return false, err
+			}
+			if err := ioutil.WriteFile(file, content, 0600); err != nil {
+				return false, err
+			}
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return false, nil
+	}
+	if _, err := c.git(repo, dir, append([]string{"add", "-A", "--"}, paths...)...); err != nil {
+		return false, err
+	}
+	// diff exits with the code 1 if staged changes are found, any other failure is an error
+	_, err := c.git(repo, dir, "diff", "--cached", "--quiet")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return true, nil
+	}
+	return false, err
+}
@@ -2,6 +2,7 @@ package dummy
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
@@ -10,6 +11,18 @@ import (
 // This ClusterManager is meant to be used for testing
 type ClusterManager struct {
 	DeployedBlueprints map[string]*v1alpha1.Blueprint
+	// FailingClusters lists clusters for which blueprint creation and update fail
+	FailingClusters []string
+	mutex           sync.Mutex
+}
+
+func (m *ClusterManager) isFailing(cluster string) bool {
+	for _, c := range m.FailingClusters {
+		if c == cluster {
+			return true
+		}
+	}
+	return false
 }
 
 func (m *ClusterManager) GetClusters() ([]multicluster.Cluster, error) {
@@ -22,6 +35,8 @@ func (m *ClusterManager) GetClusters() ([]multicluster.Cluster, error) {
 }
 
 func (m *ClusterManager) GetBlueprint(cluster string, namespace string, name string) (*v1alpha1.Blueprint, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	blueprint, found := m.DeployedBlueprints[cluster]
 	if found {
 		return blueprint, nil
@@ -30,16 +45,28 @@ func (m *ClusterManager) GetBlueprint(cluster string, namespace string, name str
 }
 
 func (m *ClusterManager) CreateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.isFailing(cluster) {
+		return errors.New("cluster " + cluster + " is not reachable")
+	}
 	m.DeployedBlueprints[cluster] = blueprint
 	return nil
 }
 
 func (m *ClusterManager) UpdateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.isFailing(cluster) {
+		return errors.New("cluster " + cluster + " is not reachable")
+	}
 	m.DeployedBlueprints[cluster] = blueprint
 	return nil
 }
 
 func (m *ClusterManager) DeleteBlueprint(cluster string, namespace string, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	delete(m.DeployedBlueprints, cluster)
 	return nil
 }
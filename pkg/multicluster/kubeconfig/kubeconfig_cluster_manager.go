@@ -0,0 +1,293 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// KubeconfigLabel marks the secrets of the control plane namespace holding the kubeconfig of a remote cluster
+	KubeconfigLabel string = "m4d.ibm.com/kubeconfig"
+	// KubeconfigKey is the key of the kubeconfig in the data of the secrets
+	KubeconfigKey string = "kubeconfig"
+
+	clusterMetadataConfigmapName string = "cluster-metadata"
+	clusterMetadataNamespace     string = "m4d-system"
+
+	// DefaultTimeout bounds the requests to the remote clusters, such that an unresponsive cluster does not block the manager
+	DefaultTimeout = 30 * time.Second
+	// DefaultRescanInterval is the minimal interval between the discoveries of the clusters triggered by unknown cluster names
+	DefaultRescanInterval = 30 * time.Second
+)
+
+var (
+	scheme = runtime.NewScheme()
+)
+
+func init() {
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+}
+
+// ClientFactory creates a client of a remote cluster from its kubeconfig
+type ClientFactory func(kubeconfig []byte) (client.Client, error)
+
+// NewClientFromKubeconfig creates a client of the cluster described by the kubeconfig, able to handle blueprints
+func NewClientFromKubeconfig(kubeconfig []byte) (client.Client, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid kubeconfig")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
+
+// remoteCluster is a cluster discovered from a kubeconfig secret
+type remoteCluster struct {
+	// resourceVersion is the version of the secret from which the client has been created
+	resourceVersion string
+	client          client.Client
+}
+
+// ClusterManager deploys blueprints directly to the remote clusters whose kubeconfig is stored in secrets
+// labeled with KubeconfigLabel in the control plane namespace, as an alternative to Razee.
+// The name and the metadata of each cluster are read from the cluster-metadata configmap of the cluster.
+// Clusters that can not be reached are not listed.
+type ClusterManager struct {
+	Client    client.Client
+	Namespace string
+	NewClient ClientFactory
+	Log       logr.Logger
+	// RescanInterval is the minimal interval between the discoveries of the clusters triggered by unknown cluster names,
+	// DefaultRescanInterval if not set
+	RescanInterval time.Duration
+	// mutex guards the fields below, it is not held while the remote clusters are accessed
+	mutex sync.Mutex
+	// clusters maps the names of the reachable clusters to their clients
+	clusters map[string]*remoteCluster
+	// clients caches the clients created from the kubeconfig secrets, by secret name
+	clients map[string]*remoteCluster
+	// lastScan is the time of the last discovery of the clusters
+	lastScan time.Time
+}
+
+// GetClusters returns the clusters whose kubeconfig secret is found and whose cluster metadata can be read
+func (cm *ClusterManager) GetClusters() ([]multicluster.Cluster, error) {
+	secrets := &corev1.SecretList{}
+	if err := cm.Client.List(context.Background(), secrets, client.InNamespace(cm.Namespace),
+		client.MatchingLabels{KubeconfigLabel: "true"}); err != nil {
+		return nil, errors.Wrap(err, "error in GetClusters")
+	}
+	sort.Slice(secrets.Items, func(i, j int) bool { return secrets.Items[i].Name < secrets.Items[j].Name })
+
+	// the clients are created and the cluster metadata is read without holding the lock
+	cm.mutex.Lock()
+	cached := cm.clients
+	cm.mutex.Unlock()
+	clients := make(map[string]*remoteCluster, len(secrets.Items))
+	reachable := make(map[string]*remoteCluster, len(secrets.Items))
+	var clusters []multicluster.Cluster
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		remote, err := cm.remoteClient(secret, cached)
+		if err != nil {
+			cm.Log.Error(err, "Could not create a client from the kubeconfig secret", "secret", secret.Name)
+			continue
+		}
+		clients[secret.Name] = remote
+		cluster, err := readClusterMetadata(remote.client)
+		if err != nil {
+			cm.Log.Error(err, "Skipping unreachable cluster", "secret", secret.Name)
+			continue
+		}
+		if _, found := reachable[cluster.Name]; found {
+			cm.Log.Info("Skipping cluster registered by several kubeconfig secrets", "cluster", cluster.Name, "secret", secret.Name)
+			continue
+		}
+		reachable[cluster.Name] = remote
+		clusters = append(clusters, cluster)
+	}
+	cm.mutex.Lock()
+	cm.clients = clients
+	cm.clusters = reachable
+	cm.lastScan = time.Now()
+	cm.mutex.Unlock()
+	return clusters, nil
+}
+
+// remoteClient returns the cached client of the secret, or creates a new one if the secret has changed
+func (cm *ClusterManager) remoteClient(secret *corev1.Secret, cached map[string]*remoteCluster) (*remoteCluster, error) {
+	if remote, found := cached[secret.Name]; found && remote.resourceVersion == secret.ResourceVersion {
+		return remote, nil
+	}
+	kubeconfig, found := secret.Data[KubeconfigKey]
+	if !found {
+		return nil, fmt.Errorf("no %s key in secret %s", KubeconfigKey, secret.Name)
+	}
+	c, err := cm.NewClient(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteCluster{resourceVersion: secret.ResourceVersion, client: c}, nil
+}
+
+// readClusterMetadata reads the cluster metadata configmap of a remote cluster
+func readClusterMetadata(c client.Client) (multicluster.Cluster, error) {
+	clusterMetadataConfigmap := corev1.ConfigMap{}
+	namespacedName := client.ObjectKey{
+		Name:      clusterMetadataConfigmapName,
+		Namespace: clusterMetadataNamespace,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	if err := c.Get(ctx, namespacedName, &clusterMetadataConfigmap); err != nil {
+		return multicluster.Cluster{}, err
+	}
+	mirrors, err := multicluster.ParseRegistryMirrors(clusterMetadataConfigmap.Data["RegistryMirrors"])
+	if err != nil {
+		return multicluster.Cluster{}, errors.Wrap(err, "invalid registry mirrors")
+	}
+	cost, err := multicluster.ParseClusterCost(clusterMetadataConfigmap.Data)
+	if err != nil {
+		return multicluster.Cluster{}, errors.Wrap(err, "invalid cluster cost")
+	}
+	return multicluster.Cluster{
+		Name: clusterMetadataConfigmap.Data["ClusterName"],
+		Metadata: multicluster.ClusterMetadata{
+			Region:          clusterMetadataConfigmap.Data["Region"],
+			Zone:            clusterMetadataConfigmap.Data["Zone"],
+			VaultAuthPath:   clusterMetadataConfigmap.Data["VaultAuthPath"],
+			VaultAddress:    clusterMetadataConfigmap.Data["VaultAddress"],
+			RegistryMirrors: mirrors,
+			Cost:            cost,
+		},
+	}, nil
+}
+
+// clusterClient returns the client of a cluster. The clusters are discovered again if the cluster is not known yet,
+// at most once per rescan interval, such that requests for unregistered clusters do not scan the kubeconfig secrets each time.
+func (cm *ClusterManager) clusterClient(cluster string) (client.Client, error) {
+	cm.mutex.Lock()
+	remote, found := cm.clusters[cluster]
+	rescan := !found && time.Since(cm.lastScan) >= cm.rescanInterval()
+	if rescan {
+		cm.lastScan = time.Now()
+	}
+	cm.mutex.Unlock()
+	if found {
+		return remote.client, nil
+	}
+	if !rescan {
+		return nil, fmt.Errorf("unregistered cluster: %s", cluster)
+	}
+	if _, err := cm.GetClusters(); err != nil {
+		return nil, err
+	}
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	if remote, found = cm.clusters[cluster]; !found {
+		return nil, fmt.Errorf("unregistered cluster: %s", cluster)
+	}
+	return remote.client, nil
+}
+
+// rescanInterval returns the minimal interval between the discoveries of the clusters
+func (cm *ClusterManager) rescanInterval() time.Duration {
+	if cm.RescanInterval > 0 {
+		return cm.RescanInterval
+	}
+	return DefaultRescanInterval
+}
+
+// CheckHealth returns an error if the cluster metadata of the cluster can not be read through its kubeconfig
+func (cm *ClusterManager) CheckHealth(cluster string) error {
+	c, err := cm.clusterClient(cluster)
+	if err != nil {
+		return err
+	}
+	if _, err := readClusterMetadata(c); err != nil {
+		return errors.Wrapf(err, "cluster %s is unreachable", cluster)
+	}
+	return nil
+}
+
+// GetBlueprint returns the blueprint of the remote cluster including its status, or nil if it does not exist
+func (cm *ClusterManager) GetBlueprint(cluster string, namespace string, name string) (*v1alpha1.Blueprint, error) {
+	c, err := cm.clusterClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	blueprint := &v1alpha1.Blueprint{}
+	if err := c.Get(context.Background(), client.ObjectKey{Name: name, Namespace: namespace}, blueprint); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return blueprint, nil
+}
+
+// CreateBlueprint creates a blueprint resource in the remote cluster or updates an existing one
+func (cm *ClusterManager) CreateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
+	return cm.UpdateBlueprint(cluster, blueprint)
+}
+
+// UpdateBlueprint updates the given blueprint in the remote cluster or creates a new one if does not exist
+func (cm *ClusterManager) UpdateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
+	c, err := cm.clusterClient(cluster)
+	if err != nil {
+		return err
+	}
+	resource := &v1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      blueprint.Name,
+			Namespace: blueprint.Namespace,
+		},
+	}
+	_, err = ctrl.CreateOrUpdate(context.Background(), c, resource, func() error {
+		resource.Spec = blueprint.Spec
+		resource.ObjectMeta.Labels = blueprint.ObjectMeta.Labels
+		return nil
+	})
+	return err
+}
+
+// DeleteBlueprint deletes the blueprint resource of the remote cluster
+func (cm *ClusterManager) DeleteBlueprint(cluster string, namespace string, name string) error {
+	c, err := cm.clusterClient(cluster)
+	if err != nil {
+		return err
+	}
+	blueprint := &v1alpha1.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	return client.IgnoreNotFound(c.Delete(context.Background(), blueprint))
+}
+
+// NewManager creates a new ClusterManager for the remote clusters whose kubeconfig secrets are in the given namespace
+func NewManager(client client.Client, namespace string) (multicluster.ClusterManager, error) {
+	return &ClusterManager{
+		Client:    client,
+		Namespace: namespace,
+		NewClient: NewClientFromKubeconfig,
+		Log:       ctrl.Log.WithName("KubeconfigManager"),
+	}, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package kubeconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/mesh-for-data/mesh-for-data/pkg/multicluster"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ multicluster.ClusterManager = &ClusterManager{}
+var _ multicluster.ClusterHealthChecker = &ClusterManager{}
+
+func kubeconfigSecret(name string, kubeconfig string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "m4d-system",
+			Labels:    map[string]string{KubeconfigLabel: "true"},
+		},
+		Data: map[string][]byte{KubeconfigKey: []byte(kubeconfig)},
+	}
+}
+
+func TestKubeconfigClusterManager(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the kubeconfig of a cluster stands for its fake client
+	reachable := fake.NewFakeClientWithScheme(scheme, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-metadata", Namespace: "m4d-system"},
+		Data: map[string]string{
+			"ClusterName":  "remote-cluster",
+			"Region":       "Region-1",
+			"Zone":         "Zone-1",
+			"VaultAddress": "http://vault.remote:8200",
+		},
+	})
+	unreachable := fake.NewFakeClientWithScheme(scheme)
+	remoteClients := map[string]client.Client{"reachable": reachable, "unreachable": unreachable}
+	newClient := func(kubeconfig []byte) (client.Client, error) {
+		if c, found := remoteClients[string(kubeconfig)]; found {
+			return c, nil
+		}
+		return nil, errors.New("invalid kubeconfig")
+	}
+	controlPlane := fake.NewFakeClientWithScheme(scheme,
+		kubeconfigSecret("cluster-a", "reachable"),
+		kubeconfigSecret("cluster-b", "unreachable"),
+		kubeconfigSecret("cluster-c", "invalid"),
+	)
+	cm := &ClusterManager{
+		Client:    controlPlane,
+		Namespace: "m4d-system",
+		NewClient: newClient,
+		Log:       ctrl.Log.WithName("test"),
+	}
+
+	// only the reachable cluster is listed
+	clusters, err := cm.GetClusters()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(clusters).To(gomega.Equal([]multicluster.Cluster{
+		{
+			Name: "remote-cluster",
+			Metadata: multicluster.ClusterMetadata{
+				Region:       "Region-1",
+				Zone:         "Zone-1",
+				VaultAddress: "http://vault.remote:8200",
+			},
+		},
+	}))
+	g.Expect(cm.CheckHealth("remote-cluster")).To(gomega.Succeed())
+	g.Expect(cm.CheckHealth("other-cluster")).NotTo(gomega.Succeed())
+
+	// blueprints are deployed directly to the remote cluster
+	blueprint := &v1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "m4d-blueprints"},
+		Spec:       v1alpha1.BlueprintSpec{Entrypoint: "notebook"},
+	}
+	g.Expect(cm.CreateBlueprint("remote-cluster", blueprint)).To(gomega.Succeed())
+	g.Expect(cm.CreateBlueprint("other-cluster", blueprint)).NotTo(gomega.Succeed())
+	remoteBlueprint, err := cm.GetBlueprint("remote-cluster", "m4d-blueprints", "notebook")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(remoteBlueprint.Spec.Entrypoint).To(gomega.Equal("notebook"))
+
+	// the status of the remote blueprint is returned
+	remoteBlueprint.Status.ObservedState.Ready = true
+	g.Expect(reachable.Status().Update(context.Background(), remoteBlueprint)).To(gomega.Succeed())
+	remoteBlueprint, err = cm.GetBlueprint("remote-cluster", "m4d-blueprints", "notebook")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(remoteBlueprint.Status.ObservedState.Ready).To(gomega.BeTrue())
+
+	g.Expect(cm.DeleteBlueprint("remote-cluster", "m4d-blueprints", "notebook")).To(gomega.Succeed())
+	remoteBlueprint, err = cm.GetBlueprint("remote-cluster", "m4d-blueprints", "notebook")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(remoteBlueprint).To(gomega.BeNil())
+}
+
+func TestKubeconfigClusterManagerRescan(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	added := fake.NewFakeClientWithScheme(scheme, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-metadata", Namespace: "m4d-system"},
+		Data:       map[string]string{"ClusterName": "added-cluster"},
+	})
+	scans := 0
+	newClient := func(kubeconfig []byte) (client.Client, error) {
+		scans++
+		return added, nil
+	}
+	controlPlane := fake.NewFakeClientWithScheme(scheme)
+	cm := &ClusterManager{
+		Client:         controlPlane,
+		Namespace:      "m4d-system",
+		NewClient:      newClient,
+		Log:            ctrl.Log.WithName("test"),
+		RescanInterval: time.Hour,
+	}
+
+	// an unknown cluster triggers a discovery of the clusters
+	g.Expect(cm.CheckHealth("added-cluster")).NotTo(gomega.Succeed())
+	g.Expect(controlPlane.Create(context.Background(), kubeconfigSecret("cluster-a", "added"))).To(gomega.Succeed())
+	// the clusters are not discovered again before the rescan interval has passed
+	g.Expect(cm.CheckHealth("added-cluster")).NotTo(gomega.Succeed())
+	g.Expect(scans).To(gomega.Equal(0))
+
+	cm.lastScan = time.Now().Add(-time.Hour)
+	g.Expect(cm.CheckHealth("added-cluster")).To(gomega.Succeed())
+	g.Expect(scans).To(gomega.Equal(1))
+	// the client of the known cluster is reused
+	g.Expect(cm.CheckHealth("added-cluster")).To(gomega.Succeed())
+	g.Expect(scans).To(gomega.Equal(1))
+}
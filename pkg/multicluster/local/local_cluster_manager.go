@@ -33,13 +33,24 @@ func (cm *ClusterManager) GetClusters() ([]multicluster.Cluster, error) {
 	if err := cm.Client.Get(context.Background(), namespacedName, &clusterMetadataConfigmap); err != nil {
 		return nil, errors.Wrap(err, "error in GetClusters")
 	}
+	mirrors, err := multicluster.ParseRegistryMirrors(clusterMetadataConfigmap.Data["RegistryMirrors"])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid registry mirrors in GetClusters")
+	}
+	cost, err := multicluster.ParseClusterCost(clusterMetadataConfigmap.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cluster cost in GetClusters")
+	}
 	var clusters []multicluster.Cluster
 	cluster := multicluster.Cluster{
 		Name: clusterMetadataConfigmap.Data["ClusterName"],
 		Metadata: multicluster.ClusterMetadata{
-			Region:        clusterMetadataConfigmap.Data["Region"],
-			Zone:          clusterMetadataConfigmap.Data["Zone"],
-			VaultAuthPath: clusterMetadataConfigmap.Data["VaultAuthPath"],
+			Region:          clusterMetadataConfigmap.Data["Region"],
+			Zone:            clusterMetadataConfigmap.Data["Zone"],
+			VaultAuthPath:   clusterMetadataConfigmap.Data["VaultAuthPath"],
+			VaultAddress:    clusterMetadataConfigmap.Data["VaultAddress"],
+			RegistryMirrors: mirrors,
+			Cost:            cost,
 		},
 	}
 	clusters = append(clusters, cluster)
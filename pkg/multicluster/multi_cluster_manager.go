@@ -1,6 +1,10 @@
 package multicluster
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
 	"github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -10,6 +14,9 @@ type ClusterLister interface {
 	GetClusters() ([]Cluster, error)
 }
 
+// ClusterManager manages blueprints in the clusters.
+// Implementations must be safe for concurrent use as blueprints of different clusters are handled in parallel,
+// e.g. by serializing the requests of a client that is not.
 type ClusterManager interface {
 	ClusterLister
 	GetBlueprint(cluster string, namespace string, name string) (*v1alpha1.Blueprint, error)
@@ -18,10 +25,34 @@ type ClusterManager interface {
 	DeleteBlueprint(cluster string, namespace string, name string) error
 }
 
+// ClusterHealthChecker is implemented by the cluster managers that reach the clusters directly,
+// and are thus able to tell whether a cluster is reachable before its blueprint is handled
+type ClusterHealthChecker interface {
+	CheckHealth(cluster string) error
+}
+
 type ClusterMetadata struct {
 	Region        string
 	Zone          string
 	VaultAuthPath string
+	// VaultAddress is the address of the vault replica serving the modules running in the cluster.
+	// If empty, the modules use the vault of the control plane.
+	VaultAddress string
+	// RegistryMirrors maps registry prefixes of module charts and images to the prefixes of the mirrors
+	// that the cluster pulls from, e.g. for air-gapped clusters
+	RegistryMirrors map[string]string
+	// Cost of running modules in the cluster, used to select the cheapest of the clusters in which a module can run
+	Cost ClusterCost
+}
+
+// ClusterCost describes the cost and the capacity of a cluster. Values that are not set are zero.
+type ClusterCost struct {
+	// Tier is the relative price of the compute resources of the cluster, e.g. 1 for the cheapest clusters
+	Tier float64
+	// Capacity is the free capacity of the cluster for running modules, e.g. in cores
+	Capacity float64
+	// Egress is the price of the network traffic leaving the cluster, e.g. per GB
+	Egress float64
 }
 
 type Cluster struct {
@@ -38,3 +69,35 @@ func Decode(json string, scheme *runtime.Scheme, object runtime.Object) error {
 	}
 	return nil
 }
+
+// ParseRegistryMirrors decodes the registry mirrors of a cluster, given as a JSON object in the cluster metadata.
+// nil is returned if no mirror is configured.
+func ParseRegistryMirrors(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	mirrors := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &mirrors); err != nil {
+		return nil, err
+	}
+	if len(mirrors) == 0 {
+		return nil, nil
+	}
+	return mirrors, nil
+}
+
+// ParseClusterCost decodes the cost of a cluster from the CostTier, Capacity and EgressCost values of the cluster metadata
+func ParseClusterCost(data map[string]string) (ClusterCost, error) {
+	cost := ClusterCost{}
+	for key, value := range map[string]*float64{"CostTier": &cost.Tier, "Capacity": &cost.Capacity, "EgressCost": &cost.Egress} {
+		if data[key] == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(data[key], 64)
+		if err != nil {
+			return ClusterCost{}, fmt.Errorf("invalid %s in cluster metadata: %v", key, err)
+		}
+		*value = parsed
+	}
+	return cost, nil
+}
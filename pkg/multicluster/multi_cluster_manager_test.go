@@ -69,3 +69,13 @@ func TestDecodeJsonToRuntimeObject(t *testing.T) {
 	}
 	g.Expect(expectedDeployment).To(gomega.Equal(actualDeployment))
 }
+
+func TestParseClusterCost(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cost, err := ParseClusterCost(map[string]string{"CostTier": "2", "EgressCost": "0.09"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cost).To(gomega.Equal(ClusterCost{Tier: 2, Egress: 0.09}))
+
+	_, err = ParseClusterCost(map[string]string{"Capacity": "many"})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 
 	"emperror.dev/errors"
 	"github.com/IBM/satcon-client-go/client/types"
@@ -35,14 +36,20 @@ func init() {
 	_ = v1alpha1.AddToScheme(scheme)
 }
 
+// ClusterManager deploys the blueprints through channels and subscriptions of Razee.
+// The requests to Razee are serialized since the client of the API is not safe for concurrent use,
+// the blueprints of different clusters are thus not deployed concurrently.
 type ClusterManager struct {
 	orgID        string
 	clusterGroup string
 	con          client.SatCon
 	log          logr.Logger
+	mutex        sync.Mutex
 }
 
 func (r *ClusterManager) GetClusters() ([]multicluster.Cluster, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	var clusters []multicluster.Cluster
 	var razeeClusters []types.Cluster
 	var err error
@@ -79,12 +86,25 @@ func (r *ClusterManager) GetClusters() ([]multicluster.Cluster, error) {
 		if err != nil {
 			return nil, err
 		}
+		mirrors, err := multicluster.ParseRegistryMirrors(clusterMetadataConfigmap.Data["RegistryMirrors"])
+		if err != nil {
+			r.log.Error(err, "Invalid registry mirrors", "cluster", c.Name)
+			return nil, err
+		}
+		cost, err := multicluster.ParseClusterCost(clusterMetadataConfigmap.Data)
+		if err != nil {
+			r.log.Error(err, "Invalid cluster cost", "cluster", c.Name)
+			return nil, err
+		}
 		cluster := multicluster.Cluster{
 			Name: clusterMetadataConfigmap.Data["ClusterName"],
 			Metadata: multicluster.ClusterMetadata{
-				Region:        clusterMetadataConfigmap.Data["Region"],
-				Zone:          clusterMetadataConfigmap.Data["Zone"],
-				VaultAuthPath: clusterMetadataConfigmap.Data["VaultAuthPath"],
+				Region:          clusterMetadataConfigmap.Data["Region"],
+				Zone:            clusterMetadataConfigmap.Data["Zone"],
+				VaultAuthPath:   clusterMetadataConfigmap.Data["VaultAuthPath"],
+				VaultAddress:    clusterMetadataConfigmap.Data["VaultAddress"],
+				RegistryMirrors: mirrors,
+				Cost:            cost,
 			},
 		}
 		clusters = append(clusters, cluster)
@@ -97,6 +117,8 @@ func createBluePrintSelfLink(namespace string, name string) string {
 }
 
 func (r *ClusterManager) GetBlueprint(clusterName string, namespace string, name string) (*v1alpha1.Blueprint, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	selfLink := createBluePrintSelfLink(namespace, name)
 	cluster, err := r.con.Clusters.ClusterByName(r.orgID, clusterName)
 	if err != nil {
@@ -139,6 +161,8 @@ type Collection struct {
 }
 
 func (r *ClusterManager) CreateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	groupName := getGroupName(cluster)
 	channelName := channelName(cluster, blueprint.Name)
 	version := "0"
@@ -194,7 +218,7 @@ func (r *ClusterManager) CreateBlueprint(cluster string, blueprint *v1alpha1.Blu
 	if existingChannel != nil {
 		// Channel already exists. Update channel instead of creating
 		r.log.Info("Channel already exists! Updating channel version...", "existingChannel", existingChannel)
-		return r.UpdateBlueprint(cluster, blueprint)
+		return r.updateBlueprint(cluster, blueprint)
 	}
 
 	// create channel
@@ -241,6 +265,13 @@ func (r *ClusterManager) CreateBlueprint(cluster string, blueprint *v1alpha1.Blu
 }
 
 func (r *ClusterManager) UpdateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.updateBlueprint(cluster, blueprint)
+}
+
+// updateBlueprint adds a version of the channel of the blueprint. It is called with the lock held.
+func (r *ClusterManager) updateBlueprint(cluster string, blueprint *v1alpha1.Blueprint) error {
 	channelName := channelName(cluster, blueprint.Name)
 
 	content, err := yaml.Marshal(blueprint)
@@ -294,6 +325,8 @@ func (r *ClusterManager) UpdateBlueprint(cluster string, blueprint *v1alpha1.Blu
 }
 
 func (r *ClusterManager) DeleteBlueprint(cluster string, namespace string, name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	channelName := channelName(cluster, name)
 	channel, err := r.con.Channels.ChannelByName(r.orgID, channelName)
 	if err != nil {
@@ -10,6 +10,11 @@ import (
 // +kubebuilder:pruning:PreserveUnknownFields
 type Arbitrary struct {
 	Data interface{} `json:"-"`
+	// Encoding is the compact encoding in which the data is marshaled, or empty to marshal it as plain JSON.
+	// It is set when unmarshaling encoded data, such that the data is encoded again when marshaled.
+	Encoding string `json:"-"`
+	// Schema tags the encoded data, e.g. with the name of its protobuf message
+	Schema string `json:"-"`
 }
 
 func NewArbitrary(in interface{}) *Arbitrary {
@@ -28,11 +33,27 @@ func (in *Arbitrary) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &in.Data); err != nil {
 		return err
 	}
+	in.Encoding, in.Schema = "", ""
+	decoded, encoding, schema, found, err := decodeEnvelope(in.Data)
+	if err != nil {
+		return err
+	}
+	if found {
+		in.Data, in.Encoding, in.Schema = decoded, encoding, schema
+	}
 	return nil
 }
 
-func (in *Arbitrary) MarshalJSON() ([]byte, error) {
-	return json.Marshal(in.Data)
+// MarshalJSON has a value receiver, such that the values held in maps, which are not addressable, are marshaled as well
+func (in Arbitrary) MarshalJSON() ([]byte, error) {
+	if in.Encoding == "" {
+		return json.Marshal(in.Data)
+	}
+	envelope, err := in.envelope()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope)
 }
 
 func (in *Arbitrary) Into(target interface{}) error {
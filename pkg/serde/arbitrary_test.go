@@ -23,6 +23,12 @@ var _ = Describe("Arbitrary", func() {
 		Expect(string(raw)).To(Equal(`{"text":"abc"}`))
 	})
 
+	It("marshals map values correctly", func() {
+		raw, err := json.Marshal(map[string]serde.Arbitrary{"example": *arbitrary})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(raw)).To(Equal(`{"example":{"text":"abc"}}`))
+	})
+
 	It("unmarshals correctly", func() {
 		target := &serde.Arbitrary{}
 		err := json.Unmarshal([]byte(`{"text":"abc"}`), target)
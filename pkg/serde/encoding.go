@@ -0,0 +1,267 @@
+package serde
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sync"
+
+	"emperror.dev/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	// GzipEncoding compresses the JSON representation of the data
+	GzipEncoding = "gzip"
+	// ProtobufEncoding encodes the data in the protobuf wire format of the message named by the schema of the envelope
+	ProtobufEncoding = "protobuf"
+)
+
+// Keys of the envelope holding encoded data in JSON
+const (
+	envelopeEncodingKey = "$encoding"
+	envelopeSchemaKey   = "$schema"
+	envelopeDataKey     = "$data"
+)
+
+// Codec encodes the JSON representation of arbitrary data, as unmarshaled into an interface{}, in a compact form.
+// The schema describes the data, e.g. the full name of a protobuf message, and may be empty.
+type Codec interface {
+	Encode(data interface{}, schema string) ([]byte, error)
+	Decode(raw []byte, schema string) (interface{}, error)
+}
+
+var (
+	codecsMutex sync.RWMutex
+	codecs      = map[string]Codec{
+		GzipEncoding:     gzipCodec{},
+		ProtobufEncoding: protobufCodec{},
+	}
+)
+
+// RegisterCodec registers the codec of an encoding, such that it can be used by NewCompactArbitrary and
+// decoded by Arbitrary, e.g. to add an Avro encoding
+func RegisterCodec(encoding string, codec Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+	codecs[encoding] = codec
+}
+
+func codecOf(encoding string) (Codec, error) {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+	codec, found := codecs[encoding]
+	if !found {
+		return nil, fmt.Errorf("unknown encoding %s", encoding)
+	}
+	return codec, nil
+}
+
+// NewCompactArbitrary wraps data that is marshaled to JSON as an envelope holding the data in the given encoding.
+// The envelope is tagged with the full name of the protobuf message of the data if it is one.
+// The data is marshaled as plain JSON if the encoding is empty or if the data cannot be encoded.
+func NewCompactArbitrary(in interface{}, encoding string) *Arbitrary {
+	arbitrary := &Arbitrary{Data: in}
+	if encoding == "" {
+		return arbitrary
+	}
+	if message, ok := in.(proto.Message); ok {
+		arbitrary.Schema = string(message.ProtoReflect().Descriptor().FullName())
+	}
+	arbitrary.Encoding = encoding
+	if _, err := arbitrary.envelope(); err != nil {
+		arbitrary.Encoding, arbitrary.Schema = "", ""
+	}
+	return arbitrary
+}
+
+// envelope encodes the data into its envelope
+func (in *Arbitrary) envelope() (map[string]interface{}, error) {
+	codec, err := codecOf(in.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	data, err := toJSONValue(in.Data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := codec.Encode(data, in.Schema)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not encode data in %s", in.Encoding)
+	}
+	envelope := map[string]interface{}{
+		envelopeEncodingKey: in.Encoding,
+		envelopeDataKey:     base64.StdEncoding.EncodeToString(raw),
+	}
+	if in.Schema != "" {
+		envelope[envelopeSchemaKey] = in.Schema
+	}
+	return envelope, nil
+}
+
+// decodeEnvelope returns the decoded data, encoding and schema of an envelope, or found=false if the value is not one
+func decodeEnvelope(value interface{}) (data interface{}, encoding string, schema string, found bool, err error) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, "", "", false, nil
+	}
+	encoding, hasEncoding := fields[envelopeEncodingKey].(string)
+	encoded, hasData := fields[envelopeDataKey].(string)
+	if !hasEncoding || !hasData {
+		return nil, "", "", false, nil
+	}
+	schema, _ = fields[envelopeSchemaKey].(string)
+	codec, err := codecOf(encoding)
+	if err != nil {
+		return nil, "", "", true, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", "", true, errors.Wrapf(err, "invalid %s data", encoding)
+	}
+	data, err = codec.Decode(raw, schema)
+	if err != nil {
+		return nil, "", "", true, errors.Wrapf(err, "could not decode %s data", encoding)
+	}
+	return data, encoding, schema, true, nil
+}
+
+// Expand replaces the envelopes found in the JSON representation of data, as unmarshaled into an interface{},
+// by the data they hold, e.g. before passing the arguments of modules to their charts
+func Expand(value interface{}) (interface{}, error) {
+	if data, _, _, found, err := decodeEnvelope(value); found {
+		return data, err
+	}
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			expanded, err := Expand(item)
+			if err != nil {
+				return nil, err
+			}
+			typed[key] = expanded
+		}
+	case []interface{}:
+		for i, item := range typed {
+			expanded, err := Expand(item)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = expanded
+		}
+	}
+	return value, nil
+}
+
+// Decode unmarshals JSON into target, decoding the envelopes it contains
+func Decode(raw []byte, target interface{}) error {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+	expanded, err := Expand(value)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(expanded)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plain, target)
+}
+
+// toJSONValue returns the JSON representation of data as unmarshaled into an interface{}
+func toJSONValue(in interface{}) (interface{}, error) {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(raw, &value)
+	return value, err
+}
+
+// gzipCodec compresses the JSON representation of the data
+type gzipCodec struct{}
+
+func (gzipCodec) Encode(data interface{}, schema string) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (gzipCodec) Decode(raw []byte, schema string) (interface{}, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	plain, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	err = json.Unmarshal(plain, &data)
+	return data, err
+}
+
+// protobufCodec encodes data in the protobuf message named by the schema. The JSON representation of the data is
+// the one of the generated Go type of the message, and data that does not match the message exactly is rejected.
+type protobufCodec struct{}
+
+func newMessage(schema string) (proto.Message, error) {
+	if schema == "" {
+		return nil, errors.New("the protobuf encoding requires the name of a message as schema")
+	}
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(schema))
+	if err != nil {
+		return nil, err
+	}
+	return messageType.New().Interface(), nil
+}
+
+func (protobufCodec) Encode(data interface{}, schema string) ([]byte, error) {
+	message, err := newMessage(schema)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, message); err != nil {
+		return nil, err
+	}
+	// fields unknown to the message would be lost
+	if roundtrip, err := toJSONValue(message); err != nil || !reflect.DeepEqual(roundtrip, data) {
+		return nil, fmt.Errorf("data does not match message %s", schema)
+	}
+	return proto.Marshal(message)
+}
+
+func (protobufCodec) Decode(raw []byte, schema string) (interface{}, error) {
+	message, err := newMessage(schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(raw, message); err != nil {
+		return nil, err
+	}
+	return toJSONValue(message)
+}
@@ -0,0 +1,73 @@
+package serde_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/mesh-for-data/mesh-for-data/pkg/serde"
+)
+
+var _ = Describe("Compact encoding", func() {
+	datastore := &pb.DataStore{
+		Type: pb.DataStore_S3,
+		Name: "cos",
+		S3:   &pb.S3DataStore{Endpoint: "s3.eu-gb.cloud-object-storage.appdomain.cloud", Bucket: "m4d-test-bucket", ObjectKey: "small.parq"},
+	}
+
+	for _, encoding := range []string{serde.ProtobufEncoding, serde.GzipEncoding} {
+		encoding := encoding
+		It("roundtrips "+encoding, func() {
+			arbitrary := serde.NewCompactArbitrary(datastore, encoding)
+			Expect(arbitrary.Encoding).To(Equal(encoding))
+			Expect(arbitrary.Schema).To(Equal("connectors.DataStore"))
+			raw, err := json.Marshal(arbitrary)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(raw)).To(ContainSubstring(`"$encoding":"` + encoding + `"`))
+
+			// the envelope is decoded transparently, and encoded again when marshaled
+			target := &serde.Arbitrary{}
+			Expect(json.Unmarshal(raw, target)).To(Succeed())
+			Expect(target.Encoding).To(Equal(encoding))
+			result := &pb.DataStore{}
+			Expect(target.Into(result)).To(Succeed())
+			Expect(result.S3.Bucket).To(Equal("m4d-test-bucket"))
+			again, err := json.Marshal(target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Unmarshal(again, target)).To(Succeed())
+			Expect(target.Into(result)).To(Succeed())
+			Expect(result.S3.ObjectKey).To(Equal("small.parq"))
+		})
+	}
+
+	It("keeps data that does not match the message as plain JSON", func() {
+		arbitrary := serde.NewCompactArbitrary(map[string]interface{}{"text": "abc"}, serde.ProtobufEncoding)
+		Expect(arbitrary.Encoding).To(BeEmpty())
+		raw, err := json.Marshal(arbitrary)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(raw)).To(Equal(`{"text":"abc"}`))
+	})
+
+	It("expands the envelopes of nested data", func() {
+		raw, err := json.Marshal(map[string]interface{}{
+			"source": serde.NewCompactArbitrary(datastore, serde.GzipEncoding),
+			"assets": []interface{}{serde.NewCompactArbitrary(datastore, serde.ProtobufEncoding)},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		target := struct {
+			Source *pb.DataStore   `json:"source"`
+			Assets []*pb.DataStore `json:"assets"`
+		}{}
+		Expect(serde.Decode(raw, &target)).To(Succeed())
+		Expect(target.Source.Name).To(Equal("cos"))
+		Expect(target.Assets[0].S3.Endpoint).To(Equal(datastore.S3.Endpoint))
+	})
+
+	It("rejects unknown encodings", func() {
+		target := &serde.Arbitrary{}
+		err := json.Unmarshal([]byte(`{"$encoding":"avro","$data":"AA=="}`), target)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,75 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// DatasetRequest is a request to provision storage, as passed to CreateDataset
+type DatasetRequest struct {
+	Ref     types.NamespacedName
+	Storage *ProvisionedStorage
+	Owner   types.NamespacedName
+}
+
+// BatchOptions limit the load put on the API server by the provisioning of many datasets at once
+type BatchOptions struct {
+	// Workers is the number of datasets created in parallel
+	Workers int
+	// QPS is the number of datasets created per second
+	QPS float32
+	// Burst is the number of datasets that may be created at once above QPS
+	Burst int
+}
+
+// DefaultBatchOptions creates a few datasets in parallel, as the default rate limits of a client would allow
+func DefaultBatchOptions() BatchOptions {
+	return BatchOptions{Workers: 4, QPS: 5, Burst: 10}
+}
+
+// CreateDatasets provisions the storage of the requests in parallel, limiting the rate of the creations.
+// The returned errors are in the order of the requests, nil for the storage that has been provisioned.
+func CreateDatasets(p ProvisionInterface, requests []DatasetRequest, options BatchOptions) []error {
+	errs := make([]error, len(requests))
+	if len(requests) == 0 {
+		return errs
+	}
+	workers := options.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+	var limiter flowcontrol.RateLimiter
+	if options.QPS > 0 {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(options.QPS, options.Burst)
+		defer limiter.Stop()
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if limiter != nil {
+					limiter.Accept()
+				}
+				request := requests[i]
+				errs[i] = p.CreateDataset(&request.Ref, request.Storage, &request.Owner)
+			}
+		}()
+	}
+	for i := range requests {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCreateDatasets(t *testing.T) {
+	provision := NewProvisionTest()
+	registry := NewRegistry(nil)
+	Register("test-batch", func(_ client.Client) ProvisionInterface { return provision })
+	owner := types.NamespacedName{Name: "notebook", Namespace: "default"}
+
+	var requests []DatasetRequest
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("bucket-%d", i)
+		requests = append(requests, DatasetRequest{
+			Ref:     types.NamespacedName{Name: name, Namespace: "m4d-system"},
+			Storage: &ProvisionedStorage{Name: name, Type: "test-batch"},
+			Owner:   owner,
+		})
+	}
+	// the storage of an unknown type can not be provisioned
+	requests[7].Storage.Type = "unknown"
+
+	errs := CreateDatasets(registry, requests, BatchOptions{Workers: 4, QPS: 1000, Burst: 20})
+	assert.Len(t, errs, len(requests))
+	for i, err := range errs {
+		if i == 7 {
+			assert.NotNil(t, err)
+			continue
+		}
+		assert.Nil(t, err)
+		status, err := provision.GetDatasetStatus(&requests[i].Ref)
+		assert.Nil(t, err)
+		assert.True(t, status.Provisioned)
+	}
+	_, err := provision.GetDatasetStatus(&requests[7].Ref)
+	assert.NotNil(t, err)
+
+	// the status of the datasets is listed by their owner
+	statuses, err := provision.ListDatasetStatuses(&owner)
+	assert.Nil(t, err)
+	assert.Len(t, statuses, len(requests)-1)
+	assert.True(t, statuses["bucket-0"].Provisioned)
+	statuses, err = provision.ListDatasetStatuses(&types.NamespacedName{Name: "analytics", Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Empty(t, statuses)
+
+	assert.Empty(t, CreateDatasets(registry, nil, DefaultBatchOptions()))
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// DataStoreDescriber is implemented by provisioners that describe the connection to the storage they provision.
+// The description is passed to the modules writing to the storage and registered in the data catalog.
+type DataStoreDescriber interface {
+	DescribeDataStore(storage *ProvisionedStorage, objectKey string) (*pb.DataStore, error)
+}
+
+// DescribeDataStore returns the connection to an object of the provisioned storage, e.g. an object prefix of an S3 bucket.
+// The provisioner of the type of the storage describes the connection if it implements DataStoreDescriber.
+// Otherwise S3 storage is described as an S3 data store, and an error is returned for other types of storage.
+func DescribeDataStore(p ProvisionInterface, storage *ProvisionedStorage, objectKey string) (*pb.DataStore, error) {
+	if describer, ok := ForType(p, storage.Type).(DataStoreDescriber); ok {
+		return describer.DescribeDataStore(storage, objectKey)
+	}
+	if storage.Type != "" && storage.Type != S3 {
+		return nil, fmt.Errorf("the connection to storage of type %s cannot be described", storage.Type)
+	}
+	return describeS3(storage, objectKey), nil
+}
+
+// DescribeDataStore describes the bucket as an S3 data store
+func (r *ProvisionImpl) DescribeDataStore(storage *ProvisionedStorage, objectKey string) (*pb.DataStore, error) {
+	return describeS3(storage, objectKey), nil
+}
+
+func describeS3(storage *ProvisionedStorage, objectKey string) *pb.DataStore {
+	return &pb.DataStore{
+		Type: pb.DataStore_S3,
+		Name: "S3",
+		S3: &pb.S3DataStore{
+			Bucket:    storage.Name,
+			Endpoint:  strings.TrimPrefix(storage.Endpoint, "http://"),
+			ObjectKey: objectKey,
+		},
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"testing"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// blobProvisioner describes the provisioned containers as Kafka topics, standing for a provider-specific data store
+type blobProvisioner struct {
+	*ProvisionTest
+}
+
+func (b *blobProvisioner) DescribeDataStore(storage *ProvisionedStorage, objectKey string) (*pb.DataStore, error) {
+	return &pb.DataStore{
+		Type:  pb.DataStore_KAFKA,
+		Kafka: &pb.KafkaDataStore{TopicName: storage.Details["container"] + "/" + objectKey},
+	}, nil
+}
+
+func TestDescribeDataStore(t *testing.T) {
+	Register("test-describer", func(c client.Client) ProvisionInterface { return &blobProvisioner{NewProvisionTest()} })
+	registry := NewRegistry(nil)
+
+	bucket := &ProvisionedStorage{Name: "bucket", Endpoint: "http://s3.eu.cloud-object-storage.appdomain.cloud"}
+	datastore, err := DescribeDataStore(registry, bucket, "asset")
+	assert.Nil(t, err)
+	assert.Equal(t, pb.DataStore_S3, datastore.Type)
+	assert.Equal(t, "s3.eu.cloud-object-storage.appdomain.cloud", datastore.S3.Endpoint)
+	assert.Equal(t, "asset", datastore.S3.ObjectKey)
+
+	// provisioners without a description of their data store are used for S3 storage
+	datastore, err = DescribeDataStore(NewProvisionTest(), bucket, "asset")
+	assert.Nil(t, err)
+	assert.Equal(t, "bucket", datastore.S3.Bucket)
+
+	container := &ProvisionedStorage{Name: "container", Type: "test-describer", Details: map[string]string{"container": "data"}}
+	datastore, err = DescribeDataStore(registry, container, "asset")
+	assert.Nil(t, err)
+	assert.Equal(t, "data/asset", datastore.Kafka.TopicName)
+
+	_, err = DescribeDataStore(registry, &ProvisionedStorage{Name: "bucket", Type: "unknown"}, "asset")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// S3 is the default type of storage accounts: S3 buckets provisioned through Dataset resources
+const S3 = "s3"
+
+// Factory creates the provisioner of a type of storage accounts
+type Factory func(c client.Client) ProvisionInterface
+
+var (
+	factoriesMutex sync.RWMutex
+	factories      = map[string]Factory{
+		S3: func(c client.Client) ProvisionInterface { return NewProvisionImpl(c) },
+	}
+)
+
+// Register makes a provisioner available for the storage accounts of the given type, e.g. azure-blob or gcs.
+// It is typically called from the init function of the package implementing the provisioner.
+// Registering a type twice replaces the previous provisioner.
+func Register(storageType string, factory Factory) {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+	factories[storageType] = factory
+}
+
+// TypeSelector is implemented by provisioners that manage several types of storage
+type TypeSelector interface {
+	ForType(storageType string) ProvisionInterface
+}
+
+// ForType returns the provisioner of the given type of storage if p manages several types, and p otherwise
+func ForType(p ProvisionInterface, storageType string) ProvisionInterface {
+	if selector, ok := p.(TypeSelector); ok {
+		return selector.ForType(storageType)
+	}
+	return p
+}
+
+// Registry dispatches the provisioning of storage to the provisioner registered for the type of the storage account.
+// Operations that only receive a reference to the provisioned storage are done by the S3 provisioner;
+// use ForType to select the provisioner of another type.
+type Registry struct {
+	client       client.Client
+	mutex        sync.Mutex
+	provisioners map[string]ProvisionInterface
+}
+
+// NewRegistry returns a Registry creating the registered provisioners on demand
+func NewRegistry(c client.Client) *Registry {
+	return &Registry{
+		client:       c,
+		provisioners: make(map[string]ProvisionInterface),
+	}
+}
+
+// ForType returns the provisioner of the given type of storage. An empty type stands for S3.
+// A provisioner failing all operations is returned if no provisioner has been registered for the type.
+func (r *Registry) ForType(storageType string) ProvisionInterface {
+	if storageType == "" {
+		storageType = S3
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if provisioner, found := r.provisioners[storageType]; found {
+		return provisioner
+	}
+	factoriesMutex.RLock()
+	factory, found := factories[storageType]
+	factoriesMutex.RUnlock()
+	if !found {
+		return &unsupportedType{storageType: storageType}
+	}
+	provisioner := factory(r.client)
+	r.provisioners[storageType] = provisioner
+	return provisioner
+}
+
+// CreateDataset provisions the storage using the provisioner of its type
+func (r *Registry) CreateDataset(ref *types.NamespacedName, dataset *ProvisionedStorage, owner *types.NamespacedName) error {
+	return r.ForType(dataset.Type).CreateDataset(ref, dataset, owner)
+}
+
+// DeleteDataset deletes S3 storage
+func (r *Registry) DeleteDataset(ref *types.NamespacedName) error {
+	return r.ForType(S3).DeleteDataset(ref)
+}
+
+// GetDatasetStatus returns the status of S3 storage
+func (r *Registry) GetDatasetStatus(ref *types.NamespacedName) (*ProvisionedStorageStatus, error) {
+	return r.ForType(S3).GetDatasetStatus(ref)
+}
+
+// ListDatasetStatuses returns the status of the S3 storage provisioned for the owner
+func (r *Registry) ListDatasetStatuses(owner *types.NamespacedName) (map[string]*ProvisionedStorageStatus, error) {
+	return r.ForType(S3).ListDatasetStatuses(owner)
+}
+
+// SetPersistent marks S3 storage as persistent
+func (r *Registry) SetPersistent(ref *types.NamespacedName, persistent bool) error {
+	return r.ForType(S3).SetPersistent(ref, persistent)
+}
+
+// Detach detaches S3 storage from its owner
+func (r *Registry) Detach(ref *types.NamespacedName, secretRef types.NamespacedName) error {
+	return r.ForType(S3).Detach(ref, secretRef)
+}
+
+// unsupportedType is returned for types of storage without a registered provisioner
+type unsupportedType struct {
+	storageType string
+}
+
+func (u *unsupportedType) err() error {
+	return fmt.Errorf("no provisioner has been registered for storage of type %s", u.storageType)
+}
+
+func (u *unsupportedType) CreateDataset(ref *types.NamespacedName, dataset *ProvisionedStorage, owner *types.NamespacedName) error {
+	return u.err()
+}
+
+func (u *unsupportedType) DeleteDataset(ref *types.NamespacedName) error {
+	return u.err()
+}
+
+func (u *unsupportedType) GetDatasetStatus(ref *types.NamespacedName) (*ProvisionedStorageStatus, error) {
+	return nil, u.err()
+}
+
+func (u *unsupportedType) ListDatasetStatuses(owner *types.NamespacedName) (map[string]*ProvisionedStorageStatus, error) {
+	return nil, u.err()
+}
+
+func (u *unsupportedType) SetPersistent(ref *types.NamespacedName, persistent bool) error {
+	return u.err()
+}
+
+func (u *unsupportedType) Detach(ref *types.NamespacedName, secretRef types.NamespacedName) error {
+	return u.err()
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRegistry(t *testing.T) {
+	blob := NewProvisionTest()
+	Register("test-blob", func(c client.Client) ProvisionInterface { return blob })
+	registry := NewRegistry(nil)
+
+	// the S3 provisioner is registered by default
+	_, isDataset := registry.ForType("").(*ProvisionImpl)
+	assert.True(t, isDataset)
+	assert.Same(t, registry.ForType(S3), registry.ForType(""))
+
+	ref := &types.NamespacedName{Name: "container", Namespace: "m4d-system"}
+	owner := &types.NamespacedName{Name: "notebook", Namespace: "default"}
+	assert.Nil(t, registry.CreateDataset(ref, &ProvisionedStorage{Name: "container", Type: "test-blob"}, owner))
+	assert.Nil(t, ForType(registry, "test-blob").SetPersistent(ref, true))
+	assert.Nil(t, ForType(registry, "test-blob").DeleteDataset(ref))
+
+	// provisioners that do not manage several types are used for all types
+	assert.Same(t, blob, ForType(blob, S3))
+
+	err := registry.CreateDataset(ref, &ProvisionedStorage{Name: "bucket", Type: "unknown"}, owner)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown")
+}
@@ -0,0 +1,507 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// BucketRecordLabel marks the ConfigMap resources recording the buckets provisioned through the S3 API
+	BucketRecordLabel = "m4d.ibm.com/bucket-record"
+	// removeOnDeleteLabel marks the storage that is removed together with its record, like the Dataset resources do
+	removeOnDeleteLabel = "remove-on-delete"
+)
+
+// Details of the storage accounts read by the S3 API provisioner
+const (
+	// BucketDetail names a bucket shared by the storage of all the applications, each under its own prefix.
+	// A bucket is created for each provisioned storage if it is not set.
+	BucketDetail = "bucket"
+	// RegionDetail is the region of the buckets, us-east-1 by default
+	RegionDetail = "region"
+	// ExpirationDaysDetail is the number of days after which the objects of temporary storage expire.
+	// The expiry reclaims the storage of temporary copies that have not been deleted by the manager.
+	// It requires a bucket for each storage: a bucket holds at most 1000 lifecycle rules, which would limit
+	// the number of copies in a shared bucket.
+	ExpirationDaysDetail = "expirationDays"
+)
+
+// Alternative secret keys of the credentials of S3 storage accounts
+var (
+	accessKeySecretKeys = []string{"accessKeyID", "accessKey", "access_key"}
+	secretKeySecretKeys = []string{"secretAccessKey", "secretKey", "SecretKey", "secret_key"}
+)
+
+// S3APIProvisioner is an implementation of ProvisionInterface creating buckets directly through the S3 API of the
+// storage accounts, for the environments in which the Dataset CRD is not installed.
+// Each provisioned storage is recorded in a ConfigMap resource named after the storage, which plays the role of
+// the Dataset resource: it is labeled with the owner of the storage and holds the status of the provisioning.
+// The storage is either a bucket, or a prefix of the bucket shared by the applications if the storage account sets one.
+// The objects of temporary storage expire according to the lifecycle rule of its bucket, if the storage account sets
+// an expiry, and the rule is removed once the storage becomes persistent.
+type S3APIProvisioner struct {
+	Client     client.Client
+	HTTPClient *http.Client
+	// mutex serializes the updates of the lifecycle configuration of the buckets
+	mutex sync.Mutex
+}
+
+// NewS3APIProvisioner returns a new S3APIProvisioner
+func NewS3APIProvisioner(c client.Client) *S3APIProvisioner {
+	return &S3APIProvisioner{
+		Client:     c,
+		HTTPClient: &http.Client{Timeout: time.Minute},
+	}
+}
+
+// bucketRecord locates provisioned storage in the S3 API
+type bucketRecord struct {
+	endpoint       string
+	region         string
+	bucket         string
+	prefix         string
+	secretRef      types.NamespacedName
+	expirationDays int
+}
+
+// newBucketRecord returns the location of the storage in a bucket of its own, or under its own prefix of a shared bucket
+func newBucketRecord(storage *ProvisionedStorage) (*bucketRecord, error) {
+	record := &bucketRecord{
+		endpoint:  storage.Endpoint,
+		region:    storage.Details[RegionDetail],
+		bucket:    storage.Name,
+		secretRef: storage.SecretRef,
+	}
+	if shared := storage.Details[BucketDetail]; shared != "" {
+		record.bucket = shared
+		record.prefix = storage.Name + "/"
+	}
+	if days := storage.Details[ExpirationDaysDetail]; days != "" {
+		expirationDays, err := strconv.Atoi(days)
+		if err != nil || expirationDays < 1 {
+			return nil, fmt.Errorf("invalid %s of the storage account: %s", ExpirationDaysDetail, days)
+		}
+		if record.prefix != "" {
+			return nil, fmt.Errorf("the %s of the storage account cannot be set together with a shared %s", ExpirationDaysDetail, BucketDetail)
+		}
+		record.expirationDays = expirationDays
+	}
+	return record, nil
+}
+
+func (b *bucketRecord) data() map[string]string {
+	return map[string]string{
+		"endpoint":         b.endpoint,
+		"region":           b.region,
+		"bucket":           b.bucket,
+		"prefix":           b.prefix,
+		"secret-name":      b.secretRef.Name,
+		"secret-namespace": b.secretRef.Namespace,
+		"expiration-days":  strconv.Itoa(b.expirationDays),
+	}
+}
+
+func recordFromData(data map[string]string) *bucketRecord {
+	days, _ := strconv.Atoi(data["expiration-days"])
+	return &bucketRecord{
+		endpoint:       data["endpoint"],
+		region:         data["region"],
+		bucket:         data["bucket"],
+		prefix:         data["prefix"],
+		secretRef:      types.NamespacedName{Name: data["secret-name"], Namespace: data["secret-namespace"]},
+		expirationDays: days,
+	}
+}
+
+// ruleID identifies the lifecycle rule of the storage in its bucket
+func (b *bucketRecord) ruleID() string {
+	return b.bucket
+}
+
+func (r *S3APIProvisioner) getRecord(ref *types.NamespacedName) (*corev1.ConfigMap, error) {
+	record := &corev1.ConfigMap{}
+	if err := r.Client.Get(context.Background(), *ref, record); err != nil {
+		return nil, err
+	}
+	if record.Labels[BucketRecordLabel] != "true" {
+		return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), ref.Name)
+	}
+	return record, nil
+}
+
+// s3Client returns a client of the S3 API of the storage, using the credentials of the secret of the storage account
+func (r *S3APIProvisioner) s3Client(record *bucketRecord) (*s3Client, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(context.Background(), record.secretRef, secret); err != nil {
+		return nil, err
+	}
+	accessKey := secretValue(secret, accessKeySecretKeys)
+	secretKey := secretValue(secret, secretKeySecretKeys)
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("secret %s must contain an access key (%s) and a secret key (%s) to access the S3 API",
+			record.secretRef.Name, strings.Join(accessKeySecretKeys, ", "), strings.Join(secretKeySecretKeys, ", "))
+	}
+	return newS3Client(r.HTTPClient, record.endpoint, record.region, accessKey, secretKey)
+}
+
+// secretValue returns the first non-empty value of the keys in the secret
+func secretValue(secret *corev1.Secret, keys []string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(string(secret.Data[key])); value != "" {
+			return value
+		}
+		if value := strings.TrimSpace(secret.StringData[key]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// CreateDataset creates the bucket of the storage, or uses the shared bucket, and sets its lifecycle rule.
+// The outcome is recorded in the status of the record of the storage, and the provisioning is attempted again
+// when it has failed.
+func (r *S3APIProvisioner) CreateDataset(ref *types.NamespacedName, storage *ProvisionedStorage, owner *types.NamespacedName) error {
+	desired, err := newBucketRecord(storage)
+	if err != nil {
+		return err
+	}
+	existing, err := r.getRecord(ref)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if existing != nil && existing.Data["status"] == "OK" && *recordFromData(existing.Data) == *desired {
+		// update is not required
+		return nil
+	}
+	data := desired.data()
+	if err := r.provision(desired, existing == nil || existing.Labels[removeOnDeleteLabel] != "false"); err != nil {
+		data["status"] = "Failed"
+		data["info"] = err.Error()
+	} else {
+		data["status"] = "OK"
+	}
+	if existing == nil {
+		record := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ref.Name,
+				Namespace: ref.Namespace,
+				Labels: map[string]string{
+					BucketRecordLabel:   "true",
+					OwnerLabel:          ownerLabelValue(owner),
+					removeOnDeleteLabel: "true",
+				},
+			},
+			Data: data,
+		}
+		return r.Client.Create(context.Background(), record)
+	}
+	existing.Data = data
+	return r.Client.Update(context.Background(), existing)
+}
+
+// provision creates the bucket and sets the expiry of the objects of temporary storage
+func (r *S3APIProvisioner) provision(record *bucketRecord, temporary bool) error {
+	s3, err := r.s3Client(record)
+	if err != nil {
+		return err
+	}
+	if _, err := s3.do(http.MethodPut, record.bucket, "", nil, nil); err != nil && !isS3Error(err, "BucketAlreadyOwnedByYou") {
+		return err
+	}
+	if record.expirationDays == 0 {
+		return nil
+	}
+	return r.setLifecycleRule(s3, record, temporary)
+}
+
+// lifecycleConfiguration is the lifecycle configuration of a bucket.
+// The rules are kept as is, such that the rules set by others are preserved.
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+type lifecycleRule struct {
+	Inner string `xml:",innerxml"`
+}
+
+// ruleIdentifier reads the identifier of a lifecycle rule
+type ruleIdentifier struct {
+	ID string `xml:"ID"`
+}
+
+// expiryRule is the lifecycle rule expiring the objects of temporary storage
+type expiryRule struct {
+	ID     string `xml:"ID"`
+	Filter struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"Filter"`
+	Status     string `xml:"Status"`
+	Expiration struct {
+		Days int `xml:"Days"`
+	} `xml:"Expiration"`
+}
+
+// setLifecycleRule adds the expiry rule of the storage to the lifecycle configuration of its bucket if the storage
+// is temporary, and removes it otherwise. The rule expires all the objects of the bucket, which holds a single storage.
+func (r *S3APIProvisioner) setLifecycleRule(s3 *s3Client, record *bucketRecord, temporary bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	query := url.Values{"lifecycle": []string{""}}
+	config := &lifecycleConfiguration{}
+	data, err := s3.do(http.MethodGet, record.bucket, "", query, nil)
+	switch {
+	case isS3Error(err, "NoSuchLifecycleConfiguration"):
+	case err != nil:
+		return err
+	default:
+		if err := xml.Unmarshal(data, config); err != nil {
+			return err
+		}
+	}
+	rules := make([]lifecycleRule, 0, len(config.Rules)+1)
+	for _, rule := range config.Rules {
+		id := &ruleIdentifier{}
+		if err := xml.Unmarshal([]byte("<Rule>"+rule.Inner+"</Rule>"), id); err == nil && id.ID == record.ruleID() {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if temporary {
+		rule := expiryRule{ID: record.ruleID(), Status: "Enabled"}
+		rule.Filter.Prefix = record.prefix
+		rule.Expiration.Days = record.expirationDays
+		inner, err := xml.Marshal(&rule)
+		if err != nil {
+			return err
+		}
+		// the marshaled element is unwrapped, the rules being wrapped by their own element
+		inner = inner[len("<expiryRule>") : len(inner)-len("</expiryRule>")]
+		rules = append(rules, lifecycleRule{Inner: string(inner)})
+	}
+	if len(rules) == len(config.Rules) && !temporary {
+		// the storage had no rule
+		return nil
+	}
+	if len(rules) == 0 {
+		_, err = s3.do(http.MethodDelete, record.bucket, "", query, nil)
+		return err
+	}
+	body, err := xml.Marshal(&lifecycleConfiguration{Rules: rules})
+	if err != nil {
+		return err
+	}
+	_, err = s3.do(http.MethodPut, record.bucket, "", query, body)
+	return err
+}
+
+// listObjectsResult is the result of listing the objects of a bucket
+type listObjectsResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// deleteObjects is the request deleting multiple objects of a bucket
+type deleteObjects struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool     `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+// deleteResult is the result of deleting multiple objects, which lists the objects that could not be deleted
+type deleteResult struct {
+	Errors []struct {
+		Key     string `xml:"Key"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// deleteObjectsRequest returns the body of the request deleting the objects, at most 1000 of them
+func deleteObjectsRequest(keys []string) ([]byte, error) {
+	request := &deleteObjects{Quiet: true}
+	for _, key := range keys {
+		request.Objects = append(request.Objects, struct {
+			Key string `xml:"Key"`
+		}{Key: key})
+	}
+	return xml.Marshal(request)
+}
+
+// removeStorage deletes the objects of the storage, and its bucket unless the bucket is shared.
+// The objects are deleted by pages of at most 1000 objects, as listed.
+func (r *S3APIProvisioner) removeStorage(record *bucketRecord) error {
+	s3, err := r.s3Client(record)
+	if err != nil {
+		return err
+	}
+	token := ""
+	for {
+		query := url.Values{"list-type": []string{"2"}, "prefix": []string{record.prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		data, err := s3.do(http.MethodGet, record.bucket, "", query, nil)
+		if isS3Error(err, "NoSuchBucket") {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		result := &listObjectsResult{}
+		if err := xml.Unmarshal(data, result); err != nil {
+			return err
+		}
+		if len(result.Contents) > 0 {
+			keys := make([]string, 0, len(result.Contents))
+			for _, object := range result.Contents {
+				keys = append(keys, object.Key)
+			}
+			body, err := deleteObjectsRequest(keys)
+			if err != nil {
+				return err
+			}
+			data, err := s3.do(http.MethodPost, record.bucket, "", url.Values{"delete": []string{""}}, body)
+			if err != nil {
+				return err
+			}
+			deleted := &deleteResult{}
+			if err := xml.Unmarshal(data, deleted); err != nil {
+				return err
+			}
+			for _, failed := range deleted.Errors {
+				if failed.Code != "NoSuchKey" {
+					return &s3Error{StatusCode: http.StatusOK, Code: failed.Code, Message: "could not delete " + failed.Key + ": " + failed.Message}
+				}
+			}
+		}
+		if !result.IsTruncated {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	if record.expirationDays > 0 {
+		if err := r.setLifecycleRule(s3, record, false); err != nil {
+			return err
+		}
+	}
+	if record.prefix != "" {
+		return nil
+	}
+	if _, err := s3.do(http.MethodDelete, record.bucket, "", nil, nil); err != nil && !isS3Error(err, "NoSuchBucket") {
+		return err
+	}
+	return nil
+}
+
+// DeleteDataset deletes the record of the storage, and the storage itself unless it is persistent
+func (r *S3APIProvisioner) DeleteDataset(ref *types.NamespacedName) error {
+	existing, err := r.getRecord(ref)
+	if err != nil {
+		return err
+	}
+	if existing.Labels[removeOnDeleteLabel] != "false" {
+		if err := r.removeStorage(recordFromData(existing.Data)); err != nil {
+			return err
+		}
+	}
+	return r.Client.Delete(context.Background(), existing)
+}
+
+// GetDatasetStatus returns the status of the provisioning recorded for the storage
+func (r *S3APIProvisioner) GetDatasetStatus(ref *types.NamespacedName) (*ProvisionedStorageStatus, error) {
+	existing, err := r.getRecord(ref)
+	if err != nil {
+		return nil, err
+	}
+	return recordStatus(existing), nil
+}
+
+// ListDatasetStatuses returns the status of the storage provisioned for the owner, by the names of the storage
+func (r *S3APIProvisioner) ListDatasetStatuses(owner *types.NamespacedName) (map[string]*ProvisionedStorageStatus, error) {
+	list := &corev1.ConfigMapList{}
+	if err := r.Client.List(context.Background(), list,
+		client.MatchingLabels{BucketRecordLabel: "true", OwnerLabel: ownerLabelValue(owner)}); err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]*ProvisionedStorageStatus, len(list.Items))
+	for i := range list.Items {
+		statuses[list.Items[i].Name] = recordStatus(&list.Items[i])
+	}
+	return statuses, nil
+}
+
+func recordStatus(record *corev1.ConfigMap) *ProvisionedStorageStatus {
+	return &ProvisionedStorageStatus{Provisioned: record.Data["status"] == "OK", ErrorMsg: record.Data["info"]}
+}
+
+// SetPersistent marks the storage as persistent or temporary.
+// The objects of persistent storage do not expire, and the storage is kept when its record is deleted.
+func (r *S3APIProvisioner) SetPersistent(ref *types.NamespacedName, persistent bool) error {
+	existing, err := r.getRecord(ref)
+	if err != nil {
+		return err
+	}
+	record := recordFromData(existing.Data)
+	if record.expirationDays > 0 && existing.Data["status"] == "OK" {
+		s3, err := r.s3Client(record)
+		if err != nil {
+			return err
+		}
+		if err := r.setLifecycleRule(s3, record, !persistent); err != nil {
+			return err
+		}
+	}
+	existing.Labels[removeOnDeleteLabel] = strconv.FormatBool(!persistent)
+	return r.Client.Update(context.Background(), existing)
+}
+
+// Detach removes the owner of the storage, makes it persistent and references the given secret holding its credentials
+func (r *S3APIProvisioner) Detach(ref *types.NamespacedName, secretRef types.NamespacedName) error {
+	if err := r.SetPersistent(ref, true); err != nil {
+		return err
+	}
+	existing, err := r.getRecord(ref)
+	if err != nil {
+		return err
+	}
+	delete(existing.Labels, OwnerLabel)
+	existing.Data["secret-name"] = secretRef.Name
+	existing.Data["secret-namespace"] = secretRef.Namespace
+	return r.Client.Update(context.Background(), existing)
+}
+
+// DescribeDataStore describes the storage as an S3 data store, whose objects are under the prefix of the storage
+// if the bucket is shared
+func (r *S3APIProvisioner) DescribeDataStore(storage *ProvisionedStorage, objectKey string) (*pb.DataStore, error) {
+	record, err := newBucketRecord(storage)
+	if err != nil {
+		return nil, err
+	}
+	description := describeS3(storage, record.prefix+objectKey)
+	description.S3.Bucket = record.bucket
+	description.S3.Region = record.region
+	return description, nil
+}
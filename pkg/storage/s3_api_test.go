@@ -0,0 +1,178 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeS3 is an in-memory S3 API serving the requests of the S3 API provisioner
+type fakeS3 struct {
+	mutex      sync.Mutex
+	buckets    map[string]map[string]bool
+	lifecycles map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+		writeS3Error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	path := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)
+	bucket, objects := path[0], f.buckets[path[0]]
+	query := req.URL.Query()
+	switch {
+	case len(path) == 2:
+		// the objects are deleted together
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	case req.Method == http.MethodPost && strings.Contains(req.URL.RawQuery, "delete"):
+		request := &deleteObjects{}
+		body, _ := ioutil.ReadAll(req.Body)
+		if err := xml.Unmarshal(body, request); err != nil || req.Header.Get("Content-MD5") == "" {
+			writeS3Error(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+		for _, object := range request.Objects {
+			delete(objects, object.Key)
+		}
+		fmt.Fprint(w, "<DeleteResult></DeleteResult>")
+	case req.Method == http.MethodPut && !strings.Contains(req.URL.RawQuery, "lifecycle"):
+		if objects != nil {
+			writeS3Error(w, http.StatusConflict, "BucketAlreadyOwnedByYou")
+			return
+		}
+		f.buckets[bucket] = map[string]bool{}
+	case objects == nil:
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+	case strings.Contains(req.URL.RawQuery, "lifecycle"):
+		switch req.Method {
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(req.Body)
+			f.lifecycles[bucket] = string(body)
+		case http.MethodDelete:
+			delete(f.lifecycles, bucket)
+		default:
+			if _, found := f.lifecycles[bucket]; !found {
+				writeS3Error(w, http.StatusNotFound, "NoSuchLifecycleConfiguration")
+				return
+			}
+			fmt.Fprint(w, f.lifecycles[bucket])
+		}
+	case req.Method == http.MethodGet:
+		keys := []string{}
+		for key := range objects {
+			if strings.HasPrefix(key, query.Get("prefix")) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, "<ListBucketResult>")
+		for _, key := range keys {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+		}
+		fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+	case req.Method == http.MethodDelete:
+		delete(f.buckets, bucket)
+	}
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func TestS3APIProvisioner(t *testing.T) {
+	s3 := &fakeS3{buckets: map[string]map[string]bool{}, lifecycles: map[string]string{}}
+	server := httptest.NewServer(s3)
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "m4d-system"},
+		Data:       map[string][]byte{"access_key": []byte("access"), "secret_key": []byte("secret")},
+	}
+	p := NewS3APIProvisioner(fake.NewFakeClientWithScheme(clientgoscheme.Scheme, secret))
+	owner := &types.NamespacedName{Name: "notebook", Namespace: "default"}
+
+	// a bucket is created for each storage, and its objects expire while it is temporary
+	ref := &types.NamespacedName{Name: "dedicated", Namespace: "m4d-system"}
+	dedicated := &ProvisionedStorage{Name: "dedicated", Endpoint: server.URL, SecretRef: types.NamespacedName{Name: "credentials", Namespace: "m4d-system"},
+		Details: map[string]string{ExpirationDaysDetail: "7"}}
+	assert.Nil(t, p.CreateDataset(ref, dedicated, owner))
+	assert.Contains(t, s3.buckets, "dedicated")
+	assert.Contains(t, s3.lifecycles["dedicated"], "<Days>7</Days>")
+	status, err := p.GetDatasetStatus(ref)
+	assert.Nil(t, err)
+	assert.True(t, status.Provisioned)
+	// creating the storage again is idempotent
+	assert.Nil(t, p.CreateDataset(ref, dedicated, owner))
+
+	// the storage of the applications sharing a bucket are under their own prefixes, with their own lifecycle rules
+	s3.buckets["shared"] = map[string]bool{"other/object": true}
+	s3.lifecycles["shared"] = "<LifecycleConfiguration><Rule><ID>others</ID><Status>Enabled</Status></Rule></LifecycleConfiguration>"
+	sharedRef := &types.NamespacedName{Name: "copy", Namespace: "m4d-system"}
+	shared := &ProvisionedStorage{Name: "copy", Endpoint: server.URL, SecretRef: dedicated.SecretRef,
+		Details: map[string]string{BucketDetail: "shared", ExpirationDaysDetail: "1"}}
+	// the storage of a shared bucket does not expire, the bucket would need a lifecycle rule for each storage
+	assert.NotNil(t, p.CreateDataset(sharedRef, shared, owner))
+	delete(shared.Details, ExpirationDaysDetail)
+	assert.Nil(t, p.CreateDataset(sharedRef, shared, owner))
+	assert.Equal(t, "<LifecycleConfiguration><Rule><ID>others</ID><Status>Enabled</Status></Rule></LifecycleConfiguration>", s3.lifecycles["shared"])
+	description, err := p.DescribeDataStore(shared, "data.parq")
+	assert.Nil(t, err)
+	assert.Equal(t, "shared", description.S3.Bucket)
+	assert.Equal(t, "copy/data.parq", description.S3.ObjectKey)
+
+	statuses, err := p.ListDatasetStatuses(owner)
+	assert.Nil(t, err)
+	assert.Len(t, statuses, 2)
+
+	// persistent storage does not expire and is kept when deleted
+	assert.Nil(t, p.SetPersistent(ref, true))
+	assert.NotContains(t, s3.lifecycles, "dedicated")
+	assert.Nil(t, p.SetPersistent(ref, false))
+	assert.Contains(t, s3.lifecycles["dedicated"], "<Days>7</Days>")
+	assert.Nil(t, p.SetPersistent(sharedRef, true))
+	s3.buckets["shared"]["copy/data.parq"] = true
+	assert.Nil(t, p.DeleteDataset(sharedRef))
+	assert.Contains(t, s3.buckets["shared"], "copy/data.parq")
+
+	// temporary storage is removed with its objects, but the objects of the others are kept
+	assert.Nil(t, p.CreateDataset(sharedRef, shared, owner))
+	assert.Nil(t, p.SetPersistent(sharedRef, false))
+	assert.Nil(t, p.DeleteDataset(sharedRef))
+	assert.Equal(t, map[string]bool{"other/object": true}, s3.buckets["shared"])
+	assert.Contains(t, s3.lifecycles["shared"], "<ID>others</ID>")
+	s3.buckets["dedicated"]["data.parq"] = true
+	s3.buckets["dedicated"]["part/00001.parq"] = true
+	assert.Nil(t, p.DeleteDataset(ref))
+	assert.NotContains(t, s3.buckets, "dedicated")
+	_, err = p.GetDatasetStatus(ref)
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// failures are reported in the status of the storage
+	dedicated.SecretRef.Name = "missing"
+	assert.Nil(t, p.CreateDataset(ref, dedicated, owner))
+	status, err = p.GetDatasetStatus(ref)
+	assert.Nil(t, err)
+	assert.False(t, status.Provisioned)
+	assert.Contains(t, status.ErrorMsg, "missing")
+}
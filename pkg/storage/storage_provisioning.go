@@ -2,14 +2,20 @@
 // SPDX-License-Identifier: Apache-2.0
 
 /*
-	This package defines an interface for managing dynamically allocated S3 buckets.
-	The current implementation manages buckets using Dataset resources.
+	This package defines an interface for managing dynamically allocated storage, e.g. S3 buckets.
+	The default implementation manages S3 buckets using Dataset resources.
+	Implementations for other types of storage accounts are registered in a Registry (see registry.go).
 	Convention: Dataset resources have the same name as the name of the provisioned bucket.
 	The following functionality is supported:
 	- allocating a bucket
 	- checking allocation status
 	- deleting a temporary bucket
 	- marking a bucket as persistent (will not be removed upon Dataset deletion)
+	- finding the owner of a Dataset resource, to watch the provisioning status
+	- detaching a bucket from its owner, to be managed independently
+	- listing the status of the Dataset resources of an owner at once, by the owner label
+	- creating many Dataset resources in parallel (see batch.go)
+	- provisioning the buckets directly through the S3 API, without Dataset resources (see s3_api.go)
 */
 
 package storage
@@ -17,9 +23,12 @@ package storage
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -30,15 +39,25 @@ var (
 	GroupVersion = schema.GroupVersion{Group: "com.ie.ibm.hpsys", Version: "v1alpha1"}
 )
 
-// ProvisionedBucket holds information about the bucket to be provisioned.
-// In the future releases this structure may be extented to include other data store types.
-type ProvisionedBucket struct {
-	// Bucket name
+const (
+	// DatasetKind is the kind of the resources provisioning S3 buckets
+	DatasetKind = "Dataset"
+	// OwnerLabel references the owner of a Dataset resource as <namespace>.<name>
+	OwnerLabel = "m4d.ibm.com/owner"
+)
+
+// ProvisionedStorage holds information about the storage to be provisioned, e.g. an S3 bucket
+type ProvisionedStorage struct {
+	// Name of the storage, e.g. the bucket name
 	Name string
+	// Type of the storage account, which selects the provisioner. The default type is S3.
+	Type string
 	// Endpoint
 	Endpoint string
 	// Secret containing credentials
 	SecretRef types.NamespacedName
+	// Details are the provider-specific connection details taken from the storage account, e.g. a container or a project
+	Details map[string]string
 }
 
 // ProvisionedStorageStatus includes the status of the provisioning and an error message if the provisioning has failed
@@ -49,10 +68,15 @@ type ProvisionedStorageStatus struct {
 
 // ProvisionInterface is an interface for managing dynamically allocated Dataset resources
 type ProvisionInterface interface {
-	CreateDataset(ref *types.NamespacedName, dataset *ProvisionedBucket, owner *types.NamespacedName) error
+	CreateDataset(ref *types.NamespacedName, dataset *ProvisionedStorage, owner *types.NamespacedName) error
 	DeleteDataset(ref *types.NamespacedName) error
 	GetDatasetStatus(ref *types.NamespacedName) (*ProvisionedStorageStatus, error)
+	// ListDatasetStatuses returns the status of all the storage provisioned for the owner, by the names of the storage
+	ListDatasetStatuses(owner *types.NamespacedName) (map[string]*ProvisionedStorageStatus, error)
 	SetPersistent(ref *types.NamespacedName, persistent bool) error
+	// Detach removes the owner of the provisioned storage and makes it persistent, so that it is managed independently.
+	// The credentials of the storage are then read from the given secret.
+	Detach(ref *types.NamespacedName, secretRef types.NamespacedName) error
 }
 
 // ProvisionImpl is an implementation of ProvisionInterface using Dataset CRDs
@@ -67,19 +91,35 @@ func NewProvisionImpl(c client.Client) *ProvisionImpl {
 	}
 }
 
-func newDatasetAsUnstructured(name string, namespace string) *unstructured.Unstructured {
+// NewDataset returns an empty Dataset resource, e.g. for watching Dataset resources
+func NewDataset() *unstructured.Unstructured {
 	object := &unstructured.Unstructured{}
-	object.SetGroupVersionKind(schema.GroupVersionKind{Group: GroupVersion.Group, Version: GroupVersion.Version, Kind: "Dataset"})
+	object.SetGroupVersionKind(GroupVersion.WithKind(DatasetKind))
+	return object
+}
+
+// OwnerOf returns the owner of a Dataset resource, as set when creating the resource
+func OwnerOf(dataset client.Object) (types.NamespacedName, bool) {
+	owner := strings.SplitN(dataset.GetLabels()[OwnerLabel], ".", 2)
+	if len(owner) != 2 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: owner[0], Name: owner[1]}, true
+}
+
+func ownerLabelValue(owner *types.NamespacedName) string {
+	return owner.Namespace + "." + owner.Name
+}
+
+func newDatasetAsUnstructured(name string, namespace string) *unstructured.Unstructured {
+	object := NewDataset()
 	object.SetNamespace(namespace)
 	object.SetName(name)
 	return object
 }
 
 func (r *ProvisionImpl) getDatasetAsUnstructured(name string, namespace string) (*unstructured.Unstructured, error) {
-	object := &unstructured.Unstructured{}
-	object.SetGroupVersionKind(schema.GroupVersionKind{Group: GroupVersion.Group, Version: GroupVersion.Version, Kind: "Dataset"})
-	object.SetNamespace(namespace)
-	object.SetName(name)
+	object := newDatasetAsUnstructured(name, namespace)
 
 	objectKey := client.ObjectKeyFromObject(object)
 
@@ -96,7 +136,7 @@ func getValue(obj map[string]interface{}, path ...string) string {
 	return ""
 }
 
-func equal(required *ProvisionedBucket, existing *unstructured.Unstructured) bool {
+func equal(required *ProvisionedStorage, existing *unstructured.Unstructured) bool {
 	obj := existing.UnstructuredContent()
 	if required.Name != getValue(obj, "spec", "local", "bucket") {
 		return false
@@ -114,7 +154,7 @@ func equal(required *ProvisionedBucket, existing *unstructured.Unstructured) boo
 }
 
 // CreateDataset generates a Dataset resource
-func (r *ProvisionImpl) CreateDataset(ref *types.NamespacedName, bucket *ProvisionedBucket, owner *types.NamespacedName) error {
+func (r *ProvisionImpl) CreateDataset(ref *types.NamespacedName, bucket *ProvisionedStorage, owner *types.NamespacedName) error {
 	existing, err := r.getDatasetAsUnstructured(ref.Name, ref.Namespace)
 	if err == nil {
 		if equal(bucket, existing) {
@@ -136,8 +176,8 @@ func (r *ProvisionImpl) CreateDataset(ref *types.NamespacedName, bucket *Provisi
 
 	dataset := newDatasetAsUnstructured(ref.Name, ref.Namespace)
 	dataset.SetLabels(map[string]string{
-		"m4d.ibm.com/owner": owner.Namespace + "." + owner.Name,
-		"remove-on-delete":  "true"})
+		OwnerLabel:         ownerLabelValue(owner),
+		"remove-on-delete": "true"})
 
 	if err = unstructured.SetNestedStringMap(dataset.Object, values, "spec", "local"); err != nil {
 		return err
@@ -167,15 +207,57 @@ func (r *ProvisionImpl) SetPersistent(ref *types.NamespacedName, persistent bool
 	return r.Client.Update(context.Background(), existing)
 }
 
+// Detach removes the owner label of the existing Dataset resource, marks it as persistent,
+// and references the given secret holding the credentials of the bucket
+func (r *ProvisionImpl) Detach(ref *types.NamespacedName, secretRef types.NamespacedName) error {
+	existing, err := r.getDatasetAsUnstructured(ref.Name, ref.Namespace)
+	if err != nil {
+		return err
+	}
+	labels := existing.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	delete(labels, OwnerLabel)
+	labels["remove-on-delete"] = "false"
+	existing.SetLabels(labels)
+	if err := unstructured.SetNestedField(existing.Object, secretRef.Name, "spec", "local", "secret-name"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(existing.Object, secretRef.Namespace, "spec", "local", "secret-namespace"); err != nil {
+		return err
+	}
+	return r.Client.Update(context.Background(), existing)
+}
+
 // GetDatasetStatus returns status of an existing Dataset resource.
 func (r *ProvisionImpl) GetDatasetStatus(ref *types.NamespacedName) (*ProvisionedStorageStatus, error) {
 	dataset, err := r.getDatasetAsUnstructured(ref.Name, ref.Namespace)
 	if err != nil {
 		return nil, err
 	}
+	return datasetStatus(dataset), nil
+}
+
+// ListDatasetStatuses returns the status of the Dataset resources created for the owner, by their names.
+// The resources are listed once by the owner label, instead of being read one by one.
+func (r *ProvisionImpl) ListDatasetStatuses(owner *types.NamespacedName) (map[string]*ProvisionedStorageStatus, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GroupVersion.WithKind(DatasetKind + "List"))
+	if err := r.Client.List(context.Background(), list, client.MatchingLabels{OwnerLabel: ownerLabelValue(owner)}); err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]*ProvisionedStorageStatus, len(list.Items))
+	for i := range list.Items {
+		statuses[list.Items[i].GetName()] = datasetStatus(&list.Items[i])
+	}
+	return statuses, nil
+}
+
+func datasetStatus(dataset *unstructured.Unstructured) *ProvisionedStorageStatus {
 	status := getValue(dataset.Object, "status", "provision", "status")
 	info := getValue(dataset.Object, "status", "provision", "info")
-	return &ProvisionedStorageStatus{Provisioned: status == "OK", ErrorMsg: info}, nil
+	return &ProvisionedStorageStatus{Provisioned: status == "OK", ErrorMsg: info}
 }
 
 // DeleteDataset deletes the existing Dataset resource
@@ -189,18 +271,26 @@ func (r *ProvisionImpl) DeleteDataset(ref *types.NamespacedName) error {
 
 // ProvisionTest is an implementation of ProvisionInterface used for testing
 type ProvisionTest struct {
-	datasets []*ProvisionedBucket
+	mutex    sync.Mutex
+	datasets []*ProvisionedStorage
+	owners   map[string]types.NamespacedName
 }
 
 // NewProvisionTest constructs a new ProvisionTest object
 func NewProvisionTest() *ProvisionTest {
 	return &ProvisionTest{
-		datasets: []*ProvisionedBucket{},
+		datasets: []*ProvisionedStorage{},
 	}
 }
 
 // CreateDataset generates a new dataset
-func (r *ProvisionTest) CreateDataset(ref *types.NamespacedName, dataset *ProvisionedBucket, owner *types.NamespacedName) error {
+func (r *ProvisionTest) CreateDataset(ref *types.NamespacedName, dataset *ProvisionedStorage, owner *types.NamespacedName) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.owners == nil {
+		r.owners = make(map[string]types.NamespacedName)
+	}
+	r.owners[dataset.Name] = *owner
 	for i, d := range r.datasets {
 		if d.Name == dataset.Name {
 			r.datasets[i] = dataset
@@ -213,6 +303,8 @@ func (r *ProvisionTest) CreateDataset(ref *types.NamespacedName, dataset *Provis
 
 // SetPersistent does nothing for the testing implementation except for verifying that the dataset exists
 func (r *ProvisionTest) SetPersistent(ref *types.NamespacedName, persistent bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	for _, d := range r.datasets {
 		if d.Name == ref.Name {
 			return nil
@@ -221,23 +313,52 @@ func (r *ProvisionTest) SetPersistent(ref *types.NamespacedName, persistent bool
 	return fmt.Errorf("could not find a dataset: %s", ref.Name)
 }
 
+// Detach removes the owner of an existing dataset and sets the secret holding its credentials
+func (r *ProvisionTest) Detach(ref *types.NamespacedName, secretRef types.NamespacedName) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, d := range r.datasets {
+		if d.Name == ref.Name {
+			d.SecretRef = secretRef
+			delete(r.owners, ref.Name)
+			return nil
+		}
+	}
+	return fmt.Errorf("could not find a dataset: %s", ref.Name)
+}
+
 // GetDatasetStatus returns status of an existing Dataset resource.
 func (r *ProvisionTest) GetDatasetStatus(ref *types.NamespacedName) (*ProvisionedStorageStatus, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	for _, d := range r.datasets {
 		if d.Name == ref.Name {
 			return &ProvisionedStorageStatus{Provisioned: true}, nil
 		}
 	}
-	return nil, fmt.Errorf("could not find a dataset: %s", ref.Name)
+	return nil, apierrors.NewNotFound(GroupVersion.WithResource("datasets").GroupResource(), ref.Name)
+}
+
+// ListDatasetStatuses returns the status of the datasets created for the owner
+func (r *ProvisionTest) ListDatasetStatuses(owner *types.NamespacedName) (map[string]*ProvisionedStorageStatus, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	statuses := make(map[string]*ProvisionedStorageStatus)
+	for _, d := range r.datasets {
+		if r.owners[d.Name] == *owner {
+			statuses[d.Name] = &ProvisionedStorageStatus{Provisioned: true}
+		}
+	}
+	return statuses, nil
 }
 
 // DeleteDataset removes an existing dataset
 func (r *ProvisionTest) DeleteDataset(ref *types.NamespacedName) error {
-	newDatasets := []*ProvisionedBucket{}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	newDatasets := []*ProvisionedStorage{}
 	found := false
-	errMessage := "The following datasets have been found:\n"
 	for _, d := range r.datasets {
-		errMessage += " " + d.Name + "\n"
 		if d.Name == ref.Name {
 			found = true
 		} else {
@@ -246,7 +367,9 @@ func (r *ProvisionTest) DeleteDataset(ref *types.NamespacedName) error {
 	}
 	if found {
 		r.datasets = newDatasets
+		delete(r.owners, ref.Name)
 		return nil
 	}
-	return fmt.Errorf("could not delete a dataset %s\n%s", ref.Name, errMessage)
+	// a missing dataset is reported as such, like by the API server
+	return apierrors.NewNotFound(GroupVersion.WithResource("datasets").GroupResource(), ref.Name)
 }
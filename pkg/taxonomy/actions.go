@@ -0,0 +1,72 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"context"
+	"encoding/json"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// DefaultModuleValuesFile is the location of the module values taxonomy inside the manager container
+const DefaultModuleValuesFile = "/tmp/taxonomy/module.values.schema.json"
+
+// Actions maps the identifiers of the actions defined in the module values taxonomy to the levels they apply to.
+// A nil or empty map does not restrict the actions.
+type Actions map[string][]pb.EnforcementAction_EnforcementActionLevel
+
+type moduleValues struct {
+	Definitions struct {
+		ActionIDColumn struct {
+			Enum []string `json:"enum"`
+		} `json:"action_id_column"`
+		ActionIDDataset struct {
+			Enum []string `json:"enum"`
+		} `json:"action_id_dataset"`
+	} `json:"definitions"`
+}
+
+// LoadActionsFrom returns the action identifiers defined in the module values taxonomy fetched from the given source,
+// which may be a file, an HTTPS URL or an OCI artifact. See FetchLayer for the checksum format.
+func LoadActionsFrom(ctx context.Context, source string, checksum string) (Actions, error) {
+	content, err := FetchLayer(ctx, source, checksum)
+	if err != nil {
+		return nil, err
+	}
+	values := moduleValues{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, err
+	}
+	actions := Actions{}
+	for _, id := range values.Definitions.ActionIDColumn.Enum {
+		actions[id] = append(actions[id], pb.EnforcementAction_COLUMN)
+	}
+	for _, id := range values.Definitions.ActionIDDataset.Enum {
+		actions[id] = append(actions[id], pb.EnforcementAction_DATASET)
+	}
+	return actions, nil
+}
+
+// Known returns true if the taxonomy defines the action identifier
+func (a Actions) Known(id string) bool {
+	if len(a) == 0 {
+		return true
+	}
+	_, found := a[id]
+	return found
+}
+
+// SupportsLevel returns true if the taxonomy defines the action identifier for the given level
+func (a Actions) SupportsLevel(id string, level pb.EnforcementAction_EnforcementActionLevel) bool {
+	if len(a) == 0 {
+		return true
+	}
+	for _, l := range a[id] {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
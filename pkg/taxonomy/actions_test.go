@@ -0,0 +1,27 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/stretchr/testify/assert"
+)
+
+var ModuleValuesName = "../../charts/m4d/files/taxonomy/module.values.schema.json"
+
+func TestLoadActions(t *testing.T) {
+	actions, err := LoadActionsFrom(context.Background(), ModuleValuesName, "")
+	assert.Nil(t, err)
+	assert.True(t, actions.Known("redact-ID"))
+	assert.True(t, actions.SupportsLevel("redact-ID", pb.EnforcementAction_COLUMN))
+	assert.False(t, actions.SupportsLevel("redact-ID", pb.EnforcementAction_DATASET))
+	assert.False(t, actions.Known("unknown-ID"))
+
+	// no restrictions without a taxonomy
+	assert.True(t, Actions(nil).Known("unknown-ID"))
+	assert.True(t, Actions(nil).SupportsLevel("unknown-ID", pb.EnforcementAction_ROW))
+}
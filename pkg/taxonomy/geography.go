@@ -0,0 +1,40 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// DefaultCatalogValuesFile is the location of the catalog values taxonomy inside the manager container
+const DefaultCatalogValuesFile = "/tmp/taxonomy/catalog.values.schema.json"
+
+type catalogValues struct {
+	Definitions struct {
+		GeographyName struct {
+			Enum []string `json:"enum"`
+		} `json:"geography_name"`
+	} `json:"definitions"`
+}
+
+// LoadGeographies returns the geography names defined in the catalog values taxonomy file.
+// An empty list is returned if the taxonomy does not restrict the geography names.
+func LoadGeographies(taxonomyFile string) ([]string, error) {
+	return LoadGeographiesFrom(context.Background(), taxonomyFile, "")
+}
+
+// LoadGeographiesFrom returns the geography names defined in the catalog values taxonomy fetched from the given source,
+// which may be a file, an HTTPS URL or an OCI artifact. See FetchLayer for the checksum format.
+func LoadGeographiesFrom(ctx context.Context, source string, checksum string) ([]string, error) {
+	content, err := FetchLayer(ctx, source, checksum)
+	if err != nil {
+		return nil, err
+	}
+	values := catalogValues{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, err
+	}
+	return values.Definitions.GeographyName.Enum, nil
+}
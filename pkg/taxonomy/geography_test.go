@@ -0,0 +1,22 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var CatalogValuesName = "../../charts/m4d/files/taxonomy/catalog.values.schema.json"
+
+func TestLoadGeographies(t *testing.T) {
+	geographies, err := LoadGeographies(CatalogValuesName)
+	assert.Nil(t, err)
+	assert.Contains(t, geographies, "Netherlands")
+	assert.Contains(t, geographies, "Turkey")
+
+	_, err = LoadGeographies("nonexistent.json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+)
+
+// DefaultPrivacyLevelsFile is the location of the privacy levels taxonomy inside the manager container
+const DefaultPrivacyLevelsFile = "/tmp/taxonomy/privacy.levels.json"
+
+// PrivacyAction is an action applied to achieve a privacy level
+type PrivacyAction struct {
+	// ID is the identifier of the action, as defined by the module values taxonomy
+	ID string `json:"id"`
+	// Name is the name of the action
+	Name string `json:"name"`
+	// Level is COLUMN or DATASET
+	Level string `json:"level"`
+	// Tags restrict a column action to the columns carrying one of the tags. The action applies to all the columns if empty.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// PrivacyLevels maps the target privacy levels that applications may request to the actions achieving them
+type PrivacyLevels map[string][]PrivacyAction
+
+type privacyValues struct {
+	Levels PrivacyLevels `json:"levels"`
+}
+
+// LoadPrivacyLevelsFrom returns the privacy levels defined in the taxonomy fetched from the given source,
+// which may be a file, an HTTPS URL or an OCI artifact. See FetchLayer for the checksum format.
+func LoadPrivacyLevelsFrom(ctx context.Context, source string, checksum string) (PrivacyLevels, error) {
+	content, err := FetchLayer(ctx, source, checksum)
+	if err != nil {
+		return nil, err
+	}
+	values := privacyValues{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, err
+	}
+	for level, actions := range values.Levels {
+		for _, action := range actions {
+			if _, found := pb.EnforcementAction_EnforcementActionLevel_value[action.Level]; !found || action.ID == "" {
+				return nil, fmt.Errorf("invalid action %q of privacy level %s", action.ID, level)
+			}
+		}
+	}
+	return values.Levels, nil
+}
+
+// Actions returns the enforcement actions achieving the privacy level for a dataset with the given metadata.
+// Column actions are returned for each matching column, in the order of the column names.
+// An error is returned if the taxonomy does not define the privacy level.
+func (p PrivacyLevels) Actions(privacyLevel string, metadata *pb.DatasetMetadata) ([]*pb.EnforcementAction, error) {
+	privacyActions, found := p[privacyLevel]
+	if !found {
+		return nil, fmt.Errorf("privacy level %s is not defined by the taxonomy", privacyLevel)
+	}
+	columns := sortedColumns(metadata)
+	actions := []*pb.EnforcementAction{}
+	for _, privacyAction := range privacyActions {
+		level := pb.EnforcementAction_EnforcementActionLevel(pb.EnforcementAction_EnforcementActionLevel_value[privacyAction.Level])
+		if level != pb.EnforcementAction_COLUMN {
+			actions = append(actions, &pb.EnforcementAction{Name: privacyAction.Name, Id: privacyAction.ID, Level: level, Args: map[string]string{}})
+			continue
+		}
+		for _, column := range columns {
+			if !hasAnyTag(metadata.ComponentsMetadata[column].GetTags(), privacyAction.Tags) {
+				continue
+			}
+			actions = append(actions, &pb.EnforcementAction{Name: privacyAction.Name, Id: privacyAction.ID, Level: level,
+				Args: map[string]string{"column_name": column}})
+		}
+	}
+	return actions, nil
+}
+
+func sortedColumns(metadata *pb.DatasetMetadata) []string {
+	columns := make([]string, 0, len(metadata.GetComponentsMetadata()))
+	for column := range metadata.GetComponentsMetadata() {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// hasAnyTag returns true if one of the tags is wanted, or if no tags are wanted
+func hasAnyTag(tags []string, wanted []string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if tag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
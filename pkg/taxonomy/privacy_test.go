@@ -0,0 +1,45 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
+	"github.com/stretchr/testify/assert"
+)
+
+var PrivacyLevelsName = "../../charts/m4d/files/taxonomy/privacy.levels.json"
+
+func TestPrivacyLevelActions(t *testing.T) {
+	levels, err := LoadPrivacyLevelsFrom(context.Background(), PrivacyLevelsName, "")
+	assert.Nil(t, err)
+	metadata := &pb.DatasetMetadata{ComponentsMetadata: map[string]*pb.DataComponentMetadata{
+		"nameOrig": {ComponentType: "column", Tags: []string{"PII"}},
+		"nameDest": {ComponentType: "column", Tags: []string{"PII"}},
+		"amount":   {ComponentType: "column"},
+		"religion": {ComponentType: "column", Tags: []string{"SPI"}},
+	}}
+
+	actions, err := levels.Actions("anonymized", metadata)
+	assert.Nil(t, err)
+	assert.Len(t, actions, 3)
+	assert.Equal(t, "redact-ID", actions[0].Id)
+	assert.Equal(t, pb.EnforcementAction_COLUMN, actions[0].Level)
+	assert.Equal(t, map[string]string{"column_name": "nameDest"}, actions[0].Args)
+	assert.Equal(t, map[string]string{"column_name": "nameOrig"}, actions[1].Args)
+	assert.Equal(t, "removed-ID", actions[2].Id)
+	assert.Equal(t, map[string]string{"column_name": "religion"}, actions[2].Args)
+
+	// no actions are required for datasets without sensitive columns
+	actions, err = levels.Actions("pseudonymized", &pb.DatasetMetadata{})
+	assert.Nil(t, err)
+	assert.Empty(t, actions)
+
+	_, err = levels.Actions("unknown", metadata)
+	assert.NotNil(t, err)
+	_, err = PrivacyLevels(nil).Actions("anonymized", metadata)
+	assert.NotNil(t, err)
+}
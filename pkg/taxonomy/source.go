@@ -0,0 +1,200 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+const (
+	httpsScheme = "https://"
+	ociScheme   = "oci://"
+
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// httpClient is used to fetch remote taxonomy layers
+var httpClient = &http.Client{Timeout: time.Minute}
+
+// FetchLayer reads a taxonomy layer from a local file, an HTTPS URL or an OCI artifact (oci://registry/repository:tag).
+// OCI artifacts must contain a single layer, which is the taxonomy, and are pulled anonymously.
+// If a checksum is given as <algorithm>:<hex digest>, where the algorithm is sha256 or sha512, the content must match it. This pins the layer to a specific
+// published version, so that a layer modified at its source is rejected.
+func FetchLayer(ctx context.Context, source string, checksum string) ([]byte, error) {
+	var content []byte
+	var err error
+	switch {
+	case strings.HasPrefix(source, httpsScheme):
+		content, err = fetchURL(ctx, source, "", "")
+	case strings.HasPrefix(source, ociScheme):
+		content, err = fetchOCI(ctx, strings.TrimPrefix(source, ociScheme))
+	case strings.Contains(source, "://"):
+		return nil, errors.New("unsupported taxonomy source " + source + ": only files, HTTPS URLs and OCI artifacts are supported")
+	default:
+		content, err = ioutil.ReadFile(source)
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed fetching taxonomy layer "+source)
+	}
+	if checksum != "" {
+		if err := verifyChecksum(content, checksum); err != nil {
+			return nil, errors.WithMessage(err, "taxonomy layer "+source)
+		}
+	}
+	return content, nil
+}
+
+// verifyChecksum checks that the content matches a checksum given as <algorithm>:<hex digest>, as the digests of OCI layers.
+// A digest without algorithm is a sha256 digest.
+func verifyChecksum(content []byte, checksum string) error {
+	algorithm, expected := "sha256", strings.ToLower(checksum)
+	if colon := strings.Index(expected, ":"); colon >= 0 {
+		algorithm, expected = expected[:colon], expected[colon+1:]
+	}
+	var actual string
+	switch algorithm {
+	case "sha256":
+		sum := sha256.Sum256(content)
+		actual = hex.EncodeToString(sum[:])
+	case "sha512":
+		sum := sha512.Sum512(content)
+		actual = hex.EncodeToString(sum[:])
+	default:
+		return fmt.Errorf("unsupported checksum algorithm %s: only sha256 and sha512 are supported", algorithm)
+	}
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch: expected %s:%s, got %s:%s", algorithm, expected, algorithm, actual)
+	}
+	return nil
+}
+
+// fetchURL returns the body of a successful GET request
+func fetchURL(ctx context.Context, location string, accept string, token string) ([]byte, error) {
+	response, err := get(ctx, location, accept, token)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", location, response.Status)
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+func get(ctx context.Context, location string, accept string, token string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	return httpClient.Do(request)
+}
+
+// fetchOCI pulls the single layer of an OCI artifact referenced as registry/repository:tag or registry/repository@digest
+func fetchOCI(ctx context.Context, ref string) ([]byte, error) {
+	slash := strings.Index(ref, "/")
+	if slash < 0 {
+		return nil, errors.New("invalid OCI reference " + ref)
+	}
+	registry, repository := ref[:slash], ref[slash+1:]
+	reference := "latest"
+	if at := strings.LastIndex(repository, "@"); at >= 0 {
+		repository, reference = repository[:at], repository[at+1:]
+	} else if colon := strings.LastIndex(repository, ":"); colon >= 0 {
+		repository, reference = repository[:colon], repository[colon+1:]
+	}
+	base := httpsScheme + registry + "/v2/" + repository
+
+	token, err := anonymousToken(ctx, base+"/manifests/"+reference)
+	if err != nil {
+		return nil, err
+	}
+	content, err := fetchURL(ctx, base+"/manifests/"+reference, ociManifestMediaType, token)
+	if err != nil {
+		return nil, err
+	}
+	manifest := struct {
+		Layers []struct {
+			Digest string `json:"digest"`
+		} `json:"layers"`
+	}{}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	if len(manifest.Layers) != 1 {
+		return nil, fmt.Errorf("OCI artifact %s must contain a single layer, found %d", ref, len(manifest.Layers))
+	}
+	digest := manifest.Layers[0].Digest
+	layer, err := fetchURL(ctx, base+"/blobs/"+digest, "", token)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyChecksum(layer, digest); err != nil {
+		return nil, errors.WithMessage(err, "layer of OCI artifact "+ref)
+	}
+	return layer, nil
+}
+
+// anonymousToken returns a token for pulling from registries that require one for anonymous access,
+// following the challenge returned for an unauthenticated request. An empty token is returned if no challenge is made.
+func anonymousToken(ctx context.Context, location string) (string, error) {
+	response, err := get(ctx, location, ociManifestMediaType, "")
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+	challenge := response.Header.Get("WWW-Authenticate")
+	if response.StatusCode != http.StatusUnauthorized || !strings.HasPrefix(challenge, "Bearer ") {
+		return "", nil
+	}
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		if kv := strings.SplitN(strings.TrimSpace(param), "=", 2); len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.String() == "" {
+		return "", errors.New("invalid authentication challenge: " + challenge)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, found := params[key]; found {
+			query.Set(key, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+	content, err := fetchURL(ctx, realm.String(), "", "")
+	if err != nil {
+		return "", err
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.Unmarshal(content, &token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
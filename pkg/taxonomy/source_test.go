@@ -0,0 +1,76 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// newTaxonomyServer serves the catalog values taxonomy as a plain file and as the single layer of an OCI artifact
+func newTaxonomyServer(t *testing.T) (*httptest.Server, []byte) {
+	content, err := ioutil.ReadFile(CatalogValuesName)
+	assert.Nil(t, err)
+	layerDigest := digest(content)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/catalog.values.schema.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	})
+	mux.HandleFunc("/v2/taxonomies/catalog/manifests/v1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ociManifestMediaType)
+		fmt.Fprintf(w, `{"schemaVersion": 2, "layers": [{"digest": "%s", "size": %d}]}`, layerDigest, len(content))
+	})
+	mux.HandleFunc("/v2/taxonomies/catalog/blobs/"+layerDigest, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	})
+	return httptest.NewTLSServer(mux), content
+}
+
+func TestFetchLayer(t *testing.T) {
+	server, content := newTaxonomyServer(t)
+	defer server.Close()
+	defaultClient := httpClient
+	httpClient = server.Client()
+	defer func() { httpClient = defaultClient }()
+	ctx := context.Background()
+
+	fetched, err := FetchLayer(ctx, server.URL+"/catalog.values.schema.json", digest(content))
+	assert.Nil(t, err)
+	assert.Equal(t, content, fetched)
+
+	_, err = FetchLayer(ctx, server.URL+"/catalog.values.schema.json", digest([]byte("another version")))
+	assert.NotNil(t, err)
+
+	geographies, err := LoadGeographiesFrom(ctx, "oci://"+strings.TrimPrefix(server.URL, "https://")+"/taxonomies/catalog:v1", "")
+	assert.Nil(t, err)
+	assert.Contains(t, geographies, "Netherlands")
+
+	_, err = FetchLayer(ctx, "ftp://example.com/catalog.values.schema.json", "")
+	assert.NotNil(t, err)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	content := []byte(`{"definitions": {}}`)
+	assert.Nil(t, verifyChecksum(content, digest(content)))
+	sum := sha512.Sum512(content)
+	assert.Nil(t, verifyChecksum(content, "sha512:"+hex.EncodeToString(sum[:])))
+	assert.Nil(t, verifyChecksum(content, strings.TrimPrefix(digest(content), "sha256:")))
+	assert.NotNil(t, verifyChecksum(content, "sha512:"+strings.TrimPrefix(digest(content), "sha256:")))
+	assert.NotNil(t, verifyChecksum(content, "md5:"+hex.EncodeToString(sum[:16])))
+}
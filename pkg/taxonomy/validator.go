@@ -0,0 +1,141 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// DefaultApplicationValuesFile is the location of the application values taxonomy inside the manager container
+const DefaultApplicationValuesFile = "/tmp/taxonomy/application.values.schema.json"
+
+// DefaultStorageValuesFile is the location of the storage values taxonomy inside the manager container
+const DefaultStorageValuesFile = "/tmp/taxonomy/storage.values.schema.json"
+
+// Validator validates documents against a definition of a taxonomy, compiled once together with the taxonomy files it references.
+// A Validator is safe for concurrent use.
+type Validator struct {
+	schema *gojsonschema.Schema
+}
+
+// NewValidator compiles the given definition of a taxonomy file, e.g. app_info.
+// The whole taxonomy is used if the definition is empty.
+func NewValidator(taxonomyFile string, definition string) (*Validator, error) {
+	path, err := filepath.Abs(taxonomyFile)
+	if err != nil {
+		return nil, err
+	}
+	reference := "file://" + filepath.ToSlash(path)
+	if definition != "" {
+		reference += "#/definitions/" + definition
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(`{"$ref": "` + reference + `"}`))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed compiling taxonomy "+taxonomyFile)
+	}
+	return &Validator{schema: schema}, nil
+}
+
+// NewSchemaValidator compiles a JSON schema given as a Go value, e.g. a schema declared in a resource
+func NewSchemaValidator(schema interface{}) (*Validator, error) {
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed compiling schema")
+	}
+	return &Validator{schema: compiled}, nil
+}
+
+// Validate returns an error listing the violations of the taxonomy by the document, given as a Go value (e.g. a map)
+func (v *Validator) Validate(document interface{}) error {
+	return v.validate(gojsonschema.NewGoLoader(document))
+}
+
+// ValidateJSON returns an error listing the violations of the taxonomy by the JSON document
+func (v *Validator) ValidateJSON(document string) error {
+	return v.validate(gojsonschema.NewStringLoader(document))
+}
+
+func (v *Validator) validate(loader gojsonschema.JSONLoader) error {
+	result, err := v.schema.Validate(loader)
+	if err != nil {
+		return err
+	}
+	if result.Valid() {
+		return nil
+	}
+	violations := make([]string, 0, len(result.Errors()))
+	for _, violation := range result.Errors() {
+		violations = append(violations, violation.String())
+	}
+	return errors.New(strings.Join(violations, "; "))
+}
+
+type validatorKey struct {
+	taxonomyFile string
+	definition   string
+}
+
+var (
+	validatorsMutex sync.Mutex
+	validators      = map[validatorKey]*Validator{}
+)
+
+// CachedValidator returns the validator of the given definition of a taxonomy file, compiling it upon the first request.
+// Taxonomy files are assumed not to change while the process runs.
+func CachedValidator(taxonomyFile string, definition string) (*Validator, error) {
+	key := validatorKey{taxonomyFile: taxonomyFile, definition: definition}
+	validatorsMutex.Lock()
+	defer validatorsMutex.Unlock()
+	if validator, found := validators[key]; found {
+		return validator, nil
+	}
+	validator, err := NewValidator(taxonomyFile, definition)
+	if err != nil {
+		return nil, err
+	}
+	validators[key] = validator
+	return validator, nil
+}
+
+// compiledSchema is a schema compiled by CachedSchemaValidator, or the reason why it could not be compiled
+type compiledSchema struct {
+	validator *Validator
+	err       error
+}
+
+// maxCachedSchemas bounds the number of schemas compiled by CachedSchemaValidator, such that the schemas of the
+// modules that have been changed or deleted are eventually released
+const maxCachedSchemas = 1024
+
+var (
+	schemaValidatorsMutex sync.Mutex
+	schemaValidators      = map[string]compiledSchema{}
+)
+
+// CachedSchemaValidator returns the validator of a JSON schema given as a Go value, compiling it upon the first request.
+// The validators are shared by the reconciles, which validate against the same schemas declared in the resources.
+// Invalid schemas are not compiled again either.
+func CachedSchemaValidator(schema interface{}) (*Validator, error) {
+	key, err := json.Marshal(schema)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed compiling schema")
+	}
+	schemaValidatorsMutex.Lock()
+	defer schemaValidatorsMutex.Unlock()
+	if compiled, found := schemaValidators[string(key)]; found {
+		return compiled.validator, compiled.err
+	}
+	validator, err := NewSchemaValidator(schema)
+	if len(schemaValidators) >= maxCachedSchemas {
+		schemaValidators = map[string]compiledSchema{}
+	}
+	schemaValidators[string(key)] = compiledSchema{validator: validator, err: err}
+	return validator, err
+}
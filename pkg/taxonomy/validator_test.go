@@ -0,0 +1,100 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var ApplicationValuesName = "../../charts/m4d/files/taxonomy/application.values.schema.json"
+
+func TestCachedValidator(t *testing.T) {
+	validator, err := CachedValidator(ApplicationValuesName, "app_info")
+	assert.Nil(t, err)
+	cached, err := CachedValidator(ApplicationValuesName, "app_info")
+	assert.Nil(t, err)
+	assert.Same(t, validator, cached)
+
+	// the validator is shared by concurrent admission requests
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, validator.Validate(map[string]string{"intent": "Fraud Detection", "role": "Data Scientist"}))
+			assert.NotNil(t, validator.Validate(map[string]string{"intent": "Fraud Detection", "role": "Wizard"}))
+		}()
+	}
+	wg.Wait()
+
+	_, err = CachedValidator("nonexistent.json", "app_info")
+	assert.NotNil(t, err)
+}
+
+const appInfo = `{"intent": "Fraud Detection", "role": "Data Scientist"}`
+
+// BenchmarkValidateCompiled validates with a validator compiled once, as done by the webhooks
+func BenchmarkValidateCompiled(b *testing.B) {
+	validator, err := CachedValidator(ApplicationValuesName, "app_info")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := validator.ValidateJSON(appInfo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkValidateUncompiled reads and compiles the taxonomy files on every validation
+func BenchmarkValidateUncompiled(b *testing.B) {
+	path, err := filepath.Abs(ApplicationValuesName)
+	if err != nil {
+		b.Fatal(err)
+	}
+	schema := gojsonschema.NewStringLoader(`{"$ref": "file://` + filepath.ToSlash(path) + `#/definitions/app_info"}`)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := gojsonschema.Validate(schema, gojsonschema.NewStringLoader(appInfo))
+		if err != nil || !result.Valid() {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCachedSchemaValidator(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"algo": map[string]interface{}{"enum": []interface{}{"md5", "sha256"}}},
+	}
+	validator, err := CachedSchemaValidator(schema)
+	assert.Nil(t, err)
+	// an equal schema, e.g. declared by another module, shares the validator
+	cached, err := CachedSchemaValidator(map[string]interface{}{
+		"properties": map[string]interface{}{"algo": map[string]interface{}{"enum": []interface{}{"md5", "sha256"}}},
+		"type":       "object",
+	})
+	assert.Nil(t, err)
+	assert.Same(t, validator, cached)
+	assert.Nil(t, validator.Validate(map[string]string{"algo": "md5"}))
+	assert.NotNil(t, validator.Validate(map[string]string{"algo": "crc32"}))
+
+	_, err = CachedSchemaValidator(map[string]interface{}{"type": 42})
+	assert.NotNil(t, err)
+	_, err = CachedSchemaValidator(map[string]interface{}{"type": 42})
+	assert.NotNil(t, err)
+
+	// the cache is bounded
+	for i := 0; i <= maxCachedSchemas; i++ {
+		_, err = CachedSchemaValidator(map[string]interface{}{"type": "string", "maxLength": i})
+		assert.Nil(t, err)
+	}
+	assert.LessOrEqual(t, len(schemaValidators), maxCachedSchemas)
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Backend is the system from which the modules retrieve the credentials of the data stores
+type Backend string
+
+const (
+	// VaultBackend retrieves the credentials from Vault, which reads the Kubernetes secrets through the
+	// vault-plugin-secrets-kubernetes-reader plugin
+	VaultBackend Backend = "vault"
+	// KubernetesBackend retrieves the credentials directly from the Kubernetes secrets, when Vault is not deployed
+	KubernetesBackend Backend = "kubernetes"
+)
+
+// SecretBackendKey is the environment variable selecting the backend of the deployment
+const SecretBackendKey = "SECRET_BACKEND"
+
+// GetSecretBackend returns the backend from which the credentials are retrieved in this deployment, Vault by default
+func GetSecretBackend() Backend {
+	if Backend(strings.TrimSpace(os.Getenv(SecretBackendKey))) == KubernetesBackend {
+		return KubernetesBackend
+	}
+	return VaultBackend
+}
+
+// SecretReader reads the credentials held at a Vault path, as a JSON object
+type SecretReader interface {
+	GetSecret(vaultPath string) (string, error)
+}
+
+// ParseKubeSecretPath returns the Kubernetes secret read by the path of the Vault plugin for reading Kubernetes secrets,
+// as constructed by PathForReadingKubeSecret. It returns false for paths of other secrets.
+func ParseKubeSecretPath(vaultPath string) (types.NamespacedName, bool) {
+	pluginPath := "/v1/" + vaultPluginPath + "/"
+	if !strings.HasPrefix(vaultPath, pluginPath) {
+		return types.NamespacedName{}, false
+	}
+	ref, err := url.Parse(strings.TrimPrefix(vaultPath, pluginPath))
+	if err != nil || ref.Path == "" || strings.Contains(ref.Path, "/") {
+		return types.NamespacedName{}, false
+	}
+	namespace := ref.Query().Get("namespace")
+	if namespace == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Name: ref.Path, Namespace: namespace}, true
+}
+
+// KubeSecretReader resolves the paths of the Vault plugin for reading Kubernetes secrets directly
+// from the Kubernetes secrets, without Vault
+type KubeSecretReader struct {
+	Client client.Client
+}
+
+// GetSecret returns the data of the Kubernetes secret referenced by the Vault path as a JSON object,
+// as returned by the Vault plugin
+func (r *KubeSecretReader) GetSecret(vaultPath string) (string, error) {
+	ref, ok := ParseKubeSecretPath(vaultPath)
+	if !ok {
+		return "", errors.Errorf("%s does not reference a Kubernetes secret", vaultPath)
+	}
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(context.Background(), ref, secret); err != nil {
+		return "", errors.Wrapf(err, "could not read secret %s", ref.String())
+	}
+	data := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+	for key, value := range secret.StringData {
+		data[key] = value
+	}
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// NewSecretReader returns a reader of the credentials from the backend of the deployment:
+// the Kubernetes secrets read by the client, or Vault at the given address
+func NewSecretReader(c client.Client, addr string, token string) (SecretReader, error) {
+	if GetSecretBackend() == KubernetesBackend {
+		return &KubeSecretReader{Client: c}, nil
+	}
+	return InitConnection(addr, token)
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseKubeSecretPath(t *testing.T) {
+	ref, ok := ParseKubeSecretPath(PathForReadingKubeSecret("m4d-system", "creds-secret-name"))
+	assert.True(t, ok)
+	assert.Equal(t, types.NamespacedName{Name: "creds-secret-name", Namespace: "m4d-system"}, ref)
+
+	_, ok = ParseKubeSecretPath("/v1/m4d/test/123")
+	assert.False(t, ok)
+	_, ok = ParseKubeSecretPath("/v1/kubernetes-secrets/creds-secret-name")
+	assert.False(t, ok)
+}
+
+func TestKubeSecretReader(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, corev1.AddToScheme(scheme))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds-secret-name", Namespace: "m4d-system"},
+		Data:       map[string][]byte{"access_key": []byte("ak"), "secret_key": []byte("sk")},
+	}
+	reader := &KubeSecretReader{Client: fake.NewFakeClientWithScheme(scheme, secret)}
+
+	credentials, err := reader.GetSecret(PathForReadingKubeSecret("m4d-system", "creds-secret-name"))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"access_key": "ak", "secret_key": "sk"}`, credentials)
+
+	_, err = reader.GetSecret(PathForReadingKubeSecret("default", "creds-secret-name"))
+	assert.NotNil(t, err)
+	_, err = reader.GetSecret("/v1/m4d/test/123")
+	assert.NotNil(t, err)
+}
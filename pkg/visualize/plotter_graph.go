@@ -0,0 +1,203 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package visualize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+)
+
+// Format is the language in which a graph is rendered
+type Format string
+
+const (
+	// DOT renders the graph for Graphviz
+	DOT Format = "dot"
+	// Mermaid renders the graph as a Mermaid flowchart
+	Mermaid Format = "mermaid"
+)
+
+// workloadNode is the node standing for the workload reading and writing the datasets
+const workloadNode = "workload"
+
+type node struct {
+	id    string
+	label string
+	shape string
+}
+
+type edge struct {
+	from string
+	to   string
+}
+
+// graph is the flow of the data of a plotter: the datasets, the steps of the blueprints grouped by cluster,
+// and the workload, linked in the direction of the data flow
+type graph struct {
+	name     string
+	datasets []node
+	clusters []string
+	steps    map[string][]node
+	edges    []edge
+}
+
+// stepRef is a step of a blueprint serving a dataset
+type stepRef struct {
+	id   string
+	step *app.FlowStep
+}
+
+// PlotterGraph renders the data flow of a plotter in the given format.
+// Datasets, as listed in the annotations of the steps, feed the copy steps or the first steps reading them.
+// The copy steps and the steps of transformation chains feed the steps reading their output,
+// and the read and write steps exchange the data with the workload.
+func PlotterGraph(plotter *app.Plotter, format Format) (string, error) {
+	g := buildGraph(plotter)
+	switch format {
+	case DOT:
+		return g.dot(), nil
+	case Mermaid:
+		return g.mermaid(), nil
+	}
+	return "", fmt.Errorf("unsupported format %s, expected %s or %s", format, DOT, Mermaid)
+}
+
+func buildGraph(plotter *app.Plotter) *graph {
+	g := &graph{name: plotter.Name, steps: map[string][]node{}}
+	for cluster := range plotter.Spec.Blueprints {
+		g.clusters = append(g.clusters, cluster)
+	}
+	sort.Strings(g.clusters)
+
+	// the steps serving each dataset
+	served := map[string][]stepRef{}
+	for _, cluster := range g.clusters {
+		blueprint := plotter.Spec.Blueprints[cluster]
+		for i := range blueprint.Flow.Steps {
+			step := &blueprint.Flow.Steps[i]
+			id := "step:" + cluster + "/" + step.Name
+			g.steps[cluster] = append(g.steps[cluster], node{id: id, label: step.Name + "\n(" + step.Template + ")", shape: "box"})
+			for _, datasetID := range strings.Split(step.Arguments.Annotations[app.DatasetIDsAnnotation], ",") {
+				if datasetID != "" {
+					served[datasetID] = append(served[datasetID], stepRef{id: id, step: step})
+				}
+			}
+		}
+	}
+
+	datasetIDs := make([]string, 0, len(served))
+	for datasetID := range served {
+		datasetIDs = append(datasetIDs, datasetID)
+	}
+	sort.Strings(datasetIDs)
+	for _, datasetID := range datasetIDs {
+		datasetNode := "dataset:" + datasetID
+		g.datasets = append(g.datasets, node{id: datasetNode, label: datasetID, shape: "cylinder"})
+		var copies, chained, reads, writes []stepRef
+		for _, ref := range served[datasetID] {
+			switch {
+			case ref.step.Arguments.Copy != nil:
+				copies = append(copies, ref)
+			case len(ref.step.Arguments.Write) > 0:
+				writes = append(writes, ref)
+			case ref.step.Arguments.Annotations[app.ChainedToAnnotation] != "":
+				chained = append(chained, ref)
+			case len(ref.step.Arguments.Read) > 0:
+				reads = append(reads, ref)
+			}
+		}
+		// the source of the data read by the workload is the copy if any, or the dataset otherwise
+		sources := []string{datasetNode}
+		if len(copies) > 0 {
+			sources = nil
+			for _, ref := range copies {
+				g.edges = append(g.edges, edge{from: datasetNode, to: ref.id})
+				sources = append(sources, ref.id)
+			}
+		}
+		// a step of a transformation chain feeds the step of the module it is chained to,
+		// and the first step of the chain reads the source
+		feeds := func(to stepRef) bool {
+			fed := false
+			for _, ref := range chained {
+				if ref.step.Arguments.Annotations[app.ChainedToAnnotation] == to.step.Template {
+					g.edges = append(g.edges, edge{from: ref.id, to: to.id})
+					fed = true
+				}
+			}
+			return fed
+		}
+		for _, ref := range append(chained, reads...) {
+			if !feeds(ref) {
+				for _, source := range sources {
+					g.edges = append(g.edges, edge{from: source, to: ref.id})
+				}
+			}
+		}
+		for _, ref := range reads {
+			g.edges = append(g.edges, edge{from: ref.id, to: workloadNode})
+		}
+		for _, ref := range writes {
+			g.edges = append(g.edges, edge{from: workloadNode, to: ref.id}, edge{from: ref.id, to: datasetNode})
+		}
+	}
+	return g
+}
+
+func (g *graph) dot() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", g.name)
+	b.WriteString("  rankdir=LR;\n")
+	fmt.Fprintf(&b, "  %q [label=%q, shape=ellipse];\n", workloadNode, workloadNode)
+	for _, n := range g.datasets {
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s];\n", n.id, n.label, n.shape)
+	}
+	for i, cluster := range g.clusters {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%q;\n", cluster)
+		for _, n := range g.steps[cluster] {
+			fmt.Fprintf(&b, "    %q [label=%q, shape=%s];\n", n.id, n.label, n.shape)
+		}
+		b.WriteString("  }\n")
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", e.from, e.to)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (g *graph) mermaid() string {
+	// mermaid identifiers are restricted, the nodes are thus numbered in the order in which they are declared
+	ids := map[string]string{}
+	id := func(name string) string {
+		if _, found := ids[name]; !found {
+			ids[name] = fmt.Sprintf("n%d", len(ids))
+		}
+		return ids[name]
+	}
+	label := func(text string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(text, "\"", "#quot;"), "\n", "<br/>")
+	}
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	fmt.Fprintf(&b, "  %s([\"%s\"])\n", id(workloadNode), workloadNode)
+	for _, n := range g.datasets {
+		fmt.Fprintf(&b, "  %s[(\"%s\")]\n", id(n.id), label(n.label))
+	}
+	for _, cluster := range g.clusters {
+		fmt.Fprintf(&b, "  subgraph %s [\"%s\"]\n", id("cluster:"+cluster), label(cluster))
+		for _, n := range g.steps[cluster] {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", id(n.id), label(n.label))
+		}
+		b.WriteString("  end\n")
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", id(e.from), id(e.to))
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package visualize
+
+import (
+	"testing"
+
+	app "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func step(name string, template string, datasetIDs string, args app.ModuleArguments) app.FlowStep {
+	if args.Annotations == nil {
+		args.Annotations = map[string]string{}
+	}
+	args.Annotations[app.DatasetIDsAnnotation] = datasetIDs
+	return app.FlowStep{Name: name, Template: template, Arguments: args}
+}
+
+// A dataset copied in the cluster of its source and read in the cluster of the workload,
+// a dataset read through a transformation chain, and a dataset written by the workload
+func testPlotter() *app.Plotter {
+	return &app.Plotter{
+		ObjectMeta: metav1.ObjectMeta{Name: "notebook-default", Namespace: "m4d-system"},
+		Spec: app.PlotterSpec{
+			Blueprints: map[string]app.BlueprintSpec{
+				"neverland": {Flow: app.DataFlow{Steps: []app.FlowStep{
+					step("copy-1", "implicit-copy", "db2/redact-dataset", app.ModuleArguments{Copy: &app.CopyModuleArgs{}}),
+				}}},
+				"thegreendragon": {Flow: app.DataFlow{Steps: []app.FlowStep{
+					step("redact-2", "redact-module", "s3/mask-dataset", app.ModuleArguments{
+						Read:        []app.ReadModuleArgs{{AssetID: "s3/mask-dataset"}},
+						Annotations: map[string]string{app.ChainedToAnnotation: "arrow-flight"},
+					}),
+					step("arrow-flight-3", "arrow-flight", "db2/redact-dataset,s3/mask-dataset", app.ModuleArguments{
+						Read: []app.ReadModuleArgs{{AssetID: "db2/redact-dataset"}, {AssetID: "s3/mask-dataset"}},
+					}),
+					step("write-4", "write-module", "s3/results", app.ModuleArguments{
+						Write: []app.WriteModuleArgs{{AssetID: "s3/results"}},
+					}),
+				}}},
+			},
+		},
+	}
+}
+
+func TestPlotterGraphDOT(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	graph, err := PlotterGraph(testPlotter(), DOT)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(graph).To(gomega.HavePrefix(`digraph "notebook-default" {`))
+	g.Expect(graph).To(gomega.ContainSubstring(`label="neverland";`))
+	g.Expect(graph).To(gomega.ContainSubstring(`"dataset:db2/redact-dataset" [label="db2/redact-dataset", shape=cylinder];`))
+	// the copy feeds the read step, which serves the workload
+	g.Expect(graph).To(gomega.ContainSubstring(`"dataset:db2/redact-dataset" -> "step:neverland/copy-1";`))
+	g.Expect(graph).To(gomega.ContainSubstring(`"step:neverland/copy-1" -> "step:thegreendragon/arrow-flight-3";`))
+	g.Expect(graph).To(gomega.ContainSubstring(`"step:thegreendragon/arrow-flight-3" -> "workload";`))
+	g.Expect(graph).NotTo(gomega.ContainSubstring(`"dataset:db2/redact-dataset" -> "step:thegreendragon/arrow-flight-3";`))
+	// the transformation chain reads the dataset and feeds the read step
+	g.Expect(graph).To(gomega.ContainSubstring(`"dataset:s3/mask-dataset" -> "step:thegreendragon/redact-2";`))
+	g.Expect(graph).To(gomega.ContainSubstring(`"step:thegreendragon/redact-2" -> "step:thegreendragon/arrow-flight-3";`))
+	g.Expect(graph).NotTo(gomega.ContainSubstring(`"dataset:s3/mask-dataset" -> "step:thegreendragon/arrow-flight-3";`))
+	// the workload writes through the write step
+	g.Expect(graph).To(gomega.ContainSubstring(`"workload" -> "step:thegreendragon/write-4";`))
+	g.Expect(graph).To(gomega.ContainSubstring(`"step:thegreendragon/write-4" -> "dataset:s3/results";`))
+}
+
+func TestPlotterGraphMermaid(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	graph, err := PlotterGraph(testPlotter(), Mermaid)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(graph).To(gomega.HavePrefix("flowchart LR\n  n0([\"workload\"])\n"))
+	g.Expect(graph).To(gomega.ContainSubstring(`n1[("db2/redact-dataset")]`))
+	g.Expect(graph).To(gomega.ContainSubstring(`subgraph n4 ["neverland"]`))
+	g.Expect(graph).To(gomega.ContainSubstring(`n5["copy-1<br/>(implicit-copy)"]`))
+	g.Expect(graph).To(gomega.ContainSubstring("n1 --> n5\n"))
+
+	_, err = PlotterGraph(testPlotter(), Format("svg"))
+	g.Expect(err).To(gomega.HaveOccurred())
+}
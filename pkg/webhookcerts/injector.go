@@ -0,0 +1,160 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookcerts
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultCAFile is the CA of the serving certificate mounted in the certificate directory of the webhook server
+const DefaultCAFile = "/tmp/k8s-webhook-server/serving-certs/ca.crt"
+
+// InjectionAnnotations are the annotations of the webhook configurations whose CA bundle is injected by cert-manager
+var InjectionAnnotations = []string{"cert-manager.io/inject-ca-from", "certmanager.k8s.io/inject-ca-from"}
+
+// CAInjector keeps the CA bundle of the webhook configurations of the manager in sync with the CA of the serving certificate,
+// for deployments in which the certificate is not managed by cert-manager.
+// Configurations annotated for the CA injection of cert-manager are left to cert-manager, and missing configurations are ignored.
+// The CA file is read periodically, such that a rotated certificate is injected without restarting the manager.
+// The configurations are read without a cache, as they are not watched.
+type CAInjector struct {
+	Reader     client.Reader
+	Writer     client.Writer
+	CAFile     string
+	Mutating   []string
+	Validating []string
+	Interval   time.Duration
+	Log        logr.Logger
+
+	// injected is the last CA injected into all the configurations
+	injected []byte
+}
+
+// NewCAInjector creates a new injector of the CA into the given mutating and validating webhook configurations
+func NewCAInjector(reader client.Reader, writer client.Writer, caFile string, mutating []string, validating []string,
+	interval time.Duration, log logr.Logger) *CAInjector {
+	return &CAInjector{
+		Reader:     reader,
+		Writer:     writer,
+		CAFile:     caFile,
+		Mutating:   mutating,
+		Validating: validating,
+		Interval:   interval,
+		Log:        log,
+	}
+}
+
+// Start injects the CA until the context is done
+func (i *CAInjector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(i.Interval)
+	defer ticker.Stop()
+	for {
+		if err := i.Inject(ctx); err != nil {
+			i.Log.Error(err, "unable to inject the CA into the webhook configurations")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Inject sets the CA bundle of the webhook configurations to the current CA, if it has changed since the last injection
+func (i *CAInjector) Inject(ctx context.Context) error {
+	ca, err := ioutil.ReadFile(i.CAFile)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the CA of the serving certificate")
+	}
+	if len(ca) == 0 {
+		return errors.New("the CA of the serving certificate is empty")
+	}
+	if bytes.Equal(ca, i.injected) {
+		return nil
+	}
+	for _, name := range i.Mutating {
+		if err := i.injectMutating(ctx, name, ca); err != nil {
+			return err
+		}
+	}
+	for _, name := range i.Validating {
+		if err := i.injectValidating(ctx, name, ca); err != nil {
+			return err
+		}
+	}
+	i.injected = ca
+	return nil
+}
+
+func (i *CAInjector) injectMutating(ctx context.Context, name string, ca []byte) error {
+	config := &admissionv1.MutatingWebhookConfiguration{}
+	if err := i.Reader.Get(ctx, types.NamespacedName{Name: name}, config); err != nil {
+		return ignoreNotFound(err, "unable to get the mutating webhook configuration "+name)
+	}
+	if injectedByCertManager(config.Annotations) {
+		return nil
+	}
+	changed := false
+	for j := range config.Webhooks {
+		if !bytes.Equal(config.Webhooks[j].ClientConfig.CABundle, ca) {
+			config.Webhooks[j].ClientConfig.CABundle = ca
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	i.Log.Info("injecting the CA into the mutating webhook configuration", "name", name)
+	return errors.Wrap(i.Writer.Update(ctx, config), "unable to update the mutating webhook configuration "+name)
+}
+
+func (i *CAInjector) injectValidating(ctx context.Context, name string, ca []byte) error {
+	config := &admissionv1.ValidatingWebhookConfiguration{}
+	if err := i.Reader.Get(ctx, types.NamespacedName{Name: name}, config); err != nil {
+		return ignoreNotFound(err, "unable to get the validating webhook configuration "+name)
+	}
+	if injectedByCertManager(config.Annotations) {
+		return nil
+	}
+	changed := false
+	for j := range config.Webhooks {
+		if !bytes.Equal(config.Webhooks[j].ClientConfig.CABundle, ca) {
+			config.Webhooks[j].ClientConfig.CABundle = ca
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	i.Log.Info("injecting the CA into the validating webhook configuration", "name", name)
+	return errors.Wrap(i.Writer.Update(ctx, config), "unable to update the validating webhook configuration "+name)
+}
+
+// injectedByCertManager returns true if the annotations request cert-manager to inject the CA
+func injectedByCertManager(annotations map[string]string) bool {
+	for _, annotation := range InjectionAnnotations {
+		if annotations[annotation] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreNotFound returns nil if the configuration does not exist, e.g. if the cluster scoped resources are not deployed
+func ignoreNotFound(err error, message string) error {
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, message)
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookcerts
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInjectCA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serving-certs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "ca.crt")
+	assert.Nil(t, ioutil.WriteFile(caFile, []byte("ca-1"), 0600))
+
+	scheme := runtime.NewScheme()
+	assert.Nil(t, admissionv1.AddToScheme(scheme))
+	mutating := &admissionv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "m4d-system-mutating-webhook"},
+		Webhooks:   []admissionv1.MutatingWebhook{{Name: "mm4dapplication.kb.io"}},
+	}
+	validating := &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "m4d-system-validating-webhook",
+			Annotations: map[string]string{"cert-manager.io/inject-ca-from": "m4d-system/serving-cert"},
+		},
+		Webhooks: []admissionv1.ValidatingWebhook{{Name: "vm4dapplication.kb.io"}},
+	}
+	cl := fake.NewFakeClientWithScheme(scheme, mutating, validating)
+	injector := NewCAInjector(cl, cl, caFile, []string{mutating.Name, "missing"}, []string{validating.Name}, time.Minute, ctrl.Log.WithName("test"))
+	ctx := context.Background()
+
+	// the CA is injected into the configurations that are not managed by cert-manager
+	assert.Nil(t, injector.Inject(ctx))
+	assert.Nil(t, cl.Get(ctx, types.NamespacedName{Name: mutating.Name}, mutating))
+	assert.Equal(t, []byte("ca-1"), mutating.Webhooks[0].ClientConfig.CABundle)
+	assert.Nil(t, cl.Get(ctx, types.NamespacedName{Name: validating.Name}, validating))
+	assert.Empty(t, validating.Webhooks[0].ClientConfig.CABundle)
+
+	// a rotated CA is injected again
+	assert.Nil(t, ioutil.WriteFile(caFile, []byte("ca-2"), 0600))
+	assert.Nil(t, injector.Inject(ctx))
+	assert.Nil(t, cl.Get(ctx, types.NamespacedName{Name: mutating.Name}, mutating))
+	assert.Equal(t, []byte("ca-2"), mutating.Webhooks[0].ClientConfig.CABundle)
+
+	// the CA of the serving certificate is required
+	assert.Nil(t, os.Remove(caFile))
+	assert.NotNil(t, injector.Inject(ctx))
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// loadtest creates and updates M4DApplications across namespaces of a cluster, such as a kind cluster running
+// the manager with the mock connectors, and reports the reconcile throughput and the load of the API server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	appv1 "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func main() {
+	options := Options{}
+	flag.IntVar(&options.Applications, "applications", 100, "The number of applications to create.")
+	flag.IntVar(&options.Namespaces, "namespaces", 10, "The number of namespaces across which the applications are spread.")
+	datasets := flag.String("datasets", "s3/allow-dataset=1", "The datasets of each application as comma separated id=count pairs, "+
+		"e.g. s3/allow-dataset=2,s3/redact-dataset=1. The mock policy manager decides by the name of the dataset.")
+	flag.IntVar(&options.Updates, "updates", 1, "The number of rounds in which the specs of all the applications are updated.")
+	flag.IntVar(&options.Parallelism, "parallelism", 10, "The maximal number of concurrent create and update requests.")
+	flag.StringVar(&options.Prefix, "prefix", "loadtest", "The prefix of the names of the namespaces.")
+	flag.DurationVar(&options.Timeout, "timeout", 10*time.Minute, "The time after which the pending applications of a phase are reported as such.")
+	flag.DurationVar(&options.Interval, "interval", 2*time.Second, "The interval at which the status of the applications is polled.")
+	cleanup := flag.Bool("cleanup", true, "Delete the namespaces of the test once done.")
+	flag.Parse()
+
+	var err error
+	if options.Datasets, err = ParseDatasetMix(*datasets); err != nil {
+		fail(err)
+	}
+	scheme := kruntime.NewScheme()
+	_ = appv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		fail(err)
+	}
+	cl, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		fail(err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		fail(err)
+	}
+
+	runner := &Runner{Client: cl, Metrics: clientset.Discovery().RESTClient(), Options: options}
+	ctx := context.Background()
+	phases, err := runner.Run(ctx)
+	for _, phase := range phases {
+		phase.Print(os.Stdout)
+	}
+	if *cleanup {
+		if cleanupErr := runner.Cleanup(ctx); cleanupErr != nil {
+			fmt.Fprintln(os.Stderr, "cleanup failed: "+cleanupErr.Error())
+		}
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// Phase is the outcome of a phase of the load test, in which all the applications are created or updated
+type Phase struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+	// Latencies are the durations between the change of the applications that are ready and the observation of their readiness
+	Latencies []time.Duration
+	// Failed is the number of applications reporting an error
+	Failed int
+	// Pending is the number of applications that have not been reconciled before the timeout
+	Pending int
+	// Requests are the requests served by the API server during the phase
+	Requests RequestCounts
+}
+
+// Print writes a summary of the phase
+func (p *Phase) Print(w io.Writer) {
+	duration := p.End.Sub(p.Start)
+	reconciled := len(p.Latencies) + p.Failed
+	fmt.Fprintf(w, "phase %s: %d ready, %d failed, %d pending in %s (%.2f applications/s)\n",
+		p.Name, len(p.Latencies), p.Failed, p.Pending, duration.Round(time.Millisecond), float64(reconciled)/duration.Seconds())
+	if len(p.Latencies) > 0 {
+		fmt.Fprintf(w, "  time to ready: p50 %s, p90 %s, p99 %s, max %s\n",
+			percentile(p.Latencies, 50), percentile(p.Latencies, 90), percentile(p.Latencies, 99), percentile(p.Latencies, 100))
+	}
+	fmt.Fprintf(w, "  API server requests: %.0f (%.2f/s)\n", p.Requests.Total, p.Requests.Total/duration.Seconds())
+	for _, key := range sortedKeys(p.Requests.ByVerb) {
+		fmt.Fprintf(w, "    %-10s %.0f\n", key, p.Requests.ByVerb[key])
+	}
+	for _, key := range sortedKeys(p.Requests.ByResource) {
+		fmt.Fprintf(w, "    %-40s %.0f\n", key, p.Requests.ByResource[key])
+	}
+}
+
+// percentile returns the nearest-rank percentile of the durations
+func percentile(durations []time.Duration, p int) time.Duration {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Millisecond)
+}
+
+func sortedKeys(counts map[string]float64) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RequestCounts are the numbers of requests served by the API server, in total, per verb,
+// and per resource of the Mesh for Data API groups
+type RequestCounts struct {
+	Total      float64
+	ByVerb     map[string]float64
+	ByResource map[string]float64
+}
+
+// Sub returns the requests counted since the given counts
+func (c RequestCounts) Sub(before RequestCounts) RequestCounts {
+	diff := RequestCounts{Total: c.Total - before.Total, ByVerb: map[string]float64{}, ByResource: map[string]float64{}}
+	for key, count := range c.ByVerb {
+		diff.ByVerb[key] = count - before.ByVerb[key]
+	}
+	for key, count := range c.ByResource {
+		diff.ByResource[key] = count - before.ByResource[key]
+	}
+	return diff
+}
+
+// ReadRequestCounts reads the apiserver_request_total counters from the metrics endpoint of the API server
+func ReadRequestCounts(ctx context.Context, metrics rest.Interface) (RequestCounts, error) {
+	data, err := metrics.Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		return RequestCounts{}, err
+	}
+	return ParseRequestCounts(data)
+}
+
+// ParseRequestCounts sums the apiserver_request_total counters of metrics in the Prometheus text format
+func ParseRequestCounts(data []byte) (RequestCounts, error) {
+	counts := RequestCounts{ByVerb: map[string]float64{}, ByResource: map[string]float64{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "apiserver_request_total{") {
+			continue
+		}
+		end := strings.LastIndex(line, "}")
+		if end < 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(line[end+1:]), 64)
+		if err != nil {
+			return counts, fmt.Errorf("invalid metric %q", line)
+		}
+		labels := parseLabels(line[len("apiserver_request_total{"):end])
+		counts.Total += value
+		counts.ByVerb[labels["verb"]] += value
+		if strings.HasSuffix(labels["group"], "m4d.ibm.com") {
+			resource := labels["resource"]
+			if labels["subresource"] != "" {
+				resource += "/" + labels["subresource"]
+			}
+			counts.ByResource[resource+"."+labels["group"]] += value
+		}
+	}
+	return counts, scanner.Err()
+}
+
+// parseLabels parses the labels of a metric, e.g. code="200",verb="GET"
+func parseLabels(text string) map[string]string {
+	labels := map[string]string{}
+	for _, pair := range strings.Split(text, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) == 2 {
+			labels[parts[0]] = strings.Trim(parts[1], `"`)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,262 @@
+// Copyright 2021 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	appv1 "github.com/mesh-for-data/mesh-for-data/manager/apis/app/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RunLabel marks the namespaces and the applications created by the load test
+const RunLabel = "m4d.ibm.com/loadtest"
+
+// Options configure a load test
+type Options struct {
+	Applications int
+	Namespaces   int
+	Datasets     []DatasetCount
+	Updates      int
+	Parallelism  int
+	Prefix       string
+	Timeout      time.Duration
+	Interval     time.Duration
+}
+
+// DatasetCount is the number of times a dataset is used by each application, under different names
+type DatasetCount struct {
+	DataSetID string
+	Count     int
+}
+
+// ParseDatasetMix parses comma separated id=count pairs
+func ParseDatasetMix(mix string) ([]DatasetCount, error) {
+	var datasets []DatasetCount
+	for _, pair := range strings.Split(mix, ",") {
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.New("invalid dataset count " + pair)
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil || count < 1 {
+			return nil, errors.New("invalid dataset count " + pair)
+		}
+		datasets = append(datasets, DatasetCount{DataSetID: parts[0], Count: count})
+	}
+	return datasets, nil
+}
+
+// Runner creates and updates the applications of a load test, and measures the time until they are reconciled
+type Runner struct {
+	Client client.Client
+	// Metrics reads the metrics of the API server
+	Metrics rest.Interface
+	Options Options
+}
+
+// Run creates the applications, then updates them in rounds, and reports each phase.
+// The phases completed before an error are returned with it.
+func (r *Runner) Run(ctx context.Context) ([]*Phase, error) {
+	var phases []*Phase
+	if err := r.createNamespaces(ctx); err != nil {
+		return phases, err
+	}
+	phase, err := r.runPhase(ctx, "create", r.create)
+	if phase != nil {
+		phases = append(phases, phase)
+	}
+	if err != nil {
+		return phases, err
+	}
+	for round := 1; round <= r.Options.Updates; round++ {
+		phase, err := r.runPhase(ctx, fmt.Sprintf("update %d", round), func(ctx context.Context, key client.ObjectKey) error {
+			return r.update(ctx, key, round)
+		})
+		if phase != nil {
+			phases = append(phases, phase)
+		}
+		if err != nil {
+			return phases, err
+		}
+	}
+	return phases, nil
+}
+
+// Cleanup deletes the namespaces of the load test, together with their applications
+func (r *Runner) Cleanup(ctx context.Context) error {
+	for i := 0; i < r.Options.Namespaces; i++ {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: r.namespace(i)}}
+		if err := r.Client.Delete(ctx, namespace); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Runner) namespace(i int) string {
+	return fmt.Sprintf("%s-%d", r.Options.Prefix, i)
+}
+
+// keys spreads the applications across the namespaces
+func (r *Runner) keys() []client.ObjectKey {
+	keys := make([]client.ObjectKey, r.Options.Applications)
+	for i := range keys {
+		keys[i] = client.ObjectKey{Namespace: r.namespace(i % r.Options.Namespaces), Name: fmt.Sprintf("app-%d", i)}
+	}
+	return keys
+}
+
+func (r *Runner) createNamespaces(ctx context.Context) error {
+	for i := 0; i < r.Options.Namespaces; i++ {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: r.namespace(i), Labels: map[string]string{RunLabel: r.Options.Prefix}}}
+		if err := r.Client.Create(ctx, namespace); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// application returns the application of the given key, reading the datasets of the mix
+func (r *Runner) application(key client.ObjectKey) *appv1.M4DApplication {
+	application := &appv1.M4DApplication{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Labels: map[string]string{RunLabel: r.Options.Prefix}},
+		Spec: appv1.M4DApplicationSpec{
+			Selector: appv1.Selector{WorkloadSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": key.Name}}},
+			AppInfo:  map[string]string{"intent": "Load Testing", "role": "Data Scientist"},
+		},
+	}
+	for _, dataset := range r.Options.Datasets {
+		for i := 0; i < dataset.Count; i++ {
+			id := dataset.DataSetID
+			if i > 0 {
+				// the mock policy manager service ignores the numeric suffix when deciding on the dataset
+				id = fmt.Sprintf("%s-%d", id, i)
+			}
+			application.Spec.Data = append(application.Spec.Data, appv1.DataContext{
+				DataSetID: id,
+				Requirements: appv1.DataRequirements{
+					Interface: appv1.InterfaceDetails{Protocol: appv1.ArrowFlight, DataFormat: appv1.Arrow},
+				},
+			})
+		}
+	}
+	return application
+}
+
+func (r *Runner) create(ctx context.Context, key client.ObjectKey) error {
+	return r.Client.Create(ctx, r.application(key))
+}
+
+// update changes the spec of the application, which is reconciled again
+func (r *Runner) update(ctx context.Context, key client.ObjectKey, round int) error {
+	application := &appv1.M4DApplication{}
+	if err := r.Client.Get(ctx, key, application); err != nil {
+		return err
+	}
+	application.Spec.AppInfo["round"] = strconv.Itoa(round)
+	return r.Client.Update(ctx, application)
+}
+
+// runPhase applies the change to all the applications with the configured parallelism,
+// and waits until they are reconciled or the timeout has expired
+func (r *Runner) runPhase(ctx context.Context, name string, change func(context.Context, client.ObjectKey) error) (*Phase, error) {
+	before, err := ReadRequestCounts(ctx, r.Metrics)
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not read the metrics of the API server")
+	}
+	phase := &Phase{Name: name, Start: time.Now()}
+	keys := r.keys()
+	changed := make([]time.Time, len(keys))
+	generations := make([]int64, len(keys))
+	errs := make([]error, len(keys))
+	slots := make(chan struct{}, r.Options.Parallelism)
+	var wg sync.WaitGroup
+	for i := range keys {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			changed[i] = time.Now()
+			errs[i] = change(ctx, keys[i])
+		}(i)
+	}
+	wg.Wait()
+	if err := errors.Combine(errs...); err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		application := &appv1.M4DApplication{}
+		if err := r.Client.Get(ctx, key, application); err != nil {
+			return nil, err
+		}
+		generations[i] = application.Generation
+	}
+
+	// the status of the applications is polled until all of them are reconciled
+	settled := make([]bool, len(keys))
+	deadline := phase.Start.Add(r.Options.Timeout)
+	for remaining := len(keys); remaining > 0 && time.Now().Before(deadline); {
+		time.Sleep(r.Options.Interval)
+		applications := map[client.ObjectKey]*appv1.M4DApplication{}
+		for i := 0; i < r.Options.Namespaces; i++ {
+			list := &appv1.M4DApplicationList{}
+			if err := r.Client.List(ctx, list, client.InNamespace(r.namespace(i)), client.MatchingLabels{RunLabel: r.Options.Prefix}); err != nil {
+				return nil, err
+			}
+			for j := range list.Items {
+				applications[client.ObjectKeyFromObject(&list.Items[j])] = &list.Items[j]
+			}
+		}
+		now := time.Now()
+		for i, key := range keys {
+			application, found := applications[key]
+			if settled[i] || !found || application.Status.ObservedGeneration < generations[i] {
+				continue
+			}
+			switch {
+			case application.Status.Ready:
+				phase.Latencies = append(phase.Latencies, now.Sub(changed[i]))
+			case hasError(application):
+				phase.Failed++
+			default:
+				continue
+			}
+			settled[i] = true
+			remaining--
+		}
+	}
+	phase.End = time.Now()
+	for _, done := range settled {
+		if !done {
+			phase.Pending++
+		}
+	}
+	after, err := ReadRequestCounts(ctx, r.Metrics)
+	if err != nil {
+		return phase, errors.WrapIf(err, "could not read the metrics of the API server")
+	}
+	phase.Requests = after.Sub(before)
+	return phase, nil
+}
+
+// hasError returns true if the status of the application reports an error or a denial
+func hasError(application *appv1.M4DApplication) bool {
+	return meta.IsStatusConditionTrue(application.Status.Conditions, appv1.ErrorCondition) ||
+		meta.IsStatusConditionTrue(application.Status.Conditions, appv1.DeniedCondition)
+}
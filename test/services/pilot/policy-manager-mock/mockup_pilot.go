@@ -106,7 +106,7 @@ func newPolicyManager() (connectors.PolicyManager, error) {
 	mainPolicyManagerName := os.Getenv("MAIN_POLICY_MANAGER_NAME")
 	mainPolicyManagerURL := os.Getenv("MAIN_POLICY_MANAGER_CONNECTOR_URL")
 	policyManager, err := connectors.NewGrpcPolicyManager(
-		mainPolicyManagerName, mainPolicyManagerURL, time.Duration(timeOutInSeconds)*time.Second)
+		mainPolicyManagerName, mainPolicyManagerURL, time.Duration(timeOutInSeconds)*time.Second, nil)
 	setupLog.Info("setting main policy manager", "Name", mainPolicyManagerName, "URL", mainPolicyManagerURL, "Timeout (sec)", timeOutInSeconds)
 	if err != nil {
 		return nil, err
@@ -117,7 +117,7 @@ func newPolicyManager() (connectors.PolicyManager, error) {
 		extensionPolicyManagerName := os.Getenv("EXTENSIONS_POLICY_MANAGER_NAME")
 		extensionPolicyManagerURL := os.Getenv("EXTENSIONS_POLICY_MANAGER_CONNECTOR_URL")
 		extensionPolicyManager, err := connectors.NewGrpcPolicyManager(
-			extensionPolicyManagerName, extensionPolicyManagerURL, time.Duration(timeOutInSeconds)*time.Second)
+			extensionPolicyManagerName, extensionPolicyManagerURL, time.Duration(timeOutInSeconds)*time.Second, nil)
 		setupLog.Info("setting extension policy manager", "Name", extensionPolicyManagerName, "URL", extensionPolicyManagerURL, "Timeout (sec)", timeOutInSeconds)
 		if err != nil {
 			return nil, err
@@ -4,19 +4,100 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	mockup "github.com/mesh-for-data/mesh-for-data/manager/controllers/mockup"
 	"github.com/mesh-for-data/mesh-for-data/manager/controllers/utils"
 	pb "github.com/mesh-for-data/mesh-for-data/pkg/connectors/protobuf"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
 	PORT = 50082
 )
 
+// Environment variables simulating the conditions of a real policy manager
+const (
+	LatencyKey         = "MOCK_LATENCY"
+	FailEveryKey       = "MOCK_FAIL_EVERY"
+	ResponsePaddingKey = "MOCK_RESPONSE_PADDING_BYTES"
+)
+
+// newPolicyManager creates a mock policy manager configured by the environment
+func newPolicyManager() *mockup.MockPolicyManager {
+	service := &mockup.MockPolicyManager{}
+	if value, found := os.LookupEnv(LatencyKey); found {
+		latency, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", LatencyKey, err)
+		}
+		service.Latency = latency
+	}
+	if value, found := os.LookupEnv(FailEveryKey); found {
+		failEvery, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", FailEveryKey, err)
+		}
+		service.FailEvery = failEvery
+	}
+	if value, found := os.LookupEnv(ResponsePaddingKey); found {
+		padding, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", ResponsePaddingKey, err)
+		}
+		service.ResponsePadding = padding
+	}
+	log.Printf("simulated latency %v, failing every %d requests, response padding %d bytes",
+		service.Latency, service.FailEvery, service.ResponsePadding)
+	return service
+}
+
+// loadTestPolicyManager decides on the numbered copies of the mock datasets requested by the load tests,
+// e.g. s3/allow-dataset-1, as the mock policy manager decides on the mock datasets themselves
+type loadTestPolicyManager struct {
+	*mockup.MockPolicyManager
+}
+
+// GetPoliciesDecisions requests the decisions on the mock datasets, and returns them for the requested datasets
+func (m *loadTestPolicyManager) GetPoliciesDecisions(ctx context.Context, in *pb.ApplicationContext) (*pb.PoliciesDecisions, error) {
+	renamed := proto.Clone(in).(*pb.ApplicationContext)
+	for _, dataset := range renamed.GetDatasets() {
+		if dataset.GetDataset() != nil {
+			dataset.Dataset.DatasetId = mockDatasetID(dataset.GetDataset().GetDatasetId())
+		}
+	}
+	decisions, err := m.MockPolicyManager.GetPoliciesDecisions(ctx, renamed)
+	if err != nil {
+		return nil, err
+	}
+	// the decisions are returned in the order of the requested datasets
+	for i, decision := range decisions.GetDatasetDecisions() {
+		if i < len(in.GetDatasets()) {
+			decision.Dataset = in.GetDatasets()[i].GetDataset()
+		}
+	}
+	return decisions, nil
+}
+
+// mockDatasetID returns the identifier of the mock dataset of a numbered copy, without its numeric suffix
+func mockDatasetID(datasetID string) string {
+	i := strings.LastIndex(datasetID, "-")
+	if i < 0 || i == len(datasetID)-1 {
+		return datasetID
+	}
+	if _, err := strconv.Atoi(datasetID[i+1:]); err != nil {
+		return datasetID
+	}
+	return datasetID[:i]
+}
+
 func main() {
 	address := utils.ListeningAddress(PORT)
 	log.Printf("starting mock policy manager server on address %s", address)
@@ -27,7 +108,7 @@ func main() {
 	}
 
 	server := grpc.NewServer()
-	service := &mockup.MockPolicyManager{}
+	service := &loadTestPolicyManager{MockPolicyManager: newPolicyManager()}
 
 	pb.RegisterPolicyManagerServiceServer(server, service)
 	if err := server.Serve(listener); err != nil {